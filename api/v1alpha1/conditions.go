@@ -1,3 +1,19 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
 package v1alpha1
 
 type ConditionType string
@@ -6,8 +22,59 @@ type ConditionReason string
 const ConditionTypeReady ConditionType = "Ready"
 const ConditionReasonProviderSuccess ConditionReason = "ProviderSuccess"
 const ConditionReasonAwaitingValidation ConditionReason = "AwaitingValidation"
+const ConditionReasonAwaitingPropagation ConditionReason = "AwaitingPropagation"
+const ConditionReasonDomainNotAllowed ConditionReason = "DomainNotAllowed"
+const ConditionReasonDriftDetected ConditionReason = "DriftDetected"
+const ConditionReasonTakeoverPending ConditionReason = "TakeoverPending"
+const ConditionReasonAwaitingDomainVerification ConditionReason = "AwaitingDomainVerification"
 
 const ConditionTypeHealthy ConditionType = "Healthy"
 const ConditionReasonHealthy ConditionReason = "AllChecksPassed"
 const ConditionReasonPartiallyHealthy ConditionReason = "SomeChecksPassed"
 const ConditionReasonUnhealthy ConditionReason = "HealthChecksFailed"
+
+const ConditionTypeUnowned ConditionType = "Unowned"
+const ConditionReasonUnownedPublish ConditionReason = "UnownedPublish"
+
+const ConditionTypeDedicatedZone ConditionType = "DedicatedZone"
+const ConditionReasonDedicatedZonePublish ConditionReason = "DedicatedZonePublish"
+
+const ConditionTypePrivateTargets ConditionType = "PrivateTargets"
+const ConditionReasonPrivateTargetsInPublicZone ConditionReason = "PrivateTargetsInPublicZone"
+
+const ConditionTypeOversizedRRsets ConditionType = "OversizedRRsets"
+const ConditionReasonExceedsUDPSize ConditionReason = "ExceedsUDPSize"
+const ConditionReasonExceedsLimits ConditionReason = "ExceedsLimits"
+
+const ConditionTypeDriftDetected ConditionType = "DriftDetected"
+const ConditionReasonOutOfBandChanges ConditionReason = "OutOfBandChanges"
+
+const ConditionTypeZoneDelegationBroken ConditionType = "ZoneDelegationBroken"
+const ConditionReasonZoneNotDelegated ConditionReason = "ZoneNotDelegated"
+const ConditionReasonZoneDelegationMismatch ConditionReason = "DelegatedToOtherNameservers"
+
+const ConditionTypeParked ConditionType = "Parked"
+const ConditionReasonParked ConditionReason = "ParkingTargetPublished"
+
+const ConditionTypeMigrating ConditionType = "Migrating"
+const ConditionReasonMigrationPublishing ConditionReason = "PublishingToTarget"
+const ConditionReasonMigrationVerifying ConditionReason = "VerifyingResolution"
+const ConditionReasonMigrationRemovingSource ConditionReason = "RemovingFromSource"
+const ConditionReasonMigrationFailed ConditionReason = "MigrationFailed"
+
+const ConditionTypePartiallyPublished ConditionType = "PartiallyPublished"
+const ConditionReasonEndpointsRejected ConditionReason = "EndpointsRejected"
+
+const ConditionTypeChangesDampened ConditionType = "ChangesDampened"
+const ConditionReasonAwaitingStability ConditionReason = "AwaitingStability"
+
+const ConditionTypeBudgetExceeded ConditionType = "BudgetExceeded"
+const ConditionReasonWriteBudgetExhausted ConditionReason = "WriteBudgetExhausted"
+
+const ConditionTypeTakeoverPending ConditionType = "TakeoverPending"
+const ConditionReasonOtherOwnersAffected ConditionReason = "OtherOwnersAffected"
+
+const ConditionTypeRegistryPressure ConditionType = "RegistryPressure"
+const ConditionReasonApproachingZoneRecordLimit ConditionReason = "ApproachingZoneRecordLimit"
+
+const ConditionTypeEndpointsHealthy ConditionType = "EndpointsHealthy"
@@ -23,6 +23,11 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+// ProbeTarget is an IPv4 or IPv6 address, with an optional port, probe requests are sent to. For example
+// "192.0.2.10", "192.0.2.10:8443", "2001:db8::10" or "[2001:db8::10]:8443".
+// +kubebuilder:validation:Pattern=`^((\d{1,3}\.){3}\d{1,3}(:\d{1,5})?|[0-9a-fA-F]*:[0-9a-fA-F:.]*|\[[0-9a-fA-F]*:[0-9a-fA-F:.]*\]:\d{1,5})$`
+type ProbeTarget string
+
 // DNSHealthCheckProbeSpec defines the desired state of DNSHealthCheckProbe
 type DNSHealthCheckProbeSpec struct {
 	// Port to connect to the host on. Must be either 80, 443 or 1024-49151
@@ -36,6 +41,23 @@ type DNSHealthCheckProbeSpec struct {
 	// Address to connect to the host on (IP Address (A Record) or hostname (CNAME)).
 	Address string `json:"address,omitempty"`
 
+	// ServerName is the name sent in the TLS server name indication of HTTPS requests.
+	// Defaults to Hostname
+	// +optional
+	ServerName string `json:"serverName,omitempty"`
+
+	// HostHeader is the value sent in the host header of requests.
+	// Defaults to Hostname
+	// +optional
+	HostHeader string `json:"hostHeader,omitempty"`
+
+	// Targets are the IP addresses, with an optional port, requests are sent to instead of the IP addresses that
+	// Address resolves to. For example "192.0.2.10" or "192.0.2.10:8443", Port is used for a target without a port.
+	// The health of the probe still applies to Address.
+	// +kubebuilder:validation:MinItems=1
+	// +optional
+	Targets []ProbeTarget `json:"targets,omitempty"`
+
 	// Path is the path to append to the host to reach the expected health check.
 	// Must start with "?" or "/", contain only valid URL characters and end with alphanumeric char or "/". For example "/" or "/healthz" are common
 	// +kubebuilder:validation:Pattern=`^(?:\?|\/)[\w\-.~:\/?#\[\]@!$&'()*+,;=]+(?:[a-zA-Z0-9]|\/){1}$`
@@ -48,6 +70,11 @@ type DNSHealthCheckProbeSpec struct {
 	// Interval defines how frequently this probe should execute
 	Interval *metav1.Duration `json:"interval,omitempty"`
 
+	// Timeout is how long a request, including resolving the address, can take before it fails.
+	// Defaults to the probe timeout of the operator
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+
 	// AdditionalHeadersRef refers to a secret that contains extra headers to send in the probe request, this is primarily useful if an authentication
 	// token is required by the endpoint.
 	// +optional
@@ -57,9 +84,19 @@ type DNSHealthCheckProbeSpec struct {
 	// +kubebuilder:validation:XValidation:rule="self > 0",message="Failure threshold must be greater than 0"
 	FailureThreshold int `json:"failureThreshold,omitempty"`
 
+	// RequiredPasses is the number of consecutive successful probes that must occur for a host that is not healthy to be considered healthy
+	// +optional
+	RequiredPasses int `json:"requiredPasses,omitempty"`
+
 	// AllowInsecureCertificate will instruct the health check probe to not fail on a self-signed or otherwise invalid SSL certificate
 	// this is primarily used in development or testing environments and is set by the --insecure-health-checks flag
 	AllowInsecureCertificate bool `json:"allowInsecureCertificate,omitempty"`
+
+	// ResolveCNAMEChain resolves the CNAME chain of a hostname Address one hop at a time and probes each of the IP
+	// addresses at the end of the chain, reporting the hops in the status, so a failure can be traced to the hop that
+	// did not resolve. Ignored when Targets are set.
+	// +optional
+	ResolveCNAMEChain bool `json:"resolveCNAMEChain,omitempty"`
 }
 
 type AdditionalHeadersRef struct {
@@ -75,12 +112,49 @@ type AdditionalHeader struct {
 
 // DNSHealthCheckProbeStatus defines the observed state of DNSHealthCheckProbe
 type DNSHealthCheckProbeStatus struct {
-	LastCheckedAt       metav1.Time `json:"-"`
-	ConsecutiveFailures int         `json:"consecutiveFailures,omitempty"`
-	Reason              string      `json:"reason,omitempty"`
-	Status              int         `json:"status,omitempty"`
-	Healthy             *bool       `json:"healthy,omitempty"`
-	ObservedGeneration  int64       `json:"observedGeneration,omitempty"`
+	LastCheckedAt        metav1.Time `json:"-"`
+	ConsecutiveFailures  int         `json:"consecutiveFailures,omitempty"`
+	ConsecutiveSuccesses int         `json:"consecutiveSuccesses,omitempty"`
+	Reason               string      `json:"reason,omitempty"`
+	Status               int         `json:"status,omitempty"`
+	Healthy              *bool       `json:"healthy,omitempty"`
+	ObservedGeneration   int64       `json:"observedGeneration,omitempty"`
+
+	// CNAMEChain are the hops the address of the probe resolved through, when ResolveCNAMEChain is set
+	// +optional
+	CNAMEChain []CNAMEHop `json:"cnameChain,omitempty"`
+
+	// TargetResults are the results of probing each of the IP addresses at the end of the CNAME chain
+	// +optional
+	TargetResults []ProbeTargetResult `json:"targetResults,omitempty"`
+}
+
+// CNAMEHop is a name the address of a probe resolved through
+type CNAMEHop struct {
+	// Name is the name resolved at this hop
+	Name string `json:"name"`
+
+	// Target is the name the CNAME record at Name points to, empty for the last hop
+	// +optional
+	Target string `json:"target,omitempty"`
+
+	// Addresses are the IP addresses Name resolved to, only set for the last hop
+	// +optional
+	Addresses []string `json:"addresses,omitempty"`
+
+	// Error is why Name failed to resolve
+	// +optional
+	Error string `json:"error,omitempty"`
+}
+
+// ProbeTargetResult is the result of probing one of the IP addresses at the end of a CNAME chain
+type ProbeTargetResult struct {
+	Address string `json:"address"`
+	Healthy bool   `json:"healthy"`
+	// +optional
+	Reason string `json:"reason,omitempty"`
+	// +optional
+	Status int `json:"status,omitempty"`
 }
 
 //+kubebuilder:object:root=true
@@ -0,0 +1,77 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DNSQuotaSpec defines the limits on the DNSRecords of a namespace
+type DNSQuotaSpec struct {
+	// MaxRecords is the maximum number of DNSRecords in the namespace.
+	// Unlimited if not set
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxRecords *int `json:"maxRecords,omitempty"`
+
+	// MaxEndpoints is the maximum number of endpoints of all DNSRecords in the namespace combined.
+	// Unlimited if not set
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxEndpoints *int `json:"maxEndpoints,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:printcolumn:name="Max Records",type="integer",JSONPath=".spec.maxRecords",description="Maximum number of DNSRecords."
+//+kubebuilder:printcolumn:name="Max Endpoints",type="integer",JSONPath=".spec.maxEndpoints",description="Maximum number of endpoints."
+
+// DNSQuota is the Schema for the dnsquotas API, it caps the DNSRecords created in its namespace.
+// DNSRecords that would exceed the quota are rejected by the DNSRecord validating webhook.
+type DNSQuota struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec DNSQuotaSpec `json:"spec,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// DNSQuotaList contains a list of DNSQuota
+type DNSQuotaList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []DNSQuota `json:"items"`
+}
+
+// Limits returns the lowest maximum number of records and endpoints of the quotas in the list, a namespace has to be
+// within all of its quotas. A limit is nil when none of the quotas set it.
+func (l *DNSQuotaList) Limits() (maxRecords, maxEndpoints *int) {
+	for i := range l.Items {
+		spec := l.Items[i].Spec
+		if spec.MaxRecords != nil && (maxRecords == nil || *spec.MaxRecords < *maxRecords) {
+			maxRecords = spec.MaxRecords
+		}
+		if spec.MaxEndpoints != nil && (maxEndpoints == nil || *spec.MaxEndpoints < *maxEndpoints) {
+			maxEndpoints = spec.MaxEndpoints
+		}
+	}
+	return maxRecords, maxEndpoints
+}
+
+func init() {
+	SchemeBuilder.Register(&DNSQuota{}, &DNSQuotaList{})
+}
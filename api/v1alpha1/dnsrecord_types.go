@@ -17,7 +17,12 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"encoding/json"
 	"fmt"
+	"net/netip"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -34,6 +39,7 @@ const HttpsProtocol Protocol = "HTTPS"
 // HealthCheckSpec configures health checks in the DNS provider.
 // By default this health check will be applied to each unique DNS A Record for
 // the listeners assigned to the target gateway
+// +kubebuilder:validation:XValidation:rule="!has(self.timeout) || !has(self.interval) || duration(self.timeout) < duration(self.interval)",message="Timeout must be less than interval"
 type HealthCheckSpec struct {
 	// Port to connect to the host on. Must be either 80, 443 or 1024-49151
 	// Defaults to port 443
@@ -57,16 +63,45 @@ type HealthCheckSpec struct {
 	// +kubebuilder:default="5m"
 	Interval *metav1.Duration `json:"interval,omitempty"`
 
+	// Timeout is how long a probe request, including resolving the address, can take before it fails.
+	// Defaults to the probe timeout of the operator, 3 seconds unless set with --probe-timeout
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+
 	// AdditionalHeadersRef refers to a secret that contains extra headers to send in the probe request, this is primarily useful if an authentication
 	// token is required by the endpoint.
 	// +optional
 	AdditionalHeadersRef *AdditionalHeadersRef `json:"additionalHeadersRef,omitempty"`
 
+	// ServerName is the name sent in the TLS server name indication of HTTPS probe requests, when it differs from the
+	// root host, e.g. for endpoints fronted by a CDN or a shared load balancer.
+	// Defaults to the root host
+	// +optional
+	ServerName string `json:"serverName,omitempty"`
+
+	// HostHeader is the value sent in the host header of probe requests.
+	// Defaults to the root host
+	// +optional
+	HostHeader string `json:"hostHeader,omitempty"`
+
 	// FailureThreshold is a limit of consecutive failures that must occur for a host to be considered unhealthy
 	// Defaults to 5
 	// +kubebuilder:validation:XValidation:rule="self > 0",message="Failure threshold must be greater than 0"
 	// +kubebuilder:default=5
 	FailureThreshold int `json:"failureThreshold,omitempty"`
+
+	// RequiredPasses is the number of consecutive successful probes that must occur for a host that is not healthy,
+	// including one that has never been probed, to be considered healthy and be published
+	// Defaults to 1
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=1
+	// +optional
+	RequiredPasses int `json:"requiredPasses,omitempty"`
+
+	// ResolveCNAMEChain probes each of the IP addresses at the end of the CNAME chain of hostname targets, resolving
+	// the chain one hop at a time, and reports which hop failed to resolve in the status of the probe
+	// +optional
+	ResolveCNAMEChain bool `json:"resolveCNAMEChain,omitempty"`
 }
 
 type HealthCheckStatus struct {
@@ -85,6 +120,10 @@ type HealthCheckStatusProbe struct {
 // DNSRecordSpec defines the desired state of DNSRecord
 // +kubebuilder:validation:XValidation:rule="!has(oldSelf.ownerID) || has(self.ownerID)", message="OwnerID can't be unset if it was previously set"
 // +kubebuilder:validation:XValidation:rule="has(oldSelf.ownerID) || !has(self.ownerID)", message="OwnerID can't be set if it was previously unset"
+// +kubebuilder:validation:XValidation:rule="has(oldSelf.registryZoneRef) == has(self.registryZoneRef)", message="RegistryZoneRef can't be added or removed"
+// +kubebuilder:validation:XValidation:rule="!has(self.migrateTo) || self.migrateTo.name != self.providerRef.name", message="MigrateTo must refer to a different provider secret than providerRef"
+// +kubebuilder:validation:XValidation:rule="!has(self.migrateTo) || !has(self.registryZoneRef)", message="MigrateTo can't be used with registryZoneRef"
+// +kubebuilder:validation:XValidation:rule="!has(self.adoptFrom) || !has(self.registryZoneRef)", message="AdoptFrom can't be used with registryZoneRef"
 type DNSRecordSpec struct {
 	// ownerID is a unique string used to identify the owner of this record.
 	// If unset or set to an empty string the record UID will be used.
@@ -106,13 +145,186 @@ type DNSRecordSpec struct {
 	// providerRef is a reference to a provider secret.
 	ProviderRef ProviderRef `json:"providerRef"`
 
+	// migrateTo is a reference to a provider secret to migrate the record to.
+	// The endpoints are published to both providers until they resolve through the nameservers of the new zone,
+	// they are then removed from the current provider and providerRef is set to migrateTo.
+	// Removing migrateTo before the migration completes removes the endpoints from the new provider.
+	// +optional
+	MigrateTo *ProviderRef `json:"migrateTo,omitempty"`
+
 	// endpoints is a list of endpoints that will be published into the dns provider.
 	// +kubebuilder:validation:MinItems=1
 	// +optional
 	Endpoints []*externaldns.Endpoint `json:"endpoints,omitempty"`
 
+	// gatewayEndpoints is a list of endpoints with targets taken from the addresses of a Gateway API Gateway.
+	// The targets are kept up to date as the addresses of the Gateway change.
+	// +optional
+	GatewayEndpoints []GatewayEndpoint `json:"gatewayEndpoints,omitempty"`
+
 	// +optional
 	HealthCheck *HealthCheckSpec `json:"healthCheck,omitempty"`
+
+	// mail is a set of SPF, DKIM and DMARC records for a mail domain, published as TXT records.
+	// +optional
+	Mail *MailSpec `json:"mail,omitempty"`
+
+	// excludeDNSNames are regular expressions matching the DNS names of endpoints that must never be published,
+	// in addition to those excluded by the operator.
+	// +optional
+	ExcludeDNSNames []string `json:"excludeDNSNames,omitempty"`
+
+	// excludeTargetCIDRs are IP ranges that the targets of A and AAAA endpoints must never be published in, in
+	// addition to those excluded by the operator. Endpoints left without targets are not published.
+	// +optional
+	ExcludeTargetCIDRs []string `json:"excludeTargetCIDRs,omitempty"`
+
+	// registryZoneRef is a reference to a separate zone the registry TXT records of the endpoints are written to,
+	// instead of the zone the endpoints are published in.
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="RegistryZoneRef is immutable"
+	// +optional
+	RegistryZoneRef *RegistryZoneRef `json:"registryZoneRef,omitempty"`
+
+	// adoptFrom configures the adoption of endpoints of the record that were published by another external-dns
+	// instance. Their registry TXT records are rewritten to be owned by this record, a batch at a time.
+	// +optional
+	AdoptFrom *ExternalDNSAdoption `json:"adoptFrom,omitempty"`
+
+	// parked replaces the endpoints of the record with a single endpoint for the rootHost pointing to the parking
+	// target of the operator, e.g. a sorry page. The endpoints are restored when parked is unset.
+	// +optional
+	Parked bool `json:"parked,omitempty"`
+
+	// lingerPolicy is how targets removed from the endpoints of the record are removed from the zone. With TTL the
+	// remaining targets are published right away and each removed target is kept in the zone for one TTL of its
+	// endpoint, so clients that resolved it before the change are not sent to nothing. Defaults to None, removing
+	// targets right away.
+	// +optional
+	LingerPolicy LingerPolicy `json:"lingerPolicy,omitempty"`
+}
+
+// ExternalDNSAdoption identifies the registry TXT records of an external-dns instance that endpoints are adopted from.
+type ExternalDNSAdoption struct {
+	// owners are the owner ids of the external-dns instances the endpoints are adopted from.
+	// +kubebuilder:validation:MinItems=1
+	Owners []string `json:"owners"`
+
+	// txtPrefix is the prefix of the registry TXT records of the external-dns instances.
+	// +optional
+	TXTPrefix string `json:"txtPrefix,omitempty"`
+
+	// txtSuffix is the suffix of the registry TXT records of the external-dns instances.
+	// +optional
+	TXTSuffix string `json:"txtSuffix,omitempty"`
+}
+
+// RegistryZoneRef is a reference to a zone that holds the registry TXT records of a DNSRecord.
+type RegistryZoneRef struct {
+	// providerRef is a reference to the provider secret of the registry zone.
+	// Defaults to the providerRef of the DNSRecord.
+	// +optional
+	ProviderRef *ProviderRef `json:"providerRef,omitempty"`
+
+	// domainName is the domain name of the registry zone.
+	// +kubebuilder:validation:MinLength=1
+	DomainName string `json:"domainName"`
+}
+
+// MailSpec is a set of mail authentication records for a mail domain.
+type MailSpec struct {
+	// domain is the mail domain the records are published for, defaults to the rootHost.
+	// +optional
+	Domain string `json:"domain,omitempty"`
+
+	// recordTTL is the TTL of the published records in seconds.
+	// +optional
+	RecordTTL externaldns.TTL `json:"recordTTL,omitempty"`
+
+	// +optional
+	SPF *SPFSpec `json:"spf,omitempty"`
+
+	// +optional
+	DKIM []DKIMSpec `json:"dkim,omitempty"`
+
+	// +optional
+	DMARC *DMARCSpec `json:"dmarc,omitempty"`
+}
+
+// SPFSpec is the Sender Policy Framework record of a mail domain, published at the mail domain.
+type SPFSpec struct {
+	// mechanisms are the SPF mechanisms and modifiers of the record in order, e.g. "mx", "ip4:192.0.2.0/24" or
+	// "include:_spf.example.com". The trailing all mechanism is set with all.
+	// +kubebuilder:validation:MinItems=1
+	Mechanisms []string `json:"mechanisms"`
+
+	// all is the qualifier of the trailing all mechanism.
+	// +kubebuilder:validation:Enum="-";"~";"?";"+"
+	// +kubebuilder:default="~"
+	// +optional
+	All string `json:"all,omitempty"`
+}
+
+// DKIMSpec is a DomainKeys Identified Mail public key of a mail domain, published at
+// <selector>._domainkey.<domain>.
+type DKIMSpec struct {
+	// selector is the DKIM selector of the key.
+	// +kubebuilder:validation:MinLength=1
+	Selector string `json:"selector"`
+
+	// keyType is the type of the key.
+	// +kubebuilder:validation:Enum=rsa;ed25519
+	// +kubebuilder:default=rsa
+	// +optional
+	KeyType string `json:"keyType,omitempty"`
+
+	// publicKey is the base64 encoded public key, a DER encoded SubjectPublicKeyInfo for rsa keys or the raw key for
+	// ed25519 keys. PEM armour and whitespace are removed.
+	// +kubebuilder:validation:MinLength=1
+	PublicKey string `json:"publicKey"`
+}
+
+// DMARCSpec is the Domain-based Message Authentication, Reporting and Conformance policy of a mail domain, published
+// at _dmarc.<domain>.
+type DMARCSpec struct {
+	// policy is the policy for mail failing authentication.
+	// +kubebuilder:validation:Enum=none;quarantine;reject
+	Policy string `json:"policy"`
+
+	// subdomainPolicy is the policy for mail from subdomains, defaults to policy.
+	// +kubebuilder:validation:Enum=none;quarantine;reject
+	// +optional
+	SubdomainPolicy string `json:"subdomainPolicy,omitempty"`
+
+	// percentage is the percentage of mail the policy is applied to.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	Percentage *int `json:"percentage,omitempty"`
+
+	// aggregateReports are the mailto: URIs aggregate reports are sent to.
+	// +optional
+	AggregateReports []string `json:"aggregateReports,omitempty"`
+
+	// failureReports are the mailto: URIs failure reports are sent to.
+	// +optional
+	FailureReports []string `json:"failureReports,omitempty"`
+}
+
+// GatewayEndpoint is an endpoint that is an alias for a Gateway API Gateway in the same namespace as the DNSRecord.
+// An A record is published for IPv4 addresses, an AAAA record for IPv6 addresses and a CNAME record for hostname
+// addresses in the Gateway status.
+type GatewayEndpoint struct {
+	// dnsName is the hostname of the endpoint.
+	// +kubebuilder:validation:MinLength=1
+	DNSName string `json:"dnsName"`
+
+	// gatewayName is the name of the Gateway.
+	// +kubebuilder:validation:MinLength=1
+	GatewayName string `json:"gatewayName"`
+
+	// recordTTL is the TTL of the published records in seconds.
+	// +optional
+	RecordTTL externaldns.TTL `json:"recordTTL,omitempty"`
 }
 
 // DNSRecordStatus defines the observed state of DNSRecord
@@ -134,6 +346,13 @@ type DNSRecordStatus struct {
 	// ValidFor indicates duration since the last reconciliation we consider data in the record to be valid
 	ValidFor string `json:"validFor,omitempty"`
 
+	// nextValidation is the time the record is next reconciled against the provider, if it is not changed before.
+	// The last reconcile is valid until this time, reconciles before it that are not caused by a change to the record
+	// or its health checks don't read the provider. A record that is not reconciled well after this time has fallen
+	// out of the reconcile schedule.
+	// +optional
+	NextValidation *metav1.Time `json:"nextValidation,omitempty"`
+
 	// WriteCounter represent a number of consecutive write attempts on the same generation of the record.
 	// It is being reset to 0 when the generation changes or there are no changes to write.
 	WriteCounter int64 `json:"writeCounter,omitempty"`
@@ -141,6 +360,16 @@ type DNSRecordStatus struct {
 	// endpoints are the last endpoints that were successfully published to the provider zone
 	Endpoints []*externaldns.Endpoint `json:"endpoints,omitempty"`
 
+	// endpointStatuses are the publish state of each endpoint of the record after the last reconcile, with the reason
+	// endpoints that were not published were left out.
+	// +optional
+	EndpointStatuses []EndpointStatus `json:"endpointStatuses,omitempty"`
+
+	// flattenedEndpoints are the addresses the targets of the CNAME endpoints with the flatten provider specific
+	// property resolved to in the last reconcile, published as A and AAAA records in place of the CNAME records.
+	// +optional
+	FlattenedEndpoints []FlattenedEndpoint `json:"flattenedEndpoints,omitempty"`
+
 	// ZoneEndpoints are all the endpoints for the DNSRecordSpec.RootHost that are present in the provider
 	ZoneEndpoints []*externaldns.Endpoint `json:"relatedEndpoints,omitempty"`
 
@@ -149,6 +378,10 @@ type DNSRecordStatus struct {
 	// ownerID is a unique string used to identify the owner of this record.
 	OwnerID string `json:"ownerID,omitempty"`
 
+	// previousOwnerID is the owner ID the record had before its owner ID was migrated. The registry TXT records
+	// of the record are rewritten from the previous owner ID to the owner ID before it is cleared.
+	PreviousOwnerID string `json:"previousOwnerID,omitempty"`
+
 	// DomainOwners is a list of all the owners working against the root domain of this record
 	DomainOwners []string `json:"domainOwners,omitempty"`
 
@@ -157,16 +390,297 @@ type DNSRecordStatus struct {
 
 	// zoneDomainName is the domain name of the zone that the dns record is publishing endpoints
 	ZoneDomainName string `json:"zoneDomainName,omitempty"`
+
+	// zoneVisibility is the visibility of the zone that the dns record is publishing endpoints, when known.
+	// +optional
+	ZoneVisibility ZoneVisibility `json:"zoneVisibility,omitempty"`
+
+	// registryZoneID is the provider specific id of the zone the registry TXT records are written to, when
+	// registryZoneRef is set
+	// +optional
+	RegistryZoneID string `json:"registryZoneID,omitempty"`
+
+	// history is the revisions of the endpoints of the record that were successfully published, oldest first.
+	// The record can be rolled back to a revision with the kuadrant.io/rollback-to annotation.
+	// +optional
+	History []DNSRecordRevision `json:"history,omitempty"`
+
+	// lastHandledReconcileRequest is the value of the kuadrant.io/reconcile-requested-at annotation when the record
+	// was last reconciled.
+	// +optional
+	LastHandledReconcileRequest string `json:"lastHandledReconcileRequest,omitempty"`
+
+	// pendingChanges is the ids of the changes submitted to the provider that are still propagating to all of its
+	// nameservers. Only set for providers that report the propagation state of their changes.
+	// +optional
+	PendingChanges []string `json:"pendingChanges,omitempty"`
+
+	// providerError describes the last error returned by the provider of the record, if the last reconcile failed
+	// because of it.
+	// +optional
+	ProviderError *ProviderError `json:"providerError,omitempty"`
+
+	// parkedEndpoints are the endpoints that were published to the provider zone before the record was parked.
+	// +optional
+	ParkedEndpoints []*externaldns.Endpoint `json:"parkedEndpoints,omitempty"`
+
+	// migration is the state of the migration of the record to the provider of migrateTo.
+	// +optional
+	Migration *MigrationStatus `json:"migration,omitempty"`
+
+	// dampening is the state of the changes to the endpoints of the record held back until they are stable, when
+	// the operator dampens changes.
+	// +optional
+	Dampening *DampeningStatus `json:"dampening,omitempty"`
+
+	// lingeringTargets are the targets removed from the endpoints of the record that are kept in the zone until they
+	// expire from the caches of resolvers, when the linger policy of the record is TTL.
+	// +optional
+	LingeringTargets []LingeringTarget `json:"lingeringTargets,omitempty"`
+
+	// domainVerification is the TXT record that verifies ownership of the root host before the record is first
+	// published, when the operator requires domain verification.
+	// +optional
+	DomainVerification *DomainVerificationStatus `json:"domainVerification,omitempty"`
+
+	// acmeChallenge is the state of the garbage collection of a record that only publishes ACME DNS-01 challenges.
+	// +optional
+	ACMEChallenge *ACMEChallengeStatus `json:"acmeChallenge,omitempty"`
+
+	// phase is a high-level summary of the state of the record, computed from its conditions.
+	// +optional
+	Phase DNSRecordPhase `json:"phase,omitempty"`
 }
 
+// EndpointStatusReason is the reason an endpoint of a DNSRecord was not published.
+type EndpointStatusReason string
+
+const (
+	// EndpointStatusReasonExcluded the endpoint is excluded by the exclusions of the operator or the record
+	EndpointStatusReasonExcluded EndpointStatusReason = "Excluded"
+	// EndpointStatusReasonUnhealthy all targets of the endpoint failed their health checks
+	EndpointStatusReasonUnhealthy EndpointStatusReason = "Unhealthy"
+	// EndpointStatusReasonRejected the endpoint failed validation and was left out of a partially published record
+	EndpointStatusReasonRejected EndpointStatusReason = "Rejected"
+	// EndpointStatusReasonRecordNotPublished the record failed to be published as a whole
+	EndpointStatusReasonRecordNotPublished EndpointStatusReason = "RecordNotPublished"
+)
+
+// EndpointStatus is the publish state of an endpoint of a DNSRecord.
+type EndpointStatus struct {
+	// dnsName is the DNS name of the endpoint.
+	DNSName string `json:"dnsName"`
+
+	// recordType is the record type of the endpoint.
+	RecordType string `json:"recordType"`
+
+	// setIdentifier is the set identifier of the endpoint.
+	// +optional
+	SetIdentifier string `json:"setIdentifier,omitempty"`
+
+	// published is true when the endpoint was published to the provider zone by the last reconcile.
+	Published bool `json:"published"`
+
+	// reason is why the endpoint was not published.
+	// +optional
+	Reason EndpointStatusReason `json:"reason,omitempty"`
+
+	// message describes why the endpoint was not published, e.g. the error of the last reconcile.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// FlattenedEndpoint is a CNAME endpoint of a DNSRecord published as the addresses its target resolves to.
+type FlattenedEndpoint struct {
+	// dnsName is the DNS name of the endpoint.
+	DNSName string `json:"dnsName"`
+
+	// setIdentifier is the set identifier of the endpoint.
+	// +optional
+	SetIdentifier string `json:"setIdentifier,omitempty"`
+
+	// target is the CNAME target that was resolved.
+	Target string `json:"target"`
+
+	// addresses are the IPv4 and IPv6 addresses the target resolved to, published in place of the CNAME record.
+	// +optional
+	Addresses []string `json:"addresses,omitempty"`
+}
+
+// DNSRecordRevision is a set of endpoints of a DNSRecord that was successfully published.
+type DNSRecordRevision struct {
+	// revision is the number of the revision, incremented for each new set of endpoints.
+	Revision int64 `json:"revision"`
+
+	// generation is the generation of the record the endpoints were published for.
+	Generation int64 `json:"generation,omitempty"`
+
+	// appliedAt is the time the endpoints were published.
+	AppliedAt metav1.Time `json:"appliedAt,omitempty"`
+
+	// endpoints are the endpoints of the record spec.
+	Endpoints []*externaldns.Endpoint `json:"endpoints,omitempty"`
+}
+
+// MigrationStatus is the state of the migration of a DNSRecord to another provider.
+type MigrationStatus struct {
+	// providerRef is the provider secret the record is being migrated to.
+	ProviderRef ProviderRef `json:"providerRef"`
+
+	// zoneID is the provider specific id of the zone the record is being migrated to.
+	ZoneID string `json:"zoneID,omitempty"`
+
+	// zoneDomainName is the domain name of the zone the record is being migrated to.
+	ZoneDomainName string `json:"zoneDomainName,omitempty"`
+
+	// endpoints are the last endpoints that were successfully published to the zone the record is being migrated to.
+	Endpoints []*externaldns.Endpoint `json:"endpoints,omitempty"`
+}
+
+// DampeningStatus is the state of the changes to the endpoints of a DNSRecord held back until they are stable.
+type DampeningStatus struct {
+	// publishedHash is a hash of the endpoints of the record that were last allowed to be published.
+	PublishedHash string `json:"publishedHash,omitempty"`
+
+	// pendingHash is a hash of the endpoints of the record that are held back, empty when no changes are held back.
+	// +optional
+	PendingHash string `json:"pendingHash,omitempty"`
+
+	// changedAt is the time the endpoints of the record last changed.
+	// +optional
+	ChangedAt metav1.Time `json:"changedAt,omitempty"`
+
+	// window is how long the endpoints must be unchanged for before they are published. It is doubled each time they
+	// change while a change is held back, and reset once they are stable.
+	// +optional
+	Window string `json:"window,omitempty"`
+
+	// suppressedChanges is the number of changes to the endpoints that were replaced before they were published,
+	// since the endpoints were last stable.
+	// +optional
+	SuppressedChanges int64 `json:"suppressedChanges,omitempty"`
+}
+
+// LingeringTarget is a target removed from an endpoint of a DNSRecord that is kept in the zone until it is removed.
+type LingeringTarget struct {
+	// dnsName is the DNS name of the endpoint.
+	DNSName string `json:"dnsName"`
+
+	// recordType is the record type of the endpoint.
+	RecordType string `json:"recordType"`
+
+	// setIdentifier is the set identifier of the endpoint.
+	// +optional
+	SetIdentifier string `json:"setIdentifier,omitempty"`
+
+	// target is the target removed from the endpoint.
+	Target string `json:"target"`
+
+	// removeAt is the time the target is removed from the zone, one TTL of the endpoint after it was removed from
+	// the record.
+	RemoveAt metav1.Time `json:"removeAt"`
+}
+
+// DomainVerificationStatus is the TXT record that verifies ownership of the root host of a DNSRecord.
+type DomainVerificationStatus struct {
+	// recordName is the name of the TXT record the owner of the root host must create.
+	RecordName string `json:"recordName"`
+
+	// recordValue is the value the TXT record must have.
+	RecordValue string `json:"recordValue"`
+
+	// verifiedAt is the time the TXT record was found, unset until ownership of the root host is verified.
+	// +optional
+	VerifiedAt *metav1.Time `json:"verifiedAt,omitempty"`
+}
+
+// ACMEChallengeStatus is the state of the garbage collection of a DNSRecord that only publishes ACME DNS-01 challenges.
+type ACMEChallengeStatus struct {
+	// generation is the generation of the record the challenges were last updated in.
+	Generation int64 `json:"generation"`
+
+	// updatedAt is the time the challenges were last updated, the creation time of the record if they never were.
+	UpdatedAt metav1.Time `json:"updatedAt"`
+
+	// ttlError is why the challenge TTL of the zone of the record could not be read, if it could not.
+	// +optional
+	TTLError string `json:"ttlError,omitempty"`
+}
+
+// DNSRecordPhase is a high-level summary of where a DNSRecord is in its lifecycle.
+// +kubebuilder:validation:Enum=Pending;Publishing;Ready;Degraded;Deleting;Conflict
+type DNSRecordPhase string
+
+const (
+	// DNSRecordPhasePending the record has not yet been published to the provider.
+	DNSRecordPhasePending DNSRecordPhase = "Pending"
+
+	// DNSRecordPhasePublishing changes have been written to the provider and are awaiting validation.
+	DNSRecordPhasePublishing DNSRecordPhase = "Publishing"
+
+	// DNSRecordPhaseReady the provider has been ensured to contain the desired state of the record.
+	DNSRecordPhaseReady DNSRecordPhase = "Ready"
+
+	// DNSRecordPhaseDegraded the record was previously published but is now failing or unhealthy.
+	DNSRecordPhaseDegraded DNSRecordPhase = "Degraded"
+
+	// DNSRecordPhaseDeleting the record is being removed from the provider.
+	DNSRecordPhaseDeleting DNSRecordPhase = "Deleting"
+
+	// DNSRecordPhaseConflict the record conflicts with records owned by another owner in the zone.
+	DNSRecordPhaseConflict DNSRecordPhase = "Conflict"
+)
+
+// ProviderError is a machine-readable description of an error returned by a provider.
+type ProviderError struct {
+	// code is the classification of the error.
+	// +kubebuilder:validation:Enum=Unknown;Throttled;Unavailable;Unauthorized;NotFound;ZoneNotFound;InvalidRequest;Conflict
+	Code string `json:"code"`
+
+	// provider is the name of the provider that returned the error, e.g. aws.
+	// +optional
+	Provider string `json:"provider,omitempty"`
+
+	// zoneID is the id of the zone the request that failed was made for, if the record has a zone assigned.
+	// +optional
+	ZoneID string `json:"zoneID,omitempty"`
+
+	// retryable is true if the request can succeed when retried without any changes to the record or the provider
+	// credentials.
+	Retryable bool `json:"retryable"`
+}
+
+// LingerPolicy is how targets removed from the endpoints of a DNSRecord are removed from the zone.
+// +kubebuilder:validation:Enum=None;TTL
+type LingerPolicy string
+
+const (
+	// LingerPolicyNone removed targets are removed from the zone right away
+	LingerPolicyNone LingerPolicy = "None"
+	// LingerPolicyTTL removed targets are kept in the zone for one TTL of their endpoint
+	LingerPolicyTTL LingerPolicy = "TTL"
+)
+
+// ZoneVisibility is whether a zone is resolvable from the internet or only from private networks.
+// +kubebuilder:validation:Enum=Public;Private
+type ZoneVisibility string
+
+const (
+	ZoneVisibilityPublic  ZoneVisibility = "Public"
+	ZoneVisibilityPrivate ZoneVisibility = "Private"
+)
+
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
+//+kubebuilder:storageversion
 //+kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].status",description="DNSRecord ready."
+//+kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase",description="DNSRecord phase."
 //+kubebuilder:printcolumn:name="Healthy",type="string",JSONPath=".status.conditions[?(@.type==\"Healthy\")].status",description="DNSRecord healthy.",priority=2
 //+kubebuilder:printcolumn:name="Root Host",type="string",JSONPath=".spec.rootHost",description="DNSRecord root host.",priority=2
 //+kubebuilder:printcolumn:name="Owner ID",type="string",JSONPath=".status.ownerID",description="DNSRecord owner id.",priority=2
 //+kubebuilder:printcolumn:name="Zone Domain",type="string",JSONPath=".status.zoneDomainName",description="DNSRecord zone domain name.",priority=2
 //+kubebuilder:printcolumn:name="Zone ID",type="string",JSONPath=".status.zoneID",description="DNSRecord zone id.",priority=2
+//+kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 
 // DNSRecord is the Schema for the dnsrecords API
 type DNSRecord struct {
@@ -203,6 +717,91 @@ const (
 
 const WildcardPrefix = "*."
 
+// UnownedPublishAnnotation when set to "true" on a DNSRecord, its endpoints are published without creating registry
+// TXT records for them and are never deleted from the provider. The root host must be in one of the domains allowed
+// by the operator for unowned publishing.
+const UnownedPublishAnnotation = "kuadrant.io/unowned-publish"
+
+// DedicatedZoneAnnotation when set to "true" on a DNSRecord, the record is the only writer to its zone. Its endpoints
+// are published without registry TXT records and any record of a managed type in the zone that is not an endpoint of
+// the record is removed. The record is only published while no other DNSRecord is assigned to the zone and the zone
+// holds no registry TXT records of other owners.
+const DedicatedZoneAnnotation = "kuadrant.io/dedicated-zone"
+
+// DriftAcknowledgedAnnotation when set on a DNSRecord that is not published because its endpoints were changed in the
+// zone outside of the operator, the changes are overwritten with the endpoints of the record. The annotation is removed
+// once the record is published.
+const DriftAcknowledgedAnnotation = "kuadrant.io/drift-acknowledged"
+
+// TakeoverConfirmedAnnotation when set on a DNSRecord that is not published because its changes would remove the
+// targets or ownership of other owners of shared endpoints, to the token reported in its TakeoverPending condition, the
+// changes are published. The annotation is removed once the record is published.
+const TakeoverConfirmedAnnotation = "kuadrant.io/confirm-takeover"
+
+// ReconcileRequestAnnotation changing the value of this annotation on a DNSRecord, e.g. to the current time, requests
+// that the record is reconciled against the provider immediately, rather than when the validity of its last reconcile
+// expires. An event is emitted when the request is handled.
+const ReconcileRequestAnnotation = "kuadrant.io/reconcile-requested-at"
+
+// ForceApplyAnnotation when set to "true" on a DNSRecord, all of its endpoints and their registry TXT records are
+// written to the provider on the next reconcile, even when they are up to date, to repair records suspected to be
+// corrupted in the provider. The annotation is removed once the record is published and events are emitted for the
+// rewrite.
+const ForceApplyAnnotation = "kuadrant.io/force-apply"
+
+// RollbackToAnnotation when set on a DNSRecord to the number of a revision in the history of the record, the endpoints
+// of the revision are restored to the spec and published. The annotation is removed once the spec is updated.
+const RollbackToAnnotation = "kuadrant.io/rollback-to"
+
+// ZoneLookupAnnotation changing the value of this annotation on a DNSRecord forces a new zone lookup for the record,
+// when a previous lookup found no zone for the root host.
+const ZoneLookupAnnotation = "kuadrant.io/zone-lookup"
+
+// TTLJitterAnnotation when set on a DNSRecord to a percentage between 0 and 50, the TTL of each published endpoint is
+// shifted by up to that percentage of the TTL in either direction, so caches of the endpoints don't all expire at once.
+// The shift of each endpoint is stable and the TTLs of the spec are unchanged, the published TTLs are in the status.
+const TTLJitterAnnotation = "kuadrant.io/ttl-jitter"
+
+// PartialPublishAnnotation when set to "true" on a DNSRecord, endpoints that fail validation are left out and the
+// other endpoints are published, rather than the record not being published at all. The endpoints left out are
+// reported in the endpoint statuses of the record and the PartiallyPublished condition.
+const PartialPublishAnnotation = "kuadrant.io/partial-publish"
+
+// SpecHashAnnotation is set by the DNSRecord defaulting webhook to a hash of the canonical form of the spec of the
+// record, so records with semantically identical specs have the same hash regardless of the order their endpoints were
+// written in.
+const SpecHashAnnotation = "kuadrant.io/spec-hash"
+
+// MaxTTLJitter is the maximum percentage of the TTL of an endpoint it can be shifted by with the TTLJitterAnnotation
+const MaxTTLJitter = 50
+
+// DefaultTTLAnnotation when set on a namespace to a number of seconds, is the TTL of the endpoints of the DNSRecords in
+// the namespace that don't set one. Namespace defaults are merged into records by the DNSRecord defaulting webhook.
+const DefaultTTLAnnotation = "kuadrant.io/default-ttl"
+
+// DefaultProviderRefAnnotation when set on a namespace to the name of a provider secret, is the provider secret of the
+// DNSRecords in the namespace that don't reference one.
+const DefaultProviderRefAnnotation = "kuadrant.io/default-provider-ref"
+
+// ZoneWarmupLabel when set to "true" on a provider secret, the zones of the secret are listed when the operator starts,
+// along with those of the provider secrets of the DefaultProviderRefAnnotation of namespaces. Requires the zone warm-up
+// of the operator to be enabled.
+const ZoneWarmupLabel = "kuadrant.io/zone-warmup"
+
+// DefaultHealthCheckAnnotation when set on a namespace to a health check spec in JSON, is the health check of the
+// DNSRecords in the namespace that don't define one.
+const DefaultHealthCheckAnnotation = "kuadrant.io/default-health-check"
+
+// GatewayDNSZoneAnnotation when set on a Gateway API Gateway to the domain of a zone, a DNSRecord is created for each
+// listener hostname of the Gateway in the zone, with the addresses of the Gateway as targets. Requires the Gateway
+// controller of the operator to be enabled.
+const GatewayDNSZoneAnnotation = "kuadrant.io/dns-zone"
+
+// GatewayDNSProviderRefAnnotation when set on a Gateway with the GatewayDNSZoneAnnotation to the name of a provider
+// secret, is the provider secret of the DNSRecords created for the Gateway. Defaults to the provider secret of the
+// DefaultProviderRefAnnotation of the namespace of the Gateway.
+const GatewayDNSProviderRefAnnotation = "kuadrant.io/dns-provider-ref"
+
 func (s *DNSRecord) Validate() error {
 	root := s.Spec.RootHost
 	if len(s.Spec.Endpoints) == 0 {
@@ -226,6 +825,37 @@ func (s *DNSRecord) Validate() error {
 	if !rootEndpointFound {
 		return fmt.Errorf("invalid endpoint set. rootHost is set but found no endpoint defining a record for the rootHost %s", root)
 	}
+	for _, expr := range s.Spec.ExcludeDNSNames {
+		if _, err := regexp.Compile(expr); err != nil {
+			return fmt.Errorf("invalid excludeDNSNames expression %s: %w", expr, err)
+		}
+	}
+	for _, cidr := range s.Spec.ExcludeTargetCIDRs {
+		if _, err := netip.ParsePrefix(cidr); err != nil {
+			return fmt.Errorf("invalid excludeTargetCIDRs range %s: %w", cidr, err)
+		}
+	}
+	if s.Spec.Mail != nil {
+		if err := s.Spec.Mail.Validate(); err != nil {
+			return fmt.Errorf("invalid mail records: %w", err)
+		}
+	}
+	if _, err := s.TTLJitter(); err != nil {
+		return err
+	}
+	if s.IsDedicatedZone() && (s.IsUnownedPublish() || s.Spec.RegistryZoneRef != nil || s.Spec.AdoptFrom != nil) {
+		return fmt.Errorf("%s annotation can't be used with unowned publishing, registryZoneRef or adoptFrom", DedicatedZoneAnnotation)
+	}
+	return nil
+}
+
+// ValidateTargets returns an error for the first endpoint with a target that is not valid for its record type.
+func (s *DNSRecord) ValidateTargets() error {
+	for _, ep := range s.Spec.Endpoints {
+		if err := ValidateEndpointTargets(ep); err != nil {
+			return fmt.Errorf("invalid endpoint target: %w", err)
+		}
+	}
 	return nil
 }
 
@@ -236,6 +866,52 @@ func (s *DNSRecord) GetUIDHash() string {
 	return hash.ToBase36HashLen(string(s.GetUID()), 8)
 }
 
+// Canonicalize sorts the endpoints of the spec by name, type and set identifier, and the targets and provider specific
+// properties of each endpoint, and the gateway endpoints by name, so specs that only differ in the order of these lists
+// are identical.
+func (s *DNSRecordSpec) Canonicalize() {
+	for _, ep := range s.Endpoints {
+		if ep == nil {
+			continue
+		}
+		sort.Strings(ep.Targets)
+		sort.SliceStable(ep.ProviderSpecific, func(i, j int) bool {
+			return ep.ProviderSpecific[i].Name < ep.ProviderSpecific[j].Name
+		})
+	}
+	sort.SliceStable(s.Endpoints, func(i, j int) bool {
+		a, b := s.Endpoints[i], s.Endpoints[j]
+		if a == nil || b == nil {
+			return b != nil
+		}
+		if a.DNSName != b.DNSName {
+			return a.DNSName < b.DNSName
+		}
+		if a.RecordType != b.RecordType {
+			return a.RecordType < b.RecordType
+		}
+		return a.SetIdentifier < b.SetIdentifier
+	})
+	sort.SliceStable(s.GatewayEndpoints, func(i, j int) bool {
+		a, b := s.GatewayEndpoints[i], s.GatewayEndpoints[j]
+		if a.DNSName != b.DNSName {
+			return a.DNSName < b.DNSName
+		}
+		return a.GatewayName < b.GatewayName
+	})
+}
+
+// Hash returns a hash of the canonical form of the spec. The spec is not changed.
+func (s *DNSRecordSpec) Hash() (string, error) {
+	spec := s.DeepCopy()
+	spec.Canonicalize()
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return "", err
+	}
+	return hash.ToBase36SHA256Hash(string(data)), nil
+}
+
 func (s *DNSRecord) GetProviderRef() ProviderRef {
 	return s.Spec.ProviderRef
 }
@@ -244,6 +920,40 @@ func (s *DNSRecord) HasDNSZoneAssigned() bool {
 	return s.Status.ZoneID != "" && s.Status.ZoneDomainName != ""
 }
 
+// IsUnownedPublish returns true if the record requests its endpoints to be published without ownership.
+func (s *DNSRecord) IsUnownedPublish() bool {
+	return s.GetAnnotations()[UnownedPublishAnnotation] == "true"
+}
+
+// IsDedicatedZone returns true if the record requests to be published as the only writer to its zone.
+func (s *DNSRecord) IsDedicatedZone() bool {
+	return s.GetAnnotations()[DedicatedZoneAnnotation] == "true"
+}
+
+// IsPartialPublish returns true if endpoints of the record that fail validation are left out of its publishing.
+func (s *DNSRecord) IsPartialPublish() bool {
+	return s.GetAnnotations()[PartialPublishAnnotation] == "true"
+}
+
+// IsForceApply returns true if all endpoints of the record are to be written to the provider on the next reconcile.
+func (s *DNSRecord) IsForceApply() bool {
+	return s.GetAnnotations()[ForceApplyAnnotation] == "true"
+}
+
+// TTLJitter returns the percentage of the TTL of each endpoint it is shifted by when published, 0 if the TTLs are
+// published unchanged.
+func (s *DNSRecord) TTLJitter() (int, error) {
+	value, ok := s.GetAnnotations()[TTLJitterAnnotation]
+	if !ok {
+		return 0, nil
+	}
+	jitter, err := strconv.Atoi(strings.TrimSuffix(value, "%"))
+	if err != nil || jitter < 0 || jitter > MaxTTLJitter {
+		return 0, fmt.Errorf("invalid %s annotation %q, must be a percentage between 0 and %d", TTLJitterAnnotation, value, MaxTTLJitter)
+	}
+	return jitter, nil
+}
+
 func (s *DNSRecord) HasOwnerIDAssigned() bool {
 	return s.Status.OwnerID != ""
 }
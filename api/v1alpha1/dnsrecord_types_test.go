@@ -89,3 +89,77 @@ func TestValidate(t *testing.T) {
 		})
 	}
 }
+
+func TestTTLJitter(t *testing.T) {
+	tests := []struct {
+		annotations map[string]string
+		want        int
+		wantErr     bool
+	}{
+		{annotations: nil, want: 0},
+		{annotations: map[string]string{TTLJitterAnnotation: "10"}, want: 10},
+		{annotations: map[string]string{TTLJitterAnnotation: "25%"}, want: 25},
+		{annotations: map[string]string{TTLJitterAnnotation: "51"}, wantErr: true},
+		{annotations: map[string]string{TTLJitterAnnotation: "-1"}, wantErr: true},
+		{annotations: map[string]string{TTLJitterAnnotation: "some"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		record := &DNSRecord{}
+		record.SetAnnotations(tt.annotations)
+		got, err := record.TTLJitter()
+		if (err != nil) != tt.wantErr {
+			t.Errorf("TTLJitter() error = %v, wantErr %v", err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("TTLJitter() = %d, want %d", got, tt.want)
+		}
+	}
+}
+
+func TestValidateDedicatedZone(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		spec        DNSRecordSpec
+		wantErr     bool
+	}{
+		{
+			name:        "dedicated zone",
+			annotations: map[string]string{DedicatedZoneAnnotation: "true"},
+		},
+		{
+			name:        "dedicated zone with unowned publish",
+			annotations: map[string]string{DedicatedZoneAnnotation: "true", UnownedPublishAnnotation: "true"},
+			wantErr:     true,
+		},
+		{
+			name:        "dedicated zone with registry zone",
+			annotations: map[string]string{DedicatedZoneAnnotation: "true"},
+			spec:        DNSRecordSpec{RegistryZoneRef: &RegistryZoneRef{}},
+			wantErr:     true,
+		},
+		{
+			name:        "dedicated zone with adoption",
+			annotations: map[string]string{DedicatedZoneAnnotation: "true"},
+			spec:        DNSRecordSpec{AdoptFrom: &ExternalDNSAdoption{}},
+			wantErr:     true,
+		},
+		{
+			name:        "unowned publish with registry zone",
+			annotations: map[string]string{DedicatedZoneAnnotation: "false", UnownedPublishAnnotation: "true"},
+			spec:        DNSRecordSpec{RegistryZoneRef: &RegistryZoneRef{}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			record := &DNSRecord{Spec: tt.spec}
+			record.SetAnnotations(tt.annotations)
+			record.Spec.RootHost = "example.com"
+			record.Spec.Endpoints = []*endpoint.Endpoint{{DNSName: "example.com"}}
+			if err := record.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,259 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"fmt"
+	"net/netip"
+	"regexp"
+	"strconv"
+	"strings"
+
+	externaldns "sigs.k8s.io/external-dns/endpoint"
+)
+
+const (
+	// MaxTXTStringLength is the maximum length of a single character string of a TXT record, longer values are split
+	// into several quoted character strings.
+	MaxTXTStringLength = 255
+
+	maxDomainNameLength = 253
+
+	// RecordTypeNAPTR is a naming authority pointer record, e.g. of a SIP service. Its targets are the order,
+	// preference, quoted flags, service and regular expression, and replacement of the record, e.g.
+	// `100 10 "u" "E2U+sip" "!^.*$!sip:info@example.com!" .`
+	RecordTypeNAPTR = "NAPTR"
+	// RecordTypeTLSA is a DANE certificate association record. Its targets are the certificate usage, selector,
+	// matching type and hex encoded certificate association data of the record, e.g. `3 1 1 0c72ac70b745ac19...`
+	RecordTypeTLSA = "TLSA"
+)
+
+// dnsLabelRegexp matches a label of a domain name. Underscores are allowed for service labels, e.g. _acme-challenge,
+// and a label can be a wildcard.
+var dnsLabelRegexp = regexp.MustCompile(`^(\*|[a-zA-Z0-9_]([a-zA-Z0-9_-]{0,61}[a-zA-Z0-9_])?)$`)
+
+// ValidateEndpointTargets returns an error if a target of the endpoint is not valid for its record type. A targets must
+// be IPv4 addresses, or domain names for alias records, AAAA targets IPv6 addresses and CNAME and NS targets domain
+// names. TXT targets are a character string of at most MaxTXTStringLength characters, or quoted character strings of
+// at most MaxTXTStringLength characters each. NAPTR and TLSA targets must be in the presentation format of their
+// records. Targets of other record types are not validated.
+func ValidateEndpointTargets(ep *externaldns.Endpoint) error {
+	for _, target := range ep.Targets {
+		var err error
+		switch ep.RecordType {
+		case externaldns.RecordTypeA:
+			if alias, _ := ep.GetProviderSpecificProperty("alias"); alias == "true" {
+				err = validateDomainName(target)
+			} else if addr, parseErr := netip.ParseAddr(target); parseErr != nil || !addr.Is4() {
+				err = fmt.Errorf("must be an IPv4 address")
+			}
+		case externaldns.RecordTypeAAAA:
+			if addr, parseErr := netip.ParseAddr(target); parseErr != nil || !addr.Is6() {
+				err = fmt.Errorf("must be an IPv6 address")
+			}
+		case externaldns.RecordTypeCNAME, externaldns.RecordTypeNS:
+			err = validateDomainName(target)
+		case externaldns.RecordTypeTXT:
+			err = validateTXT(target)
+		case RecordTypeNAPTR:
+			err = validateNAPTR(target)
+		case RecordTypeTLSA:
+			err = validateTLSA(target)
+		}
+		if err != nil {
+			return fmt.Errorf("%s target %q of %s %s", ep.RecordType, target, ep.DNSName, err)
+		}
+	}
+	return nil
+}
+
+// validateDomainName returns an error if the name is not a domain name, with or without a trailing dot.
+func validateDomainName(name string) error {
+	if _, err := netip.ParseAddr(name); err == nil {
+		return fmt.Errorf("must be a domain name, not an IP address")
+	}
+	name = strings.TrimSuffix(name, ".")
+	if name == "" || len(name) > maxDomainNameLength {
+		return fmt.Errorf("must be a domain name of 1 to %d characters", maxDomainNameLength)
+	}
+	for _, label := range strings.Split(name, ".") {
+		if !dnsLabelRegexp.MatchString(label) {
+			return fmt.Errorf("must be a domain name, label %q is not valid", label)
+		}
+	}
+	return nil
+}
+
+// validateTXT returns an error if the value is not a single unquoted character string, or a sequence of quoted
+// character strings separated by spaces, of at most MaxTXTStringLength characters each.
+func validateTXT(value string) error {
+	if !strings.Contains(value, `"`) {
+		if len(value) > MaxTXTStringLength {
+			return fmt.Errorf("is longer than %d characters, longer values must be split into quoted strings", MaxTXTStringLength)
+		}
+		return nil
+	}
+
+	rest := strings.TrimSpace(value)
+	for rest != "" {
+		if rest[0] != '"' {
+			return fmt.Errorf("must be quoted strings separated by spaces when it contains quotes")
+		}
+		length, closed := 0, false
+		i := 1
+		for ; i < len(rest); i++ {
+			if rest[i] == '\\' && i+1 < len(rest) {
+				i++
+			} else if rest[i] == '"' {
+				closed = true
+				break
+			}
+			length++
+		}
+		if !closed {
+			return fmt.Errorf("has an unterminated quoted string")
+		}
+		if length > MaxTXTStringLength {
+			return fmt.Errorf("has a quoted string longer than %d characters", MaxTXTStringLength)
+		}
+		rest = rest[i+1:]
+		trimmed := strings.TrimLeft(rest, " \t")
+		if trimmed != "" && trimmed == rest {
+			return fmt.Errorf("must have spaces between quoted strings")
+		}
+		rest = trimmed
+	}
+	return nil
+}
+
+// validateNAPTR returns an error if the value is not the order and preference, the quoted flags, service and regular
+// expression, and the replacement of a NAPTR record. Only one of the regular expression and replacement can be set,
+// the replacement is "." when the regular expression is used.
+func validateNAPTR(value string) error {
+	fields, err := presentationFields(value)
+	if err != nil {
+		return err
+	}
+	if len(fields) != 6 {
+		return fmt.Errorf("must be the order, preference, quoted flags, service and regexp, and replacement of the record")
+	}
+	for _, field := range fields[:2] {
+		if _, err := strconv.ParseUint(field.value, 10, 16); err != nil || field.quoted {
+			return fmt.Errorf("must start with an order and preference between 0 and 65535")
+		}
+	}
+	for _, field := range fields[2:5] {
+		if !field.quoted {
+			return fmt.Errorf("must have quoted flags, service and regexp")
+		}
+		if len(field.value) > MaxTXTStringLength {
+			return fmt.Errorf("has a quoted string longer than %d characters", MaxTXTStringLength)
+		}
+	}
+	for _, flag := range fields[2].value {
+		if !('a' <= flag && flag <= 'z' || 'A' <= flag && flag <= 'Z' || '0' <= flag && flag <= '9') {
+			return fmt.Errorf("must have flags of letters and digits")
+		}
+	}
+	replacement := fields[5]
+	if replacement.quoted {
+		return fmt.Errorf("must end with an unquoted replacement domain name or \".\"")
+	}
+	if replacement.value == "." {
+		return nil
+	}
+	if fields[4].value != "" {
+		return fmt.Errorf("must have the replacement \".\" when the regexp is set")
+	}
+	return validateDomainName(replacement.value)
+}
+
+// validateTLSA returns an error if the value is not the certificate usage, selector, matching type and hex encoded
+// certificate association data of a TLSA record. The data of the SHA-256 and SHA-512 matching types must be a digest.
+func validateTLSA(value string) error {
+	fields := strings.Fields(value)
+	if len(fields) < 4 {
+		return fmt.Errorf("must be the usage, selector, matching type and certificate association data of the record")
+	}
+	limits := []uint64{3, 1, 2}
+	numbers := make([]uint64, len(limits))
+	for i, limit := range limits {
+		number, err := strconv.ParseUint(fields[i], 10, 8)
+		if err != nil || number > limit {
+			return fmt.Errorf("must have a usage of 0 to 3, selector of 0 or 1 and matching type of 0 to 2")
+		}
+		numbers[i] = number
+	}
+	data := strings.Join(fields[3:], "")
+	if _, err := hex.DecodeString(data); err != nil {
+		return fmt.Errorf("must have hex encoded certificate association data")
+	}
+	if digest := map[uint64]int{1: sha256.Size, 2: sha512.Size}[numbers[2]]; digest > 0 && len(data) != 2*digest {
+		return fmt.Errorf("must have certificate association data of %d bytes for matching type %d", digest, numbers[2])
+	}
+	return nil
+}
+
+// presentationField is a field of a record in presentation format
+type presentationField struct {
+	value  string
+	quoted bool
+}
+
+// presentationFields returns the fields of a record in presentation format, separated by spaces. Quoted fields can
+// contain spaces and escaped quotes, and are returned without the quotes.
+func presentationFields(value string) ([]presentationField, error) {
+	var fields []presentationField
+	rest := strings.TrimSpace(value)
+	for rest != "" {
+		if rest[0] != '"' {
+			end := strings.IndexAny(rest, " \t")
+			if end < 0 {
+				end = len(rest)
+			}
+			if strings.Contains(rest[:end], `"`) {
+				return nil, fmt.Errorf("must have spaces between quoted strings")
+			}
+			fields = append(fields, presentationField{value: rest[:end]})
+			rest = strings.TrimLeft(rest[end:], " \t")
+			continue
+		}
+		i, closed := 1, false
+		for ; i < len(rest); i++ {
+			if rest[i] == '\\' && i+1 < len(rest) {
+				i++
+			} else if rest[i] == '"' {
+				closed = true
+				break
+			}
+		}
+		if !closed {
+			return nil, fmt.Errorf("has an unterminated quoted string")
+		}
+		fields = append(fields, presentationField{value: rest[1:i], quoted: true})
+		rest = rest[i+1:]
+		trimmed := strings.TrimLeft(rest, " \t")
+		if trimmed != "" && trimmed == rest {
+			return nil, fmt.Errorf("must have spaces between quoted strings")
+		}
+		rest = trimmed
+	}
+	return fields, nil
+}
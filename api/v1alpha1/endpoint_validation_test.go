@@ -0,0 +1,165 @@
+//go:build unit
+
+package v1alpha1
+
+import (
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/external-dns/endpoint"
+)
+
+func TestValidateEndpointTargets(t *testing.T) {
+	tests := []struct {
+		name     string
+		endpoint *endpoint.Endpoint
+		wantErr  string
+	}{
+		{
+			name:     "A with IPv4 targets",
+			endpoint: endpoint.NewEndpoint("foo.example.com", endpoint.RecordTypeA, "1.1.1.1", "2.2.2.2"),
+		},
+		{
+			name:     "A with IPv6 target",
+			endpoint: endpoint.NewEndpoint("foo.example.com", endpoint.RecordTypeA, "2001:db8::1"),
+			wantErr:  "must be an IPv4 address",
+		},
+		{
+			name:     "A with hostname target",
+			endpoint: endpoint.NewEndpoint("foo.example.com", endpoint.RecordTypeA, "lb.example.com"),
+			wantErr:  "must be an IPv4 address",
+		},
+		{
+			name:     "A alias with hostname target",
+			endpoint: endpoint.NewEndpoint("foo.example.com", endpoint.RecordTypeA, "lb.elb.amazonaws.com").WithProviderSpecific("alias", "true"),
+		},
+		{
+			name:     "AAAA with IPv6 target",
+			endpoint: endpoint.NewEndpoint("foo.example.com", endpoint.RecordTypeAAAA, "2001:db8::1"),
+		},
+		{
+			name:     "AAAA with IPv4 target",
+			endpoint: endpoint.NewEndpoint("foo.example.com", endpoint.RecordTypeAAAA, "1.1.1.1"),
+			wantErr:  "must be an IPv6 address",
+		},
+		{
+			name:     "CNAME with domain name targets",
+			endpoint: endpoint.NewEndpoint("foo.example.com", endpoint.RecordTypeCNAME, "lb.example.com.", "_service.lb-2.example.com"),
+		},
+		{
+			name:     "CNAME with IP target",
+			endpoint: endpoint.NewEndpoint("foo.example.com", endpoint.RecordTypeCNAME, "1.1.1.1"),
+			wantErr:  "not an IP address",
+		},
+		{
+			name:     "NS with invalid label",
+			endpoint: endpoint.NewEndpoint("foo.example.com", endpoint.RecordTypeNS, "ns1..example.com"),
+			wantErr:  `label "" is not valid`,
+		},
+		{
+			name:     "CNAME with label ending in a hyphen",
+			endpoint: endpoint.NewEndpoint("foo.example.com", endpoint.RecordTypeCNAME, "lb-.example.com"),
+			wantErr:  `label "lb-" is not valid`,
+		},
+		{
+			name:     "TXT unquoted",
+			endpoint: endpoint.NewEndpoint("foo.example.com", endpoint.RecordTypeTXT, "v=spf1 -all"),
+		},
+		{
+			name:     "TXT unquoted too long",
+			endpoint: endpoint.NewEndpoint("foo.example.com", endpoint.RecordTypeTXT, strings.Repeat("a", 256)),
+			wantErr:  "must be split into quoted strings",
+		},
+		{
+			name: "TXT quoted strings",
+			endpoint: endpoint.NewEndpoint("foo.example.com", endpoint.RecordTypeTXT,
+				`"`+strings.Repeat("a", 255)+`" "b \"c\""`),
+		},
+		{
+			name:     "TXT quoted string too long",
+			endpoint: endpoint.NewEndpoint("foo.example.com", endpoint.RecordTypeTXT, `"`+strings.Repeat("a", 256)+`"`),
+			wantErr:  "quoted string longer than 255",
+		},
+		{
+			name:     "TXT unterminated quote",
+			endpoint: endpoint.NewEndpoint("foo.example.com", endpoint.RecordTypeTXT, `"abc`),
+			wantErr:  "unterminated",
+		},
+		{
+			name:     "TXT text after quotes",
+			endpoint: endpoint.NewEndpoint("foo.example.com", endpoint.RecordTypeTXT, `"abc"def`),
+			wantErr:  "spaces between quoted strings",
+		},
+		{
+			name:     "NAPTR with regexp",
+			endpoint: &endpoint.Endpoint{DNSName: "foo.example.com", RecordType: RecordTypeNAPTR, Targets: endpoint.Targets{`100 10 "u" "E2U+sip" "!^.*$!sip:info@example.com!" .`}},
+		},
+		{
+			name:     "NAPTR with replacement",
+			endpoint: endpoint.NewEndpoint("foo.example.com", RecordTypeNAPTR, `100 10 "S" "SIP+D2U" "" _sip._udp.example.com.`),
+		},
+		{
+			name:     "NAPTR with regexp and replacement",
+			endpoint: endpoint.NewEndpoint("foo.example.com", RecordTypeNAPTR, `100 10 "u" "E2U+sip" "!^.*$!sip:info@example.com!" sip.example.com`),
+			wantErr:  "replacement",
+		},
+		{
+			name:     "NAPTR with unquoted service",
+			endpoint: endpoint.NewEndpoint("foo.example.com", RecordTypeNAPTR, `100 10 "S" SIP+D2U "" _sip._udp.example.com`),
+			wantErr:  "quoted flags, service and regexp",
+		},
+		{
+			name:     "NAPTR with out of range order",
+			endpoint: endpoint.NewEndpoint("foo.example.com", RecordTypeNAPTR, `70000 10 "S" "SIP+D2U" "" _sip._udp.example.com`),
+			wantErr:  "order and preference",
+		},
+		{
+			name:     "NAPTR with invalid flags",
+			endpoint: endpoint.NewEndpoint("foo.example.com", RecordTypeNAPTR, `100 10 "S!" "SIP+D2U" "" _sip._udp.example.com`),
+			wantErr:  "flags of letters and digits",
+		},
+		{
+			name:     "NAPTR missing fields",
+			endpoint: endpoint.NewEndpoint("foo.example.com", RecordTypeNAPTR, `100 10 "S" "SIP+D2U"`),
+			wantErr:  "must be the order",
+		},
+		{
+			name:     "TLSA with SHA-256 digest",
+			endpoint: endpoint.NewEndpoint("_443._tcp.foo.example.com", RecordTypeTLSA, "3 1 1 "+strings.Repeat("0C72AC70", 8)),
+		},
+		{
+			name:     "TLSA with full certificate split in chunks",
+			endpoint: endpoint.NewEndpoint("_443._tcp.foo.example.com", RecordTypeTLSA, "3 0 0 308201 0a0282"),
+		},
+		{
+			name:     "TLSA with short digest",
+			endpoint: endpoint.NewEndpoint("_443._tcp.foo.example.com", RecordTypeTLSA, "3 1 2 0c72ac70"),
+			wantErr:  "64 bytes for matching type 2",
+		},
+		{
+			name:     "TLSA with invalid usage",
+			endpoint: endpoint.NewEndpoint("_443._tcp.foo.example.com", RecordTypeTLSA, "4 1 0 0c72ac70"),
+			wantErr:  "usage of 0 to 3",
+		},
+		{
+			name:     "TLSA with data not hex encoded",
+			endpoint: endpoint.NewEndpoint("_443._tcp.foo.example.com", RecordTypeTLSA, "3 1 0 not-hex"),
+			wantErr:  "hex encoded",
+		},
+		{
+			name:     "MX not validated",
+			endpoint: endpoint.NewEndpoint("foo.example.com", endpoint.RecordTypeMX, "10 mail.example.com"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateEndpointTargets(tt.endpoint)
+			if tt.wantErr == "" && err != nil {
+				t.Errorf("ValidateEndpointTargets() unexpected error = %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Errorf("ValidateEndpointTargets() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
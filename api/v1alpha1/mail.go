@@ -0,0 +1,204 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"fmt"
+	"net/netip"
+	"regexp"
+	"strings"
+)
+
+const (
+	// maxSPFLookups is the maximum number of DNS lookups a receiver performs to evaluate an SPF record (RFC 7208)
+	maxSPFLookups = 10
+
+	// minDKIMRSAKeyBits is the minimum size of an RSA DKIM key verifiers accept (RFC 8301)
+	minDKIMRSAKeyBits = 1024
+)
+
+var dkimSelectorRegexp = regexp.MustCompile(`^[a-zA-Z0-9]([a-zA-Z0-9-]*[a-zA-Z0-9])?(\.[a-zA-Z0-9]([a-zA-Z0-9-]*[a-zA-Z0-9])?)*$`)
+
+// Validate checks the SPF, DKIM and DMARC records of the mail domain are well-formed.
+func (m *MailSpec) Validate() error {
+	if m.SPF != nil {
+		if err := m.SPF.Validate(); err != nil {
+			return fmt.Errorf("spf: %w", err)
+		}
+	}
+	selectors := map[string]struct{}{}
+	for _, dkim := range m.DKIM {
+		if _, ok := selectors[dkim.Selector]; ok {
+			return fmt.Errorf("dkim: duplicate selector %s", dkim.Selector)
+		}
+		selectors[dkim.Selector] = struct{}{}
+		if err := dkim.Validate(); err != nil {
+			return fmt.Errorf("dkim selector %s: %w", dkim.Selector, err)
+		}
+	}
+	if m.DMARC != nil {
+		if err := m.DMARC.Validate(); err != nil {
+			return fmt.Errorf("dmarc: %w", err)
+		}
+	}
+	return nil
+}
+
+// Validate checks the mechanisms of the SPF record are known and the record can be evaluated within the DNS lookup
+// limit. Only the lookups of the record itself are counted, included records add their own.
+func (s *SPFSpec) Validate() error {
+	lookups := 0
+	for _, term := range s.Mechanisms {
+		if name, value, isModifier := strings.Cut(term, "="); isModifier {
+			switch strings.ToLower(name) {
+			case "redirect":
+				lookups++
+			case "exp":
+			default:
+				return fmt.Errorf("unknown modifier %s", term)
+			}
+			if value == "" {
+				return fmt.Errorf("modifier %s has no domain", term)
+			}
+			continue
+		}
+
+		mechanism := strings.TrimLeft(term, "+-~?")
+		name, value, hasValue := strings.Cut(mechanism, ":")
+		name, _, _ = strings.Cut(name, "/")
+		switch strings.ToLower(name) {
+		case "a", "mx", "ptr":
+			lookups++
+		case "include", "exists":
+			lookups++
+			if !hasValue || value == "" {
+				return fmt.Errorf("mechanism %s has no domain", term)
+			}
+		case "ip4", "ip6":
+			if _, err := netip.ParsePrefix(value); err != nil {
+				if _, err := netip.ParseAddr(value); err != nil {
+					return fmt.Errorf("mechanism %s has an invalid address", term)
+				}
+			}
+		case "all":
+			return fmt.Errorf("mechanism %s must be set with all", term)
+		default:
+			return fmt.Errorf("unknown mechanism %s", term)
+		}
+	}
+	if lookups > maxSPFLookups {
+		return fmt.Errorf("record requires %d DNS lookups, more than the limit of %d", lookups, maxSPFLookups)
+	}
+	return nil
+}
+
+// Record returns the content of the SPF TXT record.
+func (s *SPFSpec) Record() string {
+	all := s.All
+	if all == "" {
+		all = "~"
+	}
+	return strings.Join(append(append([]string{"v=spf1"}, s.Mechanisms...), all+"all"), " ")
+}
+
+// Validate checks the selector is a valid DNS name and the public key can be decoded as a key of the key type.
+func (d *DKIMSpec) Validate() error {
+	if !dkimSelectorRegexp.MatchString(d.Selector) {
+		return fmt.Errorf("invalid selector")
+	}
+	key, err := base64.StdEncoding.DecodeString(d.publicKey())
+	if err != nil {
+		return fmt.Errorf("public key is not base64 encoded: %w", err)
+	}
+	switch d.keyType() {
+	case "rsa":
+		pub, err := x509.ParsePKIXPublicKey(key)
+		if err != nil {
+			return fmt.Errorf("invalid rsa public key: %w", err)
+		}
+		rsaKey, ok := pub.(*rsa.PublicKey)
+		if !ok {
+			return fmt.Errorf("public key is not an rsa key")
+		}
+		if rsaKey.N.BitLen() < minDKIMRSAKeyBits {
+			return fmt.Errorf("rsa public key has %d bits, less than the minimum of %d", rsaKey.N.BitLen(), minDKIMRSAKeyBits)
+		}
+	case "ed25519":
+		if len(key) != 32 {
+			return fmt.Errorf("ed25519 public key has %d bytes, expected 32", len(key))
+		}
+	default:
+		return fmt.Errorf("unknown key type %s", d.KeyType)
+	}
+	return nil
+}
+
+// Record returns the content of the DKIM TXT record.
+func (d *DKIMSpec) Record() string {
+	return fmt.Sprintf("v=DKIM1; k=%s; p=%s", d.keyType(), d.publicKey())
+}
+
+func (d *DKIMSpec) keyType() string {
+	if d.KeyType == "" {
+		return "rsa"
+	}
+	return d.KeyType
+}
+
+// publicKey returns the public key without PEM armour and whitespace
+func (d *DKIMSpec) publicKey() string {
+	var key strings.Builder
+	for _, line := range strings.Split(d.PublicKey, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "-----") {
+			continue
+		}
+		key.WriteString(strings.Join(strings.Fields(line), ""))
+	}
+	return key.String()
+}
+
+// Validate checks the report URIs are mailto: URIs.
+func (d *DMARCSpec) Validate() error {
+	for _, uri := range append(append([]string{}, d.AggregateReports...), d.FailureReports...) {
+		address, ok := strings.CutPrefix(uri, "mailto:")
+		if !ok || !strings.Contains(address, "@") {
+			return fmt.Errorf("report URI %s is not a mailto: URI", uri)
+		}
+	}
+	return nil
+}
+
+// Record returns the content of the DMARC TXT record.
+func (d *DMARCSpec) Record() string {
+	tags := []string{"v=DMARC1", "p=" + d.Policy}
+	if d.SubdomainPolicy != "" {
+		tags = append(tags, "sp="+d.SubdomainPolicy)
+	}
+	if d.Percentage != nil {
+		tags = append(tags, fmt.Sprintf("pct=%d", *d.Percentage))
+	}
+	if len(d.AggregateReports) > 0 {
+		tags = append(tags, "rua="+strings.Join(d.AggregateReports, ","))
+	}
+	if len(d.FailureReports) > 0 {
+		tags = append(tags, "ruf="+strings.Join(d.FailureReports, ","))
+	}
+	return strings.Join(tags, "; ")
+}
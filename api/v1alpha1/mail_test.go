@@ -0,0 +1,227 @@
+//go:build unit
+
+package v1alpha1
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+func TestSPFSpec(t *testing.T) {
+	tests := []struct {
+		name       string
+		spf        SPFSpec
+		wantRecord string
+		wantErr    string
+	}{
+		{
+			name:       "valid record",
+			spf:        SPFSpec{Mechanisms: []string{"mx", "ip4:192.0.2.0/24", "ip6:2001:db8::1", "include:_spf.example.com"}, All: "-"},
+			wantRecord: "v=spf1 mx ip4:192.0.2.0/24 ip6:2001:db8::1 include:_spf.example.com -all",
+		},
+		{
+			name:       "defaults to softfail",
+			spf:        SPFSpec{Mechanisms: []string{"a", "redirect=_spf.example.com"}},
+			wantRecord: "v=spf1 a redirect=_spf.example.com ~all",
+		},
+		{
+			name:    "too many lookups",
+			spf:     SPFSpec{Mechanisms: []string{"a", "mx", "include:a.com", "include:b.com", "include:c.com", "include:d.com", "include:e.com", "include:f.com", "include:g.com", "exists:h.com", "ptr"}},
+			wantErr: "record requires 11 DNS lookups, more than the limit of 10",
+		},
+		{
+			name:    "invalid address",
+			spf:     SPFSpec{Mechanisms: []string{"ip4:192.0.2"}},
+			wantErr: "mechanism ip4:192.0.2 has an invalid address",
+		},
+		{
+			name:    "include without domain",
+			spf:     SPFSpec{Mechanisms: []string{"include:"}},
+			wantErr: "mechanism include: has no domain",
+		},
+		{
+			name:    "all mechanism",
+			spf:     SPFSpec{Mechanisms: []string{"mx", "-all"}},
+			wantErr: "mechanism -all must be set with all",
+		},
+		{
+			name:    "unknown mechanism",
+			spf:     SPFSpec{Mechanisms: []string{"foo:bar"}},
+			wantErr: "unknown mechanism foo:bar",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.spf.Validate()
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("Validate() error = %v, want %s", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Validate() unexpected error = %v", err)
+			}
+			if got := tt.spf.Record(); got != tt.wantRecord {
+				t.Errorf("Record() = %s, want %s", got, tt.wantRecord)
+			}
+		})
+	}
+}
+
+func TestDKIMSpec(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsaDER, err := x509.MarshalPKIXPublicKey(&rsaKey.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsaPublicKey := base64.StdEncoding.EncodeToString(rsaDER)
+	rsaPEM := string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: rsaDER}))
+
+	smallDER, err := x509.MarshalPKIXPublicKey(&rsa.PublicKey{N: new(big.Int).Lsh(big.NewInt(1), 511), E: 65537})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	edKey, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	edPublicKey := base64.StdEncoding.EncodeToString(edKey)
+
+	tests := []struct {
+		name       string
+		dkim       DKIMSpec
+		wantRecord string
+		wantErr    string
+	}{
+		{
+			name:       "rsa key",
+			dkim:       DKIMSpec{Selector: "mail", PublicKey: rsaPublicKey},
+			wantRecord: "v=DKIM1; k=rsa; p=" + rsaPublicKey,
+		},
+		{
+			name:       "rsa key with PEM armour",
+			dkim:       DKIMSpec{Selector: "mail", KeyType: "rsa", PublicKey: rsaPEM},
+			wantRecord: "v=DKIM1; k=rsa; p=" + rsaPublicKey,
+		},
+		{
+			name:       "ed25519 key",
+			dkim:       DKIMSpec{Selector: "ed.2024", KeyType: "ed25519", PublicKey: edPublicKey},
+			wantRecord: "v=DKIM1; k=ed25519; p=" + edPublicKey,
+		},
+		{
+			name:    "invalid selector",
+			dkim:    DKIMSpec{Selector: "-mail", PublicKey: rsaPublicKey},
+			wantErr: "invalid selector",
+		},
+		{
+			name:    "not base64",
+			dkim:    DKIMSpec{Selector: "mail", PublicKey: "not-a-key!"},
+			wantErr: "public key is not base64 encoded",
+		},
+		{
+			name:    "rsa key too small",
+			dkim:    DKIMSpec{Selector: "mail", PublicKey: base64.StdEncoding.EncodeToString(smallDER)},
+			wantErr: "rsa public key has 512 bits, less than the minimum of 1024",
+		},
+		{
+			name:    "ed25519 key as rsa",
+			dkim:    DKIMSpec{Selector: "mail", PublicKey: edPublicKey},
+			wantErr: "invalid rsa public key",
+		},
+		{
+			name:    "rsa key as ed25519",
+			dkim:    DKIMSpec{Selector: "mail", KeyType: "ed25519", PublicKey: rsaPublicKey},
+			wantErr: "ed25519 public key has",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.dkim.Validate()
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Validate() error = %v, want %s", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Validate() unexpected error = %v", err)
+			}
+			if got := tt.dkim.Record(); got != tt.wantRecord {
+				t.Errorf("Record() = %s, want %s", got, tt.wantRecord)
+			}
+		})
+	}
+}
+
+func TestDMARCSpec(t *testing.T) {
+	percentage := 50
+	tests := []struct {
+		name       string
+		dmarc      DMARCSpec
+		wantRecord string
+		wantErr    string
+	}{
+		{
+			name:       "policy only",
+			dmarc:      DMARCSpec{Policy: "none"},
+			wantRecord: "v=DMARC1; p=none",
+		},
+		{
+			name: "all tags",
+			dmarc: DMARCSpec{
+				Policy:           "reject",
+				SubdomainPolicy:  "quarantine",
+				Percentage:       &percentage,
+				AggregateReports: []string{"mailto:dmarc@example.com", "mailto:reports@example.net"},
+				FailureReports:   []string{"mailto:failures@example.com"},
+			},
+			wantRecord: "v=DMARC1; p=reject; sp=quarantine; pct=50; rua=mailto:dmarc@example.com,mailto:reports@example.net; ruf=mailto:failures@example.com",
+		},
+		{
+			name:    "report URI not mailto",
+			dmarc:   DMARCSpec{Policy: "reject", AggregateReports: []string{"https://example.com/dmarc"}},
+			wantErr: "report URI https://example.com/dmarc is not a mailto: URI",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.dmarc.Validate()
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("Validate() error = %v, want %s", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Validate() unexpected error = %v", err)
+			}
+			if got := tt.dmarc.Record(); got != tt.wantRecord {
+				t.Errorf("Record() = %s, want %s", got, tt.wantRecord)
+			}
+		})
+	}
+}
+
+func TestMailSpecValidateDuplicateSelector(t *testing.T) {
+	edKey, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dkim := DKIMSpec{Selector: "mail", KeyType: "ed25519", PublicKey: base64.StdEncoding.EncodeToString(edKey)}
+	mail := MailSpec{DKIM: []DKIMSpec{dkim, dkim}}
+	if err := mail.Validate(); err == nil || err.Error() != "dkim: duplicate selector mail" {
+		t.Errorf("Validate() error = %v, want duplicate selector", err)
+	}
+}
@@ -19,4 +19,11 @@ package v1alpha1
 const (
 	ProviderSpecificWeight  = "weight"
 	ProviderSpecificGeoCode = "geo-code"
+	// ProviderSpecificFlatten set to "true" on a CNAME endpoint publishes the addresses its target resolves to as A
+	// and AAAA records in place of the CNAME record
+	ProviderSpecificFlatten = "flatten"
+	// ProviderSpecificMultiValue set to "true" on an endpoint with a set identifier and a single target publishes it as
+	// one record of a multivalue answer record set, supported by the AWS provider only. Route53 answers queries with up
+	// to eight of the healthy records of the set
+	ProviderSpecificMultiValue = "multi-value"
 )
@@ -32,6 +32,12 @@ const (
 	AWSSecretAccessKeyKey = "AWS_SECRET_ACCESS_KEY"
 	// AWSRegionKey is the key of the optional region for SecretTypeKuadrantAWS provider secrets
 	AWSRegionKey = "AWS_REGION"
+	// AWSRoleARNKey is the key of the optional role arn to assume for SecretTypeKuadrantAWS provider secrets
+	AWSRoleARNKey = "AWS_ROLE_ARN"
+	// AWSSTSEndpointKey is the key of the optional sts endpoint override used when assuming a role for SecretTypeKuadrantAWS provider secrets
+	AWSSTSEndpointKey = "AWS_STS_ENDPOINT"
+	// AWSEndpointURLKey is the key of the optional route53 endpoint override for SecretTypeKuadrantAWS provider secrets, e.g. a localstack url
+	AWSEndpointURLKey = "AWS_ENDPOINT_URL"
 
 	// SecretTypeKuadrantGCP contains data needed for gcp(google cloud dns) authentication and configuration.
 	//
@@ -44,6 +50,8 @@ const (
 	GoogleJsonKey = "GOOGLE"
 	// GoogleProjectIDKey is the key of the required project id for SecretTypeKuadrantGCP provider secrets
 	GoogleProjectIDKey = "PROJECT_ID"
+	// GoogleEndpointKey is the key of the optional cloud dns endpoint override for SecretTypeKuadrantGCP provider secrets, e.g. an emulator url
+	GoogleEndpointKey = "GOOGLE_ENDPOINT"
 
 	// SecretTypeKuadrantAzure contains data needed for azure authentication and configuration.
 	//
@@ -53,12 +61,51 @@ const (
 
 	// AzureJsonKey is the key of the required data for SecretTypeDockerConfigJson provider secrets
 	AzureJsonKey = "azure.json"
+	// AzureResourceManagerEndpointKey is the key of the optional resource manager endpoint override for SecretTypeKuadrantAzure provider secrets, e.g. an emulator url
+	AzureResourceManagerEndpointKey = "AZURE_RESOURCE_MANAGER_ENDPOINT"
 
 	// SecretTypeKuadrantInmemory contains data needed for inmemory configuration.
 	SecretTypeKuadrantInmemory corev1.SecretType = "kuadrant.io/inmemory"
 
 	// InmemInitZonesKey is the key of the optional comma separated list of zone names to initialise in the SecretTypeKuadrantInmemory provider secrets
 	InmemInitZonesKey = "INMEM_INIT_ZONES"
+	// InmemFailApplyChangesKey is the key of the optional number of next ApplyChanges calls that fail in the SecretTypeKuadrantInmemory provider secrets,
+	// counted from the last update of the secret
+	InmemFailApplyChangesKey = "INMEM_FAIL_APPLY_CHANGES"
+	// InmemFailErrorCodeKey is the key of the optional error code of the failed ApplyChanges calls in the SecretTypeKuadrantInmemory provider secrets,
+	// e.g. Throttled, defaults to Unavailable
+	InmemFailErrorCodeKey = "INMEM_FAIL_ERROR_CODE"
+	// InmemLatencyKey is the key of the optional duration added to every Records and ApplyChanges call in the SecretTypeKuadrantInmemory provider secrets
+	InmemLatencyKey = "INMEM_LATENCY"
+
+	// ZoneIDKey is the key of the optional id of the zone the credentials are scoped to, for all provider secret types.
+	// Must be set with ZoneDomainNameKey, zones are then never listed from the provider.
+	ZoneIDKey = "ZONE_ID"
+	// ZoneDomainNameKey is the key of the optional domain name of the zone the credentials are scoped to, for all
+	// provider secret types. Must be set with ZoneIDKey.
+	ZoneDomainNameKey = "ZONE_DOMAIN_NAME"
+
+	// AllowedDomainsKey is the key of the optional comma separated list of domains the zones of the credentials must be
+	// in to be published to, for all provider secret types. Records in other zones are rejected.
+	AllowedDomainsKey = "ALLOWED_DOMAINS"
+
+	// WriteBudgetKey is the key of the optional number of writes, each applying the changes of a reconcile, that can be
+	// made with the credentials in each write budget interval, for all provider secret types. Overrides the default
+	// budget, 0 disables the budget.
+	WriteBudgetKey = "WRITE_BUDGET"
+
+	// ZoneRecordLimitKey is the key of the optional maximum number of records allowed in the zones of the credentials,
+	// for all provider secret types. Overrides the zone record limit of the operator, 0 disables the limit.
+	ZoneRecordLimitKey = "ZONE_RECORD_LIMIT"
+
+	// TXTRegistryFormatKey is the key of the optional format of the registry TXT records written to the zones of the
+	// credentials, for all provider secret types. One of "new" (the default) or "both".
+	TXTRegistryFormatKey = "TXT_REGISTRY_FORMAT"
+
+	// ACMEChallengeTTLKey is the key of the optional comma separated list of zone=duration pairs, for all provider
+	// secret types. DNSRecords publishing only ACME DNS-01 challenge TXT endpoints to one of the zones are deleted once
+	// their spec is older than the duration of the zone.
+	ACMEChallengeTTLKey = "ACME_CHALLENGE_TTL"
 )
 
 type ProviderRef struct {
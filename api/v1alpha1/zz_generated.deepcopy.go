@@ -26,6 +26,22 @@ import (
 	"sigs.k8s.io/external-dns/endpoint"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ACMEChallengeStatus) DeepCopyInto(out *ACMEChallengeStatus) {
+	*out = *in
+	in.UpdatedAt.DeepCopyInto(&out.UpdatedAt)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ACMEChallengeStatus.
+func (in *ACMEChallengeStatus) DeepCopy() *ACMEChallengeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ACMEChallengeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AdditionalHeader) DeepCopyInto(out *AdditionalHeader) {
 	*out = *in
@@ -75,6 +91,71 @@ func (in *AdditionalHeadersRef) DeepCopy() *AdditionalHeadersRef {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CNAMEHop) DeepCopyInto(out *CNAMEHop) {
+	*out = *in
+	if in.Addresses != nil {
+		in, out := &in.Addresses, &out.Addresses
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CNAMEHop.
+func (in *CNAMEHop) DeepCopy() *CNAMEHop {
+	if in == nil {
+		return nil
+	}
+	out := new(CNAMEHop)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DKIMSpec) DeepCopyInto(out *DKIMSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DKIMSpec.
+func (in *DKIMSpec) DeepCopy() *DKIMSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DKIMSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DMARCSpec) DeepCopyInto(out *DMARCSpec) {
+	*out = *in
+	if in.Percentage != nil {
+		in, out := &in.Percentage, &out.Percentage
+		*out = new(int)
+		**out = **in
+	}
+	if in.AggregateReports != nil {
+		in, out := &in.AggregateReports, &out.AggregateReports
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.FailureReports != nil {
+		in, out := &in.FailureReports, &out.FailureReports
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DMARCSpec.
+func (in *DMARCSpec) DeepCopy() *DMARCSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DMARCSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DNSHealthCheckProbe) DeepCopyInto(out *DNSHealthCheckProbe) {
 	*out = *in
@@ -137,11 +218,21 @@ func (in *DNSHealthCheckProbeList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DNSHealthCheckProbeSpec) DeepCopyInto(out *DNSHealthCheckProbeSpec) {
 	*out = *in
+	if in.Targets != nil {
+		in, out := &in.Targets, &out.Targets
+		*out = make([]ProbeTarget, len(*in))
+		copy(*out, *in)
+	}
 	if in.Interval != nil {
 		in, out := &in.Interval, &out.Interval
 		*out = new(v1.Duration)
 		**out = **in
 	}
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
 	if in.AdditionalHeadersRef != nil {
 		in, out := &in.AdditionalHeadersRef, &out.AdditionalHeadersRef
 		*out = new(AdditionalHeadersRef)
@@ -168,6 +259,18 @@ func (in *DNSHealthCheckProbeStatus) DeepCopyInto(out *DNSHealthCheckProbeStatus
 		*out = new(bool)
 		**out = **in
 	}
+	if in.CNAMEChain != nil {
+		in, out := &in.CNAMEChain, &out.CNAMEChain
+		*out = make([]CNAMEHop, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.TargetResults != nil {
+		in, out := &in.TargetResults, &out.TargetResults
+		*out = make([]ProbeTargetResult, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DNSHealthCheckProbeStatus.
@@ -180,6 +283,89 @@ func (in *DNSHealthCheckProbeStatus) DeepCopy() *DNSHealthCheckProbeStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DNSQuota) DeepCopyInto(out *DNSQuota) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DNSQuota.
+func (in *DNSQuota) DeepCopy() *DNSQuota {
+	if in == nil {
+		return nil
+	}
+	out := new(DNSQuota)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DNSQuota) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DNSQuotaList) DeepCopyInto(out *DNSQuotaList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]DNSQuota, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DNSQuotaList.
+func (in *DNSQuotaList) DeepCopy() *DNSQuotaList {
+	if in == nil {
+		return nil
+	}
+	out := new(DNSQuotaList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DNSQuotaList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DNSQuotaSpec) DeepCopyInto(out *DNSQuotaSpec) {
+	*out = *in
+	if in.MaxRecords != nil {
+		in, out := &in.MaxRecords, &out.MaxRecords
+		*out = new(int)
+		**out = **in
+	}
+	if in.MaxEndpoints != nil {
+		in, out := &in.MaxEndpoints, &out.MaxEndpoints
+		*out = new(int)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DNSQuotaSpec.
+func (in *DNSQuotaSpec) DeepCopy() *DNSQuotaSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DNSQuotaSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DNSRecord) DeepCopyInto(out *DNSRecord) {
 	*out = *in
@@ -239,10 +425,42 @@ func (in *DNSRecordList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DNSRecordRevision) DeepCopyInto(out *DNSRecordRevision) {
+	*out = *in
+	in.AppliedAt.DeepCopyInto(&out.AppliedAt)
+	if in.Endpoints != nil {
+		in, out := &in.Endpoints, &out.Endpoints
+		*out = make([]*endpoint.Endpoint, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(endpoint.Endpoint)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DNSRecordRevision.
+func (in *DNSRecordRevision) DeepCopy() *DNSRecordRevision {
+	if in == nil {
+		return nil
+	}
+	out := new(DNSRecordRevision)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DNSRecordSpec) DeepCopyInto(out *DNSRecordSpec) {
 	*out = *in
 	out.ProviderRef = in.ProviderRef
+	if in.MigrateTo != nil {
+		in, out := &in.MigrateTo, &out.MigrateTo
+		*out = new(ProviderRef)
+		**out = **in
+	}
 	if in.Endpoints != nil {
 		in, out := &in.Endpoints, &out.Endpoints
 		*out = make([]*endpoint.Endpoint, len(*in))
@@ -254,11 +472,41 @@ func (in *DNSRecordSpec) DeepCopyInto(out *DNSRecordSpec) {
 			}
 		}
 	}
+	if in.GatewayEndpoints != nil {
+		in, out := &in.GatewayEndpoints, &out.GatewayEndpoints
+		*out = make([]GatewayEndpoint, len(*in))
+		copy(*out, *in)
+	}
 	if in.HealthCheck != nil {
 		in, out := &in.HealthCheck, &out.HealthCheck
 		*out = new(HealthCheckSpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Mail != nil {
+		in, out := &in.Mail, &out.Mail
+		*out = new(MailSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ExcludeDNSNames != nil {
+		in, out := &in.ExcludeDNSNames, &out.ExcludeDNSNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExcludeTargetCIDRs != nil {
+		in, out := &in.ExcludeTargetCIDRs, &out.ExcludeTargetCIDRs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.RegistryZoneRef != nil {
+		in, out := &in.RegistryZoneRef, &out.RegistryZoneRef
+		*out = new(RegistryZoneRef)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AdoptFrom != nil {
+		in, out := &in.AdoptFrom, &out.AdoptFrom
+		*out = new(ExternalDNSAdoption)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DNSRecordSpec.
@@ -282,6 +530,10 @@ func (in *DNSRecordStatus) DeepCopyInto(out *DNSRecordStatus) {
 		}
 	}
 	in.QueuedAt.DeepCopyInto(&out.QueuedAt)
+	if in.NextValidation != nil {
+		in, out := &in.NextValidation, &out.NextValidation
+		*out = (*in).DeepCopy()
+	}
 	if in.Endpoints != nil {
 		in, out := &in.Endpoints, &out.Endpoints
 		*out = make([]*endpoint.Endpoint, len(*in))
@@ -293,6 +545,18 @@ func (in *DNSRecordStatus) DeepCopyInto(out *DNSRecordStatus) {
 			}
 		}
 	}
+	if in.EndpointStatuses != nil {
+		in, out := &in.EndpointStatuses, &out.EndpointStatuses
+		*out = make([]EndpointStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.FlattenedEndpoints != nil {
+		in, out := &in.FlattenedEndpoints, &out.FlattenedEndpoints
+		*out = make([]FlattenedEndpoint, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.ZoneEndpoints != nil {
 		in, out := &in.ZoneEndpoints, &out.ZoneEndpoints
 		*out = make([]*endpoint.Endpoint, len(*in))
@@ -314,6 +578,61 @@ func (in *DNSRecordStatus) DeepCopyInto(out *DNSRecordStatus) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.History != nil {
+		in, out := &in.History, &out.History
+		*out = make([]DNSRecordRevision, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PendingChanges != nil {
+		in, out := &in.PendingChanges, &out.PendingChanges
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ProviderError != nil {
+		in, out := &in.ProviderError, &out.ProviderError
+		*out = new(ProviderError)
+		**out = **in
+	}
+	if in.ParkedEndpoints != nil {
+		in, out := &in.ParkedEndpoints, &out.ParkedEndpoints
+		*out = make([]*endpoint.Endpoint, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(endpoint.Endpoint)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+	if in.Migration != nil {
+		in, out := &in.Migration, &out.Migration
+		*out = new(MigrationStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Dampening != nil {
+		in, out := &in.Dampening, &out.Dampening
+		*out = new(DampeningStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LingeringTargets != nil {
+		in, out := &in.LingeringTargets, &out.LingeringTargets
+		*out = make([]LingeringTarget, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.DomainVerification != nil {
+		in, out := &in.DomainVerification, &out.DomainVerification
+		*out = new(DomainVerificationStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ACMEChallenge != nil {
+		in, out := &in.ACMEChallenge, &out.ACMEChallenge
+		*out = new(ACMEChallengeStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DNSRecordStatus.
@@ -326,6 +645,111 @@ func (in *DNSRecordStatus) DeepCopy() *DNSRecordStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DampeningStatus) DeepCopyInto(out *DampeningStatus) {
+	*out = *in
+	in.ChangedAt.DeepCopyInto(&out.ChangedAt)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DampeningStatus.
+func (in *DampeningStatus) DeepCopy() *DampeningStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DampeningStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DomainVerificationStatus) DeepCopyInto(out *DomainVerificationStatus) {
+	*out = *in
+	if in.VerifiedAt != nil {
+		in, out := &in.VerifiedAt, &out.VerifiedAt
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DomainVerificationStatus.
+func (in *DomainVerificationStatus) DeepCopy() *DomainVerificationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DomainVerificationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EndpointStatus) DeepCopyInto(out *EndpointStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EndpointStatus.
+func (in *EndpointStatus) DeepCopy() *EndpointStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(EndpointStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalDNSAdoption) DeepCopyInto(out *ExternalDNSAdoption) {
+	*out = *in
+	if in.Owners != nil {
+		in, out := &in.Owners, &out.Owners
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalDNSAdoption.
+func (in *ExternalDNSAdoption) DeepCopy() *ExternalDNSAdoption {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalDNSAdoption)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FlattenedEndpoint) DeepCopyInto(out *FlattenedEndpoint) {
+	*out = *in
+	if in.Addresses != nil {
+		in, out := &in.Addresses, &out.Addresses
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FlattenedEndpoint.
+func (in *FlattenedEndpoint) DeepCopy() *FlattenedEndpoint {
+	if in == nil {
+		return nil
+	}
+	out := new(FlattenedEndpoint)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GatewayEndpoint) DeepCopyInto(out *GatewayEndpoint) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayEndpoint.
+func (in *GatewayEndpoint) DeepCopy() *GatewayEndpoint {
+	if in == nil {
+		return nil
+	}
+	out := new(GatewayEndpoint)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HealthCheckSpec) DeepCopyInto(out *HealthCheckSpec) {
 	*out = *in
@@ -334,6 +758,11 @@ func (in *HealthCheckSpec) DeepCopyInto(out *HealthCheckSpec) {
 		*out = new(v1.Duration)
 		**out = **in
 	}
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
 	if in.AdditionalHeadersRef != nil {
 		in, out := &in.AdditionalHeadersRef, &out.AdditionalHeadersRef
 		*out = new(AdditionalHeadersRef)
@@ -402,6 +831,109 @@ func (in *HealthCheckStatusProbe) DeepCopy() *HealthCheckStatusProbe {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LingeringTarget) DeepCopyInto(out *LingeringTarget) {
+	*out = *in
+	in.RemoveAt.DeepCopyInto(&out.RemoveAt)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LingeringTarget.
+func (in *LingeringTarget) DeepCopy() *LingeringTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(LingeringTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MailSpec) DeepCopyInto(out *MailSpec) {
+	*out = *in
+	if in.SPF != nil {
+		in, out := &in.SPF, &out.SPF
+		*out = new(SPFSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DKIM != nil {
+		in, out := &in.DKIM, &out.DKIM
+		*out = make([]DKIMSpec, len(*in))
+		copy(*out, *in)
+	}
+	if in.DMARC != nil {
+		in, out := &in.DMARC, &out.DMARC
+		*out = new(DMARCSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MailSpec.
+func (in *MailSpec) DeepCopy() *MailSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MailSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MigrationStatus) DeepCopyInto(out *MigrationStatus) {
+	*out = *in
+	out.ProviderRef = in.ProviderRef
+	if in.Endpoints != nil {
+		in, out := &in.Endpoints, &out.Endpoints
+		*out = make([]*endpoint.Endpoint, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(endpoint.Endpoint)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MigrationStatus.
+func (in *MigrationStatus) DeepCopy() *MigrationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(MigrationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProbeTargetResult) DeepCopyInto(out *ProbeTargetResult) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProbeTargetResult.
+func (in *ProbeTargetResult) DeepCopy() *ProbeTargetResult {
+	if in == nil {
+		return nil
+	}
+	out := new(ProbeTargetResult)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProviderError) DeepCopyInto(out *ProviderError) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProviderError.
+func (in *ProviderError) DeepCopy() *ProviderError {
+	if in == nil {
+		return nil
+	}
+	out := new(ProviderError)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ProviderRef) DeepCopyInto(out *ProviderRef) {
 	*out = *in
@@ -416,3 +948,43 @@ func (in *ProviderRef) DeepCopy() *ProviderRef {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RegistryZoneRef) DeepCopyInto(out *RegistryZoneRef) {
+	*out = *in
+	if in.ProviderRef != nil {
+		in, out := &in.ProviderRef, &out.ProviderRef
+		*out = new(ProviderRef)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RegistryZoneRef.
+func (in *RegistryZoneRef) DeepCopy() *RegistryZoneRef {
+	if in == nil {
+		return nil
+	}
+	out := new(RegistryZoneRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SPFSpec) DeepCopyInto(out *SPFSpec) {
+	*out = *in
+	if in.Mechanisms != nil {
+		in, out := &in.Mechanisms, &out.Mechanisms
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SPFSpec.
+func (in *SPFSpec) DeepCopy() *SPFSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SPFSpec)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -0,0 +1,128 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	"github.com/kuadrant/dns-operator/api/v1alpha1"
+)
+
+// SetupWebhookWithManager serves the conversion webhook of DNSRecords, at /convert. Both versions of DNSRecord must be
+// in the scheme of the manager.
+func (r *DNSRecord) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		Complete()
+}
+
+// ConvertTo converts the record to the v1alpha1 DNSRecord it is stored as. The first provider reference is the
+// providerRef of the v1alpha1 record and the second, if any, its migrateTo.
+func (src *DNSRecord) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*v1alpha1.DNSRecord)
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Status = src.Status
+
+	dst.Spec = v1alpha1.DNSRecordSpec{
+		OwnerID:          src.Spec.OwnerID,
+		RootHost:         src.Spec.RootHost,
+		Endpoints:        src.Spec.Endpoints,
+		GatewayEndpoints: src.Spec.GatewayEndpoints,
+		Mail:             src.Spec.Mail,
+		RegistryZoneRef:  src.Spec.RegistryZoneRef,
+		AdoptFrom:        src.Spec.AdoptFrom,
+		Parked:           src.Spec.Parked,
+		LingerPolicy:     src.Spec.LingerPolicy,
+	}
+	if len(src.Spec.ProviderRefs) > 0 {
+		dst.Spec.ProviderRef = src.Spec.ProviderRefs[0]
+	}
+	if len(src.Spec.ProviderRefs) > 1 {
+		migrateTo := src.Spec.ProviderRefs[1]
+		dst.Spec.MigrateTo = &migrateTo
+	}
+	if src.Spec.Exclusions != nil {
+		dst.Spec.ExcludeDNSNames = src.Spec.Exclusions.DNSNames
+		dst.Spec.ExcludeTargetCIDRs = src.Spec.Exclusions.TargetCIDRs
+	}
+	if hc := src.Spec.HealthCheck; hc != nil {
+		dst.Spec.HealthCheck = &v1alpha1.HealthCheckSpec{
+			Port:                 hc.Request.Port,
+			Path:                 hc.Request.Path,
+			Protocol:             hc.Request.Protocol,
+			Interval:             hc.Interval,
+			Timeout:              hc.Timeout,
+			AdditionalHeadersRef: hc.Request.AdditionalHeadersRef,
+			ServerName:           hc.Request.ServerName,
+			HostHeader:           hc.Request.HostHeader,
+			FailureThreshold:     hc.Thresholds.Failure,
+			RequiredPasses:       hc.Thresholds.Pass,
+			ResolveCNAMEChain:    hc.ResolveCNAMEChain,
+		}
+	}
+	return nil
+}
+
+// ConvertFrom converts the v1alpha1 DNSRecord the record is stored as to the record.
+func (dst *DNSRecord) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*v1alpha1.DNSRecord)
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Status = src.Status
+
+	dst.Spec = DNSRecordSpec{
+		OwnerID:          src.Spec.OwnerID,
+		RootHost:         src.Spec.RootHost,
+		ProviderRefs:     []v1alpha1.ProviderRef{src.Spec.ProviderRef},
+		Endpoints:        src.Spec.Endpoints,
+		GatewayEndpoints: src.Spec.GatewayEndpoints,
+		Mail:             src.Spec.Mail,
+		RegistryZoneRef:  src.Spec.RegistryZoneRef,
+		AdoptFrom:        src.Spec.AdoptFrom,
+		Parked:           src.Spec.Parked,
+		LingerPolicy:     src.Spec.LingerPolicy,
+	}
+	if src.Spec.MigrateTo != nil {
+		dst.Spec.ProviderRefs = append(dst.Spec.ProviderRefs, *src.Spec.MigrateTo)
+	}
+	if src.Spec.ExcludeDNSNames != nil || src.Spec.ExcludeTargetCIDRs != nil {
+		dst.Spec.Exclusions = &Exclusions{
+			DNSNames:    src.Spec.ExcludeDNSNames,
+			TargetCIDRs: src.Spec.ExcludeTargetCIDRs,
+		}
+	}
+	if hc := src.Spec.HealthCheck; hc != nil {
+		dst.Spec.HealthCheck = &HealthCheckSpec{
+			Request: HealthCheckRequest{
+				Protocol:             hc.Protocol,
+				Port:                 hc.Port,
+				Path:                 hc.Path,
+				HostHeader:           hc.HostHeader,
+				ServerName:           hc.ServerName,
+				AdditionalHeadersRef: hc.AdditionalHeadersRef,
+			},
+			Interval: hc.Interval,
+			Timeout:  hc.Timeout,
+			Thresholds: HealthCheckThresholds{
+				Failure: hc.FailureThreshold,
+				Pass:    hc.RequiredPasses,
+			},
+			ResolveCNAMEChain: hc.ResolveCNAMEChain,
+		}
+	}
+	return nil
+}
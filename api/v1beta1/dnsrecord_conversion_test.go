@@ -0,0 +1,129 @@
+//go:build unit
+
+package v1beta1
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	externaldns "sigs.k8s.io/external-dns/endpoint"
+
+	"github.com/kuadrant/dns-operator/api/v1alpha1"
+)
+
+func testV1alpha1Records() map[string]*v1alpha1.DNSRecord {
+	meta := metav1.ObjectMeta{Name: "test", Namespace: "test", Labels: map[string]string{"app": "test"}, Generation: 3}
+	return map[string]*v1alpha1.DNSRecord{
+		"minimal": {
+			ObjectMeta: meta,
+			Spec: v1alpha1.DNSRecordSpec{
+				RootHost:    "example.com",
+				ProviderRef: v1alpha1.ProviderRef{Name: "provider"},
+			},
+		},
+		"all fields": {
+			ObjectMeta: meta,
+			Spec: v1alpha1.DNSRecordSpec{
+				OwnerID:     "owner1",
+				RootHost:    "example.com",
+				ProviderRef: v1alpha1.ProviderRef{Name: "provider"},
+				MigrateTo:   &v1alpha1.ProviderRef{Name: "other-provider"},
+				Endpoints: []*externaldns.Endpoint{
+					externaldns.NewEndpointWithTTL("www.example.com", externaldns.RecordTypeA, 60, "1.1.1.1"),
+				},
+				GatewayEndpoints: []v1alpha1.GatewayEndpoint{{DNSName: "api.example.com", GatewayName: "gw"}},
+				HealthCheck: &v1alpha1.HealthCheckSpec{
+					Port:                 8443,
+					Path:                 "/healthz",
+					Protocol:             v1alpha1.HttpsProtocol,
+					Interval:             &metav1.Duration{Duration: time.Minute},
+					Timeout:              &metav1.Duration{Duration: 5 * time.Second},
+					AdditionalHeadersRef: &v1alpha1.AdditionalHeadersRef{Name: "headers"},
+					ServerName:           "origin.example.com",
+					HostHeader:           "www.example.com",
+					FailureThreshold:     3,
+					RequiredPasses:       2,
+					ResolveCNAMEChain:    true,
+				},
+				Mail:               &v1alpha1.MailSpec{Domain: "example.com", SPF: &v1alpha1.SPFSpec{Mechanisms: []string{"mx"}}},
+				ExcludeDNSNames:    []string{`^internal\.`},
+				ExcludeTargetCIDRs: []string{"10.0.0.0/8"},
+				AdoptFrom:          &v1alpha1.ExternalDNSAdoption{Owners: []string{"external-dns"}},
+				Parked:             true,
+				LingerPolicy:       v1alpha1.LingerPolicyTTL,
+			},
+			Status: v1alpha1.DNSRecordStatus{
+				OwnerID:        "owner1",
+				ZoneDomainName: "example.com",
+			},
+		},
+		"registry zone": {
+			ObjectMeta: meta,
+			Spec: v1alpha1.DNSRecordSpec{
+				RootHost:        "example.com",
+				ProviderRef:     v1alpha1.ProviderRef{Name: "provider"},
+				RegistryZoneRef: &v1alpha1.RegistryZoneRef{DomainName: "registry.example.com"},
+				ExcludeDNSNames: []string{`^internal\.`},
+			},
+		},
+	}
+}
+
+func TestDNSRecordConversion_RoundTrip(t *testing.T) {
+	for name, record := range testV1alpha1Records() {
+		t.Run(name, func(t *testing.T) {
+			converted := &DNSRecord{}
+			if err := converted.ConvertFrom(record.DeepCopy()); err != nil {
+				t.Fatalf("unexpected error converting from v1alpha1: %v", err)
+			}
+			restored := &v1alpha1.DNSRecord{}
+			if err := converted.DeepCopy().ConvertTo(restored); err != nil {
+				t.Fatalf("unexpected error converting to v1alpha1: %v", err)
+			}
+			if !reflect.DeepEqual(record, restored) {
+				t.Errorf("expected the record to be unchanged by a round trip\nwant: %+v\ngot:  %+v", record.Spec, restored.Spec)
+			}
+
+			again := &DNSRecord{}
+			if err := again.ConvertFrom(restored); err != nil {
+				t.Fatalf("unexpected error converting from v1alpha1: %v", err)
+			}
+			if !reflect.DeepEqual(converted, again) {
+				t.Errorf("expected the v1beta1 record to be unchanged by a round trip\nwant: %+v\ngot:  %+v", converted.Spec, again.Spec)
+			}
+		})
+	}
+}
+
+func TestDNSRecordConversion_Fields(t *testing.T) {
+	record := testV1alpha1Records()["all fields"]
+	converted := &DNSRecord{}
+	if err := converted.ConvertFrom(record); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if want := []v1alpha1.ProviderRef{{Name: "provider"}, {Name: "other-provider"}}; !reflect.DeepEqual(converted.Spec.ProviderRefs, want) {
+		t.Errorf("expected providerRef and migrateTo to be the provider refs %v, got %v", want, converted.Spec.ProviderRefs)
+	}
+	if want := (&Exclusions{DNSNames: []string{`^internal\.`}, TargetCIDRs: []string{"10.0.0.0/8"}}); !reflect.DeepEqual(converted.Spec.Exclusions, want) {
+		t.Errorf("expected the exclusions %+v, got %+v", want, converted.Spec.Exclusions)
+	}
+	hc := converted.Spec.HealthCheck
+	if hc.Request.Port != 8443 || hc.Request.Path != "/healthz" || hc.Request.ServerName != "origin.example.com" ||
+		hc.Thresholds.Failure != 3 || hc.Thresholds.Pass != 2 || !hc.ResolveCNAMEChain {
+		t.Errorf("unexpected health check %+v", hc)
+	}
+	if converted.Status.ZoneDomainName != "example.com" {
+		t.Errorf("expected the status to be converted, got %+v", converted.Status)
+	}
+
+	minimal := &DNSRecord{}
+	if err := minimal.ConvertFrom(testV1alpha1Records()["minimal"]); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(minimal.Spec.ProviderRefs) != 1 || minimal.Spec.Exclusions != nil || minimal.Spec.HealthCheck != nil {
+		t.Errorf("expected a single provider ref and no exclusions or health check, got %+v", minimal.Spec)
+	}
+}
@@ -0,0 +1,234 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	externaldns "sigs.k8s.io/external-dns/endpoint"
+
+	"github.com/kuadrant/dns-operator/api/v1alpha1"
+)
+
+// HealthCheckSpec configures the health probes of the endpoints of the record.
+// +kubebuilder:validation:XValidation:rule="!has(self.timeout) || !has(self.interval) || duration(self.timeout) < duration(self.interval)",message="Timeout must be less than interval"
+type HealthCheckSpec struct {
+	// request is the request sent to each endpoint.
+	// +kubebuilder:default={}
+	// +optional
+	Request HealthCheckRequest `json:"request,omitempty"`
+
+	// interval defines how frequently the probes execute
+	// Defaults to 5 minutes
+	// +kubebuilder:default="5m"
+	Interval *metav1.Duration `json:"interval,omitempty"`
+
+	// timeout is how long a probe request, including resolving the address, can take before it fails.
+	// Defaults to the probe timeout of the operator, 3 seconds unless set with --probe-timeout
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+
+	// thresholds are the consecutive results that change the health of an endpoint.
+	// +kubebuilder:default={}
+	// +optional
+	Thresholds HealthCheckThresholds `json:"thresholds,omitempty"`
+
+	// resolveCNAMEChain probes each of the IP addresses at the end of the CNAME chain of hostname targets, resolving
+	// the chain one hop at a time, and reports which hop failed to resolve in the status of the probe
+	// +optional
+	ResolveCNAMEChain bool `json:"resolveCNAMEChain,omitempty"`
+}
+
+// HealthCheckRequest is the request of a health probe.
+type HealthCheckRequest struct {
+	// protocol to use when connecting to the host, valid values are "HTTP" or "HTTPS"
+	// Defaults to HTTPS
+	// +kubebuilder:validation:XValidation:rule="self in ['HTTP','HTTPS']",message="Only HTTP or HTTPS protocols are allowed"
+	// +kubebuilder:default=HTTPS
+	Protocol v1alpha1.Protocol `json:"protocol,omitempty"`
+
+	// port to connect to the host on. Must be either 80, 443 or 1024-49151
+	// Defaults to port 443
+	// +kubebuilder:validation:XValidation:rule="self in [80, 443] || (self >= 1024 && self <= 49151)",message="Only ports 80, 443, 1024-49151 are allowed"
+	// +kubebuilder:default=443
+	Port int `json:"port,omitempty"`
+
+	// path is the path to append to the host to reach the expected health check.
+	// Must start with "?" or "/", contain only valid URL characters and end with alphanumeric char or "/". For example "/" or "/healthz" are common
+	// +kubebuilder:validation:Pattern=`^(?:\?|\/)[\w\-.~:\/?#\[\]@!$&'()*+,;=]+(?:[a-zA-Z0-9]|\/){1}$`
+	Path string `json:"path,omitempty"`
+
+	// hostHeader is the value sent in the host header of probe requests.
+	// Defaults to the root host
+	// +optional
+	HostHeader string `json:"hostHeader,omitempty"`
+
+	// serverName is the name sent in the TLS server name indication of HTTPS probe requests, when it differs from the
+	// root host, e.g. for endpoints fronted by a CDN or a shared load balancer.
+	// Defaults to the root host
+	// +optional
+	ServerName string `json:"serverName,omitempty"`
+
+	// additionalHeadersRef refers to a secret that contains extra headers to send in the probe request, this is
+	// primarily useful if an authentication token is required by the endpoint.
+	// +optional
+	AdditionalHeadersRef *v1alpha1.AdditionalHeadersRef `json:"additionalHeadersRef,omitempty"`
+}
+
+// HealthCheckThresholds are the consecutive results of the probes of an endpoint that change its health.
+type HealthCheckThresholds struct {
+	// failure is a limit of consecutive failures that must occur for a host to be considered unhealthy
+	// Defaults to 5
+	// +kubebuilder:validation:XValidation:rule="self > 0",message="Failure threshold must be greater than 0"
+	// +kubebuilder:default=5
+	Failure int `json:"failure,omitempty"`
+
+	// pass is the number of consecutive successful probes that must occur for a host that is not healthy, including
+	// one that has never been probed, to be considered healthy and be published
+	// Defaults to 1
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=1
+	// +optional
+	Pass int `json:"pass,omitempty"`
+}
+
+// Exclusions are the endpoints and targets of a record that must never be published.
+type Exclusions struct {
+	// dnsNames are regular expressions matching the DNS names of endpoints that must never be published, in addition
+	// to those excluded by the operator.
+	// +optional
+	DNSNames []string `json:"dnsNames,omitempty"`
+
+	// targetCIDRs are IP ranges that the targets of A and AAAA endpoints must never be published in, in addition to
+	// those excluded by the operator. Endpoints left without targets are not published.
+	// +optional
+	TargetCIDRs []string `json:"targetCIDRs,omitempty"`
+}
+
+// DNSRecordSpec defines the desired state of DNSRecord
+// +kubebuilder:validation:XValidation:rule="!has(oldSelf.ownerID) || has(self.ownerID)", message="OwnerID can't be unset if it was previously set"
+// +kubebuilder:validation:XValidation:rule="has(oldSelf.ownerID) || !has(self.ownerID)", message="OwnerID can't be set if it was previously unset"
+// +kubebuilder:validation:XValidation:rule="has(oldSelf.registryZoneRef) == has(self.registryZoneRef)", message="RegistryZoneRef can't be added or removed"
+// +kubebuilder:validation:XValidation:rule="size(self.providerRefs) < 2 || self.providerRefs[1].name != self.providerRefs[0].name", message="ProviderRefs must refer to different provider secrets"
+// +kubebuilder:validation:XValidation:rule="size(self.providerRefs) < 2 || !has(self.registryZoneRef)", message="A second providerRef can't be used with registryZoneRef"
+// +kubebuilder:validation:XValidation:rule="!has(self.adoptFrom) || !has(self.registryZoneRef)", message="AdoptFrom can't be used with registryZoneRef"
+type DNSRecordSpec struct {
+	// ownerID is a unique string used to identify the owner of this record.
+	// If unset or set to an empty string the record UID will be used.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="OwnerID is immutable"
+	// +kubebuilder:validation:MinLength=6
+	// +kubebuilder:validation:MaxLength=36
+	OwnerID string `json:"ownerID,omitempty"`
+
+	// rootHost is the single root for all endpoints in a DNSRecord.
+	// it is expected all defined endpoints are children of or equal to this rootHost
+	// Must contain at least two groups of valid URL characters separated by a "."
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="RootHost is immutable"
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=255
+	// +kubebuilder:validation:Pattern=`^(?:[\w\-.~:\/?#[\]@!$&'()*+,;=]+)\.(?:[\w\-.~:\/?#[\]@!$&'()*+,;=]+)$`
+	RootHost string `json:"rootHost"`
+
+	// providerRefs are references to provider secrets. The endpoints are published to the provider of the first.
+	// A second reference migrates the record to its provider: the endpoints are published to both providers until
+	// they resolve through the nameservers of the new zone, they are then removed from the first provider and the
+	// second becomes the only reference. Removing the second reference before the migration completes removes the
+	// endpoints from its provider.
+	// +kubebuilder:validation:MinItems=1
+	// +kubebuilder:validation:MaxItems=2
+	ProviderRefs []v1alpha1.ProviderRef `json:"providerRefs"`
+
+	// endpoints is a list of endpoints that will be published into the dns provider.
+	// +kubebuilder:validation:MinItems=1
+	// +optional
+	Endpoints []*externaldns.Endpoint `json:"endpoints,omitempty"`
+
+	// gatewayEndpoints is a list of endpoints with targets taken from the addresses of a Gateway API Gateway.
+	// The targets are kept up to date as the addresses of the Gateway change.
+	// +optional
+	GatewayEndpoints []v1alpha1.GatewayEndpoint `json:"gatewayEndpoints,omitempty"`
+
+	// healthCheck configures the health probes of the endpoints.
+	// +optional
+	HealthCheck *HealthCheckSpec `json:"healthCheck,omitempty"`
+
+	// mail is a set of SPF, DKIM and DMARC records for a mail domain, published as TXT records.
+	// +optional
+	Mail *v1alpha1.MailSpec `json:"mail,omitempty"`
+
+	// exclusions are the endpoints and targets that must never be published, in addition to those excluded by the
+	// operator.
+	// +optional
+	Exclusions *Exclusions `json:"exclusions,omitempty"`
+
+	// registryZoneRef is a reference to a separate zone the registry TXT records of the endpoints are written to,
+	// instead of the zone the endpoints are published in.
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="RegistryZoneRef is immutable"
+	// +optional
+	RegistryZoneRef *v1alpha1.RegistryZoneRef `json:"registryZoneRef,omitempty"`
+
+	// adoptFrom configures the adoption of endpoints of the record that were published by another external-dns
+	// instance. Their registry TXT records are rewritten to be owned by this record, a batch at a time.
+	// +optional
+	AdoptFrom *v1alpha1.ExternalDNSAdoption `json:"adoptFrom,omitempty"`
+
+	// parked replaces the endpoints of the record with a single endpoint for the rootHost pointing to the parking
+	// target of the operator, e.g. a sorry page. The endpoints are restored when parked is unset.
+	// +optional
+	Parked bool `json:"parked,omitempty"`
+
+	// lingerPolicy is how targets removed from the endpoints of the record are removed from the zone. With TTL the
+	// remaining targets are published right away and each removed target is kept in the zone for one TTL of its
+	// endpoint. Defaults to None, removing targets right away.
+	// +optional
+	LingerPolicy v1alpha1.LingerPolicy `json:"lingerPolicy,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:unservedversion
+//+kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].status",description="DNSRecord ready."
+//+kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase",description="DNSRecord phase."
+//+kubebuilder:printcolumn:name="Healthy",type="string",JSONPath=".status.conditions[?(@.type==\"Healthy\")].status",description="DNSRecord healthy.",priority=2
+//+kubebuilder:printcolumn:name="Root Host",type="string",JSONPath=".spec.rootHost",description="DNSRecord root host.",priority=2
+//+kubebuilder:printcolumn:name="Owner ID",type="string",JSONPath=".status.ownerID",description="DNSRecord owner id.",priority=2
+//+kubebuilder:printcolumn:name="Zone Domain",type="string",JSONPath=".status.zoneDomainName",description="DNSRecord zone domain name.",priority=2
+//+kubebuilder:printcolumn:name="Zone ID",type="string",JSONPath=".status.zoneID",description="DNSRecord zone id.",priority=2
+//+kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// DNSRecord is the Schema for the dnsrecords API. It is converted to and from the v1alpha1 DNSRecord it is stored
+// as by the conversion webhook of the operator, and is not served unless the webhook is deployed.
+type DNSRecord struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   DNSRecordSpec            `json:"spec,omitempty"`
+	Status v1alpha1.DNSRecordStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// DNSRecordList contains a list of DNSRecord
+type DNSRecordList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []DNSRecord `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&DNSRecord{}, &DNSRecordList{})
+}
@@ -0,0 +1,117 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// kubectl-dns is a kubectl plugin for DNSRecords. Installed on the PATH it is run as `kubectl dns`.
+//
+// The validate command validates DNSRecord manifests without a cluster and exits with a non-zero status if any is not
+// valid, so GitOps pipelines can reject broken records before they are applied:
+//
+//	kubectl dns validate -f records/ --provider aws
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/kuadrant/dns-operator/pkg/validate"
+)
+
+const usage = `Usage: kubectl dns <command> [flags]
+
+Commands:
+  validate  validate DNSRecord manifests
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+	switch os.Args[1] {
+	case "validate":
+		os.Exit(runValidate(os.Args[2:], os.Stdout, os.Stderr))
+	case "-h", "--help", "help":
+		fmt.Fprint(os.Stdout, usage)
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n%s", os.Args[1], usage)
+		os.Exit(2)
+	}
+}
+
+// runValidate validates the manifests of the files given with -f and returns the exit status, 1 if a record is not
+// valid and 2 if the manifests could not be read.
+func runValidate(args []string, stdout, stderr io.Writer) int {
+	var (
+		files     []string
+		provider  string
+		recursive bool
+		quiet     bool
+	)
+	fs := flag.NewFlagSet("validate", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	filesFlag := func(value string) error {
+		files = append(files, value)
+		return nil
+	}
+	fs.Func("f", "The file or directory of the DNSRecord manifests to validate, can be repeated.", filesFlag)
+	fs.Func("filename", "The file or directory of the DNSRecord manifests to validate, can be repeated.", filesFlag)
+	fs.StringVar(&provider, "provider", "", fmt.Sprintf("The provider the records are published with, to validate they are supported by it. One of %s.", strings.Join(validate.Providers(), ", ")))
+	fs.BoolVar(&recursive, "R", false, "Validate the manifests of subdirectories of the directories.")
+	fs.BoolVar(&recursive, "recursive", false, "Validate the manifests of subdirectories of the directories.")
+	fs.BoolVar(&quiet, "q", false, "Only report the records that are not valid.")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if len(files) == 0 {
+		fmt.Fprintln(stderr, "at least one file or directory must be given with -f")
+		return 2
+	}
+
+	validator, err := validate.New(validate.Options{Provider: provider})
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+
+	var valid, invalid int
+	for _, file := range files {
+		results, err := validator.Path(file, recursive)
+		for _, result := range results {
+			if result.Err != nil {
+				invalid++
+				fmt.Fprintln(stdout, result)
+				continue
+			}
+			valid++
+			if !quiet {
+				fmt.Fprintln(stdout, result)
+			}
+		}
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return 2
+		}
+	}
+
+	fmt.Fprintf(stdout, "%d valid, %d invalid DNSRecords\n", valid, invalid)
+	if invalid > 0 {
+		return 1
+	}
+	return 0
+}
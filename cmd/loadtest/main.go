@@ -0,0 +1,93 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// The load test drives synthetic DNSRecords through the DNSRecord reconciler against the inmemory provider, without a
+// cluster, and reports the latency and allocations of each phase of the reconciles. CPU and heap profiles of the run
+// can be written with --cpuprofile and --memprofile, samples of the CPU profile are labelled with the reconcile phase.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"runtime"
+	"runtime/pprof"
+
+	"go.uber.org/zap/zapcore"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	"github.com/kuadrant/dns-operator/internal/loadtest"
+)
+
+func main() {
+	var config loadtest.Config
+	var cpuProfile string
+	var memProfile string
+
+	flag.IntVar(&config.Records, "records", loadtest.DefaultRecords, "The number of DNSRecords.")
+	flag.IntVar(&config.EndpointsPerRecord, "endpoints", loadtest.DefaultEndpointsPerRecord, "The number of endpoints of each DNSRecord.")
+	flag.IntVar(&config.Rounds, "rounds", loadtest.DefaultRounds, "The number of soak rounds, every round changes the targets "+
+		"of every DNSRecord then validates every DNSRecord.")
+	flag.StringVar(&config.Domain, "domain", loadtest.DefaultDomain, "The zone the DNSRecords are published to.")
+	flag.DurationVar(&config.ProviderLatency, "provider-latency", 0, "The latency added to every read and write of the inmemory provider.")
+	flag.StringVar(&cpuProfile, "cpuprofile", "", "Write a CPU profile of the run to this file.")
+	flag.StringVar(&memProfile, "memprofile", "", "Write a heap allocation profile of the run to this file.")
+	opts := zap.Options{
+		Level: zapcore.ErrorLevel,
+	}
+	opts.BindFlags(flag.CommandLine)
+	flag.Parse()
+	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
+
+	if err := run(config, cpuProfile, memProfile); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(config loadtest.Config, cpuProfile, memProfile string) error {
+	if cpuProfile != "" {
+		f, err := os.Create(cpuProfile)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		if err = pprof.StartCPUProfile(f); err != nil {
+			return err
+		}
+		defer pprof.StopCPUProfile()
+	}
+
+	report, err := loadtest.Run(context.Background(), config)
+	if err != nil {
+		return err
+	}
+
+	if memProfile != "" {
+		f, err := os.Create(memProfile)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		runtime.GC()
+		if err = pprof.Lookup("allocs").WriteTo(f, 0); err != nil {
+			return err
+		}
+	}
+	return report.Write(os.Stdout)
+}
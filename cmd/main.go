@@ -17,13 +17,18 @@ limitations under the License.
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
+	"net/url"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
@@ -33,15 +38,20 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	gatewayapiv1 "sigs.k8s.io/gateway-api/apis/v1"
 
 	"github.com/kuadrant/dns-operator/api/v1alpha1"
+	"github.com/kuadrant/dns-operator/api/v1beta1"
+	"github.com/kuadrant/dns-operator/internal/common/config"
 	"github.com/kuadrant/dns-operator/internal/controller"
 	"github.com/kuadrant/dns-operator/internal/probes"
 	"github.com/kuadrant/dns-operator/internal/provider"
 	_ "github.com/kuadrant/dns-operator/internal/provider/aws"
 	_ "github.com/kuadrant/dns-operator/internal/provider/azure"
+	"github.com/kuadrant/dns-operator/internal/provider/external"
 	_ "github.com/kuadrant/dns-operator/internal/provider/google"
-	_ "github.com/kuadrant/dns-operator/internal/provider/inmemory"
+	"github.com/kuadrant/dns-operator/internal/provider/inmemory"
+	dnswebhook "github.com/kuadrant/dns-operator/internal/webhook"
 	//+kubebuilder:scaffold:imports
 )
 
@@ -63,6 +73,8 @@ func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
 
 	utilruntime.Must(v1alpha1.AddToScheme(scheme))
+	utilruntime.Must(v1beta1.AddToScheme(scheme))
+	utilruntime.Must(gatewayapiv1.AddToScheme(scheme))
 	//+kubebuilder:scaffold:scheme
 }
 
@@ -70,6 +82,19 @@ func printControllerMetaInfo() {
 	setupLog.Info("build information", "version", version, "commit", gitSHA, "dirty", dirty)
 }
 
+// printEffectiveConfig logs the value of every option, and where the options not left at their default were set.
+func printEffectiveConfig(options []config.Option) {
+	values := make(map[string]string, len(options))
+	sources := map[string]config.Source{}
+	for _, option := range options {
+		values[option.Name] = option.Value
+		if option.Source != config.SourceDefault {
+			sources[option.Name] = option.Source
+		}
+	}
+	setupLog.Info("effective configuration", "options", values, "sources", sources)
+}
+
 func main() {
 	var metricsAddr string
 	var enableLeaderElection bool
@@ -79,10 +104,80 @@ func main() {
 	var maxRequeueTime time.Duration
 	var providers stringSliceFlags
 	var dnsProbesEnabled bool
+	var probeControllerEnabled bool
 	var allowInsecureCerts bool
+	var maxConcurrentProviderWrites int
+	var writeBudget int
+	var writeBudgetInterval time.Duration
+	var providerRecordsCacheDuration time.Duration
+	var providerZonesCacheDuration time.Duration
+	var zoneWarmup bool
+	var zoneRecordLimit int
+	var unownedPublishDomains stringSliceFlags
+	var duplicateRootHostPolicy string
+	var privateTargetPolicy string
+	var rrsetSizePolicy string
+	var deletionStuckDuration time.Duration
+	var adoptionBatchSize int
+	var excludeDNSNames repeatedStringFlags
+	var excludeTargetCIDRs stringSliceFlags
+	var probeShards int
+	var probeShard string
+	var endpointsHealthyDebounce time.Duration
+	var probeTimeout time.Duration
+	var probeSpread bool
+	var probeJitter float64
+	var watchNamespaceSelector string
+	var parkingTarget string
+	var checkZoneDelegation bool
+	var freezeOnDrift bool
+	var takeoverProtection bool
+	var zoneStateRecord bool
+	var gatewayDNSRecords bool
+	var dampeningWindow time.Duration
+	var reconcileTimeout time.Duration
+	var providerCallTimeout time.Duration
+	var enforceDNSQuota bool
+	var validateEndpointTargets bool
+	var namespaceDefaults bool
+	var canonicalizeEndpoints bool
+	var conversionWebhook bool
+	var canaryDNSRecord string
+	var canaryNameserver string
+	var requireDomainVerification bool
+	var ownerIDAlgorithm string
+	var ownerIDLength int
+	var ownerIDPrefix string
+	var migrateOwnerIDs bool
+	var eventAggregationWindow time.Duration
+	var eventRateLimit float64
+	var changeNotificationURL string
+	var externalProviders stringSliceFlags
+	var lite bool
+	var inmemoryDNSServerAddr string
+	var configFile string
+	var watchNamespaces string
 
 	flag.BoolVar(&dnsProbesEnabled, "enable-probes", true, "Enable DNSHealthProbes controller.")
+	flag.BoolVar(&probeControllerEnabled, "enable-probe-controller", true, "Run the DNSHealthProbes controller in this process. "+
+		"Set to false when health checks are executed by a separate probe-agent deployment.")
 	flag.BoolVar(&allowInsecureCerts, "insecure-health-checks", true, "Allow DNSHealthProbes to use insecure certificates")
+	flag.IntVar(&probeShards, "probe-shards", 0, "The number of shards DNSHealthProbes are spread across. "+
+		"Each probe is labelled with its shard and only run by the probe controllers of that shard. Probes are not sharded if 1 or less.")
+	flag.StringVar(&probeShard, "probe-shard", "", "The shard of the DNSHealthProbes the probe controller in this process runs, "+
+		"between 0 and probe-shards - 1. The probe controller of a shard runs on every replica, not only the leader. All probes are run if not set.")
+	flag.DurationVar(&probeTimeout, "probe-timeout", probes.PROBE_TIMEOUT,
+		"How long a health probe request, including resolving its address, can take before it fails, for probes that don't "+
+			"set a timeout in their health check spec")
+	flag.BoolVar(&probeSpread, "probe-spread", false,
+		"Run each health probe at an offset into its interval derived from its name, so probes created at the same time are "+
+			"spread through the interval instead of all sending requests at once. Disabled by default")
+	flag.Float64Var(&probeJitter, "probe-jitter", 0,
+		"The fraction of its interval, between 0 and 0.5, the wait before each run of a health probe is randomly shifted by "+
+			"in either direction, e.g. 0.1 for up to 6 seconds of a 1 minute interval. Disabled by default")
+	flag.DurationVar(&endpointsHealthyDebounce, "endpoints-healthy-debounce", 30*time.Second,
+		"The minimum time between writes of the EndpointsHealthy condition aggregating the health probes of a DNSRecord, "+
+			"changes within it are written when it ends. Changes are written as soon as they are seen if 0")
 
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
@@ -98,16 +193,172 @@ func main() {
 	flag.DurationVar(&minRequeueTime, "min-requeue-time", DefaultValidationDuration,
 		"The minimal timeout between calls to the DNS Provider"+
 			"Controls if we commit to the full reconcile loop")
+	flag.DurationVar(&providerCallTimeout, "provider-call-timeout", 0,
+		"Maximum duration of a single call to a DNS provider. Reads that take longer are abandoned and fail, changes are "+
+			"given the timeout and waited for. Disabled by default")
+	flag.DurationVar(&reconcileTimeout, "reconcile-timeout", 0,
+		"Maximum duration of a DNSRecord reconcile. A reconcile still running at the timeout has the running goroutines "+
+			"logged, is counted in dns_record_reconcile_timeouts_total and is aborted and requeued. Disabled by default")
+	flag.IntVar(&maxConcurrentProviderWrites, "max-concurrent-provider-writes", 0,
+		"The maximum number of concurrent writes allowed to a DNS Provider using the same provider secret. "+
+			"A value of 0 means no limit")
+	flag.IntVar(&writeBudget, "write-budget", 0,
+		"The number of writes, each applying all the changes of a reconcile to a zone however many endpoints it changes, that "+
+			"can be made using the same provider secret in each write budget interval. Changes over the budget are deferred to "+
+			"the next interval and the DNSRecord gets the BudgetExceeded condition. Provider secrets can set their own budget "+
+			"with the WRITE_BUDGET key. Disabled by default")
+	flag.DurationVar(&writeBudgetInterval, "write-budget-interval", time.Minute,
+		"The interval the write budget of each provider secret is renewed at")
+	flag.DurationVar(&providerRecordsCacheDuration, "provider-records-cache-duration", 0,
+		"The duration the records read from a DNS Provider zone are shared by DNS Records in the same zone, e.g. 10s, so records "+
+			"reconciled at the same time don't each read the zone. The records are read again after changes are written to the zone. "+
+			"A value of 0 disables the cache")
+	flag.DurationVar(&providerZonesCacheDuration, "provider-zones-cache-duration", 0,
+		"The duration the zones listed with a provider secret are used to find the zones of DNS Records, e.g. 5m, so each record "+
+			"doesn't list the zones of the secret again. The zones are listed again when the secret changes. A value of 0 disables the cache")
+	flag.BoolVar(&zoneWarmup, "zone-warmup", false,
+		"List the zones of the default provider secrets of namespaces and of provider secrets labeled "+v1alpha1.ZoneWarmupLabel+"=true "+
+			"at startup, logging the zones visible to each secret and a ZoneListFailed event on secrets that can't list zones. "+
+			"The zones are cached if provider-zones-cache-duration is set. Disabled by default")
+	flag.IntVar(&zoneRecordLimit, "zone-record-limit", 0,
+		"The maximum number of records allowed in a zone by the DNS Provider, e.g. 10000. The records of zones are counted, "+
+			"including the registry TXT records of every owner, and DNS Records publishing to zones with 80% of the records allowed "+
+			"get the RegistryPressure condition. Provider secrets can set their own limit with the "+v1alpha1.ZoneRecordLimitKey+" key. Disabled by default")
+	flag.DurationVar(&deletionStuckDuration, "deletion-stuck-duration", controller.DefaultDeletionStuckDuration,
+		"The duration a deleted DNS Record can fail to be removed from the DNS Provider before it is reported as stuck "+
+			"with the dns_record_deletion_stuck metric and a DeletionStuck event")
+	flag.IntVar(&adoptionBatchSize, "adoption-batch-size", controller.DefaultAdoptionBatchSize,
+		"The maximum number of endpoints adopted from another external-dns instance that have their registry TXT records "+
+			"rewritten in a single reconcile of a DNS Record")
+	flag.Var(&unownedPublishDomains, "unowned-publish-domain", "Domain(s) in which DNSRecords are allowed to publish endpoints without ownership using the "+
+		v1alpha1.UnownedPublishAnnotation+" annotation. Can be passed multiple times or as a comma separated list. "+
+		"Records published without ownership are never deleted by the operator")
+	flag.Var(&excludeDNSNames, "exclude-dns-names", "Regular expression(s) matching the DNS names of endpoints that are never published. "+
+		"Can be passed multiple times.")
+	flag.Var(&excludeTargetCIDRs, "exclude-target-cidrs", "IP range(s) that the targets of A and AAAA endpoints are never published in, "+
+		"e.g. cluster internal ranges. Can be passed multiple times or as a comma separated list.")
+	flag.StringVar(&duplicateRootHostPolicy, "duplicate-root-host-policy", "",
+		"Check new DNSRecords for a rootHost already used by a DNSRecord in another namespace with the same provider account, "+
+			"one of \"warn\" or \"reject\". Requires the DNSRecord validating webhook to be deployed. Disabled by default")
+	flag.StringVar(&privateTargetPolicy, "private-target-policy", "",
+		"Check DNSRecords publishing to public zones for private, link-local or loopback address targets, "+
+			"one of \"warn\" or \"reject\". Disabled by default")
+	flag.StringVar(&rrsetSizePolicy, "rrset-size-policy", "",
+		"Check the responses for the RRsets of DNSRecords fit in a UDP or EDNS message and the RRsets have no more targets "+
+			"than the provider allows before publishing, one of \"warn\" or \"reject\". Disabled by default")
+	flag.StringVar(&parkingTarget, "parking-target", "",
+		"Hostname or address the rootHost of DNSRecords with spec.parked set points to, e.g. a sorry page. "+
+			"Records can't be parked if not set")
+	flag.BoolVar(&checkZoneDelegation, "check-zone-delegation", false,
+		"Check the zone of a DNSRecord is delegated to its nameservers by the parent zone with live DNS queries before the record "+
+			"is first published, and set the ZoneDelegationBroken condition when it is not. Disabled by default")
+	flag.BoolVar(&freezeOnDrift, "freeze-on-drift", false,
+		"Stop publishing a DNSRecord when its published endpoints were changed in the zone outside of the operator, and set the "+
+			"DriftDetected condition with the observed values. The changes are overwritten once the record is annotated with "+
+			"kuadrant.io/drift-acknowledged. Disabled by default")
+	flag.BoolVar(&takeoverProtection, "takeover-protection", false,
+		"Stop publishing changes of a DNSRecord that remove the targets or ownership of other owners of shared endpoints, and set "+
+			"the TakeoverPending condition with a token. The changes are published once the record is annotated with "+
+			"kuadrant.io/confirm-takeover set to the token. Disabled by default")
+	flag.BoolVar(&zoneStateRecord, "zone-state-record", false,
+		"Maintain a _kuadrant-state TXT record at the apex of every zone changes are applied to, with a serial increased and "+
+			"the time updated on every change, so the freshness of zones can be monitored with DNS queries. Disabled by default")
+	flag.BoolVar(&gatewayDNSRecords, "gateway-dns-records", false,
+		"Create a DNSRecord targeting the addresses of the Gateway for every listener hostname of Gateways annotated with "+
+			"kuadrant.io/dns-zone that is in the zone. Requires the Gateway API to be installed. Disabled by default")
+	flag.DurationVar(&dampeningWindow, "dampening-window", 0,
+		"How long the endpoints of a DNSRecord must be unchanged for before changes to them are published, so sources "+
+			"flapping between values are not mirrored into the provider. The window is doubled while the endpoints keep "+
+			"changing, up to 16 times this value. Disabled by default")
+	flag.BoolVar(&enforceDNSQuota, "enforce-dns-quota", false,
+		"Reject DNSRecords that would take their namespace over the maximum number of records or endpoints of its DNSQuotas. "+
+			"Requires the DNSRecord validating webhook to be deployed. Disabled by default")
+	flag.BoolVar(&validateEndpointTargets, "validate-endpoint-targets", false,
+		"Validate endpoint targets against their record type, e.g. A targets must be IPv4 addresses. DNSRecords with "+
+			"invalid targets fail to reconcile, and are rejected on admission when the DNSRecord validating webhook is "+
+			"deployed. Disabled by default")
+	flag.BoolVar(&requireDomainVerification, "require-domain-verification", false,
+		"Require ownership of the root host of a new DNSRecord to be verified with a TXT record, created by the owner of the "+
+			"root host with the value in the status of the record, before the record is first published. Disabled by default")
+	flag.StringVar(&ownerIDAlgorithm, "owner-id-algorithm", string(controller.OwnerIDAlgorithmSHA224),
+		"The hash algorithm the owner IDs of DNSRecords without an ownerID in their spec are derived from their UID with, "+
+			"one of sha224 or sha256")
+	flag.IntVar(&ownerIDLength, "owner-id-length", 8,
+		"The number of characters of the hash in the owner IDs of DNSRecords without an ownerID in their spec. Longer "+
+			"owner IDs make collisions less likely when many clusters publish to the same zones")
+	flag.StringVar(&ownerIDPrefix, "owner-id-prefix", "",
+		"An ID of the cluster prepended to the owner IDs of DNSRecords without an ownerID in their spec, so the records of "+
+			"different clusters never share an owner ID. Not prepended if not set")
+	flag.BoolVar(&migrateOwnerIDs, "migrate-owner-ids", false,
+		"Change the owner ID of existing DNSRecords without an ownerID in their spec when the owner-id flags derive a "+
+			"different one, rewriting their registry TXT records from the previous to the new owner ID. Only records "+
+			"created after the owner-id flags are changed get the new owner IDs otherwise. Disabled by default")
+	flag.DurationVar(&eventAggregationWindow, "event-aggregation-window", 0,
+		"How long events of an object with the same reason are suppressed for after one is recorded, for the events of "+
+			"DNSRecords, Gateways and provider secrets. The number of suppressed events is added to the next event recorded "+
+			"after the window. Disabled by default")
+	flag.Float64Var(&eventRateLimit, "event-rate-limit", 0,
+		"The maximum number of events recorded per second by all controllers, with bursts of up to 25 events. Events over "+
+			"the limit are dropped. Disabled by default")
+	flag.StringVar(&changeNotificationURL, "change-notification-url", "",
+		"The HTTP URL of a CloudEvents sink, e.g. a Knative broker or a NATS or Kafka bridge, the changes applied to zones "+
+			"are published to as they are applied. Disabled by default")
+	flag.BoolVar(&namespaceDefaults, "namespace-defaults", false,
+		"Merge the TTL, provider secret and health check defaults declared with annotations on the namespace of a DNSRecord "+
+			"into the record on admission. Requires the DNSRecord mutating webhook to be deployed. Disabled by default")
+	flag.BoolVar(&canonicalizeEndpoints, "canonicalize-endpoints", false,
+		"Sort the endpoints, targets and gateway endpoints of DNSRecords on admission and set the kuadrant.io/spec-hash "+
+			"annotation, so specs only reordered by their producers don't trigger reconciles. Requires the DNSRecord "+
+			"mutating webhook to be deployed. Disabled by default")
+	flag.BoolVar(&conversionWebhook, "conversion-webhook", false,
+		"Serve the webhook converting DNSRecords between the v1alpha1 version they are stored as and the v1beta1 version. "+
+			"Required to serve v1beta1 DNSRecords. Disabled by default")
+	flag.StringVar(&canaryDNSRecord, "canary-dnsrecord", "",
+		"The namespace/name of a canary DNSRecord. When set the operator is only ready once the record is ready and its "+
+			"published endpoints resolve, so onboarding automation can wait until records are published end to end. "+
+			"Disabled by default")
+	flag.StringVar(&canaryNameserver, "canary-nameserver", "",
+		"The address of the nameserver the endpoints of the canary DNSRecord are resolved through, e.g. 10.0.0.10 or "+
+			"10.0.0.10:5353. The resolver of the system is used if not set")
+	flag.StringVar(&watchNamespaceSelector, "watch-namespace-selector", "",
+		"Label selector of the namespaces DNSRecords and DNSHealthProbes are reconciled in, e.g. kuadrant.io/dns=enabled. "+
+			"Changes to namespace labels are picked up without a restart. All namespaces are reconciled if not set")
+	flag.Var(&externalProviders, "external-provider", "Out of tree DNS Provider(s) to enable as name=url, served by a sidecar implementing "+
+		"the external-dns webhook provider API at the url. Provider secrets of type kuadrant.io/<name> use the provider. "+
+		"Can be passed multiple times or as a comma separated list e.g. --external-provider powerdns=http://localhost:8888")
+	flag.BoolVar(&lite, "lite", false, "Run with a reduced memory footprint for single node edge clusters: health checks, "+
+		"the metrics endpoint and metrics collectors are disabled and managed fields are dropped from cached resources. "+
+		"Overrides enable-probes and metrics-bind-address")
+	flag.StringVar(&inmemoryDNSServerAddr, "inmemory-dns-server-address", "", "The address of a DNS server, over UDP and TCP, answering queries "+
+		"for the zones of the inmemory provider, for tests and local demos e.g. 127.0.0.1:5353. Disabled by default")
 	flag.Var(&providers, "provider", "DNS Provider(s) to enable. Can be passed multiple times e.g. --provider aws --provider google, or as a comma separated list e.g. --provider aws,gcp")
+	flag.StringVar(&watchNamespaces, "watch-namespaces", "", "Comma separated namespaces to watch, all namespaces if unset. "+
+		"Can also be set with the WATCH_NAMESPACES environment variable.")
+	flag.StringVar(&configFile, "config", "", "Path to a YAML config file setting any of the other options, the value of an option is "+
+		"taken from the command line, then its DNS_OPERATOR_ environment variable, then the config file.")
 	opts := zap.Options{}
 	opts.BindFlags(flag.CommandLine)
 	flag.Parse()
 
+	// options not set on the command line are taken from the environment and the config file, before the logger is
+	// created so it can be configured by them as well
+	effectiveConfig, configErr := config.Load(flag.CommandLine, configFile, map[string]string{"watch-namespaces": "WATCH_NAMESPACES"})
+
 	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
 
+	if configErr != nil {
+		setupLog.Error(configErr, "invalid configuration")
+		os.Exit(1)
+	}
+
+	if lite {
+		dnsProbesEnabled = false
+		metricsAddr = "0"
+	}
+
 	printControllerMetaInfo()
+	printEffectiveConfig(effectiveConfig)
 
-	var watchNamespaces = "WATCH_NAMESPACES"
 	defaultOptions := ctrl.Options{
 		Scheme:                 scheme,
 		Metrics:                metricsserver.Options{BindAddress: metricsAddr},
@@ -117,9 +368,9 @@ func main() {
 		LeaderElectionID:       "a3f98d6c.kuadrant.io",
 	}
 
-	if watch := os.Getenv(watchNamespaces); watch != "" {
-		namespaces := strings.Split(watch, ",")
-		setupLog.Info("watching namespaces set ", watchNamespaces, namespaces)
+	if watchNamespaces != "" {
+		namespaces := strings.Split(watchNamespaces, ",")
+		setupLog.Info("watching namespaces set ", "WATCH_NAMESPACES", namespaces)
 		cacheOpts := cache.Options{
 			DefaultNamespaces: map[string]cache.Config{},
 		}
@@ -129,6 +380,20 @@ func main() {
 		defaultOptions.Cache = cacheOpts
 	}
 
+	if lite {
+		defaultOptions.Cache.DefaultTransform = cache.TransformStripManagedFields()
+	}
+
+	var namespaceSelector labels.Selector
+	if watchNamespaceSelector != "" {
+		selector, err := labels.Parse(watchNamespaceSelector)
+		if err != nil {
+			setupLog.Error(err, "invalid watch-namespace-selector")
+			os.Exit(1)
+		}
+		namespaceSelector = selector
+	}
+
 	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), defaultOptions)
 	if err != nil {
 		setupLog.Error(err, "unable to start manager")
@@ -143,37 +408,205 @@ func main() {
 		}
 		providers = defaultProviders
 	}
+	for _, externalProvider := range externalProviders {
+		name, url, _ := strings.Cut(externalProvider, "=")
+		if err = external.Register(name, url); err != nil {
+			setupLog.Error(err, "unable to register external provider")
+			os.Exit(1)
+		}
+		providers = append(providers, name)
+	}
 
 	setupLog.Info("init provider factory", "providers", providers)
-	providerFactory, err := provider.NewFactory(mgr.GetClient(), providers)
+	providerFactory, err := provider.NewFactory(mgr.GetClient(), providers, provider.WithMaxConcurrentWrites(maxConcurrentProviderWrites),
+		provider.WithWriteBudget(writeBudget, writeBudgetInterval),
+		provider.WithRecordsCacheDuration(providerRecordsCacheDuration),
+		provider.WithZonesCacheDuration(providerZonesCacheDuration),
+		provider.WithCallTimeout(providerCallTimeout))
 	if err != nil {
 		setupLog.Error(err, "unable to create provider factory")
 		os.Exit(1)
 	}
 
+	endpointExclusions, err := controller.NewEndpointExclusions(excludeDNSNames, excludeTargetCIDRs)
+	if err != nil {
+		setupLog.Error(err, "invalid endpoint exclusions")
+		os.Exit(1)
+	}
+
+	targetPolicy, err := controller.ParsePrivateTargetPolicy(privateTargetPolicy)
+	if err != nil {
+		setupLog.Error(err, "invalid private-target-policy")
+		os.Exit(1)
+	}
+
+	sizePolicy, err := controller.ParseRRsetSizePolicy(rrsetSizePolicy)
+	if err != nil {
+		setupLog.Error(err, "invalid rrset-size-policy")
+		os.Exit(1)
+	}
+
+	ownerIDFormat := controller.OwnerIDFormat{
+		Algorithm: controller.OwnerIDAlgorithm(ownerIDAlgorithm),
+		Length:    ownerIDLength,
+		Prefix:    ownerIDPrefix,
+	}
+	if err = ownerIDFormat.Validate(); err != nil {
+		setupLog.Error(err, "invalid owner ID format")
+		os.Exit(1)
+	}
+	if changeNotificationURL != "" {
+		if u, err := url.Parse(changeNotificationURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			setupLog.Error(fmt.Errorf("%q is not an http or https URL", changeNotificationURL), "invalid change notification URL")
+			os.Exit(1)
+		}
+	}
+
+	// the events of all controllers share the same limits
+	eventLimits := controller.NewEventLimits(eventAggregationWindow, float32(eventRateLimit))
 	if err = (&controller.DNSRecordReconciler{
-		Client:          mgr.GetClient(),
-		Scheme:          mgr.GetScheme(),
-		ProviderFactory: providerFactory,
+		Client:                    mgr.GetClient(),
+		Scheme:                    mgr.GetScheme(),
+		ProviderFactory:           providerFactory,
+		UnownedPublishDomains:     unownedPublishDomains,
+		EndpointExclusions:        endpointExclusions,
+		DeletionStuckDuration:     deletionStuckDuration,
+		AdoptionBatchSize:         adoptionBatchSize,
+		PrivateTargetPolicy:       targetPolicy,
+		RRsetSizePolicy:           sizePolicy,
+		ProbeShards:               probeShards,
+		ParkingTarget:             parkingTarget,
+		CheckZoneDelegation:       checkZoneDelegation,
+		FreezeOnDrift:             freezeOnDrift,
+		TakeoverProtection:        takeoverProtection,
+		ZoneStateRecord:           zoneStateRecord,
+		ZoneRecordLimit:           zoneRecordLimit,
+		DampeningWindow:           dampeningWindow,
+		ReconcileTimeout:          reconcileTimeout,
+		RequireDomainVerification: requireDomainVerification,
+		ValidateTargets:           validateEndpointTargets,
+		OwnerIDFormat:             ownerIDFormat,
+		MigrateOwnerIDs:           migrateOwnerIDs,
+		EventLimits:               eventLimits,
+		ChangeNotificationURL:     changeNotificationURL,
+		WatchNamespaceSelector:    namespaceSelector,
+		DisableOverdueMetrics:     lite,
+		DisableNamespaceMetrics:   lite,
 	}).SetupWithManager(mgr, maxRequeueTime, validFor, minRequeueTime, dnsProbesEnabled, allowInsecureCerts); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "DNSRecord")
 		os.Exit(1)
 	}
 
-	if dnsProbesEnabled {
+	if gatewayDNSRecords {
+		if err = (&controller.GatewayReconciler{
+			Client:                 mgr.GetClient(),
+			Scheme:                 mgr.GetScheme(),
+			Recorder:               eventLimits.Wrap(mgr.GetEventRecorderFor("gateway-controller")),
+			WatchNamespaceSelector: namespaceSelector,
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "Gateway")
+			os.Exit(1)
+		}
+	}
+
+	if zoneWarmup {
+		if err = mgr.Add(&controller.ZoneWarmup{
+			Client:                 mgr.GetClient(),
+			ProviderFactory:        providerFactory,
+			Recorder:               eventLimits.Wrap(mgr.GetEventRecorderFor("zone-warmup")),
+			WatchNamespaceSelector: namespaceSelector,
+		}); err != nil {
+			setupLog.Error(err, "unable to add zone warm-up")
+			os.Exit(1)
+		}
+	}
+
+	if probeShard != "" {
+		if shard, err := strconv.Atoi(probeShard); err != nil || shard < 0 || (probeShards > 1 && shard >= probeShards) {
+			setupLog.Error(fmt.Errorf("shard must be between 0 and %d", probeShards-1), "invalid probe-shard", "probe-shard", probeShard)
+			os.Exit(1)
+		}
+	}
+
+	if dnsProbesEnabled && probeControllerEnabled {
+		if probeTimeout <= 0 {
+			setupLog.Error(fmt.Errorf("probe timeout must be greater than 0"), "invalid probe-timeout", "probe-timeout", probeTimeout)
+			os.Exit(1)
+		}
+		probes.ProbeTimeout = probeTimeout
+		if probeJitter < 0 || probeJitter > 0.5 {
+			setupLog.Error(fmt.Errorf("probe jitter must be between 0 and 0.5"), "invalid probe-jitter", "probe-jitter", probeJitter)
+			os.Exit(1)
+		}
+		probes.ProbeSpread = probeSpread
+		probes.ProbeJitter = probeJitter
+		if endpointsHealthyDebounce < 0 {
+			setupLog.Error(fmt.Errorf("debounce must be 0 or more"), "invalid endpoints-healthy-debounce", "endpoints-healthy-debounce", endpointsHealthyDebounce)
+			os.Exit(1)
+		}
 		probeManager := probes.NewProbeManager()
 		if err = (&controller.DNSProbeReconciler{
-			Client:       mgr.GetClient(),
-			Scheme:       mgr.GetScheme(),
-			ProbeManager: probeManager,
+			Client:                   mgr.GetClient(),
+			Scheme:                   mgr.GetScheme(),
+			ProbeManager:             probeManager,
+			Shard:                    probeShard,
+			WatchNamespaceSelector:   namespaceSelector,
+			EndpointsHealthyDebounce: endpointsHealthyDebounce,
 		}).SetupWithManager(mgr, maxRequeueTime, validFor, minRequeueTime); err != nil {
 			setupLog.Error(err, "unable to create controller", "controller", "DNSProbe")
 			os.Exit(1)
 		}
 	}
 
+	rootHostPolicy, err := dnswebhook.ParseDuplicateRootHostPolicy(duplicateRootHostPolicy)
+	if err != nil {
+		setupLog.Error(err, "invalid duplicate-root-host-policy")
+		os.Exit(1)
+	}
+	if rootHostPolicy != dnswebhook.DuplicateRootHostPolicyNone || enforceDNSQuota || validateEndpointTargets {
+		if err = (&dnswebhook.DNSRecordValidator{
+			Client:          mgr.GetClient(),
+			Policy:          rootHostPolicy,
+			EnforceQuota:    enforceDNSQuota,
+			ValidateTargets: validateEndpointTargets,
+		}).SetupWebhookWithManager(context.Background(), mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "DNSRecord")
+			os.Exit(1)
+		}
+	}
+
+	if namespaceDefaults || canonicalizeEndpoints {
+		defaulter := &dnswebhook.DNSRecordDefaulter{CanonicalizeEndpoints: canonicalizeEndpoints}
+		if namespaceDefaults {
+			defaulter.Client = mgr.GetClient()
+		}
+		if err = defaulter.SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create defaulting webhook", "webhook", "DNSRecord")
+			os.Exit(1)
+		}
+	}
+
+	if conversionWebhook {
+		if err = (&v1beta1.DNSRecord{}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create conversion webhook", "webhook", "DNSRecord")
+			os.Exit(1)
+		}
+	}
+
 	//+kubebuilder:scaffold:builder
 
+	if inmemoryDNSServerAddr != "" {
+		server, err := inmemory.NewServer(inmemoryDNSServerAddr)
+		if err != nil {
+			setupLog.Error(err, "unable to create inmemory DNS server")
+			os.Exit(1)
+		}
+		if err = mgr.Add(server); err != nil {
+			setupLog.Error(err, "unable to add inmemory DNS server")
+			os.Exit(1)
+		}
+	}
+
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
 		setupLog.Error(err, "unable to set up health check")
 		os.Exit(1)
@@ -182,6 +615,21 @@ func main() {
 		setupLog.Error(err, "unable to set up ready check")
 		os.Exit(1)
 	}
+	if canaryDNSRecord != "" {
+		namespace, name, ok := strings.Cut(canaryDNSRecord, "/")
+		if !ok || namespace == "" || name == "" {
+			setupLog.Error(fmt.Errorf("canary DNSRecord must be namespace/name"), "invalid canary-dnsrecord", "canary-dnsrecord", canaryDNSRecord)
+			os.Exit(1)
+		}
+		if err := mgr.AddReadyzCheck("canary", (&controller.CanaryCheck{
+			Client:     mgr.GetAPIReader(),
+			Record:     types.NamespacedName{Namespace: namespace, Name: name},
+			Nameserver: canaryNameserver,
+		}).Check); err != nil {
+			setupLog.Error(err, "unable to set up canary ready check")
+			os.Exit(1)
+		}
+	}
 
 	setupLog.Info("starting manager")
 	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
@@ -210,3 +658,18 @@ func (n *stringSliceFlags) Set(s string) error {
 	}
 	return nil
 }
+
+// repeatedStringFlags is a flag that can be passed multiple times, values are not split so they can contain commas
+type repeatedStringFlags []string
+
+func (n *repeatedStringFlags) String() string {
+	return strings.Join(*n, " ")
+}
+
+func (n *repeatedStringFlags) Set(s string) error {
+	if len(s) == 0 {
+		return fmt.Errorf("cannot be empty")
+	}
+	*n = append(*n, s)
+	return nil
+}
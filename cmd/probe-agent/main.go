@@ -0,0 +1,200 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// The probe agent runs only the DNSHealthCheckProbe controller, so that health check workers can be deployed and scaled
+// separately from the DNSRecord controller. The dns operator must be started with --enable-probe-controller=false
+// when a probe agent is deployed, the two communicate only through the DNSHealthCheckProbe resources.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	_ "k8s.io/client-go/plugin/pkg/client/auth"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+
+	"github.com/kuadrant/dns-operator/api/v1alpha1"
+	"github.com/kuadrant/dns-operator/internal/controller"
+	"github.com/kuadrant/dns-operator/internal/probes"
+)
+
+var (
+	scheme   = runtime.NewScheme()
+	setupLog = ctrl.Log.WithName("setup")
+	gitSHA   string // pass ldflag here to display gitSHA hash
+	dirty    string // must be string as passed in by ldflag to determine display .
+	version  string // must be string as passed in by ldflag to determine display .
+)
+
+const (
+	RequeueDuration           = time.Minute * 15
+	ValidityDuration          = time.Minute * 14
+	DefaultValidationDuration = time.Second * 5
+)
+
+func init() {
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+
+	utilruntime.Must(v1alpha1.AddToScheme(scheme))
+}
+
+func main() {
+	var metricsAddr string
+	var enableLeaderElection bool
+	var probeAddr string
+	var minRequeueTime time.Duration
+	var validFor time.Duration
+	var maxRequeueTime time.Duration
+	var probeShard string
+	var watchNamespaceSelector string
+	var probeTimeout time.Duration
+	var probeSpread bool
+	var probeJitter float64
+	var endpointsHealthyDebounce time.Duration
+
+	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
+	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
+	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
+		"Enable leader election for the probe agent. "+
+			"Enabling this will ensure there is only one active probe agent.")
+	flag.DurationVar(&maxRequeueTime, "max-requeue-time", RequeueDuration,
+		"The maximum times it takes between reconciliations of DNSHealthCheckProbes")
+	flag.DurationVar(&validFor, "valid-for", ValidityDuration,
+		"Duration when the probe is considered to hold valid information")
+	flag.DurationVar(&minRequeueTime, "min-requeue-time", DefaultValidationDuration,
+		"The minimal timeout between reconciliations of DNSHealthCheckProbes")
+	flag.StringVar(&probeShard, "probe-shard", "", "The shard of the DNSHealthProbes this agent runs, "+
+		"set by the operator when started with --probe-shards. Sharded agents run without leader election. All probes are run if not set.")
+	flag.StringVar(&watchNamespaceSelector, "watch-namespace-selector", "",
+		"Label selector of the namespaces DNSHealthProbes are run in, e.g. kuadrant.io/dns=enabled. "+
+			"Changes to namespace labels are picked up without a restart. All namespaces are run if not set")
+	flag.DurationVar(&probeTimeout, "probe-timeout", probes.PROBE_TIMEOUT,
+		"How long a health probe request, including resolving its address, can take before it fails, for probes that don't "+
+			"set a timeout in their health check spec")
+	flag.BoolVar(&probeSpread, "probe-spread", false,
+		"Run each health probe at an offset into its interval derived from its name, so probes created at the same time are "+
+			"spread through the interval instead of all sending requests at once. Disabled by default")
+	flag.Float64Var(&probeJitter, "probe-jitter", 0,
+		"The fraction of its interval, between 0 and 0.5, the wait before each run of a health probe is randomly shifted by "+
+			"in either direction, e.g. 0.1 for up to 6 seconds of a 1 minute interval. Disabled by default")
+	flag.DurationVar(&endpointsHealthyDebounce, "endpoints-healthy-debounce", 30*time.Second,
+		"The minimum time between writes of the EndpointsHealthy condition aggregating the health probes of a DNSRecord, "+
+			"changes within it are written when it ends. Changes are written as soon as they are seen if 0")
+	opts := zap.Options{}
+	opts.BindFlags(flag.CommandLine)
+	flag.Parse()
+
+	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
+
+	setupLog.Info("build information", "version", version, "commit", gitSHA, "dirty", dirty)
+
+	if shard, err := strconv.Atoi(probeShard); probeShard != "" && (err != nil || shard < 0) {
+		setupLog.Error(fmt.Errorf("shard must be a number of 0 or more"), "invalid probe-shard", "probe-shard", probeShard)
+		os.Exit(1)
+	}
+
+	if probeTimeout <= 0 {
+		setupLog.Error(fmt.Errorf("probe timeout must be greater than 0"), "invalid probe-timeout", "probe-timeout", probeTimeout)
+		os.Exit(1)
+	}
+	probes.ProbeTimeout = probeTimeout
+	if probeJitter < 0 || probeJitter > 0.5 {
+		setupLog.Error(fmt.Errorf("probe jitter must be between 0 and 0.5"), "invalid probe-jitter", "probe-jitter", probeJitter)
+		os.Exit(1)
+	}
+	probes.ProbeSpread = probeSpread
+	probes.ProbeJitter = probeJitter
+	if endpointsHealthyDebounce < 0 {
+		setupLog.Error(fmt.Errorf("debounce must be 0 or more"), "invalid endpoints-healthy-debounce", "endpoints-healthy-debounce", endpointsHealthyDebounce)
+		os.Exit(1)
+	}
+
+	var watchNamespaces = "WATCH_NAMESPACES"
+	defaultOptions := ctrl.Options{
+		Scheme:                 scheme,
+		Metrics:                metricsserver.Options{BindAddress: metricsAddr},
+		HealthProbeBindAddress: probeAddr,
+		LeaderElection:         enableLeaderElection,
+		LeaderElectionID:       "b7e0c1f2.probe-agent.kuadrant.io",
+	}
+
+	if watch := os.Getenv(watchNamespaces); watch != "" {
+		namespaces := strings.Split(watch, ",")
+		setupLog.Info("watching namespaces set ", watchNamespaces, namespaces)
+		cacheOpts := cache.Options{
+			DefaultNamespaces: map[string]cache.Config{},
+		}
+		for _, ns := range namespaces {
+			cacheOpts.DefaultNamespaces[ns] = cache.Config{}
+		}
+		defaultOptions.Cache = cacheOpts
+	}
+
+	var namespaceSelector labels.Selector
+	if watchNamespaceSelector != "" {
+		selector, err := labels.Parse(watchNamespaceSelector)
+		if err != nil {
+			setupLog.Error(err, "invalid watch-namespace-selector")
+			os.Exit(1)
+		}
+		namespaceSelector = selector
+	}
+
+	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), defaultOptions)
+	if err != nil {
+		setupLog.Error(err, "unable to start manager")
+		os.Exit(1)
+	}
+
+	if err = (&controller.DNSProbeReconciler{
+		Client:                   mgr.GetClient(),
+		Scheme:                   mgr.GetScheme(),
+		ProbeManager:             probes.NewProbeManager(),
+		Shard:                    probeShard,
+		WatchNamespaceSelector:   namespaceSelector,
+		EndpointsHealthyDebounce: endpointsHealthyDebounce,
+	}).SetupWithManager(mgr, maxRequeueTime, validFor, minRequeueTime); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "DNSProbe")
+		os.Exit(1)
+	}
+
+	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
+		setupLog.Error(err, "unable to set up health check")
+		os.Exit(1)
+	}
+	if err := mgr.AddReadyzCheck("readyz", healthz.Ping); err != nil {
+		setupLog.Error(err, "unable to set up ready check")
+		os.Exit(1)
+	}
+
+	setupLog.Info("starting probe agent")
+	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
+		setupLog.Error(err, "problem running probe agent")
+		os.Exit(1)
+	}
+}
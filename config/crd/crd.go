@@ -0,0 +1,9 @@
+// Package crd embeds the CustomResourceDefinitions of the dns operator, so the schemas can be used without a cluster.
+package crd
+
+import _ "embed"
+
+// DNSRecord is the CustomResourceDefinition of the DNSRecord resource.
+//
+//go:embed bases/kuadrant.io_dnsrecords.yaml
+var DNSRecord []byte
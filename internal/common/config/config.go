@@ -0,0 +1,184 @@
+package config
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+)
+
+// EnvPrefix is the prefix of the environment variables options can be set with, e.g. DNS_OPERATOR_WRITE_BUDGET for
+// the write-budget option.
+const EnvPrefix = "DNS_OPERATOR_"
+
+// Source is where the effective value of an option was taken from.
+type Source string
+
+const (
+	SourceDefault Source = "default"
+	SourceFile    Source = "file"
+	SourceEnv     Source = "env"
+	SourceFlag    Source = "flag"
+)
+
+// Option is the effective value of a command line option and where it was taken from.
+type Option struct {
+	Name   string
+	Value  string
+	Source Source
+}
+
+// EnvName returns the name of the environment variable that sets the option with the given name.
+func EnvName(name string) string {
+	return EnvPrefix + strings.ToUpper(strings.ReplaceAll(name, "-", "_"))
+}
+
+// Load sets the options of the given parsed flag set that were not set on the command line from the environment, and
+// the options set by neither from the YAML config file at the given path, if any. The keys of the file are the names
+// of the options, options of a subsystem can be grouped in a section named after their common prefix, e.g. a probe
+// section with a timeout key sets the probe-timeout option. Aliases are environment variables that set an option in
+// addition to the one named after it.
+// Returns the effective value of every option of the flag set, sorted by name.
+func Load(fs *flag.FlagSet, path string, aliases map[string]string) ([]Option, error) {
+	sources := map[string]Source{}
+	fs.Visit(func(f *flag.Flag) {
+		sources[f.Name] = SourceFlag
+	})
+
+	var errs []string
+	fs.VisitAll(func(f *flag.Flag) {
+		if _, ok := sources[f.Name]; ok {
+			return
+		}
+		names := []string{EnvName(f.Name)}
+		if alias, ok := aliases[f.Name]; ok {
+			names = append(names, alias)
+		}
+		for _, name := range names {
+			value, ok := os.LookupEnv(name)
+			if !ok {
+				continue
+			}
+			if err := fs.Set(f.Name, value); err != nil {
+				errs = append(errs, fmt.Sprintf("environment variable %s: %v", name, err))
+			}
+			sources[f.Name] = SourceEnv
+			return
+		}
+	})
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("invalid configuration: %s", strings.Join(errs, "; "))
+	}
+
+	if path != "" {
+		values, err := readFile(path)
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range sortedKeys(values) {
+			f := fs.Lookup(name)
+			if f == nil {
+				errs = append(errs, fmt.Sprintf("%s: unknown option", name))
+				continue
+			}
+			if _, ok := sources[name]; ok {
+				continue
+			}
+			for _, value := range values[name] {
+				if err := fs.Set(name, value); err != nil {
+					errs = append(errs, fmt.Sprintf("%s: %v", name, err))
+				}
+			}
+			sources[name] = SourceFile
+		}
+		if len(errs) > 0 {
+			return nil, fmt.Errorf("invalid config file %s: %s", path, strings.Join(errs, "; "))
+		}
+	}
+
+	var options []Option
+	fs.VisitAll(func(f *flag.Flag) {
+		source, ok := sources[f.Name]
+		if !ok {
+			source = SourceDefault
+		}
+		options = append(options, Option{Name: f.Name, Value: f.Value.String(), Source: source})
+	})
+	return options, nil
+}
+
+// readFile returns the values of each option set in the YAML config file at the given path, with the sections of the
+// file flattened to the names of the options.
+func readFile(path string) (map[string][]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading config file: %w", err)
+	}
+	var raw map[string]interface{}
+	if err = yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("parsing config file %s: %w", path, err)
+	}
+	values := map[string][]string{}
+	if err = flatten("", raw, values); err != nil {
+		return nil, fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+	return values, nil
+}
+
+func flatten(prefix string, raw map[string]interface{}, values map[string][]string) error {
+	for key, value := range raw {
+		name := key
+		if prefix != "" {
+			name = prefix + "-" + key
+		}
+		switch v := value.(type) {
+		case map[string]interface{}:
+			if err := flatten(name, v, values); err != nil {
+				return err
+			}
+		case []interface{}:
+			for _, item := range v {
+				s, err := scalar(item)
+				if err != nil {
+					return fmt.Errorf("%s: %w", name, err)
+				}
+				values[name] = append(values[name], s)
+			}
+		default:
+			s, err := scalar(v)
+			if err != nil {
+				return fmt.Errorf("%s: %w", name, err)
+			}
+			values[name] = append(values[name], s)
+		}
+	}
+	return nil
+}
+
+// scalar returns the value of a YAML scalar as it would be passed on the command line.
+func scalar(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case nil:
+		return "", fmt.Errorf("must have a value")
+	}
+	return "", fmt.Errorf("must be a string, number, boolean or a list of them")
+}
+
+func sortedKeys(values map[string][]string) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
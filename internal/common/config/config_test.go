@@ -0,0 +1,174 @@
+//go:build unit
+
+package config
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+type listFlag []string
+
+func (l *listFlag) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *listFlag) Set(s string) error {
+	*l = append(*l, s)
+	return nil
+}
+
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func newFlagSet(providers *listFlag) *flag.FlagSet {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.Int("write-budget", 0, "")
+	fs.Duration("probe-timeout", 3*time.Second, "")
+	fs.Bool("takeover-protection", false, "")
+	fs.String("watch-namespaces", "", "")
+	fs.String("parking-target", "", "")
+	fs.Var(providers, "provider", "")
+	return fs
+}
+
+func TestLoad(t *testing.T) {
+	path := writeConfig(t, `
+write-budget: 10
+takeover-protection: true
+parking-target: parked.example.com
+provider:
+- aws
+- inmemory
+probe:
+  timeout: 10s
+`)
+	t.Setenv(EnvName("parking-target"), "sorry.example.com")
+	t.Setenv("WATCH_NAMESPACES", "dns")
+
+	providers := listFlag{}
+	fs := newFlagSet(&providers)
+	if err := fs.Parse([]string{"--write-budget", "5"}); err != nil {
+		t.Fatal(err)
+	}
+	options, err := Load(fs, path, map[string]string{"watch-namespaces": "WATCH_NAMESPACES"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := map[string]Option{
+		"parking-target":      {Name: "parking-target", Value: "sorry.example.com", Source: SourceEnv},
+		"probe-timeout":       {Name: "probe-timeout", Value: "10s", Source: SourceFile},
+		"provider":            {Name: "provider", Value: "aws,inmemory", Source: SourceFile},
+		"takeover-protection": {Name: "takeover-protection", Value: "true", Source: SourceFile},
+		"watch-namespaces":    {Name: "watch-namespaces", Value: "dns", Source: SourceEnv},
+		"write-budget":        {Name: "write-budget", Value: "5", Source: SourceFlag},
+	}
+	if len(options) != len(want) {
+		t.Fatalf("expected %d options, got %v", len(want), options)
+	}
+	for i, option := range options {
+		if i > 0 && options[i-1].Name > option.Name {
+			t.Errorf("options are not sorted: %v", options)
+		}
+		if option != want[option.Name] {
+			t.Errorf("expected %v, got %v", want[option.Name], option)
+		}
+	}
+}
+
+func TestLoadDefaults(t *testing.T) {
+	fs := newFlagSet(&listFlag{})
+	if err := fs.Parse(nil); err != nil {
+		t.Fatal(err)
+	}
+	options, err := Load(fs, "", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, option := range options {
+		if option.Source != SourceDefault {
+			t.Errorf("expected %s to be the default, got %v", option.Name, option)
+		}
+	}
+}
+
+func TestLoadInvalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		env     map[string]string
+		wantErr string
+	}{
+		{
+			name:    "unknown option",
+			content: "write-budgets: 10",
+			wantErr: "write-budgets: unknown option",
+		},
+		{
+			name:    "unknown option in section",
+			content: "probe:\n  timeouts: 10s",
+			wantErr: "probe-timeouts: unknown option",
+		},
+		{
+			name:    "invalid value",
+			content: "probe-timeout: ten",
+			wantErr: "probe-timeout: parse error",
+		},
+		{
+			name:    "missing value",
+			content: "parking-target:",
+			wantErr: "parking-target: must have a value",
+		},
+		{
+			name:    "not yaml",
+			content: "write-budget: [",
+			wantErr: "parsing config file",
+		},
+		{
+			name:    "invalid environment variable",
+			env:     map[string]string{EnvName("write-budget"): "many"},
+			wantErr: "environment variable DNS_OPERATOR_WRITE_BUDGET",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for name, value := range tt.env {
+				t.Setenv(name, value)
+			}
+			path := ""
+			if tt.content != "" {
+				path = writeConfig(t, tt.content)
+			}
+			fs := newFlagSet(&listFlag{})
+			fs.SetOutput(&strings.Builder{})
+			if err := fs.Parse(nil); err != nil {
+				t.Fatal(err)
+			}
+			_, err := Load(fs, path, nil)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestLoadMissingFile(t *testing.T) {
+	fs := newFlagSet(&listFlag{})
+	if err := fs.Parse(nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(fs, filepath.Join(t.TempDir(), "missing.yaml"), nil); err == nil {
+		t.Error("expected an error for a missing config file")
+	}
+}
@@ -13,6 +13,12 @@ func ToBase36Hash(s string) string {
 	return strings.ToLower(base36.EncodeBytes(hash[:]))
 }
 
+// ToBase36SHA256Hash is ToBase36Hash with a SHA-256 hash, for hashes that are longer than ToBase36Hash can be
+func ToBase36SHA256Hash(s string) string {
+	hash := sha256.Sum256([]byte(s))
+	return strings.ToLower(base36.EncodeBytes(hash[:]))
+}
+
 func ToBase36HashLen(s string, l int) string {
 	return ToBase36Hash(s)[:l]
 }
@@ -23,6 +23,14 @@ func TestToBase36Hash(t *testing.T) {
 	}
 }
 
+func TestToBase36SHA256Hash(t *testing.T) {
+	input := "9c8f876c-4ddc-44a3-9842-460f97e6c037"
+	want := "1zfz0ovlm93zt7ibifc48rrydd1m57fislllj98tbrdpi8yj3k"
+	if got := ToBase36SHA256Hash(input); got != want {
+		t.Errorf("ToBase36SHA256Hash() = %v, want %v", got, want)
+	}
+}
+
 func TestToBase36HashLen(t *testing.T) {
 	tests := []struct {
 		name   string
@@ -0,0 +1,111 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	externaldnsendpoint "sigs.k8s.io/external-dns/endpoint"
+
+	"github.com/kuadrant/dns-operator/api/v1alpha1"
+)
+
+// acmeChallengePrefix is the label ACME DNS-01 challenge TXT records are published under
+const acmeChallengePrefix = "_acme-challenge."
+
+// isACMEChallengeRecord returns true if all the endpoints of the record are ACME DNS-01 challenge TXT records.
+func isACMEChallengeRecord(dnsRecord *v1alpha1.DNSRecord) bool {
+	if len(dnsRecord.Spec.Endpoints) == 0 {
+		return false
+	}
+	for _, ep := range dnsRecord.Spec.Endpoints {
+		if ep.RecordType != externaldnsendpoint.RecordTypeTXT || !strings.HasPrefix(strings.ToLower(ep.DNSName), acmeChallengePrefix) {
+			return false
+		}
+	}
+	return true
+}
+
+// parseACMEChallengeTTLs returns the challenge TTL of each zone domain name in the given comma separated list of
+// zone=duration pairs.
+func parseACMEChallengeTTLs(value string) (map[string]time.Duration, error) {
+	ttls := map[string]time.Duration{}
+	for _, pair := range strings.Split(value, ",") {
+		if pair = strings.TrimSpace(pair); pair == "" {
+			continue
+		}
+		zone, duration, ok := strings.Cut(pair, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid ACME challenge TTL %q, must be zone=duration", pair)
+		}
+		ttl, err := time.ParseDuration(strings.TrimSpace(duration))
+		if err != nil || ttl <= 0 {
+			return nil, fmt.Errorf("invalid ACME challenge TTL %q for zone %s, must be a positive duration", duration, zone)
+		}
+		ttls[strings.ToLower(strings.TrimSuffix(strings.TrimSpace(zone), "."))] = ttl
+	}
+	return ttls, nil
+}
+
+// specUpdatedAt returns the time the spec of the record was last updated, the time the operator first observed its
+// generation or its creation time if it was never updated. The time is kept in the status, as the managed fields of
+// the record can't be relied on.
+func specUpdatedAt(dnsRecord *v1alpha1.DNSRecord, now time.Time) time.Time {
+	state := dnsRecord.Status.ACMEChallenge
+	if state == nil {
+		state = &v1alpha1.ACMEChallengeStatus{}
+		dnsRecord.Status.ACMEChallenge = state
+	}
+	if state.Generation != dnsRecord.Generation || state.UpdatedAt.IsZero() {
+		state.Generation = dnsRecord.Generation
+		state.UpdatedAt = metav1.NewTime(now)
+		if dnsRecord.Generation <= 1 {
+			state.UpdatedAt = dnsRecord.CreationTimestamp
+		}
+	}
+	return state.UpdatedAt.Time
+}
+
+// acmeChallengeExpired returns true if the record only publishes ACME DNS-01 challenge TXT endpoints to a zone that
+// opted in to challenge garbage collection in the provider secret, and has not been updated for the challenge TTL of
+// the zone. Challenges are only valid while an issuance is in progress, records left behind by failed issuances would
+// otherwise accumulate in the zone. The error reading the challenge TTL of the zone, if any, is kept in the status.
+func (r *DNSRecordReconciler) acmeChallengeExpired(ctx context.Context, dnsRecord *v1alpha1.DNSRecord, now time.Time) (bool, error) {
+	if !isACMEChallengeRecord(dnsRecord) {
+		dnsRecord.Status.ACMEChallenge = nil
+		return false, nil
+	}
+	if !dnsRecord.HasDNSZoneAssigned() {
+		return false, nil
+	}
+	updatedAt := specUpdatedAt(dnsRecord, now)
+	expired, err := r.acmeChallengeTTLExceeded(ctx, dnsRecord, now.Sub(updatedAt))
+	dnsRecord.Status.ACMEChallenge.TTLError = ""
+	if err != nil {
+		dnsRecord.Status.ACMEChallenge.TTLError = err.Error()
+	}
+	return expired, err
+}
+
+// acmeChallengeTTLExceeded returns true if the challenge TTL of the zone of the record is set and less than the given age.
+func (r *DNSRecordReconciler) acmeChallengeTTLExceeded(ctx context.Context, dnsRecord *v1alpha1.DNSRecord, age time.Duration) (bool, error) {
+	secret := &v1.Secret{}
+	if err := r.Get(ctx, client.ObjectKey{Namespace: dnsRecord.Namespace, Name: dnsRecord.Spec.ProviderRef.Name}, secret); err != nil {
+		// a missing secret is reported when the provider is loaded
+		return false, client.IgnoreNotFound(err)
+	}
+	value, ok := secret.Data[v1alpha1.ACMEChallengeTTLKey]
+	if !ok {
+		return false, nil
+	}
+	ttls, err := parseACMEChallengeTTLs(string(value))
+	if err != nil {
+		return false, err
+	}
+	ttl, ok := ttls[strings.ToLower(strings.TrimSuffix(dnsRecord.Status.ZoneDomainName, "."))]
+	return ok && age > ttl, nil
+}
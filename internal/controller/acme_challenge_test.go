@@ -0,0 +1,140 @@
+//go:build integration
+
+package controller
+
+import (
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	toolsrecord "k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	externaldnsendpoint "sigs.k8s.io/external-dns/endpoint"
+
+	"github.com/kuadrant/dns-operator/api/v1alpha1"
+	"github.com/kuadrant/dns-operator/internal/provider"
+)
+
+var _ = Describe("ACME challenge garbage collection", func() {
+	var (
+		now    time.Time
+		record *v1alpha1.DNSRecord
+		secret *v1.Secret
+	)
+
+	BeforeEach(func() {
+		now = time.Now()
+		record = &v1alpha1.DNSRecord{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "challenge",
+				Namespace:         "default",
+				CreationTimestamp: metav1.NewTime(now.Add(-2 * time.Hour)),
+			},
+			Spec: v1alpha1.DNSRecordSpec{
+				RootHost:    "foo.example.com",
+				ProviderRef: v1alpha1.ProviderRef{Name: "dns-provider-creds"},
+				Endpoints: []*externaldnsendpoint.Endpoint{
+					externaldnsendpoint.NewEndpoint("_acme-challenge.foo.example.com", externaldnsendpoint.RecordTypeTXT, "token"),
+				},
+			},
+			Status: v1alpha1.DNSRecordStatus{ZoneID: "example.com", ZoneDomainName: "example.com"},
+		}
+		secret = &v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "dns-provider-creds", Namespace: "default"},
+			Data:       map[string][]byte{v1alpha1.ACMEChallengeTTLKey: []byte("example.com=1h, other.com=30m")},
+		}
+	})
+
+	It("should only match records of challenge TXT endpoints", func() {
+		Expect(isACMEChallengeRecord(record)).To(BeTrue())
+		record.Spec.Endpoints = append(record.Spec.Endpoints,
+			externaldnsendpoint.NewEndpoint("foo.example.com", externaldnsendpoint.RecordTypeTXT, "token"))
+		Expect(isACMEChallengeRecord(record)).To(BeFalse())
+		Expect(isACMEChallengeRecord(&v1alpha1.DNSRecord{})).To(BeFalse())
+	})
+
+	It("should parse the challenge TTL of each zone", func() {
+		ttls, err := parseACMEChallengeTTLs("Example.com.=1h, other.com=30m,")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ttls).To(Equal(map[string]time.Duration{"example.com": time.Hour, "other.com": 30 * time.Minute}))
+
+		_, err = parseACMEChallengeTTLs("example.com")
+		Expect(err).To(MatchError(ContainSubstring("must be zone=duration")))
+		_, err = parseACMEChallengeTTLs("example.com=-1h")
+		Expect(err).To(MatchError(ContainSubstring("must be a positive duration")))
+	})
+
+	It("should expire challenge records not updated within the TTL of their zone", func() {
+		r := &DNSRecordReconciler{Client: fake.NewClientBuilder().WithObjects(secret).Build()}
+		record.Generation = 1
+		Expect(r.acmeChallengeExpired(ctx, record, now)).To(BeTrue())
+		Expect(record.Status.ACMEChallenge.UpdatedAt).To(Equal(record.CreationTimestamp))
+
+		// updated within the TTL
+		record.Generation = 2
+		Expect(r.acmeChallengeExpired(ctx, record, now)).To(BeFalse())
+		Expect(record.Status.ACMEChallenge.Generation).To(BeEquivalentTo(2))
+		Expect(record.Status.ACMEChallenge.UpdatedAt.Time).To(BeTemporally("==", now))
+
+		// the time of the update is kept until the next update
+		Expect(r.acmeChallengeExpired(ctx, record, now.Add(30*time.Minute))).To(BeFalse())
+		Expect(r.acmeChallengeExpired(ctx, record, now.Add(2*time.Hour))).To(BeTrue())
+
+		// zones are opted in by the provider secret
+		record.Status.ZoneDomainName = "example.org"
+		Expect(r.acmeChallengeExpired(ctx, record, now.Add(2*time.Hour))).To(BeFalse())
+
+		// records that stop being challenge records are not tracked
+		record.Spec.Endpoints = append(record.Spec.Endpoints,
+			externaldnsendpoint.NewEndpoint("foo.example.com", externaldnsendpoint.RecordTypeTXT, "token"))
+		Expect(r.acmeChallengeExpired(ctx, record, now)).To(BeFalse())
+		Expect(record.Status.ACMEChallenge).To(BeNil())
+	})
+
+	It("should only record an event when the error reading the TTL changes", func() {
+		secret.Type = v1alpha1.SecretTypeKuadrantInmemory
+		secret.Data[v1alpha1.InmemInitZonesKey] = []byte("example.com")
+		secret.Data[v1alpha1.ACMEChallengeTTLKey] = []byte("example.com")
+		record.CreationTimestamp = metav1.Time{}
+		record.Finalizers = []string{DNSRecordFinalizer}
+		c := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(record, secret).WithStatusSubresource(record).Build()
+		providerFactory, err := provider.NewFactory(c, []string{"inmemory"})
+		Expect(err).NotTo(HaveOccurred())
+		recorder := toolsrecord.NewFakeRecorder(10)
+		r := &DNSRecordReconciler{Client: c, Scheme: scheme.Scheme, ProviderFactory: providerFactory}
+		r.setup(recorder, RequeueDuration, ValidityDuration, DefaultValidationDuration, true, true)
+		req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(record)}
+
+		for range 2 {
+			_, err = r.Reconcile(ctx, req)
+			Expect(err).NotTo(HaveOccurred())
+		}
+		Expect(c.Get(ctx, req.NamespacedName, record)).To(Succeed())
+		Expect(record.Status.ACMEChallenge).NotTo(BeNil())
+		Expect(record.Status.ACMEChallenge.TTLError).To(ContainSubstring("must be zone=duration"))
+
+		var warnings []string
+		for len(recorder.Events) > 0 {
+			if event := <-recorder.Events; strings.Contains(event, "ACMEChallengeTTLError") {
+				warnings = append(warnings, event)
+			}
+		}
+		Expect(warnings).To(HaveLen(1))
+	})
+
+	It("should not expire records without challenge TTLs", func() {
+		delete(secret.Data, v1alpha1.ACMEChallengeTTLKey)
+		r := &DNSRecordReconciler{Client: fake.NewClientBuilder().WithObjects(secret).Build()}
+		Expect(r.acmeChallengeExpired(ctx, record, now)).To(BeFalse())
+
+		r = &DNSRecordReconciler{Client: fake.NewClientBuilder().Build()}
+		Expect(r.acmeChallengeExpired(ctx, record, now)).To(BeFalse())
+	})
+})
@@ -0,0 +1,74 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/kuadrant/dns-operator/api/v1alpha1"
+)
+
+// canaryCheckTimeout bounds reading the canary record and resolving its endpoints in a single check
+const canaryCheckTimeout = 5 * time.Second
+
+// CanaryCheck is a readiness check that passes once the canary DNSRecord is ready and its published endpoints resolve
+// through the resolver, showing that records are published end to end. Once it has passed it keeps passing, so the
+// operator doesn't become unready on a transient resolution failure after publishing was shown to work.
+type CanaryCheck struct {
+	// Client reads the canary record, it must not depend on the cache of the manager as readiness is checked
+	// before the cache is synced
+	Client client.Reader
+	// Record is the namespace and name of the canary record
+	Record types.NamespacedName
+	// Nameserver is the address of the nameserver the endpoints are resolved through, e.g. 10.0.0.10 or 10.0.0.10:5353.
+	// The resolver of the system is used if empty
+	Nameserver string
+
+	passed atomic.Bool
+}
+
+// Check implements healthz.Checker.
+func (c *CanaryCheck) Check(req *http.Request) error {
+	if c.passed.Load() {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(req.Context(), canaryCheckTimeout)
+	defer cancel()
+
+	if err := c.verify(ctx); err != nil {
+		log.FromContext(ctx).V(1).Info("canary DNSRecord is not resolvable", "dnsRecord", c.Record.String(), "error", err.Error())
+		return err
+	}
+	log.FromContext(ctx).Info("canary DNSRecord is published and resolvable", "dnsRecord", c.Record.String())
+	c.passed.Store(true)
+	return nil
+}
+
+// verify returns an error unless the canary record is ready and its published endpoints resolve.
+func (c *CanaryCheck) verify(ctx context.Context) error {
+	record := &v1alpha1.DNSRecord{}
+	if err := c.Client.Get(ctx, c.Record, record); err != nil {
+		return fmt.Errorf("reading canary DNSRecord %s: %w", c.Record, err)
+	}
+	if record.Generation != record.Status.ObservedGeneration ||
+		!meta.IsStatusConditionTrue(record.Status.Conditions, string(v1alpha1.ConditionTypeReady)) {
+		return fmt.Errorf("canary DNSRecord %s is not ready", c.Record)
+	}
+	if len(record.Status.Endpoints) == 0 {
+		return fmt.Errorf("canary DNSRecord %s has no published endpoints", c.Record)
+	}
+
+	resolver, via := net.DefaultResolver, "the system resolver"
+	if c.Nameserver != "" {
+		resolver, via = nameserverResolver(c.Nameserver), c.Nameserver
+	}
+	return verifyResolution(ctx, resolver, via, record.Status.Endpoints)
+}
@@ -0,0 +1,102 @@
+//go:build integration
+
+package controller
+
+import (
+	"context"
+	"net/http"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	externaldnsendpoint "sigs.k8s.io/external-dns/endpoint"
+	externaldnsplan "sigs.k8s.io/external-dns/plan"
+
+	"github.com/kuadrant/dns-operator/api/v1alpha1"
+	"github.com/kuadrant/dns-operator/internal/provider"
+	inmemoryprovider "github.com/kuadrant/dns-operator/internal/provider/inmemory"
+)
+
+var _ = Describe("Canary check", func() {
+	var server *inmemoryprovider.Server
+	var record *v1alpha1.DNSRecord
+	var req *http.Request
+
+	BeforeEach(func() {
+		p, err := inmemoryprovider.NewProviderFromSecret(ctx, &v1.Secret{
+			Data: map[string][]byte{v1alpha1.InmemInitZonesKey: []byte("canary.example.com")},
+		}, provider.Config{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(p.ApplyChanges(ctx, &externaldnsplan.Changes{Create: []*externaldnsendpoint.Endpoint{
+			externaldnsendpoint.NewEndpointWithTTL("www.canary.example.com", externaldnsendpoint.RecordTypeA, 60, "192.0.2.1"),
+		}})).To(Succeed())
+		DeferCleanup(func() {
+			_ = p.ApplyChanges(ctx, &externaldnsplan.Changes{Delete: []*externaldnsendpoint.Endpoint{
+				externaldnsendpoint.NewEndpointWithTTL("www.canary.example.com", externaldnsendpoint.RecordTypeA, 60, "192.0.2.1"),
+			}})
+		})
+
+		s, err := inmemoryprovider.NewServer("127.0.0.1:0")
+		Expect(err).NotTo(HaveOccurred())
+		serverCtx, cancel := context.WithCancel(ctx)
+		DeferCleanup(cancel)
+		go func() { _ = s.Start(serverCtx) }()
+		server = s
+
+		record = &v1alpha1.DNSRecord{
+			ObjectMeta: metav1.ObjectMeta{Name: "canary", Namespace: "default", Generation: 1},
+			Spec: v1alpha1.DNSRecordSpec{
+				RootHost:    "www.canary.example.com",
+				ProviderRef: v1alpha1.ProviderRef{Name: "inmemory"},
+			},
+			Status: v1alpha1.DNSRecordStatus{
+				ObservedGeneration: 1,
+				Conditions: []metav1.Condition{{
+					Type:   string(v1alpha1.ConditionTypeReady),
+					Status: metav1.ConditionTrue,
+					Reason: string(v1alpha1.ConditionReasonProviderSuccess),
+				}},
+				Endpoints: []*externaldnsendpoint.Endpoint{
+					externaldnsendpoint.NewEndpointWithTTL("www.canary.example.com", externaldnsendpoint.RecordTypeA, 60, "192.0.2.1"),
+				},
+			},
+		}
+		req, err = http.NewRequestWithContext(ctx, http.MethodGet, "/readyz/canary", nil)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	newCheck := func(objs ...client.Object) *CanaryCheck {
+		return &CanaryCheck{
+			Client:     fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(objs...).Build(),
+			Record:     client.ObjectKeyFromObject(record),
+			Nameserver: server.Addr().String(),
+		}
+	}
+
+	It("should fail while the canary record doesn't exist", func() {
+		Expect(newCheck().Check(req)).To(MatchError(ContainSubstring("reading canary DNSRecord default/canary")))
+	})
+
+	It("should fail while the canary record is not ready", func() {
+		record.Status.ObservedGeneration = 0
+		Expect(newCheck(record).Check(req)).To(MatchError("canary DNSRecord default/canary is not ready"))
+	})
+
+	It("should fail while the published endpoints don't resolve", func() {
+		record.Status.Endpoints[0].Targets = externaldnsendpoint.Targets{"192.0.2.2"}
+		Expect(newCheck(record).Check(req)).To(MatchError(ContainSubstring("does not resolve to 192.0.2.2")))
+	})
+
+	It("should pass once the published endpoints resolve and keep passing", func() {
+		check := newCheck(record)
+		Expect(check.Check(req)).To(Succeed())
+
+		Expect(check.Client.(client.Client).Delete(ctx, record)).To(Succeed())
+		Expect(check.Check(req)).To(Succeed())
+	})
+})
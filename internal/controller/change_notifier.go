@@ -0,0 +1,158 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/kuadrant/dns-operator/api/v1alpha1"
+	externaldnsplan "github.com/kuadrant/dns-operator/internal/external-dns/plan"
+	"github.com/kuadrant/dns-operator/internal/metrics"
+)
+
+const (
+	// ChangeNotificationType is the type of the CloudEvents published for the changes applied to a zone for a record.
+	ChangeNotificationType = "io.kuadrant.dns.changes.applied"
+	// ChangeNotificationSource is the source of the CloudEvents published for the changes applied to zones.
+	ChangeNotificationSource = "kuadrant.io/dns-operator"
+
+	// changeNotificationQueueSize is the number of notifications waiting to be published before new ones are dropped.
+	changeNotificationQueueSize = 1000
+	// changeNotificationTimeout is how long publishing a notification can take before it fails.
+	changeNotificationTimeout = 10 * time.Second
+
+	cloudEventsContentType = "application/cloudevents+json"
+)
+
+// ChangeNotification is the data of the CloudEvent published for the changes applied to a zone for a record.
+type ChangeNotification struct {
+	Zone    string           `json:"zone"`
+	ZoneID  string           `json:"zoneID"`
+	Record  string           `json:"record"`
+	OwnerID string           `json:"ownerID,omitempty"`
+	Changes []NotifiedChange `json:"changes"`
+}
+
+// NotifiedChange is a change applied to a zone, and why it was applied.
+type NotifiedChange struct {
+	Action        string   `json:"action"`
+	DNSName       string   `json:"dnsName"`
+	RecordType    string   `json:"recordType"`
+	SetIdentifier string   `json:"setIdentifier,omitempty"`
+	Reasons       []string `json:"reasons"`
+}
+
+// cloudEvent is a CloudEvent in the structured JSON format.
+type cloudEvent struct {
+	SpecVersion     string             `json:"specversion"`
+	ID              string             `json:"id"`
+	Source          string             `json:"source"`
+	Type            string             `json:"type"`
+	Subject         string             `json:"subject"`
+	Time            time.Time          `json:"time"`
+	DataContentType string             `json:"datacontenttype"`
+	Data            ChangeNotification `json:"data"`
+}
+
+// changeNotifier publishes the changes applied to zones as CloudEvents to an HTTP sink, e.g. a Knative broker or a
+// NATS or Kafka bridge, so other systems can react to them. Notifications are queued and published in the background,
+// a reconcile never waits on the sink. Notifications that fail to be published are not retried.
+type changeNotifier struct {
+	url    string
+	client *http.Client
+	queue  chan cloudEvent
+}
+
+func newChangeNotifier(url string) *changeNotifier {
+	return &changeNotifier{
+		url:    url,
+		client: &http.Client{Timeout: changeNotificationTimeout},
+		queue:  make(chan cloudEvent, changeNotificationQueueSize),
+	}
+}
+
+// notify queues the notification of the changes applied for the record, or drops it if the queue is full.
+func (n *changeNotifier) notify(ctx context.Context, dnsRecord *v1alpha1.DNSRecord, explanations []externaldnsplan.ChangeExplanation) {
+	notification := ChangeNotification{
+		Zone:    dnsRecord.Status.ZoneDomainName,
+		ZoneID:  dnsRecord.Status.ZoneID,
+		Record:  fmt.Sprintf("%s/%s", dnsRecord.Namespace, dnsRecord.Name),
+		OwnerID: dnsRecord.Status.OwnerID,
+		Changes: make([]NotifiedChange, 0, len(explanations)),
+	}
+	for _, explanation := range explanations {
+		change := NotifiedChange{
+			Action:        string(explanation.Action),
+			DNSName:       explanation.DNSName,
+			RecordType:    explanation.RecordType,
+			SetIdentifier: explanation.SetIdentifier,
+		}
+		for _, reason := range explanation.Reasons {
+			change.Reasons = append(change.Reasons, string(reason))
+		}
+		notification.Changes = append(notification.Changes, change)
+	}
+
+	event := cloudEvent{
+		SpecVersion:     "1.0",
+		ID:              uuid.NewString(),
+		Source:          ChangeNotificationSource,
+		Type:            ChangeNotificationType,
+		Subject:         notification.Zone,
+		Time:            time.Now().UTC(),
+		DataContentType: "application/json",
+		Data:            notification,
+	}
+	select {
+	case n.queue <- event:
+	default:
+		log.FromContext(ctx).Info("dropping change notification, the queue is full", "zone", notification.Zone)
+		metrics.ChangeNotifications.WithLabelValues("dropped").Inc()
+	}
+}
+
+// Start publishes the queued notifications until the context is done.
+func (n *changeNotifier) Start(ctx context.Context) error {
+	logger := log.FromContext(ctx).WithName("change-notifier")
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event := <-n.queue:
+			if err := n.publish(ctx, event); err != nil {
+				logger.Error(err, "failed to publish change notification", "id", event.ID, "zone", event.Subject)
+				metrics.ChangeNotifications.WithLabelValues("failed").Inc()
+				continue
+			}
+			metrics.ChangeNotifications.WithLabelValues("published").Inc()
+		}
+	}
+}
+
+func (n *changeNotifier) publish(ctx context.Context, event cloudEvent) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", cloudEventsContentType)
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected response status %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+//go:build integration
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kuadrant/dns-operator/api/v1alpha1"
+	externaldnsplan "github.com/kuadrant/dns-operator/internal/external-dns/plan"
+)
+
+var _ = Describe("Change notifier", func() {
+	var (
+		server   *httptest.Server
+		received chan cloudEvent
+		status   int
+		record   *v1alpha1.DNSRecord
+	)
+
+	BeforeEach(func() {
+		received = make(chan cloudEvent, 10)
+		status = http.StatusAccepted
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer GinkgoRecover()
+			Expect(r.Header.Get("Content-Type")).To(Equal(cloudEventsContentType))
+			var event cloudEvent
+			Expect(json.NewDecoder(r.Body).Decode(&event)).To(Succeed())
+			received <- event
+			w.WriteHeader(status)
+		}))
+		DeferCleanup(server.Close)
+
+		record = &v1alpha1.DNSRecord{ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "dnstest"}}
+		record.Status.ZoneDomainName = "example.com"
+		record.Status.ZoneID = "zone-1"
+		record.Status.OwnerID = "owner1"
+	})
+
+	It("should publish the applied changes as a CloudEvent", func(ctx SpecContext) {
+		notifier := newChangeNotifier(server.URL)
+		notifierCtx, cancel := context.WithCancel(ctx)
+		DeferCleanup(cancel)
+		go func() {
+			_ = notifier.Start(notifierCtx)
+		}()
+
+		notifier.notify(ctx, record, []externaldnsplan.ChangeExplanation{
+			{Action: externaldnsplan.ChangeActionCreate, DNSName: "foo.example.com", RecordType: "A",
+				Reasons: []externaldnsplan.ChangeReason{externaldnsplan.ChangeReasonNewEndpoint}},
+			{Action: externaldnsplan.ChangeActionUpdate, DNSName: "bar.example.com", RecordType: "CNAME", SetIdentifier: "eu",
+				Reasons: []externaldnsplan.ChangeReason{externaldnsplan.ChangeReasonTargetChange, externaldnsplan.ChangeReasonTTLChange}},
+		})
+
+		var event cloudEvent
+		Eventually(received).Should(Receive(&event))
+		Expect(event.SpecVersion).To(Equal("1.0"))
+		Expect(event.ID).NotTo(BeEmpty())
+		Expect(event.Type).To(Equal(ChangeNotificationType))
+		Expect(event.Source).To(Equal(ChangeNotificationSource))
+		Expect(event.Subject).To(Equal("example.com"))
+		Expect(event.Data).To(Equal(ChangeNotification{
+			Zone:    "example.com",
+			ZoneID:  "zone-1",
+			Record:  "dnstest/foo",
+			OwnerID: "owner1",
+			Changes: []NotifiedChange{
+				{Action: "Create", DNSName: "foo.example.com", RecordType: "A", Reasons: []string{"NewEndpoint"}},
+				{Action: "Update", DNSName: "bar.example.com", RecordType: "CNAME", SetIdentifier: "eu",
+					Reasons: []string{"TargetChange", "TTLChange"}},
+			},
+		}))
+	})
+
+	It("should fail to publish when the sink rejects the event", func(ctx SpecContext) {
+		status = http.StatusInternalServerError
+		notifier := newChangeNotifier(server.URL)
+		Expect(notifier.publish(ctx, cloudEvent{ID: "1"})).To(MatchError(ContainSubstring("500")))
+	})
+
+	It("should drop notifications when the queue is full", func(ctx SpecContext) {
+		notifier := newChangeNotifier(server.URL)
+		for range changeNotificationQueueSize + 1 {
+			notifier.notify(ctx, record, nil)
+		}
+		Expect(notifier.queue).To(HaveLen(changeNotificationQueueSize))
+	})
+})
@@ -0,0 +1,130 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"time"
+
+	externaldnsendpoint "sigs.k8s.io/external-dns/endpoint"
+
+	"github.com/kuadrant/dns-operator/api/v1alpha1"
+)
+
+// cnameFlattenTimeout is the time allowed for resolving the target of a flattened CNAME endpoint.
+const cnameFlattenTimeout = 5 * time.Second
+
+// ipResolver resolves a hostname to its addresses.
+type ipResolver interface {
+	LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error)
+}
+
+// flattenResolver resolves the targets of flattened CNAME endpoints.
+var flattenResolver ipResolver = net.DefaultResolver
+
+// isFlattened returns true if the endpoint is a CNAME endpoint with the flatten provider specific property.
+func isFlattened(ep *externaldnsendpoint.Endpoint) bool {
+	if ep.RecordType != externaldnsendpoint.RecordTypeCNAME {
+		return false
+	}
+	value, ok := ep.GetProviderSpecificProperty(v1alpha1.ProviderSpecificFlatten)
+	return ok && value == "true"
+}
+
+// flattenCNAMEEndpoints replaces the flattened CNAME endpoints of the record with A and AAAA endpoints for the
+// addresses their target resolves to, for providers and zones that do not allow a CNAME record at the name, e.g. the
+// zone apex. The resolved addresses are reported in the status, and the record is requeued within the TTL of the
+// endpoints so changes to the addresses of the target are published. As with gateway endpoints the spec of the record
+// is never updated on the cluster after this point.
+func flattenCNAMEEndpoints(ctx context.Context, dnsRecord *v1alpha1.DNSRecord) error {
+	var endpoints []*externaldnsendpoint.Endpoint
+	var flattened []v1alpha1.FlattenedEndpoint
+	for _, ep := range dnsRecord.Spec.Endpoints {
+		if !isFlattened(ep) {
+			endpoints = append(endpoints, ep)
+			continue
+		}
+		if len(ep.Targets) != 1 {
+			return fmt.Errorf("flattened CNAME endpoint %s must have exactly one target", ep.DNSName)
+		}
+		target := ep.Targets[0]
+		ipv4, ipv6, err := resolveTarget(ctx, target)
+		if err != nil {
+			return fmt.Errorf("target %s of flattened CNAME endpoint %s could not be resolved: %w", target, ep.DNSName, err)
+		}
+		if len(ipv4) > 0 {
+			endpoints = append(endpoints, flattenedEndpoint(ep, externaldnsendpoint.RecordTypeA, ipv4))
+		}
+		if len(ipv6) > 0 {
+			endpoints = append(endpoints, flattenedEndpoint(ep, externaldnsendpoint.RecordTypeAAAA, ipv6))
+		}
+		flattened = append(flattened, v1alpha1.FlattenedEndpoint{
+			DNSName:       ep.DNSName,
+			SetIdentifier: ep.SetIdentifier,
+			Target:        target,
+			Addresses:     append(append([]string{}, ipv4...), ipv6...),
+		})
+	}
+	dnsRecord.Spec.Endpoints = endpoints
+	dnsRecord.Status.FlattenedEndpoints = flattened
+	return nil
+}
+
+// resolveTarget returns the sorted IPv4 and IPv6 addresses the target resolves to.
+func resolveTarget(ctx context.Context, target string) (ipv4, ipv6 []string, err error) {
+	ctx, cancel := context.WithTimeout(ctx, cnameFlattenTimeout)
+	defer cancel()
+	addrs, err := flattenResolver.LookupIPAddr(ctx, target)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, addr := range addrs {
+		if addr.IP.To4() != nil {
+			ipv4 = append(ipv4, addr.IP.String())
+		} else {
+			ipv6 = append(ipv6, addr.IP.String())
+		}
+	}
+	if len(ipv4) == 0 && len(ipv6) == 0 {
+		return nil, nil, fmt.Errorf("no addresses found")
+	}
+	sort.Strings(ipv4)
+	sort.Strings(ipv6)
+	return ipv4, ipv6, nil
+}
+
+// flattenedEndpoint returns an endpoint of the given type and targets in place of the flattened CNAME endpoint.
+func flattenedEndpoint(cname *externaldnsendpoint.Endpoint, recordType string, targets []string) *externaldnsendpoint.Endpoint {
+	ep := externaldnsendpoint.NewEndpointWithTTL(cname.DNSName, recordType, cname.RecordTTL, targets...).
+		WithSetIdentifier(cname.SetIdentifier)
+	for key, value := range cname.Labels {
+		ep.Labels[key] = value
+	}
+	for _, property := range cname.ProviderSpecific {
+		if property.Name != v1alpha1.ProviderSpecificFlatten {
+			ep.ProviderSpecific = append(ep.ProviderSpecific, property)
+		}
+	}
+	return ep
+}
+
+// flattenRequeueTime returns the lowest TTL of the given flattened CNAME endpoints, the addresses of their targets are
+// resolved again once the records published for them may have expired from caches. Returns 0 if there are no
+// flattened endpoints with a TTL.
+func flattenRequeueTime(endpoints []*externaldnsendpoint.Endpoint) time.Duration {
+	var requeue time.Duration
+	for _, ep := range endpoints {
+		if !isFlattened(ep) || !ep.RecordTTL.IsConfigured() {
+			continue
+		}
+		ttl := time.Duration(ep.RecordTTL) * time.Second
+		if requeue == 0 || ttl < requeue {
+			requeue = ttl
+		}
+	}
+	if requeue > 0 && requeue < defaultValidationRequeue {
+		return defaultValidationRequeue
+	}
+	return requeue
+}
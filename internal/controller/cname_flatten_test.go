@@ -0,0 +1,115 @@
+//go:build integration
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"net"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	externaldnsendpoint "sigs.k8s.io/external-dns/endpoint"
+
+	"github.com/kuadrant/dns-operator/api/v1alpha1"
+)
+
+type fakeIPResolver map[string][]string
+
+func (r fakeIPResolver) LookupIPAddr(_ context.Context, host string) ([]net.IPAddr, error) {
+	addresses, ok := r[host]
+	if !ok {
+		return nil, errors.New("no such host")
+	}
+	var addrs []net.IPAddr
+	for _, address := range addresses {
+		addrs = append(addrs, net.IPAddr{IP: net.ParseIP(address)})
+	}
+	return addrs, nil
+}
+
+var _ = Describe("CNAME flattening", func() {
+	var dnsRecord *v1alpha1.DNSRecord
+
+	flattenedCNAME := func(dnsName, target string, ttl externaldnsendpoint.TTL) *externaldnsendpoint.Endpoint {
+		return externaldnsendpoint.NewEndpointWithTTL(dnsName, externaldnsendpoint.RecordTypeCNAME, ttl, target).
+			WithProviderSpecific(v1alpha1.ProviderSpecificFlatten, "true").
+			WithProviderSpecific(v1alpha1.ProviderSpecificWeight, "100")
+	}
+
+	BeforeEach(func() {
+		resolver := flattenResolver
+		DeferCleanup(func() {
+			flattenResolver = resolver
+		})
+		flattenResolver = fakeIPResolver{
+			"lb.example.net":  {"2001:db8::1", "10.0.0.2", "10.0.0.1"},
+			"lb4.example.net": {"10.0.0.3"},
+		}
+		dnsRecord = &v1alpha1.DNSRecord{
+			ObjectMeta: metav1.ObjectMeta{Name: "flatten", Namespace: "default"},
+			Spec: v1alpha1.DNSRecordSpec{
+				RootHost: "example.com",
+				Endpoints: []*externaldnsendpoint.Endpoint{
+					flattenedCNAME("example.com", "lb.example.net", 60),
+					externaldnsendpoint.NewEndpoint("www.example.com", externaldnsendpoint.RecordTypeCNAME, "lb.example.net"),
+				},
+			},
+		}
+	})
+
+	It("should replace flattened CNAME endpoints with the addresses of their target", func() {
+		Expect(flattenCNAMEEndpoints(context.Background(), dnsRecord)).To(Succeed())
+		Expect(dnsRecord.Spec.Endpoints).To(HaveLen(3))
+		Expect(dnsRecord.Spec.Endpoints[2].DNSName).To(Equal("www.example.com"))
+		Expect(dnsRecord.Spec.Endpoints[2].RecordType).To(Equal(externaldnsendpoint.RecordTypeCNAME))
+
+		a, aaaa := dnsRecord.Spec.Endpoints[0], dnsRecord.Spec.Endpoints[1]
+		Expect(a.DNSName).To(Equal("example.com"))
+		Expect(a.RecordType).To(Equal(externaldnsendpoint.RecordTypeA))
+		Expect(a.Targets).To(ConsistOf("10.0.0.1", "10.0.0.2"))
+		Expect(a.RecordTTL).To(Equal(externaldnsendpoint.TTL(60)))
+		Expect(aaaa.RecordType).To(Equal(externaldnsendpoint.RecordTypeAAAA))
+		Expect(aaaa.Targets).To(ConsistOf("2001:db8::1"))
+		for _, ep := range []*externaldnsendpoint.Endpoint{a, aaaa} {
+			_, ok := ep.GetProviderSpecificProperty(v1alpha1.ProviderSpecificFlatten)
+			Expect(ok).To(BeFalse())
+			weight, _ := ep.GetProviderSpecificProperty(v1alpha1.ProviderSpecificWeight)
+			Expect(weight).To(Equal("100"))
+		}
+
+		Expect(dnsRecord.Status.FlattenedEndpoints).To(ConsistOf(v1alpha1.FlattenedEndpoint{
+			DNSName:   "example.com",
+			Target:    "lb.example.net",
+			Addresses: []string{"10.0.0.1", "10.0.0.2", "2001:db8::1"},
+		}))
+	})
+
+	It("should only publish the address types the target resolves to", func() {
+		dnsRecord.Spec.Endpoints = []*externaldnsendpoint.Endpoint{flattenedCNAME("example.com", "lb4.example.net", 60)}
+		Expect(flattenCNAMEEndpoints(context.Background(), dnsRecord)).To(Succeed())
+		Expect(dnsRecord.Spec.Endpoints).To(HaveLen(1))
+		Expect(dnsRecord.Spec.Endpoints[0].RecordType).To(Equal(externaldnsendpoint.RecordTypeA))
+	})
+
+	It("should fail when the target does not resolve", func() {
+		dnsRecord.Spec.Endpoints = []*externaldnsendpoint.Endpoint{flattenedCNAME("example.com", "missing.example.net", 60)}
+		Expect(flattenCNAMEEndpoints(context.Background(), dnsRecord)).To(MatchError(ContainSubstring("could not be resolved")))
+	})
+
+	It("should clear the flattened endpoints of a record without any", func() {
+		dnsRecord.Status.FlattenedEndpoints = []v1alpha1.FlattenedEndpoint{{DNSName: "example.com", Target: "lb.example.net"}}
+		dnsRecord.Spec.Endpoints = dnsRecord.Spec.Endpoints[1:]
+		Expect(flattenCNAMEEndpoints(context.Background(), dnsRecord)).To(Succeed())
+		Expect(dnsRecord.Status.FlattenedEndpoints).To(BeEmpty())
+	})
+
+	It("should requeue within the lowest TTL of the flattened endpoints", func() {
+		endpoints := append(dnsRecord.Spec.Endpoints, flattenedCNAME("api.example.com", "lb.example.net", 3600))
+		Expect(flattenRequeueTime(endpoints)).To(Equal(max(60*time.Second, defaultValidationRequeue)))
+		Expect(flattenRequeueTime(endpoints[1:2])).To(BeZero())
+	})
+})
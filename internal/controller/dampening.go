@@ -0,0 +1,114 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	externaldnsendpoint "sigs.k8s.io/external-dns/endpoint"
+
+	"github.com/kuadrant/dns-operator/api/v1alpha1"
+)
+
+// maxDampeningFactor is how many times the configured dampening window the window of a flapping record can grow to.
+const maxDampeningFactor = 16
+
+// endpointsHash returns a hash of the given endpoints, to tell whether the desired endpoints of a record changed
+// between reconciles without keeping them in the status.
+func endpointsHash(endpoints []*externaldnsendpoint.Endpoint) string {
+	data, _ := json.Marshal(endpoints)
+	h := fnv.New64a()
+	_, _ = h.Write(data)
+	return strconv.FormatUint(h.Sum64(), 16)
+}
+
+// dampenChanges returns how long changes to the desired endpoints of the record are held back for, 0 if the endpoints
+// can be published. Changes are published once the endpoints have not changed for the dampening window, so a source
+// flapping between values, e.g. the addresses of a load balancer, is not mirrored into the provider on every flap.
+// The window is doubled each time the endpoints change again while a change is held back, up to maxDampeningFactor
+// times the given window, and reset once they are stable. The first endpoints of a record are never held back.
+func dampenChanges(dnsRecord *v1alpha1.DNSRecord, window time.Duration, now time.Time) time.Duration {
+	current := endpointsHash(dnsRecord.Spec.Endpoints)
+	state := dnsRecord.Status.Dampening
+	if state == nil {
+		dnsRecord.Status.Dampening = &v1alpha1.DampeningStatus{
+			PublishedHash: current,
+			ChangedAt:     metav1.NewTime(now),
+			Window:        window.String(),
+		}
+		return 0
+	}
+
+	maxWindow := window * maxDampeningFactor
+	dampeningWindow, err := time.ParseDuration(state.Window)
+	if err != nil || dampeningWindow < window {
+		dampeningWindow = window
+	}
+	dampeningWindow = min(dampeningWindow, maxWindow)
+
+	if current == state.PublishedHash {
+		if state.PendingHash != "" {
+			// the endpoints flapped back before the held back change was published
+			state.PendingHash = ""
+			state.SuppressedChanges++
+			state.ChangedAt = metav1.NewTime(now)
+			dampeningWindow = min(2*dampeningWindow, maxWindow)
+		} else if now.Sub(state.ChangedAt.Time) >= dampeningWindow {
+			dampeningWindow = window
+			state.SuppressedChanges = 0
+		}
+		state.Window = dampeningWindow.String()
+		return 0
+	}
+
+	if current != state.PendingHash {
+		if state.PendingHash != "" {
+			// the held back change is replaced before it was published
+			state.SuppressedChanges++
+			dampeningWindow = min(2*dampeningWindow, maxWindow)
+		}
+		state.PendingHash = current
+		state.ChangedAt = metav1.NewTime(now)
+	}
+	state.Window = dampeningWindow.String()
+	if remaining := state.ChangedAt.Add(dampeningWindow).Sub(now); remaining > 0 {
+		return remaining
+	}
+
+	state.PublishedHash, state.PendingHash = current, ""
+	state.Window = window.String()
+	state.SuppressedChanges = 0
+	return 0
+}
+
+// holdChanges updates the status of a record with changes to its endpoints held back by dampening, without publishing
+// them, and requeues the record for when the changes are stable.
+func (r *DNSRecordReconciler) holdChanges(ctx context.Context, previous, dnsRecord *v1alpha1.DNSRecord, requeueIn time.Duration) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+	logger.Info("Holding back changes to the endpoints until they are stable", "requeueIn", requeueIn.String())
+
+	// nothing was published, the state of the last reconcile still applies
+	dnsRecord.Status.EndpointStatuses = previous.Status.EndpointStatuses
+	dnsRecord.Status.FlattenedEndpoints = previous.Status.FlattenedEndpoints
+	setDNSRecordCondition(dnsRecord, string(v1alpha1.ConditionTypeChangesDampened), metav1.ConditionTrue,
+		string(v1alpha1.ConditionReasonAwaitingStability), fmt.Sprintf("Changes to the endpoints are published once they are unchanged for %s, %d changes suppressed",
+			dnsRecord.Status.Dampening.Window, dnsRecord.Status.Dampening.SuppressedChanges))
+
+	if !equality.Semantic.DeepEqual(previous.Status, dnsRecord.Status) {
+		if err := r.Status().Update(ctx, dnsRecord); err != nil {
+			if apierrors.IsConflict(err) {
+				return ctrl.Result{Requeue: true}, nil
+			}
+			return ctrl.Result{}, err
+		}
+	}
+	return ctrl.Result{RequeueAfter: requeueIn}, nil
+}
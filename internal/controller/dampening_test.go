@@ -0,0 +1,88 @@
+//go:build integration
+
+package controller
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	externaldnsendpoint "sigs.k8s.io/external-dns/endpoint"
+
+	"github.com/kuadrant/dns-operator/api/v1alpha1"
+)
+
+var _ = Describe("Dampening", func() {
+	const window = time.Minute
+	var (
+		dnsRecord *v1alpha1.DNSRecord
+		start     time.Time
+	)
+
+	setTarget := func(target string) {
+		dnsRecord.Spec.Endpoints = []*externaldnsendpoint.Endpoint{
+			externaldnsendpoint.NewEndpoint("foo.example.com", externaldnsendpoint.RecordTypeA, target),
+		}
+	}
+
+	BeforeEach(func() {
+		dnsRecord = &v1alpha1.DNSRecord{}
+		start = time.Now()
+		setTarget("1.1.1.1")
+		Expect(dampenChanges(dnsRecord, window, start)).To(BeZero())
+		Expect(dnsRecord.Status.Dampening).ToNot(BeNil())
+	})
+
+	It("should publish the endpoints the record is first seen with", func() {
+		Expect(dnsRecord.Status.Dampening.PublishedHash).To(Equal(endpointsHash(dnsRecord.Spec.Endpoints)))
+		Expect(dnsRecord.Status.Dampening.PendingHash).To(BeEmpty())
+	})
+
+	It("should hold back a change until it is stable for the window", func() {
+		setTarget("2.2.2.2")
+		Expect(dampenChanges(dnsRecord, window, start.Add(time.Second))).To(Equal(window))
+		Expect(dampenChanges(dnsRecord, window, start.Add(31*time.Second))).To(Equal(30 * time.Second))
+		Expect(dampenChanges(dnsRecord, window, start.Add(61*time.Second))).To(BeZero())
+		Expect(dnsRecord.Status.Dampening.PublishedHash).To(Equal(endpointsHash(dnsRecord.Spec.Endpoints)))
+		Expect(dnsRecord.Status.Dampening.PendingHash).To(BeEmpty())
+	})
+
+	It("should double the window while the endpoints keep changing", func() {
+		setTarget("2.2.2.2")
+		Expect(dampenChanges(dnsRecord, window, start)).To(Equal(window))
+		setTarget("3.3.3.3")
+		Expect(dampenChanges(dnsRecord, window, start.Add(10*time.Second))).To(Equal(2 * window))
+		Expect(dnsRecord.Status.Dampening.SuppressedChanges).To(BeEquivalentTo(1))
+
+		// flapping back to the published endpoints suppresses the held back change
+		setTarget("1.1.1.1")
+		Expect(dampenChanges(dnsRecord, window, start.Add(20*time.Second))).To(BeZero())
+		Expect(dnsRecord.Status.Dampening.PendingHash).To(BeEmpty())
+		Expect(dnsRecord.Status.Dampening.SuppressedChanges).To(BeEquivalentTo(2))
+		Expect(dnsRecord.Status.Dampening.Window).To(Equal((4 * window).String()))
+
+		setTarget("2.2.2.2")
+		Expect(dampenChanges(dnsRecord, window, start.Add(30*time.Second))).To(Equal(4 * window))
+	})
+
+	It("should not grow the window beyond the maximum", func() {
+		for i := 0; i < 10; i++ {
+			setTarget("2.2.2." + string(rune('0'+i)))
+			dampenChanges(dnsRecord, window, start.Add(time.Duration(i)*time.Second))
+		}
+		Expect(dnsRecord.Status.Dampening.Window).To(Equal((maxDampeningFactor * window).String()))
+	})
+
+	It("should reset the window once the endpoints are stable", func() {
+		setTarget("2.2.2.2")
+		dampenChanges(dnsRecord, window, start)
+		setTarget("1.1.1.1")
+		dampenChanges(dnsRecord, window, start.Add(time.Second))
+		Expect(dnsRecord.Status.Dampening.Window).To(Equal((2 * window).String()))
+
+		Expect(dampenChanges(dnsRecord, window, start.Add(3*window))).To(BeZero())
+		Expect(dnsRecord.Status.Dampening.Window).To(Equal(window.String()))
+		Expect(dnsRecord.Status.Dampening.SuppressedChanges).To(BeZero())
+	})
+})
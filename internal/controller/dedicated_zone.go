@@ -0,0 +1,95 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	externaldnsendpoint "sigs.k8s.io/external-dns/endpoint"
+
+	"github.com/kuadrant/dns-operator/api/v1alpha1"
+	externaldnsregistry "github.com/kuadrant/dns-operator/internal/external-dns/registry"
+	"github.com/kuadrant/dns-operator/internal/provider"
+)
+
+// validateDedicatedZone returns an error if the zone of a record published with the dedicated zone annotation is not
+// exclusively owned by the record, i.e. another DNSRecord is assigned to the zone or the zone has registry TXT records
+// of other owners. Records of a dedicated zone are published without ownership, so publishing into a shared zone
+// would remove the records of others.
+func (r *DNSRecordReconciler) validateDedicatedZone(ctx context.Context, dnsRecord *v1alpha1.DNSRecord, dnsProvider provider.Provider) error {
+	records := &v1alpha1.DNSRecordList{}
+	if err := r.List(ctx, records, &client.ListOptions{}); err != nil {
+		return err
+	}
+	for _, record := range records.Items {
+		if record.UID != dnsRecord.UID && record.Status.ZoneID == dnsRecord.Status.ZoneID &&
+			record.Status.ZoneDomainName == dnsRecord.Status.ZoneDomainName {
+			return fmt.Errorf("zone %s is also assigned to DNSRecord %s/%s", dnsRecord.Status.ZoneDomainName, record.Namespace, record.Name)
+		}
+	}
+
+	zoneEndpoints, err := dnsProvider.Records(ctx)
+	if err != nil {
+		return err
+	}
+	if owners := registryOwners(zoneEndpoints, dnsRecord.Status.OwnerID); len(owners) > 0 {
+		return fmt.Errorf("zone %s has registry records of other owners: %s", dnsRecord.Status.ZoneDomainName, strings.Join(owners, ", "))
+	}
+	return nil
+}
+
+// registryLabels returns the labels of a registry TXT record, false if the endpoint is not a registry TXT record.
+func registryLabels(ep *externaldnsendpoint.Endpoint) (externaldnsendpoint.Labels, bool) {
+	if ep.RecordType != externaldnsendpoint.RecordTypeTXT || !strings.HasPrefix(ep.DNSName, externaldnsregistry.KuadrantTXTPrefix) {
+		return nil, false
+	}
+	for _, target := range ep.Targets {
+		if labels, err := externaldnsendpoint.NewLabelsFromString(target, nil); err == nil {
+			return labels, true
+		}
+	}
+	return nil, false
+}
+
+// registryOwners returns the sorted owners of the registry TXT records of the given endpoints, other than the given
+// owner.
+func registryOwners(endpoints []*externaldnsendpoint.Endpoint, ownerID string) []string {
+	var owners []string
+	for _, ep := range endpoints {
+		labels, ok := registryLabels(ep)
+		if owner := labels[externaldnsendpoint.OwnerLabelKey]; ok && owner != ownerID && !slices.Contains(owners, owner) {
+			owners = append(owners, owner)
+		}
+	}
+	slices.Sort(owners)
+	return owners
+}
+
+// splitRegistryRecords returns the registry TXT records of the given owner and the remaining endpoints.
+func splitRegistryRecords(endpoints []*externaldnsendpoint.Endpoint, ownerID string) ([]*externaldnsendpoint.Endpoint, []*externaldnsendpoint.Endpoint) {
+	var registryRecords, remaining []*externaldnsendpoint.Endpoint
+	for _, ep := range endpoints {
+		if labels, ok := registryLabels(ep); ok && labels[externaldnsendpoint.OwnerLabelKey] == ownerID {
+			registryRecords = append(registryRecords, ep)
+			continue
+		}
+		remaining = append(remaining, ep)
+	}
+	return registryRecords, remaining
+}
+
+// publishedEndpoints returns the given endpoints that have the name, type and set identifier of one of the published
+// endpoints.
+func publishedEndpoints(endpoints, published []*externaldnsendpoint.Endpoint) []*externaldnsendpoint.Endpoint {
+	filtered := []*externaldnsendpoint.Endpoint{}
+	for _, ep := range endpoints {
+		if slices.ContainsFunc(published, func(p *externaldnsendpoint.Endpoint) bool {
+			return p.Key() == ep.Key()
+		}) {
+			filtered = append(filtered, ep)
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,45 @@
+//go:build integration
+
+package controller
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	externaldnsendpoint "sigs.k8s.io/external-dns/endpoint"
+)
+
+var _ = Describe("Dedicated zone", func() {
+	var endpoints []*externaldnsendpoint.Endpoint
+
+	BeforeEach(func() {
+		endpoints = []*externaldnsendpoint.Endpoint{
+			externaldnsendpoint.NewEndpoint("foo.example.com", externaldnsendpoint.RecordTypeA, "1.1.1.1"),
+			externaldnsendpoint.NewEndpoint("kuadrant-a-foo.example.com", externaldnsendpoint.RecordTypeTXT,
+				"\"heritage=external-dns,external-dns/owner=owner1,external-dns/version=1\""),
+			externaldnsendpoint.NewEndpoint("kuadrant-a-bar.example.com", externaldnsendpoint.RecordTypeTXT,
+				"\"heritage=external-dns,external-dns/owner=owner2,external-dns/version=1\""),
+			externaldnsendpoint.NewEndpoint("baz.example.com", externaldnsendpoint.RecordTypeTXT, "\"v=spf1 -all\""),
+		}
+	})
+
+	It("should return the registry owners other than the given owner", func() {
+		Expect(registryOwners(endpoints, "owner1")).To(Equal([]string{"owner2"}))
+		Expect(registryOwners(endpoints, "owner3")).To(Equal([]string{"owner1", "owner2"}))
+		Expect(registryOwners(endpoints[3:], "owner1")).To(BeEmpty())
+	})
+
+	It("should split the registry records of the given owner from the remaining endpoints", func() {
+		registryRecords, remaining := splitRegistryRecords(endpoints, "owner1")
+		Expect(registryRecords).To(ConsistOf(endpoints[1]))
+		Expect(remaining).To(ConsistOf(endpoints[0], endpoints[2], endpoints[3]))
+	})
+
+	It("should only return the published endpoints", func() {
+		published := []*externaldnsendpoint.Endpoint{
+			externaldnsendpoint.NewEndpoint("foo.example.com", externaldnsendpoint.RecordTypeA, "2.2.2.2"),
+		}
+		Expect(publishedEndpoints(endpoints, published)).To(ConsistOf(endpoints[0]))
+		Expect(publishedEndpoints(endpoints, nil)).To(BeEmpty())
+	})
+})
@@ -11,11 +11,16 @@ import (
 
 	v1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	"github.com/kuadrant/dns-operator/api/v1alpha1"
 	"github.com/kuadrant/dns-operator/internal/probes"
@@ -35,6 +40,17 @@ type DNSProbeReconciler struct {
 	client.Client
 	Scheme       *runtime.Scheme
 	ProbeManager *probes.ProbeManager
+	// Shard is the value of the shard label of the probes this reconciler runs, all probes are run if empty. Sharded
+	// reconcilers don't need to be the leader, so probes are spread across all replicas.
+	Shard string
+	// WatchNamespaceSelector selects the namespaces of the probes that are run by the labels of the namespace, all
+	// namespaces are run if nil
+	WatchNamespaceSelector labels.Selector
+	// EndpointsHealthyDebounce is the minimum time between writes of the EndpointsHealthy condition of a record, changes
+	// to it are written as soon as they are seen if 0
+	EndpointsHealthyDebounce time.Duration
+
+	healthReports healthReports
 }
 
 //+kubebuilder:rbac:groups=kuadrant.io,resources=dnshealthcheckprobes,verbs=get;list;watch;create;update;patch;delete
@@ -66,6 +82,25 @@ func (r *DNSProbeReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 	dnsProbe := previous.DeepCopy()
 	ctx, _ = r.setLoggerValues(ctx, baseLogger, dnsProbe)
 
+	// the probe was moved to another shard, it is run by the replica of that shard
+	if !r.inShard(dnsProbe) {
+		logger.V(1).Info("probe moved to another shard, stopping health checks", "shard", dnsProbe.GetLabels()[ProbeShardLabel])
+		r.ProbeManager.StopProbeWorker(ctx, dnsProbe)
+		return ctrl.Result{}, nil
+	}
+
+	if dnsProbe.DeletionTimestamp == nil {
+		watched, err := namespaceWatched(ctx, r.Client, r.WatchNamespaceSelector, dnsProbe.Namespace)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if !watched {
+			logger.V(1).Info("namespace does not match the watch namespace selector, stopping health checks")
+			r.ProbeManager.StopProbeWorker(ctx, dnsProbe)
+			return ctrl.Result{}, nil
+		}
+	}
+
 	if dnsProbe.DeletionTimestamp != nil && !dnsProbe.DeletionTimestamp.IsZero() {
 		logger.Info("healthcheckprobe deleted cleaning up workers")
 		r.ProbeManager.StopProbeWorker(ctx, dnsProbe)
@@ -76,7 +111,7 @@ func (r *DNSProbeReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 			}
 			return ctrl.Result{}, err
 		}
-		return ctrl.Result{}, nil
+		return r.reportEndpointsHealth(ctx, dnsProbe)
 	}
 
 	if !controllerutil.ContainsFinalizer(dnsProbe, DNSHealthCheckFinalizer) {
@@ -99,7 +134,8 @@ func (r *DNSProbeReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 
 	r.ProbeManager.EnsureProbeWorker(ctx, r.Client, dnsProbe, headers)
 
-	return ctrl.Result{}, nil
+	// every probe cycle updates the status of the probe, reconciling it
+	return r.reportEndpointsHealth(ctx, dnsProbe)
 }
 
 func getAdditionalHeaders(ctx context.Context, clt client.Client, probeObj *v1alpha1.DNSHealthCheckProbe) (v1alpha1.AdditionalHeaders, error) {
@@ -147,7 +183,24 @@ func (r *DNSProbeReconciler) SetupWithManager(mgr ctrl.Manager, maxRequeue, vali
 	validFor = validForDuration
 	defaultValidationRequeue = minRequeue
 
-	return ctrl.NewControllerManagedBy(mgr).
-		For(&v1alpha1.DNSHealthCheckProbe{}).
-		Complete(r)
+	b := ctrl.NewControllerManagedBy(mgr)
+	if r.Shard != "" {
+		b = b.For(&v1alpha1.DNSHealthCheckProbe{}, builder.WithPredicates(r.shardPredicate())).
+			WithOptions(controller.Options{NeedLeaderElection: ptr.To(false)})
+	} else {
+		b = b.For(&v1alpha1.DNSHealthCheckProbe{})
+	}
+	if r.WatchNamespaceSelector != nil {
+		b = b.Watches(&v1.Namespace{}, enqueueNamespaceObjects(mgr.GetClient(), &v1alpha1.DNSHealthCheckProbeList{}, func(l client.ObjectList) []reconcile.Request {
+			var toReconcile []reconcile.Request
+			for _, probe := range l.(*v1alpha1.DNSHealthCheckProbeList).Items {
+				if !r.inShard(&probe) {
+					continue
+				}
+				toReconcile = append(toReconcile, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&probe)})
+			}
+			return toReconcile
+		}), namespaceLabelsChanged)
+	}
+	return b.Complete(r)
 }
@@ -0,0 +1,30 @@
+package controller
+
+import (
+	"github.com/kuadrant/dns-operator/api/v1alpha1"
+	externaldnsregistry "github.com/kuadrant/dns-operator/internal/external-dns/registry"
+)
+
+// DefaultAdoptionBatchSize is the maximum number of adopted endpoints of a record that have their registry TXT records
+// rewritten in a single reconcile, if not set on the reconciler.
+const DefaultAdoptionBatchSize = 20
+
+// ownerAdoptionFor returns the adoption of the endpoints of the record published by the external-dns instances in
+// spec.adoptFrom. Only the endpoints of the record are adopted, others owned by the same instances are left alone.
+func (r *DNSRecordReconciler) ownerAdoptionFor(dnsRecord *v1alpha1.DNSRecord) externaldnsregistry.OwnerAdoption {
+	batchSize := r.AdoptionBatchSize
+	if batchSize <= 0 {
+		batchSize = DefaultAdoptionBatchSize
+	}
+	dnsNames := make([]string, 0, len(dnsRecord.Spec.Endpoints))
+	for _, ep := range dnsRecord.Spec.Endpoints {
+		dnsNames = append(dnsNames, ep.DNSName)
+	}
+	return externaldnsregistry.OwnerAdoption{
+		Owners:    dnsRecord.Spec.AdoptFrom.Owners,
+		TXTPrefix: dnsRecord.Spec.AdoptFrom.TXTPrefix,
+		TXTSuffix: dnsRecord.Spec.AdoptFrom.TXTSuffix,
+		DNSNames:  dnsNames,
+		BatchSize: batchSize,
+	}
+}
@@ -32,6 +32,8 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
@@ -40,6 +42,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	externaldnsendpoint "sigs.k8s.io/external-dns/endpoint"
 	externaldnsprovider "sigs.k8s.io/external-dns/provider"
+	gatewayapiv1 "sigs.k8s.io/gateway-api/apis/v1"
 
 	"github.com/kuadrant/dns-operator/api/v1alpha1"
 	"github.com/kuadrant/dns-operator/internal/common"
@@ -47,18 +50,12 @@ import (
 	externaldnsregistry "github.com/kuadrant/dns-operator/internal/external-dns/registry"
 	"github.com/kuadrant/dns-operator/internal/metrics"
 	"github.com/kuadrant/dns-operator/internal/provider"
+	"github.com/kuadrant/dns-operator/pkg/authoritative"
 )
 
 const (
 	DNSRecordFinalizer        = "kuadrant.io/dns-record"
 	validationRequeueVariance = 0.5
-
-	txtRegistryPrefix              = "kuadrant-"
-	txtRegistrySuffix              = ""
-	txtRegistryWildcardReplacement = "wildcard"
-	txtRegistryEncryptEnabled      = false
-	txtRegistryEncryptAESKey       = ""
-	txtRegistryCacheInterval       = time.Duration(0)
 )
 
 var (
@@ -77,6 +74,83 @@ type DNSRecordReconciler struct {
 	client.Client
 	Scheme          *runtime.Scheme
 	ProviderFactory provider.Factory
+	// UnownedPublishDomains are the domains records are allowed to be published in without ownership
+	UnownedPublishDomains []string
+	// EndpointExclusions are the endpoints and targets that are never published, in addition to those excluded by
+	// each record
+	EndpointExclusions EndpointExclusions
+
+	// DeletionStuckDuration is how long a deleted record can fail to be removed from the provider before it is reported
+	// as stuck, defaults to DefaultDeletionStuckDuration
+	DeletionStuckDuration time.Duration
+	// AdoptionBatchSize is the maximum number of adopted endpoints of a record that have their registry TXT records
+	// rewritten in a single reconcile, defaults to DefaultAdoptionBatchSize
+	AdoptionBatchSize int
+	// PrivateTargetPolicy is the action taken when a record publishing to a public zone has private address targets
+	PrivateTargetPolicy PrivateTargetPolicy
+	// RRsetSizePolicy is the action taken when a record has RRsets with responses too large for resolvers or more
+	// targets than the provider allows
+	RRsetSizePolicy RRsetSizePolicy
+	// ProbeShards is the number of shards the health check probes of records are spread across, probes are not
+	// sharded if 1 or less
+	ProbeShards int
+	// CheckZoneDelegation enables checking the zone of a record is delegated to its nameservers by the parent zone
+	// before the record is first published
+	CheckZoneDelegation bool
+	// ParkingTarget is the hostname or address the root host of parked records points to
+	ParkingTarget string
+	// WatchNamespaceSelector selects the namespaces of the records that are reconciled by the labels of the namespace,
+	// all namespaces are reconciled if nil
+	WatchNamespaceSelector labels.Selector
+	// DisableOverdueMetrics disables the collector of the number of records overdue to be reconciled, which lists all
+	// records on every scrape
+	DisableOverdueMetrics bool
+	// DisableNamespaceMetrics disables the collector of the records, endpoints and zones of each namespace, which lists
+	// all records and quotas on every scrape
+	DisableNamespaceMetrics bool
+	// FreezeOnDrift stops publishing records with endpoints changed in the zone outside of the operator, until the
+	// changes are acknowledged with the drift acknowledged annotation
+	FreezeOnDrift bool
+	// TakeoverProtection stops publishing changes that remove the targets or ownership of other owners of shared
+	// endpoints, until they are confirmed with the takeover confirmation annotation
+	TakeoverProtection bool
+	// DampeningWindow is how long the endpoints of a record must be unchanged for before changes to them are published,
+	// changes are published as soon as they are seen if 0
+	DampeningWindow time.Duration
+	// ReconcileTimeout is how long a reconcile can run for before it is reported as stuck and aborted, reconciles are
+	// not bounded if 0
+	ReconcileTimeout time.Duration
+	// ValidateTargets fails the reconcile of records with endpoint targets that are not valid for their record type,
+	// partially published records leave out the invalid endpoints instead
+	ValidateTargets bool
+	// RequireDomainVerification stops records from being first published until ownership of their root host is
+	// verified with a TXT record
+	RequireDomainVerification bool
+	// OwnerIDFormat is how the owner IDs of records without an owner ID in their spec are derived
+	OwnerIDFormat OwnerIDFormat
+	// EventLimits aggregates and rate limits the events of records, they are not limited if nil
+	EventLimits *EventLimits
+	// ChangeNotificationURL is the HTTP sink the changes applied to zones are published to as CloudEvents, changes are
+	// not published if empty
+	ChangeNotificationURL string
+	// MigrateOwnerIDs changes the owner IDs of existing records to the owner IDs derived with OwnerIDFormat, rewriting
+	// their registry TXT records
+	MigrateOwnerIDs bool
+	// ZoneStateRecord maintains a TXT record in every zone changes are applied to, with a serial and the time of the last
+	// change, so the freshness of the zone can be monitored with DNS queries
+	ZoneStateRecord bool
+	// ZoneRecordLimit is the maximum number of records allowed in a zone by the provider, records publishing to zones
+	// with most of the records allowed get the RegistryPressure condition. Provider secrets can set their own limit with
+	// the ZONE_RECORD_LIMIT key, zone records are not counted if 0
+	ZoneRecordLimit int
+	// PhaseObserver is told of the phases of every reconcile, in addition to the reconcile phase duration metrics
+	PhaseObserver PhaseObserver
+
+	zoneCache      *negativeZoneCache
+	zoneGauges     *zoneRecordGauges
+	excluded       *excludedEndpoints
+	recorder       record.EventRecorder
+	changeNotifier *changeNotifier
 }
 
 func postReconcile(ctx context.Context) {
@@ -86,8 +160,15 @@ func postReconcile(ctx context.Context) {
 //+kubebuilder:rbac:groups=kuadrant.io,resources=dnsrecords,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=kuadrant.io,resources=dnsrecords/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=kuadrant.io,resources=dnsrecords/finalizers,verbs=update
+//+kubebuilder:rbac:groups=kuadrant.io,resources=dnsquotas,verbs=get;list;watch
 
 func (r *DNSRecordReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	return withWatchdog(ctx, r.ReconcileTimeout, func(ctx context.Context) (ctrl.Result, error) {
+		return r.reconcile(ctx, req)
+	})
+}
+
+func (r *DNSRecordReconciler) reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	// Keep a reference to the initial logger(baseLogger) so we can update it throughout the reconcile
 	baseLogger := log.FromContext(ctx).WithName("dnsrecord_controller")
 	ctx = log.IntoContext(ctx, baseLogger)
@@ -113,20 +194,45 @@ func (r *DNSRecordReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 		}
 	}
 	dnsRecord := previous.DeepCopy()
+	// set again below if the provider fails in this reconcile
+	dnsRecord.Status.ProviderError = nil
 
 	// Update the logger with appropriate record/zone metadata from the dnsRecord
 	ctx, logger = r.setLogger(ctx, baseLogger, dnsRecord)
 
+	// records in namespaces that stopped matching the selector are left as they are, but are still removed if deleted
+	if dnsRecord.DeletionTimestamp == nil {
+		watched, err := namespaceWatched(ctx, r.Client, r.WatchNamespaceSelector, dnsRecord.Namespace)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if !watched {
+			logger.V(1).Info("namespace does not match the watch namespace selector, skipping")
+			return ctrl.Result{}, nil
+		}
+	}
+
 	if dnsRecord.DeletionTimestamp != nil && !dnsRecord.DeletionTimestamp.IsZero() {
 		logger.Info("Deleting DNSRecord")
+		if dnsRecord.Status.Phase != v1alpha1.DNSRecordPhaseDeleting {
+			dnsRecord.Status.Phase = v1alpha1.DNSRecordPhaseDeleting
+			if err = r.Status().Update(ctx, dnsRecord); client.IgnoreNotFound(err) != nil {
+				if apierrors.IsConflict(err) {
+					return ctrl.Result{Requeue: true}, nil
+				}
+				return ctrl.Result{}, err
+			}
+		}
 		if dnsRecord.HasDNSZoneAssigned() {
 			// Create a dns provider with config calculated for the current dns record status (Last successful)
 			dnsProvider, err := r.getDNSProvider(ctx, dnsRecord)
 			if err != nil {
 				logger.Error(err, "Failed to load DNS Provider")
+				r.trackDeletionFailure(ctx, dnsRecord, err)
 				reason := "DNSProviderError"
 				message := fmt.Sprintf("The dns provider could not be loaded: %v", err)
 				setDNSRecordCondition(dnsRecord, string(v1alpha1.ConditionTypeReady), metav1.ConditionFalse, reason, message)
+				r.setProviderError(ctx, dnsRecord, err)
 				return r.updateStatus(ctx, previous, dnsRecord, probes, false, []string{}, err)
 			}
 
@@ -138,6 +244,7 @@ func (r *DNSRecordReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 			hadChanges, err := r.deleteRecord(ctx, dnsRecord, dnsProvider)
 			if err != nil {
 				logger.Error(err, "Failed to delete DNSRecord")
+				r.trackDeletionFailure(ctx, dnsRecord, err)
 				return ctrl.Result{}, err
 			}
 			// if hadChanges - the deleteRecord has successfully applied changes
@@ -146,10 +253,23 @@ func (r *DNSRecordReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 			if hadChanges {
 				return ctrl.Result{RequeueAfter: randomizedValidationRequeue}, nil
 			}
+			// remove the endpoints from the zone the record was being migrated to
+			hadChanges, err = r.deleteFromMigrationTarget(ctx, dnsRecord)
+			if err != nil {
+				logger.Error(err, "Failed to delete DNSRecord from migration target")
+				r.trackDeletionFailure(ctx, dnsRecord, err)
+				return ctrl.Result{}, err
+			}
+			if hadChanges {
+				return ctrl.Result{RequeueAfter: randomizedValidationRequeue}, nil
+			}
 		} else {
 			logger.Info("dns zone was never assigned, skipping zone cleanup")
 		}
 
+		metrics.ResetDeletionMetrics(dnsRecord.Name, dnsRecord.Namespace)
+		r.zoneGauges.remove(client.ObjectKeyFromObject(dnsRecord))
+		r.excluded.remove(client.ObjectKeyFromObject(dnsRecord))
 		logger.Info("Removing Finalizer", "finalizer_name", DNSRecordFinalizer)
 		controllerutil.RemoveFinalizer(dnsRecord, DNSRecordFinalizer)
 		if err = r.Update(ctx, dnsRecord); client.IgnoreNotFound(err) != nil {
@@ -171,7 +291,76 @@ func (r *DNSRecordReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 		return ctrl.Result{RequeueAfter: randomizedValidationRequeue}, nil
 	}
 
+	expired, err := r.acmeChallengeExpired(ctx, dnsRecord, time.Now())
+	if err != nil {
+		// the record is still published, only its garbage collection is affected
+		logger.Error(err, "Failed to check ACME challenge expiry")
+		// the error is kept in the status, the event is only recorded when it changes
+		if previous.Status.ACMEChallenge == nil || previous.Status.ACMEChallenge.TTLError != err.Error() {
+			r.recorder.Eventf(dnsRecord, v1.EventTypeWarning, "ACMEChallengeTTLError", "The ACME challenge TTL could not be read: %v", err)
+		}
+	}
+	if expired {
+		logger.Info("Deleting expired ACME challenge DNSRecord")
+		r.recorder.Eventf(dnsRecord, v1.EventTypeNormal, "ACMEChallengeExpired",
+			"The record only publishes ACME challenges and was not updated within the challenge TTL of zone %s", dnsRecord.Status.ZoneDomainName)
+		if err = r.Delete(ctx, dnsRecord); client.IgnoreNotFound(err) != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, nil
+	}
+
+	if isRollbackRequested(dnsRecord) {
+		if err = rollbackEndpoints(dnsRecord); err != nil {
+			logger.Error(err, "Failed to roll back record")
+			setDNSRecordCondition(dnsRecord, string(v1alpha1.ConditionTypeReady), metav1.ConditionFalse,
+				"RollbackError", fmt.Sprintf("The record could not be rolled back: %v", err))
+			return r.updateStatus(ctx, previous, dnsRecord, probes, false, []string{}, err)
+		}
+		logger.Info("Rolling back endpoints", "revision", previous.GetAnnotations()[v1alpha1.RollbackToAnnotation])
+		if err = r.Update(ctx, dnsRecord); err != nil {
+			if apierrors.IsConflict(err) {
+				return ctrl.Result{Requeue: true}, nil
+			}
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{Requeue: true}, nil
+	}
+
+	if err = r.expandGatewayEndpoints(ctx, dnsRecord); err != nil {
+		logger.Error(err, "Failed to expand gateway endpoints")
+		setDNSRecordCondition(dnsRecord, string(v1alpha1.ConditionTypeReady), metav1.ConditionFalse,
+			"GatewayEndpointError", fmt.Sprintf("gateway endpoints could not be resolved: %v", err))
+		return r.updateStatus(ctx, previous, dnsRecord, probes, false, []string{}, err)
+	}
+
+	if err = expandMailEndpoints(dnsRecord); err != nil {
+		logger.Error(err, "Failed to expand mail endpoints")
+		setDNSRecordCondition(dnsRecord, string(v1alpha1.ConditionTypeReady), metav1.ConditionFalse,
+			"ValidationError", fmt.Sprintf("validation of DNSRecord failed: %v", err))
+		return r.updateStatus(ctx, previous, dnsRecord, probes, false, []string{}, err)
+	}
+
+	if err = flattenCNAMEEndpoints(ctx, dnsRecord); err != nil {
+		logger.Error(err, "Failed to flatten CNAME endpoints")
+		setDNSRecordCondition(dnsRecord, string(v1alpha1.ConditionTypeReady), metav1.ConditionFalse,
+			"FlattenError", fmt.Sprintf("CNAME endpoints could not be flattened: %v", err))
+		return r.updateStatus(ctx, previous, dnsRecord, probes, false, []string{}, err)
+	}
+
+	resetEndpointStatuses(dnsRecord)
+	// partially published records leave out the endpoints that are invalid on their own
+	if dnsRecord.IsPartialPublish() {
+		rejectEndpoints(dnsRecord, rootHostMismatch(dnsRecord.Spec.RootHost))
+		if r.ValidateTargets {
+			rejectEndpoints(dnsRecord, invalidTargets)
+		}
+	}
+
 	err = dnsRecord.Validate()
+	if err == nil && r.ValidateTargets {
+		err = dnsRecord.ValidateTargets()
+	}
 	if err != nil {
 		logger.Error(err, "Failed to validate record")
 		setDNSRecordCondition(dnsRecord, string(v1alpha1.ConditionTypeReady), metav1.ConditionFalse,
@@ -179,39 +368,103 @@ func (r *DNSRecordReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 		return r.updateStatus(ctx, previous, dnsRecord, probes, false, []string{}, err)
 	}
 
+	if dnsRecord.IsUnownedPublish() {
+		if err = r.validateUnownedPublish(dnsRecord); err != nil {
+			logger.Error(err, "Failed to validate record")
+			setDNSRecordCondition(dnsRecord, string(v1alpha1.ConditionTypeReady), metav1.ConditionFalse,
+				"ValidationError", fmt.Sprintf("validation of DNSRecord failed: %v", err))
+			return r.updateStatus(ctx, previous, dnsRecord, probes, false, []string{}, err)
+		}
+		logger.Info("WARNING: publishing record without ownership, endpoints will not be tracked in the registry and will never be deleted")
+		setDNSRecordCondition(dnsRecord, string(v1alpha1.ConditionTypeUnowned), metav1.ConditionTrue,
+			string(v1alpha1.ConditionReasonUnownedPublish), "Endpoints are published without ownership and will never be deleted from the provider")
+	} else {
+		meta.RemoveStatusCondition(&dnsRecord.Status.Conditions, string(v1alpha1.ConditionTypeUnowned))
+	}
+
+	if r.RequireDomainVerification {
+		verified, err := verifyDomain(ctx, dnsRecord)
+		if err != nil {
+			logger.Error(err, "Failed to validate record")
+			setDNSRecordCondition(dnsRecord, string(v1alpha1.ConditionTypeReady), metav1.ConditionFalse,
+				"ValidationError", fmt.Sprintf("validation of DNSRecord failed: %v", err))
+			return r.updateStatus(ctx, previous, dnsRecord, probes, false, []string{}, err)
+		}
+		if !verified {
+			return r.awaitDomainVerification(ctx, previous, dnsRecord)
+		}
+	}
+
 	//Ensure an Owner ID has been assigned to the record (OwnerID set in the status)
 	if !dnsRecord.HasOwnerIDAssigned() {
 		if dnsRecord.Spec.OwnerID != "" {
 			dnsRecord.Status.OwnerID = dnsRecord.Spec.OwnerID
 		} else {
-			dnsRecord.Status.OwnerID = dnsRecord.GetUIDHash()
+			dnsRecord.Status.OwnerID = r.OwnerIDFormat.OwnerIDFor(dnsRecord)
 		}
 		//Update logger and context so it includes updated owner metadata
 		ctx, logger = r.setLogger(ctx, baseLogger, dnsRecord)
+	} else if r.MigrateOwnerIDs && migrateOwnerID(dnsRecord, r.OwnerIDFormat) {
+		logger.Info("Migrating owner ID", "previousOwnerID", dnsRecord.Status.PreviousOwnerID, "newOwnerID", dnsRecord.Status.OwnerID)
+		ctx, logger = r.setLogger(ctx, baseLogger, dnsRecord)
+	}
+
+	// the record has been switched to the provider it was migrated to, it is now published in the zone of the migration
+	if completeMigration(dnsRecord) {
+		logger.Info("Migration complete", "providerRef", dnsRecord.Spec.ProviderRef.Name)
+		ctx, logger = r.setLogger(ctx, baseLogger, dnsRecord)
 	}
 
 	// Ensure a DNS Zone has been assigned to the record (ZoneID and ZoneDomainName are set in the status)
 	if !dnsRecord.HasDNSZoneAssigned() {
 		logger.Info(fmt.Sprintf("provider zone not assigned for root host %s, finding suitable zone", dnsRecord.Spec.RootHost))
 
+		secretKey := client.ObjectKey{Namespace: dnsRecord.Namespace, Name: dnsRecord.Spec.ProviderRef.Name}
+		zoneLookupToken := dnsRecord.GetAnnotations()[v1alpha1.ZoneLookupAnnotation]
+
+		// no zone was found recently for this root host, zones rarely appear so don't ask the provider again yet
+		if err, retryIn, ok := r.zoneCache.get(secretKey, dnsRecord.Spec.RootHost, zoneLookupToken); ok {
+			logger.V(1).Info("skipping zone lookup, no suitable zone was found recently", "retryIn", retryIn.String())
+			setDNSRecordCondition(dnsRecord, string(v1alpha1.ConditionTypeReady), metav1.ConditionFalse,
+				"DNSProviderError", fmt.Sprintf("Unable to find suitable zone in provider: %v", provider.SanitizeError(err)))
+			r.setProviderError(ctx, dnsRecord, err)
+			if result, err := r.updateStatus(ctx, previous, dnsRecord, probes, false, []string{}, err); err != nil {
+				return result, err
+			}
+			return ctrl.Result{RequeueAfter: retryIn}, nil
+		}
+
 		// Create a dns provider with no config to list all potential zones available from the configured provider
 		p, err := r.ProviderFactory.ProviderFor(ctx, dnsRecord, provider.Config{})
 		if err != nil {
 			setDNSRecordCondition(dnsRecord, string(v1alpha1.ConditionTypeReady), metav1.ConditionFalse,
 				"DNSProviderError", fmt.Sprintf("The dns provider could not be loaded: %v", err))
+			r.setProviderError(ctx, dnsRecord, err)
 			return r.updateStatus(ctx, previous, dnsRecord, probes, false, []string{}, err)
 		}
 
 		z, err := p.DNSZoneForHost(ctx, dnsRecord.Spec.RootHost)
+		if errors.Is(err, provider.ErrDomainNotAllowed) {
+			logger.Error(err, "Zone of record is not allowed")
+			setDNSRecordCondition(dnsRecord, string(v1alpha1.ConditionTypeReady), metav1.ConditionFalse,
+				string(v1alpha1.ConditionReasonDomainNotAllowed), fmt.Sprintf("The provider secret does not allow publishing the record: %v", err))
+			return r.updateStatus(ctx, previous, dnsRecord, probes, false, []string{}, err)
+		}
 		if err != nil {
+			if errors.Is(err, provider.ErrNoZoneForHost) {
+				r.zoneCache.add(secretKey, dnsRecord.Spec.RootHost, zoneLookupToken, err)
+			}
 			setDNSRecordCondition(dnsRecord, string(v1alpha1.ConditionTypeReady), metav1.ConditionFalse,
 				"DNSProviderError", fmt.Sprintf("Unable to find suitable zone in provider: %v", provider.SanitizeError(err)))
+			r.setProviderError(ctx, dnsRecord, err)
 			return r.updateStatus(ctx, previous, dnsRecord, probes, false, []string{}, err)
 		}
+		r.zoneCache.remove(secretKey, dnsRecord.Spec.RootHost)
 
 		//Add zone id/domainName to status
 		dnsRecord.Status.ZoneID = z.ID
 		dnsRecord.Status.ZoneDomainName = z.DNSName
+		dnsRecord.Status.ZoneVisibility = zoneVisibility(z)
 
 		//Update logger and context so it includes updated zone metadata
 		ctx, logger = r.setLogger(ctx, baseLogger, dnsRecord)
@@ -222,9 +475,39 @@ func (r *DNSRecordReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 	if err != nil {
 		setDNSRecordCondition(dnsRecord, string(v1alpha1.ConditionTypeReady), metav1.ConditionFalse,
 			"DNSProviderError", fmt.Sprintf("The dns provider could not be loaded: %v", err))
+		r.setProviderError(ctx, dnsRecord, err)
 		return r.updateStatus(ctx, previous, dnsRecord, probes, false, []string{}, err)
 	}
 
+	if r.PrivateTargetPolicy != PrivateTargetPolicyNone {
+		if err = ensureZoneVisibility(ctx, dnsRecord, dnsProvider); err != nil {
+			setDNSRecordCondition(dnsRecord, string(v1alpha1.ConditionTypeReady), metav1.ConditionFalse,
+				"DNSProviderError", fmt.Sprintf("Unable to determine the visibility of the zone: %v", provider.SanitizeError(err)))
+			r.setProviderError(ctx, dnsRecord, err)
+			return r.updateStatus(ctx, previous, dnsRecord, probes, false, []string{}, err)
+		}
+	}
+	if err = r.checkPrivateTargets(dnsRecord); err != nil {
+		logger.Error(err, "Failed to validate record")
+		setDNSRecordCondition(dnsRecord, string(v1alpha1.ConditionTypeReady), metav1.ConditionFalse,
+			"ValidationError", fmt.Sprintf("validation of DNSRecord failed: %v", err))
+		return r.updateStatus(ctx, previous, dnsRecord, probes, false, []string{}, err)
+	}
+	r.checkZoneDelegation(ctx, dnsRecord, dnsProvider)
+
+	if dnsRecord.IsDedicatedZone() {
+		if err = r.validateDedicatedZone(ctx, dnsRecord, dnsProvider); err != nil {
+			logger.Error(err, "Failed to validate dedicated zone")
+			setDNSRecordCondition(dnsRecord, string(v1alpha1.ConditionTypeReady), metav1.ConditionFalse,
+				"DedicatedZoneConflict", fmt.Sprintf("The zone is not dedicated to the record: %v", provider.SanitizeError(err)))
+			return r.updateStatus(ctx, previous, dnsRecord, probes, false, []string{}, err)
+		}
+		setDNSRecordCondition(dnsRecord, string(v1alpha1.ConditionTypeDedicatedZone), metav1.ConditionTrue,
+			string(v1alpha1.ConditionReasonDedicatedZonePublish), "Endpoints are published without registry records and records in the zone that are not endpoints of the record are removed")
+	} else {
+		meta.RemoveStatusCondition(&dnsRecord.Status.Conditions, string(v1alpha1.ConditionTypeDedicatedZone))
+	}
+
 	if probesEnabled {
 		if err = r.ReconcileHealthChecks(ctx, dnsRecord, allowInsecureCert); err != nil {
 			return ctrl.Result{}, err
@@ -239,14 +522,93 @@ func (r *DNSRecordReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 			return ctrl.Result{}, err
 		}
 	}
+	// parked after the health checks are reconciled, so the endpoints are probed while parked and ready to be restored
+	if err = r.parkRecord(dnsRecord); err != nil {
+		logger.Error(err, "Failed to park record")
+		setDNSRecordCondition(dnsRecord, string(v1alpha1.ConditionTypeReady), metav1.ConditionFalse,
+			"ValidationError", fmt.Sprintf("validation of DNSRecord failed: %v", err))
+		return r.updateStatus(ctx, previous, dnsRecord, probes, false, []string{}, err)
+	}
+
+	if isMigrating(dnsRecord) {
+		return r.reconcileMigration(ctx, previous, dnsRecord, probes, dnsProvider)
+	}
+
+	// changes to the endpoints of flapping sources are only published once they are stable
+	if r.DampeningWindow > 0 && !dnsRecord.IsForceApply() {
+		if holdFor := dampenChanges(dnsRecord, r.DampeningWindow, reconcileStart.Time); holdFor > 0 {
+			return r.holdChanges(ctx, previous, dnsRecord, holdFor)
+		}
+	} else {
+		dnsRecord.Status.Dampening = nil
+	}
+	meta.RemoveStatusCondition(&dnsRecord.Status.Conditions, string(v1alpha1.ConditionTypeChangesDampened))
+
 	// Publish the record
 	hadChanges, notHealthyProbes, err := r.publishRecord(ctx, dnsRecord, probes, dnsProvider)
 	if err != nil {
 		logger.Error(err, "Failed to publish record")
+		if errors.Is(err, provider.ErrDomainNotAllowed) {
+			setDNSRecordCondition(dnsRecord, string(v1alpha1.ConditionTypeReady), metav1.ConditionFalse,
+				string(v1alpha1.ConditionReasonDomainNotAllowed), fmt.Sprintf("The provider secret does not allow publishing the record: %v", err))
+			return r.updateStatus(ctx, previous, dnsRecord, probes, hadChanges, notHealthyProbes, err)
+		}
+		var budgetErr *provider.WriteBudgetExceededError
+		if errors.As(err, &budgetErr) {
+			return r.deferChanges(ctx, previous, dnsRecord, budgetErr)
+		}
+		if errors.Is(err, errDriftDetected) {
+			setDNSRecordCondition(dnsRecord, string(v1alpha1.ConditionTypeReady), metav1.ConditionFalse,
+				string(v1alpha1.ConditionReasonDriftDetected), fmt.Sprintf("The record is not published until the changes are acknowledged with the %s annotation: %v",
+					v1alpha1.DriftAcknowledgedAnnotation, err))
+			return r.updateStatus(ctx, previous, dnsRecord, probes, hadChanges, notHealthyProbes, err)
+		}
+		if errors.Is(err, errTakeoverPending) {
+			setDNSRecordCondition(dnsRecord, string(v1alpha1.ConditionTypeReady), metav1.ConditionFalse,
+				string(v1alpha1.ConditionReasonTakeoverPending), fmt.Sprintf("The record is not published until the takeover is confirmed with the %s annotation: %v",
+					v1alpha1.TakeoverConfirmedAnnotation, err))
+			return r.updateStatus(ctx, previous, dnsRecord, probes, hadChanges, notHealthyProbes, err)
+		}
+		if errors.Is(err, errOversizedRRsets) || errors.Is(err, provider.ErrRecordTypeNotSupported) ||
+			errors.Is(err, externaldnsregistry.ErrUnknownTXTFormat) {
+			setDNSRecordCondition(dnsRecord, string(v1alpha1.ConditionTypeReady), metav1.ConditionFalse,
+				"ValidationError", fmt.Sprintf("validation of DNSRecord failed: %v", err))
+			return r.updateStatus(ctx, previous, dnsRecord, probes, hadChanges, notHealthyProbes, err)
+		}
 		setDNSRecordCondition(dnsRecord, string(v1alpha1.ConditionTypeReady), metav1.ConditionFalse,
 			"ProviderError", fmt.Sprintf("The DNS provider failed to ensure the record: %v", provider.SanitizeError(err)))
+		r.setProviderError(ctx, dnsRecord, err)
 		return r.updateStatus(ctx, previous, dnsRecord, probes, hadChanges, notHealthyProbes, err)
 	}
+	meta.RemoveStatusCondition(&dnsRecord.Status.Conditions, string(v1alpha1.ConditionTypeBudgetExceeded))
+	trackPropagation(ctx, dnsRecord, dnsProvider)
+
+	if isDriftAcknowledged(dnsRecord) {
+		if err = r.clearDriftAcknowledgement(ctx, dnsRecord); err != nil {
+			if apierrors.IsConflict(err) {
+				return ctrl.Result{Requeue: true}, nil
+			}
+			return ctrl.Result{}, err
+		}
+	}
+
+	if isTakeoverConfirmed(dnsRecord) {
+		if err = r.removeAnnotation(ctx, dnsRecord, v1alpha1.TakeoverConfirmedAnnotation); err != nil {
+			if apierrors.IsConflict(err) {
+				return ctrl.Result{Requeue: true}, nil
+			}
+			return ctrl.Result{}, err
+		}
+	}
+
+	if dnsRecord.IsForceApply() {
+		if err = r.removeAnnotation(ctx, dnsRecord, v1alpha1.ForceApplyAnnotation); err != nil {
+			if apierrors.IsConflict(err) {
+				return ctrl.Result{Requeue: true}, nil
+			}
+			return ctrl.Result{}, err
+		}
+	}
 
 	return r.updateStatus(ctx, previous, dnsRecord, probes, hadChanges, notHealthyProbes, nil)
 }
@@ -269,6 +631,9 @@ func (r *DNSRecordReconciler) updateStatus(ctx context.Context, previous, curren
 	// failure
 	if specErr != nil {
 		logger.Error(specErr, "Error reconciling DNS Record")
+		failEndpointStatuses(current, specErr)
+		setPartiallyPublishedCondition(current)
+		setStatusPhase(current, specErr)
 		var updateError error
 		if !equality.Semantic.DeepEqual(previous.Status, current.Status) {
 			if updateError = r.Status().Update(ctx, current); updateError != nil && apierrors.IsConflict(updateError) {
@@ -293,6 +658,12 @@ func (r *DNSRecordReconciler) updateStatus(ctx context.Context, previous, curren
 			logger.V(1).Info("Changes needed on the same generation of record")
 		}
 		requeueTime = randomizedValidationRequeue
+		if propagationPending(current) {
+			requeueTime = propagationRequeue
+		}
+	} else if propagationPending(current) {
+		logger.Info("Awaiting propagation of changes", "pendingChanges", current.Status.PendingChanges)
+		requeueTime = propagationRequeue
 	} else {
 		logger.Info("All records are already up to date")
 
@@ -301,8 +672,9 @@ func (r *DNSRecordReconciler) updateStatus(ctx context.Context, previous, curren
 		// this is the first reconciliation current.Status.ValidFor is not set
 		if readyCond == nil {
 			requeueTime = defaultValidationRequeue
-		} else if readyCond.Status == metav1.ConditionFalse && readyCond.Reason == string(v1alpha1.ConditionReasonAwaitingValidation) {
-			// no changes and we are awaiting validation - validation succeeded
+		} else if readyCond.Status == metav1.ConditionFalse && (readyCond.Reason == string(v1alpha1.ConditionReasonAwaitingValidation) ||
+			readyCond.Reason == string(v1alpha1.ConditionReasonAwaitingPropagation)) {
+			// no changes and we are awaiting validation or propagation - validation succeeded
 			// reset to a fixed value from a randomized one
 			requeueTime = exponentialRequeueTime(defaultValidationRequeue.String())
 		} else {
@@ -318,7 +690,20 @@ func (r *DNSRecordReconciler) updateStatus(ctx context.Context, previous, curren
 		}
 	}
 
+	// flattened CNAME endpoints are resolved again within their TTL
+	if flattenRequeue := flattenRequeueTime(previous.Spec.Endpoints); flattenRequeue > 0 && flattenRequeue < requeueTime {
+		requeueTime = flattenRequeue
+	}
+	// lingering targets are removed once they have expired from caches
+	if lingerRequeue := lingerRequeueTime(current.Status.LingeringTargets, reconcileStart.Time); lingerRequeue > 0 && lingerRequeue < requeueTime {
+		requeueTime = lingerRequeue
+	}
+
 	setStatusConditions(current, hadChanges, notHealthyProbes)
+	setPartiallyPublishedCondition(current)
+	setStatusPhase(current, nil)
+	// the endpoints of the spec as it was received, not as expanded while reconciling
+	recordRevision(current, previous.Spec.Endpoints)
 
 	// valid for is always a requeue time
 	current.Status.ValidFor = requeueTime.String()
@@ -332,30 +717,99 @@ func (r *DNSRecordReconciler) updateStatus(ctx context.Context, previous, curren
 
 	current.Status.ObservedGeneration = current.Generation
 	current.Status.QueuedAt = reconcileStart
+	setValidationSchedule(current, requeueTime)
+	if reconcileRequested(current) {
+		r.recorder.Eventf(current, v1.EventTypeNormal, "ForceReconcile", "Reconciled against the provider as requested with %s=%s",
+			v1alpha1.ReconcileRequestAnnotation, current.GetAnnotations()[v1alpha1.ReconcileRequestAnnotation])
+		current.Status.LastHandledReconcileRequest = current.GetAnnotations()[v1alpha1.ReconcileRequestAnnotation]
+	}
 
 	// update the record after setting the status
-	if !equality.Semantic.DeepEqual(previous.Status, current.Status) {
+	if statusChanged(previous, current) {
 		logger.V(1).Info("Updating status of DNSRecord")
+		completeStatus := r.startPhase(ctx, ReconcilePhaseStatus)
 		if updateError := r.Status().Update(ctx, current); updateError != nil {
 			if apierrors.IsConflict(updateError) {
 				return ctrl.Result{Requeue: true}, nil
 			}
 			return ctrl.Result{}, updateError
 		}
+		completeStatus()
+	} else if !equality.Semantic.DeepEqual(previous.Status, current.Status) {
+		logger.V(1).Info("Skipping status update of DNSRecord, only the queued time changed")
+		metrics.StatusUpdatesSuppressed.Inc()
 	}
 	logger.V(1).Info(fmt.Sprintf("Requeue in %s", requeueTime.String()))
 	return ctrl.Result{RequeueAfter: requeueTime}, nil
 }
 
-// SetupWithManager sets up the controller with the Manager.
-func (r *DNSRecordReconciler) SetupWithManager(mgr ctrl.Manager, maxRequeue, validForDuration, minRequeue time.Duration, healthProbesEnabled, allowInsecureHealthCert bool) error {
+// setValidationSchedule sets the time the record is next reconciled against the provider from the queued time and the
+// requeue time, the reconcile it was queued for is valid until then.
+func setValidationSchedule(record *v1alpha1.DNSRecord, requeueTime time.Duration) {
+	next := metav1.NewTime(record.Status.QueuedAt.Add(requeueTime))
+	record.Status.NextValidation = &next
+}
+
+// statusChanged returns true if the status of the record has to be written. A status where only the queued time, and the
+// validation schedule derived from it, changed is not written while the previous status is still valid, the record is
+// not due to be reconciled until then so the previous queued time has the same meaning to the premature reconcile check
+// and the queue metrics.
+func statusChanged(previous, current *v1alpha1.DNSRecord) bool {
+	if equality.Semantic.DeepEqual(previous.Status, current.Status) {
+		return false
+	}
+	previousStatus := previous.Status.DeepCopy()
+	previousStatus.QueuedAt = current.Status.QueuedAt
+	previousStatus.NextValidation = current.Status.NextValidation
+	if !equality.Semantic.DeepEqual(*previousStatus, current.Status) {
+		return true
+	}
+	validFor, err := time.ParseDuration(previous.Status.ValidFor)
+	if err != nil {
+		return true
+	}
+	return !current.Status.QueuedAt.Time.Before(previous.Status.QueuedAt.Add(validFor))
+}
+
+// SetupWithoutManager sets up the reconciler to be driven by calling Reconcile directly, without watches, health checks
+// or change notifications, e.g. by a load test. Events are recorded with the given recorder.
+func (r *DNSRecordReconciler) SetupWithoutManager(recorder record.EventRecorder, maxRequeue, validForDuration, minRequeue time.Duration) {
+	r.setup(recorder, maxRequeue, validForDuration, minRequeue, false, false)
+}
+
+func (r *DNSRecordReconciler) setup(recorder record.EventRecorder, maxRequeue, validForDuration, minRequeue time.Duration, healthProbesEnabled, allowInsecureHealthCert bool) {
 	defaultRequeueTime = maxRequeue
 	validFor = validForDuration
 	defaultValidationRequeue = minRequeue
 	probesEnabled = healthProbesEnabled
 	allowInsecureCert = allowInsecureHealthCert
+	r.zoneCache = newNegativeZoneCache(minRequeue, maxRequeue)
+	r.zoneGauges = newZoneRecordGauges()
+	r.excluded = newExcludedEndpoints()
+	r.recorder = r.EventLimits.Wrap(recorder)
+}
 
-	return ctrl.NewControllerManagedBy(mgr).
+// SetupWithManager sets up the controller with the Manager.
+func (r *DNSRecordReconciler) SetupWithManager(mgr ctrl.Manager, maxRequeue, validForDuration, minRequeue time.Duration, healthProbesEnabled, allowInsecureHealthCert bool) error {
+	r.setup(mgr.GetEventRecorderFor("dnsrecord-controller"), maxRequeue, validForDuration, minRequeue, healthProbesEnabled, allowInsecureHealthCert)
+	if r.ChangeNotificationURL != "" {
+		r.changeNotifier = newChangeNotifier(r.ChangeNotificationURL)
+		if err := mgr.Add(r.changeNotifier); err != nil {
+			return err
+		}
+	}
+	if !r.DisableOverdueMetrics {
+		if err := metrics.RegisterOverdueCollector(overdueDNSRecords(mgr.GetCache())); err != nil {
+			return err
+		}
+	}
+	if !r.DisableNamespaceMetrics {
+		if err := metrics.RegisterNamespaceCollector(namespaceUsage(mgr.GetCache())); err != nil {
+			return err
+		}
+	}
+
+	b := ctrl.NewControllerManagedBy(mgr).
 		For(&v1alpha1.DNSRecord{}).
 		Watches(&v1.Secret{}, handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, o client.Object) []reconcile.Request {
 			logger := log.FromContext(ctx)
@@ -367,6 +821,8 @@ func (r *DNSRecordReconciler) SetupWithManager(mgr ctrl.Manager, maxRequeue, val
 			if !strings.HasPrefix(string(s.Type), "kuadrant.io") {
 				return nil
 			}
+			// zones may have been added to the account the secret refers to
+			r.zoneCache.invalidate(client.ObjectKeyFromObject(s))
 			var toReconcile []reconcile.Request
 			// list dns records in the secret namespace as they will be in the same namespace as the secret
 			records := &v1alpha1.DNSRecordList{}
@@ -375,7 +831,7 @@ func (r *DNSRecordReconciler) SetupWithManager(mgr ctrl.Manager, maxRequeue, val
 				return toReconcile
 			}
 			for _, record := range records.Items {
-				if record.Spec.ProviderRef.Name == o.GetName() {
+				if record.Spec.ProviderRef.Name == o.GetName() || (record.Spec.MigrateTo != nil && record.Spec.MigrateTo.Name == o.GetName()) {
 					logger.Info("secret updated", "secret", o.GetNamespace()+"/"+o.GetName(), "enqueuing dnsrecord ", record.GetName())
 					toReconcile = append(toReconcile, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&record)})
 				}
@@ -423,8 +879,47 @@ func (r *DNSRecordReconciler) SetupWithManager(mgr ctrl.Manager, maxRequeue, val
 			}
 			// nothing to do
 			return []reconcile.Request{}
-		})).
-		Complete(r)
+		}))
+
+	if r.WatchNamespaceSelector != nil {
+		b = b.Watches(&v1.Namespace{}, enqueueNamespaceObjects(mgr.GetClient(), &v1alpha1.DNSRecordList{}, func(l client.ObjectList) []reconcile.Request {
+			var toReconcile []reconcile.Request
+			for _, record := range l.(*v1alpha1.DNSRecordList).Items {
+				toReconcile = append(toReconcile, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&record)})
+			}
+			return toReconcile
+		}), namespaceLabelsChanged)
+	}
+
+	// gateway endpoints can only be updated when gateway addresses change if the Gateway API is installed
+	if _, err := mgr.GetRESTMapper().RESTMapping(schema.GroupKind{Group: gatewayapiv1.GroupName, Kind: "Gateway"}, gatewayapiv1.GroupVersion.Version); err != nil {
+		if !meta.IsNoMatchError(err) {
+			return err
+		}
+		log.Log.Info("Gateway API is not installed, gateway endpoints will not be updated when gateway addresses change")
+	} else {
+		b = b.Watches(&gatewayapiv1.Gateway{}, handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, o client.Object) []reconcile.Request {
+			logger := log.FromContext(ctx)
+			var toReconcile []reconcile.Request
+			// gateway endpoints can only refer to gateways in the same namespace as the record
+			records := &v1alpha1.DNSRecordList{}
+			if err := mgr.GetClient().List(ctx, records, &client.ListOptions{Namespace: o.GetNamespace()}); err != nil {
+				logger.Error(err, "failed to list dnsrecords ", "namespace", o.GetNamespace())
+				return toReconcile
+			}
+			for _, record := range records.Items {
+				for _, gatewayEndpoint := range record.Spec.GatewayEndpoints {
+					if gatewayEndpoint.GatewayName == o.GetName() {
+						toReconcile = append(toReconcile, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&record)})
+						break
+					}
+				}
+			}
+			return toReconcile
+		}))
+	}
+
+	return b.Complete(r)
 }
 
 // deleteRecord deletes record(s) in the DNSPRovider(i.e. route53) zone (dnsRecord.Status.ZoneID).
@@ -452,7 +947,8 @@ func (r *DNSRecordReconciler) deleteRecord(ctx context.Context, dnsRecord *v1alp
 func (r *DNSRecordReconciler) publishRecord(ctx context.Context, dnsRecord *v1alpha1.DNSRecord, probes *v1alpha1.DNSHealthCheckProbeList, dnsProvider provider.Provider) (bool, []string, error) {
 	logger := log.FromContext(ctx)
 
-	if prematurely, _ := recordReceivedPrematurely(dnsRecord, probes); prematurely {
+	// a record migrating its owner ID is published right away so its registry TXT records are rewritten
+	if prematurely, _ := recordReceivedPrematurely(dnsRecord, probes); prematurely && dnsRecord.Status.PreviousOwnerID == "" {
 		logger.V(1).Info("Skipping DNSRecord - is still valid")
 		return false, []string{}, nil
 	}
@@ -463,6 +959,12 @@ func (r *DNSRecordReconciler) publishRecord(ctx context.Context, dnsRecord *v1al
 	}
 	logger.Info("Published DNSRecord to zone")
 
+	// all the registry TXT records of the previous owner ID are rewritten by a successful publish
+	if dnsRecord.Status.PreviousOwnerID != "" {
+		logger.Info("Owner ID migrated", "previousOwnerID", dnsRecord.Status.PreviousOwnerID)
+		dnsRecord.Status.PreviousOwnerID = ""
+	}
+
 	return hadChanges, notHealthyProbes, nil
 }
 
@@ -479,7 +981,7 @@ func recordReceivedPrematurely(record *v1alpha1.DNSRecord, probes *v1alpha1.DNSH
 		requeueIn, _ = time.ParseDuration(record.Status.ValidFor)
 	}
 	expiryTime := metav1.NewTime(record.Status.QueuedAt.Add(requeueIn))
-	prematurely = !generationChanged(record) && reconcileStart.Before(&expiryTime)
+	prematurely = !generationChanged(record) && !reconcileRequested(record) && !isDriftAcknowledged(record) && reconcileStart.Before(&expiryTime)
 
 	// Check for the exception if we are received prematurely.
 	// This cuts off all the cases when we are creating.
@@ -511,6 +1013,11 @@ func generationChanged(record *v1alpha1.DNSRecord) bool {
 	return record.Generation != record.Status.ObservedGeneration
 }
 
+// reconcileRequested returns true if the reconcile request annotation of the record has changed since it was last handled
+func reconcileRequested(record *v1alpha1.DNSRecord) bool {
+	return record.GetAnnotations()[v1alpha1.ReconcileRequestAnnotation] != record.Status.LastHandledReconcileRequest
+}
+
 // exponentialRequeueTime consumes the current time and doubles it until it reaches defaultRequeueTime
 func exponentialRequeueTime(lastRequeueTime string) time.Duration {
 	lastRequeue, err := time.ParseDuration(lastRequeueTime)
@@ -537,6 +1044,11 @@ func setStatusConditions(record *v1alpha1.DNSRecord, hadChanges bool, notHealthy
 		return
 	}
 
+	if propagationPending(record) {
+		setDNSRecordCondition(record, string(v1alpha1.ConditionTypeReady), metav1.ConditionFalse, string(v1alpha1.ConditionReasonAwaitingPropagation), "Awaiting propagation of changes to the provider nameservers")
+		return
+	}
+
 	setDNSRecordCondition(record, string(v1alpha1.ConditionTypeReady), metav1.ConditionTrue, string(v1alpha1.ConditionReasonProviderSuccess), "Provider ensured the dns record")
 
 	// probes are disabled or not defined, or this is a wildcard record
@@ -572,6 +1084,80 @@ func setStatusConditions(record *v1alpha1.DNSRecord, hadChanges bool, notHealthy
 
 }
 
+// setStatusPhase computes the phase of the given DNSRecord from its current conditions and the given reconcile error.
+func setStatusPhase(record *v1alpha1.DNSRecord, err error) {
+	record.Status.Phase = statusPhase(record, err)
+}
+
+func statusPhase(record *v1alpha1.DNSRecord, err error) v1alpha1.DNSRecordPhase {
+	if record.DeletionTimestamp != nil && !record.DeletionTimestamp.IsZero() {
+		return v1alpha1.DNSRecordPhaseDeleting
+	}
+	if errors.Is(err, externaldnsplan.ErrOwnerConflict) || errors.Is(err, externaldnsplan.ErrRecordTypeConflict) {
+		return v1alpha1.DNSRecordPhaseConflict
+	}
+
+	readyCond := meta.FindStatusCondition(record.Status.Conditions, string(v1alpha1.ConditionTypeReady))
+	if readyCond == nil {
+		return v1alpha1.DNSRecordPhasePending
+	}
+
+	if readyCond.Status == metav1.ConditionTrue {
+		healthyCond := meta.FindStatusCondition(record.Status.Conditions, string(v1alpha1.ConditionTypeHealthy))
+		if healthyCond != nil && healthyCond.Status != metav1.ConditionTrue {
+			return v1alpha1.DNSRecordPhaseDegraded
+		}
+		return v1alpha1.DNSRecordPhaseReady
+	}
+
+	switch readyCond.Reason {
+	case string(v1alpha1.ConditionReasonAwaitingValidation), string(v1alpha1.ConditionReasonAwaitingPropagation):
+		return v1alpha1.DNSRecordPhasePublishing
+	case string(v1alpha1.ConditionReasonUnhealthy):
+		return v1alpha1.DNSRecordPhaseDegraded
+	}
+
+	// any other failure is degraded if we have previously published endpoints for this record
+	if len(record.Status.Endpoints) > 0 {
+		return v1alpha1.DNSRecordPhaseDegraded
+	}
+	return v1alpha1.DNSRecordPhasePending
+}
+
+// setProviderError sets the provider error of the record to the classification of the given error returned by its
+// provider.
+func (r *DNSRecordReconciler) setProviderError(ctx context.Context, dnsRecord *v1alpha1.DNSRecord, err error) {
+	name := r.providerNameFor(ctx, dnsRecord)
+	code := provider.ClassifyError(name, err)
+	dnsRecord.Status.ProviderError = &v1alpha1.ProviderError{
+		Code:      string(code),
+		Provider:  name,
+		ZoneID:    dnsRecord.Status.ZoneID,
+		Retryable: code.Retryable(),
+	}
+}
+
+// providerNameFor returns the name of the provider of the provider secret of the given record, empty if the secret
+// can't be read.
+func (r *DNSRecordReconciler) providerNameFor(ctx context.Context, dnsRecord *v1alpha1.DNSRecord) string {
+	var name string
+	secret := &v1.Secret{}
+	if r.Get(ctx, client.ObjectKey{Namespace: dnsRecord.Namespace, Name: dnsRecord.Spec.ProviderRef.Name}, secret) == nil {
+		name, _ = provider.NameForProviderSecret(secret)
+	}
+	return name
+}
+
+// txtRegistryFormatFor returns the format of the registry TXT records declared in the provider secret of the given
+// record.
+func (r *DNSRecordReconciler) txtRegistryFormatFor(ctx context.Context, dnsRecord *v1alpha1.DNSRecord) (externaldnsregistry.TXTFormat, error) {
+	secret := &v1.Secret{}
+	if err := r.Get(ctx, client.ObjectKey{Namespace: dnsRecord.Namespace, Name: dnsRecord.Spec.ProviderRef.Name}, secret); err != nil {
+		return "", err
+	}
+	return externaldnsregistry.ParseTXTFormat(string(secret.Data[v1alpha1.TXTRegistryFormatKey]))
+}
+
 // setDNSRecordCondition adds or updates a given condition in the DNSRecord status.
 func setDNSRecordCondition(dnsRecord *v1alpha1.DNSRecord, conditionType string, status metav1.ConditionStatus, reason, message string) {
 	cond := metav1.Condition{
@@ -584,6 +1170,16 @@ func setDNSRecordCondition(dnsRecord *v1alpha1.DNSRecord, conditionType string,
 	meta.SetStatusCondition(&dnsRecord.Status.Conditions, cond)
 }
 
+// validateUnownedPublish returns an error if the given record is not allowed to be published without ownership.
+// The root host of the record must belong to one of the domains allowed for unowned publishing.
+func (r *DNSRecordReconciler) validateUnownedPublish(dnsRecord *v1alpha1.DNSRecord) error {
+	rootHost, _ := strings.CutPrefix(dnsRecord.Spec.RootHost, v1alpha1.WildcardPrefix)
+	if len(r.UnownedPublishDomains) == 0 || !externaldnsendpoint.NewDomainFilter(r.UnownedPublishDomains).Match(rootHost) {
+		return fmt.Errorf("root host %s is not in a domain allowed for unowned publishing", dnsRecord.Spec.RootHost)
+	}
+	return nil
+}
+
 // getDNSProvider returns a Provider configured for the given DNSRecord
 // If no zone/id/domain has been assigned to the given record, an error is thrown.
 // If no owner has been assigned to the given record, an error is thrown.
@@ -613,15 +1209,37 @@ func (r *DNSRecordReconciler) applyChanges(ctx context.Context, dnsRecord *v1alp
 	logger := log.FromContext(ctx)
 	rootDomainName := dnsRecord.Spec.RootHost
 	zoneDomainFilter := externaldnsendpoint.NewDomainFilter([]string{dnsRecord.Status.ZoneDomainName})
-	managedDNSRecordTypes := []string{externaldnsendpoint.RecordTypeA, externaldnsendpoint.RecordTypeAAAA, externaldnsendpoint.RecordTypeCNAME}
+	managedDNSRecordTypes := managedRecordTypesFor(dnsRecord)
 	var excludeDNSRecordTypes []string
 
-	registry, err := externaldnsregistry.NewTXTRegistry(ctx, dnsProvider, txtRegistryPrefix, txtRegistrySuffix,
-		dnsRecord.Status.OwnerID, txtRegistryCacheInterval, txtRegistryWildcardReplacement, managedDNSRecordTypes,
-		excludeDNSRecordTypes, txtRegistryEncryptEnabled, []byte(txtRegistryEncryptAESKey))
+	completeRegistry := r.startPhase(ctx, ReconcilePhaseRegistry)
+	registry, err := authoritative.NewRegistry(ctx, dnsProvider, dnsRecord.Status.OwnerID, managedDNSRecordTypes)
 	if err != nil {
 		return false, []string{}, err
 	}
+	registry.WithResourceLabels(externaldnsendpoint.Labels{
+		externaldnsendpoint.ResourceLabelKey:    fmt.Sprintf("dnsrecord/%s/%s", dnsRecord.Namespace, dnsRecord.Name),
+		externaldnsregistry.ResourceUIDLabelKey: string(dnsRecord.UID),
+	})
+	if dnsRecord.Spec.RegistryZoneRef != nil {
+		registryZoneProvider, err := r.getRegistryZoneProvider(ctx, dnsRecord)
+		if err != nil {
+			return false, []string{}, fmt.Errorf("registry zone: %w", err)
+		}
+		registry.WithRegistryZone(registryZoneProvider, dnsRecord.Spec.RegistryZoneRef.DomainName)
+	}
+	txtFormat, err := r.txtRegistryFormatFor(ctx, dnsRecord)
+	if err != nil {
+		return false, []string{}, err
+	}
+	registry.WithTXTFormat(txtFormat)
+	if dnsRecord.Status.PreviousOwnerID != "" {
+		registry.WithOwnerRename(dnsRecord.Status.PreviousOwnerID)
+	}
+	// endpoints are only adopted while they are published, on deletion those still owned by another instance are left
+	if dnsRecord.Spec.AdoptFrom != nil && !isDelete {
+		registry.WithOwnerAdoption(r.ownerAdoptionFor(dnsRecord))
+	}
 
 	policyID := "sync"
 	policy, exists := externaldnsplan.Policies[policyID]
@@ -635,10 +1253,25 @@ func (r *DNSRecordReconciler) applyChanges(ctx context.Context, dnsRecord *v1alp
 	}
 
 	//zoneEndpoints = Records in the current dns provider zone
-	zoneEndpoints, err := registry.Records(ctx)
+	var zoneEndpoints, registryEndpoints []*externaldnsendpoint.Endpoint
+	var zoneRecordCounts externaldnsregistry.ZoneRecordCounts
+	dedicated := dnsRecord.IsDedicatedZone()
+	if dedicated {
+		// dedicated zones are read without the registry, registry TXT records left from before the zone was dedicated
+		// are removed
+		zoneEndpoints, err = dnsProvider.Records(ctx)
+		zoneRecordCounts.Records = len(zoneEndpoints)
+		registryEndpoints, zoneEndpoints = splitRegistryRecords(zoneEndpoints, dnsRecord.Status.OwnerID)
+		zoneRecordCounts.Registry = len(registryEndpoints)
+	} else {
+		zoneEndpoints, err = registry.Records(ctx)
+		zoneRecordCounts = registry.ZoneRecordCounts()
+	}
 	if err != nil {
 		return false, []string{}, err
 	}
+	// the state record of the zone is written by every owner publishing to the zone
+	_, zoneEndpoints = splitZoneState(zoneEndpoints, dnsRecord.Status.ZoneDomainName)
 
 	//specEndpoints = Records that this DNSRecord expects to exist
 	specEndpoints, err := registry.AdjustEndpoints(dnsRecord.Spec.Endpoints)
@@ -646,6 +1279,31 @@ func (r *DNSRecordReconciler) applyChanges(ctx context.Context, dnsRecord *v1alp
 		return false, []string{}, fmt.Errorf("adjusting specEndpoints: %w", err)
 	}
 
+	// remove the endpoints and targets that must never be published
+	exclusions, err := r.endpointExclusionsFor(dnsRecord)
+	if err != nil {
+		return false, []string{}, err
+	}
+	specEndpoints, excluded := exclusions.filter(specEndpoints)
+	if r.excluded.changed(client.ObjectKeyFromObject(dnsRecord), excluded) {
+		logger.Info("WARNING: excluded endpoints will not be published", "excluded", excluded)
+		r.recorder.Eventf(dnsRecord, v1.EventTypeWarning, "EndpointsExcluded", "Excluded endpoints will not be published: %s", strings.Join(excluded, ", "))
+	}
+
+	// smear the expiry of cached endpoints, the status keeps the published TTLs
+	jitter, err := dnsRecord.TTLJitter()
+	if err != nil {
+		return false, []string{}, err
+	}
+	specEndpoints = jitterTTLs(specEndpoints, jitter)
+
+	// endpoints of record types the provider can't publish are rejected rather than silently never published
+	if !isDelete {
+		if err = provider.ValidateRecordTypes(dnsProvider, specEndpoints); err != nil {
+			return false, []string{}, err
+		}
+	}
+
 	// healthySpecEndpoints = Records that this DNSRecord expects to exist, that do not have matching unhealthy probes
 	healthySpecEndpoints, notHealthyProbes, err := removeUnhealthyEndpoints(specEndpoints, dnsRecord, probes)
 	if err != nil {
@@ -657,6 +1315,16 @@ func (r *DNSRecordReconciler) applyChanges(ctx context.Context, dnsRecord *v1alp
 	if err != nil {
 		return false, []string{}, fmt.Errorf("adjusting statusEndpoints: %w", err)
 	}
+	completeRegistry()
+
+	// targets removed from the spec are kept in the zone until they expire from the caches of resolvers
+	if !isDelete {
+		var started []string
+		healthySpecEndpoints, started = lingerRemovedTargets(dnsRecord, specEndpoints, healthySpecEndpoints, statusEndpoints, reconcileStart.Time)
+		if len(started) > 0 {
+			r.recorder.Eventf(dnsRecord, v1.EventTypeNormal, "TargetsLingering", "Removed targets are kept for one TTL: %s", strings.Join(started, ", "))
+		}
+	}
 
 	// add related endpoints to the record
 	dnsRecord.Status.ZoneEndpoints = mergeZoneEndpoints(
@@ -667,25 +1335,128 @@ func (r *DNSRecordReconciler) applyChanges(ctx context.Context, dnsRecord *v1alp
 	logger.V(1).Info("applyChanges", "zoneEndpoints", zoneEndpoints,
 		"specEndpoints", healthySpecEndpoints, "statusEndpoints", statusEndpoints)
 
+	// unowned records are planned without an owner, so they can't take over records owned by others,
+	// and are written directly to the provider without registry TXT records
+	// records of dedicated zones are planned without an owner as well, so every record in the zone that is not desired
+	// is removed
+	ownerID := registry.OwnerID()
+	unowned := dnsRecord.IsUnownedPublish()
+	if unowned || dedicated {
+		ownerID = ""
+	}
+
+	// the endpoints published last are left as they are in the zone if they were changed by someone else
+	if !isDelete && !unowned {
+		if err = r.checkDrift(dnsRecord, dnsProvider, zoneEndpoints, statusEndpoints, ownerID); err != nil {
+			return false, notHealthyProbes, err
+		}
+	}
+
+	completePlan := r.startPhase(ctx, ReconcilePhasePlan)
 	plan := externaldnsplan.NewPlan(ctx, zoneEndpoints, statusEndpoints, healthySpecEndpoints, []externaldnsplan.Policy{policy},
 		externaldnsendpoint.MatchAllDomainFilters{&zoneDomainFilter}, managedDNSRecordTypes, excludeDNSRecordTypes,
-		registry.OwnerID(), &rootDomainName,
+		ownerID, &rootDomainName,
 	)
+	if normalizer, ok := provider.As[provider.TargetNormalizer](dnsProvider); ok {
+		plan.TargetNormalizer = normalizer.NormalizeTarget
+	}
+	// all desired endpoints are written again to repair records suspected to be corrupted in the provider
+	forceApply := dnsRecord.IsForceApply() && !isDelete
+	plan.ForceUpdate = forceApply
 
 	plan = plan.Calculate()
 	if err = plan.Error(); err != nil {
 		return false, notHealthyProbes, err
 	}
+	if unowned {
+		// unowned records are never deleted
+		plan.Changes.Delete = []*externaldnsendpoint.Endpoint{}
+		plan.Owners = nil
+	}
+	if dedicated {
+		// on deletion only the endpoints published by the record are removed, in case the zone is no longer dedicated
+		if isDelete {
+			plan.Changes.Delete = publishedEndpoints(plan.Changes.Delete, statusEndpoints)
+		}
+		plan.Changes.Delete = append(plan.Changes.Delete, registryEndpoints...)
+		plan.Owners = nil
+	}
+	// endpoints shared with other owners are only taken over once confirmed
+	if !isDelete && !unowned && !dedicated {
+		if err = r.checkTakeovers(dnsRecord, plan.Takeovers); err != nil {
+			return false, notHealthyProbes, err
+		}
+	}
+	// simulate the responses for the RRsets of the record before anything is written
+	if !isDelete {
+		if err = r.checkRRsetSizes(dnsRecord, dnsProvider, resultingRRsets(healthySpecEndpoints, zoneEndpoints, plan.Changes)); err != nil {
+			return false, notHealthyProbes, err
+		}
+		if err = r.checkZoneRecordPressure(ctx, dnsRecord, zoneRecordCounts, txtFormat); err != nil {
+			return false, notHealthyProbes, err
+		}
+	}
 	dnsRecord.Status.DomainOwners = plan.Owners
 	dnsRecord.Status.Endpoints = healthySpecEndpoints
+	if !isDelete {
+		setPlannedEndpointStatuses(dnsRecord, specEndpoints, healthySpecEndpoints)
+	}
+	completePlan()
+	if forceApply {
+		r.recorder.Eventf(dnsRecord, v1.EventTypeNormal, "ForceApply", "Writing %d created and %d updated endpoints as requested with %s",
+			len(plan.Changes.Create), len(plan.Changes.UpdateNew), v1alpha1.ForceApplyAnnotation)
+	}
 	if plan.Changes.HasChanges() {
-		logger.Info("Applying changes")
-		err = registry.ApplyChanges(ctx, plan.Changes)
+		explanations := plan.Explain()
+		changes := describeChanges(explanations)
+		logger.Info("Applying changes", "changes", changes)
+		r.recorder.Eventf(dnsRecord, v1.EventTypeNormal, "ApplyingChanges", "Applying %d changes: %s",
+			len(changes), summarizeChanges(changes))
+		// updates to records that have already been reconciled at this generation are churn, e.g. provider formatted
+		// values that are not recognised as equal to the desired values
+		if dnsRecord.Generation == dnsRecord.Status.ObservedGeneration && len(plan.Changes.UpdateNew) > 0 {
+			metrics.UpdateChurnCounter.WithLabelValues(dnsRecord.Name, dnsRecord.Namespace).Add(float64(len(plan.Changes.UpdateNew)))
+		}
+		completeApply := r.startPhase(ctx, ReconcilePhaseApply)
+		if unowned || dedicated {
+			err = dnsProvider.ApplyChanges(ctx, plan.Changes)
+		} else {
+			err = registry.ApplyChanges(ctx, plan.Changes)
+		}
+		if err == nil {
+			completeApply()
+		}
+		if err == nil && r.ZoneStateRecord {
+			r.updateZoneState(ctx, dnsRecord, dnsProvider)
+		}
+		if err == nil && r.changeNotifier != nil {
+			r.changeNotifier.notify(ctx, dnsRecord, explanations)
+		}
 		return true, notHealthyProbes, err
 	}
 	return false, notHealthyProbes, nil
 }
 
+// maxChangesInEvent is the maximum number of changes described in the event of the changes applied by a reconcile.
+const maxChangesInEvent = 10
+
+// describeChanges returns the description of each explained change, e.g. "update A foo.example.com (TTLChange)".
+func describeChanges(explanations []externaldnsplan.ChangeExplanation) []string {
+	changes := make([]string, 0, len(explanations))
+	for _, explanation := range explanations {
+		changes = append(changes, explanation.String())
+	}
+	return changes
+}
+
+// summarizeChanges joins up to maxChangesInEvent change descriptions, so events stay short for large records.
+func summarizeChanges(changes []string) string {
+	if len(changes) <= maxChangesInEvent {
+		return strings.Join(changes, "; ")
+	}
+	return fmt.Sprintf("%s and %d more", strings.Join(changes[:maxChangesInEvent], "; "), len(changes)-maxChangesInEvent)
+}
+
 // filterEndpoints takes a list of zoneEndpoints and removes from it all endpoints
 // that do not belong to the rootDomainName (some.example.com does belong to the example.com domain).
 // it is not using ownerID of this record as well as domainOwners from the status for filtering
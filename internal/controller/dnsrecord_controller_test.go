@@ -34,6 +34,8 @@ import (
 	externaldnsendpoint "sigs.k8s.io/external-dns/endpoint"
 
 	"github.com/kuadrant/dns-operator/api/v1alpha1"
+	"github.com/kuadrant/dns-operator/internal/provider"
+	"github.com/kuadrant/dns-operator/internal/provider/inmemory"
 	"github.com/kuadrant/dns-operator/pkg/builder"
 )
 
@@ -254,6 +256,381 @@ var _ = Describe("DNSRecordReconciler", func() {
 		}, TestTimeoutMedium, time.Second, ctx).Should(Succeed())
 	})
 
+	Context("unowned publish", func() {
+		It("should not publish a record without ownership outside of the allowed domains", func(ctx SpecContext) {
+			dnsRecord.Annotations = map[string]string{v1alpha1.UnownedPublishAnnotation: "true"}
+			Expect(k8sClient.Create(ctx, dnsRecord)).To(Succeed())
+			Eventually(func(g Gomega) {
+				err := k8sClient.Get(ctx, client.ObjectKeyFromObject(dnsRecord), dnsRecord)
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(dnsRecord.Status.Conditions).To(
+					ContainElement(MatchFields(IgnoreExtras, Fields{
+						"Type":    Equal(string(v1alpha1.ConditionTypeReady)),
+						"Status":  Equal(metav1.ConditionFalse),
+						"Reason":  Equal("ValidationError"),
+						"Message": ContainSubstring("is not in a domain allowed for unowned publishing"),
+					})),
+				)
+			}, TestTimeoutMedium, time.Second).Should(Succeed())
+		})
+
+		It("should publish a record without ownership in an allowed domain and not delete it", func(ctx SpecContext) {
+			unownedZoneDomainName := strings.Join([]string{GenerateName(), unownedPublishDomain}, ".")
+			unownedHostname := strings.Join([]string{"foo", unownedZoneDomainName}, ".")
+			unownedProviderSecret := builder.NewProviderBuilder("inmemory-credentials-unowned", testNamespace).
+				For(v1alpha1.SecretTypeKuadrantInmemory).
+				WithZonesInitialisedFor(unownedZoneDomainName).
+				Build()
+			Expect(k8sClient.Create(ctx, unownedProviderSecret)).To(Succeed())
+
+			dnsRecord = &v1alpha1.DNSRecord{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        unownedHostname,
+					Namespace:   testNamespace,
+					Annotations: map[string]string{v1alpha1.UnownedPublishAnnotation: "true"},
+				},
+				Spec: v1alpha1.DNSRecordSpec{
+					RootHost: unownedHostname,
+					ProviderRef: v1alpha1.ProviderRef{
+						Name: unownedProviderSecret.Name,
+					},
+					Endpoints: getTestEndpoints(unownedHostname, []string{"127.0.0.1"}),
+				},
+			}
+			Expect(k8sClient.Create(ctx, dnsRecord)).To(Succeed())
+			Eventually(func(g Gomega) {
+				err := k8sClient.Get(ctx, client.ObjectKeyFromObject(dnsRecord), dnsRecord)
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(dnsRecord.Status.Conditions).To(
+					ContainElement(MatchFields(IgnoreExtras, Fields{
+						"Type":   Equal(string(v1alpha1.ConditionTypeReady)),
+						"Status": Equal(metav1.ConditionTrue),
+					})),
+				)
+				g.Expect(dnsRecord.Status.Conditions).To(
+					ContainElement(MatchFields(IgnoreExtras, Fields{
+						"Type":   Equal(string(v1alpha1.ConditionTypeUnowned)),
+						"Status": Equal(metav1.ConditionTrue),
+						"Reason": Equal(string(v1alpha1.ConditionReasonUnownedPublish)),
+					})),
+				)
+				g.Expect(dnsRecord.Status.DomainOwners).To(BeEmpty())
+			}, TestTimeoutMedium, time.Second).Should(Succeed())
+
+			Expect(k8sClient.Delete(ctx, dnsRecord)).To(Succeed())
+			Eventually(func(g Gomega, ctx context.Context) {
+				err := k8sClient.Get(ctx, client.ObjectKeyFromObject(dnsRecord), dnsRecord)
+				g.Expect(err).To(MatchError(ContainSubstring("not found")))
+			}, TestTimeoutMedium, time.Second, ctx).Should(Succeed())
+
+			// the endpoints are left in the zone once the record is deleted
+			dnsProvider, err := inmemory.NewProviderFromSecret(ctx, unownedProviderSecret, provider.Config{})
+			Expect(err).NotTo(HaveOccurred())
+			records, err := dnsProvider.Records(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(records).To(ContainElement(PointTo(MatchFields(IgnoreExtras, Fields{
+				"DNSName":    Equal(unownedHostname),
+				"RecordType": Equal(externaldnsendpoint.RecordTypeA),
+				"Targets":    ConsistOf("127.0.0.1"),
+			}))))
+		})
+	})
+
+	It("should not publish a record in a zone outside the allowed domains of the provider secret", func(ctx SpecContext) {
+		allowedProviderSecret := builder.NewProviderBuilder("inmemory-credentials-allowed", testNamespace).
+			For(v1alpha1.SecretTypeKuadrantInmemory).
+			WithZonesInitialisedFor(testZoneDomainName).
+			WithAllowedDomains("example.org").
+			Build()
+		Expect(k8sClient.Create(ctx, allowedProviderSecret)).To(Succeed())
+
+		dnsRecord.Spec.ProviderRef.Name = allowedProviderSecret.Name
+		Expect(k8sClient.Create(ctx, dnsRecord)).To(Succeed())
+		Eventually(func(g Gomega) {
+			err := k8sClient.Get(ctx, client.ObjectKeyFromObject(dnsRecord), dnsRecord)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(dnsRecord.Status.Conditions).To(
+				ContainElement(MatchFields(IgnoreExtras, Fields{
+					"Type":    Equal(string(v1alpha1.ConditionTypeReady)),
+					"Status":  Equal(metav1.ConditionFalse),
+					"Reason":  Equal(string(v1alpha1.ConditionReasonDomainNotAllowed)),
+					"Message": ContainSubstring("is not in the allowed domains example.org"),
+				})),
+			)
+			g.Expect(dnsRecord.Status.ZoneID).To(BeEmpty())
+		}, TestTimeoutMedium, time.Second).Should(Succeed())
+	})
+
+	It("should not publish a record with an unknown TXT registry format in the provider secret", func(ctx SpecContext) {
+		formatProviderSecret := builder.NewProviderBuilder("inmemory-credentials-format", testNamespace).
+			For(v1alpha1.SecretTypeKuadrantInmemory).
+			WithZonesInitialisedFor(testZoneDomainName).
+			WithTXTRegistryFormat("v3").
+			Build()
+		Expect(k8sClient.Create(ctx, formatProviderSecret)).To(Succeed())
+
+		dnsRecord.Spec.ProviderRef.Name = formatProviderSecret.Name
+		Expect(k8sClient.Create(ctx, dnsRecord)).To(Succeed())
+		Eventually(func(g Gomega) {
+			err := k8sClient.Get(ctx, client.ObjectKeyFromObject(dnsRecord), dnsRecord)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(dnsRecord.Status.Conditions).To(
+				ContainElement(MatchFields(IgnoreExtras, Fields{
+					"Type":    Equal(string(v1alpha1.ConditionTypeReady)),
+					"Status":  Equal(metav1.ConditionFalse),
+					"Reason":  Equal("ValidationError"),
+					"Message": ContainSubstring("unknown TXT registry format"),
+				})),
+			)
+		}, TestTimeoutMedium, time.Second).Should(Succeed())
+	})
+
+	Context("dedicated zone", func() {
+		It("should publish a record as the only writer to its zone and refuse other records in the zone", func(ctx SpecContext) {
+			dnsRecord.Annotations = map[string]string{v1alpha1.DedicatedZoneAnnotation: "true"}
+			Expect(k8sClient.Create(ctx, dnsRecord)).To(Succeed())
+			Eventually(func(g Gomega) {
+				err := k8sClient.Get(ctx, client.ObjectKeyFromObject(dnsRecord), dnsRecord)
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(dnsRecord.Status.Conditions).To(
+					ContainElement(MatchFields(IgnoreExtras, Fields{
+						"Type":   Equal(string(v1alpha1.ConditionTypeReady)),
+						"Status": Equal(metav1.ConditionTrue),
+					})),
+				)
+				g.Expect(dnsRecord.Status.Conditions).To(
+					ContainElement(MatchFields(IgnoreExtras, Fields{
+						"Type":   Equal(string(v1alpha1.ConditionTypeDedicatedZone)),
+						"Status": Equal(metav1.ConditionTrue),
+						"Reason": Equal(string(v1alpha1.ConditionReasonDedicatedZonePublish)),
+					})),
+				)
+				g.Expect(dnsRecord.Status.DomainOwners).To(BeEmpty())
+			}, TestTimeoutMedium, time.Second).Should(Succeed())
+
+			barHostname := strings.Join([]string{"bar", testZoneDomainName}, ".")
+			dnsRecord2 = &v1alpha1.DNSRecord{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        barHostname,
+					Namespace:   testNamespace,
+					Annotations: map[string]string{v1alpha1.DedicatedZoneAnnotation: "true"},
+				},
+				Spec: v1alpha1.DNSRecordSpec{
+					RootHost: barHostname,
+					ProviderRef: v1alpha1.ProviderRef{
+						Name: dnsProviderSecret.Name,
+					},
+					Endpoints: getTestEndpoints(barHostname, []string{"127.0.0.2"}),
+				},
+			}
+			Expect(k8sClient.Create(ctx, dnsRecord2)).To(Succeed())
+			Eventually(func(g Gomega) {
+				err := k8sClient.Get(ctx, client.ObjectKeyFromObject(dnsRecord2), dnsRecord2)
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(dnsRecord2.Status.Conditions).To(
+					ContainElement(MatchFields(IgnoreExtras, Fields{
+						"Type":    Equal(string(v1alpha1.ConditionTypeReady)),
+						"Status":  Equal(metav1.ConditionFalse),
+						"Reason":  Equal("DedicatedZoneConflict"),
+						"Message": ContainSubstring("is also assigned to DNSRecord"),
+					})),
+				)
+			}, TestTimeoutMedium, time.Second).Should(Succeed())
+		})
+	})
+
+	It("should not publish a record with a gateway endpoint for a gateway that can't be found", func(ctx SpecContext) {
+		dnsRecord.Spec.GatewayEndpoints = []v1alpha1.GatewayEndpoint{
+			{
+				DNSName:     "gw." + testHostname,
+				GatewayName: "missing",
+			},
+		}
+		Expect(k8sClient.Create(ctx, dnsRecord)).To(Succeed())
+		Eventually(func(g Gomega) {
+			err := k8sClient.Get(ctx, client.ObjectKeyFromObject(dnsRecord), dnsRecord)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(dnsRecord.Status.Conditions).To(
+				ContainElement(MatchFields(IgnoreExtras, Fields{
+					"Type":    Equal(string(v1alpha1.ConditionTypeReady)),
+					"Status":  Equal(metav1.ConditionFalse),
+					"Reason":  Equal("GatewayEndpointError"),
+					"Message": ContainSubstring("unable to get gateway missing"),
+				})),
+			)
+		}, TestTimeoutMedium, time.Second).Should(Succeed())
+	})
+
+	It("should not migrate a record to the zone it is already published in", func(ctx SpecContext) {
+		migrationSecret := builder.NewProviderBuilder("inmemory-credentials-migration", testNamespace).
+			For(v1alpha1.SecretTypeKuadrantInmemory).
+			WithZonesInitialisedFor(testZoneDomainName).
+			Build()
+		Expect(k8sClient.Create(ctx, migrationSecret)).To(Succeed())
+
+		Expect(k8sClient.Create(ctx, dnsRecord)).To(Succeed())
+		Eventually(func(g Gomega) {
+			err := k8sClient.Get(ctx, client.ObjectKeyFromObject(dnsRecord), dnsRecord)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(dnsRecord.Status.ZoneID).To(Equal(testZoneID))
+		}, TestTimeoutMedium, time.Second).Should(Succeed())
+
+		Eventually(func(g Gomega) {
+			err := k8sClient.Get(ctx, client.ObjectKeyFromObject(dnsRecord), dnsRecord)
+			g.Expect(err).NotTo(HaveOccurred())
+			dnsRecord.Spec.MigrateTo = &v1alpha1.ProviderRef{Name: migrationSecret.Name}
+			g.Expect(k8sClient.Update(ctx, dnsRecord)).To(Succeed())
+		}, TestTimeoutMedium, time.Second).Should(Succeed())
+
+		Eventually(func(g Gomega) {
+			err := k8sClient.Get(ctx, client.ObjectKeyFromObject(dnsRecord), dnsRecord)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(dnsRecord.Status.Conditions).To(
+				ContainElement(MatchFields(IgnoreExtras, Fields{
+					"Type":    Equal(string(v1alpha1.ConditionTypeMigrating)),
+					"Status":  Equal(metav1.ConditionFalse),
+					"Reason":  Equal(string(v1alpha1.ConditionReasonMigrationFailed)),
+					"Message": ContainSubstring("the record is already published in"),
+				})),
+			)
+			g.Expect(dnsRecord.Spec.ProviderRef.Name).To(Equal(dnsProviderSecret.Name))
+		}, TestTimeoutMedium, time.Second).Should(Succeed())
+	})
+
+	It("should not publish excluded endpoint targets", func(ctx SpecContext) {
+		dnsRecord.Spec.Endpoints = getTestEndpoints(testHostname, []string{"127.0.0.1", "10.1.2.3"})
+		dnsRecord.Spec.ExcludeTargetCIDRs = []string{"10.0.0.0/8"}
+		Expect(k8sClient.Create(ctx, dnsRecord)).To(Succeed())
+		Eventually(func(g Gomega) {
+			err := k8sClient.Get(ctx, client.ObjectKeyFromObject(dnsRecord), dnsRecord)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(dnsRecord.Status.Conditions).To(
+				ContainElement(MatchFields(IgnoreExtras, Fields{
+					"Type":   Equal(string(v1alpha1.ConditionTypeReady)),
+					"Status": Equal(metav1.ConditionTrue),
+				})),
+			)
+			g.Expect(dnsRecord.Status.Endpoints).To(ConsistOf(
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"DNSName": Equal(testHostname),
+					"Targets": ConsistOf("127.0.0.1"),
+				})),
+			))
+		}, TestTimeoutMedium, time.Second).Should(Succeed())
+	})
+
+	It("should reconcile a record immediately when a reconcile is requested", func(ctx SpecContext) {
+		Expect(k8sClient.Create(ctx, dnsRecord)).To(Succeed())
+		var queuedAt metav1.Time
+		Eventually(func(g Gomega) {
+			err := k8sClient.Get(ctx, client.ObjectKeyFromObject(dnsRecord), dnsRecord)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(dnsRecord.Status.Phase).To(Equal(v1alpha1.DNSRecordPhaseReady))
+			queuedAt = dnsRecord.Status.QueuedAt
+		}, TestTimeoutMedium, time.Second).Should(Succeed())
+
+		Eventually(func(g Gomega) {
+			err := k8sClient.Get(ctx, client.ObjectKeyFromObject(dnsRecord), dnsRecord)
+			g.Expect(err).NotTo(HaveOccurred())
+			dnsRecord.SetAnnotations(map[string]string{v1alpha1.ReconcileRequestAnnotation: "request-1"})
+			g.Expect(k8sClient.Update(ctx, dnsRecord)).To(Succeed())
+		}, TestTimeoutMedium, time.Second).Should(Succeed())
+
+		Eventually(func(g Gomega) {
+			err := k8sClient.Get(ctx, client.ObjectKeyFromObject(dnsRecord), dnsRecord)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(dnsRecord.Status.LastHandledReconcileRequest).To(Equal("request-1"))
+			g.Expect(dnsRecord.Status.QueuedAt.After(queuedAt.Time)).To(BeTrue())
+		}, TestTimeoutMedium, time.Second).Should(Succeed())
+	})
+
+	It("should report when the record is next validated", func(ctx SpecContext) {
+		Expect(k8sClient.Create(ctx, dnsRecord)).To(Succeed())
+		Eventually(func(g Gomega) {
+			err := k8sClient.Get(ctx, client.ObjectKeyFromObject(dnsRecord), dnsRecord)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(dnsRecord.Status.Phase).To(Equal(v1alpha1.DNSRecordPhaseReady))
+			validFor, err := time.ParseDuration(dnsRecord.Status.ValidFor)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(dnsRecord.Status.NextValidation).NotTo(BeNil())
+			g.Expect(dnsRecord.Status.NextValidation.Time).To(BeTemporally("~", dnsRecord.Status.QueuedAt.Add(validFor), time.Second))
+		}, TestTimeoutMedium, time.Second).Should(Succeed())
+	})
+
+	It("should publish mail records as TXT endpoints", func(ctx SpecContext) {
+		dnsRecord.Spec.Mail = &v1alpha1.MailSpec{
+			SPF:   &v1alpha1.SPFSpec{Mechanisms: []string{"mx", "include:_spf.example.com"}, All: "-"},
+			DMARC: &v1alpha1.DMARCSpec{Policy: "reject", AggregateReports: []string{"mailto:dmarc@example.com"}},
+		}
+		Expect(k8sClient.Create(ctx, dnsRecord)).To(Succeed())
+		Eventually(func(g Gomega) {
+			err := k8sClient.Get(ctx, client.ObjectKeyFromObject(dnsRecord), dnsRecord)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(dnsRecord.Status.Conditions).To(
+				ContainElement(MatchFields(IgnoreExtras, Fields{
+					"Type":   Equal(string(v1alpha1.ConditionTypeReady)),
+					"Status": Equal(metav1.ConditionTrue),
+				})),
+			)
+			g.Expect(dnsRecord.Status.Endpoints).To(ContainElements(
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"DNSName":    Equal(testHostname),
+					"RecordType": Equal("TXT"),
+					"Targets":    ConsistOf(`"v=spf1 mx include:_spf.example.com -all"`),
+				})),
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"DNSName":    Equal("_dmarc." + testHostname),
+					"RecordType": Equal("TXT"),
+					"Targets":    ConsistOf(`"v=DMARC1; p=reject; rua=mailto:dmarc@example.com"`),
+				})),
+			))
+		}, TestTimeoutMedium, time.Second).Should(Succeed())
+	})
+
+	It("should record revisions of published endpoints and roll back to a revision", func(ctx SpecContext) {
+		Expect(k8sClient.Create(ctx, dnsRecord)).To(Succeed())
+		Eventually(func(g Gomega) {
+			err := k8sClient.Get(ctx, client.ObjectKeyFromObject(dnsRecord), dnsRecord)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(dnsRecord.Status.History).To(ConsistOf(
+				MatchFields(IgnoreExtras, Fields{
+					"Revision":  Equal(int64(1)),
+					"Endpoints": Equal(getTestEndpoints(testHostname, []string{"127.0.0.1"})),
+				}),
+			))
+		}, TestTimeoutMedium, time.Second).Should(Succeed())
+
+		Eventually(func(g Gomega) {
+			err := k8sClient.Get(ctx, client.ObjectKeyFromObject(dnsRecord), dnsRecord)
+			g.Expect(err).NotTo(HaveOccurred())
+			dnsRecord.Spec.Endpoints = getTestEndpoints(testHostname, []string{"127.0.0.2"})
+			g.Expect(k8sClient.Update(ctx, dnsRecord)).To(Succeed())
+		}, TestTimeoutMedium, time.Second).Should(Succeed())
+
+		Eventually(func(g Gomega) {
+			err := k8sClient.Get(ctx, client.ObjectKeyFromObject(dnsRecord), dnsRecord)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(dnsRecord.Status.History).To(HaveLen(2))
+			g.Expect(dnsRecord.Status.History[1].Revision).To(Equal(int64(2)))
+		}, TestTimeoutMedium, time.Second).Should(Succeed())
+
+		Eventually(func(g Gomega) {
+			err := k8sClient.Get(ctx, client.ObjectKeyFromObject(dnsRecord), dnsRecord)
+			g.Expect(err).NotTo(HaveOccurred())
+			dnsRecord.SetAnnotations(map[string]string{v1alpha1.RollbackToAnnotation: "1"})
+			g.Expect(k8sClient.Update(ctx, dnsRecord)).To(Succeed())
+		}, TestTimeoutMedium, time.Second).Should(Succeed())
+
+		Eventually(func(g Gomega) {
+			err := k8sClient.Get(ctx, client.ObjectKeyFromObject(dnsRecord), dnsRecord)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(dnsRecord.GetAnnotations()).NotTo(HaveKey(v1alpha1.RollbackToAnnotation))
+			g.Expect(dnsRecord.Spec.Endpoints).To(Equal(getTestEndpoints(testHostname, []string{"127.0.0.1"})))
+			g.Expect(dnsRecord.Status.History).To(HaveLen(3))
+			g.Expect(dnsRecord.Status.History[2].Endpoints).To(Equal(getTestEndpoints(testHostname, []string{"127.0.0.1"})))
+		}, TestTimeoutMedium, time.Second).Should(Succeed())
+	})
+
 	It("should have ready condition with status true", func() {
 		Expect(k8sClient.Create(ctx, dnsRecord)).To(Succeed())
 		Eventually(func(g Gomega) {
@@ -270,6 +647,7 @@ var _ = Describe("DNSRecordReconciler", func() {
 			)
 			g.Expect(dnsRecord.Finalizers).To(ContainElement(DNSRecordFinalizer))
 			g.Expect(dnsRecord.Status.WriteCounter).To(BeZero())
+			g.Expect(dnsRecord.Status.Phase).To(Equal(v1alpha1.DNSRecordPhaseReady))
 			g.Expect(dnsRecord.Status.ZoneID).To(Equal(testZoneID))
 			g.Expect(dnsRecord.Status.ZoneDomainName).To(Equal(testZoneDomainName))
 			g.Expect(dnsRecord.Status.DomainOwners).To(ConsistOf(dnsRecord.GetUIDHash()))
@@ -799,6 +1177,11 @@ var _ = Describe("DNSRecordReconciler", func() {
 						"ObservedGeneration": Equal(dnsRecord.Generation),
 					})),
 				)
+				g.Expect(dnsRecord.Status.ProviderError).To(PointTo(MatchFields(IgnoreExtras, Fields{
+					"Code":      Equal("ZoneNotFound"),
+					"Provider":  Equal("inmemory"),
+					"Retryable": BeFalse(),
+				})))
 			}, TestTimeoutMedium, time.Second).Should(Succeed())
 		})
 
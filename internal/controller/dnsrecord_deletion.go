@@ -0,0 +1,42 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+
+	"github.com/kuadrant/dns-operator/api/v1alpha1"
+	"github.com/kuadrant/dns-operator/internal/metrics"
+	"github.com/kuadrant/dns-operator/internal/provider"
+)
+
+// DefaultDeletionStuckDuration is how long a deleted record can fail to be removed from the provider before it is
+// reported as stuck, if not set on the reconciler.
+const DefaultDeletionStuckDuration = time.Hour
+
+//+kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+
+// trackDeletionFailure counts a failed attempt to remove a deleted record from the provider by the provider error code
+// of the error, e.g. Throttled or Unauthorized. Once the record has been deleting for longer than the deletion stuck
+// duration it is reported as stuck with a metric and a warning event.
+func (r *DNSRecordReconciler) trackDeletionFailure(ctx context.Context, dnsRecord *v1alpha1.DNSRecord, err error) {
+	errorClass := string(provider.ClassifyError(r.providerNameFor(ctx, dnsRecord), err))
+	metrics.DeletionFailures.WithLabelValues(dnsRecord.Name, dnsRecord.Namespace, errorClass).Inc()
+
+	stuckAfter := r.DeletionStuckDuration
+	if stuckAfter <= 0 {
+		stuckAfter = DefaultDeletionStuckDuration
+	}
+	deleting := time.Since(dnsRecord.DeletionTimestamp.Time)
+	if deleting < stuckAfter {
+		return
+	}
+
+	metrics.SetDeletionStuck(dnsRecord.Name, dnsRecord.Namespace, errorClass)
+	if r.recorder != nil {
+		r.recorder.Eventf(dnsRecord, v1.EventTypeWarning, "DeletionStuck",
+			"record has not been removed from the DNS provider after %s (%s): %v",
+			deleting.Round(time.Second), errorClass, provider.SanitizeError(err))
+	}
+}
@@ -0,0 +1,101 @@
+//go:build integration
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kuadrant/dns-operator/api/v1alpha1"
+	"github.com/kuadrant/dns-operator/internal/metrics"
+	"github.com/kuadrant/dns-operator/internal/provider"
+)
+
+var _ = Describe("Deletion failures", func() {
+	var r *DNSRecordReconciler
+	var recorder *record.FakeRecorder
+	var dnsRecord *v1alpha1.DNSRecord
+
+	deletingFor := func(d time.Duration) {
+		dnsRecord.DeletionTimestamp = &metav1.Time{Time: time.Now().Add(-d)}
+	}
+
+	BeforeEach(func() {
+		secret := &v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "provider", Namespace: "default"},
+			Type:       v1alpha1.SecretTypeKuadrantInmemory,
+		}
+		recorder = record.NewFakeRecorder(10)
+		r = &DNSRecordReconciler{
+			Client:                fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(secret).Build(),
+			DeletionStuckDuration: time.Minute,
+			recorder:              recorder,
+		}
+		dnsRecord = &v1alpha1.DNSRecord{
+			ObjectMeta: metav1.ObjectMeta{Name: "deleting", Namespace: "default"},
+			Spec: v1alpha1.DNSRecordSpec{
+				RootHost:    "foo.example.com",
+				ProviderRef: v1alpha1.ProviderRef{Name: "provider"},
+			},
+		}
+		DeferCleanup(metrics.ResetDeletionMetrics, dnsRecord.Name, dnsRecord.Namespace)
+	})
+
+	failures := func(errorClass provider.ErrorCode) float64 {
+		return testutil.ToFloat64(metrics.DeletionFailures.WithLabelValues(dnsRecord.Name, dnsRecord.Namespace, string(errorClass)))
+	}
+	stuck := func(errorClass provider.ErrorCode) float64 {
+		return testutil.ToFloat64(metrics.DeletionStuck.WithLabelValues(dnsRecord.Name, dnsRecord.Namespace, string(errorClass)))
+	}
+
+	DescribeTable("should count failures by the provider error code",
+		func(err error, expected provider.ErrorCode) {
+			deletingFor(time.Second)
+			r.trackDeletionFailure(ctx, dnsRecord, err)
+			r.trackDeletionFailure(ctx, dnsRecord, err)
+			Expect(failures(expected)).To(Equal(2.0))
+			Expect(testutil.CollectAndCount(metrics.DeletionFailures)).To(Equal(1))
+		},
+		Entry("no zone", fmt.Errorf("finding zone: %w", provider.ErrNoZoneForHost), provider.ErrorCodeZoneNotFound),
+		Entry("timeout", fmt.Errorf("reading records: %w", context.DeadlineExceeded), provider.ErrorCodeUnavailable),
+		Entry("unclassified", fmt.Errorf("something went wrong"), provider.ErrorCodeUnknown),
+	)
+
+	It("should only report records deleting for longer than the stuck duration", func() {
+		err := fmt.Errorf("reading records: %w", context.DeadlineExceeded)
+		deletingFor(30 * time.Second)
+		r.trackDeletionFailure(ctx, dnsRecord, err)
+		Expect(testutil.CollectAndCount(metrics.DeletionStuck)).To(Equal(0))
+		Expect(recorder.Events).To(BeEmpty())
+
+		deletingFor(2 * time.Minute)
+		r.trackDeletionFailure(ctx, dnsRecord, err)
+		Expect(stuck(provider.ErrorCodeUnavailable)).To(Equal(1.0))
+		Expect(recorder.Events).To(Receive(And(
+			HavePrefix("Warning DeletionStuck record has not been removed from the DNS provider after 2m0s (Unavailable)"),
+			ContainSubstring(context.DeadlineExceeded.Error()),
+		)))
+
+		// the stuck metric only has the class of the last failure
+		r.trackDeletionFailure(ctx, dnsRecord, provider.ErrNoZoneForHost)
+		Expect(testutil.CollectAndCount(metrics.DeletionStuck)).To(Equal(1))
+		Expect(stuck(provider.ErrorCodeZoneNotFound)).To(Equal(1.0))
+		Expect(failures(provider.ErrorCodeUnavailable)).To(Equal(2.0))
+		Expect(failures(provider.ErrorCodeZoneNotFound)).To(Equal(1.0))
+
+		metrics.ResetDeletionMetrics(dnsRecord.Name, dnsRecord.Namespace)
+		Expect(testutil.CollectAndCount(metrics.DeletionStuck)).To(Equal(0))
+		Expect(testutil.CollectAndCount(metrics.DeletionFailures)).To(Equal(0))
+	})
+})
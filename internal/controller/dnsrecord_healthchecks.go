@@ -3,13 +3,10 @@ package controller
 import (
 	"context"
 	"fmt"
-	"reflect"
 	"strings"
 
-	"github.com/go-logr/logr"
 	"github.com/hashicorp/go-multierror"
 
-	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/utils/ptr"
@@ -43,9 +40,10 @@ func (r *DNSRecordReconciler) ReconcileHealthChecks(ctx context.Context, dnsReco
 		if err := controllerruntime.SetControllerReference(dnsRecord, probe, r.Scheme); err != nil {
 			return err
 		}
-		if err := r.ensureProbe(ctx, probe, logger); err != nil {
-			return err
-		}
+		r.setProbeShard(probe)
+	}
+	if err := r.syncProbes(ctx, dnsRecord, desiredProbes, logger); err != nil {
+		return err
 	}
 	logger.Info("Healthecks reconciled")
 	return nil
@@ -77,30 +75,6 @@ func (r *DNSRecordReconciler) DeleteHealthChecks(ctx context.Context, dnsRecord
 	return deleteErrors
 }
 
-func (r *DNSRecordReconciler) ensureProbe(ctx context.Context, generated *v1alpha1.DNSHealthCheckProbe, logger logr.Logger) error {
-	current := &v1alpha1.DNSHealthCheckProbe{}
-
-	if err := r.Get(ctx, client.ObjectKeyFromObject(generated), current); err != nil {
-		if errors.IsNotFound(err) {
-			logger.V(1).Info(fmt.Sprintf("Creating probe: %s", generated.Name))
-			return r.Create(ctx, generated)
-		}
-		return err
-	}
-
-	desired := current.DeepCopy()
-	desired.Spec = generated.Spec
-
-	if !reflect.DeepEqual(current, desired) {
-		logger.V(1).Info(fmt.Sprintf("Updating probe: %s", desired.Name))
-		if err := r.Update(ctx, desired); err != nil {
-			return err
-		}
-	}
-	logger.V(1).Info(fmt.Sprintf("No updates needed for probe: %s", desired.Name))
-	return nil
-}
-
 // removeUnhealthyEndpoints fetches all probes associated with this record and uses the following criteria while removing endpoints:
 //   - If the Leaf Address has no health check CR - it is healthy
 //   - If the health check CR has insufficient failures - it is healthy
@@ -114,8 +88,8 @@ func (r *DNSRecordReconciler) ensureProbe(ctx context.Context, generated *v1alph
 // it returns the list of healthy endpoints, an array of unhealthy addresses and an error
 func removeUnhealthyEndpoints(specEndpoints []*endpoint.Endpoint, dnsRecord *v1alpha1.DNSRecord, probes *v1alpha1.DNSHealthCheckProbeList) ([]*endpoint.Endpoint, []string, error) {
 
-	// we are deleting or don't have health checks - don't bother
-	if (dnsRecord.DeletionTimestamp != nil && !dnsRecord.DeletionTimestamp.IsZero()) || dnsRecord.Spec.HealthCheck == nil || !probesEnabled {
+	// we are deleting, parked or don't have health checks - don't bother
+	if (dnsRecord.DeletionTimestamp != nil && !dnsRecord.DeletionTimestamp.IsZero()) || dnsRecord.Spec.Parked || dnsRecord.Spec.HealthCheck == nil || !probesEnabled {
 		return specEndpoints, []string{}, nil
 	}
 
@@ -180,9 +154,14 @@ func buildDesiredProbes(dnsRecord *v1alpha1.DNSRecord, leafs *[]string, allowIns
 				Path:                     dnsRecord.Spec.HealthCheck.Path,
 				Protocol:                 dnsRecord.Spec.HealthCheck.Protocol,
 				Interval:                 dnsRecord.Spec.HealthCheck.Interval,
+				Timeout:                  dnsRecord.Spec.HealthCheck.Timeout,
+				ServerName:               dnsRecord.Spec.HealthCheck.ServerName,
+				HostHeader:               dnsRecord.Spec.HealthCheck.HostHeader,
 				AdditionalHeadersRef:     dnsRecord.Spec.HealthCheck.AdditionalHeadersRef,
 				FailureThreshold:         dnsRecord.Spec.HealthCheck.FailureThreshold,
+				RequiredPasses:           dnsRecord.Spec.HealthCheck.RequiredPasses,
 				AllowInsecureCertificate: allowInsecureCerts,
+				ResolveCNAMEChain:        dnsRecord.Spec.HealthCheck.ResolveCNAMEChain,
 			},
 		})
 	}
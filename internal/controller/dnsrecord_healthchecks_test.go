@@ -207,6 +207,41 @@ var _ = Describe("DNSRecordReconciler_HealthChecks", func() {
 		}, TestTimeoutMedium, time.Second).Should(Succeed())
 	})
 
+	It("Should remove the probe CRs of removed targets", func() {
+		Expect(k8sClient.Create(ctx, dnsRecord)).To(Succeed())
+
+		listProbes := func(g Gomega) []v1alpha1.DNSHealthCheckProbe {
+			probes := &v1alpha1.DNSHealthCheckProbeList{}
+			g.Expect(k8sClient.List(ctx, probes, &client.ListOptions{
+				LabelSelector: labels.SelectorFromSet(map[string]string{
+					ProbeOwnerLabel: BuildOwnerLabelValue(dnsRecord),
+				}),
+				Namespace: dnsRecord.Namespace,
+			})).To(Succeed())
+			return probes.Items
+		}
+		Eventually(func(g Gomega) {
+			g.Expect(listProbes(g)).To(HaveLen(2))
+		}, TestTimeoutMedium, time.Second).Should(Succeed())
+
+		By("removing a target from the DNSRecord")
+		Eventually(func(g Gomega) {
+			g.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(dnsRecord), dnsRecord)).To(Succeed())
+			dnsRecord.Spec.Endpoints = getTestEndpoints(testHostname, []string{"172.32.200.1"})
+			g.Expect(k8sClient.Update(ctx, dnsRecord)).To(Succeed())
+		}, TestTimeoutMedium, time.Second).Should(Succeed())
+
+		Eventually(func(g Gomega) {
+			g.Expect(listProbes(g)).To(ConsistOf(
+				MatchFields(IgnoreExtras, Fields{
+					"ObjectMeta": MatchFields(IgnoreExtras, Fields{
+						"Name": Equal(fmt.Sprintf("%s-%s", dnsRecord.Name, "172.32.200.1")),
+					}),
+				}),
+			))
+		}, TestTimeoutMedium, time.Second).Should(Succeed())
+	})
+
 	It("Should create valid probe CRs with default values", func() {
 		//Create test dnsrecord with nils for optional fields
 		dnsRecord.Spec.HealthCheck = &v1alpha1.HealthCheckSpec{
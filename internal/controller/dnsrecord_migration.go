@@ -0,0 +1,338 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"slices"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	externaldnsendpoint "sigs.k8s.io/external-dns/endpoint"
+
+	"github.com/kuadrant/dns-operator/api/v1alpha1"
+	externaldnsregistry "github.com/kuadrant/dns-operator/internal/external-dns/registry"
+	"github.com/kuadrant/dns-operator/internal/provider"
+)
+
+// migrationResolutionTimeout is the time allowed for checking the endpoints resolve through the nameservers of the
+// zone a record is being migrated to.
+const migrationResolutionTimeout = 10 * time.Second
+
+// isMigrating returns true if the record is being migrated to another provider, or a migration was abandoned and the
+// endpoints are still to be removed from its target.
+func isMigrating(dnsRecord *v1alpha1.DNSRecord) bool {
+	return dnsRecord.Spec.MigrateTo != nil || dnsRecord.Status.Migration != nil
+}
+
+// completeMigration moves the zone and endpoints of a finished migration into the status of the record, once the
+// providerRef of the record has been set to the migration target.
+func completeMigration(dnsRecord *v1alpha1.DNSRecord) bool {
+	m := dnsRecord.Status.Migration
+	if dnsRecord.Spec.MigrateTo != nil || m == nil || m.ProviderRef != dnsRecord.Spec.ProviderRef {
+		return false
+	}
+	dnsRecord.Status.ZoneID = m.ZoneID
+	dnsRecord.Status.ZoneDomainName = m.ZoneDomainName
+	dnsRecord.Status.ZoneVisibility = ""
+	dnsRecord.Status.Endpoints = m.Endpoints
+	dnsRecord.Status.ZoneEndpoints = nil
+	dnsRecord.Status.DomainOwners = nil
+	dnsRecord.Status.Migration = nil
+	meta.RemoveStatusCondition(&dnsRecord.Status.Conditions, string(v1alpha1.ConditionTypeMigrating))
+	return true
+}
+
+// migrationTarget returns a copy of the record that publishes to the zone the record is being migrated to.
+func migrationTarget(dnsRecord *v1alpha1.DNSRecord) *v1alpha1.DNSRecord {
+	m := dnsRecord.Status.Migration
+	target := dnsRecord.DeepCopy()
+	target.Spec.ProviderRef = m.ProviderRef
+	target.Spec.MigrateTo = nil
+	target.Status.ZoneID = m.ZoneID
+	target.Status.ZoneDomainName = m.ZoneDomainName
+	target.Status.ZoneVisibility = ""
+	target.Status.Endpoints = m.Endpoints
+	target.Status.ZoneEndpoints = nil
+	target.Status.Migration = nil
+	return target
+}
+
+// reconcileMigration publishes the record while it is migrated to the provider of spec.migrateTo, advancing the
+// migration by one step on each reconcile:
+//   - the endpoints are published to the zone for the root host in the new provider as well as the current one
+//   - once they resolve through the nameservers of the new zone they are removed from the current provider
+//   - providerRef is set to migrateTo, the status is moved to the new zone when the record is next reconciled
+//
+// If migrateTo is removed or changed before the migration completes, the endpoints are removed from the abandoned
+// target.
+func (r *DNSRecordReconciler) reconcileMigration(ctx context.Context, previous, dnsRecord *v1alpha1.DNSRecord, probes *v1alpha1.DNSHealthCheckProbeList, dnsProvider provider.Provider) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	removeSource, err := r.migrate(ctx, dnsRecord, probes)
+	if err != nil {
+		logger.Error(err, "Failed to migrate record")
+		setDNSRecordCondition(dnsRecord, string(v1alpha1.ConditionTypeMigrating), metav1.ConditionFalse,
+			string(v1alpha1.ConditionReasonMigrationFailed), fmt.Sprintf("The record could not be migrated: %v", provider.SanitizeError(err)))
+		return r.updateStatus(ctx, previous, dnsRecord, probes, false, []string{}, err)
+	}
+
+	if removeSource {
+		hadChanges, err := r.deleteRecord(ctx, dnsRecord.DeepCopy(), dnsProvider)
+		if err != nil {
+			logger.Error(err, "Failed to remove record from the current provider")
+			setDNSRecordCondition(dnsRecord, string(v1alpha1.ConditionTypeMigrating), metav1.ConditionFalse,
+				string(v1alpha1.ConditionReasonMigrationFailed), fmt.Sprintf("The record could not be removed from the current provider: %v", provider.SanitizeError(err)))
+			return r.updateStatus(ctx, previous, dnsRecord, probes, false, []string{}, err)
+		}
+		if !hadChanges {
+			logger.Info("Record removed from the current provider, switching providerRef", "providerRef", dnsRecord.Spec.MigrateTo.Name)
+			// the zone and endpoints of the migration are moved into the status once the providerRef is switched
+			if err = r.updateMigrationStatus(ctx, previous, dnsRecord); err != nil {
+				if apierrors.IsConflict(err) {
+					return ctrl.Result{Requeue: true}, nil
+				}
+				return ctrl.Result{}, err
+			}
+			if err = r.switchProvider(ctx, previous); err != nil {
+				if apierrors.IsConflict(err) {
+					return ctrl.Result{Requeue: true}, nil
+				}
+				return ctrl.Result{}, err
+			}
+			return ctrl.Result{Requeue: true}, nil
+		}
+		setDNSRecordCondition(dnsRecord, string(v1alpha1.ConditionTypeMigrating), metav1.ConditionTrue,
+			string(v1alpha1.ConditionReasonMigrationRemovingSource), "Removing endpoints from the current provider")
+		if err = r.updateMigrationStatus(ctx, previous, dnsRecord); err != nil {
+			if apierrors.IsConflict(err) {
+				return ctrl.Result{Requeue: true}, nil
+			}
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{RequeueAfter: randomizedValidationRequeue}, nil
+	}
+
+	// keep publishing to the current provider until the endpoints resolve through the new zone
+	if prematurely, _ := recordReceivedPrematurely(dnsRecord, probes); prematurely {
+		if err = r.updateMigrationStatus(ctx, previous, dnsRecord); err != nil {
+			if apierrors.IsConflict(err) {
+				return ctrl.Result{Requeue: true}, nil
+			}
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{RequeueAfter: randomizedValidationRequeue}, nil
+	}
+	hadChanges, notHealthyProbes, err := r.publishRecord(ctx, dnsRecord, probes, dnsProvider)
+	if err != nil {
+		logger.Error(err, "Failed to publish record")
+		if errors.Is(err, externaldnsregistry.ErrUnknownTXTFormat) {
+			setDNSRecordCondition(dnsRecord, string(v1alpha1.ConditionTypeReady), metav1.ConditionFalse,
+				"ValidationError", fmt.Sprintf("validation of DNSRecord failed: %v", err))
+			return r.updateStatus(ctx, previous, dnsRecord, probes, hadChanges, notHealthyProbes, err)
+		}
+		setDNSRecordCondition(dnsRecord, string(v1alpha1.ConditionTypeReady), metav1.ConditionFalse,
+			"ProviderError", fmt.Sprintf("The DNS provider failed to ensure the record: %v", provider.SanitizeError(err)))
+		r.setProviderError(ctx, dnsRecord, err)
+		return r.updateStatus(ctx, previous, dnsRecord, probes, hadChanges, notHealthyProbes, err)
+	}
+	result, err := r.updateStatus(ctx, previous, dnsRecord, probes, hadChanges, notHealthyProbes, nil)
+	if err == nil && isMigrating(dnsRecord) && result.RequeueAfter > randomizedValidationRequeue {
+		result.RequeueAfter = randomizedValidationRequeue
+	}
+	return result, err
+}
+
+// migrate advances the migration of the record to the provider of spec.migrateTo. It returns true once the endpoints
+// resolve through the nameservers of the new zone and can be removed from the current provider.
+func (r *DNSRecordReconciler) migrate(ctx context.Context, dnsRecord *v1alpha1.DNSRecord, probes *v1alpha1.DNSHealthCheckProbeList) (bool, error) {
+	logger := log.FromContext(ctx)
+
+	// migrateTo was removed or changed before the migration completed
+	if m := dnsRecord.Status.Migration; m != nil && (dnsRecord.Spec.MigrateTo == nil || m.ProviderRef != *dnsRecord.Spec.MigrateTo) {
+		hadChanges, err := r.deleteFromMigrationTarget(ctx, dnsRecord)
+		if err != nil {
+			return false, err
+		}
+		if hadChanges {
+			setDNSRecordCondition(dnsRecord, string(v1alpha1.ConditionTypeMigrating), metav1.ConditionFalse,
+				string(v1alpha1.ConditionReasonMigrationFailed), fmt.Sprintf("Migration to %s abandoned, removing endpoints from zone %s", m.ProviderRef.Name, m.ZoneDomainName))
+			return false, nil
+		}
+		logger.Info("Migration abandoned", "providerRef", m.ProviderRef.Name)
+		dnsRecord.Status.Migration = nil
+		meta.RemoveStatusCondition(&dnsRecord.Status.Conditions, string(v1alpha1.ConditionTypeMigrating))
+	}
+	if dnsRecord.Spec.MigrateTo == nil {
+		return false, nil
+	}
+
+	if migratingCond := meta.FindStatusCondition(dnsRecord.Status.Conditions, string(v1alpha1.ConditionTypeMigrating)); migratingCond != nil &&
+		migratingCond.Reason == string(v1alpha1.ConditionReasonMigrationRemovingSource) {
+		return true, nil
+	}
+
+	if dnsRecord.Status.Migration == nil {
+		dnsRecord.Status.Migration = &v1alpha1.MigrationStatus{ProviderRef: *dnsRecord.Spec.MigrateTo}
+	}
+	m := dnsRecord.Status.Migration
+
+	if m.ZoneID == "" {
+		p, err := r.ProviderFactory.ProviderFor(ctx, providerRefAccessor{namespace: dnsRecord.Namespace, providerRef: m.ProviderRef}, provider.Config{})
+		if err != nil {
+			return false, err
+		}
+		z, err := p.DNSZoneForHost(ctx, dnsRecord.Spec.RootHost)
+		if err != nil {
+			return false, fmt.Errorf("unable to find suitable zone in provider %s: %w", m.ProviderRef.Name, err)
+		}
+		if z.ID == dnsRecord.Status.ZoneID && z.DNSName == dnsRecord.Status.ZoneDomainName {
+			return false, fmt.Errorf("provider %s resolves to zone %s the record is already published in", m.ProviderRef.Name, z.DNSName)
+		}
+		m.ZoneID = z.ID
+		m.ZoneDomainName = z.DNSName
+		logger.Info("Migrating record", "providerRef", m.ProviderRef.Name, "migrationZoneID", m.ZoneID, "migrationZoneDomainName", m.ZoneDomainName)
+	}
+
+	target := migrationTarget(dnsRecord)
+	targetProvider, err := r.getDNSProvider(ctx, target)
+	if err != nil {
+		return false, err
+	}
+	hadChanges, _, err := r.applyChanges(ctx, target, probes, targetProvider, false)
+	m.Endpoints = target.Status.Endpoints
+	if err != nil {
+		return false, err
+	}
+	if hadChanges {
+		setDNSRecordCondition(dnsRecord, string(v1alpha1.ConditionTypeMigrating), metav1.ConditionTrue,
+			string(v1alpha1.ConditionReasonMigrationPublishing), fmt.Sprintf("Publishing endpoints to zone %s", m.ZoneDomainName))
+		return false, nil
+	}
+
+	if err = verifyMigrationResolution(ctx, target, targetProvider); err != nil {
+		logger.V(1).Info("endpoints do not resolve through the new zone yet", "reason", err.Error())
+		setDNSRecordCondition(dnsRecord, string(v1alpha1.ConditionTypeMigrating), metav1.ConditionTrue,
+			string(v1alpha1.ConditionReasonMigrationVerifying), fmt.Sprintf("Waiting for endpoints to resolve through the nameservers of zone %s: %v", m.ZoneDomainName, err))
+		return false, nil
+	}
+	setDNSRecordCondition(dnsRecord, string(v1alpha1.ConditionTypeMigrating), metav1.ConditionTrue,
+		string(v1alpha1.ConditionReasonMigrationRemovingSource), "Removing endpoints from the current provider")
+	return true, nil
+}
+
+// deleteFromMigrationTarget removes the endpoints of the record from the zone it is being migrated to.
+// Returns true if there were changes applied to the zone.
+func (r *DNSRecordReconciler) deleteFromMigrationTarget(ctx context.Context, dnsRecord *v1alpha1.DNSRecord) (bool, error) {
+	m := dnsRecord.Status.Migration
+	if m == nil || m.ZoneID == "" {
+		return false, nil
+	}
+	target := migrationTarget(dnsRecord)
+	targetProvider, err := r.getDNSProvider(ctx, target)
+	if err != nil {
+		return false, err
+	}
+	hadChanges, err := r.deleteRecord(ctx, target, targetProvider)
+	if err != nil {
+		return false, err
+	}
+	m.Endpoints = target.Status.Endpoints
+	return hadChanges, nil
+}
+
+// updateMigrationStatus saves the progress of a migration without updating the validity of the record.
+func (r *DNSRecordReconciler) updateMigrationStatus(ctx context.Context, previous, current *v1alpha1.DNSRecord) error {
+	if equality.Semantic.DeepEqual(previous.Status, current.Status) {
+		return nil
+	}
+	return r.Status().Update(ctx, current)
+}
+
+// verifyMigrationResolution returns an error unless the published endpoints of the given record resolve through all
+// the nameservers of its zone.
+func verifyMigrationResolution(ctx context.Context, dnsRecord *v1alpha1.DNSRecord, dnsProvider provider.Provider) error {
+	ctx, cancel := context.WithTimeout(ctx, migrationResolutionTimeout)
+	defer cancel()
+
+	nameservers, err := zoneNameservers(ctx, dnsRecord, dnsProvider)
+	if err != nil {
+		return err
+	}
+
+	for _, nameserver := range nameservers {
+		if err = verifyResolution(ctx, nameserverResolver(nameserver), nameserver, dnsRecord.Status.Endpoints); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// verifyResolution returns an error unless the given endpoints resolve through the given resolver, named by via in
+// errors. Targets of endpoints with a set identifier are not compared, as resolvers only answer with one of the record
+// sets.
+func verifyResolution(ctx context.Context, resolver *net.Resolver, via string, endpoints []*externaldnsendpoint.Endpoint) error {
+	for _, ep := range endpoints {
+		switch ep.RecordType {
+		case externaldnsendpoint.RecordTypeA, externaldnsendpoint.RecordTypeAAAA:
+			addrs, err := resolver.LookupHost(ctx, ep.DNSName)
+			if err != nil {
+				return fmt.Errorf("resolving %s through %s: %w", ep.DNSName, via, err)
+			}
+			if ep.SetIdentifier != "" {
+				continue
+			}
+			for _, target := range ep.Targets {
+				if !slices.Contains(addrs, target) {
+					return fmt.Errorf("%s does not resolve to %s through %s", ep.DNSName, target, via)
+				}
+			}
+		case externaldnsendpoint.RecordTypeCNAME:
+			cname, err := resolver.LookupCNAME(ctx, ep.DNSName)
+			if err != nil {
+				return fmt.Errorf("resolving %s through %s: %w", ep.DNSName, via, err)
+			}
+			if ep.SetIdentifier != "" || len(ep.Targets) == 0 {
+				continue
+			}
+			if !strings.EqualFold(strings.TrimSuffix(cname, "."), strings.TrimSuffix(ep.Targets[0], ".")) {
+				return fmt.Errorf("%s does not resolve to %s through %s", ep.DNSName, ep.Targets[0], via)
+			}
+		}
+	}
+	return nil
+}
+
+// nameserverResolver returns a resolver that sends all queries to the given nameserver, on port 53 unless the
+// nameserver has a port.
+func nameserverResolver(nameserver string) *net.Resolver {
+	address := nameserver
+	if _, _, err := net.SplitHostPort(nameserver); err != nil {
+		address = net.JoinHostPort(strings.TrimSuffix(nameserver, "."), "53")
+	}
+	return &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			d := net.Dialer{}
+			return d.DialContext(ctx, network, address)
+		},
+	}
+}
+
+// switchProvider patches the providerRef of the record to its migrateTo, leaving the rest of the spec as it was read,
+// as the spec of the record being reconciled has its endpoints expanded.
+func (r *DNSRecordReconciler) switchProvider(ctx context.Context, previous *v1alpha1.DNSRecord) error {
+	record := previous.DeepCopy()
+	patch := client.MergeFrom(record.DeepCopy())
+	record.Spec.ProviderRef = *record.Spec.MigrateTo
+	record.Spec.MigrateTo = nil
+	return r.Patch(ctx, record, patch)
+}
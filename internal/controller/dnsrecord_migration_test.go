@@ -0,0 +1,41 @@
+//go:build integration
+
+package controller
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kuadrant/dns-operator/api/v1alpha1"
+)
+
+var _ = Describe("DNSRecord migration", func() {
+	It("should only switch the providerRef of the stored record", func() {
+		dnsRecord := &v1alpha1.DNSRecord{
+			ObjectMeta: metav1.ObjectMeta{Name: "migrated", Namespace: "default"},
+			Spec: v1alpha1.DNSRecordSpec{
+				RootHost:         "foo.example.com",
+				ProviderRef:      v1alpha1.ProviderRef{Name: "current"},
+				MigrateTo:        &v1alpha1.ProviderRef{Name: "target"},
+				GatewayEndpoints: []v1alpha1.GatewayEndpoint{{DNSName: "foo.example.com", GatewayName: "gw"}},
+			},
+		}
+		c := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(dnsRecord).Build()
+		r := &DNSRecordReconciler{Client: c}
+
+		previous := &v1alpha1.DNSRecord{}
+		Expect(c.Get(ctx, client.ObjectKeyFromObject(dnsRecord), previous)).To(Succeed())
+		Expect(r.switchProvider(ctx, previous)).To(Succeed())
+
+		Expect(c.Get(ctx, client.ObjectKeyFromObject(dnsRecord), dnsRecord)).To(Succeed())
+		Expect(dnsRecord.Spec.ProviderRef.Name).To(Equal("target"))
+		Expect(dnsRecord.Spec.MigrateTo).To(BeNil())
+		Expect(dnsRecord.Spec.Endpoints).To(BeEmpty())
+		Expect(dnsRecord.Spec.GatewayEndpoints).To(Equal(previous.Spec.GatewayEndpoints))
+	})
+})
@@ -0,0 +1,61 @@
+package controller
+
+import (
+	"fmt"
+	"net/netip"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	externaldnsendpoint "sigs.k8s.io/external-dns/endpoint"
+
+	"github.com/kuadrant/dns-operator/api/v1alpha1"
+)
+
+// parkingEndpoint returns the endpoint of the root host of the record pointing to the parking target, an A or AAAA
+// endpoint for address targets and a CNAME endpoint for hostname targets.
+func parkingEndpoint(dnsRecord *v1alpha1.DNSRecord, target string) (*externaldnsendpoint.Endpoint, error) {
+	recordType := externaldnsendpoint.RecordTypeCNAME
+	if addr, err := netip.ParseAddr(target); err == nil {
+		recordType = externaldnsendpoint.RecordTypeA
+		if addr.Is6() {
+			recordType = externaldnsendpoint.RecordTypeAAAA
+		}
+	} else if dnsRecord.Spec.RootHost == dnsRecord.Status.ZoneDomainName {
+		return nil, fmt.Errorf("the zone apex %s can't be parked with a CNAME, the parking target must be an address", dnsRecord.Spec.RootHost)
+	}
+
+	// keep the TTL of the root host so the endpoints are restored as quickly as they were parked
+	var ttl externaldnsendpoint.TTL
+	for _, ep := range dnsRecord.Spec.Endpoints {
+		if ep.DNSName == dnsRecord.Spec.RootHost {
+			ttl = ep.RecordTTL
+			break
+		}
+	}
+	return externaldnsendpoint.NewEndpointWithTTL(dnsRecord.Spec.RootHost, recordType, ttl, target), nil
+}
+
+// parkRecord replaces the endpoints of a parked record with the parking endpoint, and keeps the endpoints that were
+// published before the record was parked in the status until it is unparked.
+func (r *DNSRecordReconciler) parkRecord(dnsRecord *v1alpha1.DNSRecord) error {
+	if !dnsRecord.Spec.Parked {
+		meta.RemoveStatusCondition(&dnsRecord.Status.Conditions, string(v1alpha1.ConditionTypeParked))
+		dnsRecord.Status.ParkedEndpoints = nil
+		return nil
+	}
+	if r.ParkingTarget == "" {
+		return fmt.Errorf("the record can't be parked, no parking target is configured")
+	}
+	ep, err := parkingEndpoint(dnsRecord, r.ParkingTarget)
+	if err != nil {
+		return err
+	}
+
+	if meta.FindStatusCondition(dnsRecord.Status.Conditions, string(v1alpha1.ConditionTypeParked)) == nil {
+		dnsRecord.Status.ParkedEndpoints = dnsRecord.Status.Endpoints
+	}
+	dnsRecord.Spec.Endpoints = []*externaldnsendpoint.Endpoint{ep}
+	setDNSRecordCondition(dnsRecord, string(v1alpha1.ConditionTypeParked), metav1.ConditionTrue,
+		string(v1alpha1.ConditionReasonParked), fmt.Sprintf("Endpoints are replaced with parking target %s", r.ParkingTarget))
+	return nil
+}
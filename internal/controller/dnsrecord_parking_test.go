@@ -0,0 +1,82 @@
+//go:build integration
+
+package controller
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	externaldnsendpoint "sigs.k8s.io/external-dns/endpoint"
+
+	"github.com/kuadrant/dns-operator/api/v1alpha1"
+)
+
+var _ = Describe("Parking", func() {
+	var dnsRecord *v1alpha1.DNSRecord
+	var published []*externaldnsendpoint.Endpoint
+
+	BeforeEach(func() {
+		published = []*externaldnsendpoint.Endpoint{
+			externaldnsendpoint.NewEndpointWithTTL("foo.example.com", externaldnsendpoint.RecordTypeA, 60, "1.1.1.1"),
+		}
+		dnsRecord = &v1alpha1.DNSRecord{
+			Spec: v1alpha1.DNSRecordSpec{
+				RootHost: "foo.example.com",
+				Parked:   true,
+				Endpoints: []*externaldnsendpoint.Endpoint{
+					externaldnsendpoint.NewEndpointWithTTL("foo.example.com", externaldnsendpoint.RecordTypeA, 60, "1.1.1.1", "2.2.2.2"),
+					externaldnsendpoint.NewEndpointWithTTL("bar.foo.example.com", externaldnsendpoint.RecordTypeCNAME, 300, "foo.example.com"),
+				},
+			},
+			Status: v1alpha1.DNSRecordStatus{
+				ZoneDomainName: "example.com",
+				Endpoints:      published,
+			},
+		}
+	})
+
+	It("should replace the endpoints with a CNAME to a hostname parking target", func() {
+		r := &DNSRecordReconciler{ParkingTarget: "sorry.example.net"}
+		Expect(r.parkRecord(dnsRecord)).To(Succeed())
+		Expect(dnsRecord.Spec.Endpoints).To(ConsistOf(
+			externaldnsendpoint.NewEndpointWithTTL("foo.example.com", externaldnsendpoint.RecordTypeCNAME, 60, "sorry.example.net"),
+		))
+		Expect(dnsRecord.Status.ParkedEndpoints).To(Equal(published))
+		cond := meta.FindStatusCondition(dnsRecord.Status.Conditions, string(v1alpha1.ConditionTypeParked))
+		Expect(cond).ToNot(BeNil())
+		Expect(cond.Status).To(Equal(metav1.ConditionTrue))
+		Expect(cond.Reason).To(Equal(string(v1alpha1.ConditionReasonParked)))
+
+		By("keeping the endpoints published before the record was parked")
+		dnsRecord.Status.Endpoints = dnsRecord.Spec.Endpoints
+		Expect(r.parkRecord(dnsRecord)).To(Succeed())
+		Expect(dnsRecord.Status.ParkedEndpoints).To(Equal(published))
+
+		By("unparking the record")
+		dnsRecord.Spec.Parked = false
+		Expect(r.parkRecord(dnsRecord)).To(Succeed())
+		Expect(dnsRecord.Status.ParkedEndpoints).To(BeNil())
+		Expect(meta.FindStatusCondition(dnsRecord.Status.Conditions, string(v1alpha1.ConditionTypeParked))).To(BeNil())
+	})
+
+	It("should replace the endpoints with an address record to an address parking target", func() {
+		r := &DNSRecordReconciler{ParkingTarget: "2001:db8::1"}
+		Expect(r.parkRecord(dnsRecord)).To(Succeed())
+		Expect(dnsRecord.Spec.Endpoints).To(ConsistOf(
+			externaldnsendpoint.NewEndpointWithTTL("foo.example.com", externaldnsendpoint.RecordTypeAAAA, 60, "2001:db8::1"),
+		))
+	})
+
+	It("should not park the zone apex with a CNAME", func() {
+		r := &DNSRecordReconciler{ParkingTarget: "sorry.example.net"}
+		dnsRecord.Status.ZoneDomainName = "foo.example.com"
+		Expect(r.parkRecord(dnsRecord)).To(MatchError(ContainSubstring("can't be parked with a CNAME")))
+	})
+
+	It("should not park records without a parking target", func() {
+		r := &DNSRecordReconciler{}
+		Expect(r.parkRecord(dnsRecord)).To(MatchError(ContainSubstring("no parking target is configured")))
+	})
+})
@@ -0,0 +1,43 @@
+package controller
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/kuadrant/dns-operator/api/v1alpha1"
+	"github.com/kuadrant/dns-operator/internal/provider"
+)
+
+// propagationRequeue is the requeue time of a record while the provider is propagating its changes
+const propagationRequeue = 5 * time.Second
+
+// trackPropagation sets the pending changes of the record to the changes submitted by the provider, in this and
+// previous reconciles, that the provider is still propagating to its nameservers. It does nothing for providers that
+// don't report the propagation state of their changes.
+func trackPropagation(ctx context.Context, dnsRecord *v1alpha1.DNSRecord, dnsProvider provider.Provider) {
+	tracker, ok := provider.As[provider.ChangeTracker](dnsProvider)
+	if !ok {
+		dnsRecord.Status.PendingChanges = nil
+		return
+	}
+	ids := append(slices.Clone(dnsRecord.Status.PendingChanges), tracker.SubmittedChanges()...)
+	if len(ids) == 0 {
+		return
+	}
+	pending, err := tracker.PendingChanges(ctx, ids)
+	if err != nil {
+		// fall back to the normal requeue times rather than waiting on changes that can't be checked
+		log.FromContext(ctx).Error(err, "Failed to get the propagation state of changes")
+		dnsRecord.Status.PendingChanges = nil
+		return
+	}
+	dnsRecord.Status.PendingChanges = pending
+}
+
+// propagationPending returns true if the provider is still propagating changes of the record.
+func propagationPending(dnsRecord *v1alpha1.DNSRecord) bool {
+	return len(dnsRecord.Status.PendingChanges) > 0
+}
@@ -0,0 +1,77 @@
+package controller
+
+import (
+	"fmt"
+	"strconv"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/meta"
+	externaldnsendpoint "sigs.k8s.io/external-dns/endpoint"
+
+	"github.com/kuadrant/dns-operator/api/v1alpha1"
+)
+
+// revisionHistoryLimit is the number of revisions kept in the history of a record
+const revisionHistoryLimit = 5
+
+// recordRevision adds the given spec endpoints to the history of the record once they have been successfully
+// published, unless they are the endpoints of the latest revision. The oldest revisions are removed beyond the
+// history limit.
+func recordRevision(record *v1alpha1.DNSRecord, endpoints []*externaldnsendpoint.Endpoint) {
+	if len(endpoints) == 0 || !meta.IsStatusConditionTrue(record.Status.Conditions, string(v1alpha1.ConditionTypeReady)) {
+		return
+	}
+
+	revision := int64(1)
+	if len(record.Status.History) > 0 {
+		latest := record.Status.History[len(record.Status.History)-1]
+		if equality.Semantic.DeepEqual(latest.Endpoints, endpoints) {
+			return
+		}
+		revision = latest.Revision + 1
+	}
+
+	applied := v1alpha1.DNSRecordRevision{
+		Revision:   revision,
+		Generation: record.Generation,
+		AppliedAt:  reconcileStart,
+	}
+	for _, ep := range endpoints {
+		applied.Endpoints = append(applied.Endpoints, ep.DeepCopy())
+	}
+	record.Status.History = append(record.Status.History, applied)
+	if len(record.Status.History) > revisionHistoryLimit {
+		record.Status.History = record.Status.History[len(record.Status.History)-revisionHistoryLimit:]
+	}
+}
+
+// rollbackEndpoints restores the endpoints of the revision requested by the rollback annotation of the record to its
+// spec, and removes the annotation.
+func rollbackEndpoints(record *v1alpha1.DNSRecord) error {
+	value := record.GetAnnotations()[v1alpha1.RollbackToAnnotation]
+	revision, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid revision %q: %w", value, err)
+	}
+
+	for _, applied := range record.Status.History {
+		if applied.Revision != revision {
+			continue
+		}
+		record.Spec.Endpoints = nil
+		for _, ep := range applied.Endpoints {
+			record.Spec.Endpoints = append(record.Spec.Endpoints, ep.DeepCopy())
+		}
+		annotations := record.GetAnnotations()
+		delete(annotations, v1alpha1.RollbackToAnnotation)
+		record.SetAnnotations(annotations)
+		return nil
+	}
+	return fmt.Errorf("revision %d is not in the history of the record", revision)
+}
+
+// isRollbackRequested returns true if the record has the rollback annotation.
+func isRollbackRequested(record *v1alpha1.DNSRecord) bool {
+	_, ok := record.GetAnnotations()[v1alpha1.RollbackToAnnotation]
+	return ok
+}
@@ -0,0 +1,64 @@
+//go:build integration
+
+package controller
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kuadrant/dns-operator/api/v1alpha1"
+)
+
+var _ = Describe("DNSRecord status writes", func() {
+	queuedAt := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	previous := func() *v1alpha1.DNSRecord {
+		nextValidation := metav1.NewTime(queuedAt.Add(time.Minute))
+		return &v1alpha1.DNSRecord{Status: v1alpha1.DNSRecordStatus{
+			QueuedAt:           metav1.NewTime(queuedAt),
+			ValidFor:           "1m",
+			NextValidation:     &nextValidation,
+			ObservedGeneration: 1,
+			Conditions: []metav1.Condition{{
+				Type:   string(v1alpha1.ConditionTypeReady),
+				Status: metav1.ConditionTrue,
+				Reason: string(v1alpha1.ConditionReasonProviderSuccess),
+			}},
+		}}
+	}
+
+	DescribeTable("should only write changes other than the queued time of valid statuses",
+		func(change func(*v1alpha1.DNSRecord), expected bool) {
+			current := previous()
+			change(current)
+			Expect(statusChanged(previous(), current)).To(Equal(expected))
+		},
+		Entry("unchanged", func(*v1alpha1.DNSRecord) {}, false),
+		Entry("queued time within the validity", func(r *v1alpha1.DNSRecord) {
+			r.Status.QueuedAt = metav1.NewTime(queuedAt.Add(30 * time.Second))
+			nextValidation := metav1.NewTime(queuedAt.Add(90 * time.Second))
+			r.Status.NextValidation = &nextValidation
+		}, false),
+		Entry("queued time once the status is no longer valid", func(r *v1alpha1.DNSRecord) {
+			r.Status.QueuedAt = metav1.NewTime(queuedAt.Add(time.Minute))
+		}, true),
+		Entry("queued time of a status without a valid duration", func(r *v1alpha1.DNSRecord) {
+			r.Status.QueuedAt = metav1.NewTime(queuedAt.Add(time.Second))
+			r.Status.ValidFor = ""
+		}, true),
+		Entry("observed generation", func(r *v1alpha1.DNSRecord) {
+			r.Status.ObservedGeneration = 2
+		}, true),
+		Entry("condition", func(r *v1alpha1.DNSRecord) {
+			r.Status.Conditions[0].Status = metav1.ConditionFalse
+		}, true),
+		Entry("validity with the queued time", func(r *v1alpha1.DNSRecord) {
+			r.Status.QueuedAt = metav1.NewTime(queuedAt.Add(time.Second))
+			r.Status.ValidFor = "2m"
+		}, true),
+	)
+})
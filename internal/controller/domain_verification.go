@@ -0,0 +1,104 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"slices"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/kuadrant/dns-operator/api/v1alpha1"
+	"github.com/kuadrant/dns-operator/internal/common/hash"
+)
+
+const (
+	// domainVerificationPrefix is the label prepended to the root host of a record for the name of its verification
+	// TXT record.
+	domainVerificationPrefix = "_kuadrant-verification."
+	// domainVerificationValuePrefix is prepended to the token of a record for the value of its verification TXT record.
+	domainVerificationValuePrefix = "kuadrant-verification="
+	// domainVerificationTimeout is the time allowed for looking up the verification TXT record of a record.
+	domainVerificationTimeout = 5 * time.Second
+	// domainVerificationRequeue is how often a record awaiting verification looks up its verification TXT record.
+	domainVerificationRequeue = time.Minute
+)
+
+// txtResolver resolves the TXT records of a name.
+type txtResolver interface {
+	LookupTXT(ctx context.Context, name string) ([]string, error)
+}
+
+// verificationResolver resolves the verification TXT records of root hosts.
+var verificationResolver txtResolver = net.DefaultResolver
+
+// verifyDomain returns true if ownership of the root host of the record is verified, by a TXT record created by the
+// owner of the root host outside of the operator with the value in the status of the record. Ownership is only
+// verified before the record is first published, records published before verification was required are not
+// verified. Returns an error if the record has endpoints for verification records, so a record can't verify
+// ownership of a root host for another record.
+func verifyDomain(ctx context.Context, dnsRecord *v1alpha1.DNSRecord) (bool, error) {
+	for _, ep := range dnsRecord.Spec.Endpoints {
+		if strings.HasPrefix(ep.DNSName, domainVerificationPrefix) || strings.Contains(ep.DNSName, "."+domainVerificationPrefix) {
+			return false, fmt.Errorf("endpoint %s is a domain verification record, which can't be published by a DNSRecord", ep.DNSName)
+		}
+	}
+
+	verification := dnsRecord.Status.DomainVerification
+	if verification != nil && verification.VerifiedAt != nil {
+		return true, nil
+	}
+	if verification == nil && len(dnsRecord.Status.Endpoints) > 0 {
+		return true, nil
+	}
+	if verification == nil {
+		rootHost, _ := strings.CutPrefix(dnsRecord.Spec.RootHost, v1alpha1.WildcardPrefix)
+		verification = &v1alpha1.DomainVerificationStatus{
+			RecordName:  domainVerificationPrefix + rootHost,
+			RecordValue: domainVerificationValuePrefix + hash.ToBase36Hash(string(dnsRecord.GetUID())),
+		}
+		dnsRecord.Status.DomainVerification = verification
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, domainVerificationTimeout)
+	defer cancel()
+	values, err := verificationResolver.LookupTXT(ctx, verification.RecordName)
+	if err != nil {
+		// the record not existing yet is the expected state until the owner creates it
+		log.FromContext(ctx).V(1).Info("verification record not found", "name", verification.RecordName, "error", err.Error())
+		return false, nil
+	}
+	if !slices.Contains(values, verification.RecordValue) {
+		return false, nil
+	}
+	verification.VerifiedAt = ptr.To(metav1.Now())
+	return true, nil
+}
+
+// awaitDomainVerification updates the status of a record awaiting verification of its root host, without publishing it
+// or recording a revision, and requeues the record to look up its verification TXT record again.
+func (r *DNSRecordReconciler) awaitDomainVerification(ctx context.Context, previous, dnsRecord *v1alpha1.DNSRecord) (ctrl.Result, error) {
+	verification := dnsRecord.Status.DomainVerification
+	log.FromContext(ctx).Info("Waiting for ownership of the root host to be verified", "name", verification.RecordName)
+
+	setDNSRecordCondition(dnsRecord, string(v1alpha1.ConditionTypeReady), metav1.ConditionFalse,
+		string(v1alpha1.ConditionReasonAwaitingDomainVerification),
+		fmt.Sprintf("Create a TXT record %s with the value %s to verify ownership of the root host", verification.RecordName, verification.RecordValue))
+
+	if !equality.Semantic.DeepEqual(previous.Status, dnsRecord.Status) {
+		if err := r.Status().Update(ctx, dnsRecord); err != nil {
+			if apierrors.IsConflict(err) {
+				return ctrl.Result{Requeue: true}, nil
+			}
+			return ctrl.Result{}, err
+		}
+	}
+	return ctrl.Result{RequeueAfter: domainVerificationRequeue}, nil
+}
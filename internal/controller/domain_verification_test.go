@@ -0,0 +1,133 @@
+//go:build integration
+
+package controller
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	externaldnsendpoint "sigs.k8s.io/external-dns/endpoint"
+
+	"github.com/kuadrant/dns-operator/api/v1alpha1"
+	"github.com/kuadrant/dns-operator/internal/provider"
+)
+
+type fakeTXTResolver map[string][]string
+
+func (r fakeTXTResolver) LookupTXT(_ context.Context, name string) ([]string, error) {
+	values, ok := r[name]
+	if !ok {
+		return nil, errors.New("no such host")
+	}
+	return values, nil
+}
+
+var _ = Describe("Domain verification", func() {
+	var (
+		dnsRecord *v1alpha1.DNSRecord
+		resolver  fakeTXTResolver
+	)
+
+	BeforeEach(func() {
+		previous := verificationResolver
+		DeferCleanup(func() {
+			verificationResolver = previous
+		})
+		resolver = fakeTXTResolver{}
+		verificationResolver = resolver
+		dnsRecord = &v1alpha1.DNSRecord{
+			ObjectMeta: metav1.ObjectMeta{Name: "verify", Namespace: "default", UID: "a-uid"},
+			Spec: v1alpha1.DNSRecordSpec{
+				RootHost: "*.foo.example.com",
+				Endpoints: []*externaldnsendpoint.Endpoint{
+					externaldnsendpoint.NewEndpoint("*.foo.example.com", externaldnsendpoint.RecordTypeA, "1.1.1.1"),
+				},
+			},
+		}
+	})
+
+	It("should not publish a new record until the verification record exists", func() {
+		Expect(verifyDomain(context.Background(), dnsRecord)).To(BeFalse())
+		verification := dnsRecord.Status.DomainVerification
+		Expect(verification).ToNot(BeNil())
+		Expect(verification.RecordName).To(Equal("_kuadrant-verification.foo.example.com"))
+		Expect(verification.RecordValue).To(HavePrefix(domainVerificationValuePrefix))
+		Expect(verification.VerifiedAt).To(BeNil())
+
+		resolver[verification.RecordName] = []string{"kuadrant-verification=other"}
+		Expect(verifyDomain(context.Background(), dnsRecord)).To(BeFalse())
+
+		resolver[verification.RecordName] = append(resolver[verification.RecordName], verification.RecordValue)
+		Expect(verifyDomain(context.Background(), dnsRecord)).To(BeTrue())
+		Expect(dnsRecord.Status.DomainVerification.VerifiedAt).ToNot(BeNil())
+
+		// verification is not repeated once the record is verified
+		delete(resolver, verification.RecordName)
+		Expect(verifyDomain(context.Background(), dnsRecord)).To(BeTrue())
+	})
+
+	It("should keep records not ready until the verification record exists", func() {
+		dnsRecord.Finalizers = []string{DNSRecordFinalizer}
+		dnsRecord.Spec.ProviderRef = v1alpha1.ProviderRef{Name: "inmemory"}
+		secret := &v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "inmemory", Namespace: "default"},
+			Type:       v1alpha1.SecretTypeKuadrantInmemory,
+			Data:       map[string][]byte{v1alpha1.InmemInitZonesKey: []byte("example.com")},
+		}
+		c := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(dnsRecord, secret).WithStatusSubresource(dnsRecord).Build()
+		providerFactory, err := provider.NewFactory(c, []string{"inmemory"})
+		Expect(err).NotTo(HaveOccurred())
+		r := &DNSRecordReconciler{Client: c, Scheme: scheme.Scheme, ProviderFactory: providerFactory, RequireDomainVerification: true}
+		r.setup(record.NewFakeRecorder(10), RequeueDuration, ValidityDuration, DefaultValidationDuration, true, true)
+		req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(dnsRecord)}
+
+		for range 2 {
+			result, err := r.Reconcile(ctx, req)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(domainVerificationRequeue))
+
+			Expect(c.Get(ctx, req.NamespacedName, dnsRecord)).To(Succeed())
+			ready := meta.FindStatusCondition(dnsRecord.Status.Conditions, string(v1alpha1.ConditionTypeReady))
+			Expect(ready).NotTo(BeNil())
+			Expect(ready.Status).To(Equal(metav1.ConditionFalse))
+			Expect(ready.Reason).To(Equal(string(v1alpha1.ConditionReasonAwaitingDomainVerification)))
+			Expect(dnsRecord.Status.History).To(BeEmpty())
+			Expect(dnsRecord.Status.Endpoints).To(BeEmpty())
+		}
+
+		verification := dnsRecord.Status.DomainVerification
+		Expect(verification).NotTo(BeNil())
+		resolver[verification.RecordName] = []string{verification.RecordValue}
+		Eventually(func(g Gomega) {
+			_, err := r.Reconcile(ctx, req)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(c.Get(ctx, req.NamespacedName, dnsRecord)).To(Succeed())
+			g.Expect(meta.IsStatusConditionTrue(dnsRecord.Status.Conditions, string(v1alpha1.ConditionTypeReady))).To(BeTrue())
+		}).Should(Succeed())
+		Expect(dnsRecord.Status.DomainVerification.VerifiedAt).NotTo(BeNil())
+	})
+
+	It("should not verify records published before verification was required", func() {
+		dnsRecord.Status.Endpoints = dnsRecord.Spec.Endpoints
+		Expect(verifyDomain(context.Background(), dnsRecord)).To(BeTrue())
+		Expect(dnsRecord.Status.DomainVerification).To(BeNil())
+	})
+
+	It("should reject records with endpoints for verification records", func() {
+		dnsRecord.Spec.Endpoints = append(dnsRecord.Spec.Endpoints,
+			externaldnsendpoint.NewEndpoint("_kuadrant-verification.bar.foo.example.com", externaldnsendpoint.RecordTypeTXT, "kuadrant-verification=token"))
+		_, err := verifyDomain(context.Background(), dnsRecord)
+		Expect(err).To(MatchError(ContainSubstring("is a domain verification record")))
+	})
+})
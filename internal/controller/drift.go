@@ -0,0 +1,116 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	externaldnsendpoint "sigs.k8s.io/external-dns/endpoint"
+
+	"github.com/kuadrant/dns-operator/api/v1alpha1"
+	"github.com/kuadrant/dns-operator/internal/provider"
+)
+
+// errDriftDetected is returned by applyChanges for a record with endpoints changed in the zone outside of the operator,
+// when publishing is frozen on drift.
+var errDriftDetected = errors.New("endpoints changed outside of the operator")
+
+// publishedByLastReconcile returns true if the endpoints in the status of the record were published by its last
+// reconcile, or publishing has been frozen on drift since, so they are what the zone is expected to hold. The status
+// endpoints are also set when publishing fails, the zone then holds older values.
+func publishedByLastReconcile(dnsRecord *v1alpha1.DNSRecord) bool {
+	readyCond := meta.FindStatusCondition(dnsRecord.Status.Conditions, string(v1alpha1.ConditionTypeReady))
+	if readyCond == nil {
+		return false
+	}
+	switch v1alpha1.ConditionReason(readyCond.Reason) {
+	case v1alpha1.ConditionReasonProviderSuccess, v1alpha1.ConditionReasonAwaitingValidation,
+		v1alpha1.ConditionReasonAwaitingPropagation, v1alpha1.ConditionReasonUnhealthy, v1alpha1.ConditionReasonDriftDetected,
+		v1alpha1.ConditionReasonTakeoverPending:
+		return true
+	}
+	return false
+}
+
+// normalizedTargets returns a copy of the given targets of the given record type as formatted by the given normalizer,
+// comparing targets sorts them.
+func normalizedTargets(recordType string, targets externaldnsendpoint.Targets, normalize func(recordType, target string) string) externaldnsendpoint.Targets {
+	normalized := make(externaldnsendpoint.Targets, 0, len(targets))
+	for _, target := range targets {
+		if normalize != nil {
+			target = normalize(recordType, target)
+		}
+		normalized = append(normalized, target)
+	}
+	return normalized
+}
+
+// driftedEndpoints returns a description of each of the given published endpoints that has been changed or removed
+// in the zone since it was published. Only endpoints the given owner is the sole owner of are compared, the targets of
+// endpoints shared with other owners are changed by them. An empty owner compares all endpoints.
+func driftedEndpoints(zoneEndpoints, published []*externaldnsendpoint.Endpoint, ownerID string, normalize func(recordType, target string) string) []string {
+	zone := make(map[externaldnsendpoint.EndpointKey]*externaldnsendpoint.Endpoint, len(zoneEndpoints))
+	for _, ep := range zoneEndpoints {
+		zone[ep.Key()] = ep
+	}
+
+	var drifted []string
+	for _, ep := range published {
+		name := strings.TrimSpace(ep.RecordType + " " + ep.DNSName + " " + ep.SetIdentifier)
+		observed, ok := zone[ep.Key()]
+		if !ok {
+			drifted = append(drifted, fmt.Sprintf("%s published [%s] was removed", name, strings.Join(ep.Targets, " ")))
+			continue
+		}
+		if ownerID != "" && observed.Labels[externaldnsendpoint.OwnerLabelKey] != ownerID {
+			continue
+		}
+		targetsChanged := !normalizedTargets(ep.RecordType, ep.Targets, normalize).Same(normalizedTargets(observed.RecordType, observed.Targets, normalize))
+		ttlChanged := ep.RecordTTL.IsConfigured() && observed.RecordTTL.IsConfigured() && ep.RecordTTL != observed.RecordTTL
+		if targetsChanged || ttlChanged {
+			drifted = append(drifted, fmt.Sprintf("%s published [%s] ttl %d, observed [%s] ttl %d", name,
+				strings.Join(ep.Targets, " "), ep.RecordTTL, strings.Join(observed.Targets, " "), observed.RecordTTL))
+		}
+	}
+	return drifted
+}
+
+// isDriftAcknowledged returns true if the record has the annotation acknowledging the drift of its endpoints.
+func isDriftAcknowledged(dnsRecord *v1alpha1.DNSRecord) bool {
+	_, ok := dnsRecord.GetAnnotations()[v1alpha1.DriftAcknowledgedAnnotation]
+	return ok
+}
+
+// checkDrift compares the endpoints published by the record with the zone when publishing is frozen on drift. If they
+// were changed outside of the operator, the DriftDetected condition is set with the observed values and an error is
+// returned so they are not overwritten, until the drift is acknowledged with the annotation.
+func (r *DNSRecordReconciler) checkDrift(dnsRecord *v1alpha1.DNSRecord, dnsProvider provider.Provider, zoneEndpoints, published []*externaldnsendpoint.Endpoint, ownerID string) error {
+	// records being migrated publish to two zones, the status of the record holds the endpoints of only one
+	migrating := meta.FindStatusCondition(dnsRecord.Status.Conditions, string(v1alpha1.ConditionTypeMigrating)) != nil
+	if !r.FreezeOnDrift || migrating || isDriftAcknowledged(dnsRecord) || !publishedByLastReconcile(dnsRecord) {
+		meta.RemoveStatusCondition(&dnsRecord.Status.Conditions, string(v1alpha1.ConditionTypeDriftDetected))
+		return nil
+	}
+
+	var normalize func(recordType, target string) string
+	if normalizer, ok := provider.As[provider.TargetNormalizer](dnsProvider); ok {
+		normalize = normalizer.NormalizeTarget
+	}
+	drifted := driftedEndpoints(zoneEndpoints, published, ownerID, normalize)
+	if len(drifted) == 0 {
+		meta.RemoveStatusCondition(&dnsRecord.Status.Conditions, string(v1alpha1.ConditionTypeDriftDetected))
+		return nil
+	}
+	setDNSRecordCondition(dnsRecord, string(v1alpha1.ConditionTypeDriftDetected), metav1.ConditionTrue,
+		string(v1alpha1.ConditionReasonOutOfBandChanges), strings.Join(drifted, ", "))
+	return fmt.Errorf("%w: %s", errDriftDetected, strings.Join(drifted, ", "))
+}
+
+// clearDriftAcknowledgement removes the drift acknowledged annotation from the record once it is published, so changes
+// made outside of the operator later are detected again.
+func (r *DNSRecordReconciler) clearDriftAcknowledgement(ctx context.Context, dnsRecord *v1alpha1.DNSRecord) error {
+	return r.removeAnnotation(ctx, dnsRecord, v1alpha1.DriftAcknowledgedAnnotation)
+}
@@ -0,0 +1,100 @@
+//go:build integration
+
+package controller
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	externaldnsendpoint "sigs.k8s.io/external-dns/endpoint"
+
+	"github.com/kuadrant/dns-operator/api/v1alpha1"
+)
+
+// ownedEndpoint returns an endpoint with the given owner label and TTL.
+func ownedEndpoint(dnsName, owner string, ttl externaldnsendpoint.TTL, targets ...string) *externaldnsendpoint.Endpoint {
+	ep := externaldnsendpoint.NewEndpointWithTTL(dnsName, externaldnsendpoint.RecordTypeA, ttl, targets...)
+	if owner != "" {
+		ep.Labels[externaldnsendpoint.OwnerLabelKey] = owner
+	}
+	return ep
+}
+
+var _ = Describe("Drift", func() {
+	It("should report published endpoints changed or removed in the zone", func() {
+		published := []*externaldnsendpoint.Endpoint{
+			ownedEndpoint("same.example.com", "", 60, "1.1.1.1", "2.2.2.2"),
+			ownedEndpoint("targets.example.com", "", 60, "1.1.1.1"),
+			ownedEndpoint("ttl.example.com", "", 60, "1.1.1.1"),
+			ownedEndpoint("removed.example.com", "", 60, "1.1.1.1"),
+			ownedEndpoint("shared.example.com", "", 60, "1.1.1.1"),
+		}
+		zone := []*externaldnsendpoint.Endpoint{
+			ownedEndpoint("same.example.com", "owner", 60, "2.2.2.2", "1.1.1.1"),
+			ownedEndpoint("targets.example.com", "owner", 60, "3.3.3.3"),
+			ownedEndpoint("ttl.example.com", "owner", 300, "1.1.1.1"),
+			ownedEndpoint("shared.example.com", "owner&&other", 60, "1.1.1.1", "4.4.4.4"),
+		}
+		drifted := driftedEndpoints(zone, published, "owner", nil)
+		Expect(drifted).To(ConsistOf(
+			"A targets.example.com published [1.1.1.1] ttl 60, observed [3.3.3.3] ttl 60",
+			"A ttl.example.com published [1.1.1.1] ttl 60, observed [1.1.1.1] ttl 300",
+			"A removed.example.com published [1.1.1.1] was removed",
+		))
+
+		// all endpoints are compared without an owner, e.g. in dedicated zones
+		Expect(driftedEndpoints(zone, published, "", nil)).To(HaveLen(4))
+	})
+
+	It("should compare the targets as formatted by the provider", func() {
+		published := []*externaldnsendpoint.Endpoint{externaldnsendpoint.NewEndpoint("foo.example.com", externaldnsendpoint.RecordTypeTXT, "bar")}
+		observed := externaldnsendpoint.NewEndpoint("foo.example.com", externaldnsendpoint.RecordTypeTXT, "\"bar\"")
+		observed.Labels[externaldnsendpoint.OwnerLabelKey] = "owner"
+		zone := []*externaldnsendpoint.Endpoint{observed}
+		Expect(driftedEndpoints(zone, published, "owner", nil)).To(HaveLen(1))
+		Expect(driftedEndpoints(zone, published, "owner", func(_, target string) string {
+			return strings.Trim(target, "\"")
+		})).To(BeEmpty())
+	})
+
+	It("should freeze publishing until the drift is acknowledged", func() {
+		record := &v1alpha1.DNSRecord{}
+		setDNSRecordCondition(record, string(v1alpha1.ConditionTypeReady), metav1.ConditionTrue,
+			string(v1alpha1.ConditionReasonProviderSuccess), "Provider ensured the dns record")
+		published := []*externaldnsendpoint.Endpoint{ownedEndpoint("foo.example.com", "", 60, "1.1.1.1")}
+		zone := []*externaldnsendpoint.Endpoint{ownedEndpoint("foo.example.com", "owner", 60, "2.2.2.2")}
+
+		r := &DNSRecordReconciler{}
+		Expect(r.checkDrift(record, nil, zone, published, "owner")).To(Succeed())
+		Expect(meta.FindStatusCondition(record.Status.Conditions, string(v1alpha1.ConditionTypeDriftDetected))).To(BeNil())
+
+		r.FreezeOnDrift = true
+		Expect(r.checkDrift(record, nil, zone, published, "owner")).To(MatchError(errDriftDetected))
+		cond := meta.FindStatusCondition(record.Status.Conditions, string(v1alpha1.ConditionTypeDriftDetected))
+		Expect(cond).NotTo(BeNil())
+		Expect(cond.Reason).To(Equal(string(v1alpha1.ConditionReasonOutOfBandChanges)))
+		Expect(cond.Message).To(ContainSubstring("observed [2.2.2.2]"))
+
+		// frozen records stay frozen
+		setDNSRecordCondition(record, string(v1alpha1.ConditionTypeReady), metav1.ConditionFalse,
+			string(v1alpha1.ConditionReasonDriftDetected), "frozen")
+		Expect(r.checkDrift(record, nil, zone, published, "owner")).To(MatchError(errDriftDetected))
+
+		record.Annotations = map[string]string{v1alpha1.DriftAcknowledgedAnnotation: "true"}
+		Expect(r.checkDrift(record, nil, zone, published, "owner")).To(Succeed())
+		Expect(meta.FindStatusCondition(record.Status.Conditions, string(v1alpha1.ConditionTypeDriftDetected))).To(BeNil())
+	})
+
+	It("should not compare endpoints that failed to publish", func() {
+		record := &v1alpha1.DNSRecord{}
+		setDNSRecordCondition(record, string(v1alpha1.ConditionTypeReady), metav1.ConditionFalse, "ProviderError", "failed")
+		published := []*externaldnsendpoint.Endpoint{ownedEndpoint("foo.example.com", "", 60, "1.1.1.1")}
+
+		r := &DNSRecordReconciler{FreezeOnDrift: true}
+		Expect(r.checkDrift(record, nil, nil, published, "owner")).To(Succeed())
+	})
+})
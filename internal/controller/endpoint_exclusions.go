@@ -0,0 +1,132 @@
+package controller
+
+import (
+	"fmt"
+	"net/netip"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/types"
+	externaldnsendpoint "sigs.k8s.io/external-dns/endpoint"
+
+	"github.com/kuadrant/dns-operator/api/v1alpha1"
+)
+
+// EndpointExclusions filter endpoints out of DNSRecords before they are planned, so they are never published, e.g.
+// cluster internal IP ranges or reserved hostnames that must never leak into a public zone.
+type EndpointExclusions struct {
+	// DNSNames endpoints with a DNS name matching any of the expressions are excluded
+	DNSNames []*regexp.Regexp
+	// TargetCIDRs A and AAAA targets in any of the ranges are excluded, endpoints left without targets are excluded
+	TargetCIDRs []netip.Prefix
+}
+
+// NewEndpointExclusions returns the exclusions for the given regular expressions and CIDRs.
+func NewEndpointExclusions(dnsNames, targetCIDRs []string) (EndpointExclusions, error) {
+	exclusions := EndpointExclusions{}
+	for _, expr := range dnsNames {
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return exclusions, fmt.Errorf("invalid DNS name exclusion %q: %w", expr, err)
+		}
+		exclusions.DNSNames = append(exclusions.DNSNames, re)
+	}
+	for _, cidr := range targetCIDRs {
+		prefix, err := netip.ParsePrefix(cidr)
+		if err != nil {
+			return exclusions, fmt.Errorf("invalid target CIDR exclusion %q: %w", cidr, err)
+		}
+		exclusions.TargetCIDRs = append(exclusions.TargetCIDRs, prefix.Masked())
+	}
+	return exclusions, nil
+}
+
+// endpointExclusionsFor returns the exclusions of the operator combined with those of the given record.
+func (r *DNSRecordReconciler) endpointExclusionsFor(dnsRecord *v1alpha1.DNSRecord) (EndpointExclusions, error) {
+	recordExclusions, err := NewEndpointExclusions(dnsRecord.Spec.ExcludeDNSNames, dnsRecord.Spec.ExcludeTargetCIDRs)
+	if err != nil {
+		return EndpointExclusions{}, err
+	}
+	return EndpointExclusions{
+		DNSNames:    append(slices.Clone(r.EndpointExclusions.DNSNames), recordExclusions.DNSNames...),
+		TargetCIDRs: append(slices.Clone(r.EndpointExclusions.TargetCIDRs), recordExclusions.TargetCIDRs...),
+	}, nil
+}
+
+// filter returns the endpoints without the excluded endpoints and targets, and a description of each exclusion.
+// The given endpoints are not modified.
+func (e EndpointExclusions) filter(endpoints []*externaldnsendpoint.Endpoint) ([]*externaldnsendpoint.Endpoint, []string) {
+	if len(e.DNSNames) == 0 && len(e.TargetCIDRs) == 0 {
+		return endpoints, nil
+	}
+
+	var kept []*externaldnsendpoint.Endpoint
+	var excluded []string
+	for _, ep := range endpoints {
+		if slices.ContainsFunc(e.DNSNames, func(re *regexp.Regexp) bool { return re.MatchString(ep.DNSName) }) {
+			excluded = append(excluded, fmt.Sprintf("%s %s", ep.RecordType, ep.DNSName))
+			continue
+		}
+		if ep.RecordType != externaldnsendpoint.RecordTypeA && ep.RecordType != externaldnsendpoint.RecordTypeAAAA {
+			kept = append(kept, ep)
+			continue
+		}
+
+		var targets externaldnsendpoint.Targets
+		for _, target := range ep.Targets {
+			if addr, err := netip.ParseAddr(target); err == nil && slices.ContainsFunc(e.TargetCIDRs, func(p netip.Prefix) bool { return p.Contains(addr.Unmap()) }) {
+				excluded = append(excluded, fmt.Sprintf("%s %s target %s", ep.RecordType, ep.DNSName, target))
+				continue
+			}
+			targets = append(targets, target)
+		}
+		if len(targets) == 0 {
+			continue
+		}
+		if len(targets) != len(ep.Targets) {
+			ep = ep.DeepCopy()
+			ep.Targets = targets
+		}
+		kept = append(kept, ep)
+	}
+	return kept, excluded
+}
+
+// excludedEndpoints remembers the exclusions of each record at its last reconcile, so the event of the exclusions is
+// only emitted when they change.
+type excludedEndpoints struct {
+	lock    sync.Mutex
+	records map[types.NamespacedName]string
+}
+
+func newExcludedEndpoints() *excludedEndpoints {
+	return &excludedEndpoints{records: map[types.NamespacedName]string{}}
+}
+
+// changed remembers the given exclusions of the record and returns true if there are exclusions and they differ from
+// the exclusions of the last reconcile of the record.
+func (e *excludedEndpoints) changed(record types.NamespacedName, excluded []string) bool {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	if len(excluded) == 0 {
+		delete(e.records, record)
+		return false
+	}
+	sorted := slices.Clone(excluded)
+	slices.Sort(sorted)
+	key := strings.Join(sorted, ",")
+	if e.records[record] == key {
+		return false
+	}
+	e.records[record] = key
+	return true
+}
+
+// remove forgets the exclusions of the given record, e.g. when it is deleted.
+func (e *excludedEndpoints) remove(record types.NamespacedName) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	delete(e.records, record)
+}
@@ -0,0 +1,63 @@
+//go:build integration
+
+package controller
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"k8s.io/apimachinery/pkg/types"
+	externaldnsendpoint "sigs.k8s.io/external-dns/endpoint"
+)
+
+var _ = Describe("Endpoint exclusions", func() {
+	a := externaldnsendpoint.NewEndpoint("foo.example.com", externaldnsendpoint.RecordTypeA, "10.0.0.1", "192.0.2.1")
+	aaaa := externaldnsendpoint.NewEndpoint("foo.example.com", externaldnsendpoint.RecordTypeAAAA, "fd00::1")
+	internal := externaldnsendpoint.NewEndpoint("foo.internal.example.com", externaldnsendpoint.RecordTypeA, "192.0.2.2")
+	cname := externaldnsendpoint.NewEndpoint("bar.example.com", externaldnsendpoint.RecordTypeCNAME, "10.0.0.1")
+	endpoints := []*externaldnsendpoint.Endpoint{a, aaaa, internal, cname}
+
+	It("should reject invalid exclusions", func() {
+		_, err := NewEndpointExclusions([]string{"("}, nil)
+		Expect(err).To(MatchError(ContainSubstring(`invalid DNS name exclusion "("`)))
+		_, err = NewEndpointExclusions(nil, []string{"10.0.0.0"})
+		Expect(err).To(MatchError(ContainSubstring(`invalid target CIDR exclusion "10.0.0.0"`)))
+	})
+
+	DescribeTable("should filter the excluded endpoints and targets",
+		func(dnsNames, targetCIDRs []string, expected []*externaldnsendpoint.Endpoint, expectedExcluded []string) {
+			exclusions, err := NewEndpointExclusions(dnsNames, targetCIDRs)
+			Expect(err).NotTo(HaveOccurred())
+			kept, excluded := exclusions.filter(endpoints)
+			Expect(kept).To(Equal(expected))
+			Expect(excluded).To(Equal(expectedExcluded))
+			// the given endpoints are not modified
+			Expect(a.Targets).To(HaveLen(2))
+		},
+		Entry("without exclusions", nil, nil, endpoints, nil),
+		Entry("by DNS name", []string{`\.internal\.`}, nil,
+			[]*externaldnsendpoint.Endpoint{a, aaaa, cname}, []string{"A foo.internal.example.com"}),
+		Entry("by target CIDR", nil, []string{"10.0.0.1/8", "fd00::/8"},
+			[]*externaldnsendpoint.Endpoint{
+				externaldnsendpoint.NewEndpoint("foo.example.com", externaldnsendpoint.RecordTypeA, "192.0.2.1"), internal, cname,
+			},
+			[]string{"A foo.example.com target 10.0.0.1", "AAAA foo.example.com target fd00::1"}),
+		Entry("by DNS name and target CIDR", []string{"^foo\\."}, []string{"192.0.2.0/24"},
+			[]*externaldnsendpoint.Endpoint{cname},
+			[]string{"A foo.example.com", "AAAA foo.example.com", "A foo.internal.example.com"}),
+	)
+
+	It("should only report exclusions that changed", func() {
+		excluded := newExcludedEndpoints()
+		record := types.NamespacedName{Namespace: "default", Name: "record"}
+		Expect(excluded.changed(record, nil)).To(BeFalse())
+		Expect(excluded.changed(record, []string{"A foo.example.com", "A bar.example.com"})).To(BeTrue())
+		Expect(excluded.changed(record, []string{"A bar.example.com", "A foo.example.com"})).To(BeFalse())
+		Expect(excluded.changed(types.NamespacedName{Namespace: "default", Name: "other"}, []string{"A foo.example.com"})).To(BeTrue())
+		Expect(excluded.changed(record, []string{"A foo.example.com"})).To(BeTrue())
+		Expect(excluded.changed(record, nil)).To(BeFalse())
+		Expect(excluded.changed(record, []string{"A foo.example.com"})).To(BeTrue())
+		excluded.remove(record)
+		Expect(excluded.changed(record, []string{"A foo.example.com"})).To(BeTrue())
+	})
+})
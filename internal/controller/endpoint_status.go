@@ -0,0 +1,154 @@
+package controller
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	externaldnsendpoint "sigs.k8s.io/external-dns/endpoint"
+
+	"github.com/kuadrant/dns-operator/api/v1alpha1"
+	"github.com/kuadrant/dns-operator/internal/provider"
+)
+
+func endpointStatusKey(status v1alpha1.EndpointStatus) externaldnsendpoint.EndpointKey {
+	return externaldnsendpoint.EndpointKey{DNSName: status.DNSName, RecordType: status.RecordType, SetIdentifier: status.SetIdentifier}
+}
+
+func endpointKeys(endpoints []*externaldnsendpoint.Endpoint) map[externaldnsendpoint.EndpointKey]struct{} {
+	keys := make(map[externaldnsendpoint.EndpointKey]struct{}, len(endpoints))
+	for _, ep := range endpoints {
+		keys[ep.Key()] = struct{}{}
+	}
+	return keys
+}
+
+// resetEndpointStatuses sets a status for each endpoint of the spec of the record, the statuses are completed as the
+// record is published.
+func resetEndpointStatuses(dnsRecord *v1alpha1.DNSRecord) {
+	statuses := make([]v1alpha1.EndpointStatus, 0, len(dnsRecord.Spec.Endpoints))
+	for _, ep := range dnsRecord.Spec.Endpoints {
+		statuses = append(statuses, v1alpha1.EndpointStatus{DNSName: ep.DNSName, RecordType: ep.RecordType, SetIdentifier: ep.SetIdentifier})
+	}
+	dnsRecord.Status.EndpointStatuses = statuses
+}
+
+// rejectEndpoints removes the endpoints of the spec of the record the given check returns a message for, and sets the
+// status of each with the rejected reason and the message. The spec of the record is not written, the endpoints are
+// only left out of this reconcile.
+func rejectEndpoints(dnsRecord *v1alpha1.DNSRecord, check func(ep *externaldnsendpoint.Endpoint) string) {
+	var kept []*externaldnsendpoint.Endpoint
+	for _, ep := range dnsRecord.Spec.Endpoints {
+		message := check(ep)
+		if message == "" {
+			kept = append(kept, ep)
+			continue
+		}
+		for i := range dnsRecord.Status.EndpointStatuses {
+			status := &dnsRecord.Status.EndpointStatuses[i]
+			if endpointStatusKey(*status) == ep.Key() {
+				status.Published = false
+				status.Reason = v1alpha1.EndpointStatusReasonRejected
+				status.Message = message
+			}
+		}
+	}
+	dnsRecord.Spec.Endpoints = kept
+}
+
+// setPlannedEndpointStatuses sets the status of each endpoint of the spec of the record from the endpoints left after
+// the exclusions and the healthy endpoints that are planned to be published. Endpoints rejected earlier in the
+// reconcile keep their status.
+func setPlannedEndpointStatuses(dnsRecord *v1alpha1.DNSRecord, kept, healthy []*externaldnsendpoint.Endpoint) {
+	specKeys := endpointKeys(dnsRecord.Spec.Endpoints)
+	keptKeys := endpointKeys(kept)
+	healthyKeys := endpointKeys(healthy)
+
+	// the spec may have been replaced since the statuses were reset, e.g. with the parking endpoint
+	var statuses []v1alpha1.EndpointStatus
+	seen := map[externaldnsendpoint.EndpointKey]struct{}{}
+	for _, status := range dnsRecord.Status.EndpointStatuses {
+		key := endpointStatusKey(status)
+		if _, ok := specKeys[key]; ok || status.Reason == v1alpha1.EndpointStatusReasonRejected {
+			statuses = append(statuses, status)
+			seen[key] = struct{}{}
+		}
+	}
+	for _, ep := range dnsRecord.Spec.Endpoints {
+		if _, ok := seen[ep.Key()]; !ok {
+			statuses = append(statuses, v1alpha1.EndpointStatus{DNSName: ep.DNSName, RecordType: ep.RecordType, SetIdentifier: ep.SetIdentifier})
+		}
+	}
+
+	for i := range statuses {
+		status := &statuses[i]
+		if status.Reason == v1alpha1.EndpointStatusReasonRejected {
+			continue
+		}
+		key := endpointStatusKey(*status)
+		_, isKept := keptKeys[key]
+		_, isHealthy := healthyKeys[key]
+		switch {
+		case isHealthy:
+			status.Published, status.Reason, status.Message = true, "", ""
+		case isKept:
+			status.Published, status.Reason, status.Message = false, v1alpha1.EndpointStatusReasonUnhealthy,
+				"The targets of the endpoint failed their health checks"
+		default:
+			status.Published, status.Reason, status.Message = false, v1alpha1.EndpointStatusReasonExcluded,
+				"The endpoint matches an exclusion of the operator or the record"
+		}
+	}
+	dnsRecord.Status.EndpointStatuses = statuses
+}
+
+// failEndpointStatuses sets the endpoints of a record that failed to be published as not published with the error,
+// apart from those that were left out for another reason.
+func failEndpointStatuses(dnsRecord *v1alpha1.DNSRecord, err error) {
+	for i := range dnsRecord.Status.EndpointStatuses {
+		status := &dnsRecord.Status.EndpointStatuses[i]
+		switch status.Reason {
+		case v1alpha1.EndpointStatusReasonRejected, v1alpha1.EndpointStatusReasonExcluded, v1alpha1.EndpointStatusReasonUnhealthy:
+			continue
+		}
+		status.Published = false
+		status.Reason = v1alpha1.EndpointStatusReasonRecordNotPublished
+		status.Message = provider.SanitizeError(err).Error()
+	}
+}
+
+// setPartiallyPublishedCondition sets the PartiallyPublished condition when endpoints of the record were rejected.
+func setPartiallyPublishedCondition(dnsRecord *v1alpha1.DNSRecord) {
+	var rejected []string
+	for _, status := range dnsRecord.Status.EndpointStatuses {
+		if status.Reason == v1alpha1.EndpointStatusReasonRejected {
+			rejected = append(rejected, strings.TrimSpace(fmt.Sprintf("%s %s %s", status.RecordType, status.DNSName, status.SetIdentifier)))
+		}
+	}
+	if len(rejected) == 0 {
+		meta.RemoveStatusCondition(&dnsRecord.Status.Conditions, string(v1alpha1.ConditionTypePartiallyPublished))
+		return
+	}
+	setDNSRecordCondition(dnsRecord, string(v1alpha1.ConditionTypePartiallyPublished), metav1.ConditionTrue,
+		string(v1alpha1.ConditionReasonEndpointsRejected), fmt.Sprintf("Endpoints failed validation and are not published: %s", strings.Join(rejected, ", ")))
+}
+
+// rootHostMismatch returns a check rejecting endpoints with a DNS name that is not the given root host or a subdomain.
+func rootHostMismatch(rootHost string) func(ep *externaldnsendpoint.Endpoint) string {
+	root, _ := strings.CutPrefix(rootHost, v1alpha1.WildcardPrefix)
+	return func(ep *externaldnsendpoint.Endpoint) string {
+		if strings.HasSuffix(ep.DNSName, root) {
+			return ""
+		}
+		return fmt.Sprintf("the DNS name must be equal to or end with the rootHost %s", root)
+	}
+}
+
+// invalidTargets rejects endpoints with a target that is not valid for their record type.
+func invalidTargets(ep *externaldnsendpoint.Endpoint) string {
+	if err := v1alpha1.ValidateEndpointTargets(ep); err != nil {
+		return err.Error()
+	}
+	return ""
+}
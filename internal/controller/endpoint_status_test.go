@@ -0,0 +1,83 @@
+//go:build integration
+
+package controller
+
+import (
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	externaldnsendpoint "sigs.k8s.io/external-dns/endpoint"
+
+	"github.com/kuadrant/dns-operator/api/v1alpha1"
+)
+
+var _ = Describe("Endpoint statuses", func() {
+	var (
+		dnsRecord           *v1alpha1.DNSRecord
+		root, www, excluded *externaldnsendpoint.Endpoint
+	)
+
+	BeforeEach(func() {
+		root = externaldnsendpoint.NewEndpoint("foo.example.com", externaldnsendpoint.RecordTypeA, "1.1.1.1")
+		www = externaldnsendpoint.NewEndpoint("www.foo.example.com", externaldnsendpoint.RecordTypeCNAME, "foo.example.com")
+		excluded = externaldnsendpoint.NewEndpoint("internal.foo.example.com", externaldnsendpoint.RecordTypeA, "10.0.0.1")
+		dnsRecord = &v1alpha1.DNSRecord{
+			Spec: v1alpha1.DNSRecordSpec{
+				RootHost: "foo.example.com",
+				Endpoints: []*externaldnsendpoint.Endpoint{
+					root, www, excluded,
+					externaldnsendpoint.NewEndpoint("foo.example.org", externaldnsendpoint.RecordTypeA, "1.1.1.1"),
+				},
+			},
+		}
+		resetEndpointStatuses(dnsRecord)
+	})
+
+	It("should report the state of each endpoint of a partially published record", func() {
+		rejectEndpoints(dnsRecord, rootHostMismatch(dnsRecord.Spec.RootHost))
+		Expect(dnsRecord.Spec.Endpoints).To(HaveLen(3))
+
+		setPlannedEndpointStatuses(dnsRecord, []*externaldnsendpoint.Endpoint{root, www}, []*externaldnsendpoint.Endpoint{root})
+		Expect(dnsRecord.Status.EndpointStatuses).To(Equal([]v1alpha1.EndpointStatus{
+			{DNSName: "foo.example.com", RecordType: "A", Published: true},
+			{DNSName: "www.foo.example.com", RecordType: "CNAME", Reason: v1alpha1.EndpointStatusReasonUnhealthy,
+				Message: "The targets of the endpoint failed their health checks"},
+			{DNSName: "internal.foo.example.com", RecordType: "A", Reason: v1alpha1.EndpointStatusReasonExcluded,
+				Message: "The endpoint matches an exclusion of the operator or the record"},
+			{DNSName: "foo.example.org", RecordType: "A", Reason: v1alpha1.EndpointStatusReasonRejected,
+				Message: "the DNS name must be equal to or end with the rootHost foo.example.com"},
+		}))
+
+		setPartiallyPublishedCondition(dnsRecord)
+		cond := meta.FindStatusCondition(dnsRecord.Status.Conditions, string(v1alpha1.ConditionTypePartiallyPublished))
+		Expect(cond).NotTo(BeNil())
+		Expect(cond.Reason).To(Equal(string(v1alpha1.ConditionReasonEndpointsRejected)))
+		Expect(cond.Message).To(ContainSubstring("A foo.example.org"))
+	})
+
+	It("should report the endpoints of a record that failed to publish", func() {
+		setPlannedEndpointStatuses(dnsRecord, []*externaldnsendpoint.Endpoint{root, www}, []*externaldnsendpoint.Endpoint{root, www})
+		failEndpointStatuses(dnsRecord, errors.New("throttled"))
+		for _, status := range dnsRecord.Status.EndpointStatuses[:2] {
+			Expect(status.Published).To(BeFalse())
+			Expect(status.Reason).To(Equal(v1alpha1.EndpointStatusReasonRecordNotPublished))
+			Expect(status.Message).To(Equal("throttled"))
+		}
+		Expect(dnsRecord.Status.EndpointStatuses[2].Reason).To(Equal(v1alpha1.EndpointStatusReasonExcluded))
+
+		setPartiallyPublishedCondition(dnsRecord)
+		Expect(meta.FindStatusCondition(dnsRecord.Status.Conditions, string(v1alpha1.ConditionTypePartiallyPublished))).To(BeNil())
+	})
+
+	It("should report the endpoints that replaced the spec", func() {
+		parking := externaldnsendpoint.NewEndpoint("foo.example.com", externaldnsendpoint.RecordTypeCNAME, "parking.example.net")
+		dnsRecord.Spec.Endpoints = []*externaldnsendpoint.Endpoint{parking}
+		setPlannedEndpointStatuses(dnsRecord, dnsRecord.Spec.Endpoints, dnsRecord.Spec.Endpoints)
+		Expect(dnsRecord.Status.EndpointStatuses).To(Equal([]v1alpha1.EndpointStatus{
+			{DNSName: "foo.example.com", RecordType: "CNAME", Published: true},
+		}))
+	})
+})
@@ -0,0 +1,151 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kuadrant/dns-operator/api/v1alpha1"
+)
+
+// healthReports tracks when the EndpointsHealthy condition of each record was last written, so changes to it are
+// written at most once per debounce window.
+type healthReports struct {
+	lock    sync.Mutex
+	written map[types.NamespacedName]time.Time
+}
+
+// wait returns how long until the condition of the given record can be written again.
+func (h *healthReports) wait(key types.NamespacedName, window time.Duration, now time.Time) time.Duration {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	written, ok := h.written[key]
+	if !ok {
+		return 0
+	}
+	return max(written.Add(window).Sub(now), 0)
+}
+
+func (h *healthReports) record(key types.NamespacedName, now time.Time) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	if h.written == nil {
+		h.written = map[types.NamespacedName]time.Time{}
+	}
+	h.written[key] = now
+}
+
+func (h *healthReports) forget(key types.NamespacedName) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	delete(h.written, key)
+}
+
+// endpointsHealthyCondition returns the EndpointsHealthy condition aggregating the health of the given probes, or nil
+// if there are none. Probes that have not been probed yet are not healthy.
+func endpointsHealthyCondition(probes []v1alpha1.DNSHealthCheckProbe) *metav1.Condition {
+	if len(probes) == 0 {
+		return nil
+	}
+	var notHealthy []string
+	for _, probe := range probes {
+		if probe.Status.Healthy == nil || !*probe.Status.Healthy {
+			notHealthy = append(notHealthy, probe.Spec.Address)
+		}
+	}
+	slices.Sort(notHealthy)
+
+	healthy := len(probes) - len(notHealthy)
+	message := fmt.Sprintf("%d of %d endpoints healthy", healthy, len(probes))
+	if len(notHealthy) > 0 {
+		message = fmt.Sprintf("%s, not healthy: %s", message, strings.Join(notHealthy, ", "))
+	}
+	cond := &metav1.Condition{
+		Type:    string(v1alpha1.ConditionTypeEndpointsHealthy),
+		Status:  metav1.ConditionFalse,
+		Reason:  string(v1alpha1.ConditionReasonPartiallyHealthy),
+		Message: message,
+	}
+	switch healthy {
+	case len(probes):
+		cond.Status = metav1.ConditionTrue
+		cond.Reason = string(v1alpha1.ConditionReasonHealthy)
+	case 0:
+		cond.Reason = string(v1alpha1.ConditionReasonUnhealthy)
+	}
+	return cond
+}
+
+// reportEndpointsHealth sets the EndpointsHealthy condition of the record owning the probe to the aggregated health of
+// all of its probes, so the record shows both its publishing and health state. The condition is removed once the record
+// has no probes. It is only written when it changes, and at most once per debounce window, a change within the window
+// is written when the window ends.
+func (r *DNSProbeReconciler) reportEndpointsHealth(ctx context.Context, probe *v1alpha1.DNSHealthCheckProbe) (ctrl.Result, error) {
+	owner := metav1.GetControllerOf(probe)
+	if owner == nil || owner.Kind != "DNSRecord" {
+		return ctrl.Result{}, nil
+	}
+	key := types.NamespacedName{Namespace: probe.Namespace, Name: owner.Name}
+	dnsRecord := &v1alpha1.DNSRecord{}
+	if err := r.Get(ctx, key, dnsRecord); err != nil {
+		if apierrors.IsNotFound(err) {
+			r.healthReports.forget(key)
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+	if dnsRecord.DeletionTimestamp != nil {
+		return ctrl.Result{}, nil
+	}
+
+	probes := &v1alpha1.DNSHealthCheckProbeList{}
+	if err := r.List(ctx, probes, &client.ListOptions{
+		LabelSelector: labels.SelectorFromSet(map[string]string{ProbeOwnerLabel: BuildOwnerLabelValue(dnsRecord)}),
+		Namespace:     dnsRecord.Namespace,
+	}); err != nil {
+		return ctrl.Result{}, err
+	}
+	probes.Items = slices.DeleteFunc(probes.Items, func(p v1alpha1.DNSHealthCheckProbe) bool {
+		return p.DeletionTimestamp != nil
+	})
+
+	desired := endpointsHealthyCondition(probes.Items)
+	current := meta.FindStatusCondition(dnsRecord.Status.Conditions, string(v1alpha1.ConditionTypeEndpointsHealthy))
+	if desired == nil && current == nil {
+		return ctrl.Result{}, nil
+	}
+	if desired != nil && current != nil && desired.Status == current.Status && desired.Reason == current.Reason &&
+		desired.Message == current.Message {
+		return ctrl.Result{}, nil
+	}
+	now := time.Now()
+	if wait := r.healthReports.wait(key, r.EndpointsHealthyDebounce, now); wait > 0 {
+		return ctrl.Result{RequeueAfter: wait}, nil
+	}
+
+	patchFrom := client.MergeFromWithOptions(dnsRecord.DeepCopy(), client.MergeFromWithOptimisticLock{})
+	if desired == nil {
+		meta.RemoveStatusCondition(&dnsRecord.Status.Conditions, string(v1alpha1.ConditionTypeEndpointsHealthy))
+	} else {
+		setDNSRecordCondition(dnsRecord, desired.Type, desired.Status, desired.Reason, desired.Message)
+	}
+	if err := r.Status().Patch(ctx, dnsRecord, patchFrom); err != nil {
+		if apierrors.IsConflict(err) {
+			return ctrl.Result{Requeue: true}, nil
+		}
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	r.healthReports.record(key, now)
+	return ctrl.Result{}, nil
+}
@@ -0,0 +1,115 @@
+//go:build integration
+
+package controller
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kuadrant/dns-operator/api/v1alpha1"
+)
+
+var _ = Describe("Endpoints healthy condition", func() {
+	var (
+		dnsRecord *v1alpha1.DNSRecord
+		probes    []*v1alpha1.DNSHealthCheckProbe
+	)
+
+	newProbe := func(address string, healthy *bool) *v1alpha1.DNSHealthCheckProbe {
+		probe := &v1alpha1.DNSHealthCheckProbe{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo-" + address,
+				Namespace: "default",
+				Labels:    map[string]string{ProbeOwnerLabel: "foo"},
+			},
+			Spec: v1alpha1.DNSHealthCheckProbeSpec{Address: address},
+		}
+		probe.Status.Healthy = healthy
+		probe.OwnerReferences = []metav1.OwnerReference{{APIVersion: v1alpha1.GroupVersion.String(), Kind: "DNSRecord",
+			Name: "foo", UID: "foo-uid", Controller: ptr.To(true)}}
+		return probe
+	}
+
+	BeforeEach(func() {
+		Expect(v1alpha1.AddToScheme(scheme.Scheme)).To(Succeed())
+		dnsRecord = &v1alpha1.DNSRecord{ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "default", UID: "foo-uid"}}
+		probes = []*v1alpha1.DNSHealthCheckProbe{
+			newProbe("1.1.1.1", ptr.To(true)),
+			newProbe("2.2.2.2", ptr.To(false)),
+			newProbe("3.3.3.3", nil),
+		}
+	})
+
+	It("should aggregate the health of the probes", func() {
+		items := []v1alpha1.DNSHealthCheckProbe{*probes[0], *probes[1], *probes[2]}
+		cond := endpointsHealthyCondition(items)
+		Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+		Expect(cond.Reason).To(Equal(string(v1alpha1.ConditionReasonPartiallyHealthy)))
+		Expect(cond.Message).To(Equal("1 of 3 endpoints healthy, not healthy: 2.2.2.2, 3.3.3.3"))
+
+		cond = endpointsHealthyCondition(items[:1])
+		Expect(cond.Status).To(Equal(metav1.ConditionTrue))
+		Expect(cond.Reason).To(Equal(string(v1alpha1.ConditionReasonHealthy)))
+		Expect(cond.Message).To(Equal("1 of 1 endpoints healthy"))
+
+		cond = endpointsHealthyCondition(items[1:])
+		Expect(cond.Reason).To(Equal(string(v1alpha1.ConditionReasonUnhealthy)))
+
+		Expect(endpointsHealthyCondition(nil)).To(BeNil())
+	})
+
+	It("should write the condition to the record of the probe, debounced", func() {
+		builder := fake.NewClientBuilder().WithStatusSubresource(&v1alpha1.DNSRecord{}, &v1alpha1.DNSHealthCheckProbe{}).WithObjects(dnsRecord)
+		for _, probe := range probes {
+			builder = builder.WithObjects(probe)
+		}
+		k8sClient := builder.Build()
+		r := &DNSProbeReconciler{Client: k8sClient, EndpointsHealthyDebounce: time.Minute}
+
+		condition := func() *metav1.Condition {
+			stored := &v1alpha1.DNSRecord{}
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(dnsRecord), stored)).To(Succeed())
+			return meta.FindStatusCondition(stored.Status.Conditions, string(v1alpha1.ConditionTypeEndpointsHealthy))
+		}
+
+		result, err := r.reportEndpointsHealth(ctx, probes[0])
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).To(BeZero())
+		Expect(condition().Message).To(Equal("1 of 3 endpoints healthy, not healthy: 2.2.2.2, 3.3.3.3"))
+
+		// unchanged health is not written again
+		result, err = r.reportEndpointsHealth(ctx, probes[1])
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).To(BeZero())
+
+		// changes within the debounce window are written when it ends
+		probes[1].Status.Healthy = ptr.To(true)
+		Expect(k8sClient.Status().Update(ctx, probes[1])).To(Succeed())
+		result, err = r.reportEndpointsHealth(ctx, probes[1])
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).To(BeNumerically("~", time.Minute, time.Second))
+		Expect(condition().Message).To(Equal("1 of 3 endpoints healthy, not healthy: 2.2.2.2, 3.3.3.3"))
+
+		r.EndpointsHealthyDebounce = 0
+		_, err = r.reportEndpointsHealth(ctx, probes[1])
+		Expect(err).NotTo(HaveOccurred())
+		Expect(condition().Message).To(Equal("2 of 3 endpoints healthy, not healthy: 3.3.3.3"))
+
+		// the condition is removed once the record has no probes
+		for _, probe := range probes {
+			Expect(k8sClient.Delete(ctx, probe)).To(Succeed())
+		}
+		_, err = r.reportEndpointsHealth(ctx, probes[0])
+		Expect(err).NotTo(HaveOccurred())
+		Expect(condition()).To(BeNil())
+	})
+})
@@ -0,0 +1,165 @@
+package controller
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/flowcontrol"
+
+	"github.com/kuadrant/dns-operator/internal/metrics"
+)
+
+const (
+	// maxSuppressedWindows is the number of windows the count of suppressed events of an object is kept for, if no
+	// other event of the object with the same reason is recorded.
+	maxSuppressedWindows = 10
+	// eventBurst is the number of events that can be recorded at once before the event rate limit applies.
+	eventBurst = 25
+)
+
+// eventKey identifies the events of an object that are aggregated together.
+type eventKey struct {
+	uid       types.UID
+	eventType string
+	reason    string
+}
+
+// eventAggregate is the time the last event of a key was recorded and how many were suppressed since.
+type eventAggregate struct {
+	recorded   time.Time
+	suppressed int
+}
+
+// aggregatingRecorder is an EventRecorder that records the first event of each reason and type of an object in a
+// window, and counts the rest. The count is added to the message of the next event of the reason after the window.
+// Events of all objects over the rate limit are dropped. During a provider outage every record fails with the same
+// warning on every reconcile, the recorder keeps those from flooding the API server.
+type aggregatingRecorder struct {
+	record.EventRecorder
+
+	window  time.Duration
+	limiter flowcontrol.RateLimiter
+
+	mu         sync.Mutex
+	aggregates map[eventKey]*eventAggregate
+	pruned     time.Time
+	now        func() time.Time
+}
+
+// EventLimits aggregates and rate limits the events of the recorders of the controllers of the operator. The rate limit
+// is shared by all the recorders wrapped with the same limits.
+type EventLimits struct {
+	window  time.Duration
+	limiter flowcontrol.RateLimiter
+}
+
+// NewEventLimits returns the limits that aggregate events of an object with the same reason within the window, and
+// record at most ratePerSecond events with bursts of up to 25 events. Either is disabled if 0.
+func NewEventLimits(window time.Duration, ratePerSecond float32) *EventLimits {
+	return newEventLimits(window, ratePerSecond, eventBurst)
+}
+
+func newEventLimits(window time.Duration, ratePerSecond float32, burst int) *EventLimits {
+	limits := &EventLimits{window: window}
+	if ratePerSecond > 0 {
+		limits.limiter = flowcontrol.NewTokenBucketRateLimiter(ratePerSecond, max(burst, 1))
+	}
+	return limits
+}
+
+// Wrap returns the recorder with the limits applied, the recorder is returned as it is without limits.
+func (l *EventLimits) Wrap(recorder record.EventRecorder) record.EventRecorder {
+	if l == nil || (l.window <= 0 && l.limiter == nil) {
+		return recorder
+	}
+	return &aggregatingRecorder{
+		EventRecorder: recorder,
+		window:        l.window,
+		limiter:       l.limiter,
+		aggregates:    map[eventKey]*eventAggregate{},
+		now:           time.Now,
+	}
+}
+
+// newAggregatingRecorder returns the recorder wrapped to aggregate events of an object with the same reason within the
+// window, and to record at most ratePerSecond events, with bursts of up to burst events. Either is disabled if 0, the
+// recorder is returned as it is if both are.
+func newAggregatingRecorder(recorder record.EventRecorder, window time.Duration, ratePerSecond float32, burst int) record.EventRecorder {
+	return newEventLimits(window, ratePerSecond, burst).Wrap(recorder)
+}
+
+func (r *aggregatingRecorder) Event(object runtime.Object, eventType, reason, message string) {
+	if message, ok := r.admit(object, eventType, reason, message); ok {
+		r.EventRecorder.Event(object, eventType, reason, message)
+	}
+}
+
+func (r *aggregatingRecorder) Eventf(object runtime.Object, eventType, reason, messageFmt string, args ...interface{}) {
+	r.Event(object, eventType, reason, fmt.Sprintf(messageFmt, args...))
+}
+
+func (r *aggregatingRecorder) AnnotatedEventf(object runtime.Object, annotations map[string]string, eventType, reason, messageFmt string, args ...interface{}) {
+	if message, ok := r.admit(object, eventType, reason, fmt.Sprintf(messageFmt, args...)); ok {
+		r.EventRecorder.AnnotatedEventf(object, annotations, eventType, reason, "%s", message)
+	}
+}
+
+// admit returns true and the message to record if the event is to be recorded, with the number of similar events
+// suppressed since the last one added to the message.
+func (r *aggregatingRecorder) admit(object runtime.Object, eventType, reason, message string) (string, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.now()
+	var aggregate *eventAggregate
+	if r.window > 0 {
+		if accessor, err := meta.Accessor(object); err == nil {
+			r.prune(now)
+			key := eventKey{uid: accessor.GetUID(), eventType: eventType, reason: reason}
+			if aggregate = r.aggregates[key]; aggregate == nil {
+				aggregate = &eventAggregate{}
+				r.aggregates[key] = aggregate
+			} else if now.Sub(aggregate.recorded) < r.window {
+				aggregate.suppressed++
+				metrics.EventsSuppressed.WithLabelValues(reason).Inc()
+				return "", false
+			}
+		}
+	}
+
+	if r.limiter != nil && !r.limiter.TryAccept() {
+		if aggregate != nil {
+			aggregate.suppressed++
+		}
+		metrics.EventsSuppressed.WithLabelValues(reason).Inc()
+		return "", false
+	}
+
+	if aggregate != nil {
+		if aggregate.suppressed > 0 {
+			message = fmt.Sprintf("%s (%d similar events suppressed)", message, aggregate.suppressed)
+		}
+		aggregate.recorded, aggregate.suppressed = now, 0
+	}
+	return message, true
+}
+
+// prune removes the aggregates of events last recorded more than a window ago, at most once a window. Aggregates with
+// suppressed events are kept for the count to be reported with the next event, up to maxSuppressedWindows windows.
+func (r *aggregatingRecorder) prune(now time.Time) {
+	if now.Sub(r.pruned) < r.window {
+		return
+	}
+	for key, aggregate := range r.aggregates {
+		age := now.Sub(aggregate.recorded)
+		if age >= r.window && (aggregate.suppressed == 0 || age >= maxSuppressedWindows*r.window) {
+			delete(r.aggregates, key)
+		}
+	}
+	r.pruned = now
+}
@@ -0,0 +1,92 @@
+//go:build integration
+
+package controller
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/kuadrant/dns-operator/api/v1alpha1"
+)
+
+var _ = Describe("Aggregating event recorder", func() {
+	var (
+		fake     *record.FakeRecorder
+		now      time.Time
+		foo, bar *v1alpha1.DNSRecord
+	)
+
+	BeforeEach(func() {
+		fake = record.NewFakeRecorder(100)
+		now = time.Now()
+		foo = &v1alpha1.DNSRecord{ObjectMeta: metav1.ObjectMeta{Name: "foo", UID: "foo-uid"}}
+		bar = &v1alpha1.DNSRecord{ObjectMeta: metav1.ObjectMeta{Name: "bar", UID: "bar-uid"}}
+	})
+
+	recorded := func() []string {
+		var events []string
+		for {
+			select {
+			case event := <-fake.Events:
+				events = append(events, event)
+			default:
+				return events
+			}
+		}
+	}
+
+	It("should return the recorder when disabled", func() {
+		Expect(newAggregatingRecorder(fake, 0, 0, eventBurst)).To(BeIdenticalTo(fake))
+	})
+
+	It("should suppress similar events of a record within the window", func() {
+		recorder := newAggregatingRecorder(fake, time.Minute, 0, eventBurst).(*aggregatingRecorder)
+		recorder.now = func() time.Time { return now }
+
+		for range 3 {
+			recorder.Eventf(foo, v1.EventTypeWarning, "ProviderError", "provider unavailable")
+		}
+		recorder.Eventf(foo, v1.EventTypeNormal, "ApplyingChanges", "Applying 1 changes")
+		recorder.Eventf(bar, v1.EventTypeWarning, "ProviderError", "provider unavailable")
+		Expect(recorded()).To(Equal([]string{
+			"Warning ProviderError provider unavailable",
+			"Normal ApplyingChanges Applying 1 changes",
+			"Warning ProviderError provider unavailable",
+		}))
+
+		now = now.Add(time.Minute)
+		recorder.Eventf(foo, v1.EventTypeWarning, "ProviderError", "provider unavailable")
+		recorder.Eventf(bar, v1.EventTypeWarning, "ProviderError", "provider unavailable")
+		Expect(recorded()).To(Equal([]string{
+			"Warning ProviderError provider unavailable (2 similar events suppressed)",
+			"Warning ProviderError provider unavailable",
+		}))
+	})
+
+	It("should drop events over the rate limit", func() {
+		recorder := newAggregatingRecorder(fake, 0, 0.001, 2)
+		for range 5 {
+			recorder.Eventf(foo, v1.EventTypeWarning, "ProviderError", "provider unavailable")
+		}
+		Expect(recorded()).To(HaveLen(2))
+	})
+
+	It("should share the rate limit between the recorders wrapped with the same limits", func() {
+		limits := newEventLimits(0, 0.001, 2)
+		records, gateways := limits.Wrap(fake), limits.Wrap(fake)
+		records.Eventf(foo, v1.EventTypeWarning, "ProviderError", "provider unavailable")
+		gateways.Eventf(bar, v1.EventTypeWarning, "NoProvider", "no provider")
+		records.Eventf(bar, v1.EventTypeWarning, "ProviderError", "provider unavailable")
+		gateways.Eventf(foo, v1.EventTypeWarning, "NoProvider", "no provider")
+		Expect(recorded()).To(HaveLen(2))
+
+		Expect((*EventLimits)(nil).Wrap(fake)).To(BeIdenticalTo(fake))
+		Expect(NewEventLimits(0, 0).Wrap(fake)).To(BeIdenticalTo(fake))
+	})
+})
@@ -0,0 +1,26 @@
+package controller
+
+import (
+	"context"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kuadrant/dns-operator/api/v1alpha1"
+)
+
+// removeAnnotation removes the given annotation from the record once it is handled. The record is patched so the
+// spec, which may have been expanded while reconciling, is not written.
+func (r *DNSRecordReconciler) removeAnnotation(ctx context.Context, dnsRecord *v1alpha1.DNSRecord, annotation string) error {
+	record := dnsRecord.DeepCopy()
+	patch := client.MergeFrom(record.DeepCopy())
+	annotations := record.GetAnnotations()
+	delete(annotations, annotation)
+	record.SetAnnotations(annotations)
+	if err := r.Patch(ctx, record, patch); err != nil {
+		return err
+	}
+	// the status of the record is updated next, with the version of the patched record
+	dnsRecord.SetAnnotations(record.GetAnnotations())
+	dnsRecord.SetResourceVersion(record.GetResourceVersion())
+	return nil
+}
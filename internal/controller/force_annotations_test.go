@@ -0,0 +1,61 @@
+//go:build integration
+
+package controller
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kuadrant/dns-operator/api/v1alpha1"
+)
+
+var _ = Describe("Force annotations", func() {
+	var dnsRecord *v1alpha1.DNSRecord
+
+	BeforeEach(func() {
+		Expect(v1alpha1.AddToScheme(scheme.Scheme)).To(Succeed())
+		dnsRecord = &v1alpha1.DNSRecord{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "force",
+				Namespace: "default",
+				Annotations: map[string]string{
+					v1alpha1.ReconcileRequestAnnotation: "2024-01-01T00:00:00Z",
+					v1alpha1.ForceApplyAnnotation:       "true",
+				},
+			},
+		}
+	})
+
+	It("should request a reconcile until the reconcile request annotation is handled", func() {
+		Expect(reconcileRequested(dnsRecord)).To(BeTrue())
+
+		dnsRecord.Status.LastHandledReconcileRequest = "2024-01-01T00:00:00Z"
+		Expect(reconcileRequested(dnsRecord)).To(BeFalse())
+	})
+
+	It("should force apply only when the annotation is true", func() {
+		Expect(dnsRecord.IsForceApply()).To(BeTrue())
+		dnsRecord.Annotations[v1alpha1.ForceApplyAnnotation] = "false"
+		Expect(dnsRecord.IsForceApply()).To(BeFalse())
+	})
+
+	It("should remove a handled annotation without writing the spec", func() {
+		r := &DNSRecordReconciler{Client: fake.NewClientBuilder().WithObjects(dnsRecord.DeepCopy()).Build()}
+		dnsRecord.Spec.RootHost = "expanded.example.com"
+
+		Expect(r.removeAnnotation(ctx, dnsRecord, v1alpha1.ForceApplyAnnotation)).To(Succeed())
+		Expect(dnsRecord.IsForceApply()).To(BeFalse())
+
+		stored := &v1alpha1.DNSRecord{}
+		Expect(r.Get(ctx, client.ObjectKeyFromObject(dnsRecord), stored)).To(Succeed())
+		Expect(stored.Annotations).ToNot(HaveKey(v1alpha1.ForceApplyAnnotation))
+		Expect(stored.Annotations).To(HaveKey(v1alpha1.ReconcileRequestAnnotation))
+		Expect(stored.Spec.RootHost).To(BeEmpty())
+		Expect(dnsRecord.ResourceVersion).To(Equal(stored.ResourceVersion))
+	})
+})
@@ -0,0 +1,234 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	gatewayapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	"github.com/kuadrant/dns-operator/api/v1alpha1"
+	"github.com/kuadrant/dns-operator/internal/common/hash"
+)
+
+// GatewayLabel is the label of the DNSRecords created for a Gateway, set to the name of the Gateway.
+const GatewayLabel = "kuadrant.io/gateway"
+
+// errNoGatewayProvider is returned for Gateways without a provider secret for their records.
+var errNoGatewayProvider = fmt.Errorf("gateway has no %s annotation and its namespace has no %s annotation",
+	v1alpha1.GatewayDNSProviderRefAnnotation, v1alpha1.DefaultProviderRefAnnotation)
+
+// GatewayReconciler creates a DNSRecord for each listener hostname of the Gateways with the GatewayDNSZoneAnnotation
+// that is in the zone of the annotation. The targets of the records are the addresses of the Gateway, kept up to date
+// by the DNSRecord controller through the gateway endpoints of the records. Records are removed with their Gateway, or
+// once their hostname is no longer a listener hostname in the zone.
+type GatewayReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+	// WatchNamespaceSelector selects the namespaces of the Gateways records are created for by the labels of the
+	// namespace, all namespaces are watched if nil
+	WatchNamespaceSelector labels.Selector
+}
+
+//+kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=gateways,verbs=get;list;watch
+//+kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=gateways/finalizers,verbs=update
+//+kubebuilder:rbac:groups=kuadrant.io,resources=dnsrecords,verbs=get;list;watch;create;update;patch;delete
+
+func (r *GatewayReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx).WithName("gateway_controller")
+	ctx = log.IntoContext(ctx, logger)
+
+	gateway := &gatewayapiv1.Gateway{}
+	if err := r.Get(ctx, req.NamespacedName, gateway); err != nil {
+		// records of deleted gateways are garbage collected
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	watched, err := namespaceWatched(ctx, r.Client, r.WatchNamespaceSelector, gateway.Namespace)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if !watched {
+		logger.V(1).Info("namespace does not match the watch namespace selector, skipping")
+		return ctrl.Result{}, nil
+	}
+
+	desired, err := r.desiredRecords(ctx, gateway)
+	if errors.Is(err, errNoGatewayProvider) {
+		// the gateway is reconciled again once it or its namespace is annotated with a provider
+		logger.Info("Unable to determine the provider of the DNSRecords of the gateway, the existing records are left as they are")
+		r.Recorder.Eventf(gateway, v1.EventTypeWarning, "NoProvider", "DNSRecords of the gateway are not created or updated: %v", err)
+		return ctrl.Result{}, nil
+	}
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	existing := &v1alpha1.DNSRecordList{}
+	if err = r.List(ctx, existing, client.InNamespace(gateway.Namespace), client.MatchingLabels{GatewayLabel: gateway.Name}); err != nil {
+		return ctrl.Result{}, err
+	}
+	for i := range existing.Items {
+		record := &existing.Items[i]
+		if !metav1.IsControlledBy(record, gateway) || slices.ContainsFunc(desired, func(d *v1alpha1.DNSRecord) bool {
+			return d.Name == record.Name
+		}) {
+			continue
+		}
+		logger.Info("Deleting DNSRecord of hostname no longer in the zone", "dnsRecord", record.Name, "rootHost", record.Spec.RootHost)
+		if err = r.Delete(ctx, record); client.IgnoreNotFound(err) != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	for _, record := range desired {
+		if err = r.ensureRecord(ctx, gateway, record); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	return ctrl.Result{}, nil
+}
+
+// ensureRecord creates the given record, or updates the existing record of the same name to it.
+func (r *GatewayReconciler) ensureRecord(ctx context.Context, gateway *gatewayapiv1.Gateway, desired *v1alpha1.DNSRecord) error {
+	logger := log.FromContext(ctx)
+	existing := &v1alpha1.DNSRecord{}
+	err := r.Get(ctx, client.ObjectKeyFromObject(desired), existing)
+	if apierrors.IsNotFound(err) {
+		logger.Info("Creating DNSRecord", "dnsRecord", desired.Name, "rootHost", desired.Spec.RootHost)
+		return r.Create(ctx, desired)
+	}
+	if err != nil {
+		return err
+	}
+	if !metav1.IsControlledBy(existing, gateway) {
+		logger.Info("DNSRecord exists and is not managed by the gateway, skipping", "dnsRecord", existing.Name)
+		return nil
+	}
+	if existing.Spec.ProviderRef == desired.Spec.ProviderRef &&
+		equality.Semantic.DeepEqual(existing.Spec.GatewayEndpoints, desired.Spec.GatewayEndpoints) {
+		return nil
+	}
+	existing.Spec.ProviderRef = desired.Spec.ProviderRef
+	existing.Spec.GatewayEndpoints = desired.Spec.GatewayEndpoints
+	logger.Info("Updating DNSRecord", "dnsRecord", existing.Name, "rootHost", existing.Spec.RootHost)
+	return r.Update(ctx, existing)
+}
+
+// desiredRecords returns the records of the listener hostnames of the gateway in the zone of its annotation, none if
+// the gateway is not annotated.
+func (r *GatewayReconciler) desiredRecords(ctx context.Context, gateway *gatewayapiv1.Gateway) ([]*v1alpha1.DNSRecord, error) {
+	zone := strings.TrimSuffix(strings.ToLower(gateway.GetAnnotations()[v1alpha1.GatewayDNSZoneAnnotation]), ".")
+	if zone == "" || gateway.DeletionTimestamp != nil {
+		return nil, nil
+	}
+
+	providerRef := gateway.GetAnnotations()[v1alpha1.GatewayDNSProviderRefAnnotation]
+	if providerRef == "" {
+		namespace := &v1.Namespace{}
+		if err := r.Get(ctx, client.ObjectKey{Name: gateway.Namespace}, namespace); err != nil {
+			return nil, err
+		}
+		providerRef = namespace.GetAnnotations()[v1alpha1.DefaultProviderRefAnnotation]
+	}
+	if providerRef == "" {
+		return nil, errNoGatewayProvider
+	}
+
+	var records []*v1alpha1.DNSRecord
+	for _, hostname := range gatewayHostnames(gateway, zone) {
+		record := &v1alpha1.DNSRecord{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      gatewayRecordName(gateway.Name, hostname),
+				Namespace: gateway.Namespace,
+				Labels:    map[string]string{GatewayLabel: gateway.Name},
+			},
+			Spec: v1alpha1.DNSRecordSpec{
+				RootHost:    hostname,
+				ProviderRef: v1alpha1.ProviderRef{Name: providerRef},
+				GatewayEndpoints: []v1alpha1.GatewayEndpoint{
+					{DNSName: hostname, GatewayName: gateway.Name},
+				},
+			},
+		}
+		if err := controllerutil.SetControllerReference(gateway, record, r.Scheme); err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// gatewayHostnames returns the sorted, unique listener hostnames of the gateway that are in the given zone.
+func gatewayHostnames(gateway *gatewayapiv1.Gateway, zone string) []string {
+	var hostnames []string
+	for _, listener := range gateway.Spec.Listeners {
+		if listener.Hostname == nil {
+			continue
+		}
+		hostname := strings.ToLower(string(*listener.Hostname))
+		if hostname != zone && !strings.HasSuffix(hostname, "."+zone) {
+			continue
+		}
+		if !slices.Contains(hostnames, hostname) {
+			hostnames = append(hostnames, hostname)
+		}
+	}
+	slices.Sort(hostnames)
+	return hostnames
+}
+
+// gatewayRecordName returns the name of the record of the given hostname of the gateway, e.g. gw-www.example.com, or
+// gw-wildcard.example.com for *.example.com. Names that would be too long end with a hash of the hostname instead.
+func gatewayRecordName(gatewayName, hostname string) string {
+	name := gatewayName + "-" + strings.Replace(hostname, "*", "wildcard", 1)
+	if len(name) > validation.DNS1123SubdomainMaxLength {
+		suffix := "-" + hash.ToBase36HashLen(hostname, 8)
+		name = gatewayName[:min(len(gatewayName), validation.DNS1123SubdomainMaxLength-len(suffix))] + suffix
+	}
+	return name
+}
+
+// SetupWithManager sets up the controller with the Manager. Records are restored if they are changed or deleted, and
+// the Gateways with a zone of a namespace are reconciled when the annotations or labels of the namespace change, e.g.
+// when its default provider is set. Returns an error if the Gateway API is not installed.
+func (r *GatewayReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if _, err := mgr.GetRESTMapper().RESTMapping(schema.GroupKind{Group: gatewayapiv1.GroupName, Kind: "Gateway"}, gatewayapiv1.GroupVersion.Version); err != nil {
+		return fmt.Errorf("the Gateway API is not installed: %w", err)
+	}
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&gatewayapiv1.Gateway{}, builder.WithPredicates(predicate.Or(predicate.GenerationChangedPredicate{}, predicate.AnnotationChangedPredicate{}))).
+		Owns(&v1alpha1.DNSRecord{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Watches(&v1.Namespace{}, enqueueNamespaceObjects(mgr.GetClient(), &gatewayapiv1.GatewayList{}, namespaceGateways),
+			builder.WithPredicates(predicate.Or(predicate.AnnotationChangedPredicate{}, predicate.LabelChangedPredicate{}))).
+		Complete(r)
+}
+
+// namespaceGateways returns the requests of the Gateways of the given list with a zone.
+func namespaceGateways(l client.ObjectList) []reconcile.Request {
+	var requests []reconcile.Request
+	for _, gateway := range l.(*gatewayapiv1.GatewayList).Items {
+		if gateway.GetAnnotations()[v1alpha1.GatewayDNSZoneAnnotation] != "" {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&gateway)})
+		}
+	}
+	return requests
+}
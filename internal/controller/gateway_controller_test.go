@@ -0,0 +1,150 @@
+//go:build integration
+
+package controller
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	gatewayapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	"github.com/kuadrant/dns-operator/api/v1alpha1"
+)
+
+var _ = Describe("Gateway DNSRecords", func() {
+	var gateway *gatewayapiv1.Gateway
+
+	listener := func(hostname string) gatewayapiv1.Listener {
+		h := gatewayapiv1.Hostname(hostname)
+		return gatewayapiv1.Listener{Name: gatewayapiv1.SectionName(strings.ReplaceAll(hostname, "*", "wildcard")), Hostname: &h}
+	}
+
+	BeforeEach(func() {
+		gateway = &gatewayapiv1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "gw",
+				Namespace: "default",
+				UID:       types.UID("gw"),
+				Annotations: map[string]string{
+					v1alpha1.GatewayDNSZoneAnnotation: "example.com",
+				},
+			},
+			Spec: gatewayapiv1.GatewaySpec{
+				Listeners: []gatewayapiv1.Listener{
+					listener("www.example.com"),
+					listener("*.Example.com"),
+					listener("api.other.com"),
+					listener("www.example.com"),
+					{Name: "any"},
+				},
+			},
+		}
+	})
+
+	It("should only return the unique listener hostnames in the zone", func() {
+		Expect(gatewayHostnames(gateway, "example.com")).To(Equal([]string{"*.example.com", "www.example.com"}))
+		Expect(gatewayHostnames(gateway, "ample.com")).To(BeEmpty())
+	})
+
+	It("should name records after the gateway and the hostname", func() {
+		Expect(gatewayRecordName("gw", "www.example.com")).To(Equal("gw-www.example.com"))
+		Expect(gatewayRecordName("gw", "*.example.com")).To(Equal("gw-wildcard.example.com"))
+
+		long := strings.Repeat("a", 63) + "." + strings.Repeat("b", 63) + "." + strings.Repeat("c", 63) + ".example.com"
+		name := gatewayRecordName(strings.Repeat("g", 60), long)
+		Expect(len(name)).To(BeNumerically("<=", 253))
+		Expect(name).To(HavePrefix(strings.Repeat("g", 60) + "-"))
+		Expect(name).To(Equal(gatewayRecordName(strings.Repeat("g", 60), long)))
+		Expect(name).NotTo(Equal(gatewayRecordName(strings.Repeat("g", 60), "x"+long)))
+	})
+
+	It("should create, update and delete the records of the gateway", func() {
+		namespace := &v1.Namespace{ObjectMeta: metav1.ObjectMeta{
+			Name:        "default",
+			Annotations: map[string]string{v1alpha1.DefaultProviderRefAnnotation: "default-provider"},
+		}}
+		c := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(namespace, gateway).Build()
+		r := &GatewayReconciler{Client: c, Scheme: scheme.Scheme}
+		req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(gateway)}
+
+		_, err := r.Reconcile(ctx, req)
+		Expect(err).NotTo(HaveOccurred())
+		records := &v1alpha1.DNSRecordList{}
+		Expect(c.List(ctx, records, client.MatchingLabels{GatewayLabel: "gw"})).To(Succeed())
+		Expect(records.Items).To(HaveLen(2))
+		for _, record := range records.Items {
+			Expect(record.Spec.ProviderRef.Name).To(Equal("default-provider"))
+			Expect(record.Spec.GatewayEndpoints).To(Equal([]v1alpha1.GatewayEndpoint{{DNSName: record.Spec.RootHost, GatewayName: "gw"}}))
+			Expect(metav1.IsControlledBy(&record, gateway)).To(BeTrue())
+		}
+
+		By("removing a listener and setting the provider of the gateway")
+		Expect(c.Get(ctx, req.NamespacedName, gateway)).To(Succeed())
+		gateway.Annotations[v1alpha1.GatewayDNSProviderRefAnnotation] = "gateway-provider"
+		gateway.Spec.Listeners = gateway.Spec.Listeners[:1]
+		Expect(c.Update(ctx, gateway)).To(Succeed())
+
+		_, err = r.Reconcile(ctx, req)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(c.List(ctx, records, client.MatchingLabels{GatewayLabel: "gw"})).To(Succeed())
+		Expect(records.Items).To(HaveLen(1))
+		Expect(records.Items[0].Name).To(Equal("gw-www.example.com"))
+		Expect(records.Items[0].Spec.ProviderRef.Name).To(Equal("gateway-provider"))
+	})
+
+	It("should leave records that are not controlled by the gateway", func() {
+		existing := &v1alpha1.DNSRecord{
+			ObjectMeta: metav1.ObjectMeta{Name: "gw-www.example.com", Namespace: "default", Labels: map[string]string{GatewayLabel: "gw"}},
+			Spec:       v1alpha1.DNSRecordSpec{RootHost: "www.example.com", ProviderRef: v1alpha1.ProviderRef{Name: "mine"}},
+		}
+		gateway.Annotations[v1alpha1.GatewayDNSProviderRefAnnotation] = "gateway-provider"
+		gateway.Spec.Listeners = nil
+		c := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(gateway, existing).Build()
+		r := &GatewayReconciler{Client: c, Scheme: scheme.Scheme}
+
+		_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(gateway)})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(c.Get(ctx, client.ObjectKeyFromObject(existing), existing)).To(Succeed())
+		Expect(existing.Spec.ProviderRef.Name).To(Equal("mine"))
+	})
+
+	It("should not create records without a provider", func() {
+		c := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}}, gateway).Build()
+		recorder := record.NewFakeRecorder(10)
+		r := &GatewayReconciler{Client: c, Scheme: scheme.Scheme, Recorder: recorder}
+		_, err := r.desiredRecords(ctx, gateway)
+		Expect(err).To(MatchError(errNoGatewayProvider))
+
+		_, err = r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(gateway)})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(recorder.Events).To(Receive(And(HavePrefix("Warning NoProvider"), ContainSubstring(v1alpha1.GatewayDNSProviderRefAnnotation))))
+		records := &v1alpha1.DNSRecordList{}
+		Expect(c.List(ctx, records)).To(Succeed())
+		Expect(records.Items).To(BeEmpty())
+	})
+
+	It("should return the error when the records of the gateway can't be determined", func() {
+		c := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(gateway).Build()
+		r := &GatewayReconciler{Client: c, Scheme: scheme.Scheme, Recorder: record.NewFakeRecorder(10)}
+		_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(gateway)})
+		Expect(err).To(MatchError(ContainSubstring("not found")))
+	})
+
+	It("should reconcile the gateways with a zone of a namespace", func() {
+		other := gateway.DeepCopy()
+		other.Name = "other"
+		other.Annotations = nil
+		requests := namespaceGateways(&gatewayapiv1.GatewayList{Items: []gatewayapiv1.Gateway{*gateway, *other}})
+		Expect(requests).To(ConsistOf(ctrl.Request{NamespacedName: client.ObjectKeyFromObject(gateway)}))
+	})
+})
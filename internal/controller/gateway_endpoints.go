@@ -0,0 +1,81 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	externaldnsendpoint "sigs.k8s.io/external-dns/endpoint"
+	gatewayapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	"github.com/kuadrant/dns-operator/api/v1alpha1"
+)
+
+//+kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=gateways,verbs=get;list;watch
+
+// expandGatewayEndpoints appends the endpoints for the gateway endpoints of the record to its spec endpoints.
+// The spec of the record is never updated on the cluster after this point, so the expanded endpoints are only used for
+// the current reconcile.
+func (r *DNSRecordReconciler) expandGatewayEndpoints(ctx context.Context, dnsRecord *v1alpha1.DNSRecord) error {
+	for _, gatewayEndpoint := range dnsRecord.Spec.GatewayEndpoints {
+		for _, ep := range dnsRecord.Spec.Endpoints {
+			if ep.DNSName == gatewayEndpoint.DNSName {
+				return fmt.Errorf("gateway endpoint %s is also defined in endpoints", gatewayEndpoint.DNSName)
+			}
+		}
+
+		gateway := &gatewayapiv1.Gateway{}
+		if err := r.Get(ctx, client.ObjectKey{Namespace: dnsRecord.Namespace, Name: gatewayEndpoint.GatewayName}, gateway); err != nil {
+			return fmt.Errorf("unable to get gateway %s for endpoint %s: %w", gatewayEndpoint.GatewayName, gatewayEndpoint.DNSName, err)
+		}
+		endpoints, err := gatewayAddressEndpoints(gatewayEndpoint, gateway)
+		if err != nil {
+			return err
+		}
+		dnsRecord.Spec.Endpoints = append(dnsRecord.Spec.Endpoints, endpoints...)
+	}
+	return nil
+}
+
+// gatewayAddressEndpoints returns A, AAAA and CNAME endpoints for the IPv4, IPv6 and hostname addresses of the gateway.
+// Named addresses are ignored, hostname addresses can not be mixed with IP addresses as a CNAME can't coexist with
+// other records for the same name.
+func gatewayAddressEndpoints(gatewayEndpoint v1alpha1.GatewayEndpoint, gateway *gatewayapiv1.Gateway) ([]*externaldnsendpoint.Endpoint, error) {
+	targets := map[string]externaldnsendpoint.Targets{}
+	for _, address := range gateway.Status.Addresses {
+		addressType := gatewayapiv1.IPAddressType
+		if address.Type != nil {
+			addressType = *address.Type
+		}
+		switch addressType {
+		case gatewayapiv1.IPAddressType:
+			ip, err := netip.ParseAddr(address.Value)
+			if err != nil {
+				return nil, fmt.Errorf("gateway %s has invalid IP address %s: %w", gateway.Name, address.Value, err)
+			}
+			if ip.Is4() {
+				targets[externaldnsendpoint.RecordTypeA] = append(targets[externaldnsendpoint.RecordTypeA], ip.String())
+			} else {
+				targets[externaldnsendpoint.RecordTypeAAAA] = append(targets[externaldnsendpoint.RecordTypeAAAA], ip.String())
+			}
+		case gatewayapiv1.HostnameAddressType:
+			targets[externaldnsendpoint.RecordTypeCNAME] = append(targets[externaldnsendpoint.RecordTypeCNAME], address.Value)
+		}
+	}
+
+	if len(targets) == 0 {
+		return nil, fmt.Errorf("gateway %s has no IP or hostname addresses for endpoint %s", gateway.Name, gatewayEndpoint.DNSName)
+	}
+	if _, ok := targets[externaldnsendpoint.RecordTypeCNAME]; ok && len(targets) > 1 {
+		return nil, fmt.Errorf("gateway %s has both hostname and IP addresses for endpoint %s", gateway.Name, gatewayEndpoint.DNSName)
+	}
+
+	var endpoints []*externaldnsendpoint.Endpoint
+	for _, recordType := range []string{externaldnsendpoint.RecordTypeA, externaldnsendpoint.RecordTypeAAAA, externaldnsendpoint.RecordTypeCNAME} {
+		if recordTargets, ok := targets[recordType]; ok {
+			endpoints = append(endpoints, externaldnsendpoint.NewEndpointWithTTL(gatewayEndpoint.DNSName, recordType, gatewayEndpoint.RecordTTL, recordTargets...))
+		}
+	}
+	return endpoints, nil
+}
@@ -0,0 +1,78 @@
+//go:build integration
+
+package controller
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	externaldnsendpoint "sigs.k8s.io/external-dns/endpoint"
+	gatewayapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	"github.com/kuadrant/dns-operator/api/v1alpha1"
+)
+
+var _ = Describe("Gateway endpoints", func() {
+	gatewayEndpoint := v1alpha1.GatewayEndpoint{DNSName: "foo.example.com", GatewayName: "gw", RecordTTL: 60}
+
+	gateway := func(addresses ...gatewayapiv1.GatewayStatusAddress) *gatewayapiv1.Gateway {
+		return &gatewayapiv1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{Name: "gw", Namespace: "default"},
+			Status:     gatewayapiv1.GatewayStatus{Addresses: addresses},
+		}
+	}
+	address := func(addressType gatewayapiv1.AddressType, value string) gatewayapiv1.GatewayStatusAddress {
+		return gatewayapiv1.GatewayStatusAddress{Type: &addressType, Value: value}
+	}
+
+	It("should return A and AAAA endpoints for IP addresses", func() {
+		endpoints, err := gatewayAddressEndpoints(gatewayEndpoint, gateway(
+			address(gatewayapiv1.IPAddressType, "192.0.2.1"),
+			address(gatewayapiv1.IPAddressType, "2001:db8::1"),
+			// addresses without a type are IP addresses
+			gatewayapiv1.GatewayStatusAddress{Value: "192.0.2.2"},
+			address(gatewayapiv1.NamedAddressType, "named"),
+		))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(endpoints).To(Equal([]*externaldnsendpoint.Endpoint{
+			externaldnsendpoint.NewEndpointWithTTL("foo.example.com", externaldnsendpoint.RecordTypeA, 60, "192.0.2.1", "192.0.2.2"),
+			externaldnsendpoint.NewEndpointWithTTL("foo.example.com", externaldnsendpoint.RecordTypeAAAA, 60, "2001:db8::1"),
+		}))
+	})
+
+	It("should return a CNAME endpoint for a hostname address", func() {
+		endpoints, err := gatewayAddressEndpoints(gatewayEndpoint, gateway(address(gatewayapiv1.HostnameAddressType, "lb.example.net")))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(endpoints).To(Equal([]*externaldnsendpoint.Endpoint{
+			externaldnsendpoint.NewEndpointWithTTL("foo.example.com", externaldnsendpoint.RecordTypeCNAME, 60, "lb.example.net"),
+		}))
+	})
+
+	It("should reject gateways without usable addresses", func() {
+		_, err := gatewayAddressEndpoints(gatewayEndpoint, gateway(address(gatewayapiv1.NamedAddressType, "named")))
+		Expect(err).To(MatchError("gateway gw has no IP or hostname addresses for endpoint foo.example.com"))
+
+		_, err = gatewayAddressEndpoints(gatewayEndpoint, gateway(
+			address(gatewayapiv1.HostnameAddressType, "lb.example.net"),
+			address(gatewayapiv1.IPAddressType, "192.0.2.1"),
+		))
+		Expect(err).To(MatchError("gateway gw has both hostname and IP addresses for endpoint foo.example.com"))
+
+		_, err = gatewayAddressEndpoints(gatewayEndpoint, gateway(address(gatewayapiv1.IPAddressType, "not-an-ip")))
+		Expect(err).To(MatchError(ContainSubstring("gateway gw has invalid IP address not-an-ip")))
+	})
+
+	It("should fail to expand the endpoints of a missing gateway", func() {
+		r := &DNSRecordReconciler{Client: fake.NewClientBuilder().WithScheme(scheme.Scheme).Build()}
+		dnsRecord := &v1alpha1.DNSRecord{
+			ObjectMeta: metav1.ObjectMeta{Name: "record", Namespace: "default"},
+			Spec:       v1alpha1.DNSRecordSpec{GatewayEndpoints: []v1alpha1.GatewayEndpoint{gatewayEndpoint}},
+		}
+		err := r.expandGatewayEndpoints(ctx, dnsRecord)
+		Expect(err).To(MatchError(ContainSubstring("unable to get gateway gw for endpoint foo.example.com")))
+		Expect(dnsRecord.Spec.Endpoints).To(BeEmpty())
+	})
+})
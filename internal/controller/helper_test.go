@@ -23,6 +23,8 @@ const (
 	RequeueDuration           = time.Second * 6
 	ValidityDuration          = time.Second * 3
 	DefaultValidationDuration = time.Millisecond * 500
+	// unownedPublishDomain is the domain records are allowed to be published in without ownership
+	unownedPublishDomain = "unowned.example.com"
 )
 
 func GenerateName() string {
@@ -0,0 +1,134 @@
+package controller
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	externaldnsendpoint "sigs.k8s.io/external-dns/endpoint"
+
+	"github.com/kuadrant/dns-operator/api/v1alpha1"
+)
+
+// lingerDefaultTTL is how long targets of endpoints without a TTL linger for, the TTL providers publish them with.
+const lingerDefaultTTL = 300 * time.Second
+
+// lingeringTargetKey identifies a target of an endpoint.
+type lingeringTargetKey struct {
+	endpoint externaldnsendpoint.EndpointKey
+	target   string
+}
+
+func (k lingeringTargetKey) String() string {
+	name := k.endpoint.DNSName
+	if k.endpoint.SetIdentifier != "" {
+		name = fmt.Sprintf("%s/%s", k.endpoint.DNSName, k.endpoint.SetIdentifier)
+	}
+	return fmt.Sprintf("%s %s %s", k.endpoint.RecordType, name, k.target)
+}
+
+// lingerRemovedTargets returns the desired endpoints with the targets removed from the spec of the record since they
+// were last published added back, until one TTL of their endpoint has passed since they were removed. The lingering
+// targets are tracked in the status of the record, and the targets that started lingering are returned.
+// Targets removed because they are unhealthy don't linger, nor do targets of CNAME endpoints that are still in the
+// spec, as a CNAME can't have more than one target.
+func lingerRemovedTargets(dnsRecord *v1alpha1.DNSRecord, spec, desired, published []*externaldnsendpoint.Endpoint, now time.Time) ([]*externaldnsendpoint.Endpoint, []string) {
+	if dnsRecord.Spec.LingerPolicy != v1alpha1.LingerPolicyTTL {
+		dnsRecord.Status.LingeringTargets = nil
+		return desired, nil
+	}
+
+	removeAt := make(map[lingeringTargetKey]metav1.Time, len(dnsRecord.Status.LingeringTargets))
+	for _, lingering := range dnsRecord.Status.LingeringTargets {
+		key := lingeringTargetKey{
+			endpoint: externaldnsendpoint.EndpointKey{DNSName: lingering.DNSName, RecordType: lingering.RecordType, SetIdentifier: lingering.SetIdentifier},
+			target:   lingering.Target,
+		}
+		removeAt[key] = lingering.RemoveAt
+	}
+	specEndpoints := make(map[externaldnsendpoint.EndpointKey]*externaldnsendpoint.Endpoint, len(spec))
+	for _, ep := range spec {
+		specEndpoints[ep.Key()] = ep
+	}
+	desiredKeys := endpointKeys(desired)
+
+	var lingering []v1alpha1.LingeringTarget
+	var started []string
+	lingeringEndpoints := map[externaldnsendpoint.EndpointKey]*externaldnsendpoint.Endpoint{}
+	for _, ep := range published {
+		specEndpoint, inSpec := specEndpoints[ep.Key()]
+		if inSpec {
+			// the endpoint is not published as it is unhealthy, or its target can only be replaced
+			if _, ok := desiredKeys[ep.Key()]; !ok || ep.RecordType == externaldnsendpoint.RecordTypeCNAME {
+				continue
+			}
+		}
+		for _, target := range ep.Targets {
+			if inSpec && slices.Contains(specEndpoint.Targets, target) {
+				continue
+			}
+			key := lingeringTargetKey{endpoint: ep.Key(), target: target}
+			at, ok := removeAt[key]
+			if !ok {
+				ttl := lingerDefaultTTL
+				if ep.RecordTTL.IsConfigured() {
+					ttl = time.Duration(ep.RecordTTL) * time.Second
+				}
+				at = metav1.NewTime(now.Add(ttl))
+				started = append(started, key.String())
+			}
+			if !now.Before(at.Time) {
+				continue
+			}
+			lingering = append(lingering, v1alpha1.LingeringTarget{
+				DNSName:       ep.DNSName,
+				RecordType:    ep.RecordType,
+				SetIdentifier: ep.SetIdentifier,
+				Target:        target,
+				RemoveAt:      at,
+			})
+			lingeringEndpoint, ok := lingeringEndpoints[ep.Key()]
+			if !ok {
+				lingeringEndpoint = ep.DeepCopy()
+				lingeringEndpoint.Targets = nil
+				lingeringEndpoints[ep.Key()] = lingeringEndpoint
+			}
+			lingeringEndpoint.Targets = append(lingeringEndpoint.Targets, target)
+		}
+	}
+	dnsRecord.Status.LingeringTargets = lingering
+	if len(lingeringEndpoints) == 0 {
+		return desired, started
+	}
+
+	endpoints := make([]*externaldnsendpoint.Endpoint, 0, len(desired)+len(lingeringEndpoints))
+	for _, ep := range desired {
+		if lingeringEndpoint, ok := lingeringEndpoints[ep.Key()]; ok {
+			ep = ep.DeepCopy()
+			ep.Targets = append(ep.Targets, lingeringEndpoint.Targets...)
+			delete(lingeringEndpoints, ep.Key())
+		}
+		endpoints = append(endpoints, ep)
+	}
+	// endpoints removed from the spec altogether are published with their lingering targets only
+	for _, ep := range published {
+		if lingeringEndpoint, ok := lingeringEndpoints[ep.Key()]; ok {
+			endpoints = append(endpoints, lingeringEndpoint)
+		}
+	}
+	return endpoints, started
+}
+
+// lingerRequeueTime returns how long until the first of the given lingering targets is removed, or 0 if there are no
+// lingering targets.
+func lingerRequeueTime(lingering []v1alpha1.LingeringTarget, now time.Time) time.Duration {
+	var requeue time.Duration
+	for _, target := range lingering {
+		remaining := max(target.RemoveAt.Sub(now), time.Second)
+		if requeue == 0 || remaining < requeue {
+			requeue = remaining
+		}
+	}
+	return requeue
+}
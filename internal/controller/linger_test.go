@@ -0,0 +1,102 @@
+//go:build integration
+
+package controller
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	externaldnsendpoint "sigs.k8s.io/external-dns/endpoint"
+
+	"github.com/kuadrant/dns-operator/api/v1alpha1"
+)
+
+var _ = Describe("Target linger", func() {
+	var (
+		record    *v1alpha1.DNSRecord
+		published []*externaldnsendpoint.Endpoint
+		now       time.Time
+	)
+
+	BeforeEach(func() {
+		record = &v1alpha1.DNSRecord{Spec: v1alpha1.DNSRecordSpec{LingerPolicy: v1alpha1.LingerPolicyTTL}}
+		published = []*externaldnsendpoint.Endpoint{
+			externaldnsendpoint.NewEndpointWithTTL("foo.example.com", externaldnsendpoint.RecordTypeA, 60, "1.1.1.1", "2.2.2.2"),
+			externaldnsendpoint.NewEndpoint("bar.example.com", externaldnsendpoint.RecordTypeA, "3.3.3.3"),
+		}
+		now = time.Now()
+	})
+
+	It("should keep removed targets for one TTL", func() {
+		spec := []*externaldnsendpoint.Endpoint{
+			externaldnsendpoint.NewEndpointWithTTL("foo.example.com", externaldnsendpoint.RecordTypeA, 60, "1.1.1.1"),
+		}
+		desired, started := lingerRemovedTargets(record, spec, spec, published, now)
+		Expect(started).To(ConsistOf("A foo.example.com 2.2.2.2", "A bar.example.com 3.3.3.3"))
+		Expect(desired).To(HaveLen(2))
+		Expect(desired[0].Targets).To(ConsistOf("1.1.1.1", "2.2.2.2"))
+		Expect(desired[1].DNSName).To(Equal("bar.example.com"))
+		Expect(desired[1].Targets).To(ConsistOf("3.3.3.3"))
+		Expect(spec[0].Targets).To(ConsistOf("1.1.1.1"))
+
+		Expect(record.Status.LingeringTargets).To(HaveLen(2))
+		Expect(record.Status.LingeringTargets[0].Target).To(Equal("2.2.2.2"))
+		Expect(record.Status.LingeringTargets[0].RemoveAt.Time).To(BeTemporally("==", now.Add(time.Minute)))
+		Expect(record.Status.LingeringTargets[1].RemoveAt.Time).To(BeTemporally("==", now.Add(lingerDefaultTTL)))
+		Expect(lingerRequeueTime(record.Status.LingeringTargets, now)).To(Equal(time.Minute))
+
+		// targets are removed once their TTL has passed
+		desired, started = lingerRemovedTargets(record, spec, spec, desired, now.Add(2*time.Minute))
+		Expect(started).To(BeEmpty())
+		Expect(desired).To(HaveLen(2))
+		Expect(desired[0].Targets).To(ConsistOf("1.1.1.1"))
+		Expect(record.Status.LingeringTargets).To(HaveLen(1))
+
+		desired, _ = lingerRemovedTargets(record, spec, spec, desired, now.Add(10*time.Minute))
+		Expect(desired).To(Equal(spec))
+		Expect(record.Status.LingeringTargets).To(BeEmpty())
+		Expect(lingerRequeueTime(record.Status.LingeringTargets, now)).To(BeZero())
+	})
+
+	It("should stop lingering targets added back to the spec", func() {
+		record.Status.LingeringTargets = []v1alpha1.LingeringTarget{
+			{DNSName: "foo.example.com", RecordType: externaldnsendpoint.RecordTypeA, Target: "2.2.2.2", RemoveAt: metav1.NewTime(now.Add(time.Minute))},
+		}
+		spec := []*externaldnsendpoint.Endpoint{published[0], published[1]}
+		desired, started := lingerRemovedTargets(record, spec, spec, published, now)
+		Expect(started).To(BeEmpty())
+		Expect(desired).To(Equal(spec))
+		Expect(record.Status.LingeringTargets).To(BeEmpty())
+	})
+
+	It("should not linger unhealthy targets or replaced CNAME targets", func() {
+		published = append(published, externaldnsendpoint.NewEndpoint("baz.example.com", externaldnsendpoint.RecordTypeCNAME, "old.example.com"))
+		spec := []*externaldnsendpoint.Endpoint{
+			published[0],
+			published[1],
+			externaldnsendpoint.NewEndpoint("baz.example.com", externaldnsendpoint.RecordTypeCNAME, "new.example.com"),
+		}
+		healthy := []*externaldnsendpoint.Endpoint{
+			externaldnsendpoint.NewEndpointWithTTL("foo.example.com", externaldnsendpoint.RecordTypeA, 60, "1.1.1.1"),
+			spec[2],
+		}
+		desired, started := lingerRemovedTargets(record, spec, healthy, published, now)
+		Expect(started).To(BeEmpty())
+		Expect(desired).To(Equal(healthy))
+		Expect(record.Status.LingeringTargets).To(BeEmpty())
+	})
+
+	It("should not linger targets without the TTL policy", func() {
+		record.Spec.LingerPolicy = v1alpha1.LingerPolicyNone
+		record.Status.LingeringTargets = []v1alpha1.LingeringTarget{
+			{DNSName: "foo.example.com", RecordType: externaldnsendpoint.RecordTypeA, Target: "2.2.2.2", RemoveAt: metav1.NewTime(now.Add(time.Minute))},
+		}
+		desired, started := lingerRemovedTargets(record, nil, nil, published, now)
+		Expect(started).To(BeEmpty())
+		Expect(desired).To(BeEmpty())
+		Expect(record.Status.LingeringTargets).To(BeNil())
+	})
+})
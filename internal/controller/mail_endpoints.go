@@ -0,0 +1,66 @@
+package controller
+
+import (
+	"fmt"
+	"strings"
+
+	externaldnsendpoint "sigs.k8s.io/external-dns/endpoint"
+
+	"github.com/kuadrant/dns-operator/api/v1alpha1"
+	externaldnsregistry "github.com/kuadrant/dns-operator/internal/external-dns/registry"
+)
+
+// expandMailEndpoints appends the TXT endpoints for the SPF, DKIM and DMARC records of the record to its spec endpoints.
+// As with gateway endpoints the spec of the record is never updated on the cluster after this point.
+func expandMailEndpoints(dnsRecord *v1alpha1.DNSRecord) error {
+	mail := dnsRecord.Spec.Mail
+	if mail == nil {
+		return nil
+	}
+	domain := mail.Domain
+	if domain == "" {
+		domain, _ = strings.CutPrefix(dnsRecord.Spec.RootHost, v1alpha1.WildcardPrefix)
+	}
+
+	type mailRecord struct {
+		dnsName string
+		value   string
+	}
+	var records []mailRecord
+	if mail.SPF != nil {
+		records = append(records, mailRecord{domain, mail.SPF.Record()})
+	}
+	for _, dkim := range mail.DKIM {
+		records = append(records, mailRecord{dkim.Selector + "._domainkey." + domain, dkim.Record()})
+	}
+	if mail.DMARC != nil {
+		records = append(records, mailRecord{"_dmarc." + domain, mail.DMARC.Record()})
+	}
+
+	for _, record := range records {
+		for _, ep := range dnsRecord.Spec.Endpoints {
+			if ep.DNSName == record.dnsName && ep.RecordType == externaldnsendpoint.RecordTypeTXT {
+				return fmt.Errorf("mail record %s is also defined in endpoints", record.dnsName)
+			}
+		}
+		dnsRecord.Spec.Endpoints = append(dnsRecord.Spec.Endpoints,
+			externaldnsendpoint.NewEndpointWithTTL(record.dnsName, externaldnsendpoint.RecordTypeTXT, mail.RecordTTL, splitTXT(record.value)))
+	}
+	return nil
+}
+
+// splitTXT returns the value as quoted character strings of at most v1alpha1.MaxTXTStringLength, separated by spaces
+func splitTXT(value string) string {
+	var quoted []string
+	for len(value) > v1alpha1.MaxTXTStringLength {
+		quoted = append(quoted, `"`+value[:v1alpha1.MaxTXTStringLength]+`"`)
+		value = value[v1alpha1.MaxTXTStringLength:]
+	}
+	return strings.Join(append(quoted, `"`+value+`"`), " ")
+}
+
+// managedRecordTypesFor returns the record types managed for the record, from the endpoints of its spec and those it
+// published last
+func managedRecordTypesFor(dnsRecord *v1alpha1.DNSRecord) []string {
+	return externaldnsregistry.ManagedRecordTypes(dnsRecord.Spec.Endpoints, dnsRecord.Status.Endpoints)
+}
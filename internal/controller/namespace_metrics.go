@@ -0,0 +1,75 @@
+package controller
+
+import (
+	"context"
+	"sort"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kuadrant/dns-operator/api/v1alpha1"
+	"github.com/kuadrant/dns-operator/internal/metrics"
+)
+
+// namespaceUsage returns a func listing the DNS usage of each namespace with DNS records or quotas, for the namespace
+// metrics.
+func namespaceUsage(reader client.Reader) metrics.NamespaceUsageFunc {
+	return func(ctx context.Context) ([]metrics.NamespaceUsage, error) {
+		records := &v1alpha1.DNSRecordList{}
+		if err := reader.List(ctx, records); err != nil {
+			return nil, err
+		}
+		quotas := &v1alpha1.DNSQuotaList{}
+		if err := reader.List(ctx, quotas); err != nil {
+			return nil, err
+		}
+		return namespaceUsages(records.Items, quotas.Items), nil
+	}
+}
+
+// namespaceUsages returns the DNS usage of each namespace with any of the given records or quotas, sorted by
+// namespace.
+func namespaceUsages(records []v1alpha1.DNSRecord, quotas []v1alpha1.DNSQuota) []metrics.NamespaceUsage {
+	usages := map[string]*metrics.NamespaceUsage{}
+	zones := map[string]map[string]struct{}{}
+	usageFor := func(namespace string) *metrics.NamespaceUsage {
+		if _, ok := usages[namespace]; !ok {
+			usages[namespace] = &metrics.NamespaceUsage{Namespace: namespace}
+			zones[namespace] = map[string]struct{}{}
+		}
+		return usages[namespace]
+	}
+
+	for i := range records {
+		record := &records[i]
+		usage := usageFor(record.Namespace)
+		usage.Records++
+		usage.Endpoints += len(record.Spec.Endpoints)
+		if record.Status.ZoneID != "" {
+			zones[record.Namespace][record.Status.ZoneID] = struct{}{}
+		}
+	}
+
+	quotasByNamespace := map[string]*v1alpha1.DNSQuotaList{}
+	for i := range quotas {
+		list, ok := quotasByNamespace[quotas[i].Namespace]
+		if !ok {
+			list = &v1alpha1.DNSQuotaList{}
+			quotasByNamespace[quotas[i].Namespace] = list
+		}
+		list.Items = append(list.Items, quotas[i])
+	}
+	for namespace, list := range quotasByNamespace {
+		usage := usageFor(namespace)
+		usage.MaxRecords, usage.MaxEndpoints = list.Limits()
+	}
+
+	result := make([]metrics.NamespaceUsage, 0, len(usages))
+	for namespace, usage := range usages {
+		usage.Zones = len(zones[namespace])
+		result = append(result, *usage)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Namespace < result[j].Namespace
+	})
+	return result
+}
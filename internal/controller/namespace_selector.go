@@ -0,0 +1,45 @@
+package controller
+
+import (
+	"context"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// +kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch
+
+// namespaceWatched returns true if the labels of the namespace match the selector, all namespaces are watched without
+// a selector.
+func namespaceWatched(ctx context.Context, c client.Client, selector labels.Selector, namespace string) (bool, error) {
+	if selector == nil {
+		return true, nil
+	}
+	ns := &v1.Namespace{}
+	if err := c.Get(ctx, client.ObjectKey{Name: namespace}, ns); err != nil {
+		return false, client.IgnoreNotFound(err)
+	}
+	return selector.Matches(labels.Set(ns.Labels)), nil
+}
+
+// enqueueNamespaceObjects returns the handler of a watch on namespaces that enqueues the objects in the namespace, so
+// namespaces that start or stop matching the selector are picked up without a restart.
+func enqueueNamespaceObjects(c client.Client, list client.ObjectList, requests func(client.ObjectList) []reconcile.Request) handler.EventHandler {
+	return handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, o client.Object) []reconcile.Request {
+		objects := list.DeepCopyObject().(client.ObjectList)
+		if err := c.List(ctx, objects, client.InNamespace(o.GetName())); err != nil {
+			log.FromContext(ctx).Error(err, "failed to list objects", "namespace", o.GetName())
+			return nil
+		}
+		return requests(objects)
+	})
+}
+
+// namespaceLabelsChanged passes the events of namespaces that can change whether they match the selector
+var namespaceLabelsChanged = builder.WithPredicates(predicate.LabelChangedPredicate{})
@@ -0,0 +1,39 @@
+//go:build integration
+
+package controller
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("WatchNamespaceSelector", func() {
+	var selector labels.Selector
+
+	BeforeEach(func() {
+		var err error
+		selector, err = labels.Parse("kuadrant.io/dns=enabled")
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("should watch namespaces matching the selector", func() {
+		c := fake.NewClientBuilder().WithObjects(
+			&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "enabled", Labels: map[string]string{"kuadrant.io/dns": "enabled"}}},
+			&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "disabled"}},
+		).Build()
+
+		Expect(namespaceWatched(ctx, c, selector, "enabled")).To(BeTrue())
+		Expect(namespaceWatched(ctx, c, selector, "disabled")).To(BeFalse())
+		Expect(namespaceWatched(ctx, c, selector, "missing")).To(BeFalse())
+	})
+
+	It("should watch all namespaces without a selector", func() {
+		c := fake.NewClientBuilder().Build()
+		Expect(namespaceWatched(ctx, c, nil, "missing")).To(BeTrue())
+	})
+})
@@ -0,0 +1,68 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kuadrant/dns-operator/api/v1alpha1"
+	"github.com/kuadrant/dns-operator/internal/metrics"
+)
+
+// overdueDNSRecords returns a func listing the DNS records whose status shows they are due to be reconciled, for the
+// overdue metrics.
+func overdueDNSRecords(reader client.Reader) metrics.OverdueRecordsFunc {
+	return func(ctx context.Context) ([]metrics.OverdueRecord, error) {
+		records := &v1alpha1.DNSRecordList{}
+		if err := reader.List(ctx, records); err != nil {
+			return nil, err
+		}
+		now := time.Now()
+		var overdue []metrics.OverdueRecord
+		for i := range records.Items {
+			record := &records.Items[i]
+			since, ok := overdueSince(record, now)
+			if !ok {
+				continue
+			}
+			overdue = append(overdue, metrics.OverdueRecord{
+				ProviderSecretNamespace: record.Namespace,
+				ProviderSecretName:      record.Spec.ProviderRef.Name,
+				Since:                   since,
+			})
+		}
+		return overdue, nil
+	}
+}
+
+// overdueSince returns the time the record became due to be reconciled, or false if it is not due.
+// A record is due when it has never been reconciled, its spec has changed since it was last reconciled or the time
+// it was valid for has passed. Records being deleted are reported by the deletion metrics instead.
+func overdueSince(record *v1alpha1.DNSRecord, now time.Time) (time.Time, bool) {
+	if record.DeletionTimestamp != nil {
+		return time.Time{}, false
+	}
+	if record.Status.QueuedAt.IsZero() {
+		return record.CreationTimestamp.Time, true
+	}
+	if record.Generation != record.Status.ObservedGeneration {
+		// the spec was changed by the latest update of the record that was not to its status
+		since := record.Status.QueuedAt.Time
+		for _, f := range record.ManagedFields {
+			if f.Subresource == "" && f.Time != nil && f.Time.After(since) {
+				since = f.Time.Time
+			}
+		}
+		return since, true
+	}
+	validFor, err := time.ParseDuration(record.Status.ValidFor)
+	if err != nil {
+		return time.Time{}, false
+	}
+	due := record.Status.QueuedAt.Add(validFor)
+	if now.Before(due) {
+		return time.Time{}, false
+	}
+	return due, true
+}
@@ -0,0 +1,97 @@
+package controller
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/kuadrant/dns-operator/api/v1alpha1"
+	"github.com/kuadrant/dns-operator/internal/common/hash"
+)
+
+// OwnerIDAlgorithm is the hash algorithm the owner IDs of records are derived from their UID with.
+type OwnerIDAlgorithm string
+
+const (
+	// OwnerIDAlgorithmSHA224 is the algorithm of GetUIDHash, the default.
+	OwnerIDAlgorithmSHA224 OwnerIDAlgorithm = "sha224"
+	// OwnerIDAlgorithmSHA256 derives owner IDs from a SHA-256 hash of the UID.
+	OwnerIDAlgorithmSHA256 OwnerIDAlgorithm = "sha256"
+
+	// defaultOwnerIDLength is the length of the hash of the owner IDs of GetUIDHash.
+	defaultOwnerIDLength = 8
+	// maxOwnerIDLength is the maximum length of an owner ID, the maximum length of the owner ID of a record spec.
+	maxOwnerIDLength = 36
+)
+
+var ownerIDPrefixRegexp = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]*[a-z0-9])?$`)
+
+// OwnerIDFormat is how the owner IDs of records without an owner ID in their spec are derived from their UID. The zero
+// value derives the same owner IDs as GetUIDHash.
+type OwnerIDFormat struct {
+	// Algorithm is the hash algorithm, OwnerIDAlgorithmSHA224 if empty.
+	Algorithm OwnerIDAlgorithm
+	// Length is the number of characters of the hash, 8 if 0. Longer hashes make collisions of the owner IDs of
+	// records across a fleet of clusters less likely.
+	Length int
+	// Prefix is an optional ID of the cluster, prepended to the hash with a "-" so records of different clusters never
+	// share an owner ID.
+	Prefix string
+}
+
+// Validate returns an error if the format can't derive valid owner IDs.
+func (f OwnerIDFormat) Validate() error {
+	switch f.Algorithm {
+	case "", OwnerIDAlgorithmSHA224, OwnerIDAlgorithmSHA256:
+	default:
+		return fmt.Errorf("unknown owner ID algorithm %q, must be one of %q or %q", f.Algorithm, OwnerIDAlgorithmSHA224, OwnerIDAlgorithmSHA256)
+	}
+	if f.Prefix != "" && !ownerIDPrefixRegexp.MatchString(f.Prefix) {
+		return fmt.Errorf("owner ID prefix %q must consist of lower case alphanumeric characters or '-'", f.Prefix)
+	}
+	if f.Length < 0 || f.Length > 0 && f.Length < defaultOwnerIDLength {
+		return fmt.Errorf("owner ID length must be at least %d, got %d", defaultOwnerIDLength, f.Length)
+	}
+	if length := len(f.ownerID("")); length > maxOwnerIDLength {
+		return fmt.Errorf("owner IDs must not be longer than %d characters, the length and prefix make them %d", maxOwnerIDLength, length)
+	}
+	return nil
+}
+
+// OwnerIDFor returns the owner ID derived from the UID of the record.
+func (f OwnerIDFormat) OwnerIDFor(dnsRecord *v1alpha1.DNSRecord) string {
+	return f.ownerID(string(dnsRecord.GetUID()))
+}
+
+func (f OwnerIDFormat) ownerID(uid string) string {
+	length := f.Length
+	if length == 0 {
+		length = defaultOwnerIDLength
+	}
+	ownerID := hash.ToBase36Hash(uid)
+	if f.Algorithm == OwnerIDAlgorithmSHA256 {
+		ownerID = hash.ToBase36SHA256Hash(uid)
+	}
+	// the hash is only shorter than the length if it happens to start with zero bytes
+	ownerID = ownerID[:min(length, len(ownerID))]
+	if f.Prefix != "" {
+		ownerID = f.Prefix + "-" + ownerID
+	}
+	return ownerID
+}
+
+// migrateOwnerID changes the owner ID of a record with an owner ID derived from its UID to the owner ID derived with
+// the format, keeping the owner ID it had as the previous owner ID until its registry TXT records are rewritten.
+// Returns true if the owner ID of the record was changed. Records with an owner ID in their spec, or that are still
+// migrating from a previous owner ID, are not changed.
+func migrateOwnerID(dnsRecord *v1alpha1.DNSRecord, format OwnerIDFormat) bool {
+	if dnsRecord.Spec.OwnerID != "" || !dnsRecord.HasOwnerIDAssigned() || dnsRecord.Status.PreviousOwnerID != "" {
+		return false
+	}
+	ownerID := format.OwnerIDFor(dnsRecord)
+	if dnsRecord.Status.OwnerID == ownerID {
+		return false
+	}
+	dnsRecord.Status.PreviousOwnerID = dnsRecord.Status.OwnerID
+	dnsRecord.Status.OwnerID = ownerID
+	return true
+}
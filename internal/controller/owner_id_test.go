@@ -0,0 +1,65 @@
+//go:build integration
+
+package controller
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kuadrant/dns-operator/api/v1alpha1"
+)
+
+var _ = Describe("Owner IDs", func() {
+	var dnsRecord *v1alpha1.DNSRecord
+
+	BeforeEach(func() {
+		dnsRecord = &v1alpha1.DNSRecord{
+			ObjectMeta: metav1.ObjectMeta{Name: "owner", Namespace: "default", UID: "9c8f876c-4ddc-44a3-9842-460f97e6c037"},
+			Spec:       v1alpha1.DNSRecordSpec{RootHost: "foo.example.com"},
+		}
+	})
+
+	It("should derive the owner IDs of GetUIDHash by default", func() {
+		Expect(OwnerIDFormat{}.OwnerIDFor(dnsRecord)).To(Equal(dnsRecord.GetUIDHash()))
+		Expect(OwnerIDFormat{}.Validate()).To(Succeed())
+	})
+
+	It("should derive owner IDs with the algorithm, length and prefix", func() {
+		format := OwnerIDFormat{Algorithm: OwnerIDAlgorithmSHA256, Length: 16, Prefix: "eu-west-1"}
+		Expect(format.Validate()).To(Succeed())
+		Expect(format.OwnerIDFor(dnsRecord)).To(Equal("eu-west-1-1zfz0ovlm93zt7ib"))
+		Expect(OwnerIDFormat{Length: 16}.OwnerIDFor(dnsRecord)).To(Equal("32ah7xkbrefse005"))
+	})
+
+	It("should reject formats that can't derive valid owner IDs", func() {
+		Expect(OwnerIDFormat{Algorithm: "md5"}.Validate()).To(MatchError(ContainSubstring("unknown owner ID algorithm")))
+		Expect(OwnerIDFormat{Length: 4}.Validate()).To(MatchError(ContainSubstring("at least 8")))
+		Expect(OwnerIDFormat{Prefix: "EU_West"}.Validate()).To(MatchError(ContainSubstring("lower case alphanumeric")))
+		Expect(OwnerIDFormat{Length: 32, Prefix: "eu-west-1"}.Validate()).To(MatchError(ContainSubstring("longer than 36")))
+	})
+
+	It("should migrate owner IDs derived with another format", func() {
+		format := OwnerIDFormat{Length: 16}
+		dnsRecord.Status.OwnerID = dnsRecord.GetUIDHash()
+
+		Expect(migrateOwnerID(dnsRecord, format)).To(BeTrue())
+		Expect(dnsRecord.Status.OwnerID).To(Equal("32ah7xkbrefse005"))
+		Expect(dnsRecord.Status.PreviousOwnerID).To(Equal("32ah7xkb"))
+
+		// the owner ID isn't changed again until the migration from the previous owner ID completes
+		Expect(migrateOwnerID(dnsRecord, OwnerIDFormat{Length: 24})).To(BeFalse())
+		Expect(dnsRecord.Status.OwnerID).To(Equal("32ah7xkbrefse005"))
+
+		dnsRecord.Status.PreviousOwnerID = ""
+		Expect(migrateOwnerID(dnsRecord, format)).To(BeFalse())
+	})
+
+	It("should not migrate owner IDs set in the spec", func() {
+		dnsRecord.Spec.OwnerID = "my-owner"
+		dnsRecord.Status.OwnerID = "my-owner"
+		Expect(migrateOwnerID(dnsRecord, OwnerIDFormat{Length: 16})).To(BeFalse())
+		Expect(dnsRecord.Status.PreviousOwnerID).To(BeEmpty())
+	})
+})
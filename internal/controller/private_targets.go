@@ -0,0 +1,123 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	externaldnsendpoint "sigs.k8s.io/external-dns/endpoint"
+
+	"github.com/kuadrant/dns-operator/api/v1alpha1"
+	"github.com/kuadrant/dns-operator/internal/provider"
+)
+
+// PrivateTargetPolicy is the action taken when a DNSRecord publishing to a public zone has endpoints with private,
+// link-local or loopback address targets.
+type PrivateTargetPolicy string
+
+const (
+	// PrivateTargetPolicyNone private targets are published without any checks
+	PrivateTargetPolicyNone PrivateTargetPolicy = ""
+	// PrivateTargetPolicyWarn private targets are published and the PrivateTargets condition is set on the record
+	PrivateTargetPolicyWarn PrivateTargetPolicy = "warn"
+	// PrivateTargetPolicyReject records with private targets are not published
+	PrivateTargetPolicyReject PrivateTargetPolicy = "reject"
+)
+
+// ParsePrivateTargetPolicy returns the PrivateTargetPolicy for the given value, or an error if it is unknown.
+func ParsePrivateTargetPolicy(value string) (PrivateTargetPolicy, error) {
+	switch policy := PrivateTargetPolicy(value); policy {
+	case PrivateTargetPolicyNone, PrivateTargetPolicyWarn, PrivateTargetPolicyReject:
+		return policy, nil
+	}
+	return PrivateTargetPolicyNone, fmt.Errorf("unknown private target policy %q, must be one of %q or %q",
+		value, PrivateTargetPolicyWarn, PrivateTargetPolicyReject)
+}
+
+// zoneVisibility returns the visibility of the given zone.
+func zoneVisibility(z *provider.DNSZone) v1alpha1.ZoneVisibility {
+	if z.Private {
+		return v1alpha1.ZoneVisibilityPrivate
+	}
+	return v1alpha1.ZoneVisibilityPublic
+}
+
+// ensureZoneVisibility sets the visibility of the zone of the record, for records that had their zone assigned
+// before the visibility was recorded.
+func ensureZoneVisibility(ctx context.Context, dnsRecord *v1alpha1.DNSRecord, dnsProvider provider.Provider) error {
+	if dnsRecord.Status.ZoneVisibility != "" {
+		return nil
+	}
+	zones, err := dnsProvider.DNSZones(ctx)
+	if err != nil {
+		return err
+	}
+	for i := range zones {
+		if zones[i].ID == dnsRecord.Status.ZoneID {
+			dnsRecord.Status.ZoneVisibility = zoneVisibility(&zones[i])
+			return nil
+		}
+	}
+	return fmt.Errorf("zone %s not found", dnsRecord.Status.ZoneID)
+}
+
+// privateTargets returns a description of each private, link-local or loopback address target of the given endpoints.
+func privateTargets(endpoints []*externaldnsendpoint.Endpoint) []string {
+	var private []string
+	for _, ep := range endpoints {
+		if ep.RecordType != externaldnsendpoint.RecordTypeA && ep.RecordType != externaldnsendpoint.RecordTypeAAAA {
+			continue
+		}
+		for _, target := range ep.Targets {
+			addr, err := netip.ParseAddr(target)
+			if err != nil {
+				continue
+			}
+			addr = addr.Unmap()
+			if addr.IsPrivate() || addr.IsLoopback() || addr.IsLinkLocalUnicast() || addr.IsUnspecified() {
+				private = append(private, fmt.Sprintf("%s %s target %s", ep.RecordType, ep.DNSName, target))
+			}
+		}
+	}
+	return private
+}
+
+// checkPrivateTargets checks the endpoints of a record publishing to a public zone have no private targets, as
+// leaking cluster internal addresses into public DNS is a common mistake. With the warn policy the PrivateTargets
+// condition is set on the record, with the reject policy an error is returned, or the endpoints with private targets
+// are rejected when the record is partially published.
+func (r *DNSRecordReconciler) checkPrivateTargets(dnsRecord *v1alpha1.DNSRecord) error {
+	var private []string
+	// excluded targets are never published, invalid exclusions are reported when publishing
+	exclusions, _ := r.endpointExclusionsFor(dnsRecord)
+	if r.PrivateTargetPolicy != PrivateTargetPolicyNone && dnsRecord.Status.ZoneVisibility == v1alpha1.ZoneVisibilityPublic {
+		endpoints, _ := exclusions.filter(dnsRecord.Spec.Endpoints)
+		private = privateTargets(endpoints)
+	}
+
+	if len(private) == 0 || r.PrivateTargetPolicy != PrivateTargetPolicyWarn {
+		meta.RemoveStatusCondition(&dnsRecord.Status.Conditions, string(v1alpha1.ConditionTypePrivateTargets))
+	}
+	if len(private) == 0 {
+		return nil
+	}
+	if r.PrivateTargetPolicy == PrivateTargetPolicyReject {
+		if !dnsRecord.IsPartialPublish() {
+			return fmt.Errorf("private targets can't be published in public zone %s: %s", dnsRecord.Status.ZoneDomainName, strings.Join(private, ", "))
+		}
+		rejectEndpoints(dnsRecord, func(ep *externaldnsendpoint.Endpoint) string {
+			endpoints, _ := exclusions.filter([]*externaldnsendpoint.Endpoint{ep})
+			if private := privateTargets(endpoints); len(private) > 0 {
+				return fmt.Sprintf("private targets can't be published in public zone %s: %s", dnsRecord.Status.ZoneDomainName, strings.Join(private, ", "))
+			}
+			return ""
+		})
+		return nil
+	}
+	setDNSRecordCondition(dnsRecord, string(v1alpha1.ConditionTypePrivateTargets), metav1.ConditionTrue,
+		string(v1alpha1.ConditionReasonPrivateTargetsInPublicZone), fmt.Sprintf("Private targets are published in public zone %s: %s", dnsRecord.Status.ZoneDomainName, strings.Join(private, ", ")))
+	return nil
+}
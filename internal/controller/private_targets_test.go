@@ -0,0 +1,102 @@
+//go:build integration
+
+package controller
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	externaldnsendpoint "sigs.k8s.io/external-dns/endpoint"
+
+	"github.com/kuadrant/dns-operator/api/v1alpha1"
+)
+
+var _ = Describe("PrivateTargets", func() {
+	var dnsRecord *v1alpha1.DNSRecord
+
+	BeforeEach(func() {
+		dnsRecord = &v1alpha1.DNSRecord{
+			Spec: v1alpha1.DNSRecordSpec{
+				RootHost: "foo.example.com",
+				Endpoints: []*externaldnsendpoint.Endpoint{
+					externaldnsendpoint.NewEndpoint("foo.example.com", externaldnsendpoint.RecordTypeA, "1.1.1.1", "10.0.0.1"),
+					externaldnsendpoint.NewEndpoint("foo.example.com", externaldnsendpoint.RecordTypeAAAA, "fe80::1"),
+					externaldnsendpoint.NewEndpoint("bar.example.com", externaldnsendpoint.RecordTypeCNAME, "localhost"),
+				},
+			},
+			Status: v1alpha1.DNSRecordStatus{
+				ZoneDomainName: "example.com",
+				ZoneVisibility: v1alpha1.ZoneVisibilityPublic,
+			},
+		}
+	})
+
+	It("should find private, link-local and loopback targets", func() {
+		Expect(privateTargets([]*externaldnsendpoint.Endpoint{
+			externaldnsendpoint.NewEndpoint("foo.example.com", externaldnsendpoint.RecordTypeA, "1.1.1.1", "10.0.0.1", "192.168.1.1", "127.0.0.1", "169.254.0.1"),
+			externaldnsendpoint.NewEndpoint("foo.example.com", externaldnsendpoint.RecordTypeAAAA, "2001:db8::1", "fd00::1", "::ffff:172.16.0.1"),
+			externaldnsendpoint.NewEndpoint("foo.example.com", externaldnsendpoint.RecordTypeTXT, "10.0.0.1"),
+		})).To(ConsistOf(
+			"A foo.example.com target 10.0.0.1",
+			"A foo.example.com target 192.168.1.1",
+			"A foo.example.com target 127.0.0.1",
+			"A foo.example.com target 169.254.0.1",
+			"AAAA foo.example.com target fd00::1",
+			"AAAA foo.example.com target ::ffff:172.16.0.1",
+		))
+	})
+
+	It("should set the PrivateTargets condition with the warn policy", func() {
+		r := &DNSRecordReconciler{PrivateTargetPolicy: PrivateTargetPolicyWarn}
+		Expect(r.checkPrivateTargets(dnsRecord)).To(Succeed())
+		cond := meta.FindStatusCondition(dnsRecord.Status.Conditions, string(v1alpha1.ConditionTypePrivateTargets))
+		Expect(cond).ToNot(BeNil())
+		Expect(cond.Status).To(Equal(metav1.ConditionTrue))
+		Expect(cond.Reason).To(Equal(string(v1alpha1.ConditionReasonPrivateTargetsInPublicZone)))
+		Expect(cond.Message).To(ContainSubstring("A foo.example.com target 10.0.0.1, AAAA foo.example.com target fe80::1"))
+
+		By("removing the private targets")
+		dnsRecord.Spec.Endpoints = dnsRecord.Spec.Endpoints[2:]
+		Expect(r.checkPrivateTargets(dnsRecord)).To(Succeed())
+		Expect(meta.FindStatusCondition(dnsRecord.Status.Conditions, string(v1alpha1.ConditionTypePrivateTargets))).To(BeNil())
+	})
+
+	It("should reject private targets with the reject policy", func() {
+		r := &DNSRecordReconciler{PrivateTargetPolicy: PrivateTargetPolicyReject}
+		Expect(r.checkPrivateTargets(dnsRecord)).To(MatchError(ContainSubstring("private targets can't be published in public zone example.com")))
+		Expect(meta.FindStatusCondition(dnsRecord.Status.Conditions, string(v1alpha1.ConditionTypePrivateTargets))).To(BeNil())
+	})
+
+	It("should reject only the endpoints with private targets of partially published records", func() {
+		r := &DNSRecordReconciler{PrivateTargetPolicy: PrivateTargetPolicyReject}
+		dnsRecord.Annotations = map[string]string{v1alpha1.PartialPublishAnnotation: "true"}
+		resetEndpointStatuses(dnsRecord)
+		Expect(r.checkPrivateTargets(dnsRecord)).To(Succeed())
+		Expect(dnsRecord.Spec.Endpoints).To(HaveLen(1))
+		Expect(dnsRecord.Spec.Endpoints[0].RecordType).To(Equal(externaldnsendpoint.RecordTypeCNAME))
+		Expect(dnsRecord.Status.EndpointStatuses[0].Reason).To(Equal(v1alpha1.EndpointStatusReasonRejected))
+		Expect(dnsRecord.Status.EndpointStatuses[0].Message).To(ContainSubstring("A foo.example.com target 10.0.0.1"))
+		Expect(dnsRecord.Status.EndpointStatuses[1].Reason).To(Equal(v1alpha1.EndpointStatusReasonRejected))
+		Expect(dnsRecord.Status.EndpointStatuses[2].Reason).To(BeEmpty())
+	})
+
+	It("should ignore excluded targets", func() {
+		r := &DNSRecordReconciler{PrivateTargetPolicy: PrivateTargetPolicyReject}
+		dnsRecord.Spec.ExcludeTargetCIDRs = []string{"10.0.0.0/8", "fe80::/10"}
+		Expect(r.checkPrivateTargets(dnsRecord)).To(Succeed())
+	})
+
+	It("should allow private targets in private zones", func() {
+		r := &DNSRecordReconciler{PrivateTargetPolicy: PrivateTargetPolicyReject}
+		dnsRecord.Status.ZoneVisibility = v1alpha1.ZoneVisibilityPrivate
+		Expect(r.checkPrivateTargets(dnsRecord)).To(Succeed())
+	})
+
+	It("should not check targets without a policy", func() {
+		r := &DNSRecordReconciler{}
+		Expect(r.checkPrivateTargets(dnsRecord)).To(Succeed())
+		Expect(meta.FindStatusCondition(dnsRecord.Status.Conditions, string(v1alpha1.ConditionTypePrivateTargets))).To(BeNil())
+	})
+})
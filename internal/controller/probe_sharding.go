@@ -0,0 +1,63 @@
+package controller
+
+import (
+	"hash/fnv"
+	"strconv"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	"github.com/kuadrant/dns-operator/api/v1alpha1"
+)
+
+// ProbeShardLabel is the shard of a DNSHealthCheckProbe, set by the DNSRecord controller when probes are sharded
+// across several probe controller replicas.
+const ProbeShardLabel = "kuadrant.io/probe-shard"
+
+// ProbeShardFor returns the shard, between 0 and shards-1, of the probe with the given namespace and name. Shards are
+// assigned with a consistent hash so changing the number of shards only moves the probes of added or removed shards.
+func ProbeShardFor(namespace, name string, shards int) int {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(namespace + "/" + name))
+	return jumpHash(h.Sum64(), shards)
+}
+
+// jumpHash is the jump consistent hash of Lamping and Veach (https://arxiv.org/abs/1406.2294)
+func jumpHash(key uint64, buckets int) int {
+	var b, j int64 = -1, 0
+	for j < int64(buckets) {
+		b = j
+		key = key*2862933555777941757 + 1
+		j = int64(float64(b+1) * (float64(int64(1)<<31) / float64((key>>33)+1)))
+	}
+	return int(b)
+}
+
+// setProbeShard sets the shard label of the probe if probes are sharded, or removes it if they are not.
+func (r *DNSRecordReconciler) setProbeShard(probe *v1alpha1.DNSHealthCheckProbe) {
+	if r.ProbeShards <= 1 {
+		delete(probe.Labels, ProbeShardLabel)
+		return
+	}
+	if probe.Labels == nil {
+		probe.Labels = map[string]string{}
+	}
+	probe.Labels[ProbeShardLabel] = strconv.Itoa(ProbeShardFor(probe.Namespace, probe.Name, r.ProbeShards))
+}
+
+// inShard returns true if the probe belongs to the shard of the reconciler, all probes belong to unsharded reconcilers.
+func (r *DNSProbeReconciler) inShard(probe client.Object) bool {
+	return r.Shard == "" || probe.GetLabels()[ProbeShardLabel] == r.Shard
+}
+
+// shardPredicate passes the events of probes in the shard of the reconciler, and of probes that were moved out of it
+// so their workers are stopped.
+func (r *DNSProbeReconciler) shardPredicate() predicate.Predicate {
+	return predicate.Funcs{
+		CreateFunc:  func(e event.CreateEvent) bool { return r.inShard(e.Object) },
+		DeleteFunc:  func(e event.DeleteEvent) bool { return r.inShard(e.Object) },
+		UpdateFunc:  func(e event.UpdateEvent) bool { return r.inShard(e.ObjectOld) || r.inShard(e.ObjectNew) },
+		GenericFunc: func(e event.GenericEvent) bool { return r.inShard(e.Object) },
+	}
+}
@@ -0,0 +1,62 @@
+//go:build integration
+
+package controller
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kuadrant/dns-operator/api/v1alpha1"
+)
+
+var _ = Describe("ProbeSharding", func() {
+	It("should spread probes across all shards", func() {
+		counts := make([]int, 4)
+		for i := 0; i < 1000; i++ {
+			shard := ProbeShardFor("test", fmt.Sprintf("probe-%d", i), 4)
+			Expect(shard).To(BeNumerically(">=", 0))
+			Expect(shard).To(BeNumerically("<", 4))
+			counts[shard]++
+		}
+		for _, count := range counts {
+			Expect(count).To(BeNumerically("~", 250, 50))
+		}
+	})
+
+	It("should only move probes to an added shard", func() {
+		for i := 0; i < 1000; i++ {
+			before := ProbeShardFor("test", fmt.Sprintf("probe-%d", i), 4)
+			after := ProbeShardFor("test", fmt.Sprintf("probe-%d", i), 5)
+			if before != after {
+				Expect(after).To(Equal(4))
+			}
+		}
+	})
+
+	It("should label probes with their shard when sharded", func() {
+		probe := &v1alpha1.DNSHealthCheckProbe{
+			ObjectMeta: metav1.ObjectMeta{Name: "probe", Namespace: "test", Labels: map[string]string{ProbeOwnerLabel: "record"}},
+		}
+		r := &DNSRecordReconciler{ProbeShards: 3}
+		r.setProbeShard(probe)
+		Expect(probe.Labels).To(HaveKeyWithValue(ProbeShardLabel, fmt.Sprint(ProbeShardFor("test", "probe", 3))))
+
+		r.ProbeShards = 0
+		r.setProbeShard(probe)
+		Expect(probe.Labels).ToNot(HaveKey(ProbeShardLabel))
+		Expect(probe.Labels).To(HaveKey(ProbeOwnerLabel))
+	})
+
+	It("should only run probes of its shard", func() {
+		probe := &v1alpha1.DNSHealthCheckProbe{
+			ObjectMeta: metav1.ObjectMeta{Name: "probe", Namespace: "test", Labels: map[string]string{ProbeShardLabel: "1"}},
+		}
+		Expect((&DNSProbeReconciler{}).inShard(probe)).To(BeTrue())
+		Expect((&DNSProbeReconciler{Shard: "1"}).inShard(probe)).To(BeTrue())
+		Expect((&DNSProbeReconciler{Shard: "0"}).inShard(probe)).To(BeFalse())
+	})
+})
@@ -0,0 +1,104 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	"github.com/hashicorp/go-multierror"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kuadrant/dns-operator/api/v1alpha1"
+)
+
+// probeFieldOwner is the field manager of the health check probes applied by the DNSRecord controller
+const probeFieldOwner = "dns-operator"
+
+// probeChanges are the changes to the health check probes of a record that make them match its desired probes.
+type probeChanges struct {
+	// apply are the desired probes that don't exist or differ from the existing probe
+	apply []*v1alpha1.DNSHealthCheckProbe
+	// delete are the existing probes that are no longer desired, e.g. for targets removed from the record
+	delete []*v1alpha1.DNSHealthCheckProbe
+}
+
+// diffProbes returns the changes that make the current probes of a record match the desired probes. Desired probes
+// that already exist with the same spec, labels and owner are left alone, so only the probes of changed targets are
+// written.
+func diffProbes(desired []*v1alpha1.DNSHealthCheckProbe, current []v1alpha1.DNSHealthCheckProbe) probeChanges {
+	existing := make(map[string]*v1alpha1.DNSHealthCheckProbe, len(current))
+	for i := range current {
+		existing[current[i].Name] = &current[i]
+	}
+
+	var changes probeChanges
+	for _, probe := range desired {
+		if c, ok := existing[probe.Name]; !ok || !probeUpToDate(probe, c) {
+			changes.apply = append(changes.apply, probe)
+		}
+		delete(existing, probe.Name)
+	}
+	for _, probe := range current {
+		if stale, ok := existing[probe.Name]; ok && stale.DeletionTimestamp == nil {
+			changes.delete = append(changes.delete, stale)
+		}
+	}
+	return changes
+}
+
+// probeUpToDate returns true if the current probe has the spec, owner and shard label of the desired probe.
+func probeUpToDate(desired, current *v1alpha1.DNSHealthCheckProbe) bool {
+	if !equality.Semantic.DeepEqual(desired.Spec, current.Spec) {
+		return false
+	}
+	for _, key := range []string{ProbeOwnerLabel, ProbeShardLabel} {
+		desiredValue, desiredOk := desired.Labels[key]
+		currentValue, currentOk := current.Labels[key]
+		if desiredOk != currentOk || desiredValue != currentValue {
+			return false
+		}
+	}
+	desiredOwner, currentOwner := metav1.GetControllerOf(desired), metav1.GetControllerOf(current)
+	return desiredOwner == nil || (currentOwner != nil && currentOwner.UID == desiredOwner.UID)
+}
+
+// syncProbes makes the health check probes of the record match the desired probes. The existing probes are read with
+// a single list, new and changed probes are written with server-side apply and probes that are no longer desired are
+// deleted.
+func (r *DNSRecordReconciler) syncProbes(ctx context.Context, dnsRecord *v1alpha1.DNSRecord, desired []*v1alpha1.DNSHealthCheckProbe, logger logr.Logger) error {
+	current := &v1alpha1.DNSHealthCheckProbeList{}
+	if err := r.List(ctx, current, &client.ListOptions{
+		LabelSelector: labels.SelectorFromSet(map[string]string{
+			ProbeOwnerLabel: BuildOwnerLabelValue(dnsRecord),
+		}),
+		Namespace: dnsRecord.Namespace,
+	}); err != nil {
+		return err
+	}
+
+	changes := diffProbes(desired, current.Items)
+	for _, probe := range changes.apply {
+		logger.V(1).Info(fmt.Sprintf("Applying probe: %s", probe.Name))
+		probe.SetGroupVersionKind(v1alpha1.GroupVersion.WithKind("DNSHealthCheckProbe"))
+		// if one of them fails - health checks for this record are invalid anyway, so no sense to continue
+		if err := r.Patch(ctx, probe, client.Apply, client.FieldOwner(probeFieldOwner), client.ForceOwnership); err != nil {
+			return err
+		}
+	}
+
+	var deleteErrors error
+	for _, probe := range changes.delete {
+		logger.V(1).Info(fmt.Sprintf("Deleting probe: %s", probe.Name))
+		if err := r.Delete(ctx, probe); client.IgnoreNotFound(err) != nil {
+			deleteErrors = multierror.Append(deleteErrors, err)
+		}
+	}
+	if len(changes.apply) > 0 || len(changes.delete) > 0 {
+		logger.Info("Synced probes", "applied", len(changes.apply), "deleted", len(changes.delete), "unchanged", len(desired)-len(changes.apply))
+	}
+	return deleteErrors
+}
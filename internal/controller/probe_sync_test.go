@@ -0,0 +1,66 @@
+//go:build integration
+
+package controller
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+
+	"github.com/kuadrant/dns-operator/api/v1alpha1"
+)
+
+var _ = Describe("Probe sync", func() {
+	var dnsRecord *v1alpha1.DNSRecord
+
+	desiredProbes := func() []*v1alpha1.DNSHealthCheckProbe {
+		probes := buildDesiredProbes(dnsRecord, &[]string{"1.1.1.1", "2.2.2.2"}, false)
+		for _, probe := range probes {
+			probe.OwnerReferences = []metav1.OwnerReference{{Kind: "DNSRecord", Name: dnsRecord.Name, UID: dnsRecord.UID, Controller: ptr.To(true)}}
+		}
+		return probes
+	}
+
+	BeforeEach(func() {
+		dnsRecord = &v1alpha1.DNSRecord{
+			ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "test", UID: types.UID("uid")},
+			Spec: v1alpha1.DNSRecordSpec{
+				RootHost:    "foo.example.com",
+				HealthCheck: getTestHealthCheckSpec(),
+			},
+		}
+	})
+
+	It("should apply all probes that don't exist", func() {
+		changes := diffProbes(desiredProbes(), nil)
+		Expect(changes.apply).To(HaveLen(2))
+		Expect(changes.delete).To(BeEmpty())
+	})
+
+	It("should only apply changed probes and delete probes of removed targets", func() {
+		current := []v1alpha1.DNSHealthCheckProbe{}
+		for _, probe := range desiredProbes() {
+			current = append(current, *probe)
+		}
+		current[1].Spec.Address = "3.3.3.3"
+		current = append(current, *buildDesiredProbes(dnsRecord, &[]string{"4.4.4.4"}, false)[0])
+
+		changes := diffProbes(desiredProbes(), current)
+		Expect(changes.apply).To(HaveLen(1))
+		Expect(changes.apply[0].Spec.Address).To(Equal("2.2.2.2"))
+		Expect(changes.delete).To(HaveLen(1))
+		Expect(changes.delete[0].Spec.Address).To(Equal("4.4.4.4"))
+	})
+
+	It("should apply probes with a changed shard", func() {
+		current := []v1alpha1.DNSHealthCheckProbe{}
+		for _, probe := range desiredProbes() {
+			current = append(current, *probe)
+		}
+		current[0].Labels[ProbeShardLabel] = "1"
+		Expect(diffProbes(desiredProbes(), current).apply).To(HaveLen(1))
+	})
+})
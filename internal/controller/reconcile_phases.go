@@ -0,0 +1,48 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	"github.com/kuadrant/dns-operator/internal/metrics"
+)
+
+// ReconcilePhase is a part of the reconcile of a DNSRecord that is timed.
+type ReconcilePhase string
+
+const (
+	// ReconcilePhaseRegistry reads the zone through the registry and adjusts the endpoints of the record to it.
+	ReconcilePhaseRegistry ReconcilePhase = "registry"
+	// ReconcilePhasePlan calculates and checks the changes to the zone.
+	ReconcilePhasePlan ReconcilePhase = "plan"
+	// ReconcilePhaseApply writes the changes to the provider.
+	ReconcilePhaseApply ReconcilePhase = "apply"
+	// ReconcilePhaseStatus writes the status of the record.
+	ReconcilePhaseStatus ReconcilePhase = "status"
+)
+
+// ReconcilePhases are the timed phases of a reconcile, in the order they run.
+var ReconcilePhases = []ReconcilePhase{ReconcilePhaseRegistry, ReconcilePhasePlan, ReconcilePhaseApply, ReconcilePhaseStatus}
+
+// PhaseObserver observes the phases of reconciles, e.g. to profile them.
+type PhaseObserver interface {
+	// StartPhase is called as a phase starts, the returned function is called once it completes. It is not called for
+	// phases that fail.
+	StartPhase(ctx context.Context, phase ReconcilePhase) func()
+}
+
+// startPhase starts timing a phase of the reconcile, the returned function completes it.
+func (r *DNSRecordReconciler) startPhase(ctx context.Context, phase ReconcilePhase) func() {
+	var complete func()
+	if r.PhaseObserver != nil {
+		complete = r.PhaseObserver.StartPhase(ctx, phase)
+	}
+	start := time.Now()
+	return func() {
+		duration := time.Since(start)
+		if complete != nil {
+			complete()
+		}
+		metrics.ReconcilePhaseDuration.WithLabelValues(string(phase)).Observe(duration.Seconds())
+	}
+}
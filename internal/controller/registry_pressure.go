@@ -0,0 +1,137 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kuadrant/dns-operator/api/v1alpha1"
+	externaldnsregistry "github.com/kuadrant/dns-operator/internal/external-dns/registry"
+	"github.com/kuadrant/dns-operator/internal/metrics"
+)
+
+// zoneRecordPressureRatio is the share of the record limit of a zone from which the records publishing to the zone get
+// the RegistryPressure condition
+const zoneRecordPressureRatio = 0.8
+
+// zoneKey identifies a zone in the zone record count metrics.
+type zoneKey struct {
+	domainName string
+	id         string
+}
+
+// zoneRecordGauges tracks the records that set the record count metrics of each zone, so the metrics of a zone are
+// removed once none of the records publishing to it set them.
+type zoneRecordGauges struct {
+	lock    sync.Mutex
+	zones   map[zoneKey]map[types.NamespacedName]struct{}
+	records map[types.NamespacedName]zoneKey
+}
+
+func newZoneRecordGauges() *zoneRecordGauges {
+	return &zoneRecordGauges{
+		zones:   map[zoneKey]map[types.NamespacedName]struct{}{},
+		records: map[types.NamespacedName]zoneKey{},
+	}
+}
+
+// set sets the record count metrics of the zone of the given record.
+func (g *zoneRecordGauges) set(record types.NamespacedName, zone zoneKey, counts externaldnsregistry.ZoneRecordCounts, limit int) {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	if previous, ok := g.records[record]; ok && previous != zone {
+		g.untrack(record, previous)
+	}
+	if g.zones[zone] == nil {
+		g.zones[zone] = map[types.NamespacedName]struct{}{}
+	}
+	g.zones[zone][record] = struct{}{}
+	g.records[record] = zone
+
+	metrics.ZoneRecords.WithLabelValues(zone.domainName, zone.id).Set(float64(counts.Records))
+	metrics.ZoneRegistryRecords.WithLabelValues(zone.domainName, zone.id).Set(float64(counts.Registry))
+	metrics.ZoneRecordLimit.WithLabelValues(zone.domainName, zone.id).Set(float64(limit))
+}
+
+// remove stops the given record from setting the record count metrics of its zone, e.g. when it is deleted.
+func (g *zoneRecordGauges) remove(record types.NamespacedName) {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	if zone, ok := g.records[record]; ok {
+		g.untrack(record, zone)
+	}
+}
+
+// untrack removes the given record from the records of the zone, and the metrics of the zone if it was the last.
+func (g *zoneRecordGauges) untrack(record types.NamespacedName, zone zoneKey) {
+	delete(g.records, record)
+	delete(g.zones[zone], record)
+	if len(g.zones[zone]) == 0 {
+		delete(g.zones, zone)
+		metrics.ResetZoneRecordMetrics(zone.domainName, zone.id)
+	}
+}
+
+// zoneRecordLimitFor returns the record limit of the zone of the given record, from the ZoneRecordLimitKey of its
+// provider secret or the ZoneRecordLimit of the reconciler. A limit of 0 disables the limit.
+func (r *DNSRecordReconciler) zoneRecordLimitFor(ctx context.Context, dnsRecord *v1alpha1.DNSRecord) (int, error) {
+	secret := &v1.Secret{}
+	if err := r.Get(ctx, client.ObjectKey{Namespace: dnsRecord.Namespace, Name: dnsRecord.Spec.ProviderRef.Name}, secret); err != nil {
+		return 0, err
+	}
+	value, ok := secret.Data[v1alpha1.ZoneRecordLimitKey]
+	if !ok {
+		return r.ZoneRecordLimit, nil
+	}
+	limit, err := strconv.Atoi(strings.TrimSpace(string(value)))
+	if err != nil || limit < 0 {
+		return 0, fmt.Errorf("provider secret %s/%s must set %s to a number of records of 0 or more",
+			secret.Namespace, secret.Name, v1alpha1.ZoneRecordLimitKey)
+	}
+	return limit, nil
+}
+
+// checkZoneRecordPressure checks the given counts of the records of the zone of the given record, from the read of the
+// zone by the registry. The registry TXT records of every owner are included, as zones with many owners can reach the
+// record limit of the provider on registry records alone. The counts are exported as metrics and the RegistryPressure
+// condition is set on the record once the zone has most of the records allowed.
+func (r *DNSRecordReconciler) checkZoneRecordPressure(ctx context.Context, dnsRecord *v1alpha1.DNSRecord, counts externaldnsregistry.ZoneRecordCounts, txtFormat externaldnsregistry.TXTFormat) error {
+	limit, err := r.zoneRecordLimitFor(ctx, dnsRecord)
+	if err != nil {
+		return err
+	}
+	if limit == 0 {
+		r.zoneGauges.remove(client.ObjectKeyFromObject(dnsRecord))
+		meta.RemoveStatusCondition(&dnsRecord.Status.Conditions, string(v1alpha1.ConditionTypeRegistryPressure))
+		return nil
+	}
+
+	r.zoneGauges.set(client.ObjectKeyFromObject(dnsRecord), zoneKey{dnsRecord.Status.ZoneDomainName, dnsRecord.Status.ZoneID}, counts, limit)
+
+	if float64(counts.Records) < zoneRecordPressureRatio*float64(limit) {
+		meta.RemoveStatusCondition(&dnsRecord.Status.Conditions, string(v1alpha1.ConditionTypeRegistryPressure))
+		return nil
+	}
+	var suggestions []string
+	if dnsRecord.Spec.RegistryZoneRef == nil {
+		suggestions = append(suggestions, "write registry TXT records to a separate zone with registryZoneRef")
+	}
+	if txtFormat == externaldnsregistry.TXTFormatBoth {
+		suggestions = append(suggestions, fmt.Sprintf("set %s of the provider secret to %q to write a single registry TXT record per endpoint",
+			v1alpha1.TXTRegistryFormatKey, externaldnsregistry.TXTFormatNew))
+	}
+	suggestions = append(suggestions, "split the records across more zones")
+	setDNSRecordCondition(dnsRecord, string(v1alpha1.ConditionTypeRegistryPressure), metav1.ConditionTrue,
+		string(v1alpha1.ConditionReasonApproachingZoneRecordLimit),
+		fmt.Sprintf("Zone %s has %d of the %d records allowed, %d of them registry TXT records, %s",
+			dnsRecord.Status.ZoneDomainName, counts.Records, limit, counts.Registry, strings.Join(suggestions, ", or ")))
+	return nil
+}
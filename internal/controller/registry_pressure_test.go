@@ -0,0 +1,102 @@
+//go:build integration
+
+package controller
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kuadrant/dns-operator/api/v1alpha1"
+	externaldnsregistry "github.com/kuadrant/dns-operator/internal/external-dns/registry"
+	"github.com/kuadrant/dns-operator/internal/metrics"
+)
+
+var _ = Describe("Registry pressure", func() {
+	counts := externaldnsregistry.ZoneRecordCounts{Records: 21, Registry: 10}
+	var secret *v1.Secret
+	var dnsRecord *v1alpha1.DNSRecord
+
+	BeforeEach(func() {
+		secret = &v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "provider", Namespace: "default"},
+			Type:       v1alpha1.SecretTypeKuadrantInmemory,
+			Data:       map[string][]byte{},
+		}
+		dnsRecord = &v1alpha1.DNSRecord{
+			ObjectMeta: metav1.ObjectMeta{Name: "record", Namespace: "default"},
+			Spec: v1alpha1.DNSRecordSpec{
+				RootHost:    "host0.example.com",
+				ProviderRef: v1alpha1.ProviderRef{Name: "provider"},
+			},
+			Status: v1alpha1.DNSRecordStatus{ZoneDomainName: "example.com", ZoneID: "example.com"},
+		}
+	})
+
+	reconciler := func(limit int) *DNSRecordReconciler {
+		return &DNSRecordReconciler{
+			Client:          fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(secret).Build(),
+			ZoneRecordLimit: limit,
+			zoneGauges:      newZoneRecordGauges(),
+		}
+	}
+
+	It("should set the condition on records of zones with most of the records allowed", func() {
+		Expect(reconciler(25).checkZoneRecordPressure(ctx, dnsRecord, counts, externaldnsregistry.TXTFormatBoth)).To(Succeed())
+		condition := meta.FindStatusCondition(dnsRecord.Status.Conditions, string(v1alpha1.ConditionTypeRegistryPressure))
+		Expect(condition).NotTo(BeNil())
+		Expect(condition.Reason).To(Equal(string(v1alpha1.ConditionReasonApproachingZoneRecordLimit)))
+		Expect(condition.Message).To(Equal("Zone example.com has 21 of the 25 records allowed, 10 of them registry TXT records, " +
+			"write registry TXT records to a separate zone with registryZoneRef, or " +
+			`set TXT_REGISTRY_FORMAT of the provider secret to "new" to write a single registry TXT record per endpoint, or ` +
+			"split the records across more zones"))
+
+		Expect(reconciler(30).checkZoneRecordPressure(ctx, dnsRecord, counts, externaldnsregistry.TXTFormatNew)).To(Succeed())
+		Expect(meta.FindStatusCondition(dnsRecord.Status.Conditions, string(v1alpha1.ConditionTypeRegistryPressure))).To(BeNil())
+	})
+
+	It("should use the limit of the provider secret", func() {
+		secret.Data[v1alpha1.ZoneRecordLimitKey] = []byte("20")
+		Expect(reconciler(0).checkZoneRecordPressure(ctx, dnsRecord, counts, externaldnsregistry.TXTFormatNew)).To(Succeed())
+		Expect(meta.IsStatusConditionTrue(dnsRecord.Status.Conditions, string(v1alpha1.ConditionTypeRegistryPressure))).To(BeTrue())
+
+		secret.Data[v1alpha1.ZoneRecordLimitKey] = []byte("0")
+		Expect(reconciler(20).checkZoneRecordPressure(ctx, dnsRecord, counts, externaldnsregistry.TXTFormatNew)).To(Succeed())
+		Expect(meta.FindStatusCondition(dnsRecord.Status.Conditions, string(v1alpha1.ConditionTypeRegistryPressure))).To(BeNil())
+
+		secret.Data[v1alpha1.ZoneRecordLimitKey] = []byte("many")
+		Expect(reconciler(20).checkZoneRecordPressure(ctx, dnsRecord, counts, externaldnsregistry.TXTFormatNew)).
+			To(MatchError("provider secret default/provider must set ZONE_RECORD_LIMIT to a number of records of 0 or more"))
+	})
+
+	It("should remove the metrics of a zone once no record sets them", func() {
+		r := reconciler(25)
+		other := dnsRecord.DeepCopy()
+		other.Name = "other"
+		Expect(r.checkZoneRecordPressure(ctx, dnsRecord, counts, externaldnsregistry.TXTFormatNew)).To(Succeed())
+		Expect(r.checkZoneRecordPressure(ctx, other, counts, externaldnsregistry.TXTFormatNew)).To(Succeed())
+		Expect(testutil.ToFloat64(metrics.ZoneRecords.WithLabelValues("example.com", "example.com"))).To(Equal(21.0))
+
+		r.zoneGauges.remove(client.ObjectKeyFromObject(dnsRecord))
+		Expect(testutil.CollectAndCount(metrics.ZoneRecordLimit)).To(Equal(1))
+
+		other.Status.ZoneDomainName = "other.example.com"
+		other.Status.ZoneID = "other.example.com"
+		Expect(r.checkZoneRecordPressure(ctx, other, counts, externaldnsregistry.TXTFormatNew)).To(Succeed())
+		Expect(testutil.CollectAndCount(metrics.ZoneRecordLimit)).To(Equal(1))
+		Expect(testutil.ToFloat64(metrics.ZoneRecordLimit.WithLabelValues("other.example.com", "other.example.com"))).To(Equal(25.0))
+
+		secret.Data[v1alpha1.ZoneRecordLimitKey] = []byte("0")
+		disabled := reconciler(25)
+		disabled.zoneGauges = r.zoneGauges
+		Expect(disabled.checkZoneRecordPressure(ctx, other, counts, externaldnsregistry.TXTFormatNew)).To(Succeed())
+		Expect(testutil.CollectAndCount(metrics.ZoneRecordLimit)).To(Equal(0))
+	})
+})
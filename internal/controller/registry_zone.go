@@ -0,0 +1,70 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	externaldnsendpoint "sigs.k8s.io/external-dns/endpoint"
+	externaldnsprovider "sigs.k8s.io/external-dns/provider"
+
+	"github.com/kuadrant/dns-operator/api/v1alpha1"
+	"github.com/kuadrant/dns-operator/internal/provider"
+)
+
+// providerRefAccessor gives access to a provider secret referenced by a DNSRecord other than its providerRef.
+type providerRefAccessor struct {
+	namespace   string
+	providerRef v1alpha1.ProviderRef
+}
+
+var _ v1alpha1.ProviderAccessor = providerRefAccessor{}
+
+func (a providerRefAccessor) GetNamespace() string {
+	return a.namespace
+}
+
+func (a providerRefAccessor) GetProviderRef() v1alpha1.ProviderRef {
+	return a.providerRef
+}
+
+// getRegistryZoneProvider returns a Provider configured for the registry zone of the given DNSRecord.
+// If no registry zone has been assigned to the record yet, the zone with the domain name of the registryZoneRef is
+// looked up in the provider and assigned to the record status.
+func (r *DNSRecordReconciler) getRegistryZoneProvider(ctx context.Context, dnsRecord *v1alpha1.DNSRecord) (provider.Provider, error) {
+	zoneRef := dnsRecord.Spec.RegistryZoneRef
+	accessor := providerRefAccessor{
+		namespace:   dnsRecord.Namespace,
+		providerRef: dnsRecord.Spec.ProviderRef,
+	}
+	if zoneRef.ProviderRef != nil {
+		accessor.providerRef = *zoneRef.ProviderRef
+	}
+	domainName := strings.ToLower(strings.Trim(zoneRef.DomainName, "."))
+
+	if dnsRecord.Status.RegistryZoneID == "" {
+		p, err := r.ProviderFactory.ProviderFor(ctx, accessor, provider.Config{})
+		if err != nil {
+			return nil, err
+		}
+		zones, err := p.DNSZones(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, zone := range zones {
+			if strings.ToLower(strings.Trim(zone.DNSName, ".")) == domainName {
+				dnsRecord.Status.RegistryZoneID = zone.ID
+				break
+			}
+		}
+		if dnsRecord.Status.RegistryZoneID == "" {
+			return nil, fmt.Errorf("no zone found for registry domain: %s", zoneRef.DomainName)
+		}
+	}
+
+	return r.ProviderFactory.ProviderFor(ctx, accessor, provider.Config{
+		DomainFilter:   externaldnsendpoint.NewDomainFilter([]string{domainName}),
+		ZoneTypeFilter: externaldnsprovider.NewZoneTypeFilter(""),
+		ZoneIDFilter:   externaldnsprovider.NewZoneIDFilter([]string{dnsRecord.Status.RegistryZoneID}),
+	})
+}
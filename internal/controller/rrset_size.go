@@ -0,0 +1,198 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	externaldnsendpoint "sigs.k8s.io/external-dns/endpoint"
+	externaldnsplan "sigs.k8s.io/external-dns/plan"
+
+	"github.com/kuadrant/dns-operator/api/v1alpha1"
+	"github.com/kuadrant/dns-operator/internal/provider"
+)
+
+// RRsetSizePolicy is the action taken when the response to a query for an RRset of a DNSRecord would be too large
+// for resolvers, or an RRset has more targets than the provider allows.
+type RRsetSizePolicy string
+
+const (
+	// RRsetSizePolicyNone RRsets are published without any checks
+	RRsetSizePolicyNone RRsetSizePolicy = ""
+	// RRsetSizePolicyWarn oversized RRsets are published and the OversizedRRsets condition is set on the record
+	RRsetSizePolicyWarn RRsetSizePolicy = "warn"
+	// RRsetSizePolicyReject records with RRsets that don't fit in an EDNS response or have more targets than the
+	// provider allows are not published, those that only exceed the classic UDP size are published with a warning
+	RRsetSizePolicyReject RRsetSizePolicy = "reject"
+)
+
+const (
+	// maxUDPResponseSize is the size of the largest response to a resolver without EDNS, larger responses are
+	// truncated and retried over TCP
+	maxUDPResponseSize = 512
+	// maxEDNSResponseSize is the EDNS buffer size recommended by DNS flag day 2020, larger responses are truncated
+	maxEDNSResponseSize = 1232
+	// ednsOptSize is the size of the OPT record of an EDNS response
+	ednsOptSize = 11
+)
+
+// errOversizedRRsets is returned by applyChanges for a record with RRsets that are rejected by the RRset size policy.
+var errOversizedRRsets = errors.New("oversized RRsets")
+
+// ParseRRsetSizePolicy returns the RRsetSizePolicy for the given value, or an error if it is unknown.
+func ParseRRsetSizePolicy(value string) (RRsetSizePolicy, error) {
+	switch policy := RRsetSizePolicy(value); policy {
+	case RRsetSizePolicyNone, RRsetSizePolicyWarn, RRsetSizePolicyReject:
+		return policy, nil
+	}
+	return RRsetSizePolicyNone, fmt.Errorf("unknown RRset size policy %q, must be one of %q or %q",
+		value, RRsetSizePolicyWarn, RRsetSizePolicyReject)
+}
+
+// wireNameSize returns the size of the given name in a DNS message without compression.
+func wireNameSize(name string) int {
+	name = strings.TrimSuffix(name, ".")
+	if name == "" {
+		return 1
+	}
+	return len(name) + 2
+}
+
+// rdataSize returns the size of the data of a resource record with the given type and target in a DNS message.
+func rdataSize(recordType, target string) int {
+	switch recordType {
+	case externaldnsendpoint.RecordTypeA:
+		return 4
+	case externaldnsendpoint.RecordTypeAAAA:
+		return 16
+	case externaldnsendpoint.RecordTypeCNAME, externaldnsendpoint.RecordTypeNS, externaldnsendpoint.RecordTypePTR:
+		return wireNameSize(target)
+	case externaldnsendpoint.RecordTypeMX:
+		// preference and exchange, e.g. "10 mail.example.com"
+		fields := strings.Fields(target)
+		return 2 + wireNameSize(fields[len(fields)-1])
+	case externaldnsendpoint.RecordTypeSRV:
+		// priority, weight, port and target, e.g. "10 5 443 svc.example.com"
+		fields := strings.Fields(target)
+		return 6 + wireNameSize(fields[len(fields)-1])
+	case externaldnsendpoint.RecordTypeTXT:
+		// character strings of up to 255 bytes, each with a length byte
+		text := strings.Trim(target, "\"")
+		return len(text) + len(text)/255 + 1
+	case v1alpha1.RecordTypeNAPTR:
+		// order, preference, flags, service and regexp character strings and replacement, e.g.
+		// `100 10 "u" "E2U+sip" "!^.*$!sip:info@example.com!" .`
+		fields := strings.Fields(target)
+		if len(fields) == 5 && strings.HasSuffix(fields[4], `"`) {
+			// the root replacement loses its dot once the target is normalized
+			fields = append(fields, ".")
+		}
+		if len(fields) < 6 {
+			return len(target)
+		}
+		// the quotes and spaces of the three character strings are replaced by a length byte each
+		text := strings.Join(fields[2:len(fields)-1], " ")
+		return 4 + len(text) - 5 + wireNameSize(fields[len(fields)-1])
+	case v1alpha1.RecordTypeTLSA:
+		// usage, selector, matching type and hex encoded data, e.g. "3 1 1 0c72ac70..."
+		fields := strings.Fields(target)
+		if len(fields) < 4 {
+			return len(target)
+		}
+		return 3 + len(strings.Join(fields[3:], ""))/2
+	}
+	return len(target)
+}
+
+// responseSize returns the size of the response to a query for the RRset of the given endpoint, with each target
+// as an answer whose owner name is compressed to a pointer to the question.
+func responseSize(ep *externaldnsendpoint.Endpoint) int {
+	// header, question name, type and class
+	size := 12 + wireNameSize(ep.DNSName) + 4
+	for _, target := range ep.Targets {
+		if strings.TrimSpace(target) == "" {
+			continue
+		}
+		// name pointer, type, class, ttl, data length and data
+		size += 2 + 10 + rdataSize(ep.RecordType, target)
+	}
+	return size
+}
+
+// resultingRRsets returns the RRsets of the given desired endpoints as they will be in the zone once the given
+// changes are applied, including the targets other owners of the RRsets publish.
+func resultingRRsets(desired, zone []*externaldnsendpoint.Endpoint, changes *externaldnsplan.Changes) []*externaldnsendpoint.Endpoint {
+	rrsets := make(map[externaldnsendpoint.EndpointKey]*externaldnsendpoint.Endpoint, len(desired))
+	for _, ep := range desired {
+		rrsets[ep.Key()] = ep
+	}
+	for _, ep := range zone {
+		if _, ok := rrsets[ep.Key()]; ok {
+			rrsets[ep.Key()] = ep
+		}
+	}
+	for _, ep := range append(append([]*externaldnsendpoint.Endpoint{}, changes.Create...), changes.UpdateNew...) {
+		if _, ok := rrsets[ep.Key()]; ok {
+			rrsets[ep.Key()] = ep
+		}
+	}
+
+	resulting := make([]*externaldnsendpoint.Endpoint, 0, len(rrsets))
+	for _, ep := range desired {
+		resulting = append(resulting, rrsets[ep.Key()])
+	}
+	return resulting
+}
+
+// oversizedRRsets returns a description of each of the given RRsets with a response larger than the EDNS size or
+// more targets than the provider allows, and of each with a response only larger than the classic UDP size.
+func oversizedRRsets(rrsets []*externaldnsendpoint.Endpoint, limiter provider.TargetLimiter) (rejected, warned []string) {
+	for _, ep := range rrsets {
+		name := strings.TrimSpace(ep.RecordType + " " + ep.DNSName + " " + ep.SetIdentifier)
+		if limiter != nil {
+			if maxTargets := limiter.MaxTargets(ep.RecordType); maxTargets > 0 && len(ep.Targets) > maxTargets {
+				rejected = append(rejected, fmt.Sprintf("%s has %d targets, the provider allows %d", name, len(ep.Targets), maxTargets))
+				continue
+			}
+		}
+		size := responseSize(ep)
+		if size+ednsOptSize > maxEDNSResponseSize {
+			rejected = append(rejected, fmt.Sprintf("%s response is %d bytes, larger than the %d bytes of EDNS", name, size+ednsOptSize, maxEDNSResponseSize))
+		} else if size > maxUDPResponseSize {
+			warned = append(warned, fmt.Sprintf("%s response is %d bytes, larger than the %d bytes of UDP without EDNS", name, size, maxUDPResponseSize))
+		}
+	}
+	return rejected, warned
+}
+
+// checkRRsetSizes simulates the responses for the given RRsets of a record before they are published, as resolvers
+// truncate oversized responses and some fail to retry them over TCP. RRsets that only exceed the classic UDP size are
+// reported with the OversizedRRsets condition, those that exceed the EDNS size or the targets allowed by the provider
+// return an error with the reject policy.
+func (r *DNSRecordReconciler) checkRRsetSizes(dnsRecord *v1alpha1.DNSRecord, dnsProvider provider.Provider, rrsets []*externaldnsendpoint.Endpoint) error {
+	if r.RRsetSizePolicy == RRsetSizePolicyNone {
+		meta.RemoveStatusCondition(&dnsRecord.Status.Conditions, string(v1alpha1.ConditionTypeOversizedRRsets))
+		return nil
+	}
+	limiter, _ := provider.As[provider.TargetLimiter](dnsProvider)
+	rejected, warned := oversizedRRsets(rrsets, limiter)
+
+	if r.RRsetSizePolicy == RRsetSizePolicyReject && len(rejected) > 0 {
+		meta.RemoveStatusCondition(&dnsRecord.Status.Conditions, string(v1alpha1.ConditionTypeOversizedRRsets))
+		return fmt.Errorf("%w: %s, split the endpoints across more names or reduce their targets", errOversizedRRsets, strings.Join(rejected, ", "))
+	}
+	oversized := append(rejected, warned...)
+	if len(oversized) == 0 {
+		meta.RemoveStatusCondition(&dnsRecord.Status.Conditions, string(v1alpha1.ConditionTypeOversizedRRsets))
+		return nil
+	}
+	reason := v1alpha1.ConditionReasonExceedsUDPSize
+	if len(rejected) > 0 {
+		reason = v1alpha1.ConditionReasonExceedsLimits
+	}
+	setDNSRecordCondition(dnsRecord, string(v1alpha1.ConditionTypeOversizedRRsets), metav1.ConditionTrue, string(reason),
+		fmt.Sprintf("Responses may be truncated, split the endpoints across more names or ensure resolvers can use TCP and EDNS: %s", strings.Join(oversized, ", ")))
+	return nil
+}
@@ -0,0 +1,113 @@
+//go:build integration
+
+package controller
+
+import (
+	"fmt"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	externaldnsendpoint "sigs.k8s.io/external-dns/endpoint"
+	externaldnsplan "sigs.k8s.io/external-dns/plan"
+
+	"github.com/kuadrant/dns-operator/api/v1alpha1"
+	"github.com/kuadrant/dns-operator/internal/provider"
+)
+
+type maxTargetsProvider struct {
+	provider.Provider
+	maxTargets int
+}
+
+func (p *maxTargetsProvider) MaxTargets(_ string) int {
+	return p.maxTargets
+}
+
+// addressTargets returns count IPv4 address targets.
+func addressTargets(count int) []string {
+	targets := make([]string, 0, count)
+	for i := 0; i < count; i++ {
+		targets = append(targets, fmt.Sprintf("192.0.2.%d", i%256))
+	}
+	return targets
+}
+
+var _ = Describe("RRset size", func() {
+	It("should estimate the size of responses", func() {
+		// header 12, question 17 + 4, answers 2 * (12 + 4)
+		Expect(responseSize(externaldnsendpoint.NewEndpoint("foo.example.com", externaldnsendpoint.RecordTypeA, "1.1.1.1", "2.2.2.2"))).To(Equal(65))
+		// answer 12 + 17
+		Expect(responseSize(externaldnsendpoint.NewEndpoint("foo.example.com", externaldnsendpoint.RecordTypeCNAME, "bar.example.com"))).To(Equal(62))
+		// answer 12 + 256 + 2 length bytes
+		Expect(responseSize(externaldnsendpoint.NewEndpoint("foo.example.com", externaldnsendpoint.RecordTypeTXT, "\""+strings.Repeat("a", 256)+"\""))).To(Equal(303))
+		// answer 12 + order and preference 4 + character strings 2 + 8 + 1 + replacement 1
+		Expect(responseSize(externaldnsendpoint.NewEndpoint("foo.example.com", v1alpha1.RecordTypeNAPTR, `100 10 "u" "E2U+sip" "" .`))).To(Equal(61))
+		// answer 12 + usage, selector and matching type 3 + digest 32
+		Expect(responseSize(externaldnsendpoint.NewEndpoint("foo.example.com", v1alpha1.RecordTypeTLSA, "3 1 1 "+strings.Repeat("0c72ac70", 8)))).To(Equal(80))
+	})
+
+	It("should report RRsets larger than the UDP and EDNS sizes", func() {
+		rejected, warned := oversizedRRsets([]*externaldnsendpoint.Endpoint{
+			externaldnsendpoint.NewEndpoint("small.example.com", externaldnsendpoint.RecordTypeA, addressTargets(2)...),
+			externaldnsendpoint.NewEndpoint("udp.example.com", externaldnsendpoint.RecordTypeA, addressTargets(40)...),
+			externaldnsendpoint.NewEndpoint("edns.example.com", externaldnsendpoint.RecordTypeA, addressTargets(80)...),
+		}, nil)
+		Expect(warned).To(ConsistOf(ContainSubstring("A udp.example.com")))
+		Expect(rejected).To(ConsistOf(ContainSubstring("A edns.example.com")))
+	})
+
+	It("should report RRsets with more targets than the provider allows", func() {
+		rejected, warned := oversizedRRsets([]*externaldnsendpoint.Endpoint{
+			externaldnsendpoint.NewEndpoint("foo.example.com", externaldnsendpoint.RecordTypeA, addressTargets(3)...),
+		}, &maxTargetsProvider{maxTargets: 2})
+		Expect(warned).To(BeEmpty())
+		Expect(rejected).To(ConsistOf(ContainSubstring("has 3 targets, the provider allows 2")))
+	})
+
+	It("should simulate the RRsets in the zone once the changes are applied", func() {
+		desired := []*externaldnsendpoint.Endpoint{
+			externaldnsendpoint.NewEndpoint("foo.example.com", externaldnsendpoint.RecordTypeA, "1.1.1.1"),
+			externaldnsendpoint.NewEndpoint("bar.example.com", externaldnsendpoint.RecordTypeA, "1.1.1.1"),
+			externaldnsendpoint.NewEndpoint("baz.example.com", externaldnsendpoint.RecordTypeA, "1.1.1.1"),
+		}
+		zone := []*externaldnsendpoint.Endpoint{
+			externaldnsendpoint.NewEndpoint("bar.example.com", externaldnsendpoint.RecordTypeA, "1.1.1.1", "2.2.2.2"),
+			externaldnsendpoint.NewEndpoint("baz.example.com", externaldnsendpoint.RecordTypeA, "1.1.1.1", "3.3.3.3"),
+			externaldnsendpoint.NewEndpoint("other.example.com", externaldnsendpoint.RecordTypeA, "4.4.4.4"),
+		}
+		changes := &externaldnsplan.Changes{
+			UpdateNew: []*externaldnsendpoint.Endpoint{
+				externaldnsendpoint.NewEndpoint("baz.example.com", externaldnsendpoint.RecordTypeA, "1.1.1.1", "3.3.3.3", "5.5.5.5"),
+			},
+		}
+		rrsets := resultingRRsets(desired, zone, changes)
+		Expect(rrsets).To(HaveLen(3))
+		Expect(rrsets[0]).To(Equal(desired[0]))
+		Expect(rrsets[1]).To(Equal(zone[0]))
+		Expect(rrsets[2]).To(Equal(changes.UpdateNew[0]))
+	})
+
+	It("should warn or reject according to the policy", func() {
+		record := &v1alpha1.DNSRecord{}
+		rrsets := []*externaldnsendpoint.Endpoint{
+			externaldnsendpoint.NewEndpoint("foo.example.com", externaldnsendpoint.RecordTypeA, addressTargets(80)...),
+		}
+
+		r := &DNSRecordReconciler{RRsetSizePolicy: RRsetSizePolicyWarn}
+		Expect(r.checkRRsetSizes(record, nil, rrsets)).To(Succeed())
+		cond := meta.FindStatusCondition(record.Status.Conditions, string(v1alpha1.ConditionTypeOversizedRRsets))
+		Expect(cond).NotTo(BeNil())
+		Expect(cond.Reason).To(Equal(string(v1alpha1.ConditionReasonExceedsLimits)))
+
+		r.RRsetSizePolicy = RRsetSizePolicyReject
+		Expect(r.checkRRsetSizes(record, nil, rrsets)).To(MatchError(errOversizedRRsets))
+		Expect(meta.FindStatusCondition(record.Status.Conditions, string(v1alpha1.ConditionTypeOversizedRRsets))).To(BeNil())
+
+		r.RRsetSizePolicy = RRsetSizePolicyNone
+		Expect(r.checkRRsetSizes(record, nil, rrsets)).To(Succeed())
+		Expect(record.Status.Conditions).To(BeEmpty())
+	})
+})
@@ -0,0 +1,67 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kuadrant/dns-operator/api/v1alpha1"
+	"github.com/kuadrant/dns-operator/internal/common/hash"
+	externaldnsplan "github.com/kuadrant/dns-operator/internal/external-dns/plan"
+)
+
+// errTakeoverPending is returned by applyChanges for a record with changes that take over endpoints of other owners,
+// when takeovers must be confirmed.
+var errTakeoverPending = errors.New("changes take over endpoints of other owners")
+
+// takeoverToken returns the token confirming the given takeovers. The token changes with the takeovers, so a
+// confirmation never applies to takeovers that were not reviewed.
+func takeoverToken(takeovers []externaldnsplan.Takeover) string {
+	descriptions := describeTakeovers(takeovers)
+	slices.Sort(descriptions)
+	return hash.ToBase36HashLen(strings.Join(descriptions, "\n"), 8)
+}
+
+func describeTakeovers(takeovers []externaldnsplan.Takeover) []string {
+	descriptions := make([]string, 0, len(takeovers))
+	for _, takeover := range takeovers {
+		descriptions = append(descriptions, takeover.String())
+	}
+	return descriptions
+}
+
+// isTakeoverConfirmed returns true if the record has the annotation confirming a takeover.
+func isTakeoverConfirmed(dnsRecord *v1alpha1.DNSRecord) bool {
+	_, ok := dnsRecord.GetAnnotations()[v1alpha1.TakeoverConfirmedAnnotation]
+	return ok
+}
+
+// checkTakeovers holds back the changes of the record when takeovers must be confirmed and they remove the targets or
+// ownership of other owners of shared endpoints, e.g. when the registry of the zone is repaired. The TakeoverPending
+// condition is set with the takeovers and the token that confirms them, and an error is returned until the record is
+// annotated with the token.
+func (r *DNSRecordReconciler) checkTakeovers(dnsRecord *v1alpha1.DNSRecord, takeovers []externaldnsplan.Takeover) error {
+	if !r.TakeoverProtection || len(takeovers) == 0 {
+		meta.RemoveStatusCondition(&dnsRecord.Status.Conditions, string(v1alpha1.ConditionTypeTakeoverPending))
+		return nil
+	}
+	token := takeoverToken(takeovers)
+	if dnsRecord.GetAnnotations()[v1alpha1.TakeoverConfirmedAnnotation] == token {
+		meta.RemoveStatusCondition(&dnsRecord.Status.Conditions, string(v1alpha1.ConditionTypeTakeoverPending))
+		r.recorder.Eventf(dnsRecord, v1.EventTypeNormal, "TakeoverConfirmed", "Taking over %d endpoints as confirmed with %s",
+			len(takeovers), v1alpha1.TakeoverConfirmedAnnotation)
+		return nil
+	}
+	descriptions := strings.Join(describeTakeovers(takeovers), ", ")
+	setDNSRecordCondition(dnsRecord, string(v1alpha1.ConditionTypeTakeoverPending), metav1.ConditionTrue,
+		string(v1alpha1.ConditionReasonOtherOwnersAffected), fmt.Sprintf("Annotate the record with %s=%s to publish: %s",
+			v1alpha1.TakeoverConfirmedAnnotation, token, descriptions))
+	r.recorder.Eventf(dnsRecord, v1.EventTypeWarning, "TakeoverPending", "Changes held back until confirmed with %s=%s: %s",
+		v1alpha1.TakeoverConfirmedAnnotation, token, descriptions)
+	return fmt.Errorf("%w: %s", errTakeoverPending, descriptions)
+}
@@ -0,0 +1,69 @@
+//go:build integration
+
+package controller
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/kuadrant/dns-operator/api/v1alpha1"
+	externaldnsplan "github.com/kuadrant/dns-operator/internal/external-dns/plan"
+)
+
+var _ = Describe("Takeover protection", func() {
+	var (
+		fake      *record.FakeRecorder
+		r         *DNSRecordReconciler
+		dnsRecord *v1alpha1.DNSRecord
+		takeovers []externaldnsplan.Takeover
+	)
+
+	BeforeEach(func() {
+		fake = record.NewFakeRecorder(10)
+		r = &DNSRecordReconciler{TakeoverProtection: true, recorder: fake}
+		dnsRecord = &v1alpha1.DNSRecord{ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "dnstest"}}
+		takeovers = []externaldnsplan.Takeover{
+			{DNSName: "foo.example.com", RecordType: "AAAA", Owners: []string{"owner2"}, RemovedTargets: []string{"::1"}},
+		}
+	})
+
+	It("should derive the token from the takeovers", func() {
+		token := takeoverToken(takeovers)
+		Expect(token).To(HaveLen(8))
+		Expect(takeoverToken(takeovers)).To(Equal(token))
+
+		other := append(takeovers, externaldnsplan.Takeover{DNSName: "bar.example.com", RecordType: "TXT",
+			Owners: []string{"owner3"}, RemovedTargets: []string{"baz"}})
+		Expect(takeoverToken(other)).NotTo(Equal(token))
+	})
+
+	It("should hold back takeovers until confirmed with the token", func() {
+		Expect(r.checkTakeovers(dnsRecord, takeovers)).To(MatchError(errTakeoverPending))
+		cond := meta.FindStatusCondition(dnsRecord.Status.Conditions, string(v1alpha1.ConditionTypeTakeoverPending))
+		Expect(cond).NotTo(BeNil())
+		Expect(cond.Reason).To(Equal(string(v1alpha1.ConditionReasonOtherOwnersAffected)))
+		Expect(cond.Message).To(ContainSubstring(v1alpha1.TakeoverConfirmedAnnotation + "=" + takeoverToken(takeovers)))
+		Expect(cond.Message).To(ContainSubstring("AAAA foo.example.com owned by owner2 removes targets ::1"))
+		Expect(fake.Events).To(Receive(HavePrefix("Warning TakeoverPending")))
+
+		// a token of other takeovers doesn't confirm them
+		dnsRecord.SetAnnotations(map[string]string{v1alpha1.TakeoverConfirmedAnnotation: "stale"})
+		Expect(r.checkTakeovers(dnsRecord, takeovers)).To(MatchError(errTakeoverPending))
+
+		dnsRecord.SetAnnotations(map[string]string{v1alpha1.TakeoverConfirmedAnnotation: takeoverToken(takeovers)})
+		Expect(r.checkTakeovers(dnsRecord, takeovers)).To(Succeed())
+		Expect(meta.FindStatusCondition(dnsRecord.Status.Conditions, string(v1alpha1.ConditionTypeTakeoverPending))).To(BeNil())
+	})
+
+	It("should publish changes without takeovers or protection", func() {
+		Expect(r.checkTakeovers(dnsRecord, nil)).To(Succeed())
+
+		r.TakeoverProtection = false
+		Expect(r.checkTakeovers(dnsRecord, takeovers)).To(Succeed())
+		Expect(meta.FindStatusCondition(dnsRecord.Status.Conditions, string(v1alpha1.ConditionTypeTakeoverPending))).To(BeNil())
+	})
+})
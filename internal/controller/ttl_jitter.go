@@ -0,0 +1,33 @@
+package controller
+
+import (
+	"hash/fnv"
+
+	externaldnsendpoint "sigs.k8s.io/external-dns/endpoint"
+)
+
+// jitterTTLs returns the endpoints with their TTL shifted by up to the given percentage of the TTL in either direction.
+// The shift of an endpoint is derived from its name and type, so it is the same on every reconcile, for every record
+// publishing the endpoint and for all the endpoints of a routing group, e.g. weighted or geo endpoints, that providers
+// publish with a single TTL. Endpoints without a TTL keep the default TTL of the provider.
+func jitterTTLs(endpoints []*externaldnsendpoint.Endpoint, percent int) []*externaldnsendpoint.Endpoint {
+	if percent <= 0 {
+		return endpoints
+	}
+	jittered := make([]*externaldnsendpoint.Endpoint, 0, len(endpoints))
+	for _, ep := range endpoints {
+		maxShift := int64(ep.RecordTTL) * int64(percent) / 100
+		if !ep.RecordTTL.IsConfigured() || maxShift == 0 {
+			jittered = append(jittered, ep)
+			continue
+		}
+		h := fnv.New64a()
+		_, _ = h.Write([]byte(ep.DNSName + "/" + ep.RecordType))
+		shift := int64(h.Sum64()%uint64(2*maxShift+1)) - maxShift
+
+		ep = ep.DeepCopy()
+		ep.RecordTTL = externaldnsendpoint.TTL(max(int64(ep.RecordTTL)+shift, 1))
+		jittered = append(jittered, ep)
+	}
+	return jittered
+}
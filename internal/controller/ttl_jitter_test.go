@@ -0,0 +1,51 @@
+//go:build integration
+
+package controller
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	externaldnsendpoint "sigs.k8s.io/external-dns/endpoint"
+)
+
+var _ = Describe("TTL jitter", func() {
+	var endpoints []*externaldnsendpoint.Endpoint
+
+	BeforeEach(func() {
+		endpoints = []*externaldnsendpoint.Endpoint{
+			externaldnsendpoint.NewEndpointWithTTL("foo.example.com", externaldnsendpoint.RecordTypeA, 300, "1.1.1.1"),
+			externaldnsendpoint.NewEndpointWithTTL("bar.example.com", externaldnsendpoint.RecordTypeA, 300, "1.1.1.1"),
+			externaldnsendpoint.NewEndpoint("baz.example.com", externaldnsendpoint.RecordTypeA, "1.1.1.1"),
+		}
+	})
+
+	It("should shift the TTLs within the jitter without changing the endpoints", func() {
+		jittered := jitterTTLs(endpoints, 10)
+		Expect(jittered).To(HaveLen(3))
+		for _, ep := range jittered[:2] {
+			Expect(int64(ep.RecordTTL)).To(BeNumerically("~", 300, 30))
+		}
+		Expect(jittered[2].RecordTTL.IsConfigured()).To(BeFalse())
+		Expect(endpoints[0].RecordTTL).To(Equal(externaldnsendpoint.TTL(300)))
+	})
+
+	It("should shift the TTL of an endpoint the same way every time", func() {
+		Expect(jitterTTLs(endpoints, 10)).To(Equal(jitterTTLs(endpoints, 10)))
+	})
+
+	It("should shift the TTLs of the endpoints of a routing group the same way", func() {
+		weighted := []*externaldnsendpoint.Endpoint{
+			externaldnsendpoint.NewEndpointWithTTL("foo.example.com", externaldnsendpoint.RecordTypeCNAME, 300, "lb-1.example.net").
+				WithSetIdentifier("lb-1").WithProviderSpecific("weight", "100"),
+			externaldnsendpoint.NewEndpointWithTTL("foo.example.com", externaldnsendpoint.RecordTypeCNAME, 300, "lb-2.example.net").
+				WithSetIdentifier("lb-2").WithProviderSpecific("weight", "200"),
+		}
+		jittered := jitterTTLs(weighted, 10)
+		Expect(jittered[0].RecordTTL).To(Equal(jittered[1].RecordTTL))
+	})
+
+	It("should not shift the TTLs without jitter", func() {
+		Expect(jitterTTLs(endpoints, 0)).To(Equal(endpoints))
+	})
+})
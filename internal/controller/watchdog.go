@@ -0,0 +1,58 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"runtime/pprof"
+	"time"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/kuadrant/dns-operator/internal/metrics"
+)
+
+// maxStackSampleSize is the maximum size of the goroutine stacks logged for a reconcile that exceeds the timeout
+const maxStackSampleSize = 64 * 1024
+
+// withWatchdog runs the given reconcile with a context bounded to the timeout. If the reconcile is still running at
+// the timeout, the stacks of the running goroutines are logged and the timeout metric is incremented, so a reconcile
+// hanging on a wedged provider connection is visible. The context is cancelled at the timeout, aborting provider calls
+// in progress, and a reconcile that returns after the timeout is failed so it is requeued with backoff.
+func withWatchdog(ctx context.Context, timeout time.Duration, reconcile func(ctx context.Context) (ctrl.Result, error)) (ctrl.Result, error) {
+	if timeout <= 0 {
+		return reconcile(ctx)
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	watchdog := time.AfterFunc(timeout, func() {
+		metrics.ReconcileTimeouts.Inc()
+		log.FromContext(ctx).Error(context.DeadlineExceeded, fmt.Sprintf("Reconcile did not complete within %s", timeout),
+			"goroutines", stackSample())
+	})
+	defer watchdog.Stop()
+
+	result, err := reconcile(ctx)
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		if err == nil {
+			err = ctx.Err()
+		}
+		return ctrl.Result{}, fmt.Errorf("reconcile did not complete within %s: %w", timeout, err)
+	}
+	return result, err
+}
+
+// stackSample returns the stacks of the running goroutines, goroutines with the same stack are listed once.
+func stackSample() string {
+	var buf bytes.Buffer
+	if err := pprof.Lookup("goroutine").WriteTo(&buf, 1); err != nil {
+		return err.Error()
+	}
+	if buf.Len() > maxStackSampleSize {
+		buf.Truncate(maxStackSampleSize)
+	}
+	return buf.String()
+}
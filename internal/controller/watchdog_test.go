@@ -0,0 +1,50 @@
+//go:build integration
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/kuadrant/dns-operator/internal/metrics"
+)
+
+var _ = Describe("Reconcile watchdog", func() {
+	It("should return the result of a reconcile within the timeout", func() {
+		result, err := withWatchdog(ctx, time.Second, func(_ context.Context) (ctrl.Result, error) {
+			return ctrl.Result{RequeueAfter: time.Minute}, nil
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(time.Minute))
+	})
+
+	It("should abort and fail a reconcile exceeding the timeout", func() {
+		timeouts := testutil.ToFloat64(metrics.ReconcileTimeouts)
+		result, err := withWatchdog(ctx, 50*time.Millisecond, func(ctx context.Context) (ctrl.Result, error) {
+			// a provider call bounded by the reconcile context
+			<-ctx.Done()
+			return ctrl.Result{RequeueAfter: time.Minute}, nil
+		})
+		Expect(err).To(MatchError(ContainSubstring("reconcile did not complete within 50ms")))
+		Expect(err).To(MatchError(context.DeadlineExceeded))
+		Expect(result).To(Equal(ctrl.Result{}))
+		Eventually(func() float64 {
+			return testutil.ToFloat64(metrics.ReconcileTimeouts)
+		}).Should(Equal(timeouts + 1))
+	})
+
+	It("should not bound reconciles without a timeout", func() {
+		_, err := withWatchdog(ctx, 0, func(ctx context.Context) (ctrl.Result, error) {
+			_, hasDeadline := ctx.Deadline()
+			Expect(hasDeadline).To(BeFalse())
+			return ctrl.Result{}, nil
+		})
+		Expect(err).ToNot(HaveOccurred())
+	})
+})
@@ -0,0 +1,45 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/kuadrant/dns-operator/api/v1alpha1"
+	"github.com/kuadrant/dns-operator/internal/common"
+	"github.com/kuadrant/dns-operator/internal/provider"
+)
+
+// deferChanges updates the status of a record with changes that were not applied as the write budget of its provider
+// secret is used up, and requeues the record for after the budget is renewed. The requeue is spread over a tenth of the
+// budget interval, so the records deferred in an interval don't all use up the next budget at once.
+func (r *DNSRecordReconciler) deferChanges(ctx context.Context, previous, dnsRecord *v1alpha1.DNSRecord, budgetErr *provider.WriteBudgetExceededError) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+	requeueIn := budgetErr.RetryAfter + common.RandomizeValidationDuration(validationRequeueVariance, budgetErr.Interval/10)
+	logger.Info("Deferring changes until the write budget of the provider secret is renewed", "requeueIn", requeueIn.String())
+
+	// nothing was published, the endpoints published last are still those of the last reconcile
+	dnsRecord.Status.Endpoints = previous.Status.Endpoints
+	dnsRecord.Status.DomainOwners = previous.Status.DomainOwners
+	dnsRecord.Status.EndpointStatuses = previous.Status.EndpointStatuses
+	dnsRecord.Status.FlattenedEndpoints = previous.Status.FlattenedEndpoints
+	setDNSRecordCondition(dnsRecord, string(v1alpha1.ConditionTypeBudgetExceeded), metav1.ConditionTrue,
+		string(v1alpha1.ConditionReasonWriteBudgetExhausted), fmt.Sprintf("Changes are deferred until the write budget of the provider secret is renewed: %v", budgetErr))
+	r.recorder.Eventf(dnsRecord, v1.EventTypeWarning, "BudgetExceeded", "Changes deferred: %v", budgetErr)
+
+	if !equality.Semantic.DeepEqual(previous.Status, dnsRecord.Status) {
+		if err := r.Status().Update(ctx, dnsRecord); err != nil {
+			if apierrors.IsConflict(err) {
+				return ctrl.Result{Requeue: true}, nil
+			}
+			return ctrl.Result{}, err
+		}
+	}
+	return ctrl.Result{RequeueAfter: requeueIn}, nil
+}
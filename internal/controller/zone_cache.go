@@ -0,0 +1,94 @@
+package controller
+
+import (
+	"sync"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// negativeZoneCache remembers failed zone lookups per provider secret and root host, so that records with no zone
+// available are not looking up zones in the provider on every reconcile. The time before the next lookup doubles with
+// each consecutive failure up to maxDelay.
+type negativeZoneCache struct {
+	lock      sync.Mutex
+	entries   map[client.ObjectKey]map[string]*negativeZoneEntry
+	baseDelay time.Duration
+	maxDelay  time.Duration
+	now       func() time.Time
+}
+
+type negativeZoneEntry struct {
+	err      error
+	failures int
+	retryAt  time.Time
+	// token is the value of the zone lookup annotation when the lookup failed, changing it invalidates the entry
+	token string
+}
+
+func newNegativeZoneCache(baseDelay, maxDelay time.Duration) *negativeZoneCache {
+	return &negativeZoneCache{
+		entries:   map[client.ObjectKey]map[string]*negativeZoneEntry{},
+		baseDelay: baseDelay,
+		maxDelay:  maxDelay,
+		now:       time.Now,
+	}
+}
+
+// get returns the error of the last failed lookup and the time until the next lookup is allowed, if the lookup for the
+// given secret and host should be skipped.
+func (c *negativeZoneCache) get(secret client.ObjectKey, host, token string) (error, time.Duration, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	entry, ok := c.entries[secret][host]
+	if !ok || entry.token != token {
+		return nil, 0, false
+	}
+	retryIn := entry.retryAt.Sub(c.now())
+	if retryIn <= 0 {
+		return nil, 0, false
+	}
+	return entry.err, retryIn, true
+}
+
+// add records a failed lookup for the given secret and host.
+func (c *negativeZoneCache) add(secret client.ObjectKey, host, token string, err error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if _, ok := c.entries[secret]; !ok {
+		c.entries[secret] = map[string]*negativeZoneEntry{}
+	}
+	entry, ok := c.entries[secret][host]
+	if !ok || entry.token != token {
+		entry = &negativeZoneEntry{token: token}
+		c.entries[secret][host] = entry
+	}
+	entry.failures++
+	entry.err = err
+
+	delay := c.baseDelay
+	for i := 1; i < entry.failures && delay < c.maxDelay; i++ {
+		delay *= 2
+	}
+	if delay > c.maxDelay {
+		delay = c.maxDelay
+	}
+	entry.retryAt = c.now().Add(delay)
+}
+
+// remove forgets any failed lookup for the given secret and host.
+func (c *negativeZoneCache) remove(secret client.ObjectKey, host string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	delete(c.entries[secret], host)
+	if len(c.entries[secret]) == 0 {
+		delete(c.entries, secret)
+	}
+}
+
+// invalidate forgets all failed lookups for the given secret.
+func (c *negativeZoneCache) invalidate(secret client.ObjectKey) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	delete(c.entries, secret)
+}
@@ -0,0 +1,82 @@
+//go:build integration
+
+package controller
+
+import (
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("Negative zone cache", func() {
+	secret := client.ObjectKey{Namespace: "default", Name: "provider"}
+	errNoZone := errors.New("no zone for host")
+
+	var now time.Time
+	var cache *negativeZoneCache
+
+	BeforeEach(func() {
+		now = time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+		cache = newNegativeZoneCache(time.Second, 8*time.Second)
+		cache.now = func() time.Time { return now }
+	})
+
+	DescribeTable("should double the time before the next lookup with each failure up to the maximum",
+		func(failures int, expected time.Duration) {
+			for i := 0; i < failures; i++ {
+				cache.add(secret, "foo.example.com", "", errNoZone)
+			}
+			err, retryIn, skip := cache.get(secret, "foo.example.com", "")
+			Expect(skip).To(BeTrue())
+			Expect(err).To(Equal(errNoZone))
+			Expect(retryIn).To(Equal(expected))
+		},
+		Entry("first failure", 1, time.Second),
+		Entry("second failure", 2, 2*time.Second),
+		Entry("third failure", 3, 4*time.Second),
+		Entry("at the maximum", 4, 8*time.Second),
+		Entry("past the maximum", 10, 8*time.Second),
+	)
+
+	DescribeTable("should skip lookups until the entry expires or is invalidated",
+		func(elapsed time.Duration, token string, expire func(*negativeZoneCache), expected bool) {
+			cache.add(secret, "foo.example.com", "1", errNoZone)
+			cache.add(secret, "foo.example.com", "1", errNoZone)
+			if expire != nil {
+				expire(cache)
+			}
+			now = now.Add(elapsed)
+			_, retryIn, skip := cache.get(secret, "foo.example.com", token)
+			Expect(skip).To(Equal(expected))
+			if expected {
+				Expect(retryIn).To(Equal(2*time.Second - elapsed))
+			} else {
+				Expect(retryIn).To(BeZero())
+			}
+		},
+		Entry("before the retry time", time.Second, "1", nil, true),
+		Entry("at the retry time", 2*time.Second, "1", nil, false),
+		Entry("after the retry time", 3*time.Second, "1", nil, false),
+		Entry("with another lookup token", time.Duration(0), "2", nil, false),
+		Entry("removed for the host", time.Duration(0), "1", func(c *negativeZoneCache) { c.remove(secret, "foo.example.com") }, false),
+		Entry("invalidated for the secret", time.Duration(0), "1", func(c *negativeZoneCache) { c.invalidate(secret) }, false),
+		Entry("removed for another host", time.Duration(0), "1", func(c *negativeZoneCache) { c.remove(secret, "bar.example.com") }, true),
+		Entry("invalidated for another secret", time.Duration(0), "1", func(c *negativeZoneCache) {
+			c.invalidate(client.ObjectKey{Namespace: "default", Name: "other"})
+		}, true),
+	)
+
+	It("should start the backoff again for failures with another lookup token", func() {
+		for i := 0; i < 3; i++ {
+			cache.add(secret, "foo.example.com", "1", errNoZone)
+		}
+		cache.add(secret, "foo.example.com", "2", errNoZone)
+		_, retryIn, skip := cache.get(secret, "foo.example.com", "2")
+		Expect(skip).To(BeTrue())
+		Expect(retryIn).To(Equal(time.Second))
+	})
+})
@@ -0,0 +1,196 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"net"
+	"slices"
+	"strings"
+	"time"
+
+	"golang.org/x/net/dns/dnsmessage"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	externaldnsendpoint "sigs.k8s.io/external-dns/endpoint"
+
+	"github.com/kuadrant/dns-operator/api/v1alpha1"
+	"github.com/kuadrant/dns-operator/internal/provider"
+)
+
+// zoneDelegationTimeout is the time allowed for looking up the delegation of a zone from its parent zone.
+const zoneDelegationTimeout = 10 * time.Second
+
+// zoneNameservers returns the nameservers of the zone of the given record, from the NS records at the zone apex.
+func zoneNameservers(ctx context.Context, dnsRecord *v1alpha1.DNSRecord, dnsProvider provider.Provider) ([]string, error) {
+	records, err := dnsProvider.Records(ctx)
+	if err != nil {
+		return nil, err
+	}
+	zoneName := strings.TrimSuffix(dnsRecord.Status.ZoneDomainName, ".")
+	var nameservers []string
+	for _, record := range records {
+		if record.RecordType == externaldnsendpoint.RecordTypeNS && strings.EqualFold(strings.TrimSuffix(record.DNSName, "."), zoneName) {
+			nameservers = append(nameservers, record.Targets...)
+		}
+	}
+	if len(nameservers) == 0 {
+		return nil, fmt.Errorf("no nameservers found for zone %s", zoneName)
+	}
+	return nameservers, nil
+}
+
+// parentNameservers returns the nameservers of the closest ancestor of the given zone that has nameservers.
+func parentNameservers(ctx context.Context, zoneName string) ([]string, error) {
+	for name := zoneName; ; {
+		_, parent, found := strings.Cut(name, ".")
+		if !found || parent == "" {
+			return nil, fmt.Errorf("no parent zone found for zone %s", zoneName)
+		}
+		if nss, err := net.DefaultResolver.LookupNS(ctx, parent); err == nil && len(nss) > 0 {
+			nameservers := make([]string, 0, len(nss))
+			for _, ns := range nss {
+				nameservers = append(nameservers, ns.Host)
+			}
+			return nameservers, nil
+		}
+		name = parent
+	}
+}
+
+// delegatedNameservers returns the nameservers the given nameserver of the parent zone delegates the zone to. The
+// referral is read from the authority section of the response, which the resolver of the net package does not expose.
+func delegatedNameservers(ctx context.Context, parentNameserver, zoneName string) ([]string, error) {
+	name, err := dnsmessage.NewName(strings.TrimSuffix(zoneName, ".") + ".")
+	if err != nil {
+		return nil, err
+	}
+	query := dnsmessage.Message{
+		Header:    dnsmessage.Header{ID: uint16(rand.Uint32())},
+		Questions: []dnsmessage.Question{{Name: name, Type: dnsmessage.TypeNS, Class: dnsmessage.ClassINET}},
+	}
+	packed, err := query.Pack()
+	if err != nil {
+		return nil, err
+	}
+
+	d := net.Dialer{}
+	conn, err := d.DialContext(ctx, "udp", net.JoinHostPort(strings.TrimSuffix(parentNameserver, "."), "53"))
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		if err = conn.SetDeadline(deadline); err != nil {
+			return nil, err
+		}
+	}
+	if _, err = conn.Write(packed); err != nil {
+		return nil, err
+	}
+	buf := make([]byte, 4096)
+	n, err := conn.Read(buf)
+	if err != nil {
+		return nil, err
+	}
+
+	var response dnsmessage.Message
+	if err = response.Unpack(buf[:n]); err != nil {
+		return nil, err
+	}
+	if response.Header.ID != query.Header.ID {
+		return nil, fmt.Errorf("unexpected response id from %s", parentNameserver)
+	}
+	switch response.Header.RCode {
+	case dnsmessage.RCodeSuccess:
+	case dnsmessage.RCodeNameError:
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("querying %s for the nameservers of %s: %s", parentNameserver, zoneName, response.Header.RCode)
+	}
+
+	var nameservers []string
+	for _, rr := range append(response.Answers, response.Authorities...) {
+		ns, ok := rr.Body.(*dnsmessage.NSResource)
+		if !ok || !strings.EqualFold(rr.Header.Name.String(), name.String()) {
+			continue
+		}
+		nameservers = append(nameservers, ns.NS.String())
+	}
+	return nameservers, nil
+}
+
+// brokenDelegation returns the reason and a description of why the delegation of a zone is broken, or empty strings
+// if the parent zone only delegates to nameservers of the zone.
+func brokenDelegation(zoneName string, zoneNameservers, delegated []string) (v1alpha1.ConditionReason, string) {
+	normalize := func(nameservers []string) []string {
+		normalized := make([]string, 0, len(nameservers))
+		for _, ns := range nameservers {
+			normalized = append(normalized, strings.ToLower(strings.TrimSuffix(ns, ".")))
+		}
+		slices.Sort(normalized)
+		return slices.Compact(normalized)
+	}
+	zoneNameservers, delegated = normalize(zoneNameservers), normalize(delegated)
+
+	if len(delegated) == 0 {
+		return v1alpha1.ConditionReasonZoneNotDelegated,
+			fmt.Sprintf("The parent zone does not delegate %s, records published to it are not resolvable", zoneName)
+	}
+	for _, ns := range delegated {
+		if !slices.Contains(zoneNameservers, ns) {
+			return v1alpha1.ConditionReasonZoneDelegationMismatch,
+				fmt.Sprintf("The parent zone delegates %s to nameservers %s, the nameservers of the zone are %s",
+					zoneName, strings.Join(delegated, ", "), strings.Join(zoneNameservers, ", "))
+		}
+	}
+	return "", ""
+}
+
+// checkZoneDelegation sets the ZoneDelegationBroken condition on records whose zone is not delegated to its
+// nameservers by the parent zone, so users learn the zone is not resolvable. The delegation is checked until the
+// record is first published, and after that only while the condition is set so it is removed once fixed. Records in
+// private zones are not checked, as their zones are never delegated.
+func (r *DNSRecordReconciler) checkZoneDelegation(ctx context.Context, dnsRecord *v1alpha1.DNSRecord, dnsProvider provider.Provider) {
+	if !r.CheckZoneDelegation || dnsRecord.Status.ZoneVisibility == v1alpha1.ZoneVisibilityPrivate {
+		return
+	}
+	if len(dnsRecord.Status.Endpoints) > 0 &&
+		meta.FindStatusCondition(dnsRecord.Status.Conditions, string(v1alpha1.ConditionTypeZoneDelegationBroken)) == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, zoneDelegationTimeout)
+	defer cancel()
+	logger := log.FromContext(ctx)
+	zoneName := strings.TrimSuffix(dnsRecord.Status.ZoneDomainName, ".")
+
+	nameservers, err := zoneNameservers(ctx, dnsRecord, dnsProvider)
+	if err != nil {
+		logger.Error(err, "Failed to check zone delegation")
+		return
+	}
+	parents, err := parentNameservers(ctx, zoneName)
+	if err != nil {
+		logger.Error(err, "Failed to check zone delegation")
+		return
+	}
+	var delegated []string
+	for _, parent := range parents {
+		if delegated, err = delegatedNameservers(ctx, parent, zoneName); err == nil {
+			break
+		}
+	}
+	if err != nil {
+		logger.Error(err, "Failed to check zone delegation")
+		return
+	}
+
+	reason, message := brokenDelegation(zoneName, nameservers, delegated)
+	if reason == "" {
+		meta.RemoveStatusCondition(&dnsRecord.Status.Conditions, string(v1alpha1.ConditionTypeZoneDelegationBroken))
+		return
+	}
+	setDNSRecordCondition(dnsRecord, string(v1alpha1.ConditionTypeZoneDelegationBroken), metav1.ConditionTrue, string(reason), message)
+}
@@ -0,0 +1,33 @@
+//go:build integration
+
+package controller
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/kuadrant/dns-operator/api/v1alpha1"
+)
+
+var _ = Describe("Zone delegation", func() {
+	nameservers := []string{"ns-1.example.net.", "ns-2.example.net."}
+
+	It("should accept a delegation to the nameservers of the zone", func() {
+		reason, _ := brokenDelegation("example.com", nameservers, []string{"NS-2.example.net", "ns-1.example.net."})
+		Expect(reason).To(BeEmpty())
+		reason, _ = brokenDelegation("example.com", nameservers, []string{"ns-1.example.net."})
+		Expect(reason).To(BeEmpty())
+	})
+
+	It("should report a zone that is not delegated", func() {
+		reason, message := brokenDelegation("example.com", nameservers, nil)
+		Expect(reason).To(Equal(v1alpha1.ConditionReasonZoneNotDelegated))
+		Expect(message).To(ContainSubstring("does not delegate example.com"))
+	})
+
+	It("should report a zone delegated to other nameservers", func() {
+		reason, message := brokenDelegation("example.com", nameservers, []string{"ns-1.example.net.", "ns-1.old-provider.net."})
+		Expect(reason).To(Equal(v1alpha1.ConditionReasonZoneDelegationMismatch))
+		Expect(message).To(ContainSubstring("ns-1.old-provider.net"))
+	})
+})
@@ -0,0 +1,118 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	externaldnsendpoint "sigs.k8s.io/external-dns/endpoint"
+	externaldnsplan "sigs.k8s.io/external-dns/plan"
+
+	"github.com/kuadrant/dns-operator/api/v1alpha1"
+	"github.com/kuadrant/dns-operator/internal/provider"
+)
+
+const (
+	// ZoneStateRecordLabel is the label of the state TXT record of a zone, e.g. _kuadrant-state.example.com for the
+	// example.com zone.
+	ZoneStateRecordLabel = "_kuadrant-state"
+	// zoneStateTTL is the TTL of the state record, short so monitors see changes soon after they are applied
+	zoneStateTTL = 60
+	// zoneStateAttempts is the number of times the state record is written before giving up when other owners write it
+	// at the same time
+	zoneStateAttempts = 3
+)
+
+// zoneStateName returns the name of the state record of the given zone.
+func zoneStateName(zone string) string {
+	return ZoneStateRecordLabel + "." + zone
+}
+
+// splitZoneState returns the state record of the given zone from the given endpoints, nil if there is none, and the
+// other endpoints. The state record is written by every owner publishing to the zone, so it is never planned.
+func splitZoneState(endpoints []*externaldnsendpoint.Endpoint, zone string) (*externaldnsendpoint.Endpoint, []*externaldnsendpoint.Endpoint) {
+	var state *externaldnsendpoint.Endpoint
+	remaining := make([]*externaldnsendpoint.Endpoint, 0, len(endpoints))
+	for _, ep := range endpoints {
+		if ep.RecordType == externaldnsendpoint.RecordTypeTXT && strings.EqualFold(ep.DNSName, zoneStateName(zone)) {
+			state = ep
+			continue
+		}
+		remaining = append(remaining, ep)
+	}
+	return state, remaining
+}
+
+// zoneStateSerial returns the serial of the given state record, 0 if there is no record or it has no valid serial.
+func zoneStateSerial(state *externaldnsendpoint.Endpoint) uint64 {
+	if state == nil {
+		return 0
+	}
+	for _, target := range state.Targets {
+		for _, field := range strings.Fields(strings.Trim(target, `"`)) {
+			if value, ok := strings.CutPrefix(field, "serial="); ok {
+				if serial, err := strconv.ParseUint(value, 10, 64); err == nil {
+					return serial
+				}
+			}
+		}
+	}
+	return 0
+}
+
+// zoneStateChanges returns the changes that replace the current state record of the given zone with a record of the
+// given serial and the given time as the time of the last change.
+func zoneStateChanges(zone string, current *externaldnsendpoint.Endpoint, serial uint64, now time.Time) *externaldnsplan.Changes {
+	state := externaldnsendpoint.NewEndpointWithTTL(zoneStateName(zone), externaldnsendpoint.RecordTypeTXT, zoneStateTTL,
+		fmt.Sprintf("serial=%d updated=%s", serial, now.UTC().Format(time.RFC3339)))
+	if current == nil {
+		return &externaldnsplan.Changes{Create: []*externaldnsendpoint.Endpoint{state}}
+	}
+	return &externaldnsplan.Changes{UpdateOld: []*externaldnsendpoint.Endpoint{current}, UpdateNew: []*externaldnsendpoint.Endpoint{state}}
+}
+
+// writeZoneState reads the state record of the given zone and writes it with the next serial. The record is read back
+// after the write and written again after the highest serial seen when it has a lower serial, as another owner that
+// read the record before the write overwrote it, so the serial never goes back.
+func writeZoneState(ctx context.Context, p provider.Provider, zone string, now time.Time) error {
+	var err error
+	var highest uint64
+	for attempt := 0; attempt < zoneStateAttempts; attempt++ {
+		var records []*externaldnsendpoint.Endpoint
+		if records, err = p.Records(ctx); err != nil {
+			return err
+		}
+		current, _ := splitZoneState(records, zone)
+		serial := max(zoneStateSerial(current), highest) + 1
+		highest = serial
+		if err = p.ApplyChanges(ctx, zoneStateChanges(zone, current, serial, now)); err != nil {
+			// the record was changed since it was read
+			continue
+		}
+		if records, err = p.Records(ctx); err != nil {
+			return err
+		}
+		written, _ := splitZoneState(records, zone)
+		if zoneStateSerial(written) >= serial {
+			return nil
+		}
+		err = fmt.Errorf("serial %d was overwritten with serial %d", serial, zoneStateSerial(written))
+	}
+	return err
+}
+
+// updateZoneState advances the state record of the zone of the record after changes were applied to the zone. The record
+// is read from the zone instead of the records cache, and is written without using up the write budget or the write
+// limit of the provider secret. The state record is best effort, failing to write it doesn't fail the reconcile.
+func (r *DNSRecordReconciler) updateZoneState(ctx context.Context, dnsRecord *v1alpha1.DNSRecord, dnsProvider provider.Provider) {
+	zone := dnsRecord.Status.ZoneDomainName
+	if err := writeZoneState(ctx, provider.Direct(dnsProvider), zone, reconcileStart.Time); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to update the zone state record", "name", zoneStateName(zone))
+		r.recorder.Eventf(dnsRecord, v1.EventTypeWarning, "ZoneStateError", "The state record %s of the zone could not be updated: %v",
+			zoneStateName(zone), provider.SanitizeError(err))
+	}
+}
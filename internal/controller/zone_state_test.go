@@ -0,0 +1,107 @@
+//go:build integration
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	externaldnsendpoint "sigs.k8s.io/external-dns/endpoint"
+	externaldnsplan "sigs.k8s.io/external-dns/plan"
+
+	"github.com/kuadrant/dns-operator/internal/external-dns/provider/inmemory"
+	"github.com/kuadrant/dns-operator/internal/provider"
+)
+
+var _ = Describe("Zone state record", func() {
+	It("should split the state record of the zone from the zone endpoints", func() {
+		state := externaldnsendpoint.NewEndpoint("_kuadrant-state.example.com", externaldnsendpoint.RecordTypeTXT, "serial=3 updated=2024-01-01T00:00:00Z")
+		other := externaldnsendpoint.NewEndpoint("_kuadrant-state.foo.example.com", externaldnsendpoint.RecordTypeTXT, "serial=1")
+		a := externaldnsendpoint.NewEndpoint("foo.example.com", externaldnsendpoint.RecordTypeA, "1.1.1.1")
+
+		found, remaining := splitZoneState([]*externaldnsendpoint.Endpoint{a, state, other}, "example.com")
+		Expect(found).To(Equal(state))
+		Expect(remaining).To(ConsistOf(a, other))
+		Expect(zoneStateSerial(found)).To(Equal(uint64(3)))
+
+		found, remaining = splitZoneState([]*externaldnsendpoint.Endpoint{a}, "example.com")
+		Expect(found).To(BeNil())
+		Expect(remaining).To(ConsistOf(a))
+		Expect(zoneStateSerial(found)).To(BeZero())
+	})
+
+	It("should start again from a state record without a valid serial", func() {
+		state := externaldnsendpoint.NewEndpoint("_kuadrant-state.example.com", externaldnsendpoint.RecordTypeTXT, `"serial=many"`)
+		Expect(zoneStateSerial(state)).To(BeZero())
+		changes := zoneStateChanges("example.com", state, zoneStateSerial(state)+1, time.Now())
+		Expect(changes.UpdateNew).To(HaveLen(1))
+		Expect(changes.UpdateNew[0].Targets[0]).To(HavePrefix("serial=1 "))
+	})
+
+	It("should increase the serial on every change", func() {
+		ctx := context.Background()
+		p := inmemory.NewInMemoryProvider(ctx)
+		Expect(p.CreateZone("example.com")).To(Succeed())
+
+		for serial, now := range []time.Time{
+			time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC),
+			time.Date(2024, 1, 1, 11, 0, 0, 0, time.UTC),
+		} {
+			Expect(writeZoneState(ctx, &zoneStateProvider{zone: p}, "example.com", now)).To(Succeed())
+
+			records, err := p.Records(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(records).To(HaveLen(1))
+			Expect(records[0].DNSName).To(Equal("_kuadrant-state.example.com"))
+			Expect(records[0].RecordTTL).To(Equal(externaldnsendpoint.TTL(zoneStateTTL)))
+			Expect(records[0].Targets).To(ConsistOf(
+				"serial=" + []string{"1", "2"}[serial] + " updated=" + now.Format(time.RFC3339)))
+		}
+	})
+
+	It("should write the state record again when an owner overwrites it with a lower serial", func() {
+		ctx := context.Background()
+		p := inmemory.NewInMemoryProvider(ctx)
+		Expect(p.CreateZone("example.com")).To(Succeed())
+		now := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+		Expect(p.ApplyChanges(ctx, zoneStateChanges("example.com", nil, 5, now))).To(Succeed())
+
+		Expect(writeZoneState(ctx, &zoneStateProvider{zone: p, staleSerial: 4}, "example.com", now)).To(Succeed())
+		records, err := p.Records(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		state, _ := splitZoneState(records, "example.com")
+		Expect(zoneStateSerial(state)).To(Equal(uint64(7)))
+	})
+})
+
+// zoneStateProvider is a provider of the records of an in memory zone that overwrites the first state record written
+// with a stale serial, as an owner that read the record before the write would.
+type zoneStateProvider struct {
+	provider.Provider
+	zone        *inmemory.InMemoryProvider
+	staleSerial uint64
+}
+
+func (p *zoneStateProvider) Records(ctx context.Context) ([]*externaldnsendpoint.Endpoint, error) {
+	return p.zone.Records(ctx)
+}
+
+func (p *zoneStateProvider) ApplyChanges(ctx context.Context, changes *externaldnsplan.Changes) error {
+	if err := p.zone.ApplyChanges(ctx, changes); err != nil {
+		return err
+	}
+	if p.staleSerial == 0 {
+		return nil
+	}
+	records, err := p.zone.Records(ctx)
+	if err != nil {
+		return err
+	}
+	current, _ := splitZoneState(records, "example.com")
+	stale := zoneStateChanges("example.com", current, p.staleSerial, time.Now())
+	p.staleSerial = 0
+	return p.zone.ApplyChanges(ctx, stale)
+}
@@ -0,0 +1,131 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	"github.com/kuadrant/dns-operator/api/v1alpha1"
+	"github.com/kuadrant/dns-operator/internal/provider"
+)
+
+// ZoneWarmup lists the zones of provider secrets once when the operator starts, so the zones are cached by the provider
+// factory before the first records are reconciled and secrets with credentials that can't list zones are reported
+// straight away. The secrets are those of the DefaultProviderRefAnnotation of watched namespaces and those with the
+// ZoneWarmupLabel set to "true".
+type ZoneWarmup struct {
+	Client                 client.Client
+	ProviderFactory        provider.Factory
+	Recorder               record.EventRecorder
+	WatchNamespaceSelector labels.Selector
+}
+
+var _ manager.Runnable = &ZoneWarmup{}
+var _ manager.LeaderElectionRunnable = &ZoneWarmup{}
+
+// NeedLeaderElection returns false, each replica has its own cache of zones to warm up.
+func (w *ZoneWarmup) NeedLeaderElection() bool {
+	return false
+}
+
+// Start lists the zones of the provider secrets and returns, failures are logged and recorded as events of the secret
+// and don't stop the operator.
+func (w *ZoneWarmup) Start(ctx context.Context) error {
+	logger := ctrl.Log.WithName("zone-warmup")
+	ctx = log.IntoContext(ctx, logger)
+
+	secrets, err := w.providerSecrets(ctx)
+	if err != nil {
+		logger.Error(err, "unable to find provider secrets to warm up")
+		return nil
+	}
+
+	var failed int
+	for _, secret := range secrets {
+		if err := w.warm(ctx, secret); err != nil {
+			failed++
+			logger.Error(err, "unable to list zones with provider secret", "secret", secret.String())
+		}
+	}
+	logger.Info("zone warm-up complete", "secrets", len(secrets), "failed", failed)
+	return nil
+}
+
+// warm lists the zones of the given provider secret and logs them.
+func (w *ZoneWarmup) warm(ctx context.Context, secret types.NamespacedName) error {
+	accessor := providerRefAccessor{namespace: secret.Namespace, providerRef: v1alpha1.ProviderRef{Name: secret.Name}}
+	p, err := w.ProviderFactory.ProviderFor(ctx, accessor, provider.Config{})
+	if err == nil {
+		var zones []provider.DNSZone
+		if zones, err = p.DNSZones(ctx); err == nil {
+			visible := make([]string, 0, len(zones))
+			for _, zone := range zones {
+				visible = append(visible, fmt.Sprintf("%s (%s)", zone.DNSName, zone.ID))
+			}
+			log.FromContext(ctx).Info("listed zones with provider secret", "secret", secret.String(),
+				"count", len(zones), "zones", strings.Join(visible, ", "))
+			return nil
+		}
+	}
+
+	if w.Recorder != nil {
+		obj := &v1.Secret{}
+		if getErr := w.Client.Get(ctx, secret, obj); getErr == nil {
+			w.Recorder.Eventf(obj, v1.EventTypeWarning, "ZoneListFailed", "Zones could not be listed with the provider secret: %v", err)
+		}
+	}
+	return err
+}
+
+// providerSecrets returns the provider secrets to warm up, sorted by namespace and name.
+func (w *ZoneWarmup) providerSecrets(ctx context.Context) ([]types.NamespacedName, error) {
+	found := map[types.NamespacedName]struct{}{}
+
+	namespaces := &v1.NamespaceList{}
+	if err := w.Client.List(ctx, namespaces); err != nil {
+		return nil, err
+	}
+	for _, ns := range namespaces.Items {
+		name := ns.GetAnnotations()[v1alpha1.DefaultProviderRefAnnotation]
+		if name == "" {
+			continue
+		}
+		if w.WatchNamespaceSelector != nil && !w.WatchNamespaceSelector.Matches(labels.Set(ns.Labels)) {
+			continue
+		}
+		found[types.NamespacedName{Namespace: ns.Name, Name: name}] = struct{}{}
+	}
+
+	labeled := &v1.SecretList{}
+	if err := w.Client.List(ctx, labeled, client.MatchingLabels{v1alpha1.ZoneWarmupLabel: "true"}); err != nil {
+		return nil, err
+	}
+	for _, secret := range labeled.Items {
+		watched, err := namespaceWatched(ctx, w.Client, w.WatchNamespaceSelector, secret.Namespace)
+		if err != nil {
+			return nil, err
+		}
+		if watched {
+			found[client.ObjectKeyFromObject(&secret)] = struct{}{}
+		}
+	}
+
+	secrets := make([]types.NamespacedName, 0, len(found))
+	for secret := range found {
+		secrets = append(secrets, secret)
+	}
+	sort.Slice(secrets, func(i, j int) bool {
+		return secrets[i].String() < secrets[j].String()
+	})
+	return secrets, nil
+}
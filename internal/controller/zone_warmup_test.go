@@ -0,0 +1,95 @@
+//go:build integration
+
+package controller
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kuadrant/dns-operator/api/v1alpha1"
+	"github.com/kuadrant/dns-operator/internal/provider"
+)
+
+var _ = Describe("Zone warm-up", func() {
+	var recorder *record.FakeRecorder
+
+	newWarmup := func(selector labels.Selector, objs ...client.Object) *ZoneWarmup {
+		c := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(objs...).Build()
+		f, err := provider.NewFactory(c, []string{"inmemory"}, provider.WithZonesCacheDuration(time.Minute))
+		Expect(err).NotTo(HaveOccurred())
+		return &ZoneWarmup{Client: c, ProviderFactory: f, Recorder: recorder, WatchNamespaceSelector: selector}
+	}
+
+	namespace := func(name string, watched bool, providerRef string) *v1.Namespace {
+		ns := &v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{}, Annotations: map[string]string{}}}
+		if watched {
+			ns.Labels["watched"] = "true"
+		}
+		if providerRef != "" {
+			ns.Annotations[v1alpha1.DefaultProviderRefAnnotation] = providerRef
+		}
+		return ns
+	}
+
+	secret := func(namespace, name string, secretType v1.SecretType, warmup bool) *v1.Secret {
+		s := &v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, Labels: map[string]string{}},
+			Type:       secretType,
+			Data:       map[string][]byte{v1alpha1.InmemInitZonesKey: []byte("warmup.example.com")},
+		}
+		if warmup {
+			s.Labels[v1alpha1.ZoneWarmupLabel] = "true"
+		}
+		return s
+	}
+
+	BeforeEach(func() {
+		recorder = record.NewFakeRecorder(10)
+	})
+
+	It("should find the default and labeled provider secrets of watched namespaces", func() {
+		selector := labels.SelectorFromSet(labels.Set{"watched": "true"})
+		w := newWarmup(selector,
+			namespace("a", true, "default-provider"),
+			namespace("b", true, ""),
+			namespace("c", false, "default-provider"),
+			secret("b", "labeled", v1alpha1.SecretTypeKuadrantInmemory, true),
+			secret("b", "unlabeled", v1alpha1.SecretTypeKuadrantInmemory, false),
+			secret("c", "labeled", v1alpha1.SecretTypeKuadrantInmemory, true),
+		)
+		Expect(w.providerSecrets(ctx)).To(Equal([]types.NamespacedName{
+			{Namespace: "a", Name: "default-provider"},
+			{Namespace: "b", Name: "labeled"},
+		}))
+	})
+
+	It("should list the zones of provider secrets without events", func() {
+		w := newWarmup(nil, secret("a", "inmemory", v1alpha1.SecretTypeKuadrantInmemory, true))
+		Expect(w.warm(ctx, types.NamespacedName{Namespace: "a", Name: "inmemory"})).To(Succeed())
+		Expect(w.Start(ctx)).To(Succeed())
+		Expect(recorder.Events).To(BeEmpty())
+	})
+
+	It("should record an event on provider secrets that can't list zones", func() {
+		w := newWarmup(nil,
+			namespace("a", true, "missing"),
+			secret("a", "opaque", v1.SecretTypeOpaque, true),
+		)
+		Expect(w.warm(ctx, types.NamespacedName{Namespace: "a", Name: "opaque"})).To(MatchError("provider type given is not supported"))
+		Expect(recorder.Events).To(Receive(Equal("Warning ZoneListFailed Zones could not be listed with the provider secret: provider type given is not supported")))
+
+		Expect(w.Start(ctx)).To(Succeed())
+		Expect(recorder.Events).To(HaveLen(1))
+	})
+})
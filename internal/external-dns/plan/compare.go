@@ -0,0 +1,65 @@
+package plan
+
+import (
+	"net/netip"
+	"slices"
+	"strings"
+
+	"sigs.k8s.io/external-dns/endpoint"
+
+	"github.com/kuadrant/dns-operator/api/v1alpha1"
+)
+
+// TargetNormalizer converts a target value of the given record type to a canonical form, so that target values that
+// are semantically equal but formatted differently by a provider (case, trailing dots, IPv6 compaction) compare equal
+// and don't generate changes.
+type TargetNormalizer func(recordType, target string) string
+
+// NormalizeTarget is the default TargetNormalizer.
+// IP addresses are converted to their canonical text form and hostnames are lower cased with any trailing dot removed.
+// The fields of NAPTR and TLSA targets are separated by single spaces, with the trailing dot removed from the NAPTR
+// replacement and the TLSA certificate association data joined and lower cased. All other target values are returned
+// with surrounding space removed.
+func NormalizeTarget(recordType, target string) string {
+	target = strings.TrimSpace(target)
+	switch recordType {
+	case endpoint.RecordTypeA, endpoint.RecordTypeAAAA:
+		if addr, err := netip.ParseAddr(target); err == nil {
+			return addr.String()
+		}
+	case endpoint.RecordTypeCNAME, endpoint.RecordTypeNS, endpoint.RecordTypeMX, endpoint.RecordTypeSRV, endpoint.RecordTypePTR:
+		return strings.TrimSuffix(strings.ToLower(target), ".")
+	case v1alpha1.RecordTypeNAPTR:
+		fields := strings.Fields(target)
+		// endpoints read from providers have the trailing dot of their targets removed, including a "." replacement
+		if strings.HasSuffix(target, `"`) {
+			fields = append(fields, ".")
+		}
+		if last := len(fields) - 1; last > 0 && fields[last] != "." {
+			fields[last] = strings.TrimSuffix(strings.ToLower(fields[last]), ".")
+		}
+		return strings.Join(fields, " ")
+	case v1alpha1.RecordTypeTLSA:
+		if fields := strings.Fields(target); len(fields) > 3 {
+			return strings.Join(append(fields[:3], strings.ToLower(strings.Join(fields[3:], ""))), " ")
+		}
+	}
+	return target
+}
+
+// normalizeTargets returns the sorted, de-duplicated, normalized form of the given targets.
+func normalizeTargets(recordType string, targets endpoint.Targets, normalize func(string) string) []string {
+	normalized := make([]string, 0, len(targets))
+	for _, t := range targets {
+		normalized = append(normalized, normalize(t))
+	}
+	slices.Sort(normalized)
+	return slices.Compact(normalized)
+}
+
+// sameTargets returns true if both lists contain the same targets after normalization.
+// As with endpoint.Targets.Same the comparison is not case-sensitive.
+func sameTargets(recordType string, a, b endpoint.Targets, normalize TargetNormalizer) bool {
+	lower := func(t string) string { return strings.ToLower(normalize(recordType, t)) }
+	return slices.Equal(normalizeTargets(recordType, a, lower), normalizeTargets(recordType, b, lower))
+}
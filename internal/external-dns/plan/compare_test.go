@@ -0,0 +1,87 @@
+package plan
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"sigs.k8s.io/external-dns/endpoint"
+
+	"github.com/kuadrant/dns-operator/api/v1alpha1"
+)
+
+func TestNormalizeTarget(t *testing.T) {
+	for _, test := range []struct {
+		recordType string
+		target     string
+		expected   string
+	}{
+		{recordType: endpoint.RecordTypeA, target: " 1.1.1.1 ", expected: "1.1.1.1"},
+		{recordType: endpoint.RecordTypeAAAA, target: "2001:0DB8:0000:0000:0000:0000:0000:0001", expected: "2001:db8::1"},
+		{recordType: endpoint.RecordTypeAAAA, target: "not-an-ip", expected: "not-an-ip"},
+		{recordType: endpoint.RecordTypeCNAME, target: "LB.Example.com.", expected: "lb.example.com"},
+		{recordType: endpoint.RecordTypeNS, target: "ns1.example.com.", expected: "ns1.example.com"},
+		{recordType: endpoint.RecordTypeTXT, target: "Some Text.", expected: "Some Text."},
+		{recordType: v1alpha1.RecordTypeNAPTR, target: `100  10 "S" "SIP+D2U" "" _sip._udp.Example.com.`, expected: `100 10 "S" "SIP+D2U" "" _sip._udp.example.com`},
+		{recordType: v1alpha1.RecordTypeNAPTR, target: `100 10 "u" "E2U+sip" "!^.*$!sip:info@example.com!" .`, expected: `100 10 "u" "E2U+sip" "!^.*$!sip:info@example.com!" .`},
+		{recordType: v1alpha1.RecordTypeNAPTR, target: `100 10 "u" "E2U+sip" "!^.*$!sip:info@example.com!" `, expected: `100 10 "u" "E2U+sip" "!^.*$!sip:info@example.com!" .`},
+		{recordType: v1alpha1.RecordTypeTLSA, target: "3 1 1 0C72AC70 B745AC19", expected: "3 1 1 0c72ac70b745ac19"},
+	} {
+		t.Run(test.recordType+"/"+test.target, func(t *testing.T) {
+			assert.Equal(t, test.expected, NormalizeTarget(test.recordType, test.target))
+		})
+	}
+}
+
+func TestCalculateNormalizedTargets(t *testing.T) {
+	for _, test := range []struct {
+		name        string
+		current     *endpoint.Endpoint
+		desired     *endpoint.Endpoint
+		normalizer  TargetNormalizer
+		wantUpdates int
+	}{
+		{
+			name:        "CNAME target with trailing dot and different case",
+			current:     endpoint.NewEndpoint("foo.example.com", endpoint.RecordTypeCNAME, "LB.Example.com."),
+			desired:     endpoint.NewEndpoint("foo.example.com", endpoint.RecordTypeCNAME, "lb.example.com"),
+			wantUpdates: 0,
+		},
+		{
+			name:        "AAAA target expanded by provider",
+			current:     endpoint.NewEndpoint("foo.example.com", endpoint.RecordTypeAAAA, "2001:0db8:0000:0000:0000:0000:0000:0001"),
+			desired:     endpoint.NewEndpoint("foo.example.com", endpoint.RecordTypeAAAA, "2001:db8::1"),
+			wantUpdates: 0,
+		},
+		{
+			name:        "CNAME target changed",
+			current:     endpoint.NewEndpoint("foo.example.com", endpoint.RecordTypeCNAME, "lb1.example.com."),
+			desired:     endpoint.NewEndpoint("foo.example.com", endpoint.RecordTypeCNAME, "lb2.example.com"),
+			wantUpdates: 1,
+		},
+		{
+			name:    "provider normalizer",
+			current: endpoint.NewEndpoint("foo.example.com", endpoint.RecordTypeCNAME, "\\052.lb.example.com."),
+			desired: endpoint.NewEndpoint("foo.example.com", endpoint.RecordTypeCNAME, "*.lb.example.com"),
+			normalizer: func(recordType, target string) string {
+				return NormalizeTarget(recordType, strings.ReplaceAll(target, "\\052", "*"))
+			},
+			wantUpdates: 0,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			test.current.Labels = endpoint.Labels{endpoint.OwnerLabelKey: "owner1"}
+			test.desired.Labels = endpoint.Labels{endpoint.OwnerLabelKey: "owner1"}
+			p := NewPlan(context.Background(), []*endpoint.Endpoint{test.current}, nil, []*endpoint.Endpoint{test.desired}, nil,
+				endpoint.MatchAllDomainFilters{}, []string{endpoint.RecordTypeA, endpoint.RecordTypeAAAA, endpoint.RecordTypeCNAME}, nil, "owner1", nil)
+			p.TargetNormalizer = test.normalizer
+
+			changes := p.Calculate().Changes
+			assert.Empty(t, changes.Create)
+			assert.Empty(t, changes.Delete)
+			assert.Len(t, changes.UpdateNew, test.wantUpdates)
+		})
+	}
+}
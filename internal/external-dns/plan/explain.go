@@ -0,0 +1,134 @@
+package plan
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"sigs.k8s.io/external-dns/endpoint"
+)
+
+// ProviderSpecificForceUpdate is the provider specific property the registry sets on current records to have them
+// updated, so their TXT records are written again.
+const ProviderSpecificForceUpdate = "txt/force-update"
+
+// ChangeAction is the kind of a change generated by the plan.
+type ChangeAction string
+
+const (
+	ChangeActionCreate ChangeAction = "Create"
+	ChangeActionUpdate ChangeAction = "Update"
+	ChangeActionDelete ChangeAction = "Delete"
+)
+
+// ChangeReason is why a change was generated by the plan.
+type ChangeReason string
+
+const (
+	// ChangeReasonNewEndpoint the endpoint is desired and does not exist
+	ChangeReasonNewEndpoint ChangeReason = "NewEndpoint"
+	// ChangeReasonEndpointRemoved the endpoint exists and is no longer desired by any owner
+	ChangeReasonEndpointRemoved ChangeReason = "EndpointRemoved"
+	// ChangeReasonTargetChange the targets of the endpoint differ from the desired targets
+	ChangeReasonTargetChange ChangeReason = "TargetChange"
+	// ChangeReasonTTLChange the TTL of the endpoint differs from the desired TTL
+	ChangeReasonTTLChange ChangeReason = "TTLChange"
+	// ChangeReasonProviderSpecificChange the provider specific properties of the endpoint differ from the desired ones
+	ChangeReasonProviderSpecificChange ChangeReason = "ProviderSpecificChange"
+	// ChangeReasonOwnerChange an owner is added to or removed from the endpoint
+	ChangeReasonOwnerChange ChangeReason = "OwnerChange"
+	// ChangeReasonRegistryRepair the registry TXT records of the endpoint are missing or have to be rewritten
+	ChangeReasonRegistryRepair ChangeReason = "RegistryRepair"
+	// ChangeReasonForceUpdate the endpoint is up to date and written again because the plan forces updates
+	ChangeReasonForceUpdate ChangeReason = "ForceUpdate"
+)
+
+// ChangeExplanation describes a change generated by the plan and why it was generated.
+type ChangeExplanation struct {
+	Action        ChangeAction
+	DNSName       string
+	RecordType    string
+	SetIdentifier string
+	Reasons       []ChangeReason
+}
+
+func (e ChangeExplanation) String() string {
+	name := e.DNSName
+	if e.SetIdentifier != "" {
+		name = fmt.Sprintf("%s/%s", e.DNSName, e.SetIdentifier)
+	}
+	reasons := make([]string, 0, len(e.Reasons))
+	for _, reason := range e.Reasons {
+		reasons = append(reasons, string(reason))
+	}
+	return fmt.Sprintf("%s %s %s (%s)", strings.ToLower(string(e.Action)), e.RecordType, name, strings.Join(reasons, ", "))
+}
+
+// Explain returns the explanation of each change of the plan, creates first, then updates, then deletes. Changes made
+// to the plan after it was calculated are explained as well.
+func (p *Plan) Explain() []ChangeExplanation {
+	if p.Changes == nil {
+		return nil
+	}
+	normalize := p.TargetNormalizer
+	if normalize == nil {
+		normalize = NormalizeTarget
+	}
+
+	explanations := make([]ChangeExplanation, 0, len(p.Changes.Create)+len(p.Changes.UpdateNew)+len(p.Changes.Delete))
+	for _, ep := range p.Changes.Create {
+		explanations = append(explanations, explain(ChangeActionCreate, ep, ChangeReasonNewEndpoint))
+	}
+	for i, desired := range p.Changes.UpdateNew {
+		if i >= len(p.Changes.UpdateOld) {
+			break
+		}
+		explanations = append(explanations, explain(ChangeActionUpdate, desired, updateReasons(desired, p.Changes.UpdateOld[i], normalize)...))
+	}
+	for _, ep := range p.Changes.Delete {
+		explanations = append(explanations, explain(ChangeActionDelete, ep, ChangeReasonEndpointRemoved))
+	}
+	return explanations
+}
+
+func explain(action ChangeAction, ep *endpoint.Endpoint, reasons ...ChangeReason) ChangeExplanation {
+	return ChangeExplanation{
+		Action:        action,
+		DNSName:       ep.DNSName,
+		RecordType:    ep.RecordType,
+		SetIdentifier: ep.SetIdentifier,
+		Reasons:       reasons,
+	}
+}
+
+// updateReasons returns the reasons the current endpoint is updated to the desired endpoint
+func updateReasons(desired, current *endpoint.Endpoint, normalize TargetNormalizer) []ChangeReason {
+	var reasons []ChangeReason
+	if targetChanged(desired, current, normalize) {
+		reasons = append(reasons, ChangeReasonTargetChange)
+	}
+	if shouldUpdateTTL(desired, current) {
+		reasons = append(reasons, ChangeReasonTTLChange)
+	}
+	// the property requesting a registry repair is never desired, it is not a change of the provider specific properties
+	compared := current
+	if _, repair := current.GetProviderSpecificProperty(ProviderSpecificForceUpdate); repair {
+		compared = current.DeepCopy()
+		compared.ProviderSpecific = slices.DeleteFunc(compared.ProviderSpecific, func(p endpoint.ProviderSpecificProperty) bool {
+			return p.Name == ProviderSpecificForceUpdate
+		})
+	}
+	if shouldUpdateProviderSpecific(desired, compared) {
+		reasons = append(reasons, ChangeReasonProviderSpecificChange)
+	}
+	if shouldUpdateOwner(desired, current) {
+		reasons = append(reasons, ChangeReasonOwnerChange)
+	}
+	if compared != current {
+		reasons = append(reasons, ChangeReasonRegistryRepair)
+	}
+	if len(reasons) == 0 {
+		reasons = append(reasons, ChangeReasonForceUpdate)
+	}
+	return reasons
+}
@@ -0,0 +1,61 @@
+package plan
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"sigs.k8s.io/external-dns/endpoint"
+)
+
+func TestExplain(t *testing.T) {
+	owned := func(ep *endpoint.Endpoint, owner string) *endpoint.Endpoint {
+		ep.Labels = endpoint.Labels{endpoint.OwnerLabelKey: owner}
+		return ep
+	}
+	repaired := owned(endpoint.NewEndpoint("repair.example.com", endpoint.RecordTypeA, "3.3.3.3"), "owner1")
+	repaired.WithProviderSpecific(ProviderSpecificForceUpdate, "true")
+
+	current := []*endpoint.Endpoint{
+		owned(endpoint.NewEndpointWithTTL("update.example.com", endpoint.RecordTypeA, 60, "1.1.1.1"), "owner1"),
+		owned(endpoint.NewEndpoint("shared.example.com", endpoint.RecordTypeA, "2.2.2.2"), "owner2"),
+		repaired,
+		owned(endpoint.NewEndpoint("delete.example.com", endpoint.RecordTypeA, "4.4.4.4"), "owner1"),
+		owned(endpoint.NewEndpoint("unchanged.example.com", endpoint.RecordTypeA, "5.5.5.5"), "owner1"),
+	}
+	desired := []*endpoint.Endpoint{
+		endpoint.NewEndpoint("create.example.com", endpoint.RecordTypeA, "6.6.6.6"),
+		endpoint.NewEndpointWithTTL("update.example.com", endpoint.RecordTypeA, 300, "1.1.1.2"),
+		endpoint.NewEndpoint("shared.example.com", endpoint.RecordTypeA, "2.2.2.2"),
+		endpoint.NewEndpoint("repair.example.com", endpoint.RecordTypeA, "3.3.3.3"),
+		endpoint.NewEndpoint("unchanged.example.com", endpoint.RecordTypeA, "5.5.5.5"),
+	}
+
+	explanations := func(forceUpdate bool) map[string]ChangeExplanation {
+		p := NewPlan(context.Background(), current, nil, desired, nil, endpoint.MatchAllDomainFilters{},
+			[]string{endpoint.RecordTypeA}, nil, "owner1", nil)
+		p.ForceUpdate = forceUpdate
+		byName := map[string]ChangeExplanation{}
+		for _, e := range p.Calculate().Explain() {
+			byName[e.DNSName] = e
+		}
+		return byName
+	}
+
+	assert.Equal(t, map[string]ChangeExplanation{
+		"create.example.com": {Action: ChangeActionCreate, DNSName: "create.example.com", RecordType: "A",
+			Reasons: []ChangeReason{ChangeReasonNewEndpoint}},
+		"update.example.com": {Action: ChangeActionUpdate, DNSName: "update.example.com", RecordType: "A",
+			Reasons: []ChangeReason{ChangeReasonTargetChange, ChangeReasonTTLChange}},
+		"shared.example.com": {Action: ChangeActionUpdate, DNSName: "shared.example.com", RecordType: "A",
+			Reasons: []ChangeReason{ChangeReasonOwnerChange}},
+		"repair.example.com": {Action: ChangeActionUpdate, DNSName: "repair.example.com", RecordType: "A",
+			Reasons: []ChangeReason{ChangeReasonRegistryRepair}},
+		"delete.example.com": {Action: ChangeActionDelete, DNSName: "delete.example.com", RecordType: "A",
+			Reasons: []ChangeReason{ChangeReasonEndpointRemoved}},
+	}, explanations(false))
+
+	assert.Equal(t, []ChangeReason{ChangeReasonForceUpdate}, explanations(true)["unchanged.example.com"].Reasons)
+	assert.Equal(t, "update A update.example.com (TargetChange, TTLChange)", explanations(false)["update.example.com"].String())
+}
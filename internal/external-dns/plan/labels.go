@@ -1,6 +1,18 @@
 package plan
 
+import "sigs.k8s.io/external-dns/endpoint"
+
 const (
 	// OwnerLabelDeliminator is a deliminator used between owners in the OwnerLabelKey value when multiple owners are assigned.
 	OwnerLabelDeliminator = "&&"
+
+	// ResourceUIDLabelKey is the name of the label that identifies the uid of the k8s resource that wrote the DNS name
+	ResourceUIDLabelKey = "resource-uid"
 )
+
+// removeResourceLabels removes the labels identifying the resource that last wrote the DNS name. The resource is not
+// known to still be an owner once an owner is released, the remaining owners add their own on their next update.
+func removeResourceLabels(labels endpoint.Labels) {
+	delete(labels, endpoint.ResourceLabelKey)
+	delete(labels, ResourceUIDLabelKey)
+}
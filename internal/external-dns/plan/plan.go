@@ -70,6 +70,14 @@ type Plan struct {
 	// Owners list of owners ids contributing to this record set.
 	// Populated after calling Calculate()
 	Owners []string
+	// Takeovers are the updates of the changes that remove targets or ownership of other owners of the endpoints.
+	// Populated after calling Calculate()
+	Takeovers []Takeover
+	// TargetNormalizer is used to compare target values of current and desired records, defaults to NormalizeTarget.
+	TargetNormalizer TargetNormalizer
+	// ForceUpdate includes an update for every desired record that already exists, even when it is up to date, so the
+	// desired state of all records is written again.
+	ForceUpdate bool
 
 	logger logr.Logger
 }
@@ -213,6 +221,10 @@ func (p *Plan) Calculate() *Plan {
 		p.DomainFilter = endpoint.MatchAllDomainFilters(nil)
 	}
 
+	if p.TargetNormalizer == nil {
+		p.TargetNormalizer = NormalizeTarget
+	}
+
 	if p.RootHost != nil {
 		rootDomainName, _ := strings.CutPrefix(*p.RootHost, v1alpha1.WildcardPrefix)
 		rootDomainFilter = endpoint.NewDomainFilter([]string{rootDomainName})
@@ -237,6 +249,8 @@ func (p *Plan) Calculate() *Plan {
 		updates:          []*endpointUpdate{},
 		dnsNameOwners:    map[string][]string{},
 		errors:           []error{},
+		normalize:        p.TargetNormalizer,
+		forceUpdate:      p.ForceUpdate,
 		logger:           p.logger,
 	}
 
@@ -274,6 +288,7 @@ func (p *Plan) Calculate() *Plan {
 						slices.Sort(owners)
 						owners = slices.Compact[[]string, string](owners)
 						candidate.Labels[endpoint.OwnerLabelKey] = strings.Join(owners, OwnerLabelDeliminator)
+						removeResourceLabels(candidate.Labels)
 					}
 
 					if len(owners) == 0 {
@@ -358,11 +373,13 @@ func (p *Plan) Calculate() *Plan {
 	}
 
 	plan := &Plan{
-		Current:        p.Current,
-		Desired:        p.Desired,
-		Changes:        changes,
-		Errors:         errs,
-		ManagedRecords: []string{endpoint.RecordTypeA, endpoint.RecordTypeAAAA, endpoint.RecordTypeCNAME},
+		Current:          p.Current,
+		Desired:          p.Desired,
+		Changes:          changes,
+		Errors:           errs,
+		Takeovers:        managedChanges.takeovers,
+		ManagedRecords:   []string{endpoint.RecordTypeA, endpoint.RecordTypeAAAA, endpoint.RecordTypeCNAME},
+		TargetNormalizer: p.TargetNormalizer,
 	}
 
 	if p.RootHost != nil {
@@ -416,8 +433,8 @@ type endpointUpdate struct {
 	desired  *endpoint.Endpoint
 }
 
-func (e *endpointUpdate) ShouldUpdate() bool {
-	return shouldUpdateOwner(e.desired, e.current) || shouldUpdateTTL(e.desired, e.current) || targetChanged(e.desired, e.current) || shouldUpdateProviderSpecific(e.desired, e.current)
+func (e *endpointUpdate) ShouldUpdate(normalize TargetNormalizer) bool {
+	return shouldUpdateOwner(e.desired, e.current) || shouldUpdateTTL(e.desired, e.current) || targetChanged(e.desired, e.current, normalize) || shouldUpdateProviderSpecific(e.desired, e.current)
 }
 
 func (e *endpointUpdate) IsDeleting() bool {
@@ -432,6 +449,9 @@ type managedRecordSetChanges struct {
 	updates          []*endpointUpdate
 	dnsNameOwners    map[string][]string
 	errors           []error
+	takeovers        []Takeover
+	normalize        TargetNormalizer
+	forceUpdate      bool
 	logger           logr.Logger
 }
 
@@ -450,13 +470,16 @@ func (e *managedRecordSetChanges) Calculate() *externaldnsplan.Changes {
 
 	for _, update := range e.updates {
 		e.calculateDesired(update)
-		if update.ShouldUpdate() {
+		if update.ShouldUpdate(e.normalize) || (e.forceUpdate && !update.IsDeleting()) {
 			if !update.IsDeleting() {
 				if err := e.validTargets(update.desired); err != nil {
 					e.errors = append(e.errors, err)
 					continue
 				}
 			}
+			if takeover := takeoverOf(update, e.ownerID, e.normalize); takeover != nil {
+				e.takeovers = append(e.takeovers, *takeover)
+			}
 			changes.UpdateNew = append(changes.UpdateNew, update.desired)
 			changes.UpdateOld = append(changes.UpdateOld, update.current)
 		}
@@ -502,7 +525,7 @@ func (e *managedRecordSetChanges) calculateDesired(update *endpointUpdate) {
 	// target values is never going to be correct.
 	if update.isDelete && update.previous != nil {
 		desiredCopy := update.desired.DeepCopy()
-		removeEndpointTargets(update.previous.Targets, desiredCopy)
+		removeEndpointTargets(update.previous.Targets, desiredCopy, e.normalize)
 		if len(desiredCopy.Targets) > 0 {
 			update.desired.Targets = desiredCopy.Targets
 		}
@@ -514,18 +537,18 @@ func (e *managedRecordSetChanges) calculateDesired(update *endpointUpdate) {
 	// A Records can be merged, but we remove the known previous target values first in order to ensure potentially stale values are removed
 	if update.current.RecordType == endpoint.RecordTypeA {
 		if update.previous != nil {
-			removeEndpointTargets(update.previous.Targets, currentCopy)
+			removeEndpointTargets(update.previous.Targets, currentCopy, e.normalize)
 		}
-		mergeEndpointTargets(desiredCopy, currentCopy)
+		mergeEndpointTargets(desiredCopy, currentCopy, e.normalize)
 	}
 
 	// CNAME records can be merged, it's expected that the provider implementation understands that a CNAME might have
 	// multiple target values and adjusts accordingly during apply.
 	if update.current.RecordType == endpoint.RecordTypeCNAME {
 		if update.previous != nil {
-			removeEndpointTargets(update.previous.Targets, currentCopy)
+			removeEndpointTargets(update.previous.Targets, currentCopy, e.normalize)
 		}
-		mergeEndpointTargets(desiredCopy, currentCopy)
+		mergeEndpointTargets(desiredCopy, currentCopy, e.normalize)
 
 		//ToDo manirn Check this is actually needed, and if it is add a test that requires it to be here
 		if len(desiredCopy.Targets) <= 1 {
@@ -547,7 +570,7 @@ func (e *managedRecordSetChanges) calculateDesired(update *endpointUpdate) {
 
 				// If the target has no owners we can just remove it
 				if len(tOwners) == 0 {
-					removeEndpointTarget(t, desiredCopy)
+					removeEndpointTarget(t, desiredCopy, e.normalize)
 					break
 				}
 
@@ -561,7 +584,7 @@ func (e *managedRecordSetChanges) calculateDesired(update *endpointUpdate) {
 						}
 					}
 					if !hasMutualOwner {
-						removeEndpointTarget(t, desiredCopy)
+						removeEndpointTarget(t, desiredCopy, e.normalize)
 					}
 				}
 
@@ -571,18 +594,18 @@ func (e *managedRecordSetChanges) calculateDesired(update *endpointUpdate) {
 	update.desired = desiredCopy
 }
 
-func removeEndpointTarget(target string, endpoint *endpoint.Endpoint) {
-	removeEndpointTargets([]string{target}, endpoint)
+func removeEndpointTarget(target string, endpoint *endpoint.Endpoint, normalize TargetNormalizer) {
+	removeEndpointTargets([]string{target}, endpoint, normalize)
 }
 
-func removeEndpointTargets(targets []string, endpoint *endpoint.Endpoint) {
+func removeEndpointTargets(targets []string, endpoint *endpoint.Endpoint, normalize TargetNormalizer) {
 	undesiredMap := map[string]string{}
 	for idx := range targets {
-		undesiredMap[targets[idx]] = targets[idx]
+		undesiredMap[normalize(endpoint.RecordType, targets[idx])] = targets[idx]
 	}
 	desiredTargets := []string{}
 	for idx := range endpoint.Targets {
-		if _, ok := undesiredMap[endpoint.Targets[idx]]; ok {
+		if _, ok := undesiredMap[normalize(endpoint.RecordType, endpoint.Targets[idx])]; ok {
 			endpoint.DeleteProviderSpecificProperty(endpoint.Targets[idx])
 		} else {
 			desiredTargets = append(desiredTargets, endpoint.Targets[idx])
@@ -591,8 +614,17 @@ func removeEndpointTargets(targets []string, endpoint *endpoint.Endpoint) {
 	endpoint.Targets = desiredTargets
 }
 
-func mergeEndpointTargets(desired, current *endpoint.Endpoint) {
-	desired.Targets = append(desired.Targets, current.Targets...)
+func mergeEndpointTargets(desired, current *endpoint.Endpoint, normalize TargetNormalizer) {
+	// current targets equal to a desired target after normalization are dropped, the desired form is kept
+	desiredTargets := map[string]struct{}{}
+	for _, t := range desired.Targets {
+		desiredTargets[normalize(desired.RecordType, t)] = struct{}{}
+	}
+	for _, t := range current.Targets {
+		if _, ok := desiredTargets[normalize(current.RecordType, t)]; !ok {
+			desired.Targets = append(desired.Targets, t)
+		}
+	}
 	slices.Sort(desired.Targets)
 	desired.Targets = slices.Compact[[]string, string](desired.Targets)
 
@@ -604,8 +636,8 @@ func mergeEndpointTargets(desired, current *endpoint.Endpoint) {
 	}
 }
 
-func targetChanged(desired, current *endpoint.Endpoint) bool {
-	return !desired.Targets.Same(current.Targets)
+func targetChanged(desired, current *endpoint.Endpoint, normalize TargetNormalizer) bool {
+	return !sameTargets(desired.RecordType, desired.Targets, current.Targets, normalize)
 }
 
 func shouldUpdateOwner(desired, current *endpoint.Endpoint) bool {
@@ -860,6 +860,29 @@ func (suite *PlanTestSuite) TestIdempotency() {
 	assert.Empty(suite.T(), cp.Errors)
 }
 
+func (suite *PlanTestSuite) TestIdempotencyForceUpdate() {
+	current := []*endpoint.Endpoint{suite.bar127A}
+	desired := []*endpoint.Endpoint{suite.bar127A}
+	expectedChanges := &plan.Changes{
+		Create:    []*endpoint.Endpoint{},
+		UpdateOld: []*endpoint.Endpoint{suite.bar127A},
+		UpdateNew: []*endpoint.Endpoint{suite.bar127A},
+		Delete:    []*endpoint.Endpoint{},
+	}
+
+	p := &Plan{
+		Policies:       []Policy{&SyncPolicy{}},
+		Current:        current,
+		Desired:        desired,
+		ManagedRecords: []string{endpoint.RecordTypeA, endpoint.RecordTypeCNAME},
+		ForceUpdate:    true,
+	}
+
+	cp := p.Calculate()
+	validateChanges(suite.T(), cp.Changes, expectedChanges)
+	assert.Empty(suite.T(), cp.Errors)
+}
+
 func (suite *PlanTestSuite) TestRecordTypeChange() {
 	suite.T().Skip("Skipping incompatible test, plan does not allow record types to change")
 	current := []*endpoint.Endpoint{suite.fooV1Cname}
@@ -2126,6 +2149,42 @@ func (suite *PlanTestSuite) TestMultiOwnerCNAMERecordDelete() {
 	assert.Empty(suite.T(), cp.Errors)
 }
 
+// Should remove the resource labels of the plan owner from records with shared ownership it is released from.
+func (suite *PlanTestSuite) TestMultiOwnerARecordDeleteResourceLabels() {
+	resourceLabels := map[string]string{
+		endpoint.ResourceLabelKey: "dnsrecord/default/foo",
+		ResourceUIDLabelKey:       "1234",
+	}
+	shared := endpoint.NewEndpoint("foo", endpoint.RecordTypeA, "1.1.1.1", "2.2.2.2")
+	shared.Labels = map[string]string{endpoint.OwnerLabelKey: "owner1&&owner2"}
+	for k, v := range resourceLabels {
+		shared.Labels[k] = v
+	}
+	previous := endpoint.NewEndpoint("foo", endpoint.RecordTypeA, "2.2.2.2")
+	previous.Labels = map[string]string{endpoint.OwnerLabelKey: "owner2"}
+
+	p := &Plan{
+		OwnerID:        "owner2",
+		Policies:       []Policy{&SyncPolicy{}},
+		Current:        []*endpoint.Endpoint{shared},
+		Desired:        []*endpoint.Endpoint{},
+		Previous:       []*endpoint.Endpoint{previous},
+		ManagedRecords: []string{endpoint.RecordTypeA, endpoint.RecordTypeAAAA, endpoint.RecordTypeCNAME},
+	}
+
+	cp := p.Calculate()
+	assert.Empty(suite.T(), cp.Errors)
+	assert.Empty(suite.T(), cp.Changes.Delete)
+	if assert.Len(suite.T(), cp.Changes.UpdateNew, 1) {
+		released := cp.Changes.UpdateNew[0]
+		assert.Equal(suite.T(), endpoint.Targets{"1.1.1.1"}, released.Targets)
+		assert.Equal(suite.T(), endpoint.Labels{endpoint.OwnerLabelKey: "owner1"}, released.Labels)
+	}
+	if assert.Len(suite.T(), cp.Changes.UpdateOld, 1) {
+		assert.Equal(suite.T(), "1234", cp.Changes.UpdateOld[0].Labels[ResourceUIDLabelKey])
+	}
+}
+
 func TestPlan(t *testing.T) {
 	suite.Run(t, new(PlanTestSuite))
 }
@@ -0,0 +1,97 @@
+package plan
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"sigs.k8s.io/external-dns/endpoint"
+)
+
+// Takeover is an update of an endpoint shared with other owners that removes targets published by them, or removes them
+// as owners of the endpoint. Targets of A and CNAME endpoints without a set identifier are merged with those of the
+// other owners, updates of other endpoints replace their targets.
+type Takeover struct {
+	DNSName       string
+	RecordType    string
+	SetIdentifier string
+	// Owners are the other owners of the endpoint
+	Owners []string
+	// RemovedTargets are the targets removed by the update that were not published by the owner of the plan
+	RemovedTargets []string
+	// RemovedOwners are the other owners removed from the endpoint by the update
+	RemovedOwners []string
+}
+
+func (t Takeover) String() string {
+	name := t.DNSName
+	if t.SetIdentifier != "" {
+		name = fmt.Sprintf("%s/%s", t.DNSName, t.SetIdentifier)
+	}
+	var removed []string
+	if len(t.RemovedTargets) > 0 {
+		removed = append(removed, fmt.Sprintf("removes targets %s", strings.Join(t.RemovedTargets, ", ")))
+	}
+	if len(t.RemovedOwners) > 0 {
+		removed = append(removed, fmt.Sprintf("removes owners %s", strings.Join(t.RemovedOwners, ", ")))
+	}
+	return fmt.Sprintf("%s %s owned by %s %s", t.RecordType, name, strings.Join(t.Owners, ", "), strings.Join(removed, " and "))
+}
+
+// takeoverOf returns the takeover of the endpoint by the update, or nil if the update leaves the targets and ownership
+// of the other owners of the endpoint as they are.
+func takeoverOf(update *endpointUpdate, ownerID string, normalize TargetNormalizer) *Takeover {
+	if ownerID == "" || update.IsDeleting() {
+		return nil
+	}
+	owners := otherOwners(update.current, ownerID)
+	if len(owners) == 0 {
+		return nil
+	}
+
+	// the targets published last by the owner of the plan are its own to remove
+	kept := map[string]struct{}{}
+	for _, t := range update.desired.Targets {
+		kept[normalize(update.desired.RecordType, t)] = struct{}{}
+	}
+	if update.previous != nil {
+		for _, t := range update.previous.Targets {
+			kept[normalize(update.previous.RecordType, t)] = struct{}{}
+		}
+	}
+	takeover := &Takeover{
+		DNSName:       update.current.DNSName,
+		RecordType:    update.current.RecordType,
+		SetIdentifier: update.current.SetIdentifier,
+		Owners:        owners,
+	}
+	for _, t := range update.current.Targets {
+		if _, ok := kept[normalize(update.current.RecordType, t)]; !ok {
+			takeover.RemovedTargets = append(takeover.RemovedTargets, t)
+		}
+	}
+	if _, ok := update.desired.Labels[endpoint.OwnerLabelKey]; ok {
+		desiredOwners := strings.Split(update.desired.Labels[endpoint.OwnerLabelKey], OwnerLabelDeliminator)
+		for _, owner := range owners {
+			if !slices.Contains(desiredOwners, owner) {
+				takeover.RemovedOwners = append(takeover.RemovedOwners, owner)
+			}
+		}
+	}
+	if len(takeover.RemovedTargets) == 0 && len(takeover.RemovedOwners) == 0 {
+		return nil
+	}
+	return takeover
+}
+
+// otherOwners returns the sorted owners of the endpoint other than the given owner
+func otherOwners(ep *endpoint.Endpoint, ownerID string) []string {
+	var owners []string
+	for _, owner := range strings.Split(ep.Labels[endpoint.OwnerLabelKey], OwnerLabelDeliminator) {
+		if owner != "" && owner != ownerID {
+			owners = append(owners, owner)
+		}
+	}
+	slices.Sort(owners)
+	return slices.Compact(owners)
+}
@@ -0,0 +1,58 @@
+package plan
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"sigs.k8s.io/external-dns/endpoint"
+)
+
+func TestTakeovers(t *testing.T) {
+	owned := func(ep *endpoint.Endpoint, owner string) *endpoint.Endpoint {
+		ep.Labels = endpoint.Labels{endpoint.OwnerLabelKey: owner}
+		return ep
+	}
+	weighted := func(ep *endpoint.Endpoint) *endpoint.Endpoint {
+		return ep.WithSetIdentifier("cluster1")
+	}
+
+	current := []*endpoint.Endpoint{
+		// targets of AAAA endpoints are not merged, the target of owner2 is replaced
+		owned(endpoint.NewEndpoint("aaaa.example.com", endpoint.RecordTypeAAAA, "::1", "::2"), "owner1&&owner2"),
+		// targets of A endpoints are merged, the target of owner2 is kept
+		owned(endpoint.NewEndpoint("a.example.com", endpoint.RecordTypeA, "1.1.1.1", "2.2.2.2"), "owner1&&owner2"),
+		// endpoints with a set identifier have a single target
+		owned(weighted(endpoint.NewEndpoint("weighted.example.com", endpoint.RecordTypeCNAME, "lb-2.example.net")), "owner2"),
+		// endpoints without other owners are never taken over
+		owned(endpoint.NewEndpoint("txt.example.com", endpoint.RecordTypeTXT, "old"), "owner1"),
+	}
+	previous := []*endpoint.Endpoint{
+		endpoint.NewEndpoint("aaaa.example.com", endpoint.RecordTypeAAAA, "::2"),
+		endpoint.NewEndpoint("a.example.com", endpoint.RecordTypeA, "2.2.2.2"),
+	}
+	desired := []*endpoint.Endpoint{
+		endpoint.NewEndpoint("aaaa.example.com", endpoint.RecordTypeAAAA, "::3"),
+		endpoint.NewEndpoint("a.example.com", endpoint.RecordTypeA, "3.3.3.3"),
+		weighted(endpoint.NewEndpoint("weighted.example.com", endpoint.RecordTypeCNAME, "lb-1.example.net")),
+		endpoint.NewEndpoint("txt.example.com", endpoint.RecordTypeTXT, "new"),
+	}
+
+	p := NewPlan(context.Background(), current, previous, desired, nil, endpoint.MatchAllDomainFilters{},
+		[]string{endpoint.RecordTypeA, endpoint.RecordTypeAAAA, endpoint.RecordTypeCNAME, endpoint.RecordTypeTXT}, nil, "owner1", nil)
+	p = p.Calculate()
+	assert.NoError(t, p.Error())
+	assert.Len(t, p.Changes.UpdateNew, 4)
+	assert.ElementsMatch(t, []Takeover{
+		{DNSName: "aaaa.example.com", RecordType: "AAAA", Owners: []string{"owner2"}, RemovedTargets: []string{"::1"}},
+		{DNSName: "weighted.example.com", RecordType: "CNAME", SetIdentifier: "cluster1", Owners: []string{"owner2"},
+			RemovedTargets: []string{"lb-2.example.net"}},
+	}, p.Takeovers)
+
+	assert.Equal(t, "AAAA aaaa.example.com owned by owner2 removes targets ::1",
+		Takeover{DNSName: "aaaa.example.com", RecordType: "AAAA", Owners: []string{"owner2"}, RemovedTargets: []string{"::1"}}.String())
+	assert.Equal(t, "CNAME weighted.example.com/cluster1 owned by owner2 removes owners owner3",
+		Takeover{DNSName: "weighted.example.com", RecordType: "CNAME", SetIdentifier: "cluster1", Owners: []string{"owner2"},
+			RemovedOwners: []string{"owner3"}}.String())
+}
@@ -241,8 +241,12 @@ type AWSProvider struct {
 	zoneTagFilter provider.ZoneTagFilter
 	preferCNAME   bool
 	zonesCache    *zonesListCache
+	// zones used instead of listing the hosted zones, for credentials that can't list them
+	staticZones map[string]*route53.HostedZone
 	// queue for collecting changes to submit them in the next iteration, but after all other changes
 	failedChangesQueue map[string]Route53Changes
+	// ids of the change batches submitted by this provider
+	submittedChanges []string
 }
 
 // AWSConfig contains configuration to create a new AWS provider.
@@ -257,6 +261,8 @@ type AWSConfig struct {
 	PreferCNAME          bool
 	DryRun               bool
 	ZoneCacheDuration    time.Duration
+	// StaticZones are used instead of listing the hosted zones if set
+	StaticZones map[string]*route53.HostedZone
 }
 
 // NewAWSProvider initializes a new AWS Route53 based Provider.
@@ -275,6 +281,7 @@ func NewAWSProvider(ctx context.Context, awsConfig AWSConfig, client Route53API)
 		preferCNAME:          awsConfig.PreferCNAME,
 		dryRun:               awsConfig.DryRun,
 		zonesCache:           &zonesListCache{duration: awsConfig.ZoneCacheDuration},
+		staticZones:          awsConfig.StaticZones,
 		failedChangesQueue:   make(map[string]Route53Changes),
 	}
 
@@ -283,6 +290,16 @@ func NewAWSProvider(ctx context.Context, awsConfig AWSConfig, client Route53API)
 
 // Zones returns the list of hosted zones.
 func (p *AWSProvider) Zones(ctx context.Context) (map[string]*route53.HostedZone, error) {
+	if p.staticZones != nil {
+		zones := make(map[string]*route53.HostedZone)
+		for id, zone := range p.staticZones {
+			if p.zoneIDFilter.Match(id) && p.domainFilter.Match(aws.StringValue(zone.Name)) {
+				zones[id] = zone
+			}
+		}
+		return zones, nil
+	}
+
 	if p.zonesCache.zones != nil && time.Since(p.zonesCache.age) < p.zonesCache.duration {
 		p.logger.V(1).Info("Using cached zones list")
 		return p.zonesCache.zones, nil
@@ -585,7 +602,7 @@ func (p *AWSProvider) submitChanges(ctx context.Context, changes Route53Changes,
 
 				successfulChanges := 0
 
-				if _, err := p.client.ChangeResourceRecordSetsWithContext(ctx, params); err != nil {
+				if out, err := p.client.ChangeResourceRecordSetsWithContext(ctx, params); err != nil {
 					p.logger.Error(err, fmt.Sprintf("Failure in zone %s [Id: %s] when submitting change batch", aws.StringValue(zones[z].Name), z))
 
 					//ToDo mnairn: Make this optional
@@ -611,10 +628,13 @@ func (p *AWSProvider) submitChanges(ctx context.Context, changes Route53Changes,
 					//		}
 					//	}
 					//} else {
-					zoneErrors = append(zoneErrors, fmt.Errorf("Failure in zone %s [Id: %s] when submitting change: %v", aws.StringValue(zones[z].Name), z, err))
+					zoneErrors = append(zoneErrors, fmt.Errorf("Failure in zone %s [Id: %s] when submitting change: %w", aws.StringValue(zones[z].Name), z, err))
 					//}
 				} else {
 					successfulChanges = len(b)
+					if out.ChangeInfo != nil {
+						p.submittedChanges = append(p.submittedChanges, aws.StringValue(out.ChangeInfo.Id))
+					}
 				}
 
 				if successfulChanges > 0 {
@@ -637,6 +657,11 @@ func (p *AWSProvider) submitChanges(ctx context.Context, changes Route53Changes,
 	return nil
 }
 
+// SubmittedChanges returns the ids of the change batches submitted by the provider.
+func (p *AWSProvider) SubmittedChanges() []string {
+	return p.submittedChanges
+}
+
 // newChanges returns a collection of Changes based on the given records and action.
 func (p *AWSProvider) newChanges(action string, endpoints []*endpoint.Endpoint) Route53Changes {
 	changes := make(Route53Changes, 0, len(endpoints))
@@ -744,6 +769,10 @@ func (p *AWSProvider) newChange(action string, ep *endpoint.Endpoint) (*Route53C
 		}
 		change.ResourceRecordSet.ResourceRecords = make([]*route53.ResourceRecord, len(ep.Targets))
 		for idx, val := range ep.Targets {
+			// the "." replacement of NAPTR records is removed with the trailing dot of targets read from Route53
+			if ep.RecordType == route53.RRTypeNaptr && strings.HasSuffix(strings.TrimSpace(val), `"`) {
+				val = strings.TrimSpace(val) + " ."
+			}
 			change.ResourceRecordSet.ResourceRecords[idx] = &route53.ResourceRecord{
 				Value: aws.String(val),
 			}
@@ -1050,7 +1079,7 @@ func cleanZoneID(id string) string {
 
 func (p *AWSProvider) SupportedRecordType(recordType string) bool {
 	switch recordType {
-	case "MX":
+	case "MX", route53.RRTypeNaptr:
 		return true
 	default:
 		return provider.SupportedRecordType(recordType)
@@ -314,6 +314,40 @@ func TestAWSZones(t *testing.T) {
 	}
 }
 
+func TestAWSStaticZones(t *testing.T) {
+	staticZones := map[string]*route53.HostedZone{
+		"/hostedzone/zone-1": {
+			Id:   aws.String("/hostedzone/zone-1"),
+			Name: aws.String("zone-1.ext-dns-test-2.teapot.zalan.do."),
+		},
+	}
+
+	for _, ti := range []struct {
+		msg           string
+		domainFilter  endpoint.DomainFilter
+		zoneIDFilter  provider.ZoneIDFilter
+		expectedZones map[string]*route53.HostedZone
+	}{
+		{"no filter", endpoint.DomainFilter{}, provider.NewZoneIDFilter([]string{}), staticZones},
+		{"zone id filter", endpoint.DomainFilter{}, provider.NewZoneIDFilter([]string{"zone-1"}), staticZones},
+		{"other zone id filter", endpoint.DomainFilter{}, provider.NewZoneIDFilter([]string{"zone-2"}), map[string]*route53.HostedZone{}},
+		{"other domain filter", endpoint.NewDomainFilter([]string{"ext-dns-test-3.teapot.zalan.do."}), provider.NewZoneIDFilter([]string{}), map[string]*route53.HostedZone{}},
+	} {
+		// the client is never used to list the hosted zones
+		p, err := NewAWSProvider(context.Background(), AWSConfig{
+			DomainFilter: ti.domainFilter,
+			ZoneIDFilter: ti.zoneIDFilter,
+			StaticZones:  staticZones,
+		}, nil)
+		require.NoError(t, err)
+
+		zones, err := p.Zones(context.Background())
+		require.NoError(t, err, ti.msg)
+
+		validateAWSZones(t, zones, ti.expectedZones)
+	}
+}
+
 func TestAWSRecordsFilter(t *testing.T) {
 	provider, _ := newAWSProvider(t, endpoint.DomainFilter{}, provider.ZoneIDFilter{}, provider.ZoneTypeFilter{}, false, false, nil)
 	domainFilter := provider.GetDomainFilter()
@@ -61,6 +61,7 @@ type AzureProvider struct {
 	ResourceGroup                  string
 	userAssignedIdentityClientID   string
 	zonesClient                    ZonesClient
+	staticZones                    []dns.Zone
 	RecordSetsClient               RecordSetsClient
 	TrafficManagerEndpointsClient  *armtrafficmanager.EndpointsClient
 	TrafficManagerGeographicClient *armtrafficmanager.GeographicHierarchiesClient
@@ -95,6 +96,7 @@ func NewAzureProviderFromConfig(ctx context.Context, azureConfig Config) (*Azure
 		zoneIDFilter:                   azureConfig.IDFilter,
 		DryRun:                         azureConfig.DryRun,
 		zonesClient:                    zonesClient,
+		staticZones:                    azureConfig.StaticZones,
 		RecordSetsClient:               recordSetsClient,
 		TrafficManagerEndpointsClient:  clientFactory.NewEndpointsClient(),
 		TrafficManagerGeographicClient: clientFactory.NewGeographicHierarchiesClient(),
@@ -190,6 +192,14 @@ func (p *AzureProvider) ApplyChanges(ctx context.Context, changes *plan.Changes)
 func (p *AzureProvider) Zones(ctx context.Context) ([]dns.Zone, error) {
 	p.logger.V(1).Info("retrieving azure DNS Zones for resource group", "resource group", p.ResourceGroup)
 	var zones []dns.Zone
+	if p.staticZones != nil {
+		for _, zone := range p.staticZones {
+			if p.DomainFilter.Match(*zone.Name) && p.zoneIDFilter.Match(*zone.ID) {
+				zones = append(zones, zone)
+			}
+		}
+		return zones, nil
+	}
 	pager := p.zonesClient.NewListByResourceGroupPager(p.ResourceGroup, &dns.ZonesClientListByResourceGroupOptions{Top: nil})
 	for pager.More() {
 		nextResult, err := pager.NextPage(ctx)
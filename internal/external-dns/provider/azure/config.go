@@ -27,6 +27,7 @@ import (
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
 	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	dns "github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/dns/armdns"
 	"github.com/go-logr/logr"
 	"gopkg.in/yaml.v2"
 
@@ -51,6 +52,10 @@ type Config struct {
 	IDFilter                     provider.ZoneIDFilter
 	DryRun                       bool
 	Transporter                  policy.Transporter
+	// ResourceManagerEndpoint overrides the resource manager endpoint of the configured cloud, e.g. to target an emulator
+	ResourceManagerEndpoint string
+	// StaticZones are used instead of listing the zones of the resource group if set
+	StaticZones []dns.Zone
 }
 
 func getConfig(configFile, resourceGroup, userAssignedIdentityClientID string) (*Config, error) {
@@ -82,6 +87,9 @@ func getCredentials(ctx context.Context, cfg Config) (azcore.TokenCredential, *a
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to get cloud configuration: %w", err)
 	}
+	if cfg.ResourceManagerEndpoint != "" {
+		cloudCfg = withResourceManagerEndpoint(cloudCfg, cfg.ResourceManagerEndpoint)
+	}
 	clientOpts := azcore.ClientOptions{
 		Cloud: cloudCfg,
 	}
@@ -168,3 +176,16 @@ func getCloudConfiguration(name string) (cloud.Configuration, error) {
 	}
 	return cloud.Configuration{}, fmt.Errorf("unknown cloud name: %s", name)
 }
+
+// withResourceManagerEndpoint returns a copy of the given cloud configuration with the resource manager endpoint replaced.
+func withResourceManagerEndpoint(cfg cloud.Configuration, endpoint string) cloud.Configuration {
+	services := make(map[cloud.ServiceName]cloud.ServiceConfiguration, len(cfg.Services))
+	for name, svc := range cfg.Services {
+		services[name] = svc
+	}
+	rm := services[cloud.ResourceManager]
+	rm.Endpoint = endpoint
+	services[cloud.ResourceManager] = rm
+	cfg.Services = services
+	return cfg
+}
@@ -44,3 +44,18 @@ func TestGetCloudConfiguration(t *testing.T) {
 		})
 	}
 }
+
+func TestWithResourceManagerEndpoint(t *testing.T) {
+	original := cloud.AzurePublic.Services[cloud.ResourceManager].Endpoint
+
+	cfg := withResourceManagerEndpoint(cloud.AzurePublic, "http://localhost:8080")
+	if got := cfg.Services[cloud.ResourceManager].Endpoint; got != "http://localhost:8080" {
+		t.Errorf("expected resource manager endpoint to be overridden, got %s", got)
+	}
+	if got := cfg.Services[cloud.ResourceManager].Audience; got != cloud.AzurePublic.Services[cloud.ResourceManager].Audience {
+		t.Errorf("expected resource manager audience to be retained, got %s", got)
+	}
+	if got := cloud.AzurePublic.Services[cloud.ResourceManager].Endpoint; got != original {
+		t.Errorf("expected the public cloud configuration to be unchanged, got %s", got)
+	}
+}
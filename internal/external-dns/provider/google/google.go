@@ -117,10 +117,14 @@ type GoogleProvider struct {
 	resourceRecordSetsClient resourceRecordSetsClientInterface
 	// A client for managing hosted zones
 	managedZonesClient managedZonesServiceInterface
+	// zones used instead of listing the managed zones, for credentials that can't list them
+	staticZones map[string]*dns.ManagedZone
 	// A client for managing change sets
 	changesClient changesServiceInterface
 	// The context parameter to be passed for gcloud API calls.
 	ctx context.Context
+	// ids of the changes submitted by this provider, as zone/id
+	submittedChanges []string
 
 	logger logr.Logger
 }
@@ -134,6 +138,8 @@ type GoogleConfig struct {
 	BatchChangeSize     int
 	BatchChangeInterval time.Duration
 	DryRun              bool
+	// StaticZones are used instead of listing the managed zones if set
+	StaticZones map[string]*dns.ManagedZone
 }
 
 // NewGoogleProvider initializes a new Google CloudDNS based Provider.
@@ -173,6 +179,7 @@ func NewGoogleProviderWithService(ctx context.Context, config GoogleConfig, dnsC
 		zoneIDFilter:             config.ZoneIDFilter,
 		resourceRecordSetsClient: resourceRecordSetsService{dnsClient.ResourceRecordSets},
 		managedZonesClient:       managedZonesService{dnsClient.ManagedZones},
+		staticZones:              config.StaticZones,
 		changesClient:            changesService{dnsClient.Changes},
 		ctx:                      ctx,
 	}
@@ -184,6 +191,15 @@ func NewGoogleProviderWithService(ctx context.Context, config GoogleConfig, dnsC
 func (p *GoogleProvider) Zones(ctx context.Context) (map[string]*dns.ManagedZone, error) {
 	zones := make(map[string]*dns.ManagedZone)
 
+	if p.staticZones != nil {
+		for name, zone := range p.staticZones {
+			if p.domainFilter.Match(zone.DnsName) && p.zoneIDFilter.Match(name) {
+				zones[name] = zone
+			}
+		}
+		return zones, nil
+	}
+
 	f := func(resp *dns.ManagedZonesListResponse) error {
 		for _, zone := range resp.ManagedZones {
 			if zone.PeeringConfig == nil {
@@ -309,6 +325,11 @@ func (p *GoogleProvider) newFilteredRecords(endpoints []*endpoint.Endpoint) []*d
 	return records
 }
 
+// SubmittedChanges returns the ids of the changes submitted by the provider, as zone/id.
+func (p *GoogleProvider) SubmittedChanges() []string {
+	return p.submittedChanges
+}
+
 // submitChange takes a zone and a Change and sends it to Google.
 func (p *GoogleProvider) submitChange(ctx context.Context, change *dns.Change) error {
 	if len(change.Additions) == 0 && len(change.Deletions) == 0 {
@@ -338,9 +359,13 @@ func (p *GoogleProvider) submitChange(ctx context.Context, change *dns.Change) e
 				continue
 			}
 
-			if _, err := p.changesClient.Create(p.project, zone, c).Do(); err != nil {
+			submitted, err := p.changesClient.Create(p.project, zone, c).Do()
+			if err != nil {
 				return err
 			}
+			if submitted != nil {
+				p.submittedChanges = append(p.submittedChanges, zone+"/"+submitted.Id)
+			}
 
 			time.Sleep(p.batchChangeInterval)
 		}
@@ -0,0 +1,47 @@
+package registry
+
+import (
+	"context"
+	"time"
+
+	"sigs.k8s.io/external-dns/endpoint"
+	"sigs.k8s.io/external-dns/provider"
+
+	"github.com/kuadrant/dns-operator/api/v1alpha1"
+)
+
+const (
+	// KuadrantTXTPrefix is the prefix of the names of the TXT records the operator writes for its endpoints
+	KuadrantTXTPrefix = "kuadrant-"
+	// KuadrantTXTSuffix is the suffix of the names of the TXT records the operator writes for its endpoints
+	KuadrantTXTSuffix = ""
+	// KuadrantWildcardReplacement is the label the wildcard of an endpoint name is replaced with in the names of its
+	// TXT records
+	KuadrantWildcardReplacement = "wildcard"
+)
+
+// ManagedRecordTypes returns the record types managed for a record with the given endpoints. TXT, NAPTR and TLSA
+// records are only managed for records that publish, or have published, endpoints of the type so other records never
+// plan changes to them in their zone
+func ManagedRecordTypes(endpoints ...[]*endpoint.Endpoint) []string {
+	recordTypes := []string{endpoint.RecordTypeA, endpoint.RecordTypeAAAA, endpoint.RecordTypeCNAME}
+	for _, recordType := range []string{endpoint.RecordTypeTXT, v1alpha1.RecordTypeNAPTR, v1alpha1.RecordTypeTLSA} {
+	search:
+		for _, eps := range endpoints {
+			for _, ep := range eps {
+				if ep.RecordType == recordType {
+					recordTypes = append(recordTypes, recordType)
+					break search
+				}
+			}
+		}
+	}
+	return recordTypes
+}
+
+// NewKuadrantTXTRegistry returns a TXTRegistry with the settings the operator publishes the endpoints of the given
+// owner with
+func NewKuadrantTXTRegistry(ctx context.Context, p provider.Provider, ownerID string, managedRecordTypes []string) (*TXTRegistry, error) {
+	return NewTXTRegistry(ctx, p, KuadrantTXTPrefix, KuadrantTXTSuffix, ownerID, time.Duration(0),
+		KuadrantWildcardReplacement, managedRecordTypes, nil, false, nil)
+}
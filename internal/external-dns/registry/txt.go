@@ -19,6 +19,8 @@ package registry
 import (
 	"context"
 	"errors"
+	"fmt"
+	"slices"
 	"strings"
 	"time"
 
@@ -27,13 +29,73 @@ import (
 	"sigs.k8s.io/external-dns/endpoint"
 	"sigs.k8s.io/external-dns/plan"
 	"sigs.k8s.io/external-dns/provider"
+
+	kuadrantPlan "github.com/kuadrant/dns-operator/internal/external-dns/plan"
 )
 
 const (
 	recordTemplate              = "%{record_type}"
-	providerSpecificForceUpdate = "txt/force-update"
+	providerSpecificForceUpdate = kuadrantPlan.ProviderSpecificForceUpdate
+
+	// ResourceUIDLabelKey is the name of the label that identifies the uid of the k8s resource that wrote the DNS name
+	ResourceUIDLabelKey = kuadrantPlan.ResourceUIDLabelKey
+
+	// adoptedOwnerLabelKey is the name of the label holding the owner of an adopted endpoint in its TXT record, it is
+	// never written to a TXT record
+	adoptedOwnerLabelKey = "adopted-owner"
+
+	// renamedOwnerLabelKey is the name of the label holding the owners of an endpoint in its TXT record before the
+	// previous owner id of this instance was renamed, it is never written to a TXT record
+	renamedOwnerLabelKey = "renamed-owner"
+)
+
+// TXTFormat is the format of the names of the TXT records written by the registry
+type TXTFormat string
+
+const (
+	// TXTFormatNew TXT records are only written with the record type in their name, the format introduced in
+	// external-dns v0.12.0
+	TXTFormatNew TXTFormat = "new"
+	// TXTFormatBoth the TXT records of A and CNAME endpoints are also written without the record type in their name,
+	// for zones shared with external-dns instances that only read that format
+	TXTFormatBoth TXTFormat = "both"
 )
 
+// ErrUnknownTXTFormat is returned for TXT registry formats that are not TXTFormatNew or TXTFormatBoth.
+var ErrUnknownTXTFormat = errors.New("unknown TXT registry format")
+
+// ParseTXTFormat returns the TXTFormat for the given value, TXTFormatNew if it is empty, or an error if it is unknown.
+func ParseTXTFormat(value string) (TXTFormat, error) {
+	switch format := TXTFormat(value); format {
+	case "":
+		return TXTFormatNew, nil
+	case TXTFormatNew, TXTFormatBoth:
+		return format, nil
+	}
+	return "", fmt.Errorf("%w %q, must be one of %q or %q", ErrUnknownTXTFormat, value, TXTFormatNew, TXTFormatBoth)
+}
+
+// OwnerAdoption identifies endpoints published by other external-dns instances that are adopted by this instance
+type OwnerAdoption struct {
+	// Owners are the owner ids of the external-dns instances the endpoints are adopted from
+	Owners []string
+	// TXTPrefix and TXTSuffix are the affixes of the TXT records of the external-dns instances
+	TXTPrefix string
+	TXTSuffix string
+	// DNSNames are the names of the endpoints that are adopted
+	DNSNames []string
+	// BatchSize is the maximum number of adopted endpoints that have their TXT records rewritten at a time
+	BatchSize int
+}
+
+// ZoneRecordCounts is the number of records in the zone of the endpoints of a registry.
+type ZoneRecordCounts struct {
+	// Records is the number of records of the zone
+	Records int
+	// Registry is the number of the records that are registry TXT records, of any owner and in any format
+	Registry int
+}
+
 // TXTRegistry implements registry interface with ownership implemented via associated TXT records
 type TXTRegistry struct {
 	provider provider.Provider
@@ -57,6 +119,30 @@ type TXTRegistry struct {
 	txtEncryptEnabled bool
 	txtEncryptAESKey  []byte
 
+	// labels identifying the resource this instance is writing records for, added to the TXT records of
+	// created and updated endpoints so they can be attributed back to the resource
+	resourceLabels endpoint.Labels
+
+	// optional provider and domain of a separate zone the TXT records are written to, with the domain of the zone
+	// appended to their names, instead of the zone of the endpoints
+	registryProvider provider.Provider
+	registryDomain   string
+
+	// optional adoption of endpoints owned by other external-dns instances, and the mapper for their TXT records
+	adoption       *OwnerAdoption
+	adoptionMapper nameMapper
+
+	// format of the TXT records written, and the owners of the TXT records without the record type in their name
+	// read from the zone. TXT records in that format are only changed when they are owned by this instance.
+	txtFormat       TXTFormat
+	legacyTXTOwners map[endpoint.EndpointKey]string
+
+	// optional owner id this instance had before its current one, renamed to the current one in the TXT records
+	previousOwnerID string
+
+	// the number of records in the zone of the endpoints at the last read of the zone
+	zoneRecordCounts ZoneRecordCounts
+
 	logger logr.Logger
 }
 
@@ -94,12 +180,13 @@ func NewTXTRegistry(ctx context.Context, provider provider.Provider, txtPrefix,
 		excludeRecordTypes:  excludeRecordTypes,
 		txtEncryptEnabled:   txtEncryptEnabled,
 		txtEncryptAESKey:    txtEncryptAESKey,
+		txtFormat:           TXTFormatNew,
 		logger:              logger,
 	}, nil
 }
 
 func getSupportedTypes() []string {
-	return []string{endpoint.RecordTypeA, endpoint.RecordTypeAAAA, endpoint.RecordTypeCNAME, endpoint.RecordTypeNS}
+	return []string{endpoint.RecordTypeA, endpoint.RecordTypeAAAA, endpoint.RecordTypeCNAME, endpoint.RecordTypeNS, endpoint.RecordTypeTXT}
 }
 
 func (im *TXTRegistry) GetDomainFilter() endpoint.DomainFilter {
@@ -110,6 +197,142 @@ func (im *TXTRegistry) OwnerID() string {
 	return im.ownerID
 }
 
+// WithResourceLabels sets the labels identifying the resource this instance is writing records for.
+// The labels are added to the TXT records of created and updated endpoints owned by this instance.
+func (im *TXTRegistry) WithResourceLabels(labels endpoint.Labels) *TXTRegistry {
+	im.resourceLabels = labels
+	return im
+}
+
+// addResourceLabels adds the resource labels of this instance to the given endpoint
+func (im *TXTRegistry) addResourceLabels(ep *endpoint.Endpoint) {
+	for k, v := range im.resourceLabels {
+		ep.Labels[k] = v
+	}
+}
+
+// WithRegistryZone sets a separate zone the TXT records are written to instead of the zone of the endpoints.
+// The TXT record names are the names they would have in the zone of the endpoints with the domain of the registry zone
+// appended, so owners can be mapped to endpoints in the same way.
+func (im *TXTRegistry) WithRegistryZone(registryProvider provider.Provider, domain string) *TXTRegistry {
+	im.registryProvider = registryProvider
+	im.registryDomain = strings.Trim(domain, ".")
+	return im
+}
+
+// WithOwnerAdoption sets the endpoints owned by other external-dns instances that are adopted by this instance.
+// Adopted endpoints are returned as owned by this instance, and up to the batch size of them are marked to be updated
+// so their TXT records are replaced by TXT records of this instance.
+func (im *TXTRegistry) WithOwnerAdoption(adoption OwnerAdoption) *TXTRegistry {
+	im.adoption = &adoption
+	im.adoptionMapper = newaffixNameMapper(adoption.TXTPrefix, adoption.TXTSuffix, im.wildcardReplacement)
+	return im
+}
+
+// WithOwnerRename sets the owner id this instance had before its current one. Endpoints owned by the previous owner id
+// are returned as owned by the current one, and marked to be updated so the previous owner id is replaced in their TXT
+// records.
+func (im *TXTRegistry) WithOwnerRename(previousOwnerID string) *TXTRegistry {
+	im.previousOwnerID = previousOwnerID
+	return im
+}
+
+// WithTXTFormat sets the format of the TXT records written by the registry. TXT records are read in every format.
+func (im *TXTRegistry) WithTXTFormat(format TXTFormat) *TXTRegistry {
+	im.txtFormat = format
+	return im
+}
+
+// adoptedLabels returns the labels of the TXT record with the given key if it is the TXT record of an adopted endpoint
+func (im *TXTRegistry) adoptedLabels(ep *endpoint.Endpoint, key endpoint.EndpointKey, labelMap map[endpoint.EndpointKey]endpoint.Labels) (endpoint.Labels, bool) {
+	if im.adoption == nil || !slices.Contains(im.adoption.DNSNames, ep.DNSName) {
+		return nil, false
+	}
+	labels, exists := labelMap[key]
+	if !exists || !slices.Contains(im.adoption.Owners, labels[endpoint.OwnerLabelKey]) {
+		return nil, false
+	}
+	return labels, true
+}
+
+// renameOwner replaces the previous owner id of this instance with the current one in the owners of the endpoint,
+// keeping the owners of its TXT record. Returns false if the endpoint is not owned by the previous owner id.
+func (im *TXTRegistry) renameOwner(ep *endpoint.Endpoint) bool {
+	if im.previousOwnerID == "" || im.previousOwnerID == im.ownerID || !isOwnedBy(ep, im.previousOwnerID) {
+		return false
+	}
+	owners := strings.Split(ep.Labels[endpoint.OwnerLabelKey], kuadrantPlan.OwnerLabelDeliminator)
+	ep.Labels[renamedOwnerLabelKey] = ep.Labels[endpoint.OwnerLabelKey]
+	for i, owner := range owners {
+		if owner == im.previousOwnerID {
+			owners[i] = im.ownerID
+		}
+	}
+	slices.Sort(owners)
+	ep.Labels[endpoint.OwnerLabelKey] = strings.Join(slices.Compact(owners), kuadrantPlan.OwnerLabelDeliminator)
+	return true
+}
+
+// withTXTOwners returns the endpoint with the owners of its TXT record in the zone, which differ from its owners if the
+// owner id of this instance was renamed in them
+func withTXTOwners(ep *endpoint.Endpoint) *endpoint.Endpoint {
+	owners, renamed := ep.Labels[renamedOwnerLabelKey]
+	if !renamed {
+		return ep
+	}
+	txtOwners := *ep
+	txtOwners.Labels = endpoint.Labels{}
+	for k, v := range ep.Labels {
+		txtOwners.Labels[k] = v
+	}
+	txtOwners.Labels[endpoint.OwnerLabelKey] = owners
+	delete(txtOwners.Labels, renamedOwnerLabelKey)
+	return &txtOwners
+}
+
+// isAdopted returns true if the TXT record of the given endpoint is still the TXT record of the owner it was adopted from
+func isAdopted(ep *endpoint.Endpoint) bool {
+	_, adopted := ep.Labels[adoptedOwnerLabelKey]
+	return adopted
+}
+
+// toRegistryZoneName returns the name of the TXT record with the given name in the registry zone
+func (im *TXTRegistry) toRegistryZoneName(txtDNSName string) string {
+	return txtDNSName + "." + im.registryDomain
+}
+
+// fromRegistryZoneName returns the name the TXT record with the given name in the registry zone would have in the zone
+// of the endpoints, or false if the name is not in the registry zone.
+func (im *TXTRegistry) fromRegistryZoneName(dnsName string) (string, bool) {
+	return strings.CutSuffix(dnsName, "."+im.registryDomain)
+}
+
+// registryZoneRecords returns the TXT records in the registry zone, renamed to the names they would have in the zone
+// of the endpoints.
+func (im *TXTRegistry) registryZoneRecords(ctx context.Context) ([]*endpoint.Endpoint, error) {
+	records, err := im.registryProvider.Records(ctx)
+	if err != nil {
+		return nil, err
+	}
+	txtRecords := []*endpoint.Endpoint{}
+	for _, record := range records {
+		if record.RecordType != endpoint.RecordTypeTXT {
+			continue
+		}
+		if name, ok := im.fromRegistryZoneName(record.DNSName); ok {
+			txtRecord := record.DeepCopy()
+			txtRecord.DNSName = name
+			txtRecords = append(txtRecords, txtRecord)
+		}
+	}
+	return txtRecords, nil
+}
+
+// isOwnedBy returns true if the given owner is one of the owners of the given endpoint
+func isOwnedBy(ep *endpoint.Endpoint, ownerID string) bool {
+	return slices.Contains(strings.Split(ep.Labels[endpoint.OwnerLabelKey], kuadrantPlan.OwnerLabelDeliminator), ownerID)
+}
+
 // Records returns the current records from the registry excluding TXT Records
 // If TXT records was created previously to indicate ownership its corresponding value
 // will be added to the endpoints Labels map
@@ -129,7 +352,32 @@ func (im *TXTRegistry) Records(ctx context.Context) ([]*endpoint.Endpoint, error
 	endpoints := []*endpoint.Endpoint{}
 
 	labelMap := map[endpoint.EndpointKey]endpoint.Labels{}
+	adoptedLabelMap := map[endpoint.EndpointKey]endpoint.Labels{}
 	txtRecordsMap := map[string]struct{}{}
+	im.legacyTXTOwners = map[endpoint.EndpointKey]string{}
+	im.zoneRecordCounts = ZoneRecordCounts{Records: len(records)}
+	rewrites := 0
+
+	// when the TXT records are in a separate zone all records in the zone of the endpoints are endpoints
+	if im.registryProvider != nil {
+		endpoints = append(endpoints, records...)
+		if records, err = im.registryZoneRecords(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	// registry TXT records of other owners that can't be read, e.g. encrypted with another key, are recognised by their
+	// name when counting the records of the zone
+	recordKeys := map[endpoint.EndpointKey]struct{}{}
+	for _, record := range records {
+		if record.RecordType != endpoint.RecordTypeTXT {
+			dnsName := strings.ToLower(record.DNSName)
+			if im.wildcardReplacement != "" && strings.HasPrefix(dnsName, "*.") {
+				dnsName = im.wildcardReplacement + dnsName[1:]
+			}
+			recordKeys[endpoint.EndpointKey{DNSName: dnsName, RecordType: record.RecordType}] = struct{}{}
+		}
+	}
 
 	for _, record := range records {
 		if record.RecordType != endpoint.RecordTypeTXT {
@@ -142,12 +390,22 @@ func (im *TXTRegistry) Records(ctx context.Context) ([]*endpoint.Endpoint, error
 			// if no heritage is found or it is invalid
 			// case when value of txt record cannot be identified
 			// record will not be removed as it will have empty owner
-			endpoints = append(endpoints, record)
+			if im.registryProvider == nil {
+				endpoints = append(endpoints, record)
+				if endpointName, recordType := im.mapper.toEndpointName(record.DNSName); recordType != "" {
+					if _, ok := recordKeys[endpoint.EndpointKey{DNSName: endpointName, RecordType: recordType}]; ok {
+						im.zoneRecordCounts.Registry++
+					}
+				}
+			}
 			continue
 		}
 		if err != nil {
 			return nil, err
 		}
+		if im.registryProvider == nil {
+			im.zoneRecordCounts.Registry++
+		}
 
 		endpointName, recordType := im.mapper.toEndpointName(record.DNSName)
 		key := endpoint.EndpointKey{
@@ -157,6 +415,18 @@ func (im *TXTRegistry) Records(ctx context.Context) ([]*endpoint.Endpoint, error
 		}
 		labelMap[key] = labels
 		txtRecordsMap[record.DNSName] = struct{}{}
+		if recordType == "" {
+			im.legacyTXTOwners[record.Key()] = labels[endpoint.OwnerLabelKey]
+		}
+
+		if im.adoption != nil {
+			endpointName, recordType = im.adoptionMapper.toEndpointName(record.DNSName)
+			adoptedLabelMap[endpoint.EndpointKey{
+				DNSName:       endpointName,
+				RecordType:    recordType,
+				SetIdentifier: record.SetIdentifier,
+			}] = labels
+		}
 	}
 
 	for _, ep := range endpoints {
@@ -180,6 +450,8 @@ func (im *TXTRegistry) Records(ctx context.Context) ([]*endpoint.Endpoint, error
 			key.RecordType = endpoint.RecordTypeCNAME
 		}
 
+		adoptionKey := key
+
 		// Handle both new and old registry format with the preference for the new one
 		labels, labelsExist := labelMap[key]
 		if !labelsExist && ep.RecordType != endpoint.RecordTypeAAAA {
@@ -190,6 +462,23 @@ func (im *TXTRegistry) Records(ctx context.Context) ([]*endpoint.Endpoint, error
 			for k, v := range labels {
 				ep.Labels[k] = v
 			}
+			// the TXT records are rewritten with the current owner id by the update
+			if im.renameOwner(ep) {
+				ep.WithProviderSpecific(providerSpecificForceUpdate, "true")
+				im.logger.V(1).Info("renaming owner of endpoint", "dnsName", ep.DNSName, "recordType", ep.RecordType, "owner", im.previousOwnerID)
+			}
+		} else if labels, adopted := im.adoptedLabels(ep, adoptionKey, adoptedLabelMap); adopted {
+			for k, v := range labels {
+				ep.Labels[k] = v
+			}
+			ep.Labels[adoptedOwnerLabelKey] = labels[endpoint.OwnerLabelKey]
+			ep.Labels[endpoint.OwnerLabelKey] = im.ownerID
+			// the TXT records of the batch are rewritten by the update, the rest are left for later updates
+			if rewrites < im.adoption.BatchSize {
+				ep.WithProviderSpecific(providerSpecificForceUpdate, "true")
+				rewrites++
+			}
+			im.logger.V(1).Info("adopting endpoint", "dnsName", ep.DNSName, "recordType", ep.RecordType, "owner", labels[endpoint.OwnerLabelKey])
 		}
 
 		// Handle the migration of TXT records created before the new format (introduced in v0.12.0).
@@ -203,6 +492,9 @@ func (im *TXTRegistry) Records(ctx context.Context) ([]*endpoint.Endpoint, error
 						ep.WithProviderSpecific(providerSpecificForceUpdate, "true")
 					}
 				}
+				if txt := im.generateLegacyTXTRecord(ep); txt != nil && !im.hasLegacyTXTRecord(txt) {
+					ep.WithProviderSpecific(providerSpecificForceUpdate, "true")
+				}
 			}
 		}
 	}
@@ -216,11 +508,21 @@ func (im *TXTRegistry) Records(ctx context.Context) ([]*endpoint.Endpoint, error
 	return endpoints, nil
 }
 
+// ZoneRecordCounts returns the number of records in the zone of the endpoints at the last read of the zone. The registry
+// TXT records are only counted when they are in the zone of the endpoints.
+func (im *TXTRegistry) ZoneRecordCounts() ZoneRecordCounts {
+	return im.zoneRecordCounts
+}
+
 // generateTXTRecord generates both "old" and "new" TXT records.
 // Once we decide to drop old format we need to drop toTXTName() and rename toNewTXTName
 func (im *TXTRegistry) generateTXTRecord(r *endpoint.Endpoint) []*endpoint.Endpoint {
 	endpoints := make([]*endpoint.Endpoint, 0)
 
+	if isAdopted(r) {
+		return im.generateAdoptedTXTRecord(r)
+	}
+
 	//mnairn: Seems to be no way to prevent the creation of the old format TXT records other than removing the code
 	//if !im.txtEncryptEnabled && !im.mapper.recordTypeInAffix() && r.RecordType != endpoint.RecordTypeAAAA {
 	//	// old TXT record format
@@ -249,6 +551,60 @@ func (im *TXTRegistry) generateTXTRecord(r *endpoint.Endpoint) []*endpoint.Endpo
 	return endpoints
 }
 
+// generateLegacyTXTRecord generates the TXT record of the given endpoint without the record type in its name, or nil
+// if the registry does not write that format for the endpoint. The format can't tell the record types of a name
+// apart, it is only written for A and CNAME endpoints as older external-dns releases did.
+func (im *TXTRegistry) generateLegacyTXTRecord(r *endpoint.Endpoint) *endpoint.Endpoint {
+	if im.txtFormat != TXTFormatBoth || im.txtEncryptEnabled || im.mapper.recordTypeInAffix() || isAdopted(r) ||
+		(r.RecordType != endpoint.RecordTypeA && r.RecordType != endpoint.RecordTypeCNAME) {
+		return nil
+	}
+	txt := endpoint.NewEndpoint(im.mapper.toTXTName(r.DNSName), endpoint.RecordTypeTXT, r.Labels.Serialize(true, im.txtEncryptEnabled, im.txtEncryptAESKey))
+	if txt == nil {
+		return nil
+	}
+	txt.WithSetIdentifier(r.SetIdentifier)
+	txt.Labels[endpoint.OwnedRecordLabelKey] = r.DNSName
+	txt.ProviderSpecific = r.ProviderSpecific
+	return txt
+}
+
+// hasLegacyTXTRecord returns true if the given TXT record without the record type in its name was read from the zone
+func (im *TXTRegistry) hasLegacyTXTRecord(txt *endpoint.Endpoint) bool {
+	_, exists := im.legacyTXTOwners[txt.Key()]
+	return exists
+}
+
+// ownsLegacyTXTRecord returns true if the given TXT record without the record type in its name was read from the zone
+// and is owned by this instance. Records in that format written by other tools are never changed.
+func (im *TXTRegistry) ownsLegacyTXTRecord(txt *endpoint.Endpoint) bool {
+	owners, exists := im.legacyTXTOwners[txt.Key()]
+	if !exists {
+		return false
+	}
+	ownerIDs := strings.Split(owners, kuadrantPlan.OwnerLabelDeliminator)
+	return slices.Contains(ownerIDs, im.ownerID) || (im.previousOwnerID != "" && slices.Contains(ownerIDs, im.previousOwnerID))
+}
+
+// generateAdoptedTXTRecord generates the TXT record of the owner an endpoint was adopted from
+func (im *TXTRegistry) generateAdoptedTXTRecord(r *endpoint.Endpoint) []*endpoint.Endpoint {
+	labels := endpoint.Labels{}
+	for k, v := range r.Labels {
+		labels[k] = v
+	}
+	labels[endpoint.OwnerLabelKey] = labels[adoptedOwnerLabelKey]
+	delete(labels, adoptedOwnerLabelKey)
+
+	txt := endpoint.NewEndpoint(im.adoptionMapper.toNewTXTName(r.DNSName, r.RecordType), endpoint.RecordTypeTXT, labels.Serialize(true, im.txtEncryptEnabled, im.txtEncryptAESKey))
+	if txt == nil {
+		return nil
+	}
+	txt.WithSetIdentifier(r.SetIdentifier)
+	txt.Labels[endpoint.OwnedRecordLabelKey] = r.DNSName
+	txt.ProviderSpecific = r.ProviderSpecific
+	return []*endpoint.Endpoint{txt}
+}
+
 // ApplyChanges updates dns provider with the changes
 // for each created/deleted record it will also take into account TXT records for creation/deletion
 func (im *TXTRegistry) ApplyChanges(ctx context.Context, changes *plan.Changes) error {
@@ -264,8 +620,12 @@ func (im *TXTRegistry) ApplyChanges(ctx context.Context, changes *plan.Changes)
 			r.Labels = make(map[string]string)
 		}
 		r.Labels[endpoint.OwnerLabelKey] = im.ownerID
+		im.addResourceLabels(r)
 
 		filteredChanges.Create = append(filteredChanges.Create, im.generateTXTRecord(r)...)
+		if txt := im.generateLegacyTXTRecord(r); txt != nil && !im.hasLegacyTXTRecord(txt) {
+			filteredChanges.Create = append(filteredChanges.Create, txt)
+		}
 
 		if im.cacheInterval > 0 {
 			im.addToCache(r)
@@ -276,18 +636,38 @@ func (im *TXTRegistry) ApplyChanges(ctx context.Context, changes *plan.Changes)
 		// when we delete TXT records for which value has changed (due to new label) this would still work because
 		// !!! TXT record value is uniquely generated from the Labels of the endpoint. Hence old TXT record can be uniquely reconstructed
 		// !!! After migration to the new TXT registry format we can drop records in old format here!!!
-		filteredChanges.Delete = append(filteredChanges.Delete, im.generateTXTRecord(r)...)
+		filteredChanges.Delete = append(filteredChanges.Delete, im.generateTXTRecord(withTXTOwners(r))...)
+		if txt := im.generateLegacyTXTRecord(withTXTOwners(r)); txt != nil && im.ownsLegacyTXTRecord(txt) {
+			filteredChanges.Delete = append(filteredChanges.Delete, txt)
+		}
 
 		if im.cacheInterval > 0 {
 			im.removeFromCache(r)
 		}
 	}
 
+	// the TXT records of adopted endpoints are replaced rather than updated, as they have different names
+	adopted := map[endpoint.EndpointKey]struct{}{}
+	// the TXT records without the record type in their name that are updated rather than created
+	legacyUpdated := map[endpoint.EndpointKey]struct{}{}
+
 	// make sure TXT records are consistently updated as well
 	for _, r := range filteredChanges.UpdateOld {
+		if isAdopted(r) {
+			adopted[r.Key()] = struct{}{}
+			filteredChanges.Delete = append(filteredChanges.Delete, im.generateTXTRecord(r)...)
+			if im.cacheInterval > 0 {
+				im.removeFromCache(r)
+			}
+			continue
+		}
 		// when we updateOld TXT records for which value has changed (due to new label) this would still work because
 		// !!! TXT record value is uniquely generated from the Labels of the endpoint. Hence old TXT record can be uniquely reconstructed
-		filteredChanges.UpdateOld = append(filteredChanges.UpdateOld, im.generateTXTRecord(r)...)
+		filteredChanges.UpdateOld = append(filteredChanges.UpdateOld, im.generateTXTRecord(withTXTOwners(r))...)
+		if txt := im.generateLegacyTXTRecord(withTXTOwners(r)); txt != nil && im.ownsLegacyTXTRecord(txt) {
+			legacyUpdated[r.Key()] = struct{}{}
+			filteredChanges.UpdateOld = append(filteredChanges.UpdateOld, txt)
+		}
 		// remove old version of record from cache
 		if im.cacheInterval > 0 {
 			im.removeFromCache(r)
@@ -296,7 +676,24 @@ func (im *TXTRegistry) ApplyChanges(ctx context.Context, changes *plan.Changes)
 
 	// make sure TXT records are consistently updated as well
 	for _, r := range filteredChanges.UpdateNew {
-		filteredChanges.UpdateNew = append(filteredChanges.UpdateNew, im.generateTXTRecord(r)...)
+		// only stamp records we still own, an update can also be the removal of this owner from a shared record
+		if r.Labels != nil && isOwnedBy(r, im.ownerID) {
+			im.addResourceLabels(r)
+		}
+		delete(r.Labels, renamedOwnerLabelKey)
+		if _, ok := adopted[r.Key()]; ok {
+			delete(r.Labels, adoptedOwnerLabelKey)
+			filteredChanges.Create = append(filteredChanges.Create, im.generateTXTRecord(r)...)
+		} else {
+			filteredChanges.UpdateNew = append(filteredChanges.UpdateNew, im.generateTXTRecord(r)...)
+		}
+		if txt := im.generateLegacyTXTRecord(r); txt != nil {
+			if _, ok := legacyUpdated[r.Key()]; ok {
+				filteredChanges.UpdateNew = append(filteredChanges.UpdateNew, txt)
+			} else if !im.hasLegacyTXTRecord(txt) {
+				filteredChanges.Create = append(filteredChanges.Create, txt)
+			}
+		}
 		// add new version of record to cache
 		if im.cacheInterval > 0 {
 			im.addToCache(r)
@@ -307,9 +704,55 @@ func (im *TXTRegistry) ApplyChanges(ctx context.Context, changes *plan.Changes)
 	if im.cacheInterval > 0 {
 		ctx = context.WithValue(ctx, provider.RecordsContextKey, nil)
 	}
+	if im.registryProvider != nil {
+		return im.applyRegistryZoneChanges(ctx, filteredChanges)
+	}
 	return im.provider.ApplyChanges(ctx, filteredChanges)
 }
 
+// applyRegistryZoneChanges applies the changes to the endpoints to the zone of the endpoints, and the changes to the
+// TXT records to the registry zone. Ownership is written before new or updated endpoints and removed after deleted
+// endpoints, so endpoints are never present without an owner.
+func (im *TXTRegistry) applyRegistryZoneChanges(ctx context.Context, changes *plan.Changes) error {
+	endpointChanges := &plan.Changes{}
+	ownershipChanges := &plan.Changes{}
+	releaseChanges := &plan.Changes{}
+
+	split := func(endpoints []*endpoint.Endpoint) (eps, txts []*endpoint.Endpoint) {
+		for _, ep := range endpoints {
+			if ep.RecordType == endpoint.RecordTypeTXT && ep.Labels[endpoint.OwnedRecordLabelKey] != "" {
+				txt := ep.DeepCopy()
+				txt.DNSName = im.toRegistryZoneName(ep.DNSName)
+				txts = append(txts, txt)
+			} else {
+				eps = append(eps, ep)
+			}
+		}
+		return eps, txts
+	}
+	endpointChanges.Create, ownershipChanges.Create = split(changes.Create)
+	endpointChanges.UpdateOld, ownershipChanges.UpdateOld = split(changes.UpdateOld)
+	endpointChanges.UpdateNew, ownershipChanges.UpdateNew = split(changes.UpdateNew)
+	endpointChanges.Delete, releaseChanges.Delete = split(changes.Delete)
+
+	for _, apply := range []struct {
+		provider provider.Provider
+		changes  *plan.Changes
+	}{
+		{im.registryProvider, ownershipChanges},
+		{im.provider, endpointChanges},
+		{im.registryProvider, releaseChanges},
+	} {
+		if !apply.changes.HasChanges() {
+			continue
+		}
+		if err := apply.provider.ApplyChanges(ctx, apply.changes); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // AdjustEndpoints modifies the endpoints as needed by the specific provider
 func (im *TXTRegistry) AdjustEndpoints(endpoints []*endpoint.Endpoint) ([]*endpoint.Endpoint, error) {
 	return im.provider.AdjustEndpoints(endpoints)
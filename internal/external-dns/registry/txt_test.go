@@ -1603,6 +1603,331 @@ func TestMultiClusterDifferentRecordTypeOwnership(t *testing.T) {
 	}
 }
 
+func TestTXTRegistryApplyChangesResourceLabels(t *testing.T) {
+	ctx := context.Background()
+	p := inmemory.NewInMemoryProvider()
+	p.CreateZone(testZone)
+	p.ApplyChanges(ctx, &plan.Changes{
+		Create: []*endpoint.Endpoint{
+			newEndpointWithOwner("shared.test-zone.example.org", "2.2.2.2", endpoint.RecordTypeA, ""),
+			newEndpointWithOwner("a-shared.test-zone.example.org", "\"heritage=external-dns,external-dns/owner=other\"", endpoint.RecordTypeTXT, ""),
+			newEndpointWithOwner("released.test-zone.example.org", "3.3.3.3", endpoint.RecordTypeA, ""),
+			newEndpointWithOwner("a-released.test-zone.example.org", "\"heritage=external-dns,external-dns/owner=other&&owner\"", endpoint.RecordTypeTXT, ""),
+		},
+	})
+
+	r, _ := NewTXTRegistry(ctx, p, "", "", "owner", time.Hour, "", []string{}, []string{}, false, nil)
+	r.WithResourceLabels(endpoint.Labels{
+		endpoint.ResourceLabelKey: "dnsrecord/default/foo",
+		ResourceUIDLabelKey:       "1234",
+	})
+
+	changes := &plan.Changes{
+		Create: []*endpoint.Endpoint{
+			newEndpointWithOwner("new-record-1.test-zone.example.org", "1.1.1.1", endpoint.RecordTypeA, ""),
+		},
+		UpdateOld: []*endpoint.Endpoint{
+			newEndpointWithOwner("shared.test-zone.example.org", "2.2.2.2", endpoint.RecordTypeA, "other"),
+			newEndpointWithOwner("released.test-zone.example.org", "3.3.3.3", endpoint.RecordTypeA, "other&&owner"),
+		},
+		UpdateNew: []*endpoint.Endpoint{
+			newEndpointWithOwner("shared.test-zone.example.org", "2.2.2.2", endpoint.RecordTypeA, "other&&owner"),
+			newEndpointWithOwner("released.test-zone.example.org", "3.3.3.3", endpoint.RecordTypeA, "other"),
+		},
+	}
+
+	p.OnApplyChanges = func(_ context.Context, got *plan.Changes) {
+		txtTargets := map[string]string{}
+		for _, ep := range append(got.Create, got.UpdateNew...) {
+			if ep.RecordType == endpoint.RecordTypeTXT {
+				txtTargets[ep.DNSName] = ep.Targets[0]
+			}
+		}
+		assert.Equal(t, map[string]string{
+			"a-new-record-1.test-zone.example.org": "\"heritage=external-dns,external-dns/owner=owner,external-dns/resource=dnsrecord/default/foo,external-dns/resource-uid=1234\"",
+			"a-shared.test-zone.example.org":       "\"heritage=external-dns,external-dns/owner=other&&owner,external-dns/resource=dnsrecord/default/foo,external-dns/resource-uid=1234\"",
+			"a-released.test-zone.example.org":     "\"heritage=external-dns,external-dns/owner=other\"",
+		}, txtTargets)
+	}
+	require.NoError(t, r.ApplyChanges(ctx, changes))
+}
+
+func TestTXTRegistryApplyChangesRegistryZone(t *testing.T) {
+	ctx := context.Background()
+	registryZone := "registry.example.net"
+	p := inmemory.NewInMemoryProvider()
+	p.CreateZone(testZone)
+	rp := inmemory.NewInMemoryProvider()
+	rp.CreateZone(registryZone)
+
+	r, _ := NewTXTRegistry(ctx, p, "", "", "owner", 0, "", []string{}, []string{}, false, nil)
+	r.WithRegistryZone(rp, registryZone)
+
+	require.NoError(t, r.ApplyChanges(ctx, &plan.Changes{
+		Create: []*endpoint.Endpoint{
+			newEndpointWithOwner("new-record-1.test-zone.example.org", "1.1.1.1", endpoint.RecordTypeA, ""),
+		},
+	}))
+
+	records, err := p.Records(ctx)
+	require.NoError(t, err)
+	assert.True(t, testutils.SameEndpoints(records, []*endpoint.Endpoint{
+		newEndpointWithOwner("new-record-1.test-zone.example.org", "1.1.1.1", endpoint.RecordTypeA, "owner"),
+	}), "no TXT records expected in the zone of the endpoints: %v", records)
+
+	registryRecords, err := rp.Records(ctx)
+	require.NoError(t, err)
+	assert.True(t, testutils.SameEndpoints(registryRecords, []*endpoint.Endpoint{
+		newEndpointWithOwnerAndOwnedRecord("a-new-record-1.test-zone.example.org.registry.example.net", "\"heritage=external-dns,external-dns/owner=owner\"", endpoint.RecordTypeTXT, "", "new-record-1.test-zone.example.org"),
+	}), "expected TXT records in the registry zone: %v", registryRecords)
+
+	endpoints, err := r.Records(ctx)
+	require.NoError(t, err)
+	require.Len(t, endpoints, 1)
+	assert.Equal(t, "owner", endpoints[0].Labels[endpoint.OwnerLabelKey])
+
+	require.NoError(t, r.ApplyChanges(ctx, &plan.Changes{
+		Delete: endpoints,
+	}))
+
+	records, err = p.Records(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, records)
+	registryRecords, err = rp.Records(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, registryRecords)
+}
+
+func TestTXTRegistryZoneRecordCounts(t *testing.T) {
+	ctx := context.Background()
+	p := inmemory.NewInMemoryProvider()
+	p.CreateZone(testZone)
+	p.ApplyChanges(ctx, &plan.Changes{
+		Create: []*endpoint.Endpoint{
+			newEndpointWithOwner("foo.test-zone.example.org", "1.1.1.1", endpoint.RecordTypeA, ""),
+			newEndpointWithOwner("a-foo.test-zone.example.org", "\"heritage=external-dns,external-dns/owner=owner\"", endpoint.RecordTypeTXT, ""),
+			newEndpointWithOwner("bar.test-zone.example.org", "2.2.2.2", endpoint.RecordTypeA, ""),
+			// registry record of another owner encrypted with another key
+			newEndpointWithOwner("a-bar.test-zone.example.org", "\"dGhpcyBpcyBub3QgcmVhZGFibGU=\"", endpoint.RecordTypeTXT, ""),
+			newEndpointWithOwner("test-zone.example.org", "\"v=spf1 -all\"", endpoint.RecordTypeTXT, ""),
+		},
+	})
+
+	r, _ := NewTXTRegistry(ctx, p, "", "", "owner", 0, "", []string{}, []string{}, false, nil)
+	_, err := r.Records(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, ZoneRecordCounts{Records: 5, Registry: 2}, r.ZoneRecordCounts())
+
+	registryZone := "registry.example.net"
+	rp := inmemory.NewInMemoryProvider()
+	rp.CreateZone(registryZone)
+	r, _ = NewTXTRegistry(ctx, p, "", "", "owner", 0, "", []string{}, []string{}, false, nil)
+	r.WithRegistryZone(rp, registryZone)
+	_, err = r.Records(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, ZoneRecordCounts{Records: 5}, r.ZoneRecordCounts())
+}
+
+func TestTXTRegistryOwnerAdoption(t *testing.T) {
+	ctx := context.Background()
+	p := inmemory.NewInMemoryProvider()
+	p.CreateZone(testZone)
+	require.NoError(t, p.ApplyChanges(ctx, &plan.Changes{
+		Create: []*endpoint.Endpoint{
+			newEndpointWithOwner("foo.test-zone.example.org", "1.1.1.1", endpoint.RecordTypeA, ""),
+			newEndpointWithOwner("a-foo.test-zone.example.org", "\"heritage=external-dns,external-dns/owner=external\"", endpoint.RecordTypeTXT, ""),
+			newEndpointWithOwner("bar.test-zone.example.org", "2.2.2.2", endpoint.RecordTypeA, ""),
+			newEndpointWithOwner("a-bar.test-zone.example.org", "\"heritage=external-dns,external-dns/owner=external\"", endpoint.RecordTypeTXT, ""),
+			newEndpointWithOwner("other.test-zone.example.org", "3.3.3.3", endpoint.RecordTypeA, ""),
+			newEndpointWithOwner("a-other.test-zone.example.org", "\"heritage=external-dns,external-dns/owner=someone-else\"", endpoint.RecordTypeTXT, ""),
+		},
+	}))
+
+	r, _ := NewTXTRegistry(ctx, p, "kuadrant-", "", "owner", 0, "", []string{}, []string{}, false, nil)
+	r.WithOwnerAdoption(OwnerAdoption{
+		Owners:    []string{"external"},
+		DNSNames:  []string{"foo.test-zone.example.org", "bar.test-zone.example.org", "other.test-zone.example.org"},
+		BatchSize: 1,
+	})
+
+	records, err := r.Records(ctx)
+	require.NoError(t, err)
+
+	owners := map[string]string{}
+	rewrites := []*endpoint.Endpoint{}
+	for _, ep := range records {
+		owners[ep.DNSName] = ep.Labels[endpoint.OwnerLabelKey]
+		if _, ok := ep.GetProviderSpecificProperty(providerSpecificForceUpdate); ok {
+			rewrites = append(rewrites, ep)
+		}
+	}
+	assert.Equal(t, map[string]string{
+		"foo.test-zone.example.org":   "owner",
+		"bar.test-zone.example.org":   "owner",
+		"other.test-zone.example.org": "",
+	}, owners)
+	require.Len(t, rewrites, 1, "only a batch of adopted endpoints is expected to be rewritten")
+
+	adopted := rewrites[0]
+	desired := adopted.DeepCopy()
+	desired.DeleteProviderSpecificProperty(providerSpecificForceUpdate)
+	desired.Labels = endpoint.Labels{endpoint.OwnerLabelKey: "owner"}
+	require.NoError(t, r.ApplyChanges(ctx, &plan.Changes{
+		UpdateOld: []*endpoint.Endpoint{adopted},
+		UpdateNew: []*endpoint.Endpoint{desired},
+	}))
+
+	zoneRecords, err := p.Records(ctx)
+	require.NoError(t, err)
+	txtTargets := map[string]string{}
+	for _, ep := range zoneRecords {
+		if ep.RecordType == endpoint.RecordTypeTXT {
+			txtTargets[ep.DNSName] = ep.Targets[0]
+		}
+	}
+	rewritten := strings.TrimSuffix(adopted.DNSName, "."+testZone)
+	remaining := "foo"
+	if rewritten == "foo" {
+		remaining = "bar"
+	}
+	assert.Equal(t, map[string]string{
+		"kuadrant-a-" + rewritten + ".test-zone.example.org": "\"heritage=external-dns,external-dns/owner=owner\"",
+		"a-" + remaining + ".test-zone.example.org":          "\"heritage=external-dns,external-dns/owner=external\"",
+		"a-other.test-zone.example.org":                      "\"heritage=external-dns,external-dns/owner=someone-else\"",
+	}, txtTargets)
+}
+
+func TestTXTRegistryOwnerRename(t *testing.T) {
+	ctx := context.Background()
+	p := inmemory.NewInMemoryProvider()
+	p.CreateZone(testZone)
+	require.NoError(t, p.ApplyChanges(ctx, &plan.Changes{
+		Create: []*endpoint.Endpoint{
+			newEndpointWithOwner("foo.test-zone.example.org", "1.1.1.1", endpoint.RecordTypeA, ""),
+			newEndpointWithOwner("kuadrant-a-foo.test-zone.example.org", "\"heritage=external-dns,external-dns/owner=previous\"", endpoint.RecordTypeTXT, ""),
+			newEndpointWithOwner("bar.test-zone.example.org", "2.2.2.2", endpoint.RecordTypeA, ""),
+			newEndpointWithOwner("kuadrant-a-bar.test-zone.example.org", "\"heritage=external-dns,external-dns/owner=previous\"", endpoint.RecordTypeTXT, ""),
+			newEndpointWithOwner("baz.test-zone.example.org", "3.3.3.3", endpoint.RecordTypeA, ""),
+			newEndpointWithOwner("kuadrant-a-baz.test-zone.example.org", "\"heritage=external-dns,external-dns/owner=other&&previous\"", endpoint.RecordTypeTXT, ""),
+			newEndpointWithOwner("other.test-zone.example.org", "4.4.4.4", endpoint.RecordTypeA, ""),
+			newEndpointWithOwner("kuadrant-a-other.test-zone.example.org", "\"heritage=external-dns,external-dns/owner=other\"", endpoint.RecordTypeTXT, ""),
+		},
+	}))
+
+	r, _ := NewTXTRegistry(ctx, p, "kuadrant-", "", "owner", 0, "", []string{}, []string{}, false, nil)
+	r.WithOwnerRename("previous")
+
+	records, err := r.Records(ctx)
+	require.NoError(t, err)
+
+	owners := map[string]string{}
+	byName := map[string]*endpoint.Endpoint{}
+	for _, ep := range records {
+		owners[ep.DNSName] = ep.Labels[endpoint.OwnerLabelKey]
+		byName[ep.DNSName] = ep
+		_, forced := ep.GetProviderSpecificProperty(providerSpecificForceUpdate)
+		assert.Equal(t, ep.DNSName != "other.test-zone.example.org", forced, "unexpected update of %s", ep.DNSName)
+	}
+	assert.Equal(t, map[string]string{
+		"foo.test-zone.example.org":   "owner",
+		"bar.test-zone.example.org":   "owner",
+		"baz.test-zone.example.org":   "other&&owner",
+		"other.test-zone.example.org": "other",
+	}, owners)
+
+	// the TXT records of the previous owner are found to be updated and deleted
+	renamed := byName["foo.test-zone.example.org"]
+	desired := renamed.DeepCopy()
+	desired.DeleteProviderSpecificProperty(providerSpecificForceUpdate)
+	require.NoError(t, r.ApplyChanges(ctx, &plan.Changes{
+		UpdateOld: []*endpoint.Endpoint{renamed},
+		UpdateNew: []*endpoint.Endpoint{desired},
+		Delete:    []*endpoint.Endpoint{byName["bar.test-zone.example.org"]},
+	}))
+	_, exists := desired.Labels[renamedOwnerLabelKey]
+	assert.False(t, exists, "the previous owners are never written")
+
+	zoneRecords, err := p.Records(ctx)
+	require.NoError(t, err)
+	txtTargets := map[string]string{}
+	for _, ep := range zoneRecords {
+		if ep.RecordType == endpoint.RecordTypeTXT {
+			txtTargets[ep.DNSName] = ep.Targets[0]
+		}
+	}
+	assert.Equal(t, map[string]string{
+		"kuadrant-a-foo.test-zone.example.org":   "\"heritage=external-dns,external-dns/owner=owner\"",
+		"kuadrant-a-baz.test-zone.example.org":   "\"heritage=external-dns,external-dns/owner=other&&previous\"",
+		"kuadrant-a-other.test-zone.example.org": "\"heritage=external-dns,external-dns/owner=other\"",
+	}, txtTargets)
+}
+
+func TestTXTRegistryBothFormats(t *testing.T) {
+	ctx := context.Background()
+	p := inmemory.NewInMemoryProvider()
+	p.CreateZone(testZone)
+	require.NoError(t, p.ApplyChanges(ctx, &plan.Changes{
+		Create: []*endpoint.Endpoint{
+			newEndpointWithOwner("bar.test-zone.example.org", "2.2.2.2", endpoint.RecordTypeA, ""),
+			newEndpointWithOwner("kuadrant-a-bar.test-zone.example.org", "\"heritage=external-dns,external-dns/owner=owner\"", endpoint.RecordTypeTXT, ""),
+			newEndpointWithOwner("kuadrant-bar.test-zone.example.org", "\"heritage=external-dns,external-dns/owner=owner\"", endpoint.RecordTypeTXT, ""),
+			newEndpointWithOwner("baz.test-zone.example.org", "3.3.3.3", endpoint.RecordTypeA, ""),
+			newEndpointWithOwner("kuadrant-a-baz.test-zone.example.org", "\"heritage=external-dns,external-dns/owner=owner\"", endpoint.RecordTypeTXT, ""),
+			newEndpointWithOwner("kuadrant-baz.test-zone.example.org", "\"heritage=external-dns,external-dns/owner=external\"", endpoint.RecordTypeTXT, ""),
+		},
+	}))
+
+	format, err := ParseTXTFormat("both")
+	require.NoError(t, err)
+	r, _ := NewTXTRegistry(ctx, p, "kuadrant-", "", "owner", 0, "", []string{}, []string{}, false, nil)
+	r.WithTXTFormat(format)
+
+	records, err := r.Records(ctx)
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	for _, ep := range records {
+		assert.Equal(t, "owner", ep.Labels[endpoint.OwnerLabelKey])
+		_, forced := ep.GetProviderSpecificProperty(providerSpecificForceUpdate)
+		assert.False(t, forced, "no TXT records are expected to be missing for %s", ep.DNSName)
+	}
+
+	// both formats are written for created endpoints, only the TXT records of this owner are deleted
+	require.NoError(t, r.ApplyChanges(ctx, &plan.Changes{
+		Create: []*endpoint.Endpoint{newEndpointWithOwner("foo.test-zone.example.org", "1.1.1.1", endpoint.RecordTypeA, "")},
+		Delete: records,
+	}))
+	txtRecords := func() []string {
+		zoneRecords, err := p.Records(ctx)
+		require.NoError(t, err)
+		names := []string{}
+		for _, ep := range zoneRecords {
+			if ep.RecordType == endpoint.RecordTypeTXT {
+				names = append(names, ep.DNSName)
+			}
+		}
+		return names
+	}
+	assert.ElementsMatch(t, []string{
+		"kuadrant-a-foo.test-zone.example.org",
+		"kuadrant-foo.test-zone.example.org",
+		"kuadrant-baz.test-zone.example.org",
+	}, txtRecords())
+
+	// TXT records without the record type are left when the registry only writes the new format
+	r, _ = NewTXTRegistry(ctx, p, "kuadrant-", "", "owner", 0, "", []string{}, []string{}, false, nil)
+	records, err = r.Records(ctx)
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	require.NoError(t, r.ApplyChanges(ctx, &plan.Changes{Delete: records}))
+	assert.ElementsMatch(t, []string{
+		"kuadrant-foo.test-zone.example.org",
+		"kuadrant-baz.test-zone.example.org",
+	}, txtRecords())
+
+	_, err = ParseTXTFormat("v3")
+	assert.ErrorIs(t, err, ErrUnknownTXTFormat)
+}
+
 /**
 
 helper methods
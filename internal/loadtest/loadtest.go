@@ -0,0 +1,350 @@
+// Package loadtest drives synthetic DNSRecords through the full reconcile path of the DNSRecord reconciler against the
+// inmemory provider, and reports the latency and allocations of each phase of the reconciles, so performance
+// regressions in the planner and registry are caught before release. The records are stored in a fake client, only
+// the reconciler and the provider are measured.
+package loadtest
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"runtime/pprof"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sruntime "k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	externaldnsendpoint "sigs.k8s.io/external-dns/endpoint"
+
+	"github.com/kuadrant/dns-operator/api/v1alpha1"
+	"github.com/kuadrant/dns-operator/internal/controller"
+	"github.com/kuadrant/dns-operator/internal/provider"
+	_ "github.com/kuadrant/dns-operator/internal/provider/inmemory"
+	"github.com/kuadrant/dns-operator/pkg/builder"
+)
+
+const (
+	namespace          = "loadtest"
+	providerSecretName = "inmemory-credentials"
+	// maxReconcilesToSettle is the number of reconciles a record can take to settle after a change before the run
+	// fails, a record settles in two reconciles when created and one otherwise
+	maxReconcilesToSettle = 5
+
+	DefaultRecords            = 1000
+	DefaultEndpointsPerRecord = 4
+	DefaultRounds             = 3
+	DefaultDomain             = "loadtest.example.com"
+)
+
+// Config is the load a run drives through the reconciler.
+type Config struct {
+	// Records is the number of DNSRecords, defaults to DefaultRecords
+	Records int
+	// EndpointsPerRecord is the number of A endpoints of each record, defaults to DefaultEndpointsPerRecord
+	EndpointsPerRecord int
+	// Rounds is the number of soak rounds, every round changes the targets of every record then validates every record
+	// with a reconcile without changes, defaults to DefaultRounds
+	Rounds int
+	// Domain is the zone the records are published to, defaults to DefaultDomain
+	Domain string
+	// ProviderLatency is added to every read and write of the provider
+	ProviderLatency time.Duration
+}
+
+func (c *Config) setDefaults() {
+	if c.Records <= 0 {
+		c.Records = DefaultRecords
+	}
+	if c.EndpointsPerRecord <= 0 {
+		c.EndpointsPerRecord = DefaultEndpointsPerRecord
+	}
+	if c.Rounds < 0 {
+		c.Rounds = 0
+	} else if c.Rounds == 0 {
+		c.Rounds = DefaultRounds
+	}
+	if c.Domain == "" {
+		c.Domain = DefaultDomain
+	}
+}
+
+// Run creates the records of the config, runs the soak rounds and deletes the records, reconciling every record after
+// each step until it has settled. Records are reconciled one at a time, as by the operator, so the allocations of a
+// phase are those of the phase alone. Phases are labelled with their name in CPU profiles taken during the run.
+func Run(ctx context.Context, config Config) (*Report, error) {
+	config.setDefaults()
+	scheme := k8sruntime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		return nil, err
+	}
+	if err := v1alpha1.AddToScheme(scheme); err != nil {
+		return nil, err
+	}
+
+	secret := builder.NewProviderBuilder(providerSecretName, namespace).
+		For(v1alpha1.SecretTypeKuadrantInmemory).
+		WithZonesInitialisedFor(config.Domain).
+		WithLatency(config.ProviderLatency).
+		Build()
+	// the fake client doesn't convert string data as the API server does
+	secret.Data = map[string][]byte{}
+	for key, value := range secret.StringData {
+		secret.Data[key] = []byte(value)
+	}
+	secret.StringData = nil
+
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: namespace}}, secret).
+		WithStatusSubresource(&v1alpha1.DNSRecord{}).
+		Build()
+	providerFactory, err := provider.NewFactory(c, []string{"inmemory"})
+	if err != nil {
+		return nil, err
+	}
+
+	phases := newPhaseRecorder()
+	reconciler := &controller.DNSRecordReconciler{
+		Client:          c,
+		Scheme:          scheme,
+		ProviderFactory: providerFactory,
+		PhaseObserver:   phases,
+	}
+	reconciler.SetupWithoutManager(&record.FakeRecorder{}, 15*time.Minute, 14*time.Minute, 5*time.Second)
+
+	d := &driver{client: c, reconciler: reconciler, config: config, reconciles: &Histogram{}}
+	start := time.Now()
+	if err = d.run(ctx); err != nil {
+		return nil, err
+	}
+	return &Report{
+		Config:     config,
+		Duration:   time.Since(start),
+		Reconciles: phases.summary(reconcilePhase, d.reconciles, d.reconcileAllocs),
+		Phases:     phases.summaries(),
+	}, nil
+}
+
+// reconcilePhase is the name of the whole reconcile in reports.
+const reconcilePhase = "reconcile"
+
+type driver struct {
+	client     client.Client
+	reconciler *controller.DNSRecordReconciler
+	config     Config
+
+	reconciles      *Histogram
+	reconcileAllocs allocations
+}
+
+func (d *driver) run(ctx context.Context) error {
+	for i := range d.config.Records {
+		if err := d.client.Create(ctx, d.newRecord(i)); err != nil {
+			return err
+		}
+		if err := d.settle(ctx, d.key(i), false); err != nil {
+			return fmt.Errorf("creating record %d: %w", i, err)
+		}
+	}
+	if err := d.validate(ctx, "created"); err != nil {
+		return err
+	}
+
+	for round := 1; round <= d.config.Rounds; round++ {
+		for i := range d.config.Records {
+			if err := d.update(ctx, d.key(i), false, func(dnsRecord *v1alpha1.DNSRecord) {
+				dnsRecord.Spec.Endpoints = d.endpoints(i, round)
+				// the fake client doesn't bump the generation on spec changes as the API server does
+				dnsRecord.Generation++
+			}); err != nil {
+				return fmt.Errorf("round %d: changing record %d: %w", round, i, err)
+			}
+		}
+		if err := d.validate(ctx, fmt.Sprintf("round-%d", round)); err != nil {
+			return err
+		}
+	}
+
+	for i := range d.config.Records {
+		dnsRecord := &v1alpha1.DNSRecord{}
+		if err := d.client.Get(ctx, d.key(i), dnsRecord); err != nil {
+			return err
+		}
+		if err := d.client.Delete(ctx, dnsRecord); err != nil {
+			return err
+		}
+		if err := d.settle(ctx, d.key(i), false); err != nil {
+			return fmt.Errorf("deleting record %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// validate reconciles every record without changes, as when the validity of their last reconcile expires, after which
+// every record must be ready.
+func (d *driver) validate(ctx context.Context, token string) error {
+	for i := range d.config.Records {
+		if err := d.update(ctx, d.key(i), true, func(dnsRecord *v1alpha1.DNSRecord) {
+			metav1.SetMetaDataAnnotation(&dnsRecord.ObjectMeta, v1alpha1.ReconcileRequestAnnotation, token)
+		}); err != nil {
+			return fmt.Errorf("validating record %d after %s: %w", i, token, err)
+		}
+	}
+	return nil
+}
+
+// update changes the record with the given key and reconciles it until it has settled.
+func (d *driver) update(ctx context.Context, key client.ObjectKey, ready bool, change func(*v1alpha1.DNSRecord)) error {
+	dnsRecord := &v1alpha1.DNSRecord{}
+	if err := d.client.Get(ctx, key, dnsRecord); err != nil {
+		return err
+	}
+	change(dnsRecord)
+	if err := d.client.Update(ctx, dnsRecord); err != nil {
+		return err
+	}
+	return d.settle(ctx, key, ready)
+}
+
+// settle reconciles the record with the given key until its status is up to date with its spec, or it is removed. The
+// record must then be ready, or awaiting the validation of its changes unless ready is true.
+func (d *driver) settle(ctx context.Context, key client.ObjectKey, ready bool) error {
+	for range maxReconcilesToSettle {
+		var before runtime.MemStats
+		runtime.ReadMemStats(&before)
+		start := time.Now()
+		_, err := d.reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+		d.reconciles.Observe(time.Since(start))
+		d.reconcileAllocs.add(&before)
+		if err != nil {
+			return err
+		}
+
+		dnsRecord := &v1alpha1.DNSRecord{}
+		if err = d.client.Get(ctx, key, dnsRecord); err != nil {
+			if apierrors.IsNotFound(err) {
+				return nil
+			}
+			return err
+		}
+		if dnsRecord.DeletionTimestamp != nil || len(dnsRecord.Finalizers) == 0 ||
+			dnsRecord.Status.ObservedGeneration != dnsRecord.Generation ||
+			dnsRecord.Status.LastHandledReconcileRequest != dnsRecord.GetAnnotations()[v1alpha1.ReconcileRequestAnnotation] {
+			continue
+		}
+		condition := meta.FindStatusCondition(dnsRecord.Status.Conditions, string(v1alpha1.ConditionTypeReady))
+		if condition == nil || condition.Status != metav1.ConditionTrue &&
+			(ready || condition.Reason != string(v1alpha1.ConditionReasonAwaitingValidation)) {
+			return fmt.Errorf("record is not ready: %v", condition)
+		}
+		return nil
+	}
+	return fmt.Errorf("record %s did not settle in %d reconciles", key, maxReconcilesToSettle)
+}
+
+func (d *driver) key(i int) client.ObjectKey {
+	return client.ObjectKey{Namespace: namespace, Name: fmt.Sprintf("record-%d", i)}
+}
+
+func (d *driver) rootHost(i int) string {
+	return fmt.Sprintf("host-%d.%s", i, d.config.Domain)
+}
+
+// endpoints returns the endpoints of the record with the given index in the given round, the targets of every endpoint
+// change every round.
+func (d *driver) endpoints(i, round int) []*externaldnsendpoint.Endpoint {
+	endpoints := make([]*externaldnsendpoint.Endpoint, 0, d.config.EndpointsPerRecord)
+	for e := range d.config.EndpointsPerRecord {
+		name := d.rootHost(i)
+		if e > 0 {
+			name = fmt.Sprintf("ep-%d.%s", e, name)
+		}
+		endpoints = append(endpoints, externaldnsendpoint.NewEndpointWithTTL(name, externaldnsendpoint.RecordTypeA, 60,
+			fmt.Sprintf("10.%d.%d.%d", i/250%250, i%250, round%250+1)))
+	}
+	return endpoints
+}
+
+func (d *driver) newRecord(i int) *v1alpha1.DNSRecord {
+	return &v1alpha1.DNSRecord{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: d.key(i).Name, Generation: 1},
+		Spec: v1alpha1.DNSRecordSpec{
+			RootHost:    d.rootHost(i),
+			ProviderRef: v1alpha1.ProviderRef{Name: providerSecretName},
+			Endpoints:   d.endpoints(i, 0),
+		},
+	}
+}
+
+// phaseRecorder records the latency and allocations of every phase of the reconciles.
+type phaseRecorder struct {
+	latencies map[controller.ReconcilePhase]*Histogram
+	allocs    map[controller.ReconcilePhase]*allocations
+}
+
+var _ controller.PhaseObserver = &phaseRecorder{}
+
+func newPhaseRecorder() *phaseRecorder {
+	p := &phaseRecorder{
+		latencies: map[controller.ReconcilePhase]*Histogram{},
+		allocs:    map[controller.ReconcilePhase]*allocations{},
+	}
+	for _, phase := range controller.ReconcilePhases {
+		p.latencies[phase] = &Histogram{}
+		p.allocs[phase] = &allocations{}
+	}
+	return p
+}
+
+// StartPhase labels the goroutine of the reconcile with the phase for CPU profiles and measures the phase. The memory
+// statistics are read outside the timed part of the phase, as reading them stops the world.
+func (p *phaseRecorder) StartPhase(ctx context.Context, phase controller.ReconcilePhase) func() {
+	pprof.SetGoroutineLabels(pprof.WithLabels(ctx, pprof.Labels("phase", string(phase))))
+	var before runtime.MemStats
+	runtime.ReadMemStats(&before)
+	start := time.Now()
+	return func() {
+		duration := time.Since(start)
+		p.allocs[phase].add(&before)
+		p.latencies[phase].Observe(duration)
+		pprof.SetGoroutineLabels(ctx)
+	}
+}
+
+func (p *phaseRecorder) summaries() []PhaseSummary {
+	summaries := make([]PhaseSummary, 0, len(controller.ReconcilePhases))
+	for _, phase := range controller.ReconcilePhases {
+		summaries = append(summaries, p.summary(string(phase), p.latencies[phase], *p.allocs[phase]))
+	}
+	return summaries
+}
+
+func (p *phaseRecorder) summary(phase string, latencies *Histogram, allocs allocations) PhaseSummary {
+	summary := PhaseSummary{Phase: phase, Latencies: latencies}
+	if count := latencies.Count(); count > 0 {
+		summary.BytesPerOp = allocs.bytes / uint64(count)
+		summary.AllocsPerOp = allocs.objects / uint64(count)
+	}
+	return summary
+}
+
+// allocations are the heap allocations of all runs of a phase.
+type allocations struct {
+	bytes   uint64
+	objects uint64
+}
+
+// add adds the allocations made since the given memory statistics were read.
+func (a *allocations) add(before *runtime.MemStats) {
+	var after runtime.MemStats
+	runtime.ReadMemStats(&after)
+	a.bytes += after.TotalAlloc - before.TotalAlloc
+	a.objects += after.Mallocs - before.Mallocs
+}
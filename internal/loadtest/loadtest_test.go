@@ -0,0 +1,77 @@
+//go:build unit
+
+package loadtest
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRun(t *testing.T) {
+	config := Config{Records: 5, EndpointsPerRecord: 2, Rounds: 2, Domain: "run.loadtest.example.com"}
+	report, err := Run(context.Background(), config)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// created in two reconciles and validated in one, changed and validated in one each every round, deleted in two
+	if want := 5*2 + 5 + 5*2*2 + 5*2; report.Reconciles.Latencies.Count() != want {
+		t.Errorf("expected %d reconciles, got %d", want, report.Reconciles.Latencies.Count())
+	}
+	want := map[string]int{
+		// every reconcile that reaches the provider
+		"registry": 5 + 5 + 5*2*2 + 5*2,
+		"plan":     5 + 5 + 5*2*2 + 5*2,
+		// created, changed and deleted
+		"apply": 5 + 5*2 + 5,
+	}
+	for _, phase := range report.Phases {
+		if count, ok := want[phase.Phase]; ok && phase.Latencies.Count() != count {
+			t.Errorf("expected %d runs of the %s phase, got %d", count, phase.Phase, phase.Latencies.Count())
+		}
+		if phase.Latencies.Count() > 0 && phase.AllocsPerOp == 0 {
+			t.Errorf("expected the allocations of the %s phase", phase.Phase)
+		}
+	}
+
+	out := &strings.Builder{}
+	if err = report.Write(out); err != nil {
+		t.Fatal(err)
+	}
+	for _, phase := range []string{"reconcile", "registry", "plan", "apply", "status"} {
+		if !strings.Contains(out.String(), phase) {
+			t.Errorf("expected the %s phase in the report, got\n%s", phase, out.String())
+		}
+	}
+}
+
+func TestHistogram(t *testing.T) {
+	h := &Histogram{}
+	if h.Quantile(0.5) != 0 || h.Mean() != 0 {
+		t.Errorf("expected an empty histogram to have no latencies")
+	}
+	for i := 100; i >= 1; i-- {
+		h.Observe(time.Duration(i) * time.Millisecond)
+	}
+	if got := h.Quantile(0.5); got != 50*time.Millisecond {
+		t.Errorf("expected p50 of 50ms, got %s", got)
+	}
+	if got := h.Quantile(0.99); got != 99*time.Millisecond {
+		t.Errorf("expected p99 of 99ms, got %s", got)
+	}
+	if got := h.Max(); got != 100*time.Millisecond {
+		t.Errorf("expected a max of 100ms, got %s", got)
+	}
+	if got := h.Mean(); got != 50500*time.Microsecond {
+		t.Errorf("expected a mean of 50.5ms, got %s", got)
+	}
+	total := 0
+	for _, count := range h.Buckets() {
+		total += count
+	}
+	if total != 100 {
+		t.Errorf("expected every latency in a bucket, got %d", total)
+	}
+}
@@ -0,0 +1,158 @@
+package loadtest
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// histogramBuckets are the upper bounds of the buckets latencies are counted in when written, doubling from 100µs.
+var histogramBuckets = func() []time.Duration {
+	buckets := make([]time.Duration, 0, 16)
+	for bound := 100 * time.Microsecond; bound <= 5*time.Second; bound *= 2 {
+		buckets = append(buckets, bound)
+	}
+	return buckets
+}()
+
+// histogramWidth is the width of the bar of the bucket with the most latencies when a histogram is written.
+const histogramWidth = 40
+
+// Histogram holds the latencies of every run of a phase.
+type Histogram struct {
+	latencies []time.Duration
+	sorted    bool
+}
+
+// Observe adds a latency to the histogram.
+func (h *Histogram) Observe(latency time.Duration) {
+	h.latencies = append(h.latencies, latency)
+	h.sorted = false
+}
+
+// Count returns the number of latencies in the histogram.
+func (h *Histogram) Count() int {
+	return len(h.latencies)
+}
+
+// Quantile returns the latency the given fraction of the latencies are less than or equal to, e.g. 0.99 for the 99th
+// percentile. Returns 0 for an empty histogram.
+func (h *Histogram) Quantile(q float64) time.Duration {
+	if len(h.latencies) == 0 {
+		return 0
+	}
+	h.sort()
+	i := int(q*float64(len(h.latencies))+0.5) - 1
+	i = max(0, min(i, len(h.latencies)-1))
+	return h.latencies[i]
+}
+
+// Mean returns the mean latency, 0 for an empty histogram.
+func (h *Histogram) Mean() time.Duration {
+	if len(h.latencies) == 0 {
+		return 0
+	}
+	var sum time.Duration
+	for _, latency := range h.latencies {
+		sum += latency
+	}
+	return sum / time.Duration(len(h.latencies))
+}
+
+// Max returns the highest latency, 0 for an empty histogram.
+func (h *Histogram) Max() time.Duration {
+	return h.Quantile(1)
+}
+
+// Buckets returns the number of latencies less than or equal to each of the bucket bounds and greater than the
+// previous bound. Latencies greater than the last bound are counted in the last bucket.
+func (h *Histogram) Buckets() []int {
+	counts := make([]int, len(histogramBuckets))
+	for _, latency := range h.latencies {
+		i := sort.Search(len(histogramBuckets), func(i int) bool { return latency <= histogramBuckets[i] })
+		counts[min(i, len(histogramBuckets)-1)]++
+	}
+	return counts
+}
+
+func (h *Histogram) sort() {
+	if h.sorted {
+		return
+	}
+	sort.Slice(h.latencies, func(i, j int) bool { return h.latencies[i] < h.latencies[j] })
+	h.sorted = true
+}
+
+// PhaseSummary is the latency and allocations of all runs of a phase of the reconciles.
+type PhaseSummary struct {
+	Phase     string
+	Latencies *Histogram
+	// BytesPerOp is the mean number of bytes allocated by a run of the phase
+	BytesPerOp uint64
+	// AllocsPerOp is the mean number of heap allocations of a run of the phase
+	AllocsPerOp uint64
+}
+
+// Report is the result of a run.
+type Report struct {
+	Config   Config
+	Duration time.Duration
+	// Reconciles summarizes the whole reconciles
+	Reconciles PhaseSummary
+	// Phases summarizes each phase of the reconciles that ran it, in the order they run
+	Phases []PhaseSummary
+}
+
+// Write writes a table of the latency percentiles and allocations of the reconciles and each of their phases, followed
+// by the latency histogram of each.
+func (r *Report) Write(w io.Writer) error {
+	summaries := append([]PhaseSummary{r.Reconciles}, r.Phases...)
+
+	fmt.Fprintf(w, "%d records with %d endpoints, %d rounds, %d reconciles in %s\n\n", r.Config.Records,
+		r.Config.EndpointsPerRecord, r.Config.Rounds, r.Reconciles.Latencies.Count(), r.Duration.Round(time.Millisecond))
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "phase\tcount\tmean\tp50\tp90\tp99\tmax\tB/op\tallocs/op\t")
+	for _, s := range summaries {
+		h := s.Latencies
+		fmt.Fprintf(tw, "%s\t%d\t%s\t%s\t%s\t%s\t%s\t%d\t%d\t\n", s.Phase, h.Count(), round(h.Mean()), round(h.Quantile(0.5)),
+			round(h.Quantile(0.9)), round(h.Quantile(0.99)), round(h.Max()), s.BytesPerOp, s.AllocsPerOp)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	for _, s := range summaries {
+		counts := s.Latencies.Buckets()
+		highest := 0
+		for _, count := range counts {
+			highest = max(highest, count)
+		}
+		if highest == 0 {
+			continue
+		}
+		fmt.Fprintf(w, "\n%s\n", s.Phase)
+		tw = tabwriter.NewWriter(w, 0, 0, 1, ' ', 0)
+		for i, count := range counts {
+			if count == 0 {
+				continue
+			}
+			bound := "<= " + histogramBuckets[i].String()
+			if i == len(counts)-1 {
+				bound = "> " + histogramBuckets[i-1].String()
+			}
+			fmt.Fprintf(tw, "%s\t%d\t%s\n", bound, count, strings.Repeat("#", max(1, count*histogramWidth/highest)))
+		}
+		if err := tw.Flush(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// round rounds latencies for reports, to microseconds.
+func round(d time.Duration) time.Duration {
+	return d.Round(time.Microsecond)
+}
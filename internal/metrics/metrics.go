@@ -15,6 +15,14 @@ const (
 	mzRecordNameLabel            = "managed_zone_name"
 	mzRecordNamespaceLabel       = "managed_zone_namespace"
 	mzSecretNameLabel            = "managed_zone_secret_name"
+	errorClassLabel              = "error_class"
+	providerTypeLabel            = "provider_type"
+	providerSecretLabel          = "provider_secret"
+	eventReasonLabel             = "reason"
+	notificationResultLabel      = "result"
+	reconcilePhaseLabel          = "phase"
+	zoneDomainNameLabel          = "zone_domain_name"
+	zoneIDLabel                  = "zone_id"
 )
 
 var (
@@ -30,6 +38,97 @@ var (
 			Help: "Count of active probes",
 		},
 		[]string{dnsHealthCheckNameLabel, dnsHealthCheckNamespaceLabel, dnsHealthCheckHostLabel})
+	UpdateChurnCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "dns_provider_update_churn_counter",
+			Help: "Counts endpoint updates written to the DNS provider for a DNS record when the record spec has not changed",
+		},
+		[]string{dnsRecordNameLabel, dnsRecordNamespaceLabel})
+	DeletionFailures = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "dns_record_deletion_failures_total",
+			Help: "Counts failed attempts to remove a deleted DNS record from the DNS provider, by the class of the error",
+		},
+		[]string{dnsRecordNameLabel, dnsRecordNamespaceLabel, errorClassLabel})
+	DeletionStuck = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "dns_record_deletion_stuck",
+			Help: "Emits one when a deleted DNS record could not be removed from the DNS provider for longer than the deletion stuck duration, with the provider error code of the last failure",
+		},
+		[]string{dnsRecordNameLabel, dnsRecordNamespaceLabel, errorClassLabel})
+	StatusUpdatesSuppressed = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "dns_record_status_updates_suppressed_total",
+			Help: "Counts DNS record status updates that were not written as only the queued time changed",
+		})
+	ReconcileTimeouts = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "dns_record_reconcile_timeouts_total",
+			Help: "Counts DNS record reconciles that did not complete within the reconcile timeout",
+		})
+	ProviderConstructionDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "dns_provider_construction_duration_seconds",
+			Help:    "Time taken to construct a DNS provider client from a provider secret, labelled by a hash of the secret",
+			Buckets: prometheus.DefBuckets,
+		},
+		[]string{providerTypeLabel, providerSecretLabel})
+	ProviderAuthFailures = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "dns_provider_auth_failures_total",
+			Help: "Counts DNS provider calls that failed to authenticate with the credentials of a provider secret, labelled by a hash of the secret",
+		},
+		[]string{providerTypeLabel, providerSecretLabel})
+	WriteBudgetExceeded = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "dns_provider_write_budget_exceeded_total",
+			Help: "Counts changes deferred as the write budget of a provider secret was used up, labelled by a hash of the secret",
+		},
+		[]string{providerSecretLabel})
+	ZoneResolutionDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "dns_provider_zone_resolution_duration_seconds",
+			Help:    "Time taken by a DNS provider to find the zone of a host, labelled by a hash of the provider secret",
+			Buckets: prometheus.DefBuckets,
+		},
+		[]string{providerTypeLabel, providerSecretLabel})
+	EventsSuppressed = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "dns_operator_events_suppressed_total",
+			Help: "Counts events that were not recorded as a similar event was recently recorded for the same object, or the event rate limit was reached",
+		},
+		[]string{eventReasonLabel})
+	ChangeNotifications = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "dns_operator_change_notifications_total",
+			Help: "Counts notifications of changes applied to zones by result, published, failed or dropped when the queue is full",
+		},
+		[]string{notificationResultLabel})
+	ReconcilePhaseDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "dns_record_reconcile_phase_duration_seconds",
+			Help:    "Time taken by the phases of DNS record reconciles that completed, reading the zone through the registry, planning, applying changes and writing the status",
+			Buckets: prometheus.DefBuckets,
+		},
+		[]string{reconcilePhaseLabel})
+	ZoneRecords = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "dns_provider_zone_records",
+			Help: "The number of records in a zone when it was last read by a DNS record, only set when a zone record limit is configured",
+		},
+		[]string{zoneDomainNameLabel, zoneIDLabel})
+	ZoneRegistryRecords = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "dns_provider_zone_registry_records",
+			Help: "The number of registry TXT records of any owner in a zone when it was last read by a DNS record, only set when a zone record limit is configured",
+		},
+		[]string{zoneDomainNameLabel, zoneIDLabel})
+	ZoneRecordLimit = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "dns_provider_zone_record_limit",
+			Help: "The maximum number of records allowed in a zone, from the zone record limit of the operator or the provider secret",
+		},
+		[]string{zoneDomainNameLabel, zoneIDLabel})
 	SecretMissing = prometheus.NewGaugeVec(
 		prometheus.GaugeOpts{
 			Name: "dns_provider_secret_absent",
@@ -42,4 +141,38 @@ func init() {
 	metrics.Registry.MustRegister(WriteCounter)
 	metrics.Registry.MustRegister(SecretMissing)
 	metrics.Registry.MustRegister(ProbeCounter)
+	metrics.Registry.MustRegister(UpdateChurnCounter)
+	metrics.Registry.MustRegister(DeletionFailures)
+	metrics.Registry.MustRegister(DeletionStuck)
+	metrics.Registry.MustRegister(StatusUpdatesSuppressed)
+	metrics.Registry.MustRegister(ReconcileTimeouts)
+	metrics.Registry.MustRegister(ProviderConstructionDuration)
+	metrics.Registry.MustRegister(ProviderAuthFailures)
+	metrics.Registry.MustRegister(ZoneResolutionDuration)
+	metrics.Registry.MustRegister(EventsSuppressed)
+	metrics.Registry.MustRegister(ChangeNotifications)
+	metrics.Registry.MustRegister(WriteBudgetExceeded)
+	metrics.Registry.MustRegister(ReconcilePhaseDuration)
+	metrics.Registry.MustRegister(ZoneRecords)
+	metrics.Registry.MustRegister(ZoneRegistryRecords)
+	metrics.Registry.MustRegister(ZoneRecordLimit)
+}
+
+// SetDeletionStuck marks the DNS record as stuck deleting with the given error class, replacing any previous class.
+func SetDeletionStuck(name, namespace, errorClass string) {
+	DeletionStuck.DeletePartialMatch(prometheus.Labels{dnsRecordNameLabel: name, dnsRecordNamespaceLabel: namespace})
+	DeletionStuck.WithLabelValues(name, namespace, errorClass).Set(1)
+}
+
+// ResetZoneRecordMetrics removes the record count metrics of a zone once no DNS record publishing to the zone sets them.
+func ResetZoneRecordMetrics(zoneDomainName, zoneID string) {
+	ZoneRecords.DeleteLabelValues(zoneDomainName, zoneID)
+	ZoneRegistryRecords.DeleteLabelValues(zoneDomainName, zoneID)
+	ZoneRecordLimit.DeleteLabelValues(zoneDomainName, zoneID)
+}
+
+// ResetDeletionMetrics removes the deletion metrics of a DNS record once it has been removed from the DNS provider.
+func ResetDeletionMetrics(name, namespace string) {
+	DeletionFailures.DeletePartialMatch(prometheus.Labels{dnsRecordNameLabel: name, dnsRecordNamespaceLabel: namespace})
+	DeletionStuck.DeletePartialMatch(prometheus.Labels{dnsRecordNameLabel: name, dnsRecordNamespaceLabel: namespace})
 }
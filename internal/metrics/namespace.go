@@ -0,0 +1,110 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	namespaceLabel = "namespace"
+
+	// namespaceCollectTimeout is the time allowed for listing the DNS records and quotas when the metrics are scraped
+	namespaceCollectTimeout = 5 * time.Second
+)
+
+var (
+	namespaceRecordsDesc = prometheus.NewDesc(
+		"dns_namespace_records",
+		"Number of DNS records in a namespace",
+		[]string{namespaceLabel}, nil)
+	namespaceEndpointsDesc = prometheus.NewDesc(
+		"dns_namespace_endpoints",
+		"Number of endpoints of the DNS records in a namespace",
+		[]string{namespaceLabel}, nil)
+	namespaceZonesDesc = prometheus.NewDesc(
+		"dns_namespace_zones",
+		"Number of distinct zones the DNS records in a namespace are published to",
+		[]string{namespaceLabel}, nil)
+	namespaceQuotaRecordsDesc = prometheus.NewDesc(
+		"dns_namespace_quota_max_records",
+		"Maximum number of DNS records allowed in a namespace by its DNS quotas",
+		[]string{namespaceLabel}, nil)
+	namespaceQuotaEndpointsDesc = prometheus.NewDesc(
+		"dns_namespace_quota_max_endpoints",
+		"Maximum number of endpoints allowed in a namespace by its DNS quotas",
+		[]string{namespaceLabel}, nil)
+)
+
+// NamespaceUsage is the DNS usage of a namespace and the limits of its quotas.
+type NamespaceUsage struct {
+	Namespace string
+	Records   int
+	Endpoints int
+	Zones     int
+	// MaxRecords is the lowest maximum number of records of the quotas of the namespace, nil when unlimited
+	MaxRecords *int
+	// MaxEndpoints is the lowest maximum number of endpoints of the quotas of the namespace, nil when unlimited
+	MaxEndpoints *int
+}
+
+// NamespaceUsageFunc lists the DNS usage of each namespace with DNS records or quotas.
+type NamespaceUsageFunc func(ctx context.Context) ([]NamespaceUsage, error)
+
+// namespaceCollector reports on the DNS usage of each namespace, it is listed each time the metrics are scraped so the
+// values are never stale and namespaces without records are no longer reported.
+type namespaceCollector struct {
+	usage NamespaceUsageFunc
+}
+
+var _ prometheus.Collector = &namespaceCollector{}
+
+// NewNamespaceCollector returns a collector of the number of DNS records, endpoints and zones of each namespace, and
+// the limits of their quotas.
+func NewNamespaceCollector(usage NamespaceUsageFunc) prometheus.Collector {
+	return &namespaceCollector{usage: usage}
+}
+
+// RegisterNamespaceCollector registers a namespace collector for the given usage with the controller metrics.
+func RegisterNamespaceCollector(usage NamespaceUsageFunc) error {
+	err := metrics.Registry.Register(NewNamespaceCollector(usage))
+	if errors.As(err, &prometheus.AlreadyRegisteredError{}) {
+		return nil
+	}
+	return err
+}
+
+func (c *namespaceCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- namespaceRecordsDesc
+	ch <- namespaceEndpointsDesc
+	ch <- namespaceZonesDesc
+	ch <- namespaceQuotaRecordsDesc
+	ch <- namespaceQuotaEndpointsDesc
+}
+
+func (c *namespaceCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), namespaceCollectTimeout)
+	defer cancel()
+
+	usages, err := c.usage(ctx)
+	if err != nil {
+		ch <- prometheus.NewInvalidMetric(namespaceRecordsDesc, err)
+		return
+	}
+
+	for _, usage := range usages {
+		ch <- prometheus.MustNewConstMetric(namespaceRecordsDesc, prometheus.GaugeValue, float64(usage.Records), usage.Namespace)
+		ch <- prometheus.MustNewConstMetric(namespaceEndpointsDesc, prometheus.GaugeValue, float64(usage.Endpoints), usage.Namespace)
+		ch <- prometheus.MustNewConstMetric(namespaceZonesDesc, prometheus.GaugeValue, float64(usage.Zones), usage.Namespace)
+		if usage.MaxRecords != nil {
+			ch <- prometheus.MustNewConstMetric(namespaceQuotaRecordsDesc, prometheus.GaugeValue, float64(*usage.MaxRecords), usage.Namespace)
+		}
+		if usage.MaxEndpoints != nil {
+			ch <- prometheus.MustNewConstMetric(namespaceQuotaEndpointsDesc, prometheus.GaugeValue, float64(*usage.MaxEndpoints), usage.Namespace)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+//go:build unit
+
+package metrics
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestNamespaceCollector(t *testing.T) {
+	maxRecords := 10
+	c := &namespaceCollector{usage: func(_ context.Context) ([]NamespaceUsage, error) {
+		return []NamespaceUsage{
+			{Namespace: "team-a", Records: 2, Endpoints: 5, Zones: 1, MaxRecords: &maxRecords},
+			{Namespace: "team-b", Records: 1, Endpoints: 1, Zones: 1},
+		}, nil
+	}}
+	expected := `
+# HELP dns_namespace_endpoints Number of endpoints of the DNS records in a namespace
+# TYPE dns_namespace_endpoints gauge
+dns_namespace_endpoints{namespace="team-a"} 5
+dns_namespace_endpoints{namespace="team-b"} 1
+# HELP dns_namespace_quota_max_records Maximum number of DNS records allowed in a namespace by its DNS quotas
+# TYPE dns_namespace_quota_max_records gauge
+dns_namespace_quota_max_records{namespace="team-a"} 10
+# HELP dns_namespace_records Number of DNS records in a namespace
+# TYPE dns_namespace_records gauge
+dns_namespace_records{namespace="team-a"} 2
+dns_namespace_records{namespace="team-b"} 1
+# HELP dns_namespace_zones Number of distinct zones the DNS records in a namespace are published to
+# TYPE dns_namespace_zones gauge
+dns_namespace_zones{namespace="team-a"} 1
+dns_namespace_zones{namespace="team-b"} 1
+`
+	if err := testutil.CollectAndCompare(c, strings.NewReader(expected)); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("listing error", func(t *testing.T) {
+		c := &namespaceCollector{usage: func(_ context.Context) ([]NamespaceUsage, error) {
+			return nil, errors.New("cache not synced")
+		}}
+		reg := prometheus.NewPedanticRegistry()
+		reg.MustRegister(c)
+		if _, err := reg.Gather(); err == nil || !strings.Contains(err.Error(), "cache not synced") {
+			t.Fatalf("expected listing error to be reported, got %v", err)
+		}
+	})
+}
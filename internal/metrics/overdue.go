@@ -0,0 +1,102 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	providerSecretNameLabel      = "dns_provider_secret_name"
+	providerSecretNamespaceLabel = "dns_provider_secret_namespace"
+
+	// overdueCollectTimeout is the time allowed for listing the overdue DNS records when the metrics are scraped
+	overdueCollectTimeout = 5 * time.Second
+)
+
+var (
+	overdueRecordsDesc = prometheus.NewDesc(
+		"dns_record_reconcile_overdue",
+		"Number of DNS records of a DNS provider secret whose status shows they are due to be reconciled",
+		[]string{providerSecretNamespaceLabel, providerSecretNameLabel}, nil)
+	oldestOverdueRecordDesc = prometheus.NewDesc(
+		"dns_record_reconcile_overdue_oldest_seconds",
+		"Time in seconds since the DNS record that has been overdue the longest became due to be reconciled",
+		nil, nil)
+)
+
+// OverdueRecord is a DNS record whose status shows it is due to be reconciled.
+type OverdueRecord struct {
+	ProviderSecretNamespace string
+	ProviderSecretName      string
+	// Since is the time the record became due to be reconciled
+	Since time.Time
+}
+
+// OverdueRecordsFunc lists the DNS records whose status shows they are due to be reconciled.
+type OverdueRecordsFunc func(ctx context.Context) ([]OverdueRecord, error)
+
+// overdueCollector reports on the DNS records that are overdue to be reconciled, they are listed each time the
+// metrics are scraped so the values are never stale. Records are overdue from their status, not from the work queue of
+// the controller: a record can be overdue before it is requeued, and is no longer overdue once its reconcile has updated
+// its status. The depth and latency of the work queue itself are reported by the workqueue metrics of
+// controller-runtime, e.g. workqueue_depth{name="dnsrecord"}.
+type overdueCollector struct {
+	overdue OverdueRecordsFunc
+	now     func() time.Time
+}
+
+var _ prometheus.Collector = &overdueCollector{}
+
+// NewOverdueCollector returns a collector of the number of DNS records overdue to be reconciled for each DNS provider
+// secret, and the time the oldest one has been overdue.
+func NewOverdueCollector(overdue OverdueRecordsFunc) prometheus.Collector {
+	return &overdueCollector{
+		overdue: overdue,
+		now:     time.Now,
+	}
+}
+
+// RegisterOverdueCollector registers an overdue collector for the given overdue records with the controller metrics.
+func RegisterOverdueCollector(overdue OverdueRecordsFunc) error {
+	err := metrics.Registry.Register(NewOverdueCollector(overdue))
+	if errors.As(err, &prometheus.AlreadyRegisteredError{}) {
+		return nil
+	}
+	return err
+}
+
+func (c *overdueCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- overdueRecordsDesc
+	ch <- oldestOverdueRecordDesc
+}
+
+func (c *overdueCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), overdueCollectTimeout)
+	defer cancel()
+
+	records, err := c.overdue(ctx)
+	if err != nil {
+		ch <- prometheus.NewInvalidMetric(overdueRecordsDesc, err)
+		return
+	}
+
+	type secretKey struct{ namespace, name string }
+	counts := map[secretKey]int{}
+	var oldest time.Duration
+	now := c.now()
+	for _, record := range records {
+		counts[secretKey{record.ProviderSecretNamespace, record.ProviderSecretName}]++
+		if age := now.Sub(record.Since); age > oldest {
+			oldest = age
+		}
+	}
+	for key, count := range counts {
+		ch <- prometheus.MustNewConstMetric(overdueRecordsDesc, prometheus.GaugeValue, float64(count), key.namespace, key.name)
+	}
+	ch <- prometheus.MustNewConstMetric(oldestOverdueRecordDesc, prometheus.GaugeValue, oldest.Seconds())
+}
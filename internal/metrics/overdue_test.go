@@ -0,0 +1,75 @@
+//go:build unit
+
+package metrics
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestOverdueCollector(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name     string
+		overdue  OverdueRecordsFunc
+		expected string
+	}{
+		{
+			name: "no overdue records",
+			overdue: func(_ context.Context) ([]OverdueRecord, error) {
+				return nil, nil
+			},
+			expected: `
+# HELP dns_record_reconcile_overdue_oldest_seconds Time in seconds since the DNS record that has been overdue the longest became due to be reconciled
+# TYPE dns_record_reconcile_overdue_oldest_seconds gauge
+dns_record_reconcile_overdue_oldest_seconds 0
+`,
+		},
+		{
+			name: "overdue records for multiple secrets",
+			overdue: func(_ context.Context) ([]OverdueRecord, error) {
+				return []OverdueRecord{
+					{ProviderSecretNamespace: "ns1", ProviderSecretName: "aws", Since: now.Add(-time.Minute)},
+					{ProviderSecretNamespace: "ns1", ProviderSecretName: "aws", Since: now.Add(-90 * time.Second)},
+					{ProviderSecretNamespace: "ns2", ProviderSecretName: "gcp", Since: now.Add(-time.Second)},
+				}, nil
+			},
+			expected: `
+# HELP dns_record_reconcile_overdue_oldest_seconds Time in seconds since the DNS record that has been overdue the longest became due to be reconciled
+# TYPE dns_record_reconcile_overdue_oldest_seconds gauge
+dns_record_reconcile_overdue_oldest_seconds 90
+# HELP dns_record_reconcile_overdue Number of DNS records of a DNS provider secret whose status shows they are due to be reconciled
+# TYPE dns_record_reconcile_overdue gauge
+dns_record_reconcile_overdue{dns_provider_secret_name="aws",dns_provider_secret_namespace="ns1"} 2
+dns_record_reconcile_overdue{dns_provider_secret_name="gcp",dns_provider_secret_namespace="ns2"} 1
+`,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &overdueCollector{overdue: tt.overdue, now: func() time.Time { return now }}
+			if err := testutil.CollectAndCompare(c, strings.NewReader(tt.expected)); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+
+	t.Run("listing error", func(t *testing.T) {
+		c := &overdueCollector{overdue: func(_ context.Context) ([]OverdueRecord, error) {
+			return nil, errors.New("cache not synced")
+		}, now: func() time.Time { return now }}
+		reg := prometheus.NewPedanticRegistry()
+		reg.MustRegister(c)
+		if _, err := reg.Gather(); err == nil || !strings.Contains(err.Error(), "cache not synced") {
+			t.Fatalf("expected listing error to be reported, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,206 @@
+package probes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net"
+	"os"
+	"strings"
+
+	"golang.org/x/net/dns/dnsmessage"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kuadrant/dns-operator/api/v1alpha1"
+)
+
+const (
+	// maxCNAMEChainLength is the number of hops a CNAME chain is followed for before it is considered a loop.
+	maxCNAMEChainLength = 8
+
+	resolvConfPath = "/etc/resolv.conf"
+)
+
+// ChainResolver resolves the CNAME chain of the address of a probe one hop at a time.
+type ChainResolver interface {
+	// LookupCNAMEHop returns the target of the CNAME record at the name, or an empty string if the name has none.
+	LookupCNAMEHop(ctx context.Context, name string) (string, error)
+	// LookupIPAddr returns the addresses of the name at the end of the chain.
+	LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error)
+}
+
+// systemChainResolver sends the CNAME queries of each hop to the nameservers of the system resolver configuration. The
+// resolver of the net package follows the whole chain on lookups and does not expose the intermediate hops.
+type systemChainResolver struct{}
+
+func (systemChainResolver) LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error) {
+	return net.DefaultResolver.LookupIPAddr(ctx, host)
+}
+
+func (systemChainResolver) LookupCNAMEHop(ctx context.Context, name string) (string, error) {
+	nameservers, err := systemNameservers()
+	if err != nil {
+		return "", err
+	}
+	var errs []error
+	for _, nameserver := range nameservers {
+		target, err := lookupCNAMEHop(ctx, nameserver, name)
+		if err == nil {
+			return target, nil
+		}
+		errs = append(errs, err)
+	}
+	return "", errors.Join(errs...)
+}
+
+// systemNameservers returns the nameservers of the system resolver configuration.
+func systemNameservers() ([]string, error) {
+	data, err := os.ReadFile(resolvConfPath)
+	if err != nil {
+		return nil, err
+	}
+	var nameservers []string
+	for _, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) >= 2 && fields[0] == "nameserver" {
+			nameservers = append(nameservers, fields[1])
+		}
+	}
+	if len(nameservers) == 0 {
+		return nil, fmt.Errorf("no nameservers in %s", resolvConfPath)
+	}
+	return nameservers, nil
+}
+
+// lookupCNAMEHop queries the nameserver for the CNAME record at the name.
+func lookupCNAMEHop(ctx context.Context, nameserver, hostname string) (string, error) {
+	name, err := dnsmessage.NewName(strings.TrimSuffix(hostname, ".") + ".")
+	if err != nil {
+		return "", err
+	}
+	query := dnsmessage.Message{
+		Header:    dnsmessage.Header{ID: uint16(rand.Uint32()), RecursionDesired: true},
+		Questions: []dnsmessage.Question{{Name: name, Type: dnsmessage.TypeCNAME, Class: dnsmessage.ClassINET}},
+	}
+	packed, err := query.Pack()
+	if err != nil {
+		return "", err
+	}
+
+	d := net.Dialer{}
+	conn, err := d.DialContext(ctx, "udp", net.JoinHostPort(nameserver, "53"))
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		if err = conn.SetDeadline(deadline); err != nil {
+			return "", err
+		}
+	}
+	if _, err = conn.Write(packed); err != nil {
+		return "", err
+	}
+	buf := make([]byte, 4096)
+	n, err := conn.Read(buf)
+	if err != nil {
+		return "", err
+	}
+
+	var response dnsmessage.Message
+	if err = response.Unpack(buf[:n]); err != nil {
+		return "", err
+	}
+	if response.Header.ID != query.Header.ID {
+		return "", fmt.Errorf("unexpected response id from %s", nameserver)
+	}
+	switch response.Header.RCode {
+	case dnsmessage.RCodeSuccess:
+	case dnsmessage.RCodeNameError:
+		return "", fmt.Errorf("no such host")
+	default:
+		return "", fmt.Errorf("querying %s: %s", nameserver, response.Header.RCode)
+	}
+
+	for _, rr := range response.Answers {
+		cname, ok := rr.Body.(*dnsmessage.CNAMEResource)
+		if ok && strings.EqualFold(rr.Header.Name.String(), name.String()) {
+			return strings.TrimSuffix(cname.CNAME.String(), "."), nil
+		}
+	}
+	return "", nil
+}
+
+// resolveCNAMEChain follows the CNAME chain of the address one hop at a time and returns the hops, with the addresses
+// of the last hop. The returned error names the hop that failed to resolve, the hops up to and including it are
+// returned with it.
+func resolveCNAMEChain(ctx context.Context, resolver ChainResolver, address string) ([]v1alpha1.CNAMEHop, error) {
+	var chain []v1alpha1.CNAMEHop
+	name := strings.TrimSuffix(address, ".")
+	for i := 1; ; i++ {
+		if i > maxCNAMEChainLength {
+			return chain, fmt.Errorf("the CNAME chain of %s is longer than %d hops", address, maxCNAMEChainLength)
+		}
+		hop := v1alpha1.CNAMEHop{Name: name}
+		target, err := resolver.LookupCNAMEHop(ctx, name)
+		if err == nil && target != "" {
+			hop.Target = target
+			chain = append(chain, hop)
+			name = target
+			continue
+		}
+		if err == nil {
+			var addrs []net.IPAddr
+			addrs, err = resolver.LookupIPAddr(ctx, name)
+			for _, addr := range addrs {
+				hop.Addresses = append(hop.Addresses, addr.IP.String())
+			}
+			if err == nil && len(hop.Addresses) == 0 {
+				err = fmt.Errorf("no addresses found")
+			}
+		}
+		if err != nil {
+			hop.Error = err.Error()
+			chain = append(chain, hop)
+			return chain, fmt.Errorf("hop %d of the CNAME chain of %s, %s, failed to resolve: %w", i, address, name, err)
+		}
+		return append(chain, hop), nil
+	}
+}
+
+// executeChain probes each of the addresses at the end of the CNAME chain of the address of the probe. The probe is
+// healthy if any of the addresses is, as with the addresses of a hostname resolved without following the chain.
+func (w *Probe) executeChain(ctx context.Context, probe *v1alpha1.DNSHealthCheckProbe) ProbeResult {
+	resolver := w.Resolver
+	if resolver == nil {
+		resolver = systemChainResolver{}
+	}
+	resolveCtx, cancel := context.WithTimeout(ctx, probeTimeout(probe))
+	defer cancel()
+	chain, err := resolveCNAMEChain(resolveCtx, resolver, probe.Spec.Address)
+	if err != nil {
+		return ProbeResult{CheckedAt: metav1.Now(), Healthy: false, Reason: err.Error(), CNAMEChain: chain}
+	}
+
+	last := chain[len(chain)-1]
+	result := ProbeResult{CNAMEChain: chain}
+	for _, address := range last.Addresses {
+		targetResult := w.performRequest(ctx, probe, address, w.probeHeaders)
+		result.TargetResults = append(result.TargetResults, v1alpha1.ProbeTargetResult{
+			Address: address,
+			Healthy: targetResult.Healthy,
+			Reason:  targetResult.Reason,
+			Status:  targetResult.Status,
+		})
+		if !result.Healthy {
+			result.Healthy, result.Status = targetResult.Healthy, targetResult.Status
+		}
+	}
+	result.CheckedAt = metav1.Now()
+	if !result.Healthy {
+		result.Reason = fmt.Sprintf("none of the addresses of %s at the end of the CNAME chain of %s are healthy", last.Name, probe.Spec.Address)
+	}
+	return result
+}
@@ -0,0 +1,129 @@
+package probes
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kuadrant/dns-operator/api/v1alpha1"
+)
+
+type fakeChainResolver struct {
+	cnames    map[string]string
+	addresses map[string][]string
+}
+
+func (r fakeChainResolver) LookupCNAMEHop(_ context.Context, name string) (string, error) {
+	if target, ok := r.cnames[name]; ok {
+		return target, nil
+	}
+	if _, ok := r.addresses[name]; ok {
+		return "", nil
+	}
+	return "", errors.New("no such host")
+}
+
+func (r fakeChainResolver) LookupIPAddr(_ context.Context, host string) ([]net.IPAddr, error) {
+	addresses, ok := r.addresses[host]
+	if !ok {
+		return nil, errors.New("no such host")
+	}
+	var addrs []net.IPAddr
+	for _, address := range addresses {
+		addrs = append(addrs, net.IPAddr{IP: net.ParseIP(address)})
+	}
+	return addrs, nil
+}
+
+func TestResolveCNAMEChain(t *testing.T) {
+	resolver := fakeChainResolver{
+		cnames: map[string]string{
+			"app.example.com":  "lb.example.net",
+			"lb.example.net":   "lb-eu.example.net",
+			"gone.example.com": "missing.example.net",
+			"loop.example.com": "loop.example.com",
+		},
+		addresses: map[string][]string{
+			"lb-eu.example.net": {"10.0.0.1", "10.0.0.2"},
+		},
+	}
+
+	chain, err := resolveCNAMEChain(context.Background(), resolver, "app.example.com")
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	if len(chain) != 3 {
+		t.Fatalf("expected 3 hops got %v", chain)
+	}
+	if chain[0].Target != "lb.example.net" || chain[1].Target != "lb-eu.example.net" {
+		t.Fatalf("unexpected hops %v", chain)
+	}
+	if last := chain[2]; last.Name != "lb-eu.example.net" || len(last.Addresses) != 2 {
+		t.Fatalf("expected the addresses of the last hop got %v", last)
+	}
+
+	chain, err = resolveCNAMEChain(context.Background(), resolver, "gone.example.com")
+	if err == nil || !strings.Contains(err.Error(), "hop 2 of the CNAME chain of gone.example.com, missing.example.net, failed to resolve") {
+		t.Fatalf("expected the error to name the hop that failed got %v", err)
+	}
+	if len(chain) != 2 || chain[1].Error == "" {
+		t.Fatalf("expected the failed hop in the chain got %v", chain)
+	}
+
+	chain, err = resolveCNAMEChain(context.Background(), resolver, "loop.example.com")
+	if err == nil || len(chain) != maxCNAMEChainLength {
+		t.Fatalf("expected a looping chain to fail after %d hops got %v", maxCNAMEChainLength, err)
+	}
+}
+
+func TestExecuteChain(t *testing.T) {
+	probe := &v1alpha1.DNSHealthCheckProbe{
+		Spec: v1alpha1.DNSHealthCheckProbeSpec{
+			Hostname:          "example.com",
+			Address:           "app.example.com",
+			Path:              "/healthz",
+			Interval:          &metav1.Duration{Duration: time.Minute},
+			Protocol:          v1alpha1.HttpProtocol,
+			ResolveCNAMEChain: true,
+		},
+	}
+	status := http.StatusServiceUnavailable
+	w := &Probe{
+		Resolver: fakeChainResolver{
+			cnames:    map[string]string{"app.example.com": "lb.example.net"},
+			addresses: map[string][]string{"lb.example.net": {"10.0.0.1", "10.0.0.2"}},
+		},
+		Transport: func(r *http.Request) (*http.Response, error) {
+			return &http.Response{StatusCode: status}, nil
+		},
+	}
+
+	result := w.execute(context.Background(), probe)
+	if result.Healthy {
+		t.Fatalf("expected the probe to be unhealthy")
+	}
+	if len(result.TargetResults) != 2 || len(result.CNAMEChain) != 2 {
+		t.Fatalf("expected each address at the end of the chain to be probed got %v", result)
+	}
+	if !strings.Contains(result.Reason, "lb.example.net") {
+		t.Fatalf("expected the reason to name the end of the chain got %s", result.Reason)
+	}
+
+	status = http.StatusOK
+	result = w.execute(context.Background(), probe)
+	if !result.Healthy || result.Status != http.StatusOK || len(result.TargetResults) != 2 {
+		t.Fatalf("expected a healthy result for each address got %v", result)
+	}
+
+	probe.Spec.Address = "missing.example.com"
+	result = w.execute(context.Background(), probe)
+	if result.Healthy || !strings.Contains(result.Reason, "hop 1") || len(result.TargetResults) != 0 {
+		t.Fatalf("expected the probe to fail on the first hop got %v", result)
+	}
+}
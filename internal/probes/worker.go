@@ -2,7 +2,10 @@ package probes
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
+	"hash/fnv"
+	"math/rand"
 	"net"
 	"net/http"
 	"time"
@@ -22,6 +25,19 @@ var (
 	ExpectedResponses = []int{200, 201}
 
 	ProbeDelay = float64(time.Second.Milliseconds())
+
+	// ProbeTimeout is how long a request of a probe without a timeout, including resolving its address, can take
+	// before it fails
+	ProbeTimeout = PROBE_TIMEOUT
+
+	// ProbeSpread runs each probe at an offset into its interval derived from its name and namespace, instead of an
+	// interval after its last check, so probes created together are spread through the interval instead of running
+	// at the same time
+	ProbeSpread = false
+
+	// ProbeJitter is the fraction of its interval each wait before a probe runs is randomly shifted by, in either
+	// direction, 0 for none
+	ProbeJitter = 0.0
 )
 
 const (
@@ -38,6 +54,10 @@ type ProbeResult struct {
 	Healthy       bool
 	Reason        string
 	Status        int
+	// CNAMEChain the hops the address resolved through, only set when the probe resolves the CNAME chain
+	CNAMEChain []v1alpha1.CNAMEHop
+	// TargetResults the results of each of the addresses at the end of the CNAME chain
+	TargetResults []v1alpha1.ProbeTargetResult
 }
 
 type RoundTripperFunc func(*http.Request) (*http.Response, error)
@@ -47,7 +67,9 @@ func (fn RoundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) {
 }
 
 type Probe struct {
-	Transport    RoundTripperFunc
+	Transport RoundTripperFunc
+	// Resolver resolves the CNAME chain of probes that resolve it, defaults to querying the system nameservers
+	Resolver     ChainResolver
 	probeHeaders v1alpha1.AdditionalHeaders
 }
 
@@ -87,46 +109,117 @@ func (w *Probe) ExecuteProbe(ctx context.Context, probe *v1alpha1.DNSHealthCheck
 				// as this routine is just executing the local config it only cares about when it should execute again
 				// set the lastCheck based on the result
 				localProbe.Status.LastCheckedAt = result.CheckedAt
-				sig <- result
+				// the result is dropped if the worker is stopped while nobody is receiving it
+				select {
+				case sig <- result:
+				case <-ctx.Done():
+				}
 			}
 		}
 	}()
 	return sig
 }
 
+// probeTimeout returns how long a request of the probe, including resolving its address, can take before it fails.
+func probeTimeout(probe *v1alpha1.DNSHealthCheckProbe) time.Duration {
+	if probe.Spec.Timeout != nil && probe.Spec.Timeout.Duration > 0 {
+		return probe.Spec.Timeout.Duration
+	}
+	return ProbeTimeout
+}
+
 func executeAt(probe *v1alpha1.DNSHealthCheckProbe) time.Duration {
-	timeUntilProbe := time.
-		Until(probe.Status.LastCheckedAt.
-			Time.Add(probe.Spec.Interval.Duration))
+	return untilNextProbe(probe, time.Now())
+}
+
+// untilNextProbe returns how long after now the probe runs next. Probes run an interval after their last check, or at
+// the first offset of the probe into its interval that is at least half an interval after the last check when probes
+// are spread. Half an interval keeps a probe that finished quickly from running twice at the same offset, and a
+// probe that ran late from skipping an interval.
+func untilNextProbe(probe *v1alpha1.DNSHealthCheckProbe, now time.Time) time.Duration {
+	interval := probe.Spec.Interval.Duration
+	var timeUntilProbe time.Duration
+	if ProbeSpread && interval > 0 {
+		after := probe.Status.LastCheckedAt.Time.Add(interval / 2)
+		if after.Before(now) {
+			after = now
+		}
+		timeUntilProbe = nextPhase(probe, after).Sub(now)
+	} else {
+		timeUntilProbe = probe.Status.LastCheckedAt.Time.Add(interval).Sub(now)
+	}
 	if timeUntilProbe <= 0 {
 		return 0
 	}
-	return timeUntilProbe
+	return max(0, timeUntilProbe+jitter(interval))
+}
+
+// phaseOffset returns the offset into its interval the probe runs at when probes are spread, derived from the name
+// and namespace of the probe so it is the same for every worker of the probe.
+func phaseOffset(probe *v1alpha1.DNSHealthCheckProbe) time.Duration {
+	interval := probe.Spec.Interval.Duration
+	if interval <= 0 {
+		return 0
+	}
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(keyForProbe(probe)))
+	return time.Duration(h.Sum64() % uint64(interval))
+}
 
+// nextPhase returns the first time at or after the given time that is at the offset of the probe into its interval,
+// with intervals counted from the unix epoch.
+func nextPhase(probe *v1alpha1.DNSHealthCheckProbe, after time.Time) time.Time {
+	interval := probe.Spec.Interval.Duration
+	if interval <= 0 {
+		return after
+	}
+	into := time.Duration(after.UnixNano() % int64(interval))
+	return after.Add((phaseOffset(probe) - into + interval) % interval)
+}
+
+// jitter returns a random shift of up to ProbeJitter of the interval in either direction.
+func jitter(interval time.Duration) time.Duration {
+	if ProbeJitter <= 0 || interval <= 0 {
+		return 0
+	}
+	return time.Duration((rand.Float64()*2 - 1) * ProbeJitter * float64(interval))
 }
 
 func (w *Probe) execute(ctx context.Context, probe *v1alpha1.DNSHealthCheckProbe) ProbeResult {
 	logger := log.FromContext(ctx).WithValues("health probe worker:", keyForProbe(probe))
 	logger.V(2).Info("performing health check")
-	var ips []net.IP
-
-	//if the address is a CNAME, check all IP Addresses that it resolves to
-	logger.V(2).Info("looking up address ", "address", probe.Spec.Address)
-	ip := net.ParseIP(probe.Spec.Address)
+	var targets []string
 
-	if ip == nil {
-		IPAddr, err := net.LookupIP(probe.Spec.Address)
-		if err != nil {
-			logger.Error(err, "error looking up address", "address", probe.Spec.Address)
-			return ProbeResult{CheckedAt: metav1.Now(), Healthy: false, Reason: err.Error()}
+	if len(probe.Spec.Targets) > 0 {
+		// requests are sent to the targets instead of the address, e.g. when the address is fronted by a CDN
+		for _, target := range probe.Spec.Targets {
+			targets = append(targets, string(target))
 		}
-		ips = append(ips, IPAddr...)
+	} else if probe.Spec.ResolveCNAMEChain && net.ParseIP(probe.Spec.Address) == nil {
+		return w.executeChain(ctx, probe)
 	} else {
-		ips = append(ips, ip)
+		//if the address is a CNAME, check all IP Addresses that it resolves to
+		logger.V(2).Info("looking up address ", "address", probe.Spec.Address)
+		ip := net.ParseIP(probe.Spec.Address)
+
+		if ip == nil {
+			lookupCtx, cancel := context.WithTimeout(ctx, probeTimeout(probe))
+			IPAddr, err := net.DefaultResolver.LookupIP(lookupCtx, "ip", probe.Spec.Address)
+			cancel()
+			if err != nil {
+				logger.Error(err, "error looking up address", "address", probe.Spec.Address)
+				return ProbeResult{CheckedAt: metav1.Now(), Healthy: false, Reason: err.Error()}
+			}
+			for _, addr := range IPAddr {
+				targets = append(targets, addr.String())
+			}
+		} else {
+			targets = append(targets, ip.String())
+		}
 	}
 	var result ProbeResult
-	for _, ip = range ips {
-		result = w.performRequest(ctx, string(probe.Spec.Protocol), probe.Spec.Hostname, probe.Spec.Path, ip.String(), probe.Spec.Port, probe.Spec.AllowInsecureCertificate, w.probeHeaders)
+	for _, target := range targets {
+		result = w.performRequest(ctx, probe, target, w.probeHeaders)
 		// return as any healthy IP is a good result (multiple can only really happen with a CNAME)
 		if result.Healthy {
 			return result
@@ -137,10 +230,18 @@ func (w *Probe) execute(ctx context.Context, probe *v1alpha1.DNSHealthCheckProbe
 	return result
 }
 
-func (w *Probe) performRequest(ctx context.Context, protocol, host, path, ip string, port int, allowInsecure bool, headers v1alpha1.AdditionalHeaders) ProbeResult {
+// performRequest sends the request of the probe to the target, an IP address with an optional port. The port of the
+// probe is used if the target has no port.
+func (w *Probe) performRequest(ctx context.Context, probe *v1alpha1.DNSHealthCheckProbe, target string, headers v1alpha1.AdditionalHeaders) ProbeResult {
 	logger := log.FromContext(ctx).WithValues("health probe worker:", "preforming request")
+	protocol, host, path, port := string(probe.Spec.Protocol), probe.Spec.Hostname, probe.Spec.Path, probe.Spec.Port
+
+	serverName := probe.Spec.ServerName
+	if serverName == "" {
+		serverName = host
+	}
 	probeClient := metrics.NewInstrumentedClient("probe", &http.Client{
-		Transport: TransportWithDNSResponse(map[string]string{host: ip}, allowInsecure),
+		Transport: newProbeTransport(map[string]string{host: target}, serverName, probe.Spec.AllowInsecureCertificate, probeTimeout(probe)),
 	})
 	if w.Transport != nil {
 		probeClient.Transport = w.Transport
@@ -150,17 +251,22 @@ func (w *Probe) performRequest(ctx context.Context, protocol, host, path, ip str
 		url = fmt.Sprintf("%s://%s%s", protocol, host, path)
 	}
 
-	// Build the http request
+	// Build the http request, bounded by the timeout of the probe
+	ctx, cancel := context.WithTimeout(ctx, probeTimeout(probe))
+	defer cancel()
 	httpReq, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return ProbeResult{CheckedAt: metav1.Now(), Healthy: false, Reason: err.Error()}
 	}
+	if probe.Spec.HostHeader != "" {
+		httpReq.Host = probe.Spec.HostHeader
+	}
 
 	for _, h := range headers {
 		httpReq.Header.Add(h.Name, h.Value)
 	}
 
-	logger.V(2).Info("health: probe executing against ", "url", httpReq.URL)
+	logger.V(2).Info("health: probe executing against ", "url", httpReq.URL, "target", target)
 
 	// Send the request
 	res, err := probeClient.Do(httpReq)
@@ -187,12 +293,16 @@ func (w *Probe) performRequest(ctx context.Context, protocol, host, path, ip str
 }
 
 // TransportWithDNSResponse creates a new transport which overrides hostnames.
+// An override is an IP address, connected to on the port of the request, or an address with a port.
 func TransportWithDNSResponse(overrides map[string]string, allowInsecureCertificates bool) http.RoundTripper {
+	return newProbeTransport(overrides, "", allowInsecureCertificates, ProbeTimeout)
+}
+
+// newProbeTransport creates a new transport which overrides hostnames, and sends the given server name in TLS
+// handshakes instead of the hostname of the request when it is set. Connections are dialed with the given timeout.
+func newProbeTransport(overrides map[string]string, serverName string, allowInsecureCertificates bool, timeout time.Duration) *http.Transport {
 	transport := http.DefaultTransport.(*http.Transport).Clone()
-	dialer := &net.Dialer{
-		Timeout:   PROBE_TIMEOUT,
-		KeepAlive: PROBE_TIMEOUT,
-	}
+	dialer := probeDialer(timeout)
 
 	transport.DialContext = func(ctx context.Context, network, address string) (net.Conn, error) {
 		host, port, err := net.SplitHostPort(address)
@@ -203,15 +313,30 @@ func TransportWithDNSResponse(overrides map[string]string, allowInsecureCertific
 		if !ok {
 			return dialer.DialContext(ctx, network, address)
 		}
+		if _, _, err := net.SplitHostPort(newHost); err == nil {
+			return dialer.DialContext(ctx, network, newHost)
+		}
 		overrideAddress := net.JoinHostPort(newHost, port)
 		return dialer.DialContext(ctx, network, overrideAddress)
 	}
 
+	if transport.TLSClientConfig == nil {
+		transport.TLSClientConfig = &tls.Config{}
+	}
 	transport.TLSClientConfig.InsecureSkipVerify = allowInsecureCertificates
+	transport.TLSClientConfig.ServerName = serverName
 
 	return transport
 }
 
+// probeDialer returns the dialer of the connections of a probe with the given timeout
+func probeDialer(timeout time.Duration) *net.Dialer {
+	return &net.Dialer{
+		Timeout:   timeout,
+		KeepAlive: timeout,
+	}
+}
+
 type ProbeManager struct {
 	probes map[string]context.CancelFunc
 }
@@ -242,46 +367,73 @@ func (w *Probe) Start(clientctx context.Context, k8sClient client.Client, probe
 
 	go func() {
 		// jitter probe execution so we aren't starting at the same time
-		time.Sleep(common.RandomizeDuration(ProbeDelayVariance, ProbeDelay))
+		jitter := time.NewTimer(common.RandomizeDuration(ProbeDelayVariance, ProbeDelay))
+		select {
+		case <-ctx.Done():
+			jitter.Stop()
+			logger.V(1).Info("health: worker stopped before the first probe")
+			return
+		case <-jitter.C:
+		}
 
 		metrics.ProbeCounter.WithLabelValues(probe.Name, probe.Namespace, probe.Spec.Hostname).Inc()
+		defer func() {
+			logger.V(1).Info("health: stopped executing probe", "probe", keyForProbe(probe))
+			metrics.ProbeCounter.WithLabelValues(probe.Name, probe.Namespace, probe.Spec.Hostname).Dec()
+		}()
 		//each time the probe executes it will send a result on the channel returned by ExecuteProbe until the probe is cancelled. The probe can be cancelled by a new spec being created for the healthcheck or on shutdown
 		for probeResult := range w.ExecuteProbe(ctx, probe) {
 			freshProbe := &v1alpha1.DNSHealthCheckProbe{}
-			if err := k8sClient.Get(clientctx, client.ObjectKeyFromObject(probe), freshProbe); err != nil {
+			if err := k8sClient.Get(ctx, client.ObjectKeyFromObject(probe), freshProbe); err != nil {
+				// the worker was stopped while the probe was fetched
+				if ctx.Err() != nil {
+					return
+				}
 				// if we hit an error here we cancel and return as it is an unusual state
 				logger.Error(err, "health: probe finished. error getting upto date probe. Cancelling")
 				cancel()
 				return
 			}
 			freshProbe.Status.ObservedGeneration = freshProbe.Generation
-			if !probeResult.Healthy {
-				freshProbe.Status.ConsecutiveFailures++
-				if freshProbe.Status.ConsecutiveFailures > freshProbe.Spec.FailureThreshold {
-					freshProbe.Status.Healthy = &probeResult.Healthy
-				}
-			} else {
-				freshProbe.Status.ConsecutiveFailures = 0
-				freshProbe.Status.Healthy = &probeResult.Healthy
-			}
+			updateHealthStatus(freshProbe, probeResult)
 			logger.V(1).Info("health: execution complete ", "result", probeResult, "checked at", probeResult.CheckedAt.String(), "previoud check at ", probeResult.PreviousCheck)
 			freshProbe.Status.LastCheckedAt = probeResult.CheckedAt
 			freshProbe.Status.Reason = probeResult.Reason
 			freshProbe.Status.Status = probeResult.Status
+			freshProbe.Status.CNAMEChain = probeResult.CNAMEChain
+			freshProbe.Status.TargetResults = probeResult.TargetResults
 
 			logger.V(2).Info("health: probe finished updating status for probe", "status", freshProbe)
-			err := k8sClient.Status().Update(clientctx, freshProbe)
-			if err != nil {
+			err := k8sClient.Status().Update(ctx, freshProbe)
+			if err != nil && ctx.Err() == nil {
 				logger.Error(err, "health: probe finished. error updating probe status")
 			}
 		}
-
-		logger.V(1).Info("health: stopped executing probe", "probe", keyForProbe(probe))
-		metrics.ProbeCounter.WithLabelValues(probe.Name, probe.Namespace, probe.Spec.Hostname).Dec()
 	}()
 	return cancel
 }
 
+// updateHealthStatus updates the consecutive failure and success counters of the probe with the given result.
+// A healthy probe only becomes unhealthy once the failure threshold is exceeded, and a probe that is not healthy,
+// including one that has never been probed, only becomes healthy once it has passed the required number of times in a row.
+func updateHealthStatus(probe *v1alpha1.DNSHealthCheckProbe, probeResult ProbeResult) {
+	if !probeResult.Healthy {
+		probe.Status.ConsecutiveSuccesses = 0
+		probe.Status.ConsecutiveFailures++
+		if probe.Status.ConsecutiveFailures > probe.Spec.FailureThreshold {
+			probe.Status.Healthy = &probeResult.Healthy
+		}
+		return
+	}
+
+	probe.Status.ConsecutiveFailures = 0
+	probe.Status.ConsecutiveSuccesses++
+	isHealthy := probe.Status.Healthy != nil && *probe.Status.Healthy
+	if isHealthy || probe.Status.ConsecutiveSuccesses >= probe.Spec.RequiredPasses {
+		probe.Status.Healthy = &probeResult.Healthy
+	}
+}
+
 // EnsureProbeWorker ensures a new worker per generation of the probe.
 // New generation of probe - new worker.
 // If the generation has not changed, it will re-create a worker. If context is done (we are deleting) that worker will die immediately.
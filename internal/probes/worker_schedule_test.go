@@ -0,0 +1,85 @@
+package probes
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kuadrant/dns-operator/api/v1alpha1"
+)
+
+func scheduleTestProbe(name string, lastCheckedAt time.Time) *v1alpha1.DNSHealthCheckProbe {
+	return &v1alpha1.DNSHealthCheckProbe{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "test"},
+		Spec:       v1alpha1.DNSHealthCheckProbeSpec{Interval: &metav1.Duration{Duration: time.Minute}},
+		Status:     v1alpha1.DNSHealthCheckProbeStatus{LastCheckedAt: metav1.NewTime(lastCheckedAt)},
+	}
+}
+
+func setSchedule(t *testing.T, spread bool, jitter float64) {
+	spreadBefore, jitterBefore := ProbeSpread, ProbeJitter
+	ProbeSpread, ProbeJitter = spread, jitter
+	t.Cleanup(func() { ProbeSpread, ProbeJitter = spreadBefore, jitterBefore })
+}
+
+func TestUntilNextProbe_NotSpread(t *testing.T) {
+	setSchedule(t, false, 0)
+	now := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	if wait := untilNextProbe(scheduleTestProbe("probe", time.Time{}), now); wait != 0 {
+		t.Errorf("expected a probe that was never checked to run immediately, got %s", wait)
+	}
+	if wait := untilNextProbe(scheduleTestProbe("probe", now.Add(-20*time.Second)), now); wait != 40*time.Second {
+		t.Errorf("expected the probe to run an interval after its last check, got %s", wait)
+	}
+}
+
+func TestUntilNextProbe_Spread(t *testing.T) {
+	setSchedule(t, true, 0)
+	now := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	offsets := map[time.Duration]bool{}
+	for i := range 20 {
+		probe := scheduleTestProbe(fmt.Sprintf("probe-%d", i), time.Time{})
+		wait := untilNextProbe(probe, now)
+		if wait < 0 || wait >= time.Minute {
+			t.Fatalf("expected a new probe to run within its interval, got %s", wait)
+		}
+		if got := time.Duration(now.Add(wait).UnixNano() % int64(time.Minute)); got != phaseOffset(probe) {
+			t.Fatalf("expected the probe to run at its offset %s, got %s", phaseOffset(probe), got)
+		}
+		offsets[wait] = true
+
+		// the next run is at the same offset of the next interval, whether the check finished quickly or late
+		for _, took := range []time.Duration{0, 20 * time.Second} {
+			checkedAt := now.Add(wait + took)
+			next := untilNextProbe(scheduleTestProbe(probe.Name, checkedAt), checkedAt)
+			if next != time.Minute-took {
+				t.Fatalf("expected the probe to run %s after a check that took %s, got %s", time.Minute-took, took, next)
+			}
+		}
+	}
+	if len(offsets) < 15 {
+		t.Errorf("expected probes created together to be spread through the interval, got %d distinct offsets", len(offsets))
+	}
+}
+
+func TestUntilNextProbe_Jitter(t *testing.T) {
+	setSchedule(t, false, 0.1)
+	now := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	probe := scheduleTestProbe("probe", now)
+
+	waits := map[time.Duration]bool{}
+	for range 50 {
+		wait := untilNextProbe(probe, now)
+		if wait < 54*time.Second || wait > 66*time.Second {
+			t.Fatalf("expected the wait to be shifted by at most 10%% of the interval, got %s", wait)
+		}
+		waits[wait] = true
+	}
+	if len(waits) < 2 {
+		t.Errorf("expected the waits to be randomly shifted")
+	}
+}
@@ -0,0 +1,82 @@
+package probes
+
+import (
+	"testing"
+
+	"k8s.io/utils/ptr"
+
+	"github.com/kuadrant/dns-operator/api/v1alpha1"
+)
+
+func TestUpdateHealthStatus(t *testing.T) {
+	testCases := []struct {
+		name           string
+		healthy        *bool
+		requiredPasses int
+		results        []bool
+		expectHealthy  *bool
+	}{
+		{
+			name:          "new probe is healthy after first pass",
+			results:       []bool{true},
+			expectHealthy: ptr.To(true),
+		},
+		{
+			name:           "new probe is not healthy until required passes",
+			requiredPasses: 3,
+			results:        []bool{true, true},
+			expectHealthy:  nil,
+		},
+		{
+			name:           "new probe is healthy after required passes",
+			requiredPasses: 3,
+			results:        []bool{true, true, true},
+			expectHealthy:  ptr.To(true),
+		},
+		{
+			name:           "failure resets required passes",
+			requiredPasses: 3,
+			results:        []bool{true, true, false, true, true},
+			expectHealthy:  nil,
+		},
+		{
+			name:           "unhealthy probe is not healthy until required passes",
+			healthy:        ptr.To(false),
+			requiredPasses: 2,
+			results:        []bool{true},
+			expectHealthy:  ptr.To(false),
+		},
+		{
+			name:           "healthy probe stays healthy regardless of required passes",
+			healthy:        ptr.To(true),
+			requiredPasses: 3,
+			results:        []bool{false, true},
+			expectHealthy:  ptr.To(true),
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			probe := &v1alpha1.DNSHealthCheckProbe{
+				Spec: v1alpha1.DNSHealthCheckProbeSpec{
+					FailureThreshold: 3,
+					RequiredPasses:   testCase.requiredPasses,
+				},
+				Status: v1alpha1.DNSHealthCheckProbeStatus{
+					Healthy: testCase.healthy,
+				},
+			}
+			for _, result := range testCase.results {
+				updateHealthStatus(probe, ProbeResult{Healthy: result})
+			}
+			if testCase.expectHealthy == nil {
+				if probe.Status.Healthy != nil {
+					t.Fatalf("expected healthy to be unset, got %v", *probe.Status.Healthy)
+				}
+				return
+			}
+			if probe.Status.Healthy == nil || *probe.Status.Healthy != *testCase.expectHealthy {
+				t.Fatalf("expected healthy to be %v, got %v", *testCase.expectHealthy, probe.Status.Healthy)
+			}
+		})
+	}
+}
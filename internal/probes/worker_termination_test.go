@@ -0,0 +1,199 @@
+package probes
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kuadrant/dns-operator/api/v1alpha1"
+	"github.com/kuadrant/dns-operator/internal/metrics"
+)
+
+func terminationTestProbe() *v1alpha1.DNSHealthCheckProbe {
+	return &v1alpha1.DNSHealthCheckProbe{
+		ObjectMeta: metav1.ObjectMeta{Name: "termination", Namespace: "test"},
+		Spec: v1alpha1.DNSHealthCheckProbeSpec{
+			Hostname:         "example.com",
+			Address:          "192.0.2.10",
+			Path:             "/healthz",
+			Interval:         &metav1.Duration{Duration: time.Millisecond},
+			Protocol:         v1alpha1.HttpProtocol,
+			FailureThreshold: 3,
+		},
+	}
+}
+
+func healthyTransport(r *http.Request) (*http.Response, error) {
+	return &http.Response{StatusCode: 200}, nil
+}
+
+func TestExecuteProbe_StopsWithoutReceiver(t *testing.T) {
+	w := &Probe{Transport: healthyTransport}
+	ctx, cancel := context.WithCancel(context.Background())
+	results := w.ExecuteProbe(ctx, terminationTestProbe())
+
+	// give the probe time to execute and block on sending its result
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+
+	timeout := time.After(time.Second)
+	for {
+		select {
+		case _, ok := <-results:
+			if !ok {
+				return
+			}
+		case <-timeout:
+			t.Fatalf("expected the results channel to be closed once the worker is stopped")
+		}
+	}
+}
+
+func TestProbe_RequestTimeout(t *testing.T) {
+	blocking := func(r *http.Request) (*http.Response, error) {
+		<-r.Context().Done()
+		return nil, r.Context().Err()
+	}
+	w := &Probe{Transport: blocking}
+
+	probe := terminationTestProbe()
+	probe.Spec.Timeout = &metav1.Duration{Duration: 50 * time.Millisecond}
+	if got := probeTimeout(probe); got != 50*time.Millisecond {
+		t.Fatalf("expected the timeout of the probe, got %s", got)
+	}
+
+	start := time.Now()
+	result := w.execute(context.Background(), probe)
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("expected the request to time out after 50ms, took %s", elapsed)
+	}
+	if result.Healthy {
+		t.Fatalf("expected a request that timed out to be unhealthy")
+	}
+	if !strings.Contains(result.Reason, context.DeadlineExceeded.Error()) {
+		t.Fatalf("expected the reason to report the deadline, got %q", result.Reason)
+	}
+
+	probe.Spec.Timeout = nil
+	if got := probeTimeout(probe); got != ProbeTimeout {
+		t.Fatalf("expected the default timeout, got %s", got)
+	}
+}
+
+func TestProbe_DialTimeout(t *testing.T) {
+	probe := terminationTestProbe()
+	probe.Spec.Timeout = &metav1.Duration{Duration: 5 * time.Second}
+
+	// the connection of a probe may take as long as the probe, rather than the default timeout
+	if got := probeDialer(probeTimeout(probe)).Timeout; got != 5*time.Second {
+		t.Fatalf("expected connections to be dialed with the timeout of the probe, got %s", got)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	serverURL, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+
+	client := &http.Client{
+		Transport: newProbeTransport(map[string]string{probe.Spec.Hostname: serverURL.Host}, "", false, probeTimeout(probe)),
+	}
+	res, err := client.Get(fmt.Sprintf("http://%s/", probe.Spec.Hostname))
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		t.Fatalf("expected status %d got %d", http.StatusOK, res.StatusCode)
+	}
+}
+
+func TestStart_TerminatesWorker(t *testing.T) {
+	if err := v1alpha1.AddToScheme(scheme.Scheme); err != nil {
+		t.Fatal(err)
+	}
+	delay := ProbeDelay
+	ProbeDelay = 10
+	t.Cleanup(func() { ProbeDelay = delay })
+
+	active := func(probe *v1alpha1.DNSHealthCheckProbe) float64 {
+		return testutil.ToFloat64(metrics.ProbeCounter.WithLabelValues(probe.Name, probe.Namespace, probe.Spec.Hostname))
+	}
+	eventually := func(t *testing.T, condition func() bool, message string) {
+		t.Helper()
+		deadline := time.Now().Add(2 * time.Second)
+		for !condition() {
+			if time.Now().After(deadline) {
+				t.Fatal(message)
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+	}
+
+	t.Run("stopped", func(t *testing.T) {
+		probe := terminationTestProbe()
+		k8sClient := fake.NewClientBuilder().WithStatusSubresource(probe).WithObjects(probe.DeepCopy()).Build()
+		stop := (&Probe{Transport: healthyTransport}).Start(context.Background(), k8sClient, probe)
+
+		eventually(t, func() bool { return active(probe) == 1 }, "expected the worker to start probing")
+		stop()
+		eventually(t, func() bool { return active(probe) == 0 }, "expected the worker to terminate once stopped")
+	})
+
+	t.Run("probe deleted", func(t *testing.T) {
+		probe := terminationTestProbe()
+		probe.Name = "deleted"
+		k8sClient := fake.NewClientBuilder().WithStatusSubresource(probe).WithObjects(probe.DeepCopy()).Build()
+		stop := (&Probe{Transport: healthyTransport}).Start(context.Background(), k8sClient, probe)
+		defer stop()
+
+		// the probe is only deleted once the worker has run it and written its status
+		eventually(t, func() bool {
+			current := &v1alpha1.DNSHealthCheckProbe{}
+			if err := k8sClient.Get(context.Background(), client.ObjectKeyFromObject(probe), current); err != nil {
+				t.Fatal(err)
+			}
+			return current.Status.ConsecutiveSuccesses > 0
+		}, "expected the worker to run the probe")
+		if active(probe) != 1 {
+			t.Fatalf("expected the worker to be probing")
+		}
+		if err := k8sClient.Delete(context.Background(), probe.DeepCopy()); err != nil {
+			t.Fatal(err)
+		}
+
+		// the worker stops itself once the probe can't be fetched
+		eventually(t, func() bool { return active(probe) == 0 }, "expected the worker to terminate once the probe is gone")
+	})
+
+	t.Run("stopped before the first probe", func(t *testing.T) {
+		ProbeDelay = float64(time.Hour.Milliseconds())
+		probe := terminationTestProbe()
+		probe.Name = "jitter"
+		k8sClient := fake.NewClientBuilder().WithObjects(probe.DeepCopy()).Build()
+		stop := (&Probe{Transport: func(r *http.Request) (*http.Response, error) {
+			t.Errorf("expected no request once the worker is stopped")
+			return healthyTransport(r)
+		}}).Start(context.Background(), k8sClient, probe)
+		stop()
+		time.Sleep(20 * time.Millisecond)
+		if active(probe) != 0 {
+			t.Fatalf("expected the worker to terminate before probing")
+		}
+	})
+}
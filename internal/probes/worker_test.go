@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"sync"
 	"testing"
 	"time"
@@ -127,6 +129,41 @@ func TestWorker_ProbeSuccess(t *testing.T) {
 				}
 			},
 		},
+		{
+			Name: "test health check with host header and targets",
+			Transport: func(headers v1alpha1.AdditionalHeaders) probes.RoundTripperFunc {
+				return func(r *http.Request) (*http.Response, error) {
+					if r.Host != "app.example.com" {
+						return &http.Response{
+							StatusCode: 421,
+						}, nil
+					}
+					return &http.Response{
+						StatusCode: 200,
+					}, nil
+				}
+			},
+			ProbeConfig: func() *v1alpha1.DNSHealthCheckProbe {
+				probe := testProbe.DeepCopy()
+				probe.Spec.HostHeader = "app.example.com"
+				probe.Spec.Targets = []v1alpha1.ProbeTarget{"192.0.2.10:8443", "192.0.2.11"}
+				return probe
+			},
+			ExpectedProbeCalls: 2,
+			Validate: func(t *testing.T, results []probes.ProbeResult, tt *testTransport, expectedCalls int) {
+				if len(results) != expectedCalls {
+					t.Fatalf("expected %v results got %v", expectedCalls, len(results))
+				}
+				lastResult := results[expectedCalls-1]
+				if !lastResult.Healthy {
+					t.Fatalf("expected the result of the probe to be healthy but got reason %s", lastResult.Reason)
+				}
+				// the first target is healthy so the second is never requested
+				if tt.calls != expectedCalls {
+					t.Fatalf("expected %v requests got %v", expectedCalls, tt.calls)
+				}
+			},
+		},
 	}
 
 	for _, test := range testCases {
@@ -167,3 +204,50 @@ func TestWorker_ProbeSuccess(t *testing.T) {
 		})
 	}
 }
+
+func TestTransportWithDNSResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	serverURL, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+
+	testCases := []struct {
+		Name     string
+		Override string
+	}{
+		{
+			Name:     "override with an ip address uses the port of the request",
+			Override: serverURL.Hostname(),
+		},
+		{
+			Name:     "override with an address uses the port of the address",
+			Override: serverURL.Host,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.Name, func(t *testing.T) {
+			client := &http.Client{
+				Transport: probes.TransportWithDNSResponse(map[string]string{"probe.example.test": test.Override}, false),
+			}
+			port := serverURL.Port()
+			if test.Override == serverURL.Host {
+				// the request port is not listened on, the connection must be made to the override
+				port = "1"
+			}
+			res, err := client.Get(fmt.Sprintf("http://probe.example.test:%s/", port))
+			if err != nil {
+				t.Fatalf("unexpected error %s", err)
+			}
+			defer res.Body.Close()
+			if res.StatusCode != http.StatusCreated {
+				t.Fatalf("expected status %d got %d", http.StatusCreated, res.StatusCode)
+			}
+		})
+	}
+}
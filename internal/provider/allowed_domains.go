@@ -0,0 +1,89 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	externaldnsendpoint "sigs.k8s.io/external-dns/endpoint"
+	"sigs.k8s.io/external-dns/plan"
+
+	"github.com/kuadrant/dns-operator/api/v1alpha1"
+)
+
+// ErrDomainNotAllowed is returned for zones and endpoints outside the domains allowed by the provider secret.
+var ErrDomainNotAllowed = errors.New("domain not allowed by the provider secret")
+
+// AllowedDomainsFromSecret returns the domains allowed by the given provider secret with the ALLOWED_DOMAINS key, or
+// nil if the secret allows all domains.
+func AllowedDomainsFromSecret(s *v1.Secret) []string {
+	var domains []string
+	for _, domain := range strings.Split(string(s.Data[v1alpha1.AllowedDomainsKey]), ",") {
+		if domain = strings.ToLower(strings.TrimSuffix(strings.TrimSpace(domain), ".")); domain != "" {
+			domains = append(domains, domain)
+		}
+	}
+	return domains
+}
+
+// allowedDomainsProvider is a Provider for credentials that may only publish to zones in the allowed domains, so a
+// leaked or overly broad credential can't be used to publish other domains of the account.
+type allowedDomainsProvider struct {
+	Provider
+	domains externaldnsendpoint.DomainFilter
+}
+
+var _ Provider = &allowedDomainsProvider{}
+
+// allowed returns an error if the given name is not in one of the allowed domains.
+func (p *allowedDomainsProvider) allowed(kind, name string) error {
+	if !p.domains.Match(name) {
+		return fmt.Errorf("%w: %s %s is not in the allowed domains %s", ErrDomainNotAllowed, kind, name, strings.Join(p.domains.Filters, ", "))
+	}
+	return nil
+}
+
+// DNSZones returns the zones of the provider in the allowed domains.
+func (p *allowedDomainsProvider) DNSZones(ctx context.Context) ([]DNSZone, error) {
+	zones, err := p.Provider.DNSZones(ctx)
+	if err != nil {
+		return nil, err
+	}
+	allowed := []DNSZone{}
+	for _, zone := range zones {
+		if p.allowed("zone", zone.DNSName) == nil {
+			allowed = append(allowed, zone)
+		}
+	}
+	return allowed, nil
+}
+
+// DNSZoneForHost returns the zone of the host, or an error if the zone is not in the allowed domains.
+func (p *allowedDomainsProvider) DNSZoneForHost(ctx context.Context, host string) (*DNSZone, error) {
+	zone, err := p.Provider.DNSZoneForHost(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+	if err = p.allowed("zone", zone.DNSName); err != nil {
+		return nil, err
+	}
+	return zone, nil
+}
+
+// ApplyChanges applies the changes if all created and updated endpoints are in the allowed domains, endpoints can
+// always be deleted.
+func (p *allowedDomainsProvider) ApplyChanges(ctx context.Context, changes *plan.Changes) error {
+	for _, ep := range append(append([]*externaldnsendpoint.Endpoint{}, changes.Create...), changes.UpdateNew...) {
+		if err := p.allowed("endpoint", ep.DNSName); err != nil {
+			return err
+		}
+	}
+	return p.Provider.ApplyChanges(ctx, changes)
+}
+
+// Unwrap returns the Provider of the allowed domains.
+func (p *allowedDomainsProvider) Unwrap() Provider {
+	return p.Provider
+}
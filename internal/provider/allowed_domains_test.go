@@ -0,0 +1,102 @@
+//go:build unit
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/external-dns/endpoint"
+	"sigs.k8s.io/external-dns/plan"
+
+	"github.com/kuadrant/dns-operator/api/v1alpha1"
+)
+
+type zonesProvider struct {
+	Provider
+	zones   []DNSZone
+	applied int
+}
+
+func (p *zonesProvider) DNSZones(_ context.Context) ([]DNSZone, error) {
+	return p.zones, nil
+}
+
+func (p *zonesProvider) DNSZoneForHost(ctx context.Context, host string) (*DNSZone, error) {
+	return FindDNSZoneForHost(ctx, host, p.zones)
+}
+
+func (p *zonesProvider) ApplyChanges(_ context.Context, _ *plan.Changes) error {
+	p.applied++
+	return nil
+}
+
+func TestAllowedDomainsFromSecret(t *testing.T) {
+	testCases := []struct {
+		name     string
+		data     map[string][]byte
+		expected []string
+	}{
+		{
+			name: "all domains allowed",
+			data: map[string][]byte{},
+		},
+		{
+			name:     "allowed domains",
+			data:     map[string][]byte{v1alpha1.AllowedDomainsKey: []byte(" Example.com., ,apps.example.org")},
+			expected: []string{"example.com", "apps.example.org"},
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			if domains := AllowedDomainsFromSecret(&v1.Secret{Data: tt.data}); !reflect.DeepEqual(domains, tt.expected) {
+				t.Errorf("AllowedDomainsFromSecret() = %v, want %v", domains, tt.expected)
+			}
+		})
+	}
+}
+
+func TestAllowedDomainsProvider(t *testing.T) {
+	ctx := context.Background()
+	inner := &zonesProvider{zones: []DNSZone{
+		{ID: "Z1", DNSName: "example.com"},
+		{ID: "Z2", DNSName: "apps.example.org"},
+		{ID: "Z3", DNSName: "example.org"},
+	}}
+	p := &allowedDomainsProvider{Provider: inner, domains: endpoint.NewDomainFilter([]string{"example.com", "apps.example.org"})}
+
+	zones, err := p.DNSZones(ctx)
+	if err != nil {
+		t.Fatalf("DNSZones() unexpected error %v", err)
+	}
+	if len(zones) != 2 || zones[0].ID != "Z1" || zones[1].ID != "Z2" {
+		t.Errorf("DNSZones() = %v, want the zones in the allowed domains", zones)
+	}
+
+	for _, host := range []string{"foo.example.com", "foo.apps.example.org"} {
+		if _, err := p.DNSZoneForHost(ctx, host); err != nil {
+			t.Errorf("DNSZoneForHost(%s) unexpected error %v", host, err)
+		}
+	}
+	if _, err := p.DNSZoneForHost(ctx, "foo.example.org"); !errors.Is(err, ErrDomainNotAllowed) {
+		t.Errorf("DNSZoneForHost(foo.example.org) error = %v, want %v", err, ErrDomainNotAllowed)
+	}
+
+	allowed := endpoint.NewEndpoint("foo.example.com", endpoint.RecordTypeA, "1.1.1.1")
+	notAllowed := endpoint.NewEndpoint("foo.example.org", endpoint.RecordTypeA, "1.1.1.1")
+	if err := p.ApplyChanges(ctx, &plan.Changes{Create: []*endpoint.Endpoint{allowed}}); err != nil {
+		t.Errorf("ApplyChanges() unexpected error %v", err)
+	}
+	if err := p.ApplyChanges(ctx, &plan.Changes{UpdateNew: []*endpoint.Endpoint{notAllowed}}); !errors.Is(err, ErrDomainNotAllowed) {
+		t.Errorf("ApplyChanges() error = %v, want %v", err, ErrDomainNotAllowed)
+	}
+	if err := p.ApplyChanges(ctx, &plan.Changes{Delete: []*endpoint.Endpoint{notAllowed}}); err != nil {
+		t.Errorf("ApplyChanges() unexpected error %v deleting endpoints", err)
+	}
+	if inner.applied != 2 {
+		t.Errorf("applied = %d, want 2", inner.applied)
+	}
+}
@@ -18,14 +18,19 @@ package aws
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
+	"github.com/aws/aws-sdk-go/aws/endpoints"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/route53"
+	"github.com/aws/aws-sdk-go/service/sts"
 	"github.com/go-logr/logr"
 
 	v1 "k8s.io/api/core/v1"
@@ -34,6 +39,7 @@ import (
 	externaldnsprovider "sigs.k8s.io/external-dns/provider"
 
 	"github.com/kuadrant/dns-operator/api/v1alpha1"
+	externaldnsplan "github.com/kuadrant/dns-operator/internal/external-dns/plan"
 	externaldnsprovideraws "github.com/kuadrant/dns-operator/internal/external-dns/provider/aws"
 	"github.com/kuadrant/dns-operator/internal/metrics"
 	"github.com/kuadrant/dns-operator/internal/provider"
@@ -44,6 +50,8 @@ const (
 	providerSpecificWeight                   = "aws/weight"
 	providerSpecificGeolocationCountryCode   = "aws/geolocation-country-code"
 	providerSpecificGeolocationContinentCode = "aws/geolocation-continent-code"
+	providerSpecificMultiValueAnswer         = "aws/multi-value-answer"
+	providerSpecificAlias                    = "alias"
 	awsBatchChangeSize                       = 1000
 	awsBatchChangeInterval                   = time.Second
 	awsEvaluateTargetHealth                  = false
@@ -60,30 +68,24 @@ type Route53DNSProvider struct {
 }
 
 var _ provider.Provider = &Route53DNSProvider{}
+var _ provider.ChangeTracker = &Route53DNSProvider{}
+var _ provider.RecordTypeSupporter = &Route53DNSProvider{}
 
 func NewProviderFromSecret(ctx context.Context, s *v1.Secret, c provider.Config) (provider.Provider, error) {
 	config := aws.NewConfig()
 
 	config.WithHTTPClient(metrics.NewInstrumentedClient("aws", config.HTTPClient))
 
-	sessionOpts := session.Options{
-		Config: *config,
-	}
-	if string(s.Data[v1alpha1.AWSAccessKeyIDKey]) == "" || string(s.Data[v1alpha1.AWSSecretAccessKeyKey]) == "" {
-		return nil, fmt.Errorf("AWS Provider credentials is empty")
-	}
-
-	sessionOpts.Config.Credentials = credentials.NewStaticCredentials(string(s.Data[v1alpha1.AWSAccessKeyIDKey]), string(s.Data[v1alpha1.AWSSecretAccessKeyKey]), "")
-	sessionOpts.SharedConfigState = session.SharedConfigDisable
-	sess, err := session.NewSessionWithOptions(sessionOpts)
+	sess, err := newSessionFromSecret(s, config)
 	if err != nil {
-		return nil, fmt.Errorf("unable to create aws session: %s", err)
-	}
-	if string(s.Data[v1alpha1.AWSRegionKey]) != "" {
-		sess.Config.WithRegion(string(s.Data[v1alpha1.AWSRegionKey]))
+		return nil, err
 	}
 
-	route53Client := route53.New(sess, config)
+	route53Config := aws.NewConfig()
+	if endpoint := string(s.Data[v1alpha1.AWSEndpointURLKey]); endpoint != "" {
+		route53Config.WithEndpoint(endpoint)
+	}
+	route53Client := route53.New(sess, route53Config)
 
 	awsConfig := externaldnsprovideraws.AWSConfig{
 		DomainFilter:         c.DomainFilter,
@@ -98,7 +100,17 @@ func NewProviderFromSecret(ctx context.Context, s *v1.Secret, c provider.Config)
 		ZoneCacheDuration:    awsZoneCacheDuration,
 	}
 
-	logger := log.FromContext(ctx).WithName("aws-dns").WithValues("region", config.Region)
+	zone, err := provider.DeclaredZoneFromSecret(s)
+	if err != nil {
+		return nil, err
+	}
+	if zone != nil {
+		awsConfig.StaticZones = map[string]*route53.HostedZone{
+			zone.ID: {Id: aws.String(zone.ID), Name: aws.String(zone.DNSName + ".")},
+		}
+	}
+
+	logger := log.FromContext(ctx).WithName("aws-dns").WithValues("region", aws.StringValue(sess.Config.Region))
 	ctx = log.IntoContext(ctx, logger)
 
 	awsProvider, err := externaldnsprovideraws.NewAWSProvider(ctx, awsConfig, route53Client)
@@ -115,6 +127,48 @@ func NewProviderFromSecret(ctx context.Context, s *v1.Secret, c provider.Config)
 	return p, nil
 }
 
+// newSessionFromSecret returns an aws session configured with the credentials and region in the given secret.
+//
+// The region is set before any clients are created so that the endpoints for route53 and sts are resolved from the
+// partition the region belongs to (e.g. aws-us-gov, aws-cn) rather than the default aws partition. If a role arn is
+// provided, the static credentials are used to assume the role against the regional sts endpoint, or the sts endpoint
+// override if one is set.
+func newSessionFromSecret(s *v1.Secret, config *aws.Config) (*session.Session, error) {
+	if string(s.Data[v1alpha1.AWSAccessKeyIDKey]) == "" || string(s.Data[v1alpha1.AWSSecretAccessKeyKey]) == "" {
+		return nil, fmt.Errorf("AWS Provider credentials is empty")
+	}
+
+	config.WithCredentials(credentials.NewStaticCredentials(string(s.Data[v1alpha1.AWSAccessKeyIDKey]), string(s.Data[v1alpha1.AWSSecretAccessKeyKey]), ""))
+	config.WithSTSRegionalEndpoint(endpoints.RegionalSTSEndpoint)
+	if region := string(s.Data[v1alpha1.AWSRegionKey]); region != "" {
+		if _, ok := endpoints.PartitionForRegion(endpoints.DefaultPartitions(), region); !ok {
+			return nil, fmt.Errorf("unknown AWS region %s", region)
+		}
+		config.WithRegion(region)
+	}
+
+	sessionOpts := session.Options{
+		Config:            *config,
+		SharedConfigState: session.SharedConfigDisable,
+	}
+	sess, err := session.NewSessionWithOptions(sessionOpts)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create aws session: %s", err)
+	}
+
+	roleARN := string(s.Data[v1alpha1.AWSRoleARNKey])
+	if roleARN == "" {
+		return sess, nil
+	}
+
+	stsConfig := aws.NewConfig()
+	if stsEndpoint := string(s.Data[v1alpha1.AWSSTSEndpointKey]); stsEndpoint != "" {
+		stsConfig.WithEndpoint(stsEndpoint)
+	}
+	sess.Config.WithCredentials(stscreds.NewCredentialsWithClient(sts.New(sess, stsConfig), roleARN))
+	return sess, nil
+}
+
 // #### External DNS Provider ####
 
 func (p *Route53DNSProvider) AdjustEndpoints(endpoints []*externaldnsendpoint.Endpoint) ([]*externaldnsendpoint.Endpoint, error) {
@@ -124,6 +178,10 @@ func (p *Route53DNSProvider) AdjustEndpoints(endpoints []*externaldnsendpoint.En
 	}
 	p.logger.V(1).Info("adjusting aws endpoints")
 	for _, ep := range endpoints {
+		if err := adjustMultiValueEndpoint(ep); err != nil {
+			return nil, err
+		}
+
 		if prop, ok := ep.GetProviderSpecificProperty(v1alpha1.ProviderSpecificWeight); ok {
 			ep.DeleteProviderSpecificProperty(v1alpha1.ProviderSpecificWeight)
 			ep.WithProviderSpecific(providerSpecificWeight, prop)
@@ -155,6 +213,36 @@ func (p *Route53DNSProvider) AdjustEndpoints(endpoints []*externaldnsendpoint.En
 	return endpoints, nil
 }
 
+// adjustMultiValueEndpoint replaces the multi-value property of the endpoint with the multivalue answer property of
+// Route53. The value of the Route53 property is empty, as it is when the record set is read from Route53.
+func adjustMultiValueEndpoint(ep *externaldnsendpoint.Endpoint) error {
+	prop, ok := ep.GetProviderSpecificProperty(v1alpha1.ProviderSpecificMultiValue)
+	if !ok {
+		return nil
+	}
+	ep.DeleteProviderSpecificProperty(v1alpha1.ProviderSpecificMultiValue)
+	if prop != "true" {
+		return nil
+	}
+
+	if ep.SetIdentifier == "" {
+		return fmt.Errorf("multi-value endpoint %s must have a set identifier", ep.DNSName)
+	}
+	if len(ep.Targets) != 1 {
+		return fmt.Errorf("multi-value endpoint %s must have a single target, got %d", ep.DNSName, len(ep.Targets))
+	}
+	if alias, _ := ep.GetProviderSpecificProperty(providerSpecificAlias); alias == "true" {
+		return fmt.Errorf("multi-value endpoint %s can't be an alias record", ep.DNSName)
+	}
+	for _, routing := range []string{v1alpha1.ProviderSpecificWeight, v1alpha1.ProviderSpecificGeoCode} {
+		if _, ok := ep.GetProviderSpecificProperty(routing); ok {
+			return fmt.Errorf("multi-value endpoint %s can't also set %s", ep.DNSName, routing)
+		}
+	}
+	ep.WithProviderSpecific(providerSpecificMultiValueAnswer, "")
+	return nil
+}
+
 // #### DNS Operator Provider ####
 
 func (p *Route53DNSProvider) DNSZones(ctx context.Context) ([]provider.DNSZone, error) {
@@ -168,6 +256,7 @@ func (p *Route53DNSProvider) DNSZones(ctx context.Context) ([]provider.DNSZone,
 		hz := provider.DNSZone{
 			ID:      *z.Id,
 			DNSName: strings.ToLower(strings.TrimSuffix(*z.Name, ".")),
+			Private: z.Config != nil && aws.BoolValue(z.Config.PrivateZone),
 		}
 		hzs = append(hzs, hz)
 	}
@@ -189,7 +278,60 @@ func (*Route53DNSProvider) ProviderSpecific() provider.ProviderSpecificLabels {
 	}
 }
 
+// NormalizeTarget unescapes wildcards in target values, Route53 returns alias and CNAME targets with '*' escaped as
+// '\052' in addition to the normalization done for all providers.
+func (*Route53DNSProvider) NormalizeTarget(recordType, target string) string {
+	return externaldnsplan.NormalizeTarget(recordType, strings.ReplaceAll(target, "\\052", "*"))
+}
+
+// SupportsRecordType returns true for NAPTR records, Route53 doesn't support TLSA records.
+func (*Route53DNSProvider) SupportsRecordType(recordType string) bool {
+	return recordType == v1alpha1.RecordTypeNAPTR
+}
+
+// PendingChanges returns the ids of the given change batches Route53 reports as still PENDING, the remaining changes
+// are INSYNC on all Route53 nameservers.
+func (p *Route53DNSProvider) PendingChanges(ctx context.Context, ids []string) ([]string, error) {
+	var pending []string
+	for _, id := range ids {
+		out, err := p.route53Client.GetChangeWithContext(ctx, &route53.GetChangeInput{Id: aws.String(id)})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get change %s: %w", id, err)
+		}
+		if aws.StringValue(out.ChangeInfo.Status) == route53.ChangeStatusPending {
+			pending = append(pending, id)
+		}
+	}
+	return pending, nil
+}
+
+// classifyError returns the code of an error returned by the AWS SDK.
+func classifyError(err error) (provider.ErrorCode, bool) {
+	var awsErr awserr.Error
+	if !errors.As(err, &awsErr) {
+		return "", false
+	}
+	switch awsErr.Code() {
+	case "Throttling", "RequestLimitExceeded", route53.ErrCodeThrottlingException, route53.ErrCodePriorRequestNotComplete,
+		route53.ErrCodeConcurrentModification:
+		return provider.ErrorCodeThrottled, true
+	case "AccessDenied", "AccessDeniedException", "InvalidClientTokenId", "SignatureDoesNotMatch", "UnrecognizedClientException",
+		"ExpiredToken", "ExpiredTokenException", "NoCredentialProviders":
+		return provider.ErrorCodeUnauthorized, true
+	case route53.ErrCodeNoSuchHostedZone, route53.ErrCodeNoSuchChange, route53.ErrCodeNoSuchHealthCheck:
+		return provider.ErrorCodeNotFound, true
+	case route53.ErrCodeInvalidChangeBatch, route53.ErrCodeInvalidInput:
+		return provider.ErrorCodeInvalidRequest, true
+	}
+	var reqErr awserr.RequestFailure
+	if errors.As(err, &reqErr) {
+		return provider.ErrorCodeForStatus(reqErr.StatusCode()), true
+	}
+	return provider.ErrorCodeUnknown, true
+}
+
 // Register this Provider with the provider factory
 func init() {
 	provider.RegisterProvider("aws", NewProviderFromSecret, true)
+	provider.RegisterErrorClassifier("aws", classifyError)
 }
@@ -1,12 +1,26 @@
 package aws
 
 import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/route53"
+	"github.com/aws/aws-sdk-go/service/sts"
+
+	v1 "k8s.io/api/core/v1"
 	"sigs.k8s.io/external-dns/endpoint"
 	externaldnsendpoint "sigs.k8s.io/external-dns/endpoint"
 
 	"github.com/kuadrant/dns-operator/api/v1alpha1"
+	"github.com/kuadrant/dns-operator/internal/provider"
 )
 
 const recordTTL = 300
@@ -98,6 +112,67 @@ func TestAWSAdjustEndpoints(t *testing.T) {
 
 			},
 		},
+		{
+			Name: "test multi-value endpoint with health check success",
+			Endpoints: []*externaldnsendpoint.Endpoint{
+				endpoint.NewEndpointWithTTL("multivalue-test.zone-1.ext-dns-test-2.teapot.zalan.do", endpoint.RecordTypeA, endpoint.TTL(recordTTL), "1.2.3.4").WithSetIdentifier("test-set-1").
+					WithProviderSpecific(v1alpha1.ProviderSpecificMultiValue, "true").WithProviderSpecific(ProviderSpecificHealthCheckID, "hc-1"),
+			},
+			Validate: func(t *testing.T, eps []*externaldnsendpoint.Endpoint, err error) {
+				if err != nil {
+					t.Fatalf("did not expect an error but got %s", err)
+				}
+				if len(eps) != 1 {
+					t.Fatalf("expected 1 endpoint but got %v", len(eps))
+				}
+				if val, ok := eps[0].GetProviderSpecificProperty(providerSpecificMultiValueAnswer); !ok || val != "" {
+					t.Fatalf("expected an empty provider specific multi value answer to be set but got %q, %v", val, ok)
+				}
+				if _, ok := eps[0].GetProviderSpecificProperty(v1alpha1.ProviderSpecificMultiValue); ok {
+					t.Fatalf("expected the multi-value property to be removed")
+				}
+				if val, _ := eps[0].GetProviderSpecificProperty(ProviderSpecificHealthCheckID); val != "hc-1" {
+					t.Fatalf("expected the health check id to be kept but got %q", val)
+				}
+			},
+		},
+		{
+			Name: "test multi-value endpoint set to false is not multi value",
+			Endpoints: []*externaldnsendpoint.Endpoint{
+				endpoint.NewEndpointWithTTL("multivalue-test.zone-1.ext-dns-test-2.teapot.zalan.do", endpoint.RecordTypeA, endpoint.TTL(recordTTL), "1.2.3.4").
+					WithProviderSpecific(v1alpha1.ProviderSpecificMultiValue, "false"),
+			},
+			Validate: func(t *testing.T, eps []*externaldnsendpoint.Endpoint, err error) {
+				if err != nil {
+					t.Fatalf("did not expect an error but got %s", err)
+				}
+				if len(eps[0].ProviderSpecific) != 0 {
+					t.Fatalf("expected no provider specific properties but got %v", eps[0].ProviderSpecific)
+				}
+			},
+		},
+		{
+			Name: "test invalid multi-value endpoints return error",
+			Endpoints: []*externaldnsendpoint.Endpoint{
+				endpoint.NewEndpointWithTTL("multivalue-test.zone-1.ext-dns-test-2.teapot.zalan.do", endpoint.RecordTypeA, endpoint.TTL(recordTTL), "1.2.3.4").
+					WithProviderSpecific(v1alpha1.ProviderSpecificMultiValue, "true"),
+			},
+			Validate: func(t *testing.T, _ []*externaldnsendpoint.Endpoint, err error) {
+				if err == nil || !strings.Contains(err.Error(), "must have a set identifier") {
+					t.Fatalf("expected a set identifier error but got %v", err)
+				}
+				for _, ep := range []*externaldnsendpoint.Endpoint{
+					endpoint.NewEndpointWithTTL("multivalue-test.zone-1.ext-dns-test-2.teapot.zalan.do", endpoint.RecordTypeA, endpoint.TTL(recordTTL), "1.2.3.4", "1.2.3.5").WithSetIdentifier("test-set-1").
+						WithProviderSpecific(v1alpha1.ProviderSpecificMultiValue, "true"),
+					endpoint.NewEndpointWithTTL("multivalue-test.zone-1.ext-dns-test-2.teapot.zalan.do", endpoint.RecordTypeA, endpoint.TTL(recordTTL), "1.2.3.4").WithSetIdentifier("test-set-1").
+						WithProviderSpecific(v1alpha1.ProviderSpecificMultiValue, "true").WithProviderSpecific(v1alpha1.ProviderSpecificWeight, "100"),
+				} {
+					if _, err := (&Route53DNSProvider{}).AdjustEndpoints([]*externaldnsendpoint.Endpoint{ep}); err == nil {
+						t.Fatalf("expected an error for %v", ep)
+					}
+				}
+			},
+		},
 	}
 
 	for _, testCase := range testCases {
@@ -109,3 +184,180 @@ func TestAWSAdjustEndpoints(t *testing.T) {
 		})
 	}
 }
+
+func TestNewSessionFromSecret(t *testing.T) {
+	testCases := []struct {
+		Name                  string
+		Data                  map[string][]byte
+		ExpectErr             bool
+		ExpectRoute53Endpoint string
+		ExpectSTSEndpoint     string
+	}{
+		{
+			Name:      "missing credentials",
+			Data:      map[string][]byte{},
+			ExpectErr: true,
+		},
+		{
+			Name: "unknown region",
+			Data: map[string][]byte{
+				v1alpha1.AWSAccessKeyIDKey:     []byte("id"),
+				v1alpha1.AWSSecretAccessKeyKey: []byte("secret"),
+				v1alpha1.AWSRegionKey:          []byte("mars-north-1"),
+			},
+			ExpectErr: true,
+		},
+		{
+			Name: "standard partition",
+			Data: map[string][]byte{
+				v1alpha1.AWSAccessKeyIDKey:     []byte("id"),
+				v1alpha1.AWSSecretAccessKeyKey: []byte("secret"),
+				v1alpha1.AWSRegionKey:          []byte("eu-west-1"),
+			},
+			ExpectRoute53Endpoint: "https://route53.amazonaws.com",
+			ExpectSTSEndpoint:     "https://sts.eu-west-1.amazonaws.com",
+		},
+		{
+			Name: "govcloud partition",
+			Data: map[string][]byte{
+				v1alpha1.AWSAccessKeyIDKey:     []byte("id"),
+				v1alpha1.AWSSecretAccessKeyKey: []byte("secret"),
+				v1alpha1.AWSRegionKey:          []byte("us-gov-west-1"),
+			},
+			ExpectRoute53Endpoint: "https://route53.us-gov.amazonaws.com",
+			ExpectSTSEndpoint:     "https://sts.us-gov-west-1.amazonaws.com",
+		},
+		{
+			Name: "china partition",
+			Data: map[string][]byte{
+				v1alpha1.AWSAccessKeyIDKey:     []byte("id"),
+				v1alpha1.AWSSecretAccessKeyKey: []byte("secret"),
+				v1alpha1.AWSRegionKey:          []byte("cn-north-1"),
+			},
+			ExpectRoute53Endpoint: "https://route53.amazonaws.com.cn",
+			ExpectSTSEndpoint:     "https://sts.cn-north-1.amazonaws.com.cn",
+		},
+		{
+			Name: "assume role",
+			Data: map[string][]byte{
+				v1alpha1.AWSAccessKeyIDKey:     []byte("id"),
+				v1alpha1.AWSSecretAccessKeyKey: []byte("secret"),
+				v1alpha1.AWSRegionKey:          []byte("us-gov-east-1"),
+				v1alpha1.AWSRoleARNKey:         []byte("arn:aws-us-gov:iam::123456789012:role/dns"),
+			},
+			ExpectRoute53Endpoint: "https://route53.us-gov.amazonaws.com",
+			ExpectSTSEndpoint:     "https://sts.us-gov-east-1.amazonaws.com",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			sess, err := newSessionFromSecret(&v1.Secret{Data: testCase.Data}, aws.NewConfig())
+			if testCase.ExpectErr {
+				if err == nil {
+					t.Fatalf("expected an error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("did not expect an error but got %s", err)
+			}
+			if got := route53.New(sess).Endpoint; got != testCase.ExpectRoute53Endpoint {
+				t.Errorf("expected route53 endpoint %s, got %s", testCase.ExpectRoute53Endpoint, got)
+			}
+			if got := sts.New(sess).Endpoint; got != testCase.ExpectSTSEndpoint {
+				t.Errorf("expected sts endpoint %s, got %s", testCase.ExpectSTSEndpoint, got)
+			}
+		})
+	}
+}
+
+func TestRoute53PendingChanges(t *testing.T) {
+	status := map[string]string{"C1": route53.ChangeStatusPending, "C2": route53.ChangeStatusInsync}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+		s, ok := status[id]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `<ErrorResponse><Error><Type>Sender</Type><Code>NoSuchChange</Code><Message>not found</Message></Error></ErrorResponse>`)
+			return
+		}
+		fmt.Fprintf(w, `<GetChangeResponse xmlns="https://route53.amazonaws.com/doc/2013-04-01/"><ChangeInfo><Id>/change/%s</Id><Status>%s</Status><SubmittedAt>2024-01-01T00:00:00Z</SubmittedAt></ChangeInfo></GetChangeResponse>`, id, s)
+	}))
+	defer server.Close()
+
+	sess, err := session.NewSession(aws.NewConfig().
+		WithCredentials(credentials.NewStaticCredentials("id", "secret", "")).
+		WithRegion("us-east-1").
+		WithEndpoint(server.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := &Route53DNSProvider{route53Client: route53.New(sess)}
+
+	pending, err := p.PendingChanges(context.Background(), []string{"/change/C1", "/change/C2"})
+	if err != nil {
+		t.Fatalf("did not expect an error but got %s", err)
+	}
+	if len(pending) != 1 || pending[0] != "/change/C1" {
+		t.Errorf("expected pending changes [/change/C1], got %v", pending)
+	}
+
+	if _, err := p.PendingChanges(context.Background(), []string{"/change/C3"}); err == nil {
+		t.Errorf("expected an error for an unknown change but got none")
+	}
+}
+
+func TestClassifyError(t *testing.T) {
+	testCases := []struct {
+		Name   string
+		Err    error
+		Expect provider.ErrorCode
+	}{
+		{
+			Name:   "throttled",
+			Err:    fmt.Errorf("Failure in zone example.com. [Id: /hostedzone/Z1] when submitting change: %w", awserr.New(route53.ErrCodeThrottlingException, "Rate exceeded", nil)),
+			Expect: provider.ErrorCodeThrottled,
+		},
+		{
+			Name:   "prior request not complete",
+			Err:    awserr.New(route53.ErrCodePriorRequestNotComplete, "The request was rejected", nil),
+			Expect: provider.ErrorCodeThrottled,
+		},
+		{
+			Name:   "access denied",
+			Err:    fmt.Errorf("failed to list hosted zones, %w", awserr.New("AccessDenied", "not authorized", nil)),
+			Expect: provider.ErrorCodeUnauthorized,
+		},
+		{
+			Name:   "invalid change batch",
+			Err:    awserr.New(route53.ErrCodeInvalidChangeBatch, "RRSet already exists", nil),
+			Expect: provider.ErrorCodeInvalidRequest,
+		},
+		{
+			Name:   "no such hosted zone",
+			Err:    awserr.New(route53.ErrCodeNoSuchHostedZone, "No hosted zone found", nil),
+			Expect: provider.ErrorCodeNotFound,
+		},
+		{
+			Name:   "service error",
+			Err:    awserr.NewRequestFailure(awserr.New("InternalFailure", "internal error", nil), http.StatusServiceUnavailable, "1"),
+			Expect: provider.ErrorCodeUnavailable,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			code, ok := classifyError(testCase.Err)
+			if !ok {
+				t.Fatalf("expected the error to be classified")
+			}
+			if code != testCase.Expect {
+				t.Errorf("expected code %s, got %s", testCase.Expect, code)
+			}
+		})
+	}
+
+	if _, ok := classifyError(fmt.Errorf("not an aws error")); ok {
+		t.Errorf("expected an error not returned by the AWS SDK not to be classified")
+	}
+}
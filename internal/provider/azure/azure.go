@@ -9,6 +9,7 @@ import (
 	"strings"
 	"sync"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	dns "github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/dns/armdns"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/trafficmanager/armtrafficmanager"
 	"github.com/go-logr/logr"
@@ -34,6 +35,7 @@ type AzureProvider struct {
 }
 
 var _ provider.Provider = &AzureProvider{}
+var _ provider.TargetLimiter = &AzureProvider{}
 
 func NewAzureProviderFromSecret(ctx context.Context, s *v1.Secret, c provider.Config) (provider.Provider, error) {
 	if string(s.Data[v1alpha1.AzureJsonKey]) == "" {
@@ -56,9 +58,18 @@ func NewAzureProviderFromSecret(ctx context.Context, s *v1.Secret, c provider.Co
 	azureConfig.ZoneNameFilter = c.DomainFilter
 	azureConfig.IDFilter = c.ZoneIDFilter
 	azureConfig.DryRun = false
+	azureConfig.ResourceManagerEndpoint = string(s.Data[v1alpha1.AzureResourceManagerEndpointKey])
 
 	azureConfig.Transporter = metrics.NewInstrumentedClient("azure", nil)
 
+	zone, err := provider.DeclaredZoneFromSecret(s)
+	if err != nil {
+		return nil, err
+	}
+	if zone != nil {
+		azureConfig.StaticZones = []dns.Zone{{ID: ptr.To(zone.ID), Name: ptr.To(zone.DNSName)}}
+	}
+
 	azureProvider, err := externaldnsproviderazure.NewAzureProviderFromConfig(ctx, azureConfig)
 
 	if err != nil {
@@ -104,9 +115,27 @@ func (p *AzureProvider) ProviderSpecific() provider.ProviderSpecificLabels {
 	return provider.ProviderSpecificLabels{}
 }
 
+// azureMaxTargets is the maximum number of records in a record set of a public Azure DNS zone
+const azureMaxTargets = 20
+
+// MaxTargets returns the maximum number of targets of a record set, the same for all record types.
+func (p *AzureProvider) MaxTargets(_ string) int {
+	return azureMaxTargets
+}
+
 // Register this Provider with the provider factory
 func init() {
 	provider.RegisterProvider("azure", NewAzureProviderFromSecret, true)
+	provider.RegisterErrorClassifier("azure", classifyError)
+}
+
+// classifyError returns the code of an error returned by the Azure Resource Manager API.
+func classifyError(err error) (provider.ErrorCode, bool) {
+	var respErr *azcore.ResponseError
+	if !errors.As(err, &respErr) {
+		return "", false
+	}
+	return provider.ErrorCodeForStatus(respErr.StatusCode), true
 }
 
 // Records gets the current records.
@@ -200,6 +229,11 @@ func (p *AzureProvider) endpointFromTrafficManager(ctx context.Context, recordSe
 
 // AdjustEndpoints takes source endpoints and translates them to an azure specific format
 func (p *AzureProvider) AdjustEndpoints(endpoints []*externaldnsendpoint.Endpoint) ([]*externaldnsendpoint.Endpoint, error) {
+	for _, ep := range endpoints {
+		if _, ok := ep.GetProviderSpecificProperty(v1alpha1.ProviderSpecificMultiValue); ok {
+			return nil, fmt.Errorf("multi-value endpoint %s is not supported by the azure provider", ep.DNSName)
+		}
+	}
 	return endpointsToAzureFormat(endpoints), nil
 }
 
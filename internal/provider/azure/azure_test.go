@@ -6,6 +6,8 @@ import (
 	. "github.com/onsi/gomega"
 
 	externaldnsendpoint "sigs.k8s.io/external-dns/endpoint"
+
+	"github.com/kuadrant/dns-operator/api/v1alpha1"
 )
 
 func TestAzureProvider_GenerateProfileName(t *testing.T) {
@@ -188,6 +190,16 @@ func TestAzureProvider_AdjustEndpoints(t *testing.T) {
 				))
 			},
 		},
+		{
+			name: "multi-value endpoints",
+			endpoints: []*externaldnsendpoint.Endpoint{
+				externaldnsendpoint.NewEndpoint("app.testdomain.com", "A", "172.32.200.1").WithSetIdentifier("ip1").
+					WithProviderSpecific(v1alpha1.ProviderSpecificMultiValue, "true"),
+			},
+			Verify: func(endpoints []*externaldnsendpoint.Endpoint, err error) {
+				Expect(err).To(MatchError("multi-value endpoint app.testdomain.com is not supported by the azure provider"))
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,142 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/external-dns/plan"
+
+	"github.com/kuadrant/dns-operator/api/v1alpha1"
+	"github.com/kuadrant/dns-operator/internal/metrics"
+)
+
+// ErrWriteBudgetExceeded is returned for changes applied with a provider credential that has used up its write budget
+// for the current interval.
+var ErrWriteBudgetExceeded = errors.New("write budget of the provider secret exceeded")
+
+// WriteBudgetExceededError is the error of changes that were not applied as the write budget of the credential was
+// used up, the changes can be applied once the budget is renewed.
+type WriteBudgetExceededError struct {
+	Budget   int
+	Interval time.Duration
+	// RetryAfter is how long until the budget is renewed
+	RetryAfter time.Duration
+}
+
+func (e *WriteBudgetExceededError) Error() string {
+	return fmt.Sprintf("%s: %d writes per %s used, renewed in %s", ErrWriteBudgetExceeded, e.Budget, e.Interval,
+		e.RetryAfter.Round(time.Second))
+}
+
+func (e *WriteBudgetExceededError) Is(target error) bool {
+	return target == ErrWriteBudgetExceeded
+}
+
+// WriteBudgetFromSecret returns the number of writes per interval allowed for the given provider secret with the
+// WRITE_BUDGET key, or false if the secret doesn't set a budget. A budget of 0 disables the budget of the credential.
+func WriteBudgetFromSecret(s *v1.Secret) (int, bool, error) {
+	value := strings.TrimSpace(string(s.Data[v1alpha1.WriteBudgetKey]))
+	if value == "" {
+		return 0, false, nil
+	}
+	budget, err := strconv.Atoi(value)
+	if err != nil || budget < 0 {
+		return 0, false, fmt.Errorf("provider secret %s/%s must set %s to a number of writes of 0 or more", s.Namespace, s.Name, v1alpha1.WriteBudgetKey)
+	}
+	return budget, true, nil
+}
+
+// writeBudget tracks the writes made with each provider credential in fixed intervals, so that every Provider
+// constructed for the same credential shares the same budget regardless of which reconciler is making the request.
+type writeBudget struct {
+	budget   int
+	interval time.Duration
+	now      func() time.Time
+
+	lock    sync.Mutex
+	windows map[string]*budgetWindow
+}
+
+// budgetWindow is the number of writes made with a credential since the start of its current interval
+type budgetWindow struct {
+	start  time.Time
+	writes int
+}
+
+func newWriteBudget(budget int, interval time.Duration) *writeBudget {
+	return &writeBudget{
+		budget:   budget,
+		interval: interval,
+		now:      time.Now,
+		windows:  map[string]*budgetWindow{},
+	}
+}
+
+// take counts a write with the given credential, or returns an error if the budget of the credential is used up.
+func (b *writeBudget) take(key string, budget int) error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	now := b.now()
+	window, ok := b.windows[key]
+	if !ok || now.Sub(window.start) >= b.interval {
+		window = &budgetWindow{start: now}
+		b.windows[key] = window
+	}
+	if window.writes >= budget {
+		return &WriteBudgetExceededError{Budget: budget, Interval: b.interval, RetryAfter: window.start.Add(b.interval).Sub(now)}
+	}
+	window.writes++
+	return nil
+}
+
+// wrap returns the given Provider with ApplyChanges limited by the write budget of the given credential, the budget of
+// the secret if it sets one. If no budget is configured the Provider is returned unchanged.
+func (b *writeBudget) wrap(key string, secret *v1.Secret, p Provider) (Provider, error) {
+	if b == nil || b.interval <= 0 {
+		return p, nil
+	}
+	budget, ok, err := WriteBudgetFromSecret(secret)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		budget = b.budget
+	}
+	if budget <= 0 {
+		return p, nil
+	}
+	return &writeBudgetProvider{Provider: p, budget: b, key: key, secret: SecretHash(secret), writes: budget}, nil
+}
+
+// writeBudgetProvider is a Provider that fails to apply changes once the write budget of its credential is used up.
+type writeBudgetProvider struct {
+	Provider
+	budget *writeBudget
+	key    string
+	secret string
+	writes int
+}
+
+var _ Provider = &writeBudgetProvider{}
+
+func (p *writeBudgetProvider) ApplyChanges(ctx context.Context, changes *plan.Changes) error {
+	if !changes.HasChanges() {
+		return p.Provider.ApplyChanges(ctx, changes)
+	}
+	if err := p.budget.take(p.key, p.writes); err != nil {
+		metrics.WriteBudgetExceeded.WithLabelValues(p.secret).Inc()
+		return err
+	}
+	return p.Provider.ApplyChanges(ctx, changes)
+}
+
+// Unwrap returns the budgeted Provider.
+func (p *writeBudgetProvider) Unwrap() Provider {
+	return p.Provider
+}
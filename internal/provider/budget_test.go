@@ -0,0 +1,90 @@
+//go:build unit
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/external-dns/endpoint"
+	"sigs.k8s.io/external-dns/plan"
+
+	"github.com/kuadrant/dns-operator/api/v1alpha1"
+)
+
+func TestWriteBudget(t *testing.T) {
+	secret := func(name, budget string) *v1.Secret {
+		s := &v1.Secret{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "ns"}, Data: map[string][]byte{}}
+		if budget != "" {
+			s.Data[v1alpha1.WriteBudgetKey] = []byte(budget)
+		}
+		return s
+	}
+	changes := &plan.Changes{Create: []*endpoint.Endpoint{endpoint.NewEndpoint("foo.example.com", endpoint.RecordTypeA, "1.1.1.1")}}
+
+	now := time.Now()
+	budget := newWriteBudget(2, time.Minute)
+	budget.now = func() time.Time { return now }
+
+	counting := &countingProvider{}
+	p, err := budget.wrap("ns/default", secret("default", ""), counting)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for range 2 {
+		if err := p.ApplyChanges(context.Background(), changes); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	// changes without any changes are not counted
+	if err := p.ApplyChanges(context.Background(), &plan.Changes{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	now = now.Add(20 * time.Second)
+	err = p.ApplyChanges(context.Background(), changes)
+	var budgetErr *WriteBudgetExceededError
+	if !errors.Is(err, ErrWriteBudgetExceeded) || !errors.As(err, &budgetErr) {
+		t.Fatalf("expected write budget exceeded error, got %v", err)
+	}
+	if budgetErr.RetryAfter != 40*time.Second {
+		t.Errorf("expected retry after 40s, got %s", budgetErr.RetryAfter)
+	}
+	if counting.writes != 3 {
+		t.Errorf("expected 3 applied changes, got %d", counting.writes)
+	}
+
+	// the budget is shared by all providers of the credential and renewed after the interval
+	other, _ := budget.wrap("ns/default", secret("default", ""), counting)
+	if err := other.ApplyChanges(context.Background(), changes); !errors.Is(err, ErrWriteBudgetExceeded) {
+		t.Errorf("expected write budget exceeded error, got %v", err)
+	}
+	now = now.Add(40 * time.Second)
+	if err := other.ApplyChanges(context.Background(), changes); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	// secrets can set their own budget, or disable it
+	own, _ := budget.wrap("ns/own", secret("own", "1"), counting)
+	if err := own.ApplyChanges(context.Background(), changes); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := own.ApplyChanges(context.Background(), changes); !errors.Is(err, ErrWriteBudgetExceeded) {
+		t.Errorf("expected write budget exceeded error, got %v", err)
+	}
+	if unlimited, _ := budget.wrap("ns/unlimited", secret("unlimited", "0"), counting); unlimited != Provider(counting) {
+		t.Errorf("expected the provider to be returned unchanged")
+	}
+	if _, err := budget.wrap("ns/invalid", secret("invalid", "lots"), counting); err == nil {
+		t.Errorf("expected error for invalid budget")
+	}
+
+	var disabled *writeBudget
+	if p, _ := disabled.wrap("ns/default", secret("default", "1"), counting); p != Provider(counting) {
+		t.Errorf("expected the provider to be returned unchanged")
+	}
+}
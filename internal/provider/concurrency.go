@@ -0,0 +1,67 @@
+package provider
+
+import (
+	"context"
+	"sync"
+
+	"sigs.k8s.io/external-dns/plan"
+)
+
+// writeLimiter hands out a counting semaphore per provider credential, so that every Provider constructed for the same
+// credential shares the same limit regardless of which reconciler is making the request.
+type writeLimiter struct {
+	limit      int
+	lock       sync.Mutex
+	semaphores map[string]chan struct{}
+}
+
+func newWriteLimiter(limit int) *writeLimiter {
+	return &writeLimiter{
+		limit:      limit,
+		semaphores: map[string]chan struct{}{},
+	}
+}
+
+// semaphoreFor returns the semaphore for the given credential key, creating it if required.
+func (l *writeLimiter) semaphoreFor(key string) chan struct{} {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	sem, ok := l.semaphores[key]
+	if !ok {
+		sem = make(chan struct{}, l.limit)
+		l.semaphores[key] = sem
+	}
+	return sem
+}
+
+// wrap returns the given Provider with ApplyChanges limited by the semaphore for the given credential key.
+// If no limit is configured the Provider is returned unchanged.
+func (l *writeLimiter) wrap(key string, p Provider) Provider {
+	if l == nil || l.limit <= 0 {
+		return p
+	}
+	return &writeLimitedProvider{Provider: p, sem: l.semaphoreFor(key)}
+}
+
+// writeLimitedProvider is a Provider that waits for a free slot in a shared semaphore before applying changes.
+type writeLimitedProvider struct {
+	Provider
+	sem chan struct{}
+}
+
+var _ Provider = &writeLimitedProvider{}
+
+func (p *writeLimitedProvider) ApplyChanges(ctx context.Context, changes *plan.Changes) error {
+	select {
+	case p.sem <- struct{}{}:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	defer func() { <-p.sem }()
+	return p.Provider.ApplyChanges(ctx, changes)
+}
+
+// Unwrap returns the limited Provider.
+func (p *writeLimitedProvider) Unwrap() Provider {
+	return p.Provider
+}
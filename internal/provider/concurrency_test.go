@@ -0,0 +1,145 @@
+//go:build unit
+
+package provider
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/external-dns/plan"
+	externaldnsprovider "sigs.k8s.io/external-dns/provider"
+
+	"github.com/kuadrant/dns-operator/api/v1alpha1"
+)
+
+type blockingProvider struct {
+	Provider
+	inFlight    atomic.Int32
+	maxInFlight atomic.Int32
+	release     chan struct{}
+}
+
+func (p *blockingProvider) ApplyChanges(_ context.Context, _ *plan.Changes) error {
+	n := p.inFlight.Add(1)
+	defer p.inFlight.Add(-1)
+	for {
+		m := p.maxInFlight.Load()
+		if n <= m || p.maxInFlight.CompareAndSwap(m, n) {
+			break
+		}
+	}
+	<-p.release
+	return nil
+}
+
+func TestWriteLimiter(t *testing.T) {
+	testCases := []struct {
+		name        string
+		limit       int
+		keys        []string
+		writes      int
+		expectedMax int32
+	}{
+		{
+			name:        "no limit",
+			limit:       0,
+			keys:        []string{"ns/secret"},
+			writes:      5,
+			expectedMax: 5,
+		},
+		{
+			name:        "limited single credential",
+			limit:       2,
+			keys:        []string{"ns/secret"},
+			writes:      5,
+			expectedMax: 2,
+		},
+		{
+			name:        "limit is per credential",
+			limit:       1,
+			keys:        []string{"ns/secret1", "ns/secret2"},
+			writes:      4,
+			expectedMax: 2,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			limiter := newWriteLimiter(testCase.limit)
+			p := &blockingProvider{release: make(chan struct{})}
+
+			wg := sync.WaitGroup{}
+			for i := 0; i < testCase.writes; i++ {
+				wp := limiter.wrap(testCase.keys[i%len(testCase.keys)], p)
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					if err := wp.ApplyChanges(context.Background(), &plan.Changes{}); err != nil {
+						t.Errorf("unexpected error: %v", err)
+					}
+				}()
+			}
+
+			// give the writers a chance to queue up before releasing them
+			time.Sleep(100 * time.Millisecond)
+			close(p.release)
+			wg.Wait()
+
+			if got := p.maxInFlight.Load(); got != testCase.expectedMax {
+				t.Errorf("expected max concurrent writes of %v, got %v", testCase.expectedMax, got)
+			}
+		})
+	}
+}
+
+func TestWriteLimiterContextCancelled(t *testing.T) {
+	limiter := newWriteLimiter(1)
+	p := &blockingProvider{release: make(chan struct{})}
+	defer close(p.release)
+
+	go func() {
+		_ = limiter.wrap("ns/secret", p).ApplyChanges(context.Background(), &plan.Changes{})
+	}()
+	time.Sleep(50 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := limiter.wrap("ns/secret", p).ApplyChanges(ctx, &plan.Changes{}); err == nil {
+		t.Errorf("expected an error waiting for a write slot with a cancelled context")
+	}
+}
+
+func TestWriteLimiterAs(t *testing.T) {
+	p := &blockingProvider{}
+	wrapped := newWriteLimiter(1).wrap("ns/secret", p)
+	if _, ok := wrapped.(*blockingProvider); ok {
+		t.Fatalf("expected the provider to be wrapped")
+	}
+	if got, ok := As[*blockingProvider](wrapped); !ok || got != p {
+		t.Errorf("expected As to return the wrapped provider, got %v", got)
+	}
+	if _, ok := As[ChangeTracker](wrapped); ok {
+		t.Errorf("expected As to fail for an interface the provider does not implement")
+	}
+}
+
+func TestDirect(t *testing.T) {
+	p := &blockingProvider{}
+	secret := &v1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "secret", Namespace: "ns"}, Data: map[string][]byte{v1alpha1.WriteBudgetKey: []byte("1")}}
+	cached := newRecordsCache(time.Minute).wrap("ns/secret", "1", Config{ZoneIDFilter: externaldnsprovider.NewZoneIDFilter([]string{"zone"})}, p)
+	budgeted, err := newWriteBudget(1, time.Minute).wrap("ns/secret", secret, cached)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wrapped := newWriteLimiter(1).wrap("ns/secret", budgeted)
+	if wrapped == Provider(p) {
+		t.Fatalf("expected the provider to be wrapped")
+	}
+	if got := Direct(wrapped); got != Provider(p) {
+		t.Errorf("expected the provider without the records cache, write budget and write limit, got %T", got)
+	}
+}
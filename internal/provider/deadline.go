@@ -0,0 +1,90 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"sigs.k8s.io/external-dns/endpoint"
+	"sigs.k8s.io/external-dns/plan"
+)
+
+// deadlineProvider is a Provider that bounds each call to the wrapped Provider to a timeout. Reads that don't return
+// within the timeout are abandoned, so a provider client that does not honour the context, e.g. blocked on a wedged
+// connection, can't hang the reconcile that made the call. Changes are never abandoned, as changes still being applied
+// after the reconcile returned could race with the changes of the next reconcile, they are given the context bounded
+// to the timeout and waited for.
+type deadlineProvider struct {
+	Provider
+	timeout time.Duration
+}
+
+var _ Provider = &deadlineProvider{}
+
+// withDeadline returns the given Provider with each call bounded to the given timeout. If no timeout is configured the
+// Provider is returned unchanged.
+func withDeadline(timeout time.Duration, p Provider) Provider {
+	if timeout <= 0 {
+		return p
+	}
+	return &deadlineProvider{Provider: p, timeout: timeout}
+}
+
+// callWithDeadline runs the given call with a context bounded to the timeout, and returns once the call returns or the
+// context is done, whichever is first.
+func callWithDeadline[T any](ctx context.Context, timeout time.Duration, name string, call func(ctx context.Context) (T, error)) (T, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	type result struct {
+		value T
+		err   error
+	}
+	done := make(chan result, 1)
+	go func() {
+		value, err := call(ctx)
+		done <- result{value, err}
+	}()
+
+	select {
+	case r := <-done:
+		return r.value, r.err
+	case <-ctx.Done():
+		var zero T
+		return zero, fmt.Errorf("provider call %s did not return within %s: %w", name, timeout, ctx.Err())
+	}
+}
+
+func (p *deadlineProvider) Records(ctx context.Context) ([]*endpoint.Endpoint, error) {
+	return callWithDeadline(ctx, p.timeout, "Records", p.Provider.Records)
+}
+
+func (p *deadlineProvider) ApplyChanges(ctx context.Context, changes *plan.Changes) error {
+	ctx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+
+	err := p.Provider.ApplyChanges(ctx, changes)
+	if err != nil && ctx.Err() != nil {
+		if !errors.Is(err, ctx.Err()) {
+			err = fmt.Errorf("%w: %w", ctx.Err(), err)
+		}
+		return fmt.Errorf("provider call ApplyChanges did not complete within %s: %w", p.timeout, err)
+	}
+	return err
+}
+
+func (p *deadlineProvider) DNSZones(ctx context.Context) ([]DNSZone, error) {
+	return callWithDeadline(ctx, p.timeout, "DNSZones", p.Provider.DNSZones)
+}
+
+func (p *deadlineProvider) DNSZoneForHost(ctx context.Context, host string) (*DNSZone, error) {
+	return callWithDeadline(ctx, p.timeout, "DNSZoneForHost", func(ctx context.Context) (*DNSZone, error) {
+		return p.Provider.DNSZoneForHost(ctx, host)
+	})
+}
+
+// Unwrap returns the bounded Provider.
+func (p *deadlineProvider) Unwrap() Provider {
+	return p.Provider
+}
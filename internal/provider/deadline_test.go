@@ -0,0 +1,97 @@
+//go:build unit
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"sigs.k8s.io/external-dns/endpoint"
+	"sigs.k8s.io/external-dns/plan"
+)
+
+// wedgedProvider ignores the context of its calls and blocks until released.
+type wedgedProvider struct {
+	Provider
+	release chan struct{}
+	applied bool
+}
+
+func (p *wedgedProvider) Records(_ context.Context) ([]*endpoint.Endpoint, error) {
+	<-p.release
+	return []*endpoint.Endpoint{endpoint.NewEndpoint("foo.example.com", endpoint.RecordTypeA, "1.1.1.1")}, nil
+}
+
+// ApplyChanges honours the context, as provider clients applying changes are waited for.
+func (p *wedgedProvider) ApplyChanges(ctx context.Context, _ *plan.Changes) error {
+	select {
+	case <-p.release:
+		p.applied = true
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("applying changes: %w", ctx.Err())
+	}
+}
+
+func TestDeadlineProvider(t *testing.T) {
+	wedged := &wedgedProvider{release: make(chan struct{})}
+	defer close(wedged.release)
+
+	if p := withDeadline(0, wedged); p != Provider(wedged) {
+		t.Fatalf("expected the provider to be returned unchanged without a timeout")
+	}
+
+	p := withDeadline(50*time.Millisecond, wedged)
+	start := time.Now()
+	_, err := p.Records(context.Background())
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected a deadline exceeded error, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("expected the call to be abandoned at the timeout, returned after %s", elapsed)
+	}
+	if code := ClassifyError("", err); code != ErrorCodeUnavailable {
+		t.Errorf("expected the timeout to be classified as %s, got %s", ErrorCodeUnavailable, code)
+	}
+	if unwrapped, ok := As[*wedgedProvider](p); !ok || unwrapped != wedged {
+		t.Errorf("expected the bounded provider to be unwrapped")
+	}
+}
+
+func TestDeadlineProviderWaitsForChanges(t *testing.T) {
+	wedged := &wedgedProvider{release: make(chan struct{})}
+	p := withDeadline(50*time.Millisecond, wedged)
+
+	err := p.ApplyChanges(context.Background(), &plan.Changes{})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected a deadline exceeded error, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "did not complete within 50ms") {
+		t.Errorf("expected the timeout in the error, got %v", err)
+	}
+
+	close(wedged.release)
+	if err = p.ApplyChanges(context.Background(), &plan.Changes{}); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if !wedged.applied {
+		t.Errorf("expected the changes to be applied")
+	}
+}
+
+func TestDeadlineProviderReturnsResult(t *testing.T) {
+	released := &wedgedProvider{release: make(chan struct{})}
+	close(released.release)
+
+	records, err := withDeadline(time.Second, released).Records(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if len(records) != 1 || records[0].DNSName != "foo.example.com" {
+		t.Errorf("expected the records of the provider, got %v", records)
+	}
+}
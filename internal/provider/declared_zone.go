@@ -0,0 +1,59 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+
+	"github.com/kuadrant/dns-operator/api/v1alpha1"
+)
+
+// DeclaredZoneFromSecret returns the zone declared in the given provider secret with the ZONE_ID and ZONE_DOMAIN_NAME
+// keys, or nil if the secret doesn't declare a zone. Credentials scoped to the declared zone don't need the permission
+// to list the zones of the provider.
+func DeclaredZoneFromSecret(s *v1.Secret) (*DNSZone, error) {
+	id := strings.TrimSpace(string(s.Data[v1alpha1.ZoneIDKey]))
+	name := strings.ToLower(strings.TrimSuffix(strings.TrimSpace(string(s.Data[v1alpha1.ZoneDomainNameKey])), "."))
+	if id == "" && name == "" {
+		return nil, nil
+	}
+	if id == "" || name == "" {
+		return nil, fmt.Errorf("provider secret %s/%s must set both %s and %s to declare a zone", s.Namespace, s.Name, v1alpha1.ZoneIDKey, v1alpha1.ZoneDomainNameKey)
+	}
+	return &DNSZone{ID: id, DNSName: name}, nil
+}
+
+// declaredZoneProvider is a Provider for credentials scoped to a single declared zone, zones are never discovered
+// from the provider.
+type declaredZoneProvider struct {
+	Provider
+	zone   DNSZone
+	config Config
+}
+
+var _ Provider = &declaredZoneProvider{}
+
+// DNSZones returns the declared zone, if it matches the filters of the provider config.
+func (p *declaredZoneProvider) DNSZones(_ context.Context) ([]DNSZone, error) {
+	if !p.config.ZoneIDFilter.Match(p.zone.ID) || !p.config.DomainFilter.Match(p.zone.DNSName) {
+		return nil, nil
+	}
+	return []DNSZone{p.zone}, nil
+}
+
+// DNSZoneForHost returns the declared zone if the host is the zone domain name or one of its subdomains.
+func (p *declaredZoneProvider) DNSZoneForHost(_ context.Context, host string) (*DNSZone, error) {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	if host != p.zone.DNSName && !strings.HasSuffix(host, "."+p.zone.DNSName) {
+		return nil, fmt.Errorf("%w : %s is not within the declared zone %s", ErrNoZoneForHost, host, p.zone.DNSName)
+	}
+	zone := p.zone
+	return &zone, nil
+}
+
+// Unwrap returns the Provider of the declared zone.
+func (p *declaredZoneProvider) Unwrap() Provider {
+	return p.Provider
+}
@@ -0,0 +1,95 @@
+//go:build unit
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	externaldnsendpoint "sigs.k8s.io/external-dns/endpoint"
+	externaldnsprovider "sigs.k8s.io/external-dns/provider"
+
+	"github.com/kuadrant/dns-operator/api/v1alpha1"
+)
+
+func TestDeclaredZoneFromSecret(t *testing.T) {
+	testCases := []struct {
+		name     string
+		data     map[string][]byte
+		expected *DNSZone
+		wantErr  bool
+	}{
+		{
+			name: "no declared zone",
+			data: map[string][]byte{},
+		},
+		{
+			name: "declared zone",
+			data: map[string][]byte{
+				v1alpha1.ZoneIDKey:         []byte("Z123"),
+				v1alpha1.ZoneDomainNameKey: []byte("Example.com."),
+			},
+			expected: &DNSZone{ID: "Z123", DNSName: "example.com"},
+		},
+		{
+			name: "missing domain name",
+			data: map[string][]byte{
+				v1alpha1.ZoneIDKey: []byte("Z123"),
+			},
+			wantErr: true,
+		},
+		{
+			name: "missing id",
+			data: map[string][]byte{
+				v1alpha1.ZoneDomainNameKey: []byte("example.com"),
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			zone, err := DeclaredZoneFromSecret(&v1.Secret{Data: tt.data})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("DeclaredZoneFromSecret() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(zone, tt.expected) {
+				t.Errorf("DeclaredZoneFromSecret() = %v, want %v", zone, tt.expected)
+			}
+		})
+	}
+}
+
+func TestDeclaredZoneProvider(t *testing.T) {
+	p := &declaredZoneProvider{zone: DNSZone{ID: "Z123", DNSName: "example.com"}}
+
+	for _, host := range []string{"example.com", "foo.example.com", "FOO.bar.example.com."} {
+		z, err := p.DNSZoneForHost(context.Background(), host)
+		if err != nil {
+			t.Fatalf("DNSZoneForHost(%s) unexpected error %v", host, err)
+		}
+		if z.ID != "Z123" {
+			t.Errorf("DNSZoneForHost(%s) = %v, want Z123", host, z.ID)
+		}
+	}
+	for _, host := range []string{"example.org", "fooexample.com", "com"} {
+		if _, err := p.DNSZoneForHost(context.Background(), host); !errors.Is(err, ErrNoZoneForHost) {
+			t.Errorf("DNSZoneForHost(%s) error = %v, want %v", host, err, ErrNoZoneForHost)
+		}
+	}
+
+	zones, err := p.DNSZones(context.Background())
+	if err != nil || len(zones) != 1 {
+		t.Fatalf("DNSZones() = %v, %v, want the declared zone", zones, err)
+	}
+	p.config = Config{ZoneIDFilter: externaldnsprovider.NewZoneIDFilter([]string{"Z456"})}
+	if zones, _ = p.DNSZones(context.Background()); len(zones) != 0 {
+		t.Errorf("DNSZones() = %v, want no zones with a non matching id filter", zones)
+	}
+	p.config = Config{DomainFilter: externaldnsendpoint.NewDomainFilter([]string{"example.org"})}
+	if zones, _ = p.DNSZones(context.Background()); len(zones) != 0 {
+		t.Errorf("DNSZones() = %v, want no zones with a non matching domain filter", zones)
+	}
+}
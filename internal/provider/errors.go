@@ -0,0 +1,105 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+
+	externaldnsplan "github.com/kuadrant/dns-operator/internal/external-dns/plan"
+)
+
+// ErrorCode is a machine-readable classification of an error returned by a provider.
+type ErrorCode string
+
+const (
+	// ErrorCodeUnknown the error could not be classified
+	ErrorCodeUnknown ErrorCode = "Unknown"
+	// ErrorCodeThrottled the provider rate limited the request
+	ErrorCodeThrottled ErrorCode = "Throttled"
+	// ErrorCodeUnavailable the provider failed to handle the request or could not be reached
+	ErrorCodeUnavailable ErrorCode = "Unavailable"
+	// ErrorCodeUnauthorized the credentials were rejected or don't allow the request
+	ErrorCodeUnauthorized ErrorCode = "Unauthorized"
+	// ErrorCodeNotFound a resource of the request, such as the zone, does not exist
+	ErrorCodeNotFound ErrorCode = "NotFound"
+	// ErrorCodeZoneNotFound no zone of the provider matches the root host of the record
+	ErrorCodeZoneNotFound ErrorCode = "ZoneNotFound"
+	// ErrorCodeInvalidRequest the provider rejected the changes as invalid
+	ErrorCodeInvalidRequest ErrorCode = "InvalidRequest"
+	// ErrorCodeConflict the changes conflict with the current state of the zone
+	ErrorCodeConflict ErrorCode = "Conflict"
+)
+
+// Retryable returns true if a request that failed with the error code can succeed when retried without any changes to
+// the record or provider configuration.
+func (c ErrorCode) Retryable() bool {
+	switch c {
+	case ErrorCodeThrottled, ErrorCodeUnavailable, ErrorCodeUnknown:
+		return true
+	}
+	return false
+}
+
+// ErrorClassifier returns the code of a provider specific error, or false if the error is not a provider error it
+// recognises.
+type ErrorClassifier func(err error) (ErrorCode, bool)
+
+var errorClassifiers = make(map[string]ErrorClassifier)
+
+// RegisterErrorClassifier will register the classifier of the errors of the provider with the given name.
+func RegisterErrorClassifier(name string, c ErrorClassifier) {
+	constructorsLock.Lock()
+	defer constructorsLock.Unlock()
+	errorClassifiers[name] = c
+}
+
+// ClassifyError returns the code of an error returned by the provider with the given name.
+func ClassifyError(name string, err error) ErrorCode {
+	if errors.Is(err, ErrNoZoneForHost) {
+		return ErrorCodeZoneNotFound
+	}
+	// the provider secret does not exist
+	if apierrors.IsNotFound(err) {
+		return ErrorCodeNotFound
+	}
+	if errors.Is(err, externaldnsplan.ErrOwnerConflict) || errors.Is(err, externaldnsplan.ErrRecordTypeConflict) {
+		return ErrorCodeConflict
+	}
+
+	constructorsLock.RLock()
+	classifier, ok := errorClassifiers[name]
+	constructorsLock.RUnlock()
+	if ok {
+		if code, ok := classifier(err); ok {
+			return code
+		}
+	}
+
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+		return ErrorCodeUnavailable
+	}
+	return ErrorCodeUnknown
+}
+
+// ErrorCodeForStatus returns the code of a provider error with the given HTTP status code.
+func ErrorCodeForStatus(status int) ErrorCode {
+	switch {
+	case status == http.StatusTooManyRequests:
+		return ErrorCodeThrottled
+	case status == http.StatusUnauthorized || status == http.StatusForbidden:
+		return ErrorCodeUnauthorized
+	case status == http.StatusNotFound:
+		return ErrorCodeNotFound
+	case status == http.StatusConflict || status == http.StatusPreconditionFailed:
+		return ErrorCodeConflict
+	case status == http.StatusBadRequest || status == http.StatusUnprocessableEntity:
+		return ErrorCodeInvalidRequest
+	case status >= http.StatusInternalServerError:
+		return ErrorCodeUnavailable
+	}
+	return ErrorCodeUnknown
+}
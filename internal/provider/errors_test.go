@@ -0,0 +1,102 @@
+//go:build unit
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	externaldnsplan "github.com/kuadrant/dns-operator/internal/external-dns/plan"
+)
+
+var errThrottledTest = errors.New("slow down")
+
+func TestClassifyError(t *testing.T) {
+	RegisterErrorClassifier("classify-test", func(err error) (ErrorCode, bool) {
+		if errors.Is(err, errThrottledTest) {
+			return ErrorCodeThrottled, true
+		}
+		return "", false
+	})
+
+	testCases := []struct {
+		name          string
+		provider      string
+		err           error
+		wantCode      ErrorCode
+		wantRetryable bool
+	}{
+		{
+			name:     "no zone for host",
+			err:      fmt.Errorf("zone lookup: %w", ErrNoZoneForHost),
+			wantCode: ErrorCodeZoneNotFound,
+		},
+		{
+			name:     "owner conflict",
+			err:      fmt.Errorf("%w, cannot update endpoint", externaldnsplan.ErrOwnerConflict),
+			wantCode: ErrorCodeConflict,
+		},
+		{
+			name:     "provider secret not found",
+			err:      apierrors.NewNotFound(schema.GroupResource{Resource: "secrets"}, "inmemory-credentials"),
+			wantCode: ErrorCodeNotFound,
+		},
+		{
+			name:          "provider classifier",
+			provider:      "classify-test",
+			err:           fmt.Errorf("failed to apply changes: %w", errThrottledTest),
+			wantCode:      ErrorCodeThrottled,
+			wantRetryable: true,
+		},
+		{
+			name:          "timeout",
+			provider:      "classify-test",
+			err:           fmt.Errorf("failed to list zones: %w", context.DeadlineExceeded),
+			wantCode:      ErrorCodeUnavailable,
+			wantRetryable: true,
+		},
+		{
+			name:          "unknown",
+			provider:      "classify-test",
+			err:           errors.New("something went wrong"),
+			wantCode:      ErrorCodeUnknown,
+			wantRetryable: true,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			code := ClassifyError(testCase.provider, testCase.err)
+			if code != testCase.wantCode {
+				t.Errorf("expected code %s, got %s", testCase.wantCode, code)
+			}
+			if code.Retryable() != testCase.wantRetryable {
+				t.Errorf("expected retryable %t, got %t", testCase.wantRetryable, code.Retryable())
+			}
+		})
+	}
+}
+
+func TestErrorCodeForStatus(t *testing.T) {
+	testCases := map[int]ErrorCode{
+		http.StatusTooManyRequests:     ErrorCodeThrottled,
+		http.StatusUnauthorized:        ErrorCodeUnauthorized,
+		http.StatusForbidden:           ErrorCodeUnauthorized,
+		http.StatusNotFound:            ErrorCodeNotFound,
+		http.StatusConflict:            ErrorCodeConflict,
+		http.StatusBadRequest:          ErrorCodeInvalidRequest,
+		http.StatusInternalServerError: ErrorCodeUnavailable,
+		http.StatusServiceUnavailable:  ErrorCodeUnavailable,
+		http.StatusTeapot:              ErrorCodeUnknown,
+	}
+	for status, want := range testCases {
+		if got := ErrorCodeForStatus(status); got != want {
+			t.Errorf("expected code %s for status %d, got %s", want, status, got)
+		}
+	}
+}
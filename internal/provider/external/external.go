@@ -0,0 +1,213 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package external
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	externaldnsendpoint "sigs.k8s.io/external-dns/endpoint"
+	externaldnsplan "sigs.k8s.io/external-dns/plan"
+
+	"github.com/kuadrant/dns-operator/internal/provider"
+)
+
+// Out of tree providers run as a sidecar serving the webhook provider API of external-dns
+// https://kubernetes-sigs.github.io/external-dns/latest/tutorials/webhook-provider/
+
+const (
+	// MediaType is the media type of the requests and responses of the webhook provider API
+	MediaType = "application/external.dns.webhook+json;version=1"
+
+	// SecretTypePrefix is the prefix of the type of the provider secrets of an external provider, followed by the
+	// name of the provider
+	SecretTypePrefix = "kuadrant.io/"
+
+	// requestTimeout is the time allowed for a request to the sidecar, if the context has no deadline
+	requestTimeout = 30 * time.Second
+)
+
+// StatusError is returned for a request the sidecar responded to with an unexpected status.
+type StatusError struct {
+	StatusCode int
+	Path       string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("external provider request %s failed with status %d", e.Path, e.StatusCode)
+}
+
+// ExternalDNSProvider is a Provider that forwards all calls to a sidecar serving the webhook provider API.
+type ExternalDNSProvider struct {
+	client       *http.Client
+	url          *url.URL
+	domainFilter externaldnsendpoint.DomainFilter
+	config       provider.Config
+}
+
+var _ provider.Provider = &ExternalDNSProvider{}
+
+// Register registers the external provider with the given name served by the sidecar at the given url. Provider
+// secrets of type kuadrant.io/<name> are routed to the provider.
+func Register(name, rawURL string) error {
+	if name == "" || provider.IsRegistered(name) {
+		return fmt.Errorf("invalid name of external provider %q: must be set and not the name of another provider", name)
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("invalid url of external provider %s: %w", name, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("invalid url of external provider %s: must be http or https", name)
+	}
+	provider.RegisterProvider(name, func(ctx context.Context, _ *v1.Secret, c provider.Config) (provider.Provider, error) {
+		return NewProvider(ctx, u, c)
+	}, false)
+	provider.RegisterSecretType(v1.SecretType(SecretTypePrefix+name), name)
+	provider.RegisterErrorClassifier(name, classifyError)
+	return nil
+}
+
+// NewProvider returns a provider for the sidecar at the given url, negotiating the domain filter of the provider.
+func NewProvider(ctx context.Context, u *url.URL, c provider.Config) (*ExternalDNSProvider, error) {
+	logger := log.FromContext(ctx).WithName("external-dns")
+	p := &ExternalDNSProvider{
+		client: &http.Client{},
+		url:    u,
+		config: c,
+	}
+	if err := p.do(ctx, http.MethodGet, "", nil, &p.domainFilter); err != nil {
+		return nil, fmt.Errorf("negotiating with external provider: %w", err)
+	}
+	logger.V(1).Info("provider initialised", "url", u.String(), "domainFilter", p.domainFilter.Filters)
+	return p, nil
+}
+
+// do sends a request to the sidecar and decodes the response body into out, if not nil.
+func (p *ExternalDNSProvider) do(ctx context.Context, method, path string, in, out any) error {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, requestTimeout)
+		defer cancel()
+	}
+
+	var body io.Reader
+	if in != nil {
+		b, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(b)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, p.url.JoinPath(path).String(), body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", MediaType)
+	if in != nil {
+		req.Header.Set("Content-Type", MediaType)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return &StatusError{StatusCode: resp.StatusCode, Path: "/" + path}
+	}
+	if out == nil {
+		return nil
+	}
+	if contentType := resp.Header.Get("Content-Type"); contentType != MediaType {
+		return fmt.Errorf("external provider request /%s returned content type %q, expected %q", path, contentType, MediaType)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// Records returns the endpoints of all zones of the sidecar.
+func (p *ExternalDNSProvider) Records(ctx context.Context) ([]*externaldnsendpoint.Endpoint, error) {
+	var endpoints []*externaldnsendpoint.Endpoint
+	if err := p.do(ctx, http.MethodGet, "records", nil, &endpoints); err != nil {
+		return nil, err
+	}
+	return endpoints, nil
+}
+
+// ApplyChanges sends the changes to the sidecar.
+func (p *ExternalDNSProvider) ApplyChanges(ctx context.Context, changes *externaldnsplan.Changes) error {
+	return p.do(ctx, http.MethodPost, "records", changes, nil)
+}
+
+// AdjustEndpoints asks the sidecar to modify the endpoints to the form it publishes them in.
+func (p *ExternalDNSProvider) AdjustEndpoints(endpoints []*externaldnsendpoint.Endpoint) ([]*externaldnsendpoint.Endpoint, error) {
+	adjusted := []*externaldnsendpoint.Endpoint{}
+	if err := p.do(context.Background(), http.MethodPost, "adjustendpoints", endpoints, &adjusted); err != nil {
+		return nil, err
+	}
+	return adjusted, nil
+}
+
+// GetDomainFilter returns the domain filter negotiated with the sidecar.
+func (p *ExternalDNSProvider) GetDomainFilter() externaldnsendpoint.DomainFilter {
+	return p.domainFilter
+}
+
+// DNSZones returns a zone for each domain of the domain filter negotiated with the sidecar, as the webhook provider
+// API has no zones. Sidecars managing any domain have no zones, the zone must then be declared in the provider secret.
+func (p *ExternalDNSProvider) DNSZones(_ context.Context) ([]provider.DNSZone, error) {
+	var zones []provider.DNSZone
+	for _, filter := range p.domainFilter.Filters {
+		name := strings.ToLower(strings.Trim(filter, "."))
+		if name == "" || !p.config.DomainFilter.Match(name) || !p.config.ZoneIDFilter.Match(name) {
+			continue
+		}
+		zones = append(zones, provider.DNSZone{ID: name, DNSName: name})
+	}
+	return zones, nil
+}
+
+func (p *ExternalDNSProvider) DNSZoneForHost(ctx context.Context, host string) (*provider.DNSZone, error) {
+	zones, err := p.DNSZones(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return provider.FindDNSZoneForHost(ctx, host, zones)
+}
+
+func (p *ExternalDNSProvider) ProviderSpecific() provider.ProviderSpecificLabels {
+	return provider.ProviderSpecificLabels{}
+}
+
+// classifyError returns the code of an error returned by the sidecar.
+func classifyError(err error) (provider.ErrorCode, bool) {
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) {
+		return "", false
+	}
+	return provider.ErrorCodeForStatus(statusErr.StatusCode), true
+}
@@ -0,0 +1,59 @@
+//go:build unit
+
+package external
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	externaldnsendpoint "sigs.k8s.io/external-dns/endpoint"
+
+	"github.com/kuadrant/dns-operator/internal/provider"
+)
+
+func TestExternalDNSProvider(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/":
+			w.Header().Set("Content-Type", MediaType)
+			_ = json.NewEncoder(w).Encode(externaldnsendpoint.NewDomainFilter([]string{"example.com", "Example.org."}))
+		case "/records":
+			w.WriteHeader(http.StatusTooManyRequests)
+		}
+	}))
+	defer server.Close()
+
+	if err := Register("example", server.URL); err != nil {
+		t.Fatalf("Register() unexpected error %v", err)
+	}
+	if err := Register("example", server.URL); err == nil {
+		t.Errorf("Register() expected an error registering a provider twice")
+	}
+	if err := Register("other", "unix:///tmp/sidecar.sock"); err == nil {
+		t.Errorf("Register() expected an error for a url that is not http")
+	}
+	name, err := provider.NameForProviderSecret(&v1.Secret{Type: "kuadrant.io/example"})
+	if err != nil || name != "example" {
+		t.Errorf("NameForProviderSecret() = %s, %v, want example", name, err)
+	}
+
+	u, _ := url.Parse(server.URL)
+	p, err := NewProvider(context.Background(), u, provider.Config{DomainFilter: externaldnsendpoint.NewDomainFilter([]string{"example.org"})})
+	if err != nil {
+		t.Fatalf("NewProvider() unexpected error %v", err)
+	}
+	zones, _ := p.DNSZones(context.Background())
+	if len(zones) != 1 || zones[0].ID != "example.org" || zones[0].DNSName != "example.org" {
+		t.Errorf("DNSZones() = %v, want the example.org zone", zones)
+	}
+
+	_, err = p.Records(context.Background())
+	if code := provider.ClassifyError("example", err); code != provider.ErrorCodeThrottled {
+		t.Errorf("ClassifyError(%v) = %s, want %s", err, code, provider.ErrorCodeThrottled)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"slices"
 	"sync"
+	"time"
 
 	"golang.org/x/exp/maps"
 
@@ -13,8 +14,10 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	externaldnsendpoint "sigs.k8s.io/external-dns/endpoint"
 
 	"github.com/kuadrant/dns-operator/api/v1alpha1"
+	"github.com/kuadrant/dns-operator/internal/metrics"
 )
 
 // +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch
@@ -29,6 +32,7 @@ var (
 	constructors     = make(map[string]ProviderConstructor)
 	constructorsLock sync.RWMutex
 	defaultProviders []string
+	secretTypes      = make(map[v1.SecretType]string)
 )
 
 // RegisterProvider will register a provider constructor, so it can be used within the application.
@@ -43,6 +47,22 @@ func RegisterProvider(name string, c ProviderConstructor, asDefault bool) {
 	}
 }
 
+// RegisterSecretType will route provider secrets of the given type to the provider with the given name, for providers
+// registered at runtime that have no secret type of their own in the API.
+func RegisterSecretType(secretType v1.SecretType, name string) {
+	constructorsLock.Lock()
+	defer constructorsLock.Unlock()
+	secretTypes[secretType] = name
+}
+
+// IsRegistered returns true if a provider with the given name is registered.
+func IsRegistered(name string) bool {
+	constructorsLock.RLock()
+	defer constructorsLock.RUnlock()
+	_, ok := constructors[name]
+	return ok
+}
+
 func RegisteredDefaultProviders() []string {
 	return defaultProviders
 }
@@ -56,12 +76,65 @@ type Factory interface {
 // factory is the default Factory implementation
 type factory struct {
 	client.Client
-	providers []string
+	providers    []string
+	writeLimiter *writeLimiter
+	writeBudget  *writeBudget
+	recordsCache *recordsCache
+	zonesCache   *zonesCache
+	callTimeout  time.Duration
+}
+
+// FactoryOption configures optional behaviour of the default Factory implementation.
+type FactoryOption func(*factory)
+
+// WithMaxConcurrentWrites limits the number of concurrent ApplyChanges calls made using any single provider secret.
+// The limit is shared by all providers created by the factory, a value of 0 or less disables the limit.
+func WithMaxConcurrentWrites(limit int) FactoryOption {
+	return func(f *factory) {
+		f.writeLimiter = newWriteLimiter(limit)
+	}
+}
+
+// WithWriteBudget limits the number of writes made using any single provider secret in each interval, each ApplyChanges
+// call is one write however many changes it applies. Further calls fail with a WriteBudgetExceededError until the next
+// interval. The budget is shared by all providers
+// created by the factory and can be overridden by the secret with the WRITE_BUDGET key, a budget or interval of 0 or
+// less disables the budget.
+func WithWriteBudget(budget int, interval time.Duration) FactoryOption {
+	return func(f *factory) {
+		f.writeBudget = newWriteBudget(budget, interval)
+	}
+}
+
+// WithRecordsCacheDuration keeps the records read from a zone for the given duration, so providers created for the same
+// credential and zone within the duration share a single read of the zone. The cached records of a zone are dropped
+// when changes are applied to it, a duration of 0 or less disables the cache.
+func WithRecordsCacheDuration(ttl time.Duration) FactoryOption {
+	return func(f *factory) {
+		f.recordsCache = newRecordsCache(ttl)
+	}
+}
+
+// WithZonesCacheDuration keeps the zones listed with a credential for the given duration, so the zones of records
+// using the same credential are found without listing its zones again. The cached zones are dropped when the
+// credential changes, a duration of 0 or less disables the cache.
+func WithZonesCacheDuration(ttl time.Duration) FactoryOption {
+	return func(f *factory) {
+		f.zonesCache = newZonesCache(ttl)
+	}
+}
+
+// WithCallTimeout bounds each call made to a provider to the given duration, calls that take longer fail with
+// context.DeadlineExceeded. A duration of 0 or less disables the timeout.
+func WithCallTimeout(timeout time.Duration) FactoryOption {
+	return func(f *factory) {
+		f.callTimeout = timeout
+	}
 }
 
 // NewFactory returns a new provider factory with the given client and given providers enabled.
 // Will return an error if any given provider has no registered provider implementation.
-func NewFactory(c client.Client, p []string) (Factory, error) {
+func NewFactory(c client.Client, p []string, opts ...FactoryOption) (Factory, error) {
 	var err error
 	registeredProviders := maps.Keys(constructors)
 	for _, provider := range p {
@@ -69,7 +142,11 @@ func NewFactory(c client.Client, p []string) (Factory, error) {
 			err = errors.Join(err, fmt.Errorf("provider '%s' not registered", provider))
 		}
 	}
-	return &factory{Client: c, providers: p}, err
+	f := &factory{Client: c, providers: p}
+	for _, opt := range opts {
+		opt(f)
+	}
+	return f, err
 }
 
 // ProviderFor will return a Provider interface for the given ProviderAccessor secret.
@@ -97,8 +174,32 @@ func (f *factory) ProviderFor(ctx context.Context, pa v1alpha1.ProviderAccessor,
 		if !slices.Contains(f.providers, provider) {
 			return nil, fmt.Errorf("provider '%s' not enabled", provider)
 		}
-		logger.V(1).Info(fmt.Sprintf("initializing %s provider with config", provider), "config", c)
-		return constructor(ctx, providerSecret, c)
+		secretHash := SecretHash(providerSecret)
+		logger.V(1).Info(fmt.Sprintf("initializing %s provider with config", provider), "config", c, "secretHash", secretHash)
+		zone, err := DeclaredZoneFromSecret(providerSecret)
+		if err != nil {
+			return nil, err
+		}
+		start := time.Now()
+		p, err := constructor(ctx, providerSecret, c)
+		metrics.ProviderConstructionDuration.WithLabelValues(provider, secretHash).Observe(time.Since(start).Seconds())
+		if err != nil {
+			return nil, observeError(provider, secretHash, err)
+		}
+		p = &instrumentedProvider{Provider: withDeadline(f.callTimeout, p), name: provider, secret: secretHash}
+		credential := client.ObjectKeyFromObject(providerSecret).String()
+		p = f.zonesCache.wrap(credential, providerSecret.ResourceVersion, c, p)
+		if zone != nil {
+			p = &declaredZoneProvider{Provider: p, zone: *zone, config: c}
+		}
+		if domains := AllowedDomainsFromSecret(providerSecret); len(domains) > 0 {
+			p = &allowedDomainsProvider{Provider: p, domains: externaldnsendpoint.NewDomainFilter(domains)}
+		}
+		p = f.recordsCache.wrap(credential, providerSecret.ResourceVersion, c, p)
+		if p, err = f.writeBudget.wrap(credential, providerSecret, p); err != nil {
+			return nil, err
+		}
+		return f.writeLimiter.wrap(credential, p), nil
 	}
 
 	return nil, fmt.Errorf("provider '%s' not registered", provider)
@@ -115,5 +216,10 @@ func NameForProviderSecret(secret *v1.Secret) (string, error) {
 	case v1alpha1.SecretTypeKuadrantInmemory:
 		return "inmemory", nil
 	}
+	constructorsLock.RLock()
+	defer constructorsLock.RUnlock()
+	if name, ok := secretTypes[secret.Type]; ok {
+		return name, nil
+	}
 	return "", errUnsupportedProvider
 }
@@ -18,6 +18,7 @@ package google
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strconv"
 	"strings"
@@ -106,6 +107,23 @@ func (r resourceRecordSetsService) List(project string, managedZone string) reso
 	return r.service.List(project, managedZone)
 }
 
+// Change interfaces
+type changesGetCallInterface interface {
+	Do(opts ...googleapi.CallOption) (*dnsv1.Change, error)
+}
+
+type changesServiceInterface interface {
+	Get(ctx context.Context, project string, managedZone string, changeId string) changesGetCallInterface
+}
+
+type changesService struct {
+	service *dnsv1.ChangesService
+}
+
+func (c changesService) Get(ctx context.Context, project string, managedZone string, changeId string) changesGetCallInterface {
+	return c.service.Get(project, managedZone, changeId).Context(ctx)
+}
+
 type GoogleDNSProvider struct {
 	*externaldnsgoogle.GoogleProvider
 	googleConfig externaldnsgoogle.GoogleConfig
@@ -114,9 +132,12 @@ type GoogleDNSProvider struct {
 	resourceRecordSetsClient resourceRecordSetsClientInterface
 	// A client for managing hosted zones
 	managedZonesClient managedZonesServiceInterface
+	// A client for getting change sets
+	changesClient changesServiceInterface
 }
 
 var _ provider.Provider = &GoogleDNSProvider{}
+var _ provider.ChangeTracker = &GoogleDNSProvider{}
 
 func NewProviderFromSecret(ctx context.Context, s *corev1.Secret, c provider.Config) (provider.Provider, error) {
 	if string(s.Data[v1alpha1.GoogleJsonKey]) == "" || string(s.Data[v1alpha1.GoogleProjectIDKey]) == "" {
@@ -130,7 +151,12 @@ func NewProviderFromSecret(ctx context.Context, s *corev1.Secret, c provider.Con
 
 	httpClient := metrics.NewInstrumentedClient("google", oauth2.NewClient(ctx, creds.TokenSource))
 
-	dnsClient, err := dnsv1.NewService(ctx, option.WithHTTPClient(httpClient))
+	clientOpts := []option.ClientOption{option.WithHTTPClient(httpClient)}
+	if endpoint := string(s.Data[v1alpha1.GoogleEndpointKey]); endpoint != "" {
+		clientOpts = append(clientOpts, option.WithEndpoint(endpoint))
+	}
+
+	dnsClient, err := dnsv1.NewService(ctx, clientOpts...)
 	if err != nil {
 		return nil, err
 	}
@@ -147,6 +173,16 @@ func NewProviderFromSecret(ctx context.Context, s *corev1.Secret, c provider.Con
 		DryRun:              false,
 	}
 
+	zone, err := provider.DeclaredZoneFromSecret(s)
+	if err != nil {
+		return nil, err
+	}
+	if zone != nil {
+		googleConfig.StaticZones = map[string]*dnsv1.ManagedZone{
+			zone.ID: {Name: zone.ID, DnsName: zone.DNSName + "."},
+		}
+	}
+
 	logger := log.FromContext(ctx).WithName("google-dns").WithValues("project", project)
 	ctx = log.IntoContext(ctx, logger)
 
@@ -161,6 +197,7 @@ func NewProviderFromSecret(ctx context.Context, s *corev1.Secret, c provider.Con
 		logger:                   logger,
 		resourceRecordSetsClient: resourceRecordSetsService{dnsClient.ResourceRecordSets},
 		managedZonesClient:       managedZonesService{dnsClient.ManagedZones},
+		changesClient:            changesService{dnsClient.Changes},
 	}
 
 	return p, nil
@@ -197,6 +234,11 @@ func (p *GoogleDNSProvider) Records(ctx context.Context) (endpoints []*externald
 
 // AdjustEndpoints takes source endpoints and translates them to a google specific format
 func (p *GoogleDNSProvider) AdjustEndpoints(endpoints []*externaldnsendpoint.Endpoint) ([]*externaldnsendpoint.Endpoint, error) {
+	for _, ep := range endpoints {
+		if _, ok := ep.GetProviderSpecificProperty(v1alpha1.ProviderSpecificMultiValue); ok {
+			return nil, fmt.Errorf("multi-value endpoint %s is not supported by the google provider", ep.DNSName)
+		}
+	}
 	return endpointsToGoogleFormat(endpoints), nil
 }
 
@@ -308,6 +350,7 @@ func (p *GoogleDNSProvider) DNSZones(ctx context.Context) ([]provider.DNSZone, e
 		hz := provider.DNSZone{
 			ID:      z.Name,
 			DNSName: strings.ToLower(strings.TrimSuffix(z.DnsName, ".")),
+			Private: z.Visibility == "private",
 		}
 		hzs = append(hzs, hz)
 	}
@@ -326,7 +369,43 @@ func (p *GoogleDNSProvider) ProviderSpecific() provider.ProviderSpecificLabels {
 	return provider.ProviderSpecificLabels{}
 }
 
+// PendingChanges returns the ids of the given changes, as zone/id, Cloud DNS reports as still pending, the remaining
+// changes are done.
+func (p *GoogleDNSProvider) PendingChanges(ctx context.Context, ids []string) ([]string, error) {
+	var pending []string
+	for _, id := range ids {
+		zone, changeID, ok := strings.Cut(id, "/")
+		if !ok {
+			return nil, fmt.Errorf("invalid change id %s", id)
+		}
+		change, err := p.changesClient.Get(ctx, p.googleConfig.Project, zone, changeID).Do()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get change %s: %w", id, err)
+		}
+		if change.Status == "pending" {
+			pending = append(pending, id)
+		}
+	}
+	return pending, nil
+}
+
+// classifyError returns the code of an error returned by the Cloud DNS API. Rate limits are reported as forbidden
+// errors with a rate limit reason.
+func classifyError(err error) (provider.ErrorCode, bool) {
+	var apiErr *googleapi.Error
+	if !errors.As(err, &apiErr) {
+		return "", false
+	}
+	for _, item := range apiErr.Errors {
+		if item.Reason == "rateLimitExceeded" || item.Reason == "userRateLimitExceeded" {
+			return provider.ErrorCodeThrottled, true
+		}
+	}
+	return provider.ErrorCodeForStatus(apiErr.Code), true
+}
+
 // Register this Provider with the provider factory
 func init() {
 	provider.RegisterProvider("google", NewProviderFromSecret, true)
+	provider.RegisterErrorClassifier("google", classifyError)
 }
@@ -4,13 +4,18 @@ package google
 
 import (
 	"context"
+	"fmt"
+	"net/http"
 	"sort"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
 	dnsv1 "google.golang.org/api/dns/v1"
+	"google.golang.org/api/googleapi"
 
 	externaldnsendpoint "sigs.k8s.io/external-dns/endpoint"
+
+	"github.com/kuadrant/dns-operator/internal/provider"
 )
 
 type MockResourceRecordSetsListCall struct {
@@ -32,6 +37,22 @@ func (m *MockResourceRecordSetsClient) List(project string, managedZone string)
 
 }
 
+type MockChangesGetCall struct {
+	DoFunc func(opts ...googleapi.CallOption) (*dnsv1.Change, error)
+}
+
+func (m *MockChangesGetCall) Do(opts ...googleapi.CallOption) (*dnsv1.Change, error) {
+	return m.DoFunc(opts...)
+}
+
+type MockChangesClient struct {
+	GetFunc func(ctx context.Context, project string, managedZone string, changeId string) changesGetCallInterface
+}
+
+func (m *MockChangesClient) Get(ctx context.Context, project string, managedZone string, changeId string) changesGetCallInterface {
+	return m.GetFunc(ctx, project, managedZone, changeId)
+}
+
 func sorted(endpoints []*externaldnsendpoint.Endpoint) {
 	sort.Slice(endpoints, func(i, j int) bool {
 		return endpoints[i].DNSName < endpoints[j].DNSName
@@ -777,3 +798,77 @@ func Test_endpointsToGoogleFormat(t *testing.T) {
 		})
 	}
 }
+
+func TestGoogleDNSProvider_PendingChanges(t *testing.T) {
+	status := map[string]string{"zone-1/1": "pending", "zone-1/2": "done", "zone-2/1": "done"}
+	p := &GoogleDNSProvider{
+		changesClient: &MockChangesClient{
+			GetFunc: func(ctx context.Context, project string, managedZone string, changeId string) changesGetCallInterface {
+				return &MockChangesGetCall{
+					DoFunc: func(opts ...googleapi.CallOption) (*dnsv1.Change, error) {
+						s, ok := status[managedZone+"/"+changeId]
+						if !ok {
+							return nil, &googleapi.Error{Code: 404}
+						}
+						return &dnsv1.Change{Id: changeId, Status: s}, nil
+					},
+				}
+			},
+		},
+	}
+
+	got, err := p.PendingChanges(context.Background(), []string{"zone-1/1", "zone-1/2", "zone-2/1"})
+	if err != nil {
+		t.Fatalf("PendingChanges() unexpected error = %v", err)
+	}
+	if diff := cmp.Diff([]string{"zone-1/1"}, got); diff != "" {
+		t.Errorf("PendingChanges (-want +got):\n%s", diff)
+	}
+
+	if _, err := p.PendingChanges(context.Background(), []string{"zone-1/3"}); err == nil {
+		t.Errorf("PendingChanges() expected an error for an unknown change")
+	}
+	if _, err := p.PendingChanges(context.Background(), []string{"1"}); err == nil {
+		t.Errorf("PendingChanges() expected an error for a change id without a zone")
+	}
+}
+
+func Test_classifyError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want provider.ErrorCode
+	}{
+		{
+			name: "rate limit",
+			err:  &googleapi.Error{Code: http.StatusForbidden, Errors: []googleapi.ErrorItem{{Reason: "rateLimitExceeded"}}},
+			want: provider.ErrorCodeThrottled,
+		},
+		{
+			name: "forbidden",
+			err:  &googleapi.Error{Code: http.StatusForbidden, Errors: []googleapi.ErrorItem{{Reason: "forbidden"}}},
+			want: provider.ErrorCodeUnauthorized,
+		},
+		{
+			name: "zone not found",
+			err:  fmt.Errorf("failed to list records: %w", &googleapi.Error{Code: http.StatusNotFound}),
+			want: provider.ErrorCodeNotFound,
+		},
+		{
+			name: "already exists",
+			err:  &googleapi.Error{Code: http.StatusConflict},
+			want: provider.ErrorCodeConflict,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := classifyError(tt.err)
+			if !ok {
+				t.Fatalf("classifyError() did not classify the error")
+			}
+			if got != tt.want {
+				t.Errorf("classifyError() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,123 @@
+package inmemory
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/kuadrant/dns-operator/api/v1alpha1"
+	"github.com/kuadrant/dns-operator/internal/provider"
+)
+
+// FaultError is the error returned by the provider for an ApplyChanges call failed by the faults of the provider
+// secret.
+type FaultError struct {
+	Code provider.ErrorCode
+}
+
+func (e *FaultError) Error() string {
+	return fmt.Sprintf("inmemory injected fault: %s", e.Code)
+}
+
+// faults are the scripted behaviors of the provider declared in the provider secret.
+type faults struct {
+	// latency is added to every Records and ApplyChanges call
+	latency time.Duration
+	// code is the error code of the failed ApplyChanges calls
+	code provider.ErrorCode
+	// state is the number of ApplyChanges calls left to fail, shared by all providers created from the secret
+	state *faultState
+}
+
+// faultState is the number of ApplyChanges calls left to fail for a version of a provider secret.
+type faultState struct {
+	sync.Mutex
+	resourceVersion string
+	remaining       int
+}
+
+// secretFaults are the fault states of the provider secrets, a provider is created from the secret on every reconcile
+// so the number of calls left to fail is kept between reconciles.
+var secretFaults = struct {
+	sync.Mutex
+	states map[types.NamespacedName]*faultState
+}{states: map[types.NamespacedName]*faultState{}}
+
+// faultsFromSecret returns the faults declared in the provider secret. Updating the secret restarts the count of
+// ApplyChanges calls to fail.
+func faultsFromSecret(s *v1.Secret) (*faults, error) {
+	f := &faults{code: provider.ErrorCodeUnavailable}
+	if v := string(s.Data[v1alpha1.InmemLatencyKey]); v != "" {
+		latency, err := time.ParseDuration(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", v1alpha1.InmemLatencyKey, err)
+		}
+		f.latency = latency
+	}
+	if v := string(s.Data[v1alpha1.InmemFailErrorCodeKey]); v != "" {
+		switch code := provider.ErrorCode(v); code {
+		case provider.ErrorCodeUnknown, provider.ErrorCodeThrottled, provider.ErrorCodeUnavailable, provider.ErrorCodeUnauthorized,
+			provider.ErrorCodeNotFound, provider.ErrorCodeInvalidRequest, provider.ErrorCodeConflict:
+			f.code = code
+		default:
+			return nil, fmt.Errorf("invalid %s: unknown error code %q", v1alpha1.InmemFailErrorCodeKey, v)
+		}
+	}
+
+	failures := 0
+	if v := string(s.Data[v1alpha1.InmemFailApplyChangesKey]); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("invalid %s: must be a positive number", v1alpha1.InmemFailApplyChangesKey)
+		}
+		failures = n
+	}
+
+	key := types.NamespacedName{Namespace: s.Namespace, Name: s.Name}
+	secretFaults.Lock()
+	defer secretFaults.Unlock()
+	state, ok := secretFaults.states[key]
+	if !ok {
+		state = &faultState{}
+		secretFaults.states[key] = state
+	}
+	state.Lock()
+	defer state.Unlock()
+	if !ok || state.resourceVersion != s.ResourceVersion {
+		state.resourceVersion = s.ResourceVersion
+		state.remaining = failures
+	}
+	f.state = state
+	return f, nil
+}
+
+// wait blocks for the latency of the faults, or until the context is done.
+func (f *faults) wait(ctx context.Context) error {
+	if f.latency <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(f.latency)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// fail returns a FaultError if the ApplyChanges call should fail.
+func (f *faults) fail() error {
+	f.state.Lock()
+	defer f.state.Unlock()
+	if f.state.remaining <= 0 {
+		return nil
+	}
+	f.state.remaining--
+	return &FaultError{Code: f.code}
+}
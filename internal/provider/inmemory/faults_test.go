@@ -0,0 +1,75 @@
+//go:build unit
+
+package inmemory
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kuadrant/dns-operator/api/v1alpha1"
+	"github.com/kuadrant/dns-operator/internal/provider"
+)
+
+func TestFaultsFromSecret(t *testing.T) {
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "faults", Namespace: "test", ResourceVersion: "1"},
+		Data: map[string][]byte{
+			v1alpha1.InmemFailApplyChangesKey: []byte("2"),
+			v1alpha1.InmemFailErrorCodeKey:    []byte("Throttled"),
+		},
+	}
+
+	// the count of calls to fail is shared by the providers created from the same version of the secret
+	for i := 0; i < 2; i++ {
+		f, err := faultsFromSecret(secret)
+		if err != nil {
+			t.Fatalf("faultsFromSecret() unexpected error %v", err)
+		}
+		err = f.fail()
+		var faultErr *FaultError
+		if !errors.As(err, &faultErr) || faultErr.Code != provider.ErrorCodeThrottled {
+			t.Fatalf("fail() = %v, want a %s fault", err, provider.ErrorCodeThrottled)
+		}
+		if code := provider.ClassifyError("inmemory", err); code != provider.ErrorCodeThrottled {
+			t.Errorf("ClassifyError() = %s, want %s", code, provider.ErrorCodeThrottled)
+		}
+	}
+	f, _ := faultsFromSecret(secret)
+	if err := f.fail(); err != nil {
+		t.Errorf("fail() = %v, want no error after the declared failures", err)
+	}
+
+	// updating the secret restarts the count
+	secret.ResourceVersion = "2"
+	f, _ = faultsFromSecret(secret)
+	if err := f.fail(); err == nil {
+		t.Errorf("fail() = nil, want a fault after the secret is updated")
+	}
+
+	for _, data := range []map[string][]byte{
+		{v1alpha1.InmemFailApplyChangesKey: []byte("-1")},
+		{v1alpha1.InmemFailErrorCodeKey: []byte("Teapot")},
+		{v1alpha1.InmemLatencyKey: []byte("soon")},
+	} {
+		if _, err := faultsFromSecret(&v1.Secret{Data: data}); err == nil {
+			t.Errorf("faultsFromSecret(%v) expected an error", data)
+		}
+	}
+}
+
+func TestFaultsLatency(t *testing.T) {
+	f := &faults{latency: time.Hour}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := f.wait(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("wait() = %v, want %v", err, context.Canceled)
+	}
+	if err := (&faults{}).wait(context.Background()); err != nil {
+		t.Errorf("wait() = %v, want no error without latency", err)
+	}
+}
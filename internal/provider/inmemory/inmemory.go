@@ -18,10 +18,13 @@ package inmemory
 
 import (
 	"context"
+	"errors"
 	"strings"
 
 	v1 "k8s.io/api/core/v1"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/external-dns/endpoint"
+	"sigs.k8s.io/external-dns/plan"
 
 	"github.com/kuadrant/dns-operator/api/v1alpha1"
 	"github.com/kuadrant/dns-operator/internal/external-dns/provider/inmemory"
@@ -30,16 +33,23 @@ import (
 
 type InMemoryDNSProvider struct {
 	*inmemory.InMemoryProvider
+	faults *faults
 }
 
 var client *inmemory.InMemoryClient
 
 var _ provider.Provider = &InMemoryDNSProvider{}
+var _ provider.RecordTypeSupporter = &InMemoryDNSProvider{}
 
 func NewProviderFromSecret(ctx context.Context, s *v1.Secret, c provider.Config) (provider.Provider, error) {
 	logger := log.FromContext(ctx).WithName("inmemory-dns")
 	ctx = log.IntoContext(ctx, logger)
 
+	f, err := faultsFromSecret(s)
+	if err != nil {
+		return nil, err
+	}
+
 	initZones := []string{}
 	if z := string(s.Data[v1alpha1.InmemInitZonesKey]); z != "" {
 		initZones = strings.Split(z, ",")
@@ -53,6 +63,7 @@ func NewProviderFromSecret(ctx context.Context, s *v1.Secret, c provider.Config)
 		inmemory.InMemoryWithLogging())
 	p := &InMemoryDNSProvider{
 		InMemoryProvider: inmemoryProvider,
+		faults:           f,
 	}
 
 	availableZones := []string{}
@@ -69,6 +80,26 @@ func NewProviderFromSecret(ctx context.Context, s *v1.Secret, c provider.Config)
 	return p, nil
 }
 
+// Records returns the endpoints of all zones after the latency declared in the provider secret.
+func (p *InMemoryDNSProvider) Records(ctx context.Context) ([]*endpoint.Endpoint, error) {
+	if err := p.faults.wait(ctx); err != nil {
+		return nil, err
+	}
+	return p.InMemoryProvider.Records(ctx)
+}
+
+// ApplyChanges applies the changes after the latency declared in the provider secret, unless the call is one of the
+// calls the provider secret declares to fail.
+func (p *InMemoryDNSProvider) ApplyChanges(ctx context.Context, changes *plan.Changes) error {
+	if err := p.faults.wait(ctx); err != nil {
+		return err
+	}
+	if err := p.faults.fail(); err != nil {
+		return err
+	}
+	return p.InMemoryProvider.ApplyChanges(ctx, changes)
+}
+
 func (p *InMemoryDNSProvider) DNSZones(_ context.Context) ([]provider.DNSZone, error) {
 	var hzs []provider.DNSZone
 	zones := p.Zones()
@@ -90,6 +121,11 @@ func (p *InMemoryDNSProvider) DNSZoneForHost(ctx context.Context, host string) (
 	return provider.FindDNSZoneForHost(ctx, host, zones)
 }
 
+// SupportsRecordType returns true for all record types, the endpoints are only stored.
+func (*InMemoryDNSProvider) SupportsRecordType(_ string) bool {
+	return true
+}
+
 func (i *InMemoryDNSProvider) ProviderSpecific() provider.ProviderSpecificLabels {
 	return provider.ProviderSpecificLabels{}
 }
@@ -98,4 +134,21 @@ func (i *InMemoryDNSProvider) ProviderSpecific() provider.ProviderSpecificLabels
 func init() {
 	client = inmemory.NewInMemoryClient()
 	provider.RegisterProvider("inmemory", NewProviderFromSecret, false)
+	provider.RegisterErrorClassifier("inmemory", classifyError)
+}
+
+// classifyError returns the code of an error returned by the inmemory client.
+func classifyError(err error) (provider.ErrorCode, bool) {
+	var faultErr *FaultError
+	switch {
+	case errors.As(err, &faultErr):
+		return faultErr.Code, true
+	case errors.Is(err, inmemory.ErrZoneNotFound):
+		return provider.ErrorCodeNotFound, true
+	case errors.Is(err, inmemory.ErrRecordAlreadyExists), errors.Is(err, inmemory.ErrRecordNotFound), errors.Is(err, inmemory.ErrDuplicateRecordFound):
+		return provider.ErrorCodeConflict, true
+	case errors.Is(err, inmemory.ErrNoTargetValue):
+		return provider.ErrorCodeInvalidRequest, true
+	}
+	return "", false
 }
@@ -0,0 +1,507 @@
+package inmemory
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/net/dns/dnsmessage"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/external-dns/endpoint"
+
+	"github.com/kuadrant/dns-operator/api/v1alpha1"
+	"github.com/kuadrant/dns-operator/internal/external-dns/provider/inmemory"
+)
+
+const (
+	// maxUDPMessageSize is the size of the largest response sent over UDP, larger responses are truncated so the client
+	// retries the query over TCP
+	maxUDPMessageSize = 512
+	// maxTCPMessageSize is the size of the largest response sent over TCP, the largest a two byte length prefix allows
+	maxTCPMessageSize = 65535
+	// tcpIdleTimeout is how long a TCP connection is kept open waiting for the next query
+	tcpIdleTimeout = 10 * time.Second
+	// defaultTTL is the TTL of answers for endpoints without a TTL, and of the SOA record of the zones
+	defaultTTL = 300
+
+	// typeNAPTR and typeTLSA are the types of NAPTR and TLSA records, which dnsmessage has no resources for
+	typeNAPTR dnsmessage.Type = 35
+	typeTLSA  dnsmessage.Type = 52
+)
+
+// recordTypes are the types of the endpoints the server answers queries for.
+var recordTypes = map[string]dnsmessage.Type{
+	endpoint.RecordTypeA:     dnsmessage.TypeA,
+	endpoint.RecordTypeAAAA:  dnsmessage.TypeAAAA,
+	endpoint.RecordTypeCNAME: dnsmessage.TypeCNAME,
+	endpoint.RecordTypeNS:    dnsmessage.TypeNS,
+	endpoint.RecordTypeTXT:   dnsmessage.TypeTXT,
+	endpoint.RecordTypeMX:    dnsmessage.TypeMX,
+	endpoint.RecordTypeSRV:   dnsmessage.TypeSRV,
+	v1alpha1.RecordTypeNAPTR: typeNAPTR,
+	v1alpha1.RecordTypeTLSA:  typeTLSA,
+}
+
+// Server is an authoritative DNS server for the zones of the inmemory provider. It answers queries over UDP and TCP
+// with the records published to the provider, so tests and local demos can resolve them end to end rather than only
+// asserting on the state of the DNSRecords.
+type Server struct {
+	conn     net.PacketConn
+	listener net.Listener
+	client   *inmemory.InMemoryClient
+}
+
+// NewServer returns a Server listening on the given address over UDP and TCP, e.g. "127.0.0.1:5353" or "127.0.0.1:0"
+// for a random port.
+func NewServer(address string) (*Server, error) {
+	conn, err := net.ListenPacket("udp", address)
+	if err != nil {
+		return nil, err
+	}
+	// listen on the port of the UDP address, which is only known once listening for a random port
+	listener, err := net.Listen("tcp", conn.LocalAddr().String())
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return &Server{conn: conn, listener: listener, client: client}, nil
+}
+
+// Addr returns the address the server is listening on.
+func (s *Server) Addr() net.Addr {
+	return s.conn.LocalAddr()
+}
+
+// Start answers queries until the given context is done.
+func (s *Server) Start(ctx context.Context) error {
+	logger := log.FromContext(ctx).WithName("inmemory-dns-server")
+	logger.Info("serving the zones of the inmemory provider", "address", s.Addr().String())
+
+	go func() {
+		<-ctx.Done()
+		s.conn.Close()
+		s.listener.Close()
+	}()
+	go s.serveTCP(ctx)
+
+	buf := make([]byte, 4096)
+	for {
+		n, addr, err := s.conn.ReadFrom(buf)
+		if err != nil {
+			if ctx.Err() != nil || errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return err
+		}
+		response, err := s.respond(buf[:n], maxUDPMessageSize)
+		if err != nil {
+			logger.V(1).Info("ignoring query", "from", addr.String(), "error", err.Error())
+			continue
+		}
+		if _, err = s.conn.WriteTo(response, addr); err != nil {
+			logger.V(1).Info("unable to send response", "to", addr.String(), "error", err.Error())
+		}
+	}
+}
+
+// serveTCP answers queries over TCP until the given context is done.
+func (s *Server) serveTCP(ctx context.Context) {
+	logger := log.FromContext(ctx).WithName("inmemory-dns-server")
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			if ctx.Err() == nil && !errors.Is(err, net.ErrClosed) {
+				logger.Error(err, "unable to accept TCP connection")
+			}
+			return
+		}
+		go s.handleTCP(ctx, conn)
+	}
+}
+
+// handleTCP answers the queries sent on the given TCP connection, each prefixed with its length, until the client
+// closes it or it is idle.
+func (s *Server) handleTCP(ctx context.Context, conn net.Conn) {
+	logger := log.FromContext(ctx).WithName("inmemory-dns-server")
+	defer conn.Close()
+	for {
+		_ = conn.SetDeadline(time.Now().Add(tcpIdleTimeout))
+		var length uint16
+		if err := binary.Read(conn, binary.BigEndian, &length); err != nil {
+			return
+		}
+		query := make([]byte, length)
+		if _, err := io.ReadFull(conn, query); err != nil {
+			return
+		}
+		response, err := s.respond(query, maxTCPMessageSize)
+		if err != nil {
+			logger.V(1).Info("ignoring query", "from", conn.RemoteAddr().String(), "error", err.Error())
+			return
+		}
+		if _, err = conn.Write(append(binary.BigEndian.AppendUint16(nil, uint16(len(response))), response...)); err != nil {
+			logger.V(1).Info("unable to send response", "to", conn.RemoteAddr().String(), "error", err.Error())
+			return
+		}
+	}
+}
+
+// respond returns the packed response to the given packed query, truncated if larger than the given size.
+func (s *Server) respond(packed []byte, maxSize int) ([]byte, error) {
+	var query dnsmessage.Message
+	if err := query.Unpack(packed); err != nil {
+		return nil, err
+	}
+	response := dnsmessage.Message{
+		Header: dnsmessage.Header{
+			ID:               query.Header.ID,
+			Response:         true,
+			OpCode:           query.Header.OpCode,
+			RecursionDesired: query.Header.RecursionDesired,
+		},
+	}
+	switch {
+	case query.Header.OpCode != 0:
+		response.Header.RCode = dnsmessage.RCodeNotImplemented
+	case len(query.Questions) != 1:
+		response.Header.RCode = dnsmessage.RCodeFormatError
+	default:
+		response.Questions = query.Questions
+		s.answer(&response, query.Questions[0])
+	}
+
+	b, err := response.Pack()
+	if err != nil || len(b) <= maxSize {
+		return b, err
+	}
+	response.Header.Truncated = true
+	response.Answers, response.Authorities = nil, nil
+	return response.Pack()
+}
+
+// answer adds the answers to the given question from the records of the zone of the question to the given response.
+func (s *Server) answer(response *dnsmessage.Message, question dnsmessage.Question) {
+	name := normalizeName(question.Name.String())
+	zone, zoneName := s.zoneFor(name)
+	if zone == "" {
+		response.Header.RCode = dnsmessage.RCodeRefused
+		return
+	}
+	records, err := s.client.Records(zone)
+	if err != nil {
+		response.Header.RCode = dnsmessage.RCodeServerFailure
+		return
+	}
+
+	// NS records below the apex delegate the names under them to another zone, the response is a referral
+	if delegation := delegationFor(records, zoneName, name); len(delegation) > 0 {
+		response.Authorities = resources(delegation...)
+		return
+	}
+	response.Header.Authoritative = true
+	soa := soaResource(records, zoneName)
+
+	matching := recordsFor(records, name)
+	if len(matching) == 0 && name != zoneName && !hasDescendants(records, name) {
+		response.Header.RCode = dnsmessage.RCodeNameError
+		response.Authorities = []dnsmessage.Resource{soa}
+		return
+	}
+
+	for _, ep := range matching {
+		if recordTypes[ep.RecordType] == question.Type ||
+			(ep.RecordType == endpoint.RecordTypeCNAME && question.Type != dnsmessage.TypeCNAME) {
+			response.Answers = append(response.Answers, resourcesNamed(question.Name, ep)...)
+		}
+	}
+	if name == zoneName && question.Type == dnsmessage.TypeSOA {
+		response.Answers = append(response.Answers, soa)
+	}
+	if len(response.Answers) == 0 {
+		response.Authorities = []dnsmessage.Resource{soa}
+	}
+}
+
+// zoneFor returns the id and name of the zone of the inmemory provider with the longest name the given name is in.
+func (s *Server) zoneFor(name string) (string, string) {
+	var zoneID, zoneName string
+	for id, zone := range s.client.Zones() {
+		zone = normalizeName(zone)
+		if (name == zone || strings.HasSuffix(name, "."+zone)) && len(zone) > len(zoneName) {
+			zoneID, zoneName = id, zone
+		}
+	}
+	return zoneID, zoneName
+}
+
+// normalizeName returns the given DNS name in lower case without the trailing dot.
+func normalizeName(name string) string {
+	return strings.ToLower(strings.TrimSuffix(name, "."))
+}
+
+// parentName returns the name of the parent of the given DNS name.
+func parentName(name string) string {
+	_, parent, _ := strings.Cut(name, ".")
+	return parent
+}
+
+// delegationFor returns the NS records of the delegation from the given zone the given name is in, or nil if the zone
+// is authoritative for the name.
+func delegationFor(records []*endpoint.Endpoint, zoneName, name string) []*endpoint.Endpoint {
+	var delegation []*endpoint.Endpoint
+	// walk up to the apex, the delegation is the cut closest to it
+	for cut := name; cut != zoneName && cut != ""; cut = parentName(cut) {
+		var nameservers []*endpoint.Endpoint
+		for _, ep := range records {
+			if ep.RecordType == endpoint.RecordTypeNS && normalizeName(ep.DNSName) == cut {
+				nameservers = append(nameservers, ep)
+			}
+		}
+		if len(nameservers) > 0 {
+			delegation = nameservers
+		}
+	}
+	return delegation
+}
+
+// recordsFor returns the records of the given name, or of the closest wildcard that matches the name.
+func recordsFor(records []*endpoint.Endpoint, name string) []*endpoint.Endpoint {
+	var matching []*endpoint.Endpoint
+	for _, ep := range records {
+		if normalizeName(ep.DNSName) == name {
+			matching = append(matching, ep)
+		}
+	}
+	if len(matching) > 0 || hasDescendants(records, name) {
+		return matching
+	}
+	for parent := parentName(name); parent != ""; parent = parentName(parent) {
+		for _, ep := range records {
+			if normalizeName(ep.DNSName) == "*."+parent {
+				matching = append(matching, ep)
+			}
+		}
+		if len(matching) > 0 {
+			return matching
+		}
+	}
+	return nil
+}
+
+// hasDescendants returns whether any of the given records is below the given name, so the name exists without
+// records of its own.
+func hasDescendants(records []*endpoint.Endpoint, name string) bool {
+	for _, ep := range records {
+		if strings.HasSuffix(normalizeName(ep.DNSName), "."+name) {
+			return true
+		}
+	}
+	return false
+}
+
+// soaResource returns the SOA record of the given zone, with the first nameserver of the zone as the primary.
+func soaResource(records []*endpoint.Endpoint, zoneName string) dnsmessage.Resource {
+	primary := "ns1." + zoneName
+	for _, ep := range records {
+		if ep.RecordType == endpoint.RecordTypeNS && normalizeName(ep.DNSName) == zoneName && len(ep.Targets) > 0 {
+			primary = normalizeName(ep.Targets[0])
+			break
+		}
+	}
+	return dnsmessage.Resource{
+		Header: dnsmessage.ResourceHeader{Name: fqdn(zoneName), Class: dnsmessage.ClassINET, TTL: defaultTTL},
+		Body: &dnsmessage.SOAResource{
+			NS:      fqdn(primary),
+			MBox:    fqdn("hostmaster." + zoneName),
+			Serial:  1,
+			Refresh: 3600,
+			Retry:   600,
+			Expire:  86400,
+			MinTTL:  defaultTTL,
+		},
+	}
+}
+
+// fqdn returns the dnsmessage name of the given DNS name, or the root name if the name is invalid.
+func fqdn(name string) dnsmessage.Name {
+	n, err := dnsmessage.NewName(normalizeName(name) + ".")
+	if err != nil {
+		return dnsmessage.MustNewName(".")
+	}
+	return n
+}
+
+// resources returns the resource records of the targets of the given endpoints.
+func resources(endpoints ...*endpoint.Endpoint) []dnsmessage.Resource {
+	var rrs []dnsmessage.Resource
+	for _, ep := range endpoints {
+		rrs = append(rrs, resourcesNamed(fqdn(ep.DNSName), ep)...)
+	}
+	return rrs
+}
+
+// resourcesNamed returns the resource records of the targets of the given endpoint with the given owner name, which
+// differs from the name of the endpoint for wildcards. Targets that are not valid for the record type are skipped.
+func resourcesNamed(name dnsmessage.Name, ep *endpoint.Endpoint) []dnsmessage.Resource {
+	ttl := uint32(defaultTTL)
+	if ep.RecordTTL.IsConfigured() {
+		ttl = uint32(ep.RecordTTL)
+	}
+	var rrs []dnsmessage.Resource
+	for _, target := range ep.Targets {
+		if body := resourceBody(ep.RecordType, target); body != nil {
+			rrs = append(rrs, dnsmessage.Resource{
+				Header: dnsmessage.ResourceHeader{Name: name, Class: dnsmessage.ClassINET, TTL: ttl},
+				Body:   body,
+			})
+		}
+	}
+	return rrs
+}
+
+// resourceBody returns the data of a resource record with the given type and target, or nil if the type is not
+// supported or the target is not valid for it.
+func resourceBody(recordType, target string) dnsmessage.ResourceBody {
+	switch recordType {
+	case endpoint.RecordTypeA:
+		if ip := net.ParseIP(target).To4(); ip != nil {
+			return &dnsmessage.AResource{A: [4]byte(ip)}
+		}
+	case endpoint.RecordTypeAAAA:
+		if ip := net.ParseIP(target); ip != nil && ip.To4() == nil {
+			return &dnsmessage.AAAAResource{AAAA: [16]byte(ip.To16())}
+		}
+	case endpoint.RecordTypeCNAME:
+		return &dnsmessage.CNAMEResource{CNAME: fqdn(target)}
+	case endpoint.RecordTypeNS:
+		return &dnsmessage.NSResource{NS: fqdn(target)}
+	case endpoint.RecordTypeTXT:
+		// character strings of up to 255 bytes
+		text := strings.Trim(target, "\"")
+		txt := []string{}
+		for len(text) > 255 {
+			txt, text = append(txt, text[:255]), text[255:]
+		}
+		return &dnsmessage.TXTResource{TXT: append(txt, text)}
+	case endpoint.RecordTypeMX:
+		// preference and exchange, e.g. "10 mail.example.com"
+		if fields := strings.Fields(target); len(fields) == 2 {
+			if pref, err := strconv.ParseUint(fields[0], 10, 16); err == nil {
+				return &dnsmessage.MXResource{Pref: uint16(pref), MX: fqdn(fields[1])}
+			}
+		}
+	case endpoint.RecordTypeSRV:
+		// priority, weight, port and target, e.g. "10 5 443 svc.example.com"
+		if fields := strings.Fields(target); len(fields) == 4 {
+			values := make([]uint16, 3)
+			for i := range values {
+				value, err := strconv.ParseUint(fields[i], 10, 16)
+				if err != nil {
+					return nil
+				}
+				values[i] = uint16(value)
+			}
+			return &dnsmessage.SRVResource{Priority: values[0], Weight: values[1], Port: values[2], Target: fqdn(fields[3])}
+		}
+	case v1alpha1.RecordTypeNAPTR:
+		// order, preference, quoted flags, service and regexp, and replacement, e.g.
+		// `100 10 "u" "E2U+sip" "!^.*$!sip:info@example.com!" .`. The replacement "." is missing from targets that had
+		// their trailing dot trimmed, as external-dns does for the targets of new endpoints.
+		fields := presentationFields(target)
+		if len(fields) == 5 {
+			fields = append(fields, ".")
+		}
+		if len(fields) == 6 {
+			var data []byte
+			for _, field := range fields[:2] {
+				value, err := strconv.ParseUint(field, 10, 16)
+				if err != nil {
+					return nil
+				}
+				data = binary.BigEndian.AppendUint16(data, uint16(value))
+			}
+			for _, field := range fields[2:5] {
+				if len(field) > 255 {
+					return nil
+				}
+				data = append(append(data, byte(len(field))), field...)
+			}
+			if data = appendName(data, fields[5]); data != nil {
+				return &dnsmessage.UnknownResource{Type: typeNAPTR, Data: data}
+			}
+		}
+	case v1alpha1.RecordTypeTLSA:
+		// usage, selector, matching type and hex encoded certificate association data, e.g. "3 1 1 0c72ac70..."
+		if fields := strings.Fields(target); len(fields) >= 4 {
+			var data []byte
+			for _, field := range fields[:3] {
+				value, err := strconv.ParseUint(field, 10, 8)
+				if err != nil {
+					return nil
+				}
+				data = append(data, byte(value))
+			}
+			association, err := hex.DecodeString(strings.Join(fields[3:], ""))
+			if err != nil {
+				return nil
+			}
+			return &dnsmessage.UnknownResource{Type: typeTLSA, Data: append(data, association...)}
+		}
+	}
+	return nil
+}
+
+// presentationFields returns the fields of a record in presentation format separated by spaces, with the quotes and
+// escapes of quoted fields removed.
+func presentationFields(value string) []string {
+	var fields []string
+	var field strings.Builder
+	inField, quoted := false, false
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		switch {
+		case c == '\\' && i+1 < len(value):
+			i++
+			field.WriteByte(value[i])
+			inField = true
+		case c == '"':
+			quoted = !quoted
+			inField = true
+		case !quoted && (c == ' ' || c == '\t'):
+			if inField {
+				fields = append(fields, field.String())
+				field.Reset()
+				inField = false
+			}
+		default:
+			field.WriteByte(c)
+			inField = true
+		}
+	}
+	if inField {
+		fields = append(fields, field.String())
+	}
+	return fields
+}
+
+// appendName appends the uncompressed wire format of the given DNS name to the given data, or returns nil if the name
+// is invalid.
+func appendName(data []byte, name string) []byte {
+	name = normalizeName(name)
+	if name == "" {
+		return append(data, 0)
+	}
+	for _, label := range strings.Split(name, ".") {
+		if label == "" || len(label) > 63 {
+			return nil
+		}
+		data = append(append(data, byte(len(label))), label...)
+	}
+	return append(data, 0)
+}
@@ -0,0 +1,285 @@
+//go:build unit
+
+package inmemory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"golang.org/x/net/dns/dnsmessage"
+	"sigs.k8s.io/external-dns/endpoint"
+	"sigs.k8s.io/external-dns/plan"
+
+	"github.com/kuadrant/dns-operator/api/v1alpha1"
+)
+
+// startServer creates the given zone with the given records in the inmemory client and starts a server for it.
+func startServer(t *testing.T, zone string, records ...*endpoint.Endpoint) *Server {
+	t.Helper()
+	if err := client.CreateZone(zone); err != nil {
+		t.Fatalf("CreateZone() unexpected error %v", err)
+	}
+	t.Cleanup(func() { _ = client.DeleteZone(zone) })
+	if err := client.ApplyChanges(context.Background(), zone, &plan.Changes{Create: records}); err != nil {
+		t.Fatalf("ApplyChanges() unexpected error %v", err)
+	}
+
+	s, err := NewServer("127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("NewServer() unexpected error %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	go func() { _ = s.Start(ctx) }()
+	return s
+}
+
+// serverResolver returns a resolver that sends all queries to the given server, over TCP when a UDP response is
+// truncated.
+func serverResolver(s *Server) *net.Resolver {
+	return &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			d := net.Dialer{}
+			return d.DialContext(ctx, network, s.Addr().String())
+		},
+	}
+}
+
+// exchange sends a query for the given name and type to the given server and returns the response.
+func exchange(t *testing.T, s *Server, name string, qtype dnsmessage.Type) dnsmessage.Message {
+	t.Helper()
+	query := dnsmessage.Message{
+		Header:    dnsmessage.Header{ID: 1},
+		Questions: []dnsmessage.Question{{Name: dnsmessage.MustNewName(name), Type: qtype, Class: dnsmessage.ClassINET}},
+	}
+	packed, err := query.Pack()
+	if err != nil {
+		t.Fatalf("Pack() unexpected error %v", err)
+	}
+	conn, err := net.Dial("udp", s.Addr().String())
+	if err != nil {
+		t.Fatalf("Dial() unexpected error %v", err)
+	}
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(5 * time.Second))
+	if _, err = conn.Write(packed); err != nil {
+		t.Fatalf("Write() unexpected error %v", err)
+	}
+	buf := make([]byte, 4096)
+	n, err := conn.Read(buf)
+	if err != nil {
+		t.Fatalf("Read() unexpected error %v", err)
+	}
+	var response dnsmessage.Message
+	if err = response.Unpack(buf[:n]); err != nil {
+		t.Fatalf("Unpack() unexpected error %v", err)
+	}
+	return response
+}
+
+func TestServerResolvesRecords(t *testing.T) {
+	s := startServer(t, "server.example.com",
+		endpoint.NewEndpoint("foo.server.example.com", endpoint.RecordTypeA, "1.1.1.1", "2.2.2.2"),
+		endpoint.NewEndpoint("foo.server.example.com", endpoint.RecordTypeTXT, "\"heritage=external-dns\""),
+		endpoint.NewEndpoint("www.server.example.com", endpoint.RecordTypeCNAME, "foo.server.example.com"),
+		endpoint.NewEndpoint("*.apps.server.example.com", endpoint.RecordTypeAAAA, "2001:db8::1"),
+	)
+	resolver := serverResolver(s)
+	ctx := context.Background()
+
+	addrs, err := resolver.LookupHost(ctx, "foo.server.example.com")
+	if err != nil {
+		t.Fatalf("LookupHost() unexpected error %v", err)
+	}
+	sort.Strings(addrs)
+	if !reflect.DeepEqual(addrs, []string{"1.1.1.1", "2.2.2.2"}) {
+		t.Errorf("LookupHost() = %v, want [1.1.1.1 2.2.2.2]", addrs)
+	}
+
+	txt, err := resolver.LookupTXT(ctx, "foo.server.example.com")
+	if err != nil || !reflect.DeepEqual(txt, []string{"heritage=external-dns"}) {
+		t.Errorf("LookupTXT() = %v, %v, want [heritage=external-dns]", txt, err)
+	}
+
+	cname, err := resolver.LookupCNAME(ctx, "www.server.example.com")
+	if err != nil || cname != "foo.server.example.com." {
+		t.Errorf("LookupCNAME() = %s, %v, want foo.server.example.com.", cname, err)
+	}
+
+	addrs, err = resolver.LookupHost(ctx, "bar.apps.server.example.com")
+	if err != nil || !reflect.DeepEqual(addrs, []string{"2001:db8::1"}) {
+		t.Errorf("LookupHost() = %v, %v, want the wildcard address [2001:db8::1]", addrs, err)
+	}
+
+	_, err = resolver.LookupHost(ctx, "missing.server.example.com")
+	var dnsErr *net.DNSError
+	if !errors.As(err, &dnsErr) || !dnsErr.IsNotFound {
+		t.Errorf("LookupHost() error = %v, want not found", err)
+	}
+}
+
+func TestServerResolvesNAPTRAndTLSA(t *testing.T) {
+	s := startServer(t, "records.example.com",
+		endpoint.NewEndpoint("sip.records.example.com", v1alpha1.RecordTypeNAPTR, `100 10 "u" "E2U+sip" "!^.*$!sip:info@example.com!" .`),
+		endpoint.NewEndpoint("_443._tcp.records.example.com", v1alpha1.RecordTypeTLSA, "3 1 1 0c72ac70"),
+	)
+
+	testCases := []struct {
+		name       string
+		qname      string
+		qtype      dnsmessage.Type
+		expectData []byte
+	}{
+		{
+			name:  "NAPTR",
+			qname: "sip.records.example.com.",
+			qtype: typeNAPTR,
+			expectData: append([]byte{0, 100, 0, 10, 1, 'u', 7, 'E', '2', 'U', '+', 's', 'i', 'p', 27},
+				append([]byte("!^.*$!sip:info@example.com!"), 0)...),
+		},
+		{
+			name:       "TLSA",
+			qname:      "_443._tcp.records.example.com.",
+			qtype:      typeTLSA,
+			expectData: []byte{3, 1, 1, 0x0c, 0x72, 0xac, 0x70},
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			response := exchange(t, s, tt.qname, tt.qtype)
+			if len(response.Answers) != 1 {
+				t.Fatalf("Answers = %v, want 1", response.Answers)
+			}
+			body, ok := response.Answers[0].Body.(*dnsmessage.UnknownResource)
+			if !ok || body.Type != tt.qtype || !reflect.DeepEqual(body.Data, tt.expectData) {
+				t.Errorf("Answer = %v, want %s record with data %v", response.Answers[0].Body, tt.qtype, tt.expectData)
+			}
+		})
+	}
+}
+
+func TestServerLargeResponses(t *testing.T) {
+	var targets []string
+	for i := 1; i <= 50; i++ {
+		targets = append(targets, fmt.Sprintf("10.0.0.%d", i))
+	}
+	s := startServer(t, "large.example.com", endpoint.NewEndpoint("foo.large.example.com", endpoint.RecordTypeA, targets...))
+
+	// 50 answers don't fit in a UDP response
+	response := exchange(t, s, "foo.large.example.com.", dnsmessage.TypeA)
+	if !response.Header.Truncated || len(response.Answers) != 0 {
+		t.Errorf("Truncated = %v, Answers = %d, want a truncated response without answers", response.Header.Truncated, len(response.Answers))
+	}
+
+	// the resolver retries over TCP
+	addrs, err := serverResolver(s).LookupHost(context.Background(), "foo.large.example.com")
+	if err != nil {
+		t.Fatalf("LookupHost() unexpected error %v", err)
+	}
+	sort.Strings(addrs)
+	sort.Strings(targets)
+	if !reflect.DeepEqual(addrs, targets) {
+		t.Errorf("LookupHost() = %v, want %v", addrs, targets)
+	}
+}
+
+func TestServerResponses(t *testing.T) {
+	s := startServer(t, "responses.example.com",
+		endpoint.NewEndpoint("responses.example.com", endpoint.RecordTypeNS, "ns1.example.net"),
+		endpoint.NewEndpoint("foo.responses.example.com", endpoint.RecordTypeA, "1.1.1.1"),
+		endpoint.NewEndpoint("sub.responses.example.com", endpoint.RecordTypeNS, "ns1.example.org", "ns2.example.org"),
+	)
+
+	testCases := []struct {
+		name                string
+		qname               string
+		qtype               dnsmessage.Type
+		expectRCode         dnsmessage.RCode
+		expectAuthoritative bool
+		expectAnswers       int
+		expectAuthorities   dnsmessage.Type
+	}{
+		{
+			name:                "answer",
+			qname:               "foo.responses.example.com.",
+			qtype:               dnsmessage.TypeA,
+			expectRCode:         dnsmessage.RCodeSuccess,
+			expectAuthoritative: true,
+			expectAnswers:       1,
+		},
+		{
+			name:                "no data",
+			qname:               "foo.responses.example.com.",
+			qtype:               dnsmessage.TypeAAAA,
+			expectRCode:         dnsmessage.RCodeSuccess,
+			expectAuthoritative: true,
+			expectAuthorities:   dnsmessage.TypeSOA,
+		},
+		{
+			name:                "name error",
+			qname:               "bar.responses.example.com.",
+			qtype:               dnsmessage.TypeA,
+			expectRCode:         dnsmessage.RCodeNameError,
+			expectAuthoritative: true,
+			expectAuthorities:   dnsmessage.TypeSOA,
+		},
+		{
+			name:                "apex nameservers",
+			qname:               "responses.example.com.",
+			qtype:               dnsmessage.TypeNS,
+			expectRCode:         dnsmessage.RCodeSuccess,
+			expectAuthoritative: true,
+			expectAnswers:       1,
+		},
+		{
+			name:                "apex SOA",
+			qname:               "responses.example.com.",
+			qtype:               dnsmessage.TypeSOA,
+			expectRCode:         dnsmessage.RCodeSuccess,
+			expectAuthoritative: true,
+			expectAnswers:       1,
+		},
+		{
+			name:              "referral to delegated zone",
+			qname:             "foo.sub.responses.example.com.",
+			qtype:             dnsmessage.TypeA,
+			expectRCode:       dnsmessage.RCodeSuccess,
+			expectAuthorities: dnsmessage.TypeNS,
+		},
+		{
+			name:        "not authoritative for zone",
+			qname:       "foo.other.com.",
+			qtype:       dnsmessage.TypeA,
+			expectRCode: dnsmessage.RCodeRefused,
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			response := exchange(t, s, tt.qname, tt.qtype)
+			if response.Header.RCode != tt.expectRCode {
+				t.Errorf("RCode = %s, want %s", response.Header.RCode, tt.expectRCode)
+			}
+			if response.Header.Authoritative != tt.expectAuthoritative {
+				t.Errorf("Authoritative = %v, want %v", response.Header.Authoritative, tt.expectAuthoritative)
+			}
+			if len(response.Answers) != tt.expectAnswers {
+				t.Errorf("Answers = %v, want %d", response.Answers, tt.expectAnswers)
+			}
+			for _, rr := range response.Authorities {
+				if rr.Header.Type != tt.expectAuthorities {
+					t.Errorf("Authorities = %v, want %s records", response.Authorities, tt.expectAuthorities)
+				}
+			}
+			if tt.expectAuthorities != 0 && len(response.Authorities) == 0 {
+				t.Errorf("Authorities empty, want %s records", tt.expectAuthorities)
+			}
+		})
+	}
+}
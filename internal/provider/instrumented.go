@@ -0,0 +1,65 @@
+package provider
+
+import (
+	"context"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/external-dns/endpoint"
+	"sigs.k8s.io/external-dns/plan"
+
+	"github.com/kuadrant/dns-operator/internal/common/hash"
+	"github.com/kuadrant/dns-operator/internal/metrics"
+)
+
+// instrumentedProvider is a Provider that reports the authentication failures and the zone resolution latency of the
+// wrapped Provider, labelled by the provider type and a hash of the provider secret, so a misbehaving credential can be
+// found among many provider secrets.
+type instrumentedProvider struct {
+	Provider
+	name   string
+	secret string
+}
+
+var _ Provider = &instrumentedProvider{}
+
+// SecretHash returns the hash of the namespace and name of the provider secret that its provider metrics are labelled
+// with, the names of secrets are not exposed in the metrics.
+func SecretHash(secret *v1.Secret) string {
+	return hash.ToBase36HashLen(client.ObjectKeyFromObject(secret).String(), 8)
+}
+
+// observeError counts the error if it is an authentication failure, and returns it.
+func observeError(name, secret string, err error) error {
+	if err != nil && ClassifyError(name, err) == ErrorCodeUnauthorized {
+		metrics.ProviderAuthFailures.WithLabelValues(name, secret).Inc()
+	}
+	return err
+}
+
+func (p *instrumentedProvider) Records(ctx context.Context) ([]*endpoint.Endpoint, error) {
+	records, err := p.Provider.Records(ctx)
+	return records, observeError(p.name, p.secret, err)
+}
+
+func (p *instrumentedProvider) ApplyChanges(ctx context.Context, changes *plan.Changes) error {
+	return observeError(p.name, p.secret, p.Provider.ApplyChanges(ctx, changes))
+}
+
+func (p *instrumentedProvider) DNSZones(ctx context.Context) ([]DNSZone, error) {
+	zones, err := p.Provider.DNSZones(ctx)
+	return zones, observeError(p.name, p.secret, err)
+}
+
+func (p *instrumentedProvider) DNSZoneForHost(ctx context.Context, host string) (*DNSZone, error) {
+	start := time.Now()
+	zone, err := p.Provider.DNSZoneForHost(ctx, host)
+	metrics.ZoneResolutionDuration.WithLabelValues(p.name, p.secret).Observe(time.Since(start).Seconds())
+	return zone, observeError(p.name, p.secret, err)
+}
+
+// Unwrap returns the instrumented Provider.
+func (p *instrumentedProvider) Unwrap() Provider {
+	return p.Provider
+}
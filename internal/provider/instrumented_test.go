@@ -0,0 +1,73 @@
+//go:build unit
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/external-dns/plan"
+
+	"github.com/kuadrant/dns-operator/internal/metrics"
+)
+
+var errUnauthorizedTest = errors.New("invalid credentials")
+
+// failingProvider fails every call with its error.
+type failingProvider struct {
+	Provider
+	err error
+}
+
+func (p *failingProvider) ApplyChanges(_ context.Context, _ *plan.Changes) error {
+	return p.err
+}
+
+func (p *failingProvider) DNSZoneForHost(_ context.Context, _ string) (*DNSZone, error) {
+	return nil, p.err
+}
+
+func TestInstrumentedProvider(t *testing.T) {
+	RegisterErrorClassifier("instrumented-test", func(err error) (ErrorCode, bool) {
+		if errors.Is(err, errUnauthorizedTest) {
+			return ErrorCodeUnauthorized, true
+		}
+		return "", false
+	})
+
+	secret := SecretHash(&v1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "credentials", Namespace: "team-a"}})
+	if len(secret) != 8 || secret == SecretHash(&v1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "credentials", Namespace: "team-b"}}) {
+		t.Fatalf("expected a distinct hash for each secret, got %s", secret)
+	}
+
+	failing := &failingProvider{err: errUnauthorizedTest}
+	p := &instrumentedProvider{Provider: failing, name: "instrumented-test", secret: secret}
+	authFailures := metrics.ProviderAuthFailures.WithLabelValues("instrumented-test", secret)
+
+	if err := p.ApplyChanges(context.Background(), &plan.Changes{}); !errors.Is(err, errUnauthorizedTest) {
+		t.Fatalf("expected the error of the provider, got %v", err)
+	}
+	if _, err := p.DNSZoneForHost(context.Background(), "foo.example.com"); err == nil {
+		t.Fatalf("expected the error of the provider")
+	}
+	if count := testutil.ToFloat64(authFailures); count != 2 {
+		t.Errorf("expected 2 auth failures, got %v", count)
+	}
+	if count := testutil.CollectAndCount(metrics.ZoneResolutionDuration, "dns_provider_zone_resolution_duration_seconds"); count != 1 {
+		t.Errorf("expected the zone resolution to be observed, got %d series", count)
+	}
+
+	failing.err = errors.New("service unavailable")
+	_ = p.ApplyChanges(context.Background(), &plan.Changes{})
+	if count := testutil.ToFloat64(authFailures); count != 2 {
+		t.Errorf("expected other errors not to be counted as auth failures, got %v", count)
+	}
+
+	if unwrapped, ok := As[*failingProvider](p); !ok || unwrapped != failing {
+		t.Errorf("expected the instrumented provider to be unwrapped")
+	}
+}
@@ -0,0 +1,77 @@
+//go:build unit
+
+package provider
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kuadrant/dns-operator/api/v1alpha1"
+)
+
+type normalizingProvider struct {
+	Provider
+}
+
+func (*normalizingProvider) NormalizeTarget(_, target string) string {
+	return strings.ToLower(target)
+}
+
+type testAccessor struct {
+	namespace string
+	ref       v1alpha1.ProviderRef
+}
+
+func (a testAccessor) GetNamespace() string {
+	return a.namespace
+}
+
+func (a testAccessor) GetProviderRef() v1alpha1.ProviderRef {
+	return a.ref
+}
+
+func TestFactoryProviderTargetNormalizer(t *testing.T) {
+	constructorsLock.RLock()
+	previous, registered := constructors["aws"]
+	constructorsLock.RUnlock()
+	RegisterProvider("aws", func(_ context.Context, _ *v1.Secret, _ Config) (Provider, error) {
+		return &normalizingProvider{}, nil
+	}, false)
+	t.Cleanup(func() {
+		constructorsLock.Lock()
+		defer constructorsLock.Unlock()
+		delete(constructors, "aws")
+		if registered {
+			constructors["aws"] = previous
+		}
+	})
+
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "aws-credentials", Namespace: "test"},
+		Type:       v1alpha1.SecretTypeKuadrantAWS,
+	}
+	f, err := NewFactory(fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(secret).Build(), []string{"aws"}, WithMaxConcurrentWrites(1))
+	if err != nil {
+		t.Fatalf("unexpected error creating factory: %v", err)
+	}
+	p, err := f.ProviderFor(context.Background(), testAccessor{namespace: "test", ref: v1alpha1.ProviderRef{Name: "aws-credentials"}}, Config{})
+	if err != nil {
+		t.Fatalf("unexpected error creating provider: %v", err)
+	}
+	if _, ok := p.(TargetNormalizer); ok {
+		t.Fatalf("expected the provider to be wrapped")
+	}
+	normalizer, ok := As[TargetNormalizer](p)
+	if !ok {
+		t.Fatalf("expected the wrapped provider to be a TargetNormalizer")
+	}
+	if got := normalizer.NormalizeTarget("CNAME", "LB.Example.com"); got != "lb.example.com" {
+		t.Errorf("NormalizeTarget() = %s, want lb.example.com", got)
+	}
+}
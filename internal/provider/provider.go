@@ -36,6 +36,59 @@ type Provider interface {
 	ProviderSpecific() ProviderSpecificLabels
 }
 
+// TargetNormalizer is implemented by providers that return target values in a different form than they were written.
+// The normalized values are compared when planning changes so that equal records don't generate updates.
+type TargetNormalizer interface {
+	NormalizeTarget(recordType, target string) string
+}
+
+// TargetLimiter is implemented by providers that limit the number of targets of a record.
+type TargetLimiter interface {
+	// MaxTargets returns the maximum number of targets of a record of the given type, 0 if there is no limit
+	MaxTargets(recordType string) int
+}
+
+// ChangeTracker is implemented by providers that report whether the changes they submitted have been propagated to all
+// of their nameservers.
+type ChangeTracker interface {
+	// SubmittedChanges returns the ids of the changes submitted by the provider
+	SubmittedChanges() []string
+	// PendingChanges returns the ids of the given changes that the provider is still propagating
+	PendingChanges(ctx context.Context, ids []string) ([]string, error)
+}
+
+// As returns the given provider as a T, if the provider or a provider it wraps is a T.
+func As[T any](p Provider) (T, bool) {
+	for {
+		if t, ok := p.(T); ok {
+			return t, true
+		}
+		wrapper, ok := p.(interface{ Unwrap() Provider })
+		if !ok {
+			var zero T
+			return zero, false
+		}
+		p = wrapper.Unwrap()
+	}
+}
+
+// Direct returns the given provider without the records cache, the write budget and the write limit of its credential,
+// for records that must be read from the zone and whose writes must not use up the writes of the planned changes.
+func Direct(p Provider) Provider {
+	for {
+		switch wrapper := p.(type) {
+		case *writeLimitedProvider:
+			p = wrapper.Provider
+		case *writeBudgetProvider:
+			p = wrapper.Provider
+		case *recordsCachedProvider:
+			p = wrapper.Provider
+		default:
+			return p
+		}
+	}
+}
+
 type Config struct {
 	// only consider hosted zones managing domains ending in this suffix
 	DomainFilter externaldnsendpoint.DomainFilter
@@ -55,6 +108,8 @@ type DNSZone struct {
 	DNSName     string
 	NameServers []*string
 	RecordCount int64
+	// Private is true if the zone is only resolvable from private networks
+	Private bool
 }
 
 // SanitizeError removes request specific data from error messages in order to make them consistent across multiple similar requests to the provider.  e.g AWS SDK Request ids `request id: 051c860b-9b30-4c19-be1a-1280c3e9fdc4`
@@ -77,6 +132,19 @@ func FindDNSZoneForHost(ctx context.Context, host string, zones []DNSZone) (*DNS
 	return z, err
 }
 
+// zoneNotFoundError is returned when there is no zone that a host could belong to, it matches ErrNoZoneForHost.
+type zoneNotFoundError struct {
+	host string
+}
+
+func (e zoneNotFoundError) Error() string {
+	return fmt.Sprintf("no valid zone found for host: %s", e.host)
+}
+
+func (e zoneNotFoundError) Is(target error) bool {
+	return target == ErrNoZoneForHost
+}
+
 func isApexDomain(host string, zones []DNSZone) (string, bool) {
 	for _, z := range zones {
 		if z.DNSName == host {
@@ -97,7 +165,7 @@ func findDNSZoneForHost(originalHost, host string, zones []DNSZone) (*DNSZone, s
 
 	//The host is a TLD, so we now know `originalHost` can't possibly have a valid `DNSZone` available.
 	if host == tld {
-		return nil, "", fmt.Errorf("no valid zone found for host: %v", originalHost)
+		return nil, "", zoneNotFoundError{host: originalHost}
 	}
 
 	// We do not currently support creating records for Apex domains, and a DNSZone represents an Apex domain we cannot setup dns for the host
@@ -107,7 +175,7 @@ func findDNSZoneForHost(originalHost, host string, zones []DNSZone) (*DNSZone, s
 
 	hostParts := strings.SplitN(host, ".", 2)
 	if len(hostParts) < 2 {
-		return nil, "", fmt.Errorf("no valid zone found for host: %s", originalHost)
+		return nil, "", zoneNotFoundError{host: originalHost}
 	}
 	parentDomain := hostParts[1]
 	// We do not currently support creating records for Apex domains, and a DNSZone represents an Apex domain, as such
@@ -0,0 +1,40 @@
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	externaldnsendpoint "sigs.k8s.io/external-dns/endpoint"
+
+	"github.com/kuadrant/dns-operator/api/v1alpha1"
+)
+
+// ErrRecordTypeNotSupported is returned for endpoints of a record type the provider can't publish.
+var ErrRecordTypeNotSupported = errors.New("record type not supported by the provider")
+
+// optionalRecordTypes are the record types only published by providers that declare support for them with a
+// RecordTypeSupporter.
+var optionalRecordTypes = []string{v1alpha1.RecordTypeNAPTR, v1alpha1.RecordTypeTLSA}
+
+// RecordTypeSupporter is implemented by providers that publish record types not every provider supports, e.g. NAPTR
+// and TLSA records.
+type RecordTypeSupporter interface {
+	// SupportsRecordType returns true if the provider publishes records of the given type
+	SupportsRecordType(recordType string) bool
+}
+
+// ValidateRecordTypes returns an error for the first of the given endpoints with a record type the provider can't
+// publish.
+func ValidateRecordTypes(p Provider, endpoints []*externaldnsendpoint.Endpoint) error {
+	supporter, _ := As[RecordTypeSupporter](p)
+	for _, ep := range endpoints {
+		if !slices.Contains(optionalRecordTypes, ep.RecordType) {
+			continue
+		}
+		if supporter == nil || !supporter.SupportsRecordType(ep.RecordType) {
+			return fmt.Errorf("%w: %s %s", ErrRecordTypeNotSupported, ep.RecordType, ep.DNSName)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+//go:build unit
+
+package provider
+
+import (
+	"errors"
+	"testing"
+
+	externaldnsendpoint "sigs.k8s.io/external-dns/endpoint"
+
+	"github.com/kuadrant/dns-operator/api/v1alpha1"
+)
+
+// naptrProvider is a Provider that only publishes NAPTR records of the optional record types
+type naptrProvider struct {
+	Provider
+}
+
+func (naptrProvider) SupportsRecordType(recordType string) bool {
+	return recordType == v1alpha1.RecordTypeNAPTR
+}
+
+func TestValidateRecordTypes(t *testing.T) {
+	naptr := externaldnsendpoint.NewEndpoint("foo.example.com", v1alpha1.RecordTypeNAPTR, `100 10 "S" "SIP+D2U" "" _sip._udp.example.com`)
+	tlsa := externaldnsendpoint.NewEndpoint("_443._tcp.foo.example.com", v1alpha1.RecordTypeTLSA, "3 1 1 0c72ac70")
+	a := externaldnsendpoint.NewEndpoint("foo.example.com", externaldnsendpoint.RecordTypeA, "1.1.1.1")
+
+	if err := ValidateRecordTypes(nil, []*externaldnsendpoint.Endpoint{a}); err != nil {
+		t.Errorf("expected record types every provider supports to be valid, got %v", err)
+	}
+	if err := ValidateRecordTypes(nil, []*externaldnsendpoint.Endpoint{a, naptr}); !errors.Is(err, ErrRecordTypeNotSupported) {
+		t.Errorf("expected NAPTR records to be rejected by providers without support, got %v", err)
+	}
+
+	// the support of wrapped providers is found
+	p := &writeBudgetProvider{Provider: naptrProvider{}}
+	if err := ValidateRecordTypes(p, []*externaldnsendpoint.Endpoint{a, naptr}); err != nil {
+		t.Errorf("expected NAPTR records to be valid, got %v", err)
+	}
+	err := ValidateRecordTypes(p, []*externaldnsendpoint.Endpoint{naptr, tlsa})
+	if !errors.Is(err, ErrRecordTypeNotSupported) {
+		t.Fatalf("expected TLSA records to be rejected, got %v", err)
+	}
+	if want := "record type not supported by the provider: TLSA _443._tcp.foo.example.com"; err.Error() != want {
+		t.Errorf("expected error %q, got %q", want, err.Error())
+	}
+}
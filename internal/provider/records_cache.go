@@ -0,0 +1,156 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"sigs.k8s.io/external-dns/endpoint"
+	"sigs.k8s.io/external-dns/plan"
+)
+
+// recordsCache keeps the records read from provider zones for a short time, so records in the same zone that are
+// reconciled within seconds of each other share a single read of the zone. Entries are keyed by the provider
+// credential and the zones the provider is filtered to, and are dropped when the credential changes or changes are
+// applied to the zones. Expired entries are evicted when entries are looked up or invalidated, so zones that are no
+// longer read don't keep their records in memory.
+type recordsCache struct {
+	ttl     time.Duration
+	lock    sync.Mutex
+	entries map[recordsCacheKey]*recordsCacheEntry
+	now     func() time.Time
+}
+
+// recordsCacheKey identifies the records read by a provider.
+type recordsCacheKey struct {
+	// credential is the namespace/name of the provider secret
+	credential string
+	// zones are the ids of the zones the provider is filtered to
+	zones string
+	// filters are the remaining filters of the provider config
+	filters string
+}
+
+// recordsCacheEntry is the records read by a provider, the lock is held while the records are read so concurrent reads
+// of the same zones wait for the first.
+type recordsCacheEntry struct {
+	lock            sync.Mutex
+	resourceVersion string
+	expires         time.Time
+	records         []*endpoint.Endpoint
+}
+
+func newRecordsCache(ttl time.Duration) *recordsCache {
+	return &recordsCache{
+		ttl:     ttl,
+		entries: map[recordsCacheKey]*recordsCacheEntry{},
+		now:     time.Now,
+	}
+}
+
+// wrap returns the given Provider with Records served from the cache. Only providers filtered to zone ids are cached,
+// as the records of a provider of all zones of a credential are only read to find zones. If no ttl is configured the
+// Provider is returned unchanged.
+func (c *recordsCache) wrap(credential, resourceVersion string, config Config, p Provider) Provider {
+	if c == nil || c.ttl <= 0 || !config.ZoneIDFilter.IsConfigured() {
+		return p
+	}
+	return &recordsCachedProvider{
+		Provider: p,
+		cache:    c,
+		key: recordsCacheKey{
+			credential: credential,
+			zones:      strings.Join(config.ZoneIDFilter.ZoneIDs, ","),
+			filters:    fmt.Sprintf("%v/%v", config.DomainFilter.Filters, config.ZoneTypeFilter),
+		},
+		resourceVersion: resourceVersion,
+	}
+}
+
+// entry returns the entry for the given key, creating it if required.
+func (c *recordsCache) entry(key recordsCacheKey) *recordsCacheEntry {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.evictExpired()
+	e, ok := c.entries[key]
+	if !ok {
+		e = &recordsCacheEntry{}
+		c.entries[key] = e
+	}
+	return e
+}
+
+// invalidate drops the entries of the given credential and zones.
+func (c *recordsCache) invalidate(key recordsCacheKey) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	for k := range c.entries {
+		if k.credential == key.credential && k.zones == key.zones {
+			delete(c.entries, k)
+		}
+	}
+	c.evictExpired()
+}
+
+// evictExpired drops the expired entries, the lock of the cache must be held. Entries that are being read are kept.
+func (c *recordsCache) evictExpired() {
+	now := c.now()
+	for k, e := range c.entries {
+		if !e.lock.TryLock() {
+			continue
+		}
+		if !e.expires.IsZero() && now.After(e.expires) {
+			delete(c.entries, k)
+		}
+		e.lock.Unlock()
+	}
+}
+
+// recordsCachedProvider is a Provider that reads records through a shared cache and invalidates it when changes are
+// applied.
+type recordsCachedProvider struct {
+	Provider
+	cache           *recordsCache
+	key             recordsCacheKey
+	resourceVersion string
+}
+
+var _ Provider = &recordsCachedProvider{}
+
+// Records returns the cached records of the zones of the provider, reading them from the provider if they are not
+// cached, expired or were read with a previous version of the credential. Callers get their own copy of the records.
+func (p *recordsCachedProvider) Records(ctx context.Context) ([]*endpoint.Endpoint, error) {
+	e := p.cache.entry(p.key)
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	if e.expires.IsZero() || e.resourceVersion != p.resourceVersion || p.cache.now().After(e.expires) {
+		records, err := p.Provider.Records(ctx)
+		if err != nil {
+			return nil, err
+		}
+		e.records = records
+		e.resourceVersion = p.resourceVersion
+		e.expires = p.cache.now().Add(p.cache.ttl)
+	}
+
+	records := make([]*endpoint.Endpoint, 0, len(e.records))
+	for _, record := range e.records {
+		records = append(records, record.DeepCopy())
+	}
+	return records, nil
+}
+
+// ApplyChanges applies the changes and invalidates the cached records of the zones of the provider, including when the
+// changes fail as they may have been partially applied.
+func (p *recordsCachedProvider) ApplyChanges(ctx context.Context, changes *plan.Changes) error {
+	defer p.cache.invalidate(p.key)
+	return p.Provider.ApplyChanges(ctx, changes)
+}
+
+// Unwrap returns the cached Provider.
+func (p *recordsCachedProvider) Unwrap() Provider {
+	return p.Provider
+}
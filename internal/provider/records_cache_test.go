@@ -0,0 +1,123 @@
+//go:build unit
+
+package provider
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"sigs.k8s.io/external-dns/endpoint"
+	"sigs.k8s.io/external-dns/plan"
+	externaldnsprovider "sigs.k8s.io/external-dns/provider"
+)
+
+type countingProvider struct {
+	Provider
+	reads  int
+	writes int
+}
+
+func (p *countingProvider) Records(_ context.Context) ([]*endpoint.Endpoint, error) {
+	p.reads++
+	return []*endpoint.Endpoint{endpoint.NewEndpoint("foo.example.com", endpoint.RecordTypeA, "1.1.1.1")}, nil
+}
+
+func (p *countingProvider) ApplyChanges(_ context.Context, _ *plan.Changes) error {
+	p.writes++
+	return nil
+}
+
+func TestRecordsCache(t *testing.T) {
+	zoneConfig := Config{ZoneIDFilter: externaldnsprovider.NewZoneIDFilter([]string{"Z1"})}
+	cache := newRecordsCache(time.Minute)
+	inner := &countingProvider{}
+
+	read := func(p Provider) []*endpoint.Endpoint {
+		t.Helper()
+		records, err := p.Records(context.Background())
+		if err != nil {
+			t.Fatalf("Records() unexpected error %v", err)
+		}
+		return records
+	}
+
+	records := read(cache.wrap("ns/secret", "1", zoneConfig, inner))
+	records[0].Targets = endpoint.Targets{"2.2.2.2"}
+	records = read(cache.wrap("ns/secret", "1", zoneConfig, inner))
+	if inner.reads != 1 {
+		t.Errorf("reads = %d, want 1 for providers of the same zone", inner.reads)
+	}
+	if records[0].Targets[0] != "1.1.1.1" {
+		t.Errorf("Records() = %v, want a copy unaffected by changes of other callers", records[0].Targets)
+	}
+
+	p := cache.wrap("ns/secret", "1", zoneConfig, inner)
+	if err := p.ApplyChanges(context.Background(), &plan.Changes{}); err != nil {
+		t.Fatalf("ApplyChanges() unexpected error %v", err)
+	}
+	read(p)
+	if inner.reads != 2 {
+		t.Errorf("reads = %d, want 2 after changes are applied", inner.reads)
+	}
+
+	read(cache.wrap("ns/secret", "2", zoneConfig, inner))
+	if inner.reads != 3 {
+		t.Errorf("reads = %d, want 3 after the credential changed", inner.reads)
+	}
+
+	read(cache.wrap("ns/secret", "2", Config{ZoneIDFilter: externaldnsprovider.NewZoneIDFilter([]string{"Z2"})}, inner))
+	if inner.reads != 4 {
+		t.Errorf("reads = %d, want 4 for another zone", inner.reads)
+	}
+
+	if p := cache.wrap("ns/secret", "2", Config{}, inner); p != inner {
+		t.Errorf("wrap() = %T, want providers not filtered to zones uncached", p)
+	}
+	if p := (*recordsCache)(nil).wrap("ns/secret", "2", zoneConfig, inner); p != inner {
+		t.Errorf("wrap() = %T, want providers uncached without a cache", p)
+	}
+}
+
+func TestRecordsCacheEviction(t *testing.T) {
+	now := time.Now()
+	cache := newRecordsCache(time.Minute)
+	cache.now = func() time.Time { return now }
+	inner := &countingProvider{}
+	zone := func(id string) Provider {
+		return cache.wrap("ns/secret", "1", Config{ZoneIDFilter: externaldnsprovider.NewZoneIDFilter([]string{id})}, inner)
+	}
+
+	for _, id := range []string{"Z1", "Z2"} {
+		if _, err := zone(id).Records(context.Background()); err != nil {
+			t.Fatalf("Records() unexpected error %v", err)
+		}
+	}
+	if len(cache.entries) != 2 {
+		t.Fatalf("entries = %d, want 2", len(cache.entries))
+	}
+
+	now = now.Add(30 * time.Second)
+	if _, err := zone("Z1").Records(context.Background()); err != nil {
+		t.Fatalf("Records() unexpected error %v", err)
+	}
+	if len(cache.entries) != 2 {
+		t.Errorf("entries = %d, want 2 before the entries expire", len(cache.entries))
+	}
+
+	now = now.Add(45 * time.Second)
+	if _, err := zone("Z1").Records(context.Background()); err != nil {
+		t.Fatalf("Records() unexpected error %v", err)
+	}
+	if _, ok := cache.entries[zone("Z2").(*recordsCachedProvider).key]; ok {
+		t.Errorf("expected the expired entry of Z2 to be evicted on read")
+	}
+
+	now = now.Add(2 * time.Minute)
+	if err := zone("Z3").ApplyChanges(context.Background(), &plan.Changes{}); err != nil {
+		t.Fatalf("ApplyChanges() unexpected error %v", err)
+	}
+	if len(cache.entries) != 0 {
+		t.Errorf("entries = %d, want expired entries evicted on invalidate", len(cache.entries))
+	}
+}
@@ -0,0 +1,113 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// zonesCache keeps the zones listed with a provider credential for a time, so finding the zone of each record doesn't
+// list the zones of the credential again. Entries are keyed by the provider credential and the filters of the provider
+// config, and are dropped when the credential changes.
+type zonesCache struct {
+	ttl     time.Duration
+	lock    sync.Mutex
+	entries map[zonesCacheKey]*zonesCacheEntry
+}
+
+// zonesCacheKey identifies the zones listed by a provider.
+type zonesCacheKey struct {
+	// credential is the namespace/name of the provider secret
+	credential string
+	// filters are the filters of the provider config
+	filters string
+}
+
+// zonesCacheEntry is the zones listed by a provider, the lock is held while the zones are listed so concurrent lists
+// with the same credential wait for the first.
+type zonesCacheEntry struct {
+	lock            sync.Mutex
+	resourceVersion string
+	expires         time.Time
+	zones           []DNSZone
+}
+
+func newZonesCache(ttl time.Duration) *zonesCache {
+	return &zonesCache{
+		ttl:     ttl,
+		entries: map[zonesCacheKey]*zonesCacheEntry{},
+	}
+}
+
+// wrap returns the given Provider with DNSZones and DNSZoneForHost served from the cache. If no ttl is configured the
+// Provider is returned unchanged.
+func (c *zonesCache) wrap(credential, resourceVersion string, config Config, p Provider) Provider {
+	if c == nil || c.ttl <= 0 {
+		return p
+	}
+	return &zonesCachedProvider{
+		Provider: p,
+		cache:    c,
+		key: zonesCacheKey{
+			credential: credential,
+			filters:    fmt.Sprintf("%v/%v/%v", config.DomainFilter.Filters, config.ZoneIDFilter.ZoneIDs, config.ZoneTypeFilter),
+		},
+		resourceVersion: resourceVersion,
+	}
+}
+
+// entry returns the entry for the given key, creating it if required.
+func (c *zonesCache) entry(key zonesCacheKey) *zonesCacheEntry {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	e, ok := c.entries[key]
+	if !ok {
+		e = &zonesCacheEntry{}
+		c.entries[key] = e
+	}
+	return e
+}
+
+// zonesCachedProvider is a Provider that lists zones through a shared cache.
+type zonesCachedProvider struct {
+	Provider
+	cache           *zonesCache
+	key             zonesCacheKey
+	resourceVersion string
+}
+
+var _ Provider = &zonesCachedProvider{}
+
+// DNSZones returns the cached zones of the credential, listing them with the provider if they are not cached, expired
+// or were listed with a previous version of the credential. Failures to list zones are not cached.
+func (p *zonesCachedProvider) DNSZones(ctx context.Context) ([]DNSZone, error) {
+	e := p.cache.entry(p.key)
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	if e.expires.IsZero() || e.resourceVersion != p.resourceVersion || time.Now().After(e.expires) {
+		zones, err := p.Provider.DNSZones(ctx)
+		if err != nil {
+			return nil, err
+		}
+		e.zones = zones
+		e.resourceVersion = p.resourceVersion
+		e.expires = time.Now().Add(p.cache.ttl)
+	}
+	return append([]DNSZone(nil), e.zones...), nil
+}
+
+// DNSZoneForHost returns the zone of the host from the cached zones of the credential.
+func (p *zonesCachedProvider) DNSZoneForHost(ctx context.Context, host string) (*DNSZone, error) {
+	zones, err := p.DNSZones(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return FindDNSZoneForHost(ctx, host, zones)
+}
+
+// Unwrap returns the cached Provider.
+func (p *zonesCachedProvider) Unwrap() Provider {
+	return p.Provider
+}
@@ -0,0 +1,77 @@
+//go:build unit
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"sigs.k8s.io/external-dns/endpoint"
+)
+
+type zonesCountingProvider struct {
+	Provider
+	lists int
+	err   error
+}
+
+func (p *zonesCountingProvider) DNSZones(_ context.Context) ([]DNSZone, error) {
+	p.lists++
+	if p.err != nil {
+		return nil, p.err
+	}
+	return []DNSZone{{ID: "Z1", DNSName: "example.com"}, {ID: "Z2", DNSName: "sub.example.com"}}, nil
+}
+
+func TestZonesCache(t *testing.T) {
+	cache := newZonesCache(time.Minute)
+	inner := &zonesCountingProvider{}
+
+	zones, err := cache.wrap("ns/secret", "1", Config{}, inner).DNSZones(context.Background())
+	if err != nil {
+		t.Fatalf("DNSZones() unexpected error %v", err)
+	}
+	zones[0].ID = "changed"
+
+	zone, err := cache.wrap("ns/secret", "1", Config{}, inner).DNSZoneForHost(context.Background(), "www.example.com")
+	if err != nil {
+		t.Fatalf("DNSZoneForHost() unexpected error %v", err)
+	}
+	if zone.ID != "Z1" {
+		t.Errorf("DNSZoneForHost() = %s, want Z1 unaffected by changes of other callers", zone.ID)
+	}
+	if inner.lists != 1 {
+		t.Errorf("lists = %d, want 1 for providers of the same credential", inner.lists)
+	}
+
+	if _, err := cache.wrap("ns/secret", "2", Config{}, inner).DNSZones(context.Background()); err != nil {
+		t.Fatalf("DNSZones() unexpected error %v", err)
+	}
+	if inner.lists != 2 {
+		t.Errorf("lists = %d, want 2 after the credential changed", inner.lists)
+	}
+
+	filtered := Config{DomainFilter: endpoint.NewDomainFilter([]string{"sub.example.com"})}
+	if _, err := cache.wrap("ns/secret", "2", filtered, inner).DNSZones(context.Background()); err != nil {
+		t.Fatalf("DNSZones() unexpected error %v", err)
+	}
+	if inner.lists != 3 {
+		t.Errorf("lists = %d, want 3 for other filters", inner.lists)
+	}
+
+	failing := &zonesCountingProvider{err: errors.New("forbidden")}
+	for range 2 {
+		if _, err := cache.wrap("ns/other", "1", Config{}, failing).DNSZones(context.Background()); err == nil {
+			t.Fatal("DNSZones() expected error")
+		}
+	}
+	if failing.lists != 2 {
+		t.Errorf("lists = %d, want 2 as failures are not cached", failing.lists)
+	}
+
+	if p := (*zonesCache)(nil).wrap("ns/secret", "2", Config{}, inner); p != inner {
+		t.Errorf("wrap() = %T, want providers uncached without a cache", p)
+	}
+}
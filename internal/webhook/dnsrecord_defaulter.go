@@ -0,0 +1,122 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	externaldnsendpoint "sigs.k8s.io/external-dns/endpoint"
+
+	"github.com/kuadrant/dns-operator/api/v1alpha1"
+)
+
+//+kubebuilder:webhook:path=/mutate-kuadrant-io-v1alpha1-dnsrecord,mutating=true,failurePolicy=ignore,sideEffects=None,groups=kuadrant.io,resources=dnsrecords,verbs=create;update,versions=v1alpha1,name=mdnsrecord.kuadrant.io,admissionReviewVersions=v1
+
+// DNSRecordDefaulter merges the defaults declared with annotations on the namespace of a DNSRecord into the record, so
+// the records of a namespace don't all have to repeat the same TTL, provider secret and health check. Values set on the
+// record are never changed.
+// With CanonicalizeEndpoints the endpoints of the record are also written in a canonical order and the hash of its spec
+// is set in the SpecHashAnnotation, so specs that only differ in the order of their endpoints or targets don't bump the
+// generation of the record.
+type DNSRecordDefaulter struct {
+	// Client reads the namespace defaults, they are not merged into records if nil.
+	Client client.Client
+	// CanonicalizeEndpoints canonicalizes the spec of records and maintains their SpecHashAnnotation.
+	CanonicalizeEndpoints bool
+}
+
+var _ webhook.CustomDefaulter = &DNSRecordDefaulter{}
+
+// SetupWebhookWithManager registers the defaulting webhook with the manager.
+func (d *DNSRecordDefaulter) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&v1alpha1.DNSRecord{}).
+		WithDefaulter(d).
+		Complete()
+}
+
+// Default merges the defaults of the namespace of the record into it, then canonicalizes its spec. Defaults are best
+// effort, records are never rejected because the namespace could not be read or declares invalid defaults.
+func (d *DNSRecordDefaulter) Default(ctx context.Context, obj runtime.Object) error {
+	record, ok := obj.(*v1alpha1.DNSRecord)
+	if !ok {
+		return nil
+	}
+	logger := log.FromContext(ctx)
+
+	if d.Client != nil {
+		namespace := &v1.Namespace{}
+		if err := d.Client.Get(ctx, client.ObjectKey{Name: record.Namespace}, namespace); err != nil {
+			logger.Error(err, "unable to get namespace defaults", "namespace", record.Namespace)
+		} else if err = applyNamespaceDefaults(record, namespace.Annotations); err != nil {
+			logger.Error(err, "invalid namespace defaults", "namespace", record.Namespace)
+		}
+	}
+
+	if d.CanonicalizeEndpoints {
+		if err := canonicalize(record); err != nil {
+			logger.Error(err, "unable to hash spec", "record", record.Name)
+		}
+	}
+	return nil
+}
+
+// canonicalize writes the spec of the record in its canonical form and sets its SpecHashAnnotation to the hash of the
+// spec.
+func canonicalize(record *v1alpha1.DNSRecord) error {
+	record.Spec.Canonicalize()
+	specHash, err := record.Spec.Hash()
+	if err != nil {
+		return err
+	}
+	if record.Annotations == nil {
+		record.Annotations = map[string]string{}
+	}
+	record.Annotations[v1alpha1.SpecHashAnnotation] = specHash
+	return nil
+}
+
+// applyNamespaceDefaults sets the fields of the record that are not set to the defaults in the given namespace
+// annotations. Invalid defaults are skipped and returned as an error, the valid ones are still applied.
+func applyNamespaceDefaults(record *v1alpha1.DNSRecord, annotations map[string]string) error {
+	var errs []error
+
+	if value, ok := annotations[v1alpha1.DefaultTTLAnnotation]; ok {
+		ttl, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || ttl <= 0 {
+			errs = append(errs, fmt.Errorf("%s must be a positive number of seconds, got %q", v1alpha1.DefaultTTLAnnotation, value))
+		} else {
+			for _, ep := range record.Spec.Endpoints {
+				if !ep.RecordTTL.IsConfigured() {
+					ep.RecordTTL = externaldnsendpoint.TTL(ttl)
+				}
+			}
+		}
+	}
+
+	if value, ok := annotations[v1alpha1.DefaultProviderRefAnnotation]; ok && record.Spec.ProviderRef.Name == "" {
+		record.Spec.ProviderRef.Name = value
+	}
+
+	if value, ok := annotations[v1alpha1.DefaultHealthCheckAnnotation]; ok && record.Spec.HealthCheck == nil {
+		healthCheck := &v1alpha1.HealthCheckSpec{}
+		decoder := json.NewDecoder(bytes.NewReader([]byte(value)))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(healthCheck); err != nil {
+			errs = append(errs, fmt.Errorf("%s must be a health check spec in JSON: %w", v1alpha1.DefaultHealthCheckAnnotation, err))
+		} else {
+			record.Spec.HealthCheck = healthCheck
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,131 @@
+//go:build unit
+
+package webhook
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	externaldnsendpoint "sigs.k8s.io/external-dns/endpoint"
+
+	"github.com/kuadrant/dns-operator/api/v1alpha1"
+)
+
+func TestDefault(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = v1alpha1.AddToScheme(scheme)
+
+	namespace := &v1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "team-a",
+			Annotations: map[string]string{
+				v1alpha1.DefaultTTLAnnotation:         "300",
+				v1alpha1.DefaultProviderRefAnnotation: "aws-credentials",
+				v1alpha1.DefaultHealthCheckAnnotation: `{"path":"/healthz","failureThreshold":3}`,
+			},
+		},
+	}
+	defaulter := &DNSRecordDefaulter{
+		Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(namespace).Build(),
+	}
+
+	record := testRecord("team-a", "foo", "foo.example.com", "")
+	record.Spec.Endpoints = []*externaldnsendpoint.Endpoint{
+		externaldnsendpoint.NewEndpoint("foo.example.com", externaldnsendpoint.RecordTypeA, "1.1.1.1"),
+		externaldnsendpoint.NewEndpointWithTTL("www.foo.example.com", externaldnsendpoint.RecordTypeA, 60, "1.1.1.1"),
+	}
+	if err := defaulter.Default(context.Background(), record); err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	if record.Spec.Endpoints[0].RecordTTL != 300 || record.Spec.Endpoints[1].RecordTTL != 60 {
+		t.Errorf("expected the default TTL for endpoints without one, got %d and %d",
+			record.Spec.Endpoints[0].RecordTTL, record.Spec.Endpoints[1].RecordTTL)
+	}
+	if record.Spec.ProviderRef.Name != "aws-credentials" {
+		t.Errorf("expected the default provider ref, got %q", record.Spec.ProviderRef.Name)
+	}
+	if record.Spec.HealthCheck == nil || record.Spec.HealthCheck.Path != "/healthz" || record.Spec.HealthCheck.FailureThreshold != 3 {
+		t.Errorf("expected the default health check, got %+v", record.Spec.HealthCheck)
+	}
+
+	record = testRecord("team-a", "bar", "bar.example.com", "gcp-credentials")
+	record.Spec.HealthCheck = &v1alpha1.HealthCheckSpec{Path: "/ready"}
+	if err := defaulter.Default(context.Background(), record); err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	if record.Spec.ProviderRef.Name != "gcp-credentials" || record.Spec.HealthCheck.Path != "/ready" {
+		t.Errorf("expected the values of the record to be kept, got %+v", record.Spec)
+	}
+
+	record = testRecord("team-b", "foo", "foo.example.com", "")
+	if err := defaulter.Default(context.Background(), record); err != nil {
+		t.Fatalf("expected a missing namespace not to reject the record, got %s", err)
+	}
+}
+
+func TestApplyNamespaceDefaultsInvalid(t *testing.T) {
+	record := testRecord("team-a", "foo", "foo.example.com", "")
+	record.Spec.Endpoints = []*externaldnsendpoint.Endpoint{
+		externaldnsendpoint.NewEndpoint("foo.example.com", externaldnsendpoint.RecordTypeA, "1.1.1.1"),
+	}
+	err := applyNamespaceDefaults(record, map[string]string{
+		v1alpha1.DefaultTTLAnnotation:         "5m",
+		v1alpha1.DefaultProviderRefAnnotation: "aws-credentials",
+		v1alpha1.DefaultHealthCheckAnnotation: `{"endpoint":"/healthz"}`,
+	})
+	if err == nil {
+		t.Fatalf("expected an error for the invalid defaults")
+	}
+	if record.Spec.Endpoints[0].RecordTTL.IsConfigured() || record.Spec.HealthCheck != nil {
+		t.Errorf("expected invalid defaults to be skipped, got %+v", record.Spec)
+	}
+	if record.Spec.ProviderRef.Name != "aws-credentials" {
+		t.Errorf("expected valid defaults to be applied, got %q", record.Spec.ProviderRef.Name)
+	}
+}
+
+func TestDefaultCanonicalizeEndpoints(t *testing.T) {
+	defaulter := &DNSRecordDefaulter{CanonicalizeEndpoints: true}
+
+	first := testRecord("team-a", "foo", "foo.example.com", "")
+	first.Spec.Endpoints = []*externaldnsendpoint.Endpoint{
+		externaldnsendpoint.NewEndpoint("www.foo.example.com", externaldnsendpoint.RecordTypeA, "2.2.2.2", "1.1.1.1"),
+		externaldnsendpoint.NewEndpoint("foo.example.com", externaldnsendpoint.RecordTypeA, "1.1.1.1"),
+	}
+	second := testRecord("team-a", "foo", "foo.example.com", "")
+	second.Spec.Endpoints = []*externaldnsendpoint.Endpoint{
+		externaldnsendpoint.NewEndpoint("foo.example.com", externaldnsendpoint.RecordTypeA, "1.1.1.1"),
+		externaldnsendpoint.NewEndpoint("www.foo.example.com", externaldnsendpoint.RecordTypeA, "1.1.1.1", "2.2.2.2"),
+	}
+	for _, record := range []*v1alpha1.DNSRecord{first, second} {
+		if err := defaulter.Default(context.Background(), record); err != nil {
+			t.Fatalf("unexpected error %s", err)
+		}
+	}
+
+	if !reflect.DeepEqual(first.Spec, second.Spec) {
+		t.Errorf("expected the specs to be identical, got %+v and %+v", first.Spec.Endpoints, second.Spec.Endpoints)
+	}
+	if first.Spec.Endpoints[0].DNSName != "foo.example.com" {
+		t.Errorf("expected the endpoints to be sorted by name, got %s first", first.Spec.Endpoints[0].DNSName)
+	}
+	specHash := first.Annotations[v1alpha1.SpecHashAnnotation]
+	if specHash == "" || specHash != second.Annotations[v1alpha1.SpecHashAnnotation] {
+		t.Errorf("expected the same spec hash, got %q and %q", specHash, second.Annotations[v1alpha1.SpecHashAnnotation])
+	}
+
+	second.Spec.Endpoints[1].Targets = externaldnsendpoint.Targets{"3.3.3.3"}
+	if err := defaulter.Default(context.Background(), second); err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	if second.Annotations[v1alpha1.SpecHashAnnotation] == specHash {
+		t.Errorf("expected the spec hash to change with the targets")
+	}
+}
@@ -0,0 +1,227 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/kuadrant/dns-operator/api/v1alpha1"
+)
+
+// DuplicateRootHostPolicy is the action taken when a new DNSRecord claims a root host already claimed by a DNSRecord in
+// another namespace using the same provider account.
+type DuplicateRootHostPolicy string
+
+const (
+	DuplicateRootHostPolicyNone   DuplicateRootHostPolicy = ""
+	DuplicateRootHostPolicyWarn   DuplicateRootHostPolicy = "warn"
+	DuplicateRootHostPolicyReject DuplicateRootHostPolicy = "reject"
+
+	// RootHostIndexKey is the field index used to find DNSRecords by root host
+	RootHostIndexKey = "spec.rootHost"
+)
+
+// ParseDuplicateRootHostPolicy returns the DuplicateRootHostPolicy for the given value, or an error if it is unknown.
+func ParseDuplicateRootHostPolicy(value string) (DuplicateRootHostPolicy, error) {
+	switch policy := DuplicateRootHostPolicy(value); policy {
+	case DuplicateRootHostPolicyNone, DuplicateRootHostPolicyWarn, DuplicateRootHostPolicyReject:
+		return policy, nil
+	}
+	return DuplicateRootHostPolicyNone, fmt.Errorf("unknown duplicate root host policy %q, must be one of %q or %q",
+		value, DuplicateRootHostPolicyWarn, DuplicateRootHostPolicyReject)
+}
+
+//+kubebuilder:webhook:path=/validate-kuadrant-io-v1alpha1-dnsrecord,mutating=false,failurePolicy=ignore,sideEffects=None,groups=kuadrant.io,resources=dnsrecords,verbs=create;update,versions=v1alpha1,name=vdnsrecord.kuadrant.io,admissionReviewVersions=v1
+
+// DNSRecordValidator checks new DNSRecords do not claim a root host that is already claimed by a DNSRecord in another
+// namespace using the same provider account, so that teams can't accidentally overwrite each others records.
+// Provider accounts are considered the same when the referenced provider secrets have the same type and data.
+// When EnforceQuota is set, DNSRecords that would take their namespace over the limits of its DNSQuotas are rejected.
+// When ValidateTargets is set, DNSRecords with endpoint targets that are not valid for their record type are rejected.
+type DNSRecordValidator struct {
+	Client          client.Client
+	Policy          DuplicateRootHostPolicy
+	EnforceQuota    bool
+	ValidateTargets bool
+}
+
+var _ webhook.CustomValidator = &DNSRecordValidator{}
+
+// SetupWebhookWithManager registers the root host index and the validating webhook with the manager.
+func (v *DNSRecordValidator) SetupWebhookWithManager(ctx context.Context, mgr ctrl.Manager) error {
+	if err := mgr.GetFieldIndexer().IndexField(ctx, &v1alpha1.DNSRecord{}, RootHostIndexKey, rootHostIndexer); err != nil {
+		return err
+	}
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&v1alpha1.DNSRecord{}).
+		WithValidator(v).
+		Complete()
+}
+
+func rootHostIndexer(o client.Object) []string {
+	record, ok := o.(*v1alpha1.DNSRecord)
+	if !ok || record.Spec.RootHost == "" {
+		return nil
+	}
+	return []string{record.Spec.RootHost}
+}
+
+// ValidateCreate rejects the record when it would exceed the quota of its namespace, and warns or rejects, depending on
+// the policy, when the root host is already claimed in another namespace.
+func (v *DNSRecordValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	record, ok := obj.(*v1alpha1.DNSRecord)
+	if !ok {
+		return nil, nil
+	}
+	if err := v.checkTargets(record); err != nil {
+		return nil, err
+	}
+	if err := v.checkQuota(ctx, record, nil); err != nil {
+		return nil, err
+	}
+	if v.Policy == DuplicateRootHostPolicyNone {
+		return nil, nil
+	}
+
+	duplicate, err := v.findDuplicate(ctx, record)
+	if err != nil || duplicate == nil {
+		// the check is best effort, records are never rejected because the check could not be completed
+		if err != nil {
+			log.FromContext(ctx).Error(err, "unable to check for duplicate root host", "rootHost", record.Spec.RootHost)
+		}
+		return nil, nil
+	}
+
+	msg := fmt.Sprintf("rootHost %s is already managed by DNSRecord %s/%s using the same provider account",
+		record.Spec.RootHost, duplicate.Namespace, duplicate.Name)
+	if v.Policy == DuplicateRootHostPolicyReject {
+		return nil, fmt.Errorf("%s", msg)
+	}
+	return admission.Warnings{msg}, nil
+}
+
+// ValidateUpdate rejects the record when its endpoints changed to have invalid targets or endpoints added to it would
+// exceed the quota of its namespace. Records being deleted are never rejected, so their finalizer can be removed. The
+// root host of a DNSRecord is immutable.
+func (v *DNSRecordValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	previous, ok := oldObj.(*v1alpha1.DNSRecord)
+	if !ok {
+		return nil, nil
+	}
+	record, ok := newObj.(*v1alpha1.DNSRecord)
+	if !ok || record.DeletionTimestamp != nil {
+		return nil, nil
+	}
+	// records with invalid targets admitted before targets were validated can still be updated, until their endpoints
+	// are changed
+	if !reflect.DeepEqual(previous.Spec.Endpoints, record.Spec.Endpoints) {
+		if err := v.checkTargets(record); err != nil {
+			return nil, err
+		}
+	}
+	return nil, v.checkQuota(ctx, record, previous)
+}
+
+// ValidateDelete does nothing.
+func (v *DNSRecordValidator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// findDuplicate returns a DNSRecord in a different namespace with the same root host and provider account as the given
+// record, or nil if there is none.
+func (v *DNSRecordValidator) findDuplicate(ctx context.Context, record *v1alpha1.DNSRecord) (*v1alpha1.DNSRecord, error) {
+	records := &v1alpha1.DNSRecordList{}
+	if err := v.Client.List(ctx, records, client.MatchingFields{RootHostIndexKey: record.Spec.RootHost}); err != nil {
+		return nil, err
+	}
+
+	var secret *v1.Secret
+	for i := range records.Items {
+		existing := &records.Items[i]
+		if existing.Namespace == record.Namespace || existing.DeletionTimestamp != nil {
+			continue
+		}
+		if secret == nil {
+			secret = &v1.Secret{}
+			if err := v.Client.Get(ctx, client.ObjectKey{Namespace: record.Namespace, Name: record.Spec.ProviderRef.Name}, secret); err != nil {
+				return nil, client.IgnoreNotFound(err)
+			}
+		}
+		existingSecret := &v1.Secret{}
+		if err := v.Client.Get(ctx, client.ObjectKey{Namespace: existing.Namespace, Name: existing.Spec.ProviderRef.Name}, existingSecret); err != nil {
+			if client.IgnoreNotFound(err) != nil {
+				return nil, err
+			}
+			continue
+		}
+		if sameProviderAccount(secret, existingSecret) {
+			return existing, nil
+		}
+	}
+	return nil, nil
+}
+
+// checkTargets returns an error if an endpoint target of the record is not valid for its record type. Partially
+// published records are not checked, their invalid endpoints are left out of publishing instead.
+func (v *DNSRecordValidator) checkTargets(record *v1alpha1.DNSRecord) error {
+	if !v.ValidateTargets || record.IsPartialPublish() {
+		return nil
+	}
+	return record.ValidateTargets()
+}
+
+// checkQuota returns an error if the given record would take its namespace over the limits of its DNSQuotas. An update
+// is only checked when it adds endpoints, so a namespace over its quota can always be brought back within it.
+func (v *DNSRecordValidator) checkQuota(ctx context.Context, record, previous *v1alpha1.DNSRecord) error {
+	if !v.EnforceQuota || (previous != nil && len(record.Spec.Endpoints) <= len(previous.Spec.Endpoints)) {
+		return nil
+	}
+
+	quotas := &v1alpha1.DNSQuotaList{}
+	if err := v.Client.List(ctx, quotas, client.InNamespace(record.Namespace)); err != nil {
+		// the check is best effort, records are never rejected because the check could not be completed
+		log.FromContext(ctx).Error(err, "unable to list DNS quotas", "namespace", record.Namespace)
+		return nil
+	}
+	maxRecords, maxEndpoints := quotas.Limits()
+	if maxRecords == nil && maxEndpoints == nil {
+		return nil
+	}
+
+	records := &v1alpha1.DNSRecordList{}
+	if err := v.Client.List(ctx, records, client.InNamespace(record.Namespace)); err != nil {
+		log.FromContext(ctx).Error(err, "unable to list DNS records", "namespace", record.Namespace)
+		return nil
+	}
+	recordCount, endpointCount := 1, len(record.Spec.Endpoints)
+	for i := range records.Items {
+		existing := &records.Items[i]
+		if existing.Name == record.Name || existing.DeletionTimestamp != nil {
+			continue
+		}
+		recordCount++
+		endpointCount += len(existing.Spec.Endpoints)
+	}
+
+	if previous == nil && maxRecords != nil && recordCount > *maxRecords {
+		return fmt.Errorf("DNS quota of namespace %s exceeded: %d DNSRecords, the maximum is %d",
+			record.Namespace, recordCount, *maxRecords)
+	}
+	if maxEndpoints != nil && endpointCount > *maxEndpoints {
+		return fmt.Errorf("DNS quota of namespace %s exceeded: %d endpoints, the maximum is %d",
+			record.Namespace, endpointCount, *maxEndpoints)
+	}
+	return nil
+}
+
+func sameProviderAccount(a, b *v1.Secret) bool {
+	return a.Type == b.Type && reflect.DeepEqual(a.Data, b.Data)
+}
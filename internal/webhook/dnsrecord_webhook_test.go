@@ -0,0 +1,280 @@
+//go:build unit
+
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	externaldnsendpoint "sigs.k8s.io/external-dns/endpoint"
+
+	"github.com/kuadrant/dns-operator/api/v1alpha1"
+)
+
+func testSecret(namespace, name, key string) *v1.Secret {
+	return &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+		Type:       v1alpha1.SecretTypeKuadrantAWS,
+		Data:       map[string][]byte{v1alpha1.AWSAccessKeyIDKey: []byte(key)},
+	}
+}
+
+func testRecord(namespace, name, rootHost, secret string) *v1alpha1.DNSRecord {
+	return &v1alpha1.DNSRecord{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+		Spec: v1alpha1.DNSRecordSpec{
+			RootHost:    rootHost,
+			ProviderRef: v1alpha1.ProviderRef{Name: secret},
+		},
+	}
+}
+
+func TestValidateCreate(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = v1alpha1.AddToScheme(scheme)
+
+	existing := []client.Object{
+		testSecret("team-a", "aws-credentials", "account-1"),
+		testRecord("team-a", "foo", "foo.example.com", "aws-credentials"),
+		testSecret("team-b", "aws-credentials", "account-1"),
+		testSecret("team-c", "aws-credentials", "account-2"),
+	}
+
+	testCases := []struct {
+		name        string
+		policy      DuplicateRootHostPolicy
+		record      *v1alpha1.DNSRecord
+		expectWarn  bool
+		expectError bool
+	}{
+		{
+			name:   "disabled",
+			policy: DuplicateRootHostPolicyNone,
+			record: testRecord("team-b", "foo", "foo.example.com", "aws-credentials"),
+		},
+		{
+			name:       "same account in another namespace warns",
+			policy:     DuplicateRootHostPolicyWarn,
+			record:     testRecord("team-b", "foo", "foo.example.com", "aws-credentials"),
+			expectWarn: true,
+		},
+		{
+			name:        "same account in another namespace rejects",
+			policy:      DuplicateRootHostPolicyReject,
+			record:      testRecord("team-b", "foo", "foo.example.com", "aws-credentials"),
+			expectError: true,
+		},
+		{
+			name:   "different account is allowed",
+			policy: DuplicateRootHostPolicyReject,
+			record: testRecord("team-c", "foo", "foo.example.com", "aws-credentials"),
+		},
+		{
+			name:   "same namespace is allowed",
+			policy: DuplicateRootHostPolicyReject,
+			record: testRecord("team-a", "bar", "foo.example.com", "aws-credentials"),
+		},
+		{
+			name:   "different root host is allowed",
+			policy: DuplicateRootHostPolicyReject,
+			record: testRecord("team-b", "bar", "bar.example.com", "aws-credentials"),
+		},
+		{
+			name:   "missing secret is allowed",
+			policy: DuplicateRootHostPolicyReject,
+			record: testRecord("team-b", "foo", "foo.example.com", "missing"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithIndex(&v1alpha1.DNSRecord{}, RootHostIndexKey, rootHostIndexer).
+				WithObjects(existing...).
+				Build()
+
+			v := &DNSRecordValidator{Client: c, Policy: tc.policy}
+			warnings, err := v.ValidateCreate(context.Background(), tc.record)
+			if (err != nil) != tc.expectError {
+				t.Fatalf("expected error %v, got %v", tc.expectError, err)
+			}
+			if (len(warnings) > 0) != tc.expectWarn {
+				t.Fatalf("expected warning %v, got %v", tc.expectWarn, warnings)
+			}
+		})
+	}
+}
+
+func TestParseDuplicateRootHostPolicy(t *testing.T) {
+	for _, value := range []string{"", "warn", "reject"} {
+		if _, err := ParseDuplicateRootHostPolicy(value); err != nil {
+			t.Errorf("unexpected error for %q: %v", value, err)
+		}
+	}
+	if _, err := ParseDuplicateRootHostPolicy("deny"); err == nil {
+		t.Error("expected error for unknown policy")
+	}
+}
+
+func testQuota(namespace, name string, maxRecords, maxEndpoints *int) *v1alpha1.DNSQuota {
+	return &v1alpha1.DNSQuota{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+		Spec:       v1alpha1.DNSQuotaSpec{MaxRecords: maxRecords, MaxEndpoints: maxEndpoints},
+	}
+}
+
+func withEndpoints(record *v1alpha1.DNSRecord, count int) *v1alpha1.DNSRecord {
+	for i := 0; i < count; i++ {
+		record.Spec.Endpoints = append(record.Spec.Endpoints, &externaldnsendpoint.Endpoint{
+			DNSName:    record.Spec.RootHost,
+			RecordType: externaldnsendpoint.RecordTypeA,
+			Targets:    externaldnsendpoint.Targets{fmt.Sprintf("192.0.2.%d", i+1)},
+		})
+	}
+	return record
+}
+
+func TestValidateQuota(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = v1alpha1.AddToScheme(scheme)
+
+	existing := []client.Object{
+		testQuota("team-a", "records", ptr.To(2), nil),
+		testQuota("team-a", "endpoints", ptr.To(10), ptr.To(5)),
+		withEndpoints(testRecord("team-a", "foo", "foo.example.com", "aws-credentials"), 3),
+		withEndpoints(testRecord("team-b", "foo", "foo.example.com", "aws-credentials"), 3),
+	}
+
+	testCases := []struct {
+		name        string
+		enforce     bool
+		previous    *v1alpha1.DNSRecord
+		record      *v1alpha1.DNSRecord
+		expectError string
+	}{
+		{
+			name:   "disabled",
+			record: withEndpoints(testRecord("team-a", "bar", "bar.example.com", "aws-credentials"), 3),
+		},
+		{
+			name:    "within quota",
+			enforce: true,
+			record:  withEndpoints(testRecord("team-a", "bar", "bar.example.com", "aws-credentials"), 2),
+		},
+		{
+			name:        "endpoints over quota",
+			enforce:     true,
+			record:      withEndpoints(testRecord("team-a", "bar", "bar.example.com", "aws-credentials"), 3),
+			expectError: "6 endpoints, the maximum is 5",
+		},
+		{
+			name:     "endpoints added over quota",
+			enforce:  true,
+			previous: withEndpoints(testRecord("team-a", "foo", "foo.example.com", "aws-credentials"), 3),
+			record:   withEndpoints(testRecord("team-a", "foo", "foo.example.com", "aws-credentials"), 6),
+			// the quota of records does not apply to updates
+			expectError: "6 endpoints, the maximum is 5",
+		},
+		{
+			name:     "endpoints removed",
+			enforce:  true,
+			previous: withEndpoints(testRecord("team-a", "foo", "foo.example.com", "aws-credentials"), 8),
+			record:   withEndpoints(testRecord("team-a", "foo", "foo.example.com", "aws-credentials"), 7),
+		},
+		{
+			name:    "namespace without quota",
+			enforce: true,
+			record:  withEndpoints(testRecord("team-b", "bar", "bar.example.com", "aws-credentials"), 20),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithIndex(&v1alpha1.DNSRecord{}, RootHostIndexKey, rootHostIndexer).
+				WithObjects(existing...).
+				Build()
+
+			v := &DNSRecordValidator{Client: c, EnforceQuota: tc.enforce}
+			var err error
+			if tc.previous != nil {
+				_, err = v.ValidateUpdate(context.Background(), tc.previous, tc.record)
+			} else {
+				_, err = v.ValidateCreate(context.Background(), tc.record)
+			}
+			if tc.expectError == "" && err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if tc.expectError != "" && (err == nil || !strings.Contains(err.Error(), tc.expectError)) {
+				t.Fatalf("expected error containing %q, got %v", tc.expectError, err)
+			}
+		})
+	}
+
+	t.Run("records over quota", func(t *testing.T) {
+		c := fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithObjects(append(existing, testRecord("team-a", "bar", "bar.example.com", "aws-credentials"))...).
+			Build()
+		v := &DNSRecordValidator{Client: c, EnforceQuota: true}
+		_, err := v.ValidateCreate(context.Background(), testRecord("team-a", "baz", "baz.example.com", "aws-credentials"))
+		if err == nil || !strings.Contains(err.Error(), "3 DNSRecords, the maximum is 2") {
+			t.Fatalf("expected records quota error, got %v", err)
+		}
+	})
+}
+
+func TestValidateTargets(t *testing.T) {
+	invalid := testRecord("team-a", "foo", "foo.example.com", "aws-credentials")
+	invalid.Spec.Endpoints = []*externaldnsendpoint.Endpoint{
+		externaldnsendpoint.NewEndpoint("foo.example.com", externaldnsendpoint.RecordTypeA, "lb.example.com"),
+	}
+
+	v := &DNSRecordValidator{ValidateTargets: true}
+	if _, err := v.ValidateCreate(context.Background(), invalid); err == nil || !strings.Contains(err.Error(), "must be an IPv4 address") {
+		t.Fatalf("expected invalid target error, got %v", err)
+	}
+	valid := invalid.DeepCopy()
+	valid.Spec.Endpoints[0].Targets = externaldnsendpoint.Targets{"192.0.2.1"}
+	if _, err := v.ValidateUpdate(context.Background(), valid, invalid); err == nil {
+		t.Fatalf("expected invalid target error on update")
+	}
+
+	// records admitted before targets were validated can be updated without changing their endpoints
+	labeled := invalid.DeepCopy()
+	labeled.Labels = map[string]string{"team": "a"}
+	if _, err := v.ValidateUpdate(context.Background(), invalid, labeled); err != nil {
+		t.Fatalf("expected unchanged endpoints not to be validated, got %v", err)
+	}
+	deleting := invalid.DeepCopy()
+	deleting.Spec.Endpoints = append(deleting.Spec.Endpoints,
+		externaldnsendpoint.NewEndpoint("www.foo.example.com", externaldnsendpoint.RecordTypeA, "lb.example.com"))
+	deleting.DeletionTimestamp = ptr.To(metav1.Now())
+	if _, err := v.ValidateUpdate(context.Background(), valid, deleting); err != nil {
+		t.Fatalf("expected records being deleted not to be validated, got %v", err)
+	}
+
+	invalid.Annotations = map[string]string{v1alpha1.PartialPublishAnnotation: "true"}
+	if _, err := v.ValidateCreate(context.Background(), invalid); err != nil {
+		t.Fatalf("expected partially published records not to be rejected, got %v", err)
+	}
+
+	v.ValidateTargets = false
+	invalid.Annotations = nil
+	if _, err := v.ValidateCreate(context.Background(), invalid); err != nil {
+		t.Fatalf("expected targets not to be validated when disabled, got %v", err)
+	}
+}
@@ -0,0 +1,146 @@
+// Package authoritative computes the content of a zone from the DNSRecords published to it, the way the operator
+// publishes them: endpoints with the same name and type published by several owners have their targets merged, each
+// endpoint has registry TXT records naming its owners, and wildcard names are written to registry TXT records with a
+// replacement label. It allows the expected authoritative content of a zone to be computed without a cluster or a
+// DNS provider.
+package authoritative
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	externaldnsendpoint "sigs.k8s.io/external-dns/endpoint"
+
+	externaldnsplan "github.com/kuadrant/dns-operator/internal/external-dns/plan"
+	externaldnsregistry "github.com/kuadrant/dns-operator/internal/external-dns/registry"
+)
+
+const (
+	// RegistryPrefix is the prefix of the names of the registry TXT records of the endpoints.
+	RegistryPrefix = externaldnsregistry.KuadrantTXTPrefix
+	// RegistrySuffix is the suffix of the names of the registry TXT records of the endpoints.
+	RegistrySuffix = externaldnsregistry.KuadrantTXTSuffix
+	// WildcardReplacement is the label the wildcard of an endpoint name is replaced with in the name of its registry
+	// TXT records.
+	WildcardReplacement = externaldnsregistry.KuadrantWildcardReplacement
+)
+
+// Record is the endpoints a DNSRecord publishes to a zone.
+type Record struct {
+	// OwnerID is the owner ID of the record, the status.ownerID of the DNSRecord.
+	OwnerID string
+	// RootHost is the root host of the record.
+	RootHost string
+	// Endpoints are the endpoints of the record.
+	Endpoints []*externaldnsendpoint.Endpoint
+}
+
+// ManagedRecordTypes returns the record types managed for a record with the given endpoints. TXT, NAPTR and TLSA
+// records are only managed for records that publish, or have published, endpoints of the type so other records never
+// plan changes to them in their zone.
+func ManagedRecordTypes(endpoints ...[]*externaldnsendpoint.Endpoint) []string {
+	return externaldnsregistry.ManagedRecordTypes(endpoints...)
+}
+
+// Zone is the content of a zone the endpoints of records are published to.
+type Zone struct {
+	domain   string
+	provider *zoneProvider
+	// published are the endpoints last published for each record, by owner ID and root host
+	published map[string][]*externaldnsendpoint.Endpoint
+}
+
+// NewZone returns an empty zone for the given domain.
+func NewZone(domain string) (*Zone, error) {
+	return &Zone{domain: domain, provider: newZoneProvider(domain), published: map[string][]*externaldnsendpoint.Endpoint{}}, nil
+}
+
+// Publish publishes the endpoints of the given record to the zone, as a reconcile of the DNSRecord does. Endpoints
+// the record published before that it no longer has are removed.
+func (z *Zone) Publish(ctx context.Context, record Record) error {
+	return z.apply(ctx, record, record.Endpoints)
+}
+
+// Remove removes the endpoints of the given record from the zone, as the deletion of the DNSRecord does.
+func (z *Zone) Remove(ctx context.Context, record Record) error {
+	return z.apply(ctx, record, []*externaldnsendpoint.Endpoint{})
+}
+
+// Records returns the records of the zone, including the registry TXT records, sorted by name, type and set
+// identifier. The records are returned as they are published, without the labels tracking their owners.
+func (z *Zone) Records(ctx context.Context) ([]*externaldnsendpoint.Endpoint, error) {
+	records, err := z.provider.Records(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, record := range records {
+		record.Labels = externaldnsendpoint.Labels{}
+	}
+	sort.Slice(records, func(i, j int) bool {
+		if records[i].DNSName != records[j].DNSName {
+			return records[i].DNSName < records[j].DNSName
+		}
+		if records[i].RecordType != records[j].RecordType {
+			return records[i].RecordType < records[j].RecordType
+		}
+		return records[i].SetIdentifier < records[j].SetIdentifier
+	})
+	return records, nil
+}
+
+func (z *Zone) apply(ctx context.Context, record Record, desired []*externaldnsendpoint.Endpoint) error {
+	if record.OwnerID == "" {
+		return fmt.Errorf("record for %s has no owner ID", record.RootHost)
+	}
+	key := record.OwnerID + "/" + record.RootHost
+	previous := z.published[key]
+
+	registry, err := NewRegistry(ctx, z.provider, record.OwnerID, ManagedRecordTypes(desired, previous))
+	if err != nil {
+		return err
+	}
+	current, err := registry.Records(ctx)
+	if err != nil {
+		return err
+	}
+	desired, err = registry.AdjustEndpoints(desired)
+	if err != nil {
+		return err
+	}
+
+	domainFilter := externaldnsendpoint.NewDomainFilter([]string{z.domain})
+	rootHost := record.RootHost
+	plan := externaldnsplan.NewPlan(ctx, current, previous, desired, []externaldnsplan.Policy{&externaldnsplan.SyncPolicy{}},
+		externaldnsendpoint.MatchAllDomainFilters{&domainFilter}, ManagedRecordTypes(desired, previous), nil,
+		record.OwnerID, &rootHost).Calculate()
+	if err = plan.Error(); err != nil {
+		return err
+	}
+	if plan.Changes.HasChanges() {
+		if err = registry.ApplyChanges(ctx, plan.Changes); err != nil {
+			return err
+		}
+	}
+	if len(desired) == 0 {
+		delete(z.published, key)
+	} else {
+		z.published[key] = desired
+	}
+	return nil
+}
+
+// Assemble returns the records of a zone for the given domain with the endpoints of the given records published to
+// it in order.
+func Assemble(ctx context.Context, domain string, records ...Record) ([]*externaldnsendpoint.Endpoint, error) {
+	zone, err := NewZone(domain)
+	if err != nil {
+		return nil, err
+	}
+	for _, record := range records {
+		if err = zone.Publish(ctx, record); err != nil {
+			return nil, fmt.Errorf("publishing record of owner %s: %w", record.OwnerID, err)
+		}
+	}
+	return zone.Records(ctx)
+}
@@ -0,0 +1,223 @@
+//go:build unit
+
+package authoritative
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	externaldnsendpoint "sigs.k8s.io/external-dns/endpoint"
+)
+
+// describe returns each record as "<name> <type> <targets>", with the targets sorted and separated by commas.
+func describe(records []*externaldnsendpoint.Endpoint) []string {
+	descriptions := make([]string, 0, len(records))
+	for _, record := range records {
+		targets := append([]string{}, record.Targets...)
+		sort.Strings(targets)
+		name := record.DNSName
+		if record.SetIdentifier != "" {
+			name = fmt.Sprintf("%s/%s", record.DNSName, record.SetIdentifier)
+		}
+		descriptions = append(descriptions, fmt.Sprintf("%s %s %s", name, record.RecordType, strings.Join(targets, ",")))
+	}
+	return descriptions
+}
+
+func TestAssemble(t *testing.T) {
+	tests := []struct {
+		name    string
+		records []Record
+		want    []string
+		wantErr string
+	}{
+		{
+			name: "single owner",
+			records: []Record{
+				{OwnerID: "owner1", RootHost: "foo.example.com", Endpoints: []*externaldnsendpoint.Endpoint{
+					externaldnsendpoint.NewEndpointWithTTL("foo.example.com", externaldnsendpoint.RecordTypeA, 60, "1.1.1.1"),
+				}},
+			},
+			want: []string{
+				"foo.example.com A 1.1.1.1",
+				`kuadrant-a-foo.example.com TXT "heritage=external-dns,external-dns/owner=owner1"`,
+			},
+		},
+		{
+			name: "targets of owners are merged",
+			records: []Record{
+				{OwnerID: "owner1", RootHost: "foo.example.com", Endpoints: []*externaldnsendpoint.Endpoint{
+					externaldnsendpoint.NewEndpointWithTTL("foo.example.com", externaldnsendpoint.RecordTypeA, 60, "1.1.1.1"),
+				}},
+				{OwnerID: "owner2", RootHost: "foo.example.com", Endpoints: []*externaldnsendpoint.Endpoint{
+					externaldnsendpoint.NewEndpointWithTTL("foo.example.com", externaldnsendpoint.RecordTypeA, 60, "2.2.2.2"),
+				}},
+			},
+			want: []string{
+				"foo.example.com A 1.1.1.1,2.2.2.2",
+				`kuadrant-a-foo.example.com TXT "heritage=external-dns,external-dns/owner=owner1&&owner2"`,
+			},
+		},
+		{
+			name: "wildcard names",
+			records: []Record{
+				{OwnerID: "owner1", RootHost: "*.foo.example.com", Endpoints: []*externaldnsendpoint.Endpoint{
+					externaldnsendpoint.NewEndpointWithTTL("*.foo.example.com", externaldnsendpoint.RecordTypeCNAME, 300, "lb.example.net"),
+				}},
+			},
+			want: []string{
+				"*.foo.example.com CNAME lb.example.net",
+				`kuadrant-cname-wildcard.foo.example.com TXT "heritage=external-dns,external-dns/owner=owner1"`,
+			},
+		},
+		{
+			name: "set identifiers",
+			records: []Record{
+				{OwnerID: "owner1", RootHost: "foo.example.com", Endpoints: []*externaldnsendpoint.Endpoint{
+					externaldnsendpoint.NewEndpointWithTTL("foo.example.com", externaldnsendpoint.RecordTypeCNAME, 300, "eu.example.net").
+						WithSetIdentifier("eu").WithProviderSpecific("weight", "100"),
+				}},
+				{OwnerID: "owner2", RootHost: "foo.example.com", Endpoints: []*externaldnsendpoint.Endpoint{
+					externaldnsendpoint.NewEndpointWithTTL("foo.example.com", externaldnsendpoint.RecordTypeCNAME, 300, "us.example.net").
+						WithSetIdentifier("us").WithProviderSpecific("weight", "100"),
+				}},
+			},
+			want: []string{
+				"foo.example.com/eu CNAME eu.example.net",
+				"foo.example.com/us CNAME us.example.net",
+				`kuadrant-cname-foo.example.com/eu TXT "heritage=external-dns,external-dns/owner=owner1"`,
+				`kuadrant-cname-foo.example.com/us TXT "heritage=external-dns,external-dns/owner=owner2"`,
+			},
+		},
+		{
+			name: "conflicting record types",
+			records: []Record{
+				{OwnerID: "owner1", RootHost: "foo.example.com", Endpoints: []*externaldnsendpoint.Endpoint{
+					externaldnsendpoint.NewEndpointWithTTL("foo.example.com", externaldnsendpoint.RecordTypeA, 60, "1.1.1.1"),
+				}},
+				{OwnerID: "owner2", RootHost: "foo.example.com", Endpoints: []*externaldnsendpoint.Endpoint{
+					externaldnsendpoint.NewEndpointWithTTL("foo.example.com", externaldnsendpoint.RecordTypeCNAME, 300, "lb.example.net"),
+				}},
+			},
+			wantErr: "publishing record of owner owner2",
+		},
+		{
+			name: "record without an owner",
+			records: []Record{
+				{RootHost: "foo.example.com"},
+			},
+			wantErr: "has no owner ID",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			records, err := Assemble(context.Background(), "example.com", tt.records...)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := describe(records); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected records\n%s\ngot\n%s", strings.Join(tt.want, "\n"), strings.Join(got, "\n"))
+			}
+		})
+	}
+}
+
+func TestZone(t *testing.T) {
+	ctx := context.Background()
+	zone, err := NewZone("example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	owner1 := Record{OwnerID: "owner1", RootHost: "foo.example.com", Endpoints: []*externaldnsendpoint.Endpoint{
+		externaldnsendpoint.NewEndpointWithTTL("foo.example.com", externaldnsendpoint.RecordTypeA, 60, "1.1.1.1"),
+	}}
+	owner2 := Record{OwnerID: "owner2", RootHost: "foo.example.com", Endpoints: []*externaldnsendpoint.Endpoint{
+		externaldnsendpoint.NewEndpointWithTTL("foo.example.com", externaldnsendpoint.RecordTypeA, 60, "2.2.2.2"),
+	}}
+
+	steps := []struct {
+		name  string
+		apply func() error
+		want  []string
+	}{
+		{
+			name:  "publish both owners",
+			apply: func() error { return errors.Join(zone.Publish(ctx, owner1), zone.Publish(ctx, owner2)) },
+			want: []string{
+				"foo.example.com A 1.1.1.1,2.2.2.2",
+				`kuadrant-a-foo.example.com TXT "heritage=external-dns,external-dns/owner=owner1&&owner2"`,
+			},
+		},
+		{
+			name: "change the targets of an owner",
+			apply: func() error {
+				owner1.Endpoints = []*externaldnsendpoint.Endpoint{
+					externaldnsendpoint.NewEndpointWithTTL("foo.example.com", externaldnsendpoint.RecordTypeA, 60, "3.3.3.3"),
+				}
+				return zone.Publish(ctx, owner1)
+			},
+			want: []string{
+				"foo.example.com A 2.2.2.2,3.3.3.3",
+				`kuadrant-a-foo.example.com TXT "heritage=external-dns,external-dns/owner=owner1&&owner2"`,
+			},
+		},
+		{
+			name:  "remove an owner",
+			apply: func() error { return zone.Remove(ctx, owner2) },
+			want: []string{
+				"foo.example.com A 3.3.3.3",
+				`kuadrant-a-foo.example.com TXT "heritage=external-dns,external-dns/owner=owner1"`,
+			},
+		},
+		{
+			name:  "remove the last owner",
+			apply: func() error { return zone.Remove(ctx, owner1) },
+			want:  []string{},
+		},
+	}
+	for _, step := range steps {
+		if err := step.apply(); err != nil {
+			t.Fatalf("%s: unexpected error: %v", step.name, err)
+		}
+		records, err := zone.Records(ctx)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", step.name, err)
+		}
+		if got := describe(records); !reflect.DeepEqual(got, step.want) {
+			t.Errorf("%s: expected records\n%s\ngot\n%s", step.name, strings.Join(step.want, "\n"), strings.Join(got, "\n"))
+		}
+	}
+}
+
+func TestManagedRecordTypes(t *testing.T) {
+	spec := []*externaldnsendpoint.Endpoint{externaldnsendpoint.NewEndpoint("foo.example.com", externaldnsendpoint.RecordTypeTXT, "v=spf1 -all")}
+	status := []*externaldnsendpoint.Endpoint{externaldnsendpoint.NewEndpoint("foo.example.com", "TLSA", "3 1 1 00")}
+
+	tests := []struct {
+		name      string
+		endpoints [][]*externaldnsendpoint.Endpoint
+		want      []string
+	}{
+		{name: "no endpoints", want: []string{"A", "AAAA", "CNAME"}},
+		{name: "spec endpoints", endpoints: [][]*externaldnsendpoint.Endpoint{spec, nil}, want: []string{"A", "AAAA", "CNAME", "TXT"}},
+		{name: "published endpoints", endpoints: [][]*externaldnsendpoint.Endpoint{spec, status}, want: []string{"A", "AAAA", "CNAME", "TXT", "TLSA"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ManagedRecordTypes(tt.endpoints...); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package authoritative
+
+import (
+	"context"
+
+	externaldnsendpoint "sigs.k8s.io/external-dns/endpoint"
+	externaldnsplan "sigs.k8s.io/external-dns/plan"
+	externaldnsprovider "sigs.k8s.io/external-dns/provider"
+
+	externaldnsregistry "github.com/kuadrant/dns-operator/internal/external-dns/registry"
+)
+
+// TXTFormat is the format of the names of the registry TXT records.
+type TXTFormat = externaldnsregistry.TXTFormat
+
+const (
+	// TXTFormatNew registry TXT records are only written with the record type in their name.
+	TXTFormatNew = externaldnsregistry.TXTFormatNew
+	// TXTFormatBoth registry TXT records of A and CNAME endpoints are also written without the record type in their
+	// name.
+	TXTFormatBoth = externaldnsregistry.TXTFormatBoth
+)
+
+// OwnerAdoption identifies endpoints published by other external-dns instances that are adopted by the owner of a
+// registry.
+type OwnerAdoption = externaldnsregistry.OwnerAdoption
+
+// ZoneRecordCounts is the number of records in the zone of the endpoints of a registry.
+type ZoneRecordCounts = externaldnsregistry.ZoneRecordCounts
+
+// Registry publishes the endpoints of an owner to a zone with registry TXT records naming the owners of each endpoint.
+type Registry interface {
+	// OwnerID returns the owner ID of the endpoints published with the registry.
+	OwnerID() string
+	// WithResourceLabels sets labels written to the registry TXT records of the endpoints.
+	WithResourceLabels(labels externaldnsendpoint.Labels) Registry
+	// WithRegistryZone writes the registry TXT records to the given provider, under the given domain, rather than
+	// next to the endpoints.
+	WithRegistryZone(registryProvider externaldnsprovider.Provider, domain string) Registry
+	// WithTXTFormat sets the format of the names of the registry TXT records.
+	WithTXTFormat(format TXTFormat) Registry
+	// WithOwnerRename moves the endpoints of the given previous owner ID to the owner ID of the registry.
+	WithOwnerRename(previousOwnerID string) Registry
+	// WithOwnerAdoption adopts the endpoints published by other external-dns instances.
+	WithOwnerAdoption(adoption OwnerAdoption) Registry
+	// Records returns the endpoints of the zone, labelled with their owners.
+	Records(ctx context.Context) ([]*externaldnsendpoint.Endpoint, error)
+	// ZoneRecordCounts returns the number of records in the zone counted by the last call to Records.
+	ZoneRecordCounts() ZoneRecordCounts
+	// AdjustEndpoints returns the given endpoints as they are published to the zone.
+	AdjustEndpoints(endpoints []*externaldnsendpoint.Endpoint) ([]*externaldnsendpoint.Endpoint, error)
+	// ApplyChanges applies the given changes to the zone with the registry TXT records of the changed endpoints.
+	ApplyChanges(ctx context.Context, changes *externaldnsplan.Changes) error
+}
+
+// NewRegistry returns the registry the endpoints of the given owner are published to the given provider with.
+func NewRegistry(ctx context.Context, p externaldnsprovider.Provider, ownerID string, managedRecordTypes []string) (Registry, error) {
+	registry, err := externaldnsregistry.NewKuadrantTXTRegistry(ctx, p, ownerID, managedRecordTypes)
+	if err != nil {
+		return nil, err
+	}
+	return &txtRegistry{TXTRegistry: registry}, nil
+}
+
+// txtRegistry is a Registry backed by the TXT registry of the operator.
+type txtRegistry struct {
+	*externaldnsregistry.TXTRegistry
+}
+
+func (r *txtRegistry) WithResourceLabels(labels externaldnsendpoint.Labels) Registry {
+	r.TXTRegistry.WithResourceLabels(labels)
+	return r
+}
+
+func (r *txtRegistry) WithRegistryZone(registryProvider externaldnsprovider.Provider, domain string) Registry {
+	r.TXTRegistry.WithRegistryZone(registryProvider, domain)
+	return r
+}
+
+func (r *txtRegistry) WithTXTFormat(format TXTFormat) Registry {
+	r.TXTRegistry.WithTXTFormat(format)
+	return r
+}
+
+func (r *txtRegistry) WithOwnerRename(previousOwnerID string) Registry {
+	r.TXTRegistry.WithOwnerRename(previousOwnerID)
+	return r
+}
+
+func (r *txtRegistry) WithOwnerAdoption(adoption OwnerAdoption) Registry {
+	r.TXTRegistry.WithOwnerAdoption(adoption)
+	return r
+}
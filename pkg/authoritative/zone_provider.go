@@ -0,0 +1,77 @@
+package authoritative
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	externaldnsendpoint "sigs.k8s.io/external-dns/endpoint"
+	externaldnsplan "sigs.k8s.io/external-dns/plan"
+	externaldnsprovider "sigs.k8s.io/external-dns/provider"
+)
+
+// zoneProvider is a provider holding the records of a single zone in memory. Changes to records outside the zone are
+// ignored, as a provider only applies the changes of the zones it serves.
+type zoneProvider struct {
+	externaldnsprovider.BaseProvider
+	domain  string
+	records map[externaldnsendpoint.EndpointKey]*externaldnsendpoint.Endpoint
+}
+
+var _ externaldnsprovider.Provider = &zoneProvider{}
+
+func newZoneProvider(domain string) *zoneProvider {
+	return &zoneProvider{domain: domain, records: map[externaldnsendpoint.EndpointKey]*externaldnsendpoint.Endpoint{}}
+}
+
+// Records returns copies of the records of the zone.
+func (p *zoneProvider) Records(_ context.Context) ([]*externaldnsendpoint.Endpoint, error) {
+	records := make([]*externaldnsendpoint.Endpoint, 0, len(p.records))
+	for _, record := range p.records {
+		records = append(records, record.DeepCopy())
+	}
+	return records, nil
+}
+
+// ApplyChanges applies the changes to the records of the zone. No change is applied if any of them is invalid: created
+// records must not exist, updated and deleted records must exist and a record can't be changed more than once.
+func (p *zoneProvider) ApplyChanges(_ context.Context, changes *externaldnsplan.Changes) error {
+	changed := map[externaldnsendpoint.EndpointKey]bool{}
+	for _, change := range []struct {
+		endpoints []*externaldnsendpoint.Endpoint
+		exists    bool
+	}{{changes.Create, false}, {changes.UpdateNew, true}, {changes.Delete, true}} {
+		for _, ep := range p.inZone(change.endpoints) {
+			key := ep.Key()
+			if _, exists := p.records[key]; exists != change.exists {
+				return fmt.Errorf("unable to change record %s %s: exists %t, expected %t", ep.DNSName, ep.RecordType, exists, change.exists)
+			}
+			if changed[key] {
+				return fmt.Errorf("unable to change record %s %s: changed more than once", ep.DNSName, ep.RecordType)
+			}
+			changed[key] = true
+		}
+	}
+
+	for _, ep := range p.inZone(changes.Create) {
+		p.records[ep.Key()] = ep.DeepCopy()
+	}
+	for _, ep := range p.inZone(changes.UpdateNew) {
+		p.records[ep.Key()] = ep.DeepCopy()
+	}
+	for _, ep := range p.inZone(changes.Delete) {
+		delete(p.records, ep.Key())
+	}
+	return nil
+}
+
+// inZone returns the endpoints with names in the zone.
+func (p *zoneProvider) inZone(endpoints []*externaldnsendpoint.Endpoint) []*externaldnsendpoint.Endpoint {
+	var filtered []*externaldnsendpoint.Endpoint
+	for _, ep := range endpoints {
+		if ep.DNSName == p.domain || strings.HasSuffix(ep.DNSName, "."+p.domain) {
+			filtered = append(filtered, ep)
+		}
+	}
+	return filtered
+}
@@ -17,7 +17,9 @@ limitations under the License.
 package builder
 
 import (
+	"strconv"
 	"strings"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -64,6 +66,35 @@ func (pb *ProviderBuilder) WithZonesInitialisedFor(domains ...string) *ProviderB
 	return pb.WithDataItem(v1alpha1.InmemInitZonesKey, strings.Join(domains, ","))
 }
 
+// WithFailedApplyChanges sets the number of next ApplyChanges calls that fail with the given error code, e.g.
+// Throttled, or Unavailable if empty. The count restarts every time the secret is updated.
+// Only used by v1alpha1.SecretTypeKuadrantInmemory provider, ignored by all others.
+func (pb *ProviderBuilder) WithFailedApplyChanges(count int, errorCode string) *ProviderBuilder {
+	pb.WithDataItem(v1alpha1.InmemFailApplyChangesKey, strconv.Itoa(count))
+	if errorCode != "" {
+		pb.WithDataItem(v1alpha1.InmemFailErrorCodeKey, errorCode)
+	}
+	return pb
+}
+
+// WithLatency sets the duration added to every Records and ApplyChanges call of the provider.
+// Only used by v1alpha1.SecretTypeKuadrantInmemory provider, ignored by all others.
+func (pb *ProviderBuilder) WithLatency(latency time.Duration) *ProviderBuilder {
+	return pb.WithDataItem(v1alpha1.InmemLatencyKey, latency.String())
+}
+
+// WithAllowedDomains sets the domains the zones of the provider must be in for records to be published to them.
+// Defaults to all domains.
+func (pb *ProviderBuilder) WithAllowedDomains(domains ...string) *ProviderBuilder {
+	return pb.WithDataItem(v1alpha1.AllowedDomainsKey, strings.Join(domains, ","))
+}
+
+// WithTXTRegistryFormat sets the format of the registry TXT records written to the zones of the provider, one of "new"
+// or "both". Defaults to "new".
+func (pb *ProviderBuilder) WithTXTRegistryFormat(format string) *ProviderBuilder {
+	return pb.WithDataItem(v1alpha1.TXTRegistryFormatKey, format)
+}
+
 // Build builds and returns the provider secret.
 func (pb *ProviderBuilder) Build() *corev1.Secret {
 	return &corev1.Secret{
@@ -0,0 +1,133 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kuadrant/dns-operator/api/v1alpha1"
+)
+
+const (
+	// DefaultFieldOwner is the field manager used when applying resources if none is given
+	DefaultFieldOwner = "dns-operator-client"
+	// DefaultPollInterval is the interval used when waiting on a resource if none is given
+	DefaultPollInterval = time.Second
+)
+
+// Client is a typed client for the dns-operator APIs.
+type Client struct {
+	client.Client
+	fieldOwner string
+}
+
+// NewScheme returns a new scheme with the dns-operator APIs registered.
+func NewScheme() *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	utilruntime.Must(v1alpha1.AddToScheme(scheme))
+	return scheme
+}
+
+// New returns a new Client for the given rest config.
+func New(config *rest.Config) (*Client, error) {
+	c, err := client.New(config, client.Options{Scheme: NewScheme()})
+	if err != nil {
+		return nil, err
+	}
+	return NewFromClient(c), nil
+}
+
+// NewFromClient returns a new Client wrapping the given controller-runtime client.
+// The scheme of the given client must have the dns-operator APIs registered.
+func NewFromClient(c client.Client) *Client {
+	return &Client{Client: c, fieldOwner: DefaultFieldOwner}
+}
+
+// WithFieldOwner sets the field manager used when applying resources.
+func (c *Client) WithFieldOwner(fieldOwner string) *Client {
+	c.fieldOwner = fieldOwner
+	return c
+}
+
+// GetDNSRecord returns the DNSRecord with the given key.
+func (c *Client) GetDNSRecord(ctx context.Context, key client.ObjectKey) (*v1alpha1.DNSRecord, error) {
+	record := &v1alpha1.DNSRecord{}
+	if err := c.Get(ctx, key, record); err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+// ListDNSRecords returns the DNSRecords matching the given options.
+func (c *Client) ListDNSRecords(ctx context.Context, opts ...client.ListOption) ([]v1alpha1.DNSRecord, error) {
+	records := &v1alpha1.DNSRecordList{}
+	if err := c.List(ctx, records, opts...); err != nil {
+		return nil, err
+	}
+	return records.Items, nil
+}
+
+// ApplyDNSRecord creates or updates the given DNSRecord using server-side apply.
+// Only the fields set on the given record are owned by the client field owner, the status is ignored.
+func (c *Client) ApplyDNSRecord(ctx context.Context, record *v1alpha1.DNSRecord) error {
+	applyRecord := &v1alpha1.DNSRecord{
+		ObjectMeta: *record.ObjectMeta.DeepCopy(),
+		Spec:       *record.Spec.DeepCopy(),
+	}
+	applyRecord.APIVersion = v1alpha1.GroupVersion.String()
+	applyRecord.Kind = "DNSRecord"
+	applyRecord.ResourceVersion = ""
+	applyRecord.ManagedFields = nil
+
+	if err := c.Patch(ctx, applyRecord, client.Apply, client.FieldOwner(c.fieldOwner), client.ForceOwnership); err != nil {
+		return err
+	}
+	applyRecord.DeepCopyInto(record)
+	return nil
+}
+
+// WaitForDNSRecordReady polls the DNSRecord with the given key until it is ready for its current generation, the
+// given context is done or the given timeout expires. The last observed DNSRecord is returned.
+func (c *Client) WaitForDNSRecordReady(ctx context.Context, key client.ObjectKey, timeout time.Duration) (*v1alpha1.DNSRecord, error) {
+	return c.WaitForDNSRecord(ctx, key, timeout, IsReady)
+}
+
+// WaitForDNSRecord polls the DNSRecord with the given key until the given condition func returns true, the given
+// context is done or the given timeout expires. The last observed DNSRecord is returned.
+func (c *Client) WaitForDNSRecord(ctx context.Context, key client.ObjectKey, timeout time.Duration, conditionFunc func(*v1alpha1.DNSRecord) bool) (*v1alpha1.DNSRecord, error) {
+	var record *v1alpha1.DNSRecord
+	err := wait.PollUntilContextTimeout(ctx, DefaultPollInterval, timeout, true, func(ctx context.Context) (bool, error) {
+		current, err := c.GetDNSRecord(ctx, key)
+		if err != nil {
+			return false, client.IgnoreNotFound(err)
+		}
+		record = current
+		return conditionFunc(record), nil
+	})
+	if err != nil {
+		return record, fmt.Errorf("waiting for DNSRecord %s: %w", key, err)
+	}
+	return record, nil
+}
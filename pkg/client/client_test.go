@@ -0,0 +1,111 @@
+//go:build unit
+
+package client
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kuadrant/dns-operator/api/v1alpha1"
+)
+
+func testRecord(name string, generation int64, conditions ...metav1.Condition) *v1alpha1.DNSRecord {
+	return &v1alpha1.DNSRecord{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       name,
+			Namespace:  "test",
+			Generation: generation,
+		},
+		Status: v1alpha1.DNSRecordStatus{
+			Conditions: conditions,
+		},
+	}
+}
+
+func TestIsReady(t *testing.T) {
+	testCases := []struct {
+		name   string
+		record *v1alpha1.DNSRecord
+		expect bool
+	}{
+		{
+			name:   "no conditions",
+			record: testRecord("foo", 1),
+			expect: false,
+		},
+		{
+			name: "ready",
+			record: testRecord("foo", 1, metav1.Condition{
+				Type:               string(v1alpha1.ConditionTypeReady),
+				Status:             metav1.ConditionTrue,
+				ObservedGeneration: 1,
+			}),
+			expect: true,
+		},
+		{
+			name: "not ready",
+			record: testRecord("foo", 1, metav1.Condition{
+				Type:               string(v1alpha1.ConditionTypeReady),
+				Status:             metav1.ConditionFalse,
+				ObservedGeneration: 1,
+			}),
+			expect: false,
+		},
+		{
+			name: "ready for previous generation",
+			record: testRecord("foo", 2, metav1.Condition{
+				Type:               string(v1alpha1.ConditionTypeReady),
+				Status:             metav1.ConditionTrue,
+				ObservedGeneration: 1,
+			}),
+			expect: false,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			if got := IsReady(testCase.record); got != testCase.expect {
+				t.Errorf("expected ready to be %v, got %v", testCase.expect, got)
+			}
+		})
+	}
+}
+
+func TestClient(t *testing.T) {
+	ctx := context.Background()
+	ready := testRecord("ready", 1, metav1.Condition{
+		Type:               string(v1alpha1.ConditionTypeReady),
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: 1,
+	})
+	notReady := testRecord("not-ready", 1)
+
+	c := NewFromClient(fake.NewClientBuilder().WithScheme(NewScheme()).WithObjects(ready, notReady).Build())
+
+	records, err := c.ListDNSRecords(ctx, client.InNamespace("test"))
+	if err != nil {
+		t.Fatalf("unexpected error listing records: %v", err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("expected 2 records, got %v", len(records))
+	}
+
+	record, err := c.GetDNSRecord(ctx, client.ObjectKeyFromObject(ready))
+	if err != nil {
+		t.Fatalf("unexpected error getting record: %v", err)
+	}
+	if record.Name != ready.Name {
+		t.Fatalf("expected record %s, got %s", ready.Name, record.Name)
+	}
+
+	if _, err = c.WaitForDNSRecordReady(ctx, client.ObjectKeyFromObject(ready), time.Second); err != nil {
+		t.Errorf("unexpected error waiting for ready record: %v", err)
+	}
+	if _, err = c.WaitForDNSRecordReady(ctx, client.ObjectKeyFromObject(notReady), time.Second); err == nil {
+		t.Errorf("expected an error waiting for a record that is not ready")
+	}
+}
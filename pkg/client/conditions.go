@@ -0,0 +1,30 @@
+package client
+
+import (
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kuadrant/dns-operator/api/v1alpha1"
+)
+
+// GetCondition returns the condition of the given type from the DNSRecord status, or nil if it is not set.
+func GetCondition(record *v1alpha1.DNSRecord, conditionType v1alpha1.ConditionType) *metav1.Condition {
+	return meta.FindStatusCondition(record.Status.Conditions, string(conditionType))
+}
+
+// IsConditionTrue returns true if the condition of the given type is true and was set for the current generation of the DNSRecord.
+func IsConditionTrue(record *v1alpha1.DNSRecord, conditionType v1alpha1.ConditionType) bool {
+	cond := GetCondition(record, conditionType)
+	return cond != nil && cond.Status == metav1.ConditionTrue && cond.ObservedGeneration == record.Generation
+}
+
+// IsReady returns true if the DNSRecord has been published to the provider for its current generation.
+func IsReady(record *v1alpha1.DNSRecord) bool {
+	return IsConditionTrue(record, v1alpha1.ConditionTypeReady)
+}
+
+// IsHealthy returns true if all the health checks of the DNSRecord are passing. A DNSRecord without health checks
+// has no healthy condition and is never considered healthy.
+func IsHealthy(record *v1alpha1.DNSRecord) bool {
+	return IsConditionTrue(record, v1alpha1.ConditionTypeHealthy)
+}
@@ -0,0 +1,134 @@
+package client
+
+import (
+	"context"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kuadrant/dns-operator/api/v1alpha1"
+)
+
+// DelegationState is the delegation of the zone a DNSRecord is published to from its parent zone.
+type DelegationState string
+
+const (
+	// DelegationStateOK no broken delegation has been detected, either the delegation was checked or the operator
+	// doesn't check delegations
+	DelegationStateOK DelegationState = "OK"
+	// DelegationStateNotDelegated the parent zone does not delegate the zone, records published to it don't resolve
+	DelegationStateNotDelegated DelegationState = DelegationState(v1alpha1.ConditionReasonZoneNotDelegated)
+	// DelegationStateOtherNameservers the parent zone delegates the zone to nameservers that are not nameservers of
+	// the zone, e.g. those of a previous provider
+	DelegationStateOtherNameservers DelegationState = DelegationState(v1alpha1.ConditionReasonZoneDelegationMismatch)
+)
+
+// RecordReadiness is the DNS readiness of a single DNSRecord.
+type RecordReadiness struct {
+	// Key is the namespace and name of the DNSRecord
+	Key client.ObjectKey
+	// Ready is true if the record is published to the provider for its current generation
+	Ready bool
+	// Phase is the phase of the record, Publishing while the current generation has not been reconciled
+	Phase v1alpha1.DNSRecordPhase
+	// Reason and Message are those of the Ready condition of the record, or of the condition that degrades it
+	Reason  string
+	Message string
+	// Delegation is the delegation state of the zone of the record
+	Delegation DelegationState
+}
+
+// Readiness is the DNS readiness of a hostname aggregated from the DNSRecords with the hostname as root host, e.g. the
+// records of a gateway listener on every cluster. Integrations should use it rather than interpret the conditions of
+// DNSRecords themselves.
+type Readiness struct {
+	// Hostname is the root host of the records
+	Hostname string
+	// Ready is true if there are records for the hostname and all of them are ready
+	Ready bool
+	// Phase is the least ready phase of the records, Pending if there are no records
+	Phase v1alpha1.DNSRecordPhase
+	// Delegation is OK unless a broken delegation was detected for the zone of one of the records
+	Delegation DelegationState
+	// Records is the readiness of each record
+	Records []RecordReadiness
+}
+
+// phaseRank orders the phases from the most to the least ready, a record without a phase is pending.
+var phaseRank = map[v1alpha1.DNSRecordPhase]int{
+	v1alpha1.DNSRecordPhaseReady:      0,
+	v1alpha1.DNSRecordPhasePublishing: 1,
+	v1alpha1.DNSRecordPhaseDeleting:   2,
+	v1alpha1.DNSRecordPhasePending:    3,
+	v1alpha1.DNSRecordPhaseDegraded:   4,
+	v1alpha1.DNSRecordPhaseConflict:   5,
+}
+
+// GetRecordReadiness returns the readiness of the given DNSRecord.
+func GetRecordReadiness(record *v1alpha1.DNSRecord) RecordReadiness {
+	readiness := RecordReadiness{
+		Key:        client.ObjectKeyFromObject(record),
+		Ready:      IsReady(record),
+		Phase:      record.Status.Phase,
+		Delegation: DelegationStateOK,
+	}
+	if _, ok := phaseRank[readiness.Phase]; !ok {
+		readiness.Phase = v1alpha1.DNSRecordPhasePending
+	}
+	if readiness.Phase == v1alpha1.DNSRecordPhaseReady && !readiness.Ready {
+		readiness.Phase = v1alpha1.DNSRecordPhasePublishing
+	}
+	if cond := GetCondition(record, v1alpha1.ConditionTypeReady); cond != nil {
+		readiness.Reason, readiness.Message = cond.Reason, cond.Message
+	}
+	if cond := GetCondition(record, v1alpha1.ConditionTypeHealthy); readiness.Ready && cond != nil && cond.Status != metav1.ConditionTrue {
+		readiness.Reason, readiness.Message = cond.Reason, cond.Message
+	}
+	if cond := GetCondition(record, v1alpha1.ConditionTypeZoneDelegationBroken); cond != nil && cond.Status == metav1.ConditionTrue {
+		readiness.Delegation = DelegationState(cond.Reason)
+		if readiness.Ready {
+			readiness.Reason, readiness.Message = cond.Reason, cond.Message
+		}
+	}
+	return readiness
+}
+
+// GetHostnameReadiness returns the readiness of the given hostname aggregated from the given DNSRecords with the
+// hostname as root host, other records are ignored.
+func GetHostnameReadiness(hostname string, records []v1alpha1.DNSRecord) Readiness {
+	readiness := Readiness{
+		Hostname:   hostname,
+		Phase:      v1alpha1.DNSRecordPhasePending,
+		Delegation: DelegationStateOK,
+	}
+	for i := range records {
+		if !strings.EqualFold(records[i].Spec.RootHost, hostname) {
+			continue
+		}
+		record := GetRecordReadiness(&records[i])
+		if len(readiness.Records) == 0 || phaseRank[record.Phase] > phaseRank[readiness.Phase] {
+			readiness.Phase = record.Phase
+		}
+		if record.Delegation != DelegationStateOK {
+			readiness.Delegation = record.Delegation
+		}
+		readiness.Records = append(readiness.Records, record)
+	}
+	readiness.Ready = len(readiness.Records) > 0
+	for _, record := range readiness.Records {
+		readiness.Ready = readiness.Ready && record.Ready
+	}
+	return readiness
+}
+
+// GetReadiness returns the readiness of the given hostname aggregated from the DNSRecords matching the given options,
+// e.g. client.InNamespace, with the hostname as root host.
+func (c *Client) GetReadiness(ctx context.Context, hostname string, opts ...client.ListOption) (*Readiness, error) {
+	records, err := c.ListDNSRecords(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+	readiness := GetHostnameReadiness(hostname, records)
+	return &readiness, nil
+}
@@ -0,0 +1,170 @@
+//go:build unit
+
+package client
+
+import (
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kuadrant/dns-operator/api/v1alpha1"
+)
+
+func readinessTestRecord(name, rootHost string, phase v1alpha1.DNSRecordPhase, conditions ...metav1.Condition) v1alpha1.DNSRecord {
+	record := testRecord(name, 1, conditions...)
+	record.Spec.RootHost = rootHost
+	record.Status.Phase = phase
+	return *record
+}
+
+func TestGetHostnameReadiness(t *testing.T) {
+	ready := metav1.Condition{
+		Type:               string(v1alpha1.ConditionTypeReady),
+		Status:             metav1.ConditionTrue,
+		Reason:             string(v1alpha1.ConditionReasonProviderSuccess),
+		ObservedGeneration: 1,
+	}
+	awaitingValidation := metav1.Condition{
+		Type:               string(v1alpha1.ConditionTypeReady),
+		Status:             metav1.ConditionFalse,
+		Reason:             string(v1alpha1.ConditionReasonAwaitingValidation),
+		ObservedGeneration: 1,
+	}
+	partiallyHealthy := metav1.Condition{
+		Type:               string(v1alpha1.ConditionTypeHealthy),
+		Status:             metav1.ConditionFalse,
+		Reason:             string(v1alpha1.ConditionReasonPartiallyHealthy),
+		ObservedGeneration: 1,
+	}
+	notDelegated := metav1.Condition{
+		Type:               string(v1alpha1.ConditionTypeZoneDelegationBroken),
+		Status:             metav1.ConditionTrue,
+		Reason:             string(v1alpha1.ConditionReasonZoneNotDelegated),
+		ObservedGeneration: 1,
+	}
+
+	testCases := []struct {
+		name           string
+		records        []v1alpha1.DNSRecord
+		expectReady    bool
+		expectPhase    v1alpha1.DNSRecordPhase
+		expectDelegate DelegationState
+		expectRecords  int
+	}{
+		{
+			name:           "no records",
+			expectPhase:    v1alpha1.DNSRecordPhasePending,
+			expectDelegate: DelegationStateOK,
+		},
+		{
+			name: "all records ready",
+			records: []v1alpha1.DNSRecord{
+				readinessTestRecord("a", "foo.example.com", v1alpha1.DNSRecordPhaseReady, ready),
+				readinessTestRecord("b", "FOO.example.com", v1alpha1.DNSRecordPhaseReady, ready),
+				readinessTestRecord("c", "bar.example.com", v1alpha1.DNSRecordPhasePending),
+			},
+			expectReady:    true,
+			expectPhase:    v1alpha1.DNSRecordPhaseReady,
+			expectDelegate: DelegationStateOK,
+			expectRecords:  2,
+		},
+		{
+			name: "one record publishing",
+			records: []v1alpha1.DNSRecord{
+				readinessTestRecord("a", "foo.example.com", v1alpha1.DNSRecordPhaseReady, ready),
+				readinessTestRecord("b", "foo.example.com", v1alpha1.DNSRecordPhasePublishing, awaitingValidation),
+			},
+			expectPhase:    v1alpha1.DNSRecordPhasePublishing,
+			expectDelegate: DelegationStateOK,
+			expectRecords:  2,
+		},
+		{
+			name: "one record degraded",
+			records: []v1alpha1.DNSRecord{
+				readinessTestRecord("a", "foo.example.com", v1alpha1.DNSRecordPhaseDegraded, ready, partiallyHealthy),
+				readinessTestRecord("b", "foo.example.com", v1alpha1.DNSRecordPhaseReady, ready),
+			},
+			expectReady:    true,
+			expectPhase:    v1alpha1.DNSRecordPhaseDegraded,
+			expectDelegate: DelegationStateOK,
+			expectRecords:  2,
+		},
+		{
+			name: "zone not delegated",
+			records: []v1alpha1.DNSRecord{
+				readinessTestRecord("a", "foo.example.com", v1alpha1.DNSRecordPhaseReady, ready, notDelegated),
+			},
+			expectReady:    true,
+			expectPhase:    v1alpha1.DNSRecordPhaseReady,
+			expectDelegate: DelegationStateNotDelegated,
+			expectRecords:  1,
+		},
+		{
+			name: "record without phase",
+			records: []v1alpha1.DNSRecord{
+				readinessTestRecord("a", "foo.example.com", ""),
+			},
+			expectPhase:    v1alpha1.DNSRecordPhasePending,
+			expectDelegate: DelegationStateOK,
+			expectRecords:  1,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			readiness := GetHostnameReadiness("foo.example.com", testCase.records)
+			if readiness.Ready != testCase.expectReady {
+				t.Errorf("expected ready to be %v, got %v", testCase.expectReady, readiness.Ready)
+			}
+			if readiness.Phase != testCase.expectPhase {
+				t.Errorf("expected phase %s, got %s", testCase.expectPhase, readiness.Phase)
+			}
+			if readiness.Delegation != testCase.expectDelegate {
+				t.Errorf("expected delegation %s, got %s", testCase.expectDelegate, readiness.Delegation)
+			}
+			if len(readiness.Records) != testCase.expectRecords {
+				t.Errorf("expected %d records, got %d", testCase.expectRecords, len(readiness.Records))
+			}
+		})
+	}
+}
+
+func TestGetRecordReadiness(t *testing.T) {
+	record := testRecord("foo", 2, metav1.Condition{
+		Type:               string(v1alpha1.ConditionTypeReady),
+		Status:             metav1.ConditionTrue,
+		Reason:             string(v1alpha1.ConditionReasonProviderSuccess),
+		ObservedGeneration: 1,
+	})
+	record.Status.Phase = v1alpha1.DNSRecordPhaseReady
+
+	readiness := GetRecordReadiness(record)
+	if readiness.Ready {
+		t.Errorf("expected a record ready for a previous generation not to be ready")
+	}
+	if readiness.Phase != v1alpha1.DNSRecordPhasePublishing {
+		t.Errorf("expected phase %s, got %s", v1alpha1.DNSRecordPhasePublishing, readiness.Phase)
+	}
+	if readiness.Reason != string(v1alpha1.ConditionReasonProviderSuccess) {
+		t.Errorf("expected reason %s, got %s", v1alpha1.ConditionReasonProviderSuccess, readiness.Reason)
+	}
+}
+
+func TestClientGetReadiness(t *testing.T) {
+	record := readinessTestRecord("foo", "foo.example.com", v1alpha1.DNSRecordPhaseReady, metav1.Condition{
+		Type:               string(v1alpha1.ConditionTypeReady),
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: 1,
+	})
+	c := NewFromClient(fake.NewClientBuilder().WithScheme(NewScheme()).WithObjects(&record).Build())
+
+	readiness, err := c.GetReadiness(context.Background(), "foo.example.com", client.InNamespace("test"))
+	if err != nil {
+		t.Fatalf("unexpected error getting readiness: %v", err)
+	}
+	if !readiness.Ready || len(readiness.Records) != 1 {
+		t.Errorf("expected the hostname to be ready with 1 record, got %+v", readiness)
+	}
+}
@@ -0,0 +1,152 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package conformance has the tests the sidecar of an external provider must pass to be used by the operator.
+package conformance
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	externaldnsendpoint "sigs.k8s.io/external-dns/endpoint"
+	externaldnsplan "sigs.k8s.io/external-dns/plan"
+
+	"github.com/kuadrant/dns-operator/internal/provider"
+	"github.com/kuadrant/dns-operator/internal/provider/external"
+)
+
+// RunExternalProvider runs the conformance tests against the external provider sidecar serving the webhook provider
+// API at the given url. The tests create, update and delete records with unique names in the given zone, which must be
+// managed by the sidecar, and remove them again when they fail.
+func RunExternalProvider(t *testing.T, sidecarURL, zone string) {
+	ctx := context.Background()
+	u, err := url.Parse(sidecarURL)
+	if err != nil {
+		t.Fatalf("invalid sidecar url: %v", err)
+	}
+	zone = strings.TrimSuffix(zone, ".")
+
+	p, err := external.NewProvider(ctx, u, provider.Config{})
+	if err != nil {
+		t.Fatalf("negotiating with the sidecar failed: %v", err)
+	}
+
+	t.Run("zone is managed", func(t *testing.T) {
+		if filters := p.GetDomainFilter().Filters; len(filters) > 0 && !p.GetDomainFilter().Match(zone) {
+			t.Fatalf("the domain filter of the sidecar %v does not match zone %s", filters, zone)
+		}
+	})
+
+	host := fmt.Sprintf("conformance-%d.%s", time.Now().UnixNano(), zone)
+	created := []*externaldnsendpoint.Endpoint{
+		externaldnsendpoint.NewEndpointWithTTL(host, externaldnsendpoint.RecordTypeA, 60, "192.0.2.1", "192.0.2.2"),
+		externaldnsendpoint.NewEndpointWithTTL("www."+host, externaldnsendpoint.RecordTypeCNAME, 60, host),
+		// registry TXT records
+		externaldnsendpoint.NewEndpointWithTTL("kuadrant-a-"+host, externaldnsendpoint.RecordTypeTXT, 60,
+			"\"heritage=external-dns,external-dns/owner=conformance,external-dns/version=1\""),
+	}
+	updated := []*externaldnsendpoint.Endpoint{
+		externaldnsendpoint.NewEndpointWithTTL(host, externaldnsendpoint.RecordTypeA, 120, "192.0.2.3"),
+	}
+	t.Cleanup(func() {
+		current, err := p.Records(ctx)
+		if err != nil {
+			return
+		}
+		var leftover []*externaldnsendpoint.Endpoint
+		for _, ep := range current {
+			if strings.HasSuffix(ep.DNSName, host) {
+				leftover = append(leftover, ep)
+			}
+		}
+		if len(leftover) > 0 {
+			_ = p.ApplyChanges(ctx, &externaldnsplan.Changes{Delete: leftover})
+		}
+	})
+
+	t.Run("adjust endpoints", func(t *testing.T) {
+		adjusted, err := p.AdjustEndpoints(created)
+		if err != nil {
+			t.Fatalf("AdjustEndpoints() failed: %v", err)
+		}
+		if len(adjusted) != len(created) {
+			t.Fatalf("AdjustEndpoints() returned %d endpoints, expected %d", len(adjusted), len(created))
+		}
+		created = adjusted
+	})
+
+	t.Run("create records", func(t *testing.T) {
+		if err := p.ApplyChanges(ctx, &externaldnsplan.Changes{Create: created}); err != nil {
+			t.Fatalf("ApplyChanges() creating records failed: %v", err)
+		}
+		expectRecords(ctx, t, p, created, nil)
+	})
+
+	t.Run("update records", func(t *testing.T) {
+		if err := p.ApplyChanges(ctx, &externaldnsplan.Changes{UpdateOld: created[:1], UpdateNew: updated}); err != nil {
+			t.Fatalf("ApplyChanges() updating records failed: %v", err)
+		}
+		expectRecords(ctx, t, p, append(updated, created[1:]...), nil)
+	})
+
+	t.Run("delete records", func(t *testing.T) {
+		deleted := append(updated, created[1:]...)
+		if err := p.ApplyChanges(ctx, &externaldnsplan.Changes{Delete: deleted}); err != nil {
+			t.Fatalf("ApplyChanges() deleting records failed: %v", err)
+		}
+		expectRecords(ctx, t, p, nil, deleted)
+	})
+}
+
+// expectRecords fails the test unless the records of the provider include the present endpoints and don't include the
+// absent endpoints.
+func expectRecords(ctx context.Context, t *testing.T, p provider.Provider, present, absent []*externaldnsendpoint.Endpoint) {
+	t.Helper()
+	records, err := p.Records(ctx)
+	if err != nil {
+		t.Fatalf("Records() failed: %v", err)
+	}
+	find := func(ep *externaldnsendpoint.Endpoint) *externaldnsendpoint.Endpoint {
+		for _, record := range records {
+			if strings.EqualFold(record.DNSName, ep.DNSName) && record.RecordType == ep.RecordType && record.SetIdentifier == ep.SetIdentifier {
+				return record
+			}
+		}
+		return nil
+	}
+	for _, ep := range present {
+		record := find(ep)
+		if record == nil {
+			t.Errorf("Records() is missing %s %s", ep.RecordType, ep.DNSName)
+			continue
+		}
+		if !record.Targets.Same(ep.Targets) {
+			t.Errorf("Records() has targets %v for %s %s, expected %v", record.Targets, ep.RecordType, ep.DNSName, ep.Targets)
+		}
+		if record.RecordTTL != ep.RecordTTL {
+			t.Errorf("Records() has TTL %d for %s %s, expected %d", record.RecordTTL, ep.RecordType, ep.DNSName, ep.RecordTTL)
+		}
+	}
+	for _, ep := range absent {
+		if find(ep) != nil {
+			t.Errorf("Records() still has %s %s", ep.RecordType, ep.DNSName)
+		}
+	}
+}
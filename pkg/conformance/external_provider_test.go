@@ -0,0 +1,68 @@
+//go:build unit
+
+package conformance
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	externaldnsendpoint "sigs.k8s.io/external-dns/endpoint"
+	externaldnsplan "sigs.k8s.io/external-dns/plan"
+
+	"github.com/kuadrant/dns-operator/internal/external-dns/provider/inmemory"
+	"github.com/kuadrant/dns-operator/internal/provider/external"
+)
+
+// newSidecar returns a server of the webhook provider API backed by an inmemory provider with the given zone.
+func newSidecar(t *testing.T, zone string) *httptest.Server {
+	p := inmemory.NewInMemoryProvider(context.Background(), inmemory.InMemoryInitZones([]string{zone}),
+		inmemory.InMemoryWithDomain(externaldnsendpoint.NewDomainFilter([]string{zone})))
+
+	respond := func(w http.ResponseWriter, v any) {
+		w.Header().Set("Content-Type", external.MediaType)
+		_ = json.NewEncoder(w).Encode(v)
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, _ *http.Request) {
+		respond(w, p.GetDomainFilter())
+	})
+	mux.HandleFunc("/records", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			records, err := p.Records(r.Context())
+			if err != nil {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			respond(w, records)
+			return
+		}
+		changes := &externaldnsplan.Changes{}
+		if err := json.NewDecoder(r.Body).Decode(changes); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if err := p.ApplyChanges(r.Context(), changes); err != nil {
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+	mux.HandleFunc("/adjustendpoints", func(w http.ResponseWriter, r *http.Request) {
+		var endpoints []*externaldnsendpoint.Endpoint
+		if err := json.NewDecoder(r.Body).Decode(&endpoints); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		respond(w, endpoints)
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestRunExternalProvider(t *testing.T) {
+	RunExternalProvider(t, newSidecar(t, "example.com").URL, "example.com")
+}
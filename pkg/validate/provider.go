@@ -0,0 +1,152 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validate
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	externaldns "sigs.k8s.io/external-dns/endpoint"
+
+	"github.com/kuadrant/dns-operator/api/v1alpha1"
+	"github.com/kuadrant/dns-operator/internal/provider"
+)
+
+const awsContinentPrefix = "GEO-"
+
+// defaultRecordTypes are the record types supported by all providers
+var defaultRecordTypes = []string{
+	externaldns.RecordTypeA,
+	externaldns.RecordTypeAAAA,
+	externaldns.RecordTypeCNAME,
+	externaldns.RecordTypeSRV,
+	externaldns.RecordTypeTXT,
+	externaldns.RecordTypeNS,
+}
+
+// providerCapabilities are the record types and provider specific values a provider supports
+type providerCapabilities struct {
+	recordTypes []string
+	// weight returns an error if the weight is not supported, weights are not validated if nil
+	weight func(string) error
+	// geoCode returns an error if the geo code is not supported, geo codes are not validated if nil
+	geoCode func(string) error
+	// multiValue is true if multi-value endpoints are supported
+	multiValue bool
+}
+
+// providers are the capabilities of the built in providers, by the name they are registered with
+var providers = map[string]providerCapabilities{
+	"aws": {
+		recordTypes: append(slices.Clone(defaultRecordTypes), externaldns.RecordTypeMX, v1alpha1.RecordTypeNAPTR),
+		weight:      integerWeight,
+		geoCode:     awsGeoCode,
+		multiValue:  true,
+	},
+	"google": {
+		recordTypes: append(slices.Clone(defaultRecordTypes), externaldns.RecordTypeMX),
+	},
+	"azure": {
+		recordTypes: append(slices.Clone(defaultRecordTypes), externaldns.RecordTypeMX),
+		weight:      integerWeight,
+	},
+	"inmemory": {
+		recordTypes: append(slices.Clone(defaultRecordTypes), externaldns.RecordTypeMX, v1alpha1.RecordTypeNAPTR, v1alpha1.RecordTypeTLSA),
+	},
+}
+
+// Providers returns the names of the providers the capabilities of are known.
+func Providers() []string {
+	names := make([]string, 0, len(providers))
+	for name := range providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// validate returns an error for each endpoint of the record the provider does not support
+func (c providerCapabilities) validate(record *v1alpha1.DNSRecord) []error {
+	var errs []error
+	for _, ep := range record.Spec.Endpoints {
+		if !slices.Contains(c.recordTypes, ep.RecordType) {
+			errs = append(errs, fmt.Errorf("record type %s of %s is not supported by the provider", ep.RecordType, ep.DNSName))
+		}
+		if weight, ok := ep.GetProviderSpecificProperty(v1alpha1.ProviderSpecificWeight); ok && c.weight != nil {
+			if err := c.weight(weight); err != nil {
+				errs = append(errs, fmt.Errorf("weight %q of %s %s", weight, ep.DNSName, err))
+			}
+		}
+		if geoCode, ok := ep.GetProviderSpecificProperty(v1alpha1.ProviderSpecificGeoCode); ok && c.geoCode != nil {
+			if err := c.geoCode(geoCode); err != nil {
+				errs = append(errs, fmt.Errorf("geo code %q of %s %s", geoCode, ep.DNSName, err))
+			}
+		}
+		if multiValue, ok := ep.GetProviderSpecificProperty(v1alpha1.ProviderSpecificMultiValue); ok {
+			errs = append(errs, c.validateMultiValue(ep, multiValue)...)
+		}
+	}
+	return errs
+}
+
+// validateMultiValue returns an error for each reason the multi-value endpoint can't be published by the provider
+func (c providerCapabilities) validateMultiValue(ep *externaldns.Endpoint, multiValue string) []error {
+	if !c.multiValue {
+		return []error{fmt.Errorf("multi-value endpoint %s is not supported by the provider", ep.DNSName)}
+	}
+	if multiValue != "true" {
+		return nil
+	}
+	var errs []error
+	if ep.SetIdentifier == "" {
+		errs = append(errs, fmt.Errorf("multi-value endpoint %s must have a set identifier", ep.DNSName))
+	}
+	if len(ep.Targets) != 1 {
+		errs = append(errs, fmt.Errorf("multi-value endpoint %s must have a single target", ep.DNSName))
+	}
+	for _, routing := range []string{v1alpha1.ProviderSpecificWeight, v1alpha1.ProviderSpecificGeoCode} {
+		if _, ok := ep.GetProviderSpecificProperty(routing); ok {
+			errs = append(errs, fmt.Errorf("multi-value endpoint %s can't also set %s", ep.DNSName, routing))
+		}
+	}
+	return errs
+}
+
+func integerWeight(weight string) error {
+	if w, err := strconv.Atoi(weight); err != nil || w < 0 {
+		return fmt.Errorf("must be a non-negative integer")
+	}
+	return nil
+}
+
+// awsGeoCode accepts the geo codes the aws provider translates to route53 geolocations
+func awsGeoCode(geoCode string) error {
+	geoCode = strings.ToUpper(geoCode)
+	if continent, ok := strings.CutPrefix(geoCode, awsContinentPrefix); ok {
+		if !provider.IsContinentCode(continent) {
+			return fmt.Errorf("is not a continent code")
+		}
+		return nil
+	}
+	if geoCode != "*" && !provider.IsISO3166Alpha2Code(geoCode) {
+		return fmt.Errorf("must be prefixed with %s for continents or be an ISO 3166 alpha 2 country code", awsContinentPrefix)
+	}
+	return nil
+}
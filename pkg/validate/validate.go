@@ -0,0 +1,231 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package validate validates DNSRecord manifests without a cluster, so broken records can be rejected by CI pipelines
+// before they are applied. Records are validated against the schema of the CustomResourceDefinition, the endpoint
+// validation of the operator and, if a provider is given, the capabilities of the provider.
+package validate
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	apiextensionsinternal "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apiextensions-apiserver/pkg/apiserver/validation"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/yaml"
+
+	"github.com/kuadrant/dns-operator/api/v1alpha1"
+	"github.com/kuadrant/dns-operator/config/crd"
+)
+
+// Options configure a Validator.
+type Options struct {
+	// Provider is the type of the provider the records are published with, one of Providers(). The provider
+	// capabilities are not validated if empty.
+	Provider string
+}
+
+// Result is the outcome of the validation of a DNSRecord manifest.
+type Result struct {
+	// Source is the file the manifest was read from
+	Source string
+	// Document is the index of the manifest in the source, starting at 1
+	Document int
+	// Name and Namespace of the record, empty if the manifest could not be decoded
+	Name      string
+	Namespace string
+	// Err is the reason the record is not valid, nil if it is valid
+	Err error
+}
+
+func (r Result) String() string {
+	name := fmt.Sprintf("document %d", r.Document)
+	if r.Name != "" {
+		name = fmt.Sprintf("%s/%s", r.Namespace, r.Name)
+	}
+	if r.Err != nil {
+		return fmt.Sprintf("%s: %s: %s", r.Source, name, r.Err)
+	}
+	return fmt.Sprintf("%s: %s: valid", r.Source, name)
+}
+
+// Validator validates DNSRecords.
+type Validator struct {
+	schema   validation.SchemaCreateValidator
+	provider *providerCapabilities
+}
+
+// New returns a Validator of DNSRecords, or an error if the provider is not known.
+func New(opts Options) (*Validator, error) {
+	v := &Validator{}
+	if opts.Provider != "" {
+		capabilities, ok := providers[opts.Provider]
+		if !ok {
+			return nil, fmt.Errorf("unknown provider %q, must be one of %v", opts.Provider, Providers())
+		}
+		v.provider = &capabilities
+	}
+
+	schema, err := dnsRecordSchema()
+	if err != nil {
+		return nil, fmt.Errorf("loading DNSRecord schema: %w", err)
+	}
+	v.schema, _, err = validation.NewSchemaValidator(schema)
+	if err != nil {
+		return nil, fmt.Errorf("loading DNSRecord schema: %w", err)
+	}
+	return v, nil
+}
+
+// Record returns an error if the endpoints of the record are not valid, or not supported by the provider.
+func (v *Validator) Record(record *v1alpha1.DNSRecord) error {
+	var errs []error
+	if err := record.Validate(); err != nil {
+		errs = append(errs, err)
+	} else if err := record.ValidateTargets(); err != nil {
+		errs = append(errs, err)
+	}
+	if v.provider != nil {
+		errs = append(errs, v.provider.validate(record)...)
+	}
+	return errors.Join(errs...)
+}
+
+// Manifests validates the DNSRecords of a stream of YAML or JSON documents. Documents of other kinds are skipped. An
+// error is returned only if the stream can't be read, invalid records are reported in the results.
+func (v *Validator) Manifests(source string, r io.Reader) ([]Result, error) {
+	var results []Result
+	reader := utilyaml.NewYAMLReader(bufio.NewReader(r))
+	for document := 1; ; document++ {
+		data, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return results, nil
+		}
+		if err != nil {
+			return results, fmt.Errorf("reading %s: %w", source, err)
+		}
+		if len(bytes.TrimSpace(data)) == 0 {
+			continue
+		}
+		if result, ok := v.manifest(data); ok {
+			result.Source, result.Document = source, document
+			results = append(results, result)
+		}
+	}
+}
+
+// Path validates the DNSRecords of the file, or of the .yaml, .yml and .json files in the directory. Files in
+// subdirectories are validated if recursive is true.
+func (v *Validator) Path(path string, recursive bool) ([]Result, error) {
+	var results []Result
+	err := filepath.WalkDir(path, func(file string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			if file != path && !recursive {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if file != path && !isManifest(file) {
+			return nil
+		}
+		f, err := os.Open(file)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		fileResults, err := v.Manifests(file, f)
+		results = append(results, fileResults...)
+		return err
+	})
+	return results, err
+}
+
+// manifest returns the result of the validation of the document, and false if it is not a DNSRecord
+func (v *Validator) manifest(data []byte) (Result, bool) {
+	var typeMeta metav1.TypeMeta
+	if err := yaml.Unmarshal(data, &typeMeta); err != nil {
+		return Result{Err: fmt.Errorf("decoding manifest: %w", err)}, true
+	}
+	if typeMeta.GroupVersionKind() != v1alpha1.GroupVersion.WithKind("DNSRecord") {
+		return Result{}, false
+	}
+
+	var object map[string]interface{}
+	if err := yaml.Unmarshal(data, &object); err != nil {
+		return Result{Err: fmt.Errorf("decoding manifest: %w", err)}, true
+	}
+	record := &v1alpha1.DNSRecord{}
+	if err := yaml.UnmarshalStrict(data, record); err != nil {
+		// the unknown fields are reported, the rest of the record is still validated
+		if err := yaml.Unmarshal(data, record); err != nil {
+			return Result{Err: fmt.Errorf("decoding manifest: %w", err)}, true
+		}
+		return v.result(record, object, fmt.Errorf("decoding manifest: %w", err)), true
+	}
+	return v.result(record, object, nil), true
+}
+
+func (v *Validator) result(record *v1alpha1.DNSRecord, object map[string]interface{}, decodeErr error) Result {
+	errs := []error{decodeErr}
+	if schemaErrs := validation.ValidateCustomResource(nil, object, v.schema); len(schemaErrs) > 0 {
+		errs = append(errs, schemaErrs.ToAggregate())
+	}
+	errs = append(errs, v.Record(record))
+	return Result{
+		Name:      record.Name,
+		Namespace: record.Namespace,
+		Err:       errors.Join(errs...),
+	}
+}
+
+func isManifest(file string) bool {
+	switch filepath.Ext(file) {
+	case ".yaml", ".yml", ".json":
+		return true
+	}
+	return false
+}
+
+// dnsRecordSchema returns the schema of the served version of the DNSRecord CustomResourceDefinition
+func dnsRecordSchema() (*apiextensionsinternal.JSONSchemaProps, error) {
+	definition := &apiextensionsv1.CustomResourceDefinition{}
+	if err := yaml.Unmarshal(crd.DNSRecord, definition); err != nil {
+		return nil, err
+	}
+	for _, version := range definition.Spec.Versions {
+		if version.Name != v1alpha1.GroupVersion.Version || version.Schema == nil {
+			continue
+		}
+		schema := &apiextensionsinternal.JSONSchemaProps{}
+		if err := apiextensionsv1.Convert_v1_JSONSchemaProps_To_apiextensions_JSONSchemaProps(version.Schema.OpenAPIV3Schema, schema, nil); err != nil {
+			return nil, err
+		}
+		return schema, nil
+	}
+	return nil, fmt.Errorf("no schema for version %s", v1alpha1.GroupVersion.Version)
+}
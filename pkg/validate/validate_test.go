@@ -0,0 +1,266 @@
+//go:build unit
+
+package validate
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const validRecord = `apiVersion: kuadrant.io/v1alpha1
+kind: DNSRecord
+metadata:
+  name: valid
+  namespace: dnstest
+spec:
+  providerRef:
+    name: dns-provider-credentials
+  rootHost: foo.example.com
+  endpoints:
+  - dnsName: foo.example.com
+    recordType: A
+    recordTTL: 60
+    targets:
+    - 1.1.1.1
+    providerSpecific:
+    - name: geo-code
+      value: GEO-EU
+`
+
+const invalidTargetRecord = `apiVersion: kuadrant.io/v1alpha1
+kind: DNSRecord
+metadata:
+  name: invalid-target
+  namespace: dnstest
+spec:
+  providerRef:
+    name: dns-provider-credentials
+  rootHost: foo.example.com
+  endpoints:
+  - dnsName: foo.example.com
+    recordType: AAAA
+    targets:
+    - 1.1.1.1
+`
+
+const unknownFieldRecord = `apiVersion: kuadrant.io/v1alpha1
+kind: DNSRecord
+metadata:
+  name: unknown-field
+  namespace: dnstest
+spec:
+  providerRef:
+    name: dns-provider-credentials
+  rootHost: foo.example.com
+  rootHosts: foo.example.com
+  endpoints:
+  - dnsName: foo.example.com
+    recordType: A
+    targets:
+    - 1.1.1.1
+`
+
+const schemaRecord = `apiVersion: kuadrant.io/v1alpha1
+kind: DNSRecord
+metadata:
+  name: schema
+  namespace: dnstest
+spec:
+  providerRef:
+    name: dns-provider-credentials
+  rootHost: foo
+  endpoints:
+  - dnsName: foo
+    recordType: A
+    targets:
+    - 1.1.1.1
+`
+
+const unsupportedRecord = `apiVersion: kuadrant.io/v1alpha1
+kind: DNSRecord
+metadata:
+  name: unsupported
+  namespace: dnstest
+spec:
+  providerRef:
+    name: dns-provider-credentials
+  rootHost: foo.example.com
+  endpoints:
+  - dnsName: foo.example.com
+    recordType: CAA
+    targets:
+    - 0 issue "letsencrypt.org"
+  - dnsName: foo.example.com
+    recordType: A
+    targets:
+    - 1.1.1.1
+    providerSpecific:
+    - name: weight
+      value: heavy
+    - name: geo-code
+      value: GEO-XX
+`
+
+const multiValueRecord = `apiVersion: kuadrant.io/v1alpha1
+kind: DNSRecord
+metadata:
+  name: multi-value
+  namespace: dnstest
+spec:
+  providerRef:
+    name: dns-provider-credentials
+  rootHost: foo.example.com
+  endpoints:
+  - dnsName: foo.example.com
+    recordType: A
+    setIdentifier: "1.1.1.1"
+    targets:
+    - 1.1.1.1
+    providerSpecific:
+    - name: multi-value
+      value: "true"
+    - name: aws/health-check-id
+      value: hc-1
+  - dnsName: bar.foo.example.com
+    recordType: A
+    targets:
+    - 1.1.1.1
+    - 2.2.2.2
+    providerSpecific:
+    - name: multi-value
+      value: "true"
+`
+
+const configMap = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: not-a-record
+`
+
+func validateManifests(t *testing.T, provider string, manifests ...string) map[string]error {
+	t.Helper()
+	v, err := New(Options{Provider: provider})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	results, err := v.Manifests("test.yaml", strings.NewReader(strings.Join(manifests, "---\n")))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	errs := map[string]error{}
+	for _, result := range results {
+		errs[result.Name] = result.Err
+	}
+	return errs
+}
+
+func TestManifests(t *testing.T) {
+	errs := validateManifests(t, "", validRecord, invalidTargetRecord, unknownFieldRecord, schemaRecord, unsupportedRecord, configMap)
+	if len(errs) != 5 {
+		t.Fatalf("expected 5 results, got %v", errs)
+	}
+	if errs["valid"] != nil {
+		t.Errorf("expected valid record, got %v", errs["valid"])
+	}
+	if err := errs["unsupported"]; err != nil {
+		t.Errorf("expected provider capabilities not to be validated, got %v", err)
+	}
+	for name, want := range map[string]string{
+		"invalid-target": "must be an IPv6 address",
+		"unknown-field":  `unknown field "rootHosts"`,
+		"schema":         "spec.rootHost",
+	} {
+		if err := errs[name]; err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("expected %s to fail with %q, got %v", name, want, err)
+		}
+	}
+}
+
+func TestProviderCapabilities(t *testing.T) {
+	if _, err := New(Options{Provider: "unknown"}); err == nil {
+		t.Errorf("expected error for unknown provider")
+	}
+
+	errs := validateManifests(t, "aws", validRecord, unsupportedRecord)
+	if errs["valid"] != nil {
+		t.Errorf("expected valid record, got %v", errs["valid"])
+	}
+	for _, want := range []string{
+		"record type CAA of foo.example.com is not supported",
+		`weight "heavy" of foo.example.com must be a non-negative integer`,
+		`geo code "GEO-XX" of foo.example.com is not a continent code`,
+	} {
+		if err := errs["unsupported"]; err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("expected error %q, got %v", want, err)
+		}
+	}
+
+	errs = validateManifests(t, "aws", multiValueRecord)
+	for _, want := range []string{
+		"multi-value endpoint bar.foo.example.com must have a set identifier",
+		"multi-value endpoint bar.foo.example.com must have a single target",
+	} {
+		if err := errs["multi-value"]; err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("expected error %q, got %v", want, err)
+		}
+	}
+	if err := errs["multi-value"]; err != nil && strings.Contains(err.Error(), "multi-value endpoint foo.example.com") {
+		t.Errorf("expected foo.example.com to be a valid multi-value endpoint, got %v", err)
+	}
+
+	errs = validateManifests(t, "google", multiValueRecord)
+	if err := errs["multi-value"]; err == nil || !strings.Contains(err.Error(), "multi-value endpoint foo.example.com is not supported by the provider") {
+		t.Errorf("expected multi-value endpoints not to be supported, got %v", err)
+	}
+
+	errs = validateManifests(t, "google", unsupportedRecord)
+	if err := errs["unsupported"]; err == nil || strings.Contains(err.Error(), "weight") || strings.Contains(err.Error(), "geo code") {
+		t.Errorf("expected only the record type to be validated, got %v", err)
+	}
+}
+
+func TestPath(t *testing.T) {
+	dir := t.TempDir()
+	for file, content := range map[string]string{
+		"valid.yaml":        validRecord,
+		"invalid.yml":       invalidTargetRecord,
+		"README.md":         "not a manifest",
+		"nested/valid.yaml": validRecord,
+	} {
+		path := filepath.Join(dir, file)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	v, err := New(Options{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	results, err := v.Path(dir, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %v", results)
+	}
+	results, err = v.Path(dir, true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != 3 {
+		t.Fatalf("expected 3 results, got %v", results)
+	}
+	results, err = v.Path(filepath.Join(dir, "invalid.yml"), false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != 1 || results[0].Err == nil || results[0].Document != 1 {
+		t.Errorf("expected an invalid record, got %v", results)
+	}
+}